@@ -0,0 +1,27 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+// Site-specific extensions are compiled in by importing them here for their
+// init-time registration. They live in their own packages inside this module,
+// such as extensions/<name>, so they can use OctoDash's internal packages:
+//
+//	import _ "github.com/wmarchesi123/octodash/extensions/badges"
+//
+// Each is enabled unless named in EXTENSIONS_DISABLED.
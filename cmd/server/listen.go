@@ -0,0 +1,80 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"net"
+	"os"
+	"strings"
+)
+
+const unixPrefix = "unix:"
+
+// listenAddresses returns the addresses to serve on: the --listen flags,
+// else LISTEN_ADDR (comma-separated), else all interfaces on PORT
+func listenAddresses(flagAddrs []string) []string {
+	if len(flagAddrs) > 0 {
+		return flagAddrs
+	}
+
+	var addrs []string
+	for _, addr := range strings.Split(os.Getenv("LISTEN_ADDR"), ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			addrs = append(addrs, addr)
+		}
+	}
+	if len(addrs) > 0 {
+		return addrs
+	}
+
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8080"
+	}
+	return []string{":" + port}
+}
+
+// listen opens a TCP listener for host:port or a Unix socket for unix:/path
+func listen(addr string) (net.Listener, error) {
+	path, isUnix := strings.CutPrefix(addr, unixPrefix)
+	if !isUnix {
+		return net.Listen("tcp", addr)
+	}
+
+	// Remove a stale socket left by an unclean shutdown
+	if info, err := os.Lstat(path); err == nil && info.Mode()&fs.ModeSocket != 0 {
+		os.Remove(path)
+	} else if err == nil {
+		return nil, errors.New(path + " exists and is not a socket")
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Let a reverse proxy in the same group connect
+	if err := os.Chmod(path, 0660); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
@@ -20,56 +20,225 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"syscall"
 	"time"
+	_ "time/tzdata" // DISPLAY_TIMEZONE must work in slim containers without zoneinfo
 
+	"github.com/spf13/cobra"
+	"github.com/wmarchesi123/go-3dprint-client/config"
+	"github.com/wmarchesi123/octodash/internal/cli"
+	"github.com/wmarchesi123/octodash/internal/demo"
 	"github.com/wmarchesi123/octodash/internal/handlers"
+	"github.com/wmarchesi123/octodash/internal/profiling"
+	"github.com/wmarchesi123/octodash/internal/settings"
+	"github.com/wmarchesi123/octodash/internal/systemd"
+	"github.com/wmarchesi123/octodash/internal/tailnet"
+	"github.com/wmarchesi123/octodash/internal/upstream"
+	"google.golang.org/grpc"
 )
 
+const listenUsage = "Address to listen on (host:port or unix:/path); repeatable, overrides LISTEN_ADDR and PORT"
+
 func main() {
-	// Get port from environment or default to 8080
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	var printUnit bool
+	var listenFlags []string
+
+	root := &cobra.Command{
+		Use:   "octodash",
+		Short: "OctoPrint and Spoolman dashboard",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if printUnit {
+				return printSystemdUnit()
+			}
+			serve(listenAddresses(listenFlags))
+			return nil
+		},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	root.Flags().BoolVar(&printUnit, "print-systemd-unit", false, "Print a systemd service unit for this binary and exit")
+	root.Flags().StringArrayVar(&listenFlags, "listen", nil, listenUsage)
+
+	serveCmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run the dashboard server (default when no command is given)",
+		Args:  cobra.NoArgs,
+		Run:   func(cmd *cobra.Command, args []string) { serve(listenAddresses(listenFlags)) },
+	}
+	serveCmd.Flags().StringArrayVar(&listenFlags, "listen", nil, listenUsage)
+	root.AddCommand(serveCmd)
+	cli.AddCommands(root)
+
+	if err := root.Execute(); err != nil {
+		log.SetFlags(0)
+		log.Fatalf("Error: %v", err)
+	}
+}
+
+// serve runs the dashboard server on addrs until interrupted
+func serve(addrs []string) {
+	s, err := settings.Load()
+	if err != nil {
+		log.Fatalf("Failed to load settings: %v", err)
+	}
+
+	// Demo mode swaps the configured printers for a simulated farm
+	var cfg *config.Config
+	if s.Demo.Enabled {
+		farm, err := demo.Start(s.Demo)
+		if err != nil {
+			log.Fatalf("Failed to start demo farm: %v", err)
+		}
+		defer farm.Close()
+		cfg = farm.Config()
+		log.Printf("Demo mode: simulating %d printers at %gx speed", len(cfg.Printers), s.Demo.Speed)
+	} else if cfg, err = config.LoadConfig(); err != nil {
+		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	// Join the tailnet before any upstream traffic so tailnet printers are reachable
+	var node *tailnet.Node
+	if s.Tailscale.Enabled {
+		if node, err = tailnet.Start(s.Tailscale); err != nil {
+			log.Fatalf("Failed to join tailnet: %v", err)
+		}
+		defer node.Close()
+		log.Printf("Joined tailnet as %s", s.Tailscale.Hostname)
+	}
+
+	// Share one tuned connection pool across every upstream client
+	transport := upstream.NewTransport(s.Upstream, cfg.Printers)
+	if node != nil {
+		upstream.ViaTailnet(transport, node.Dial)
+	}
+	upstream.Install(transport)
+
 	// Create handler
-	handler := handlers.NewHandler()
+	handler, err := handlers.NewHandler(cfg, s)
+	if err != nil {
+		log.Fatalf("Failed to create handler: %v", err)
+	}
 
 	// Configure server
 	srv := &http.Server{
-		Addr:         ":" + port,
 		Handler:      handler,
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
 	}
+	srv.RegisterOnShutdown(handler.Drain)
 
-	// Start server in goroutine
-	go func() {
-		log.Printf("OctoDash starting on port %s", port)
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Server failed to start: %v", err)
+	// Use sockets passed by systemd when socket-activated
+	listeners, err := systemd.Listeners()
+	if err != nil {
+		log.Fatalf("Socket activation failed: %v", err)
+	}
+	if len(listeners) == 0 {
+		for _, addr := range addrs {
+			listener, err := listen(addr)
+			if err != nil {
+				log.Fatalf("Server failed to listen on %s: %v", addr, err)
+			}
+			listeners = append(listeners, listener)
 		}
-	}()
+	}
 
-	// Wait for interrupt signal
+	if node != nil {
+		listeners = append(listeners, node.Listener)
+	}
+
+	// Start server in goroutines
+	for _, listener := range listeners {
+		go func(l net.Listener) {
+			log.Printf("OctoDash listening on %s", l.Addr())
+			if err := srv.Serve(l); err != nil && err != http.ErrServerClosed {
+				log.Fatalf("Server failed: %v", err)
+			}
+		}(listener)
+	}
+
+	// Profiling on its own port has no auth, so keep PROFILING_ADDR on loopback or a private network
+	var profSrv *http.Server
+	if s.Profiling.Enabled && s.Profiling.Addr != "" {
+		profSrv = &http.Server{Addr: s.Profiling.Addr, Handler: profiling.Handler()}
+		go func() {
+			log.Printf("Profiling endpoints listening on %s", s.Profiling.Addr)
+			if err := profSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Printf("Profiling server failed: %v", err)
+			}
+		}()
+	}
+
+	// The gRPC API shares the HTTP API's tokens but not its port, since it needs HTTP/2
+	var grpcSrv *grpc.Server
+	if s.GRPC.Addr != "" {
+		grpcListener, err := net.Listen("tcp", s.GRPC.Addr)
+		if err != nil {
+			log.Fatalf("gRPC API failed to listen on %s: %v", s.GRPC.Addr, err)
+		}
+		grpcSrv = handler.GRPCServer()
+		go func() {
+			log.Printf("gRPC API listening on %s", grpcListener.Addr())
+			if err := grpcSrv.Serve(grpcListener); err != nil {
+				log.Printf("gRPC server failed: %v", err)
+			}
+		}()
+	}
+
+	if _, err := systemd.Notify("READY=1"); err != nil {
+		log.Printf("sd_notify failed: %v", err)
+	}
+
+	// Wait for interrupt or systemd stop signal
 	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, os.Interrupt)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 	<-quit
 
 	log.Println("Shutting down server...")
+	systemd.Notify("STOPPING=1")
 
 	// Graceful shutdown with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	if profSrv != nil {
+		profSrv.Shutdown(ctx)
+	}
+	if grpcSrv != nil {
+		// End status streams first so GracefulStop doesn't wait on them
+		handler.Drain()
+		grpcSrv.GracefulStop()
+	}
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
+	handler.Close()
 
 	log.Println("Server exited")
 }
+
+// printSystemdUnit writes a service unit pointing at the running binary
+func printSystemdUnit() error {
+	execPath, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	if resolved, err := filepath.EvalSymlinks(execPath); err == nil {
+		execPath = resolved
+	}
+
+	unit, err := systemd.Unit(execPath)
+	if err != nil {
+		return err
+	}
+	fmt.Print(unit)
+	return nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package announce delivers spoken announcements: a Hub streams them to kiosk
+// displays, which read them out in the browser, and a Speaker forwards them
+// to a text-to-speech service for shop-wide speakers.
+package announce
+
+import (
+	"sync"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+)
+
+const (
+	// keep is how many recent announcements a reconnecting display can catch up on
+	keep = 50
+	// subscriberBuffer is how many announcements a slow display can fall behind before missing some
+	subscriberBuffer = 16
+)
+
+// Hub fans announcements out to connected displays
+type Hub struct {
+	mu     sync.Mutex
+	recent []models.Announcement
+	subs   map[chan models.Announcement]struct{}
+}
+
+// NewHub creates an empty Hub
+func NewHub() *Hub {
+	return &Hub{subs: make(map[chan models.Announcement]struct{})}
+}
+
+// Publish sends an announcement to every subscriber, skipping any that are backed up
+func (h *Hub) Publish(a models.Announcement) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.recent = append(h.recent, a)
+	if len(h.recent) > keep {
+		h.recent = h.recent[len(h.recent)-keep:]
+	}
+	for ch := range h.subs {
+		select {
+		case ch <- a:
+		default:
+		}
+	}
+}
+
+// Recent returns the kept announcements after the since ID, oldest first
+func (h *Hub) Recent(since string) []models.Announcement {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.after(since)
+}
+
+func (h *Hub) after(since string) []models.Announcement {
+	recent := []models.Announcement{}
+	for _, a := range h.recent {
+		if a.ID > since {
+			recent = append(recent, a)
+		}
+	}
+	return recent
+}
+
+// Subscribe returns the announcements after since and a channel receiving
+// every later one; cancel stops delivery and must be called
+func (h *Hub) Subscribe(since string) (missed []models.Announcement, ch <-chan models.Announcement, cancel func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sub := make(chan models.Announcement, subscriberBuffer)
+	h.subs[sub] = struct{}{}
+	if since != "" {
+		missed = h.after(since)
+	}
+	return missed, sub, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(h.subs, sub)
+	}
+}
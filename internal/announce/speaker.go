@@ -0,0 +1,129 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package announce
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/settings"
+)
+
+const (
+	// queued is how many announcements can wait for the speech service before new ones are dropped
+	queued = 20
+	// speakTimeout bounds each request to the speech service
+	speakTimeout = 10 * time.Second
+)
+
+// Speaker sends announcements to a text-to-speech service from a background
+// loop, so a slow or missing speaker never holds up polling. Announcements
+// are best effort: one the service rejects is logged and not retried, since
+// by then it is old news.
+type Speaker struct {
+	url    string
+	token  string
+	client *http.Client
+	logger *log.Logger
+	queue  chan models.Announcement
+}
+
+// NewSpeaker creates a Speaker for cfg.TTSURL; call Run to start speaking
+func NewSpeaker(cfg settings.AnnounceSettings, logger *log.Logger) *Speaker {
+	return &Speaker{
+		url:    cfg.TTSURL,
+		token:  cfg.TTSToken,
+		client: &http.Client{Timeout: speakTimeout},
+		logger: logger,
+		queue:  make(chan models.Announcement, queued),
+	}
+}
+
+// Say queues an announcement without waiting, dropping it if the queue is full
+func (s *Speaker) Say(a models.Announcement) {
+	select {
+	case s.queue <- a:
+	default:
+		s.logger.Printf("Speech service is backed up; dropped announcement %s", a.ID)
+	}
+}
+
+// Run speaks queued announcements until ctx is cancelled
+func (s *Speaker) Run(ctx context.Context) {
+	for {
+		select {
+		case a := <-s.queue:
+			if err := s.speak(ctx, a); err != nil {
+				s.logger.Printf("Failed to speak announcement %s: %v", a.ID, err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// speak sends one announcement: a GET with the text in the URL when it has a
+// {text} placeholder, as Sonos HTTP API's /say routes expect, and otherwise a
+// JSON POST such as a Home Assistant webhook takes
+func (s *Speaker) speak(ctx context.Context, a models.Announcement) error {
+	method, target := http.MethodPost, s.url
+	var body []byte
+	if strings.Contains(s.url, "{text}") {
+		method, target = http.MethodGet, strings.ReplaceAll(s.url, "{text}", url.PathEscape(a.Text))
+	} else {
+		var err error
+		if body, err = json.Marshal(map[string]interface{}{
+			"message":    a.Text,
+			"level":      a.Level,
+			"type":       a.Type,
+			"printer_id": a.PrinterID,
+		}); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("speech service returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// immutable is the Cache-Control for fingerprinted names, whose content can
+// never change
+const immutable = "public, max-age=31536000, immutable"
+
+// Assets indexes static files by content hash so URLs change whenever a file does
+type Assets struct {
+	fsys    fs.FS
+	hashes  map[string]string
+	names   map[string]string // fingerprinted name to file
+	version string
+}
+
+// New hashes every file in fsys
+func New(fsys fs.FS) (*Assets, error) {
+	a := &Assets{
+		fsys:   fsys,
+		hashes: make(map[string]string),
+		names:  make(map[string]string),
+	}
+
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		data, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return err
+		}
+
+		sum := sha256.Sum256(data)
+		a.hashes[path] = hex.EncodeToString(sum[:])[:12]
+		a.names[fingerprint(path, a.hashes[path])] = path
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The overall version changes when any asset changes
+	combined := sha256.New()
+	for _, name := range a.Names() {
+		combined.Write([]byte(name + ":" + a.hashes[name] + "\n"))
+	}
+	a.version = hex.EncodeToString(combined.Sum(nil))[:12]
+
+	return a, nil
+}
+
+// FS returns the underlying file system
+func (a *Assets) FS() fs.FS {
+	return a.fsys
+}
+
+// URL returns the cache-busting URL for an asset, with its content hash in
+// the file name
+func (a *Assets) URL(name string) string {
+	if hash, ok := a.hashes[name]; ok {
+		return "/static/" + fingerprint(name, hash)
+	}
+	return "/static/" + name
+}
+
+// Handler serves assets under /static/. Fingerprinted names are cached for a
+// year; plain names are revalidated against their hash on every use.
+func (a *Assets) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, "/static/")
+		if file, ok := a.names[name]; ok {
+			w.Header().Set("Cache-Control", immutable)
+			name = file
+		} else if hash, ok := a.hashes[name]; ok {
+			w.Header().Set("Cache-Control", "no-cache")
+			w.Header().Set("ETag", `"`+hash+`"`)
+		} else {
+			http.NotFound(w, r)
+			return
+		}
+
+		data, err := fs.ReadFile(a.fsys, name)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		http.ServeContent(w, r, name, time.Time{}, bytes.NewReader(data))
+	})
+}
+
+// fingerprint puts a hash before a file name's extension: app.js becomes
+// app.0123456789ab.js
+func fingerprint(name, hash string) string {
+	ext := path.Ext(name)
+	return strings.TrimSuffix(name, ext) + "." + hash + ext
+}
+
+// Version returns a hash covering every asset
+func (a *Assets) Version() string {
+	return a.version
+}
+
+// Names returns every asset path in sorted order
+func (a *Assets) Names() []string {
+	names := make([]string, 0, len(a.hashes))
+	for name := range a.hashes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"testing/fstest"
+)
+
+func TestHandler(t *testing.T) {
+	a, err := New(fstest.MapFS{
+		"app.js":    {Data: []byte("console.log('hi')")},
+		"style.css": {Data: []byte("body {}")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	get := func(path string, header ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		for i := 0; i+1 < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		rec := httptest.NewRecorder()
+		a.Handler().ServeHTTP(rec, req)
+		return rec
+	}
+
+	url := a.URL("app.js")
+	if !regexp.MustCompile(`^/static/app\.[0-9a-f]{12}\.js$`).MatchString(url) {
+		t.Fatalf("URL(app.js) = %q", url)
+	}
+	rec := get(url)
+	if rec.Code != http.StatusOK || rec.Body.String() != "console.log('hi')" {
+		t.Errorf("GET %s = %d %q", url, rec.Code, rec.Body)
+	}
+	if cc := rec.Header().Get("Cache-Control"); cc != immutable {
+		t.Errorf("fingerprinted Cache-Control = %q", cc)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "text/javascript; charset=utf-8" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+
+	// Plain names still work for old pages, but must be revalidated
+	rec = get("/static/style.css")
+	etag := rec.Header().Get("ETag")
+	if rec.Code != http.StatusOK || rec.Header().Get("Cache-Control") != "no-cache" || etag == "" {
+		t.Errorf("plain name = %d, Cache-Control %q, ETag %q", rec.Code, rec.Header().Get("Cache-Control"), etag)
+	}
+	if rec := get("/static/style.css", "If-None-Match", etag); rec.Code != http.StatusNotModified {
+		t.Errorf("revalidation = %d, want %d", rec.Code, http.StatusNotModified)
+	}
+
+	if rec := get("/static/app.000000000000.js"); rec.Code != http.StatusNotFound {
+		t.Errorf("stale fingerprint = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package audit keeps a persistent trail of operator actions
+package audit
+
+import (
+	"sort"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/store"
+)
+
+// Bucket holds the audit trail
+const Bucket = "audit"
+
+// retain is how many entries are kept before the oldest are dropped
+const retain = 10000
+
+// Trail is the audit log
+type Trail struct {
+	store *store.Store
+}
+
+// New creates a Trail backed by the given store
+func New(s *store.Store) *Trail {
+	return &Trail{store: s}
+}
+
+// Record appends an entry, stamping it with an ID and the given time
+func (t *Trail) Record(e models.AuditEntry, at time.Time) error {
+	id, err := t.store.NextID(Bucket)
+	if err != nil {
+		return err
+	}
+
+	e.ID = id
+	e.Time = at.UTC().Truncate(time.Second)
+	if err := t.store.Put(Bucket, id, e); err != nil {
+		return err
+	}
+	return t.store.Trim(Bucket, retain)
+}
+
+// List returns entries newest first, optionally for one printer, up to limit (0 for all)
+func (t *Trail) List(printerID string, limit int) ([]models.AuditEntry, error) {
+	all, err := store.List[models.AuditEntry](t.store, Bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(all, func(i, j int) bool { return all[i].ID > all[j].ID })
+
+	entries := []models.AuditEntry{}
+	for _, e := range all {
+		if limit > 0 && len(entries) >= limit {
+			break
+		}
+		if printerID == "" || e.PrinterID == printerID {
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
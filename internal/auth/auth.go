@@ -0,0 +1,239 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/wmarchesi123/octodash/internal/settings"
+)
+
+// Role is a user's permission level; higher roles include lower ones
+type Role int
+
+const (
+	RoleViewer Role = iota + 1
+	RoleOperator
+	RoleAdmin
+)
+
+// ParseRole converts a role name into a Role
+func ParseRole(name string) (Role, error) {
+	switch strings.ToLower(name) {
+	case "viewer":
+		return RoleViewer, nil
+	case "operator":
+		return RoleOperator, nil
+	case "admin":
+		return RoleAdmin, nil
+	default:
+		return 0, fmt.Errorf("unknown role %q", name)
+	}
+}
+
+func (r Role) String() string {
+	switch r {
+	case RoleViewer:
+		return "viewer"
+	case RoleOperator:
+		return "operator"
+	case RoleAdmin:
+		return "admin"
+	default:
+		return "unknown"
+	}
+}
+
+// User is an authenticated API caller. Printers are the printers the user may
+// operate even when their role is below operator.
+type User struct {
+	Name     string
+	Role     Role
+	Printers []string
+}
+
+// Allowed reports whether the user may take an action needing role on a
+// printer; printerID is empty for actions that don't target one. Printer
+// grants stand in for the operator role, never for admin.
+func (u *User) Allowed(role Role, printerID string) bool {
+	if u.Role >= role {
+		return true
+	}
+	return role <= RoleOperator && printerID != "" && slices.Contains(u.Printers, printerID)
+}
+
+// anonymous is used for every request when no users are configured
+var anonymous = &User{Name: "anonymous", Role: RoleAdmin}
+
+type contextKey struct{}
+
+// roleKey holds the role Require checked the user against
+type roleKey struct{}
+
+// Authenticator resolves API tokens to users and enforces roles
+type Authenticator struct {
+	users   map[string]*User
+	sources []TokenSource
+}
+
+// TokenSource resolves tokens issued at runtime rather than configured
+type TokenSource func(token string) (*User, bool)
+
+// New creates an Authenticator from the configured users
+func New(cfg settings.AuthSettings) (*Authenticator, error) {
+	a := &Authenticator{users: make(map[string]*User)}
+
+	for _, u := range cfg.Users {
+		role, err := ParseRole(u.Role)
+		if err != nil {
+			return nil, fmt.Errorf("user %s: %w", u.Name, err)
+		}
+		a.users[u.Token] = &User{Name: u.Name, Role: role, Printers: u.Printers}
+	}
+
+	return a, nil
+}
+
+// AddSource also accepts the tokens source knows. Call it before serving.
+func (a *Authenticator) AddSource(source TokenSource) {
+	a.sources = append(a.sources, source)
+}
+
+// Enabled reports whether any users are configured
+func (a *Authenticator) Enabled() bool {
+	return len(a.users) > 0
+}
+
+// Authenticate returns the user making the request, if any
+func (a *Authenticator) Authenticate(r *http.Request) (*User, bool) {
+	return a.AuthenticateToken(bearerToken(r))
+}
+
+// AuthenticateToken returns the user a bearer token belongs to, for callers
+// that don't come in over HTTP
+func (a *Authenticator) AuthenticateToken(token string) (*User, bool) {
+	if !a.Enabled() {
+		return anonymous, true
+	}
+
+	if token == "" {
+		return nil, false
+	}
+
+	for candidate, user := range a.users {
+		if subtle.ConstantTimeCompare([]byte(candidate), []byte(token)) == 1 {
+			return user, true
+		}
+	}
+	for _, source := range a.sources {
+		if user, ok := source(token); ok {
+			return user, true
+		}
+	}
+	return nil, false
+}
+
+// Identify attaches the caller to the request context when they present a
+// valid token; it never rejects requests, which is left to Require
+func (a *Authenticator) Identify(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, ok := a.Authenticate(r); ok {
+			r = r.WithContext(context.WithValue(r.Context(), contextKey{}, user))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Require wraps a handler so it only runs for users with at least the given role
+func (a *Authenticator) Require(role Role, next http.HandlerFunc) http.HandlerFunc {
+	return a.require(role, "", next)
+}
+
+// RequirePrinter is Require for routes acting on the printer named by the
+// path wildcard param, also letting in users granted that printer
+func (a *Authenticator) RequirePrinter(role Role, param string, next http.HandlerFunc) http.HandlerFunc {
+	return a.require(role, param, next)
+}
+
+func (a *Authenticator) require(role Role, param string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := r.Context().Value(contextKey{}).(*User)
+		if !ok {
+			user, ok = a.Authenticate(r)
+		}
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="octodash"`)
+			http.Error(w, "Authentication required", http.StatusUnauthorized)
+			return
+		}
+
+		printerID := ""
+		if param != "" {
+			printerID = r.PathValue(param)
+		}
+		if !user.Allowed(role, printerID) {
+			http.Error(w, "Insufficient permissions", http.StatusForbidden)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), contextKey{}, user)
+		next(w, r.WithContext(context.WithValue(ctx, roleKey{}, role)))
+	}
+}
+
+// RequiredRole returns the role Require demanded for the request, or 0 when
+// the route is open to everyone
+func RequiredRole(ctx context.Context) Role {
+	role, _ := ctx.Value(roleKey{}).(Role)
+	return role
+}
+
+// UserFromContext returns the user attached by Identify or Require
+func UserFromContext(ctx context.Context) *User {
+	if user, ok := ctx.Value(contextKey{}).(*User); ok {
+		return user
+	}
+	return anonymous
+}
+
+// WithUser attaches a user vouched for some other way than a token, such as
+// a signed URL
+func WithUser(ctx context.Context, user *User) context.Context {
+	return context.WithValue(ctx, contextKey{}, user)
+}
+
+// IdentifiedUser returns the user attached by Identify or Require, unlike
+// UserFromContext reporting when nobody presented a valid token
+func IdentifiedUser(ctx context.Context) (*User, bool) {
+	user, ok := ctx.Value(contextKey{}).(*User)
+	return user, ok
+}
+
+func bearerToken(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	if token, ok := strings.CutPrefix(header, "Bearer "); ok {
+		return strings.TrimSpace(token)
+	}
+	return ""
+}
@@ -0,0 +1,138 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/wmarchesi123/octodash/internal/settings"
+)
+
+func TestAllowed(t *testing.T) {
+	student := &User{Name: "sam", Role: RoleViewer, Printers: []string{"printer-1"}}
+	operator := &User{Name: "olga", Role: RoleOperator}
+
+	tests := []struct {
+		user    *User
+		role    Role
+		printer string
+		want    bool
+	}{
+		{student, RoleViewer, "", true},
+		{student, RoleOperator, "", false},
+		{student, RoleOperator, "printer-1", true},
+		{student, RoleOperator, "printer-2", false},
+		{student, RoleAdmin, "printer-1", false},
+		{operator, RoleOperator, "printer-2", true},
+		{operator, RoleAdmin, "", false},
+	}
+	for _, tt := range tests {
+		if got := tt.user.Allowed(tt.role, tt.printer); got != tt.want {
+			t.Errorf("%s Allowed(%s, %q) = %v, want %v", tt.user.Name, tt.role, tt.printer, got, tt.want)
+		}
+	}
+}
+
+func TestRequire(t *testing.T) {
+	a, err := New(settings.AuthSettings{Users: []settings.UserSettings{
+		{Name: "vic", Role: "viewer", Token: "vic-token"},
+		{Name: "sam", Role: "viewer", Token: "sam-token", Printers: []string{"printer-1"}},
+		{Name: "olga", Role: "operator", Token: "olga-token"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.AddSource(func(token string) (*User, bool) {
+		if token == "issued-token" {
+			return &User{Name: "kiosk", Role: RoleOperator}, true
+		}
+		return nil, false
+	})
+
+	var got *User
+	mux := http.NewServeMux()
+	ok := func(w http.ResponseWriter, r *http.Request) {
+		got = UserFromContext(r.Context())
+		if role := RequiredRole(r.Context()); role != RoleOperator {
+			t.Errorf("RequiredRole = %s, want operator", role)
+		}
+	}
+	mux.HandleFunc("POST /farm", a.Require(RoleOperator, ok))
+	mux.HandleFunc("POST /printers/{id}", a.RequirePrinter(RoleOperator, "id", ok))
+
+	tests := []struct {
+		token string
+		path  string
+		code  int
+		user  string
+	}{
+		{"", "/farm", http.StatusUnauthorized, ""},
+		{"wrong-token", "/farm", http.StatusUnauthorized, ""},
+		{"vic-token", "/farm", http.StatusForbidden, ""},
+		{"olga-token", "/farm", http.StatusOK, "olga"},
+		{"issued-token", "/farm", http.StatusOK, "kiosk"},
+		{"sam-token", "/farm", http.StatusForbidden, ""},
+		{"sam-token", "/printers/printer-1", http.StatusOK, "sam"},
+		{"sam-token", "/printers/printer-2", http.StatusForbidden, ""},
+		{"vic-token", "/printers/printer-1", http.StatusForbidden, ""},
+	}
+	for _, tt := range tests {
+		got = nil
+		req := httptest.NewRequest("POST", tt.path, nil)
+		if tt.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		if rec.Code != tt.code {
+			t.Errorf("%q %s = %d, want %d", tt.token, tt.path, rec.Code, tt.code)
+			continue
+		}
+		if tt.code == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("%q %s: no WWW-Authenticate challenge", tt.token, tt.path)
+		}
+		if tt.user != "" && (got == nil || got.Name != tt.user) {
+			t.Errorf("%q %s ran as %v, want %s", tt.token, tt.path, got, tt.user)
+		}
+		if tt.user == "" && got != nil {
+			t.Errorf("%q %s reached the handler", tt.token, tt.path)
+		}
+	}
+}
+
+func TestRequireWithoutUsers(t *testing.T) {
+	a, err := New(settings.AuthSettings{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got *User
+	handler := a.Require(RoleAdmin, func(w http.ResponseWriter, r *http.Request) {
+		got = UserFromContext(r.Context())
+	})
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest("POST", "/", nil))
+
+	if rec.Code != http.StatusOK || got == nil || got.Role != RoleAdmin {
+		t.Errorf("open install = %d as %v, want everyone let in as admin", rec.Code, got)
+	}
+}
@@ -0,0 +1,277 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/store"
+	"github.com/wmarchesi123/octodash/internal/version"
+)
+
+// Archive entry names
+const (
+	manifestName = "manifest.json"
+	databaseName = store.FileName
+	configName   = "config.env"
+	dataPrefix   = "data/"
+)
+
+// Manifest describes a backup archive
+type Manifest struct {
+	Version   string    `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Source is everything that goes into a backup
+type Source struct {
+	Store   *store.Store
+	DataDir string
+	// Environ is the OctoDash configuration as KEY=value lines
+	Environ []string
+	// Exclude lists directories inside DataDir to leave out, e.g. local backups
+	Exclude []string
+}
+
+// FileName returns the archive name for a backup taken at t
+func FileName(t time.Time) string {
+	return "octodash-backup-" + t.UTC().Format("20060102-150405") + ".tar.gz"
+}
+
+// Write streams a gzipped tarball of the database, configuration and any
+// other files in the data directory (archived thumbnails, snapshots) to w
+func Write(w io.Writer, src Source, now time.Time) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	manifest, _ := json.MarshalIndent(Manifest{Version: version.String(), CreatedAt: now.UTC()}, "", "  ")
+	if err := writeFile(tw, manifestName, manifest, now); err != nil {
+		return err
+	}
+
+	if err := writeDatabase(tw, src.Store, now); err != nil {
+		return err
+	}
+
+	env := strings.Join(src.Environ, "\n") + "\n"
+	if err := writeFile(tw, configName, []byte(env), now); err != nil {
+		return err
+	}
+
+	err := filepath.WalkDir(src.DataDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src.DataDir, p)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if excluded(p, src.Exclude) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		// The live database is covered by the consistent snapshot above
+		if rel == store.FileName || strings.HasPrefix(rel, store.FileName+".") {
+			return nil
+		}
+
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return copyFile(tw, dataPrefix+filepath.ToSlash(rel), f)
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("archive data dir: %w", err)
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func excluded(dir string, exclude []string) bool {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	for _, e := range exclude {
+		if e, err := filepath.Abs(e); err == nil && e == abs {
+			return true
+		}
+	}
+	return false
+}
+
+// writeDatabase snapshots the store to a temporary file rather than memory,
+// then copies it into the archive. Snapshotting straight into a slow download
+// would hold the store's lock, stalling Restore and Compact and every write
+// queued behind them.
+func writeDatabase(tw *tar.Writer, s *store.Store, now time.Time) error {
+	f, err := os.CreateTemp("", "octodash-backup-*.db")
+	if err != nil {
+		return fmt.Errorf("snapshot database: %w", err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	size, err := s.Backup(f)
+	if err != nil {
+		return fmt.Errorf("snapshot database: %w", err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	return writeEntry(tw, databaseName, size, now, f)
+}
+
+// copyFile archives an open file, streaming its contents
+func copyFile(tw *tar.Writer, name string, f *os.File) error {
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	return writeEntry(tw, name, info.Size(), info.ModTime(), f)
+}
+
+func writeEntry(tw *tar.Writer, name string, size int64, modTime time.Time, r io.Reader) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0o600,
+		Size:    size,
+		ModTime: modTime,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := io.CopyN(tw, r, size)
+	return err
+}
+
+func writeFile(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0o600,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+// Restore replaces the database and data files with those in a backup
+// archive. The archived configuration is not applied: it is kept as
+// config.env in the data directory for reference since OctoDash reads its
+// configuration from the environment.
+func Restore(r io.Reader, dst Source) (*Manifest, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a gzip archive: %w", err)
+	}
+	tr := tar.NewReader(gz)
+
+	var manifest *Manifest
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		switch {
+		case hdr.Name == manifestName:
+			manifest = &Manifest{}
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+				return nil, fmt.Errorf("read manifest: %w", err)
+			}
+
+		case hdr.Name == databaseName:
+			if manifest == nil {
+				return nil, errors.New("archive has no manifest")
+			}
+			if err := dst.Store.Restore(tr); err != nil {
+				return nil, fmt.Errorf("restore database: %w", err)
+			}
+
+		case hdr.Name == configName:
+			if err := restoreFile(dst.DataDir, configName+".restored", tr); err != nil {
+				return nil, err
+			}
+
+		case strings.HasPrefix(hdr.Name, dataPrefix):
+			if err := restoreFile(dst.DataDir, strings.TrimPrefix(hdr.Name, dataPrefix), tr); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if manifest == nil {
+		return nil, errors.New("archive has no manifest")
+	}
+	return manifest, nil
+}
+
+// restoreFile writes name under dir, refusing paths that escape it
+func restoreFile(dir, name string, r io.Reader) error {
+	clean := path.Clean("/" + name)[1:]
+	if clean == "" || clean == store.FileName {
+		return fmt.Errorf("invalid file in archive: %q", name)
+	}
+
+	target := filepath.Join(dir, filepath.FromSlash(clean))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
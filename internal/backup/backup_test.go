@@ -0,0 +1,111 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/store"
+)
+
+func openStore(t *testing.T, dir string) *store.Store {
+	t.Helper()
+	s, err := store.Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestBackupRestoreRoundTrip(t *testing.T) {
+	srcDir := t.TempDir()
+	src := openStore(t, srcDir)
+	if err := src.Put("queue", "0000000001", map[string]string{"file": "benchy.gcode"}); err != nil {
+		t.Fatal(err)
+	}
+	os.MkdirAll(filepath.Join(srcDir, "thumbnails"), 0o755)
+	os.WriteFile(filepath.Join(srcDir, "thumbnails", "benchy.png"), []byte("png"), 0o644)
+	os.MkdirAll(filepath.Join(srcDir, "backups"), 0o755)
+	os.WriteFile(filepath.Join(srcDir, "backups", "old.tar.gz"), []byte("old"), 0o644)
+
+	var archive bytes.Buffer
+	err := Write(&archive, Source{
+		Store:   src,
+		DataDir: srcDir,
+		Environ: []string{"PRINTER_1_NAME=Prusa"},
+		Exclude: []string{filepath.Join(srcDir, "backups")},
+	}, time.Now())
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	dstDir := t.TempDir()
+	dst := openStore(t, dstDir)
+	manifest, err := Restore(&archive, Source{Store: dst, DataDir: dstDir})
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if manifest.Version == "" {
+		t.Error("manifest has no version")
+	}
+
+	var entry map[string]string
+	if ok, err := dst.Get("queue", "0000000001", &entry); !ok || err != nil || entry["file"] != "benchy.gcode" {
+		t.Errorf("restored entry = %v (found %v, err %v)", entry, ok, err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dstDir, "thumbnails", "benchy.png")); err != nil || string(data) != "png" {
+		t.Errorf("thumbnail not restored: %v", err)
+	}
+	if env, err := os.ReadFile(filepath.Join(dstDir, "config.env.restored")); err != nil || string(env) != "PRINTER_1_NAME=Prusa\n" {
+		t.Errorf("config.env.restored = %q, %v", env, err)
+	}
+	if _, err := os.Stat(filepath.Join(dstDir, "backups", "old.tar.gz")); !os.IsNotExist(err) {
+		t.Error("excluded backups directory was archived")
+	}
+}
+
+func TestRestoreRejectsPathTraversal(t *testing.T) {
+	var archive bytes.Buffer
+	gz := gzip.NewWriter(&archive)
+	tw := tar.NewWriter(gz)
+	writeFile(tw, manifestName, []byte(`{"version":"test"}`), time.Now())
+	writeFile(tw, dataPrefix+"../../escape.txt", []byte("x"), time.Now())
+	tw.Close()
+	gz.Close()
+
+	dir := filepath.Join(t.TempDir(), "data")
+	dst := openStore(t, dir)
+	if _, err := Restore(&archive, Source{Store: dst, DataDir: dir}); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "..", "escape.txt")); !os.IsNotExist(err) {
+		t.Error("file escaped the data directory")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "escape.txt")); err != nil {
+		t.Errorf("cleaned path not written inside data dir: %v", err)
+	}
+}
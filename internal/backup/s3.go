@@ -0,0 +1,35 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+
+	"github.com/wmarchesi123/octodash/internal/s3"
+)
+
+// S3Target uploads backups to an S3-compatible bucket
+type S3Target struct {
+	s3.Client
+}
+
+// Put uploads the archive as Prefix+name
+func (t *S3Target) Put(ctx context.Context, name string, data []byte) error {
+	return t.Client.Put(ctx, name, "application/gzip", data)
+}
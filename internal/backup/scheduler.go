@@ -0,0 +1,73 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"time"
+)
+
+// Scheduler takes a backup every interval and stores it in each target
+type Scheduler struct {
+	source   Source
+	targets  []Target
+	interval time.Duration
+	logger   *log.Logger
+}
+
+// NewScheduler creates a scheduler; call Run to start it
+func NewScheduler(src Source, targets []Target, interval time.Duration, logger *log.Logger) *Scheduler {
+	return &Scheduler{source: src, targets: targets, interval: interval, logger: logger}
+}
+
+// Run takes backups until ctx is cancelled
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.runOnce(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *Scheduler) runOnce(ctx context.Context) {
+	now := time.Now()
+
+	var buf bytes.Buffer
+	if err := Write(&buf, s.source, now); err != nil {
+		s.logger.Printf("Scheduled backup failed: %v", err)
+		return
+	}
+
+	name := FileName(now)
+	for _, t := range s.targets {
+		if err := t.Put(ctx, name, buf.Bytes()); err != nil {
+			s.logger.Printf("Storing backup in %s failed: %v", t.Name(), err)
+			continue
+		}
+		s.logger.Printf("Backup %s stored in %s", name, t.Name())
+	}
+}
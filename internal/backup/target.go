@@ -0,0 +1,90 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Target is somewhere scheduled backups are stored
+type Target interface {
+	Name() string
+	Put(ctx context.Context, name string, data []byte) error
+}
+
+// LocalTarget keeps the newest Keep backups in Dir
+type LocalTarget struct {
+	Dir  string
+	Keep int
+}
+
+// Name describes the target in logs
+func (t *LocalTarget) Name() string {
+	return t.Dir
+}
+
+// Put writes the archive and prunes old ones
+func (t *LocalTarget) Put(ctx context.Context, name string, data []byte) error {
+	if err := os.MkdirAll(t.Dir, 0o700); err != nil {
+		return err
+	}
+
+	tmp := filepath.Join(t.Dir, "."+name+".tmp")
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(t.Dir, name)); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	return t.prune()
+}
+
+func (t *LocalTarget) prune() error {
+	if t.Keep <= 0 {
+		return nil
+	}
+
+	entries, err := os.ReadDir(t.Dir)
+	if err != nil {
+		return err
+	}
+
+	var backups []string
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), "octodash-backup-") && strings.HasSuffix(e.Name(), ".tar.gz") {
+			backups = append(backups, e.Name())
+		}
+	}
+
+	// Names embed the timestamp, so lexical order is chronological
+	sort.Strings(backups)
+	for len(backups) > t.Keep {
+		if err := os.Remove(filepath.Join(t.Dir, backups[0])); err != nil {
+			return err
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
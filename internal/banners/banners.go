@@ -0,0 +1,147 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package banners stores farm-wide notices shown on every dashboard
+package banners
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/store"
+)
+
+const bucket = "banners"
+
+// maxMessage caps a banner's length so it fits across a dashboard
+const maxMessage = 280
+
+var (
+	// ErrNotFound is returned when a banner does not exist
+	ErrNotFound = errors.New("banner not found")
+	// ErrInvalid is returned for banners that can't be posted as given
+	ErrInvalid = errors.New("invalid banner")
+)
+
+// severityRank orders banners so the most urgent shows first
+var severityRank = map[string]int{
+	models.BannerInfo:     0,
+	models.BannerWarning:  1,
+	models.BannerCritical: 2,
+}
+
+// Board is the persistent set of banners
+type Board struct {
+	store *store.Store
+}
+
+// New creates a Board backed by the given store
+func New(s *store.Store) *Board {
+	return &Board{store: s}
+}
+
+// Active returns the banners showing at the given time, most severe first and
+// newest first within a severity
+func (b *Board) Active(at time.Time) ([]models.Banner, error) {
+	all, err := store.List[models.Banner](b.store, bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	active := []models.Banner{}
+	for _, banner := range all {
+		if banner.Active(at) {
+			active = append(active, banner)
+		}
+	}
+	sort.SliceStable(active, func(i, j int) bool {
+		if ri, rj := severityRank[active[i].Severity], severityRank[active[j].Severity]; ri != rj {
+			return ri > rj
+		}
+		return active[i].CreatedAt.After(active[j].CreatedAt)
+	})
+	return active, nil
+}
+
+// Post validates and stores a banner, defaulting to info severity. Expired
+// banners are cleared out at the same time.
+func (b *Board) Post(banner models.Banner, now time.Time) (models.Banner, error) {
+	banner.Message = strings.TrimSpace(banner.Message)
+	if banner.Message == "" || len(banner.Message) > maxMessage {
+		return banner, fmt.Errorf("%w: message must be 1 to %d characters", ErrInvalid, maxMessage)
+	}
+	if banner.Severity == "" {
+		banner.Severity = models.BannerInfo
+	}
+	if _, ok := severityRank[banner.Severity]; !ok {
+		return banner, fmt.Errorf("%w: severity must be info, warning or critical", ErrInvalid)
+	}
+	if banner.ExpiresAt != nil {
+		expires := banner.ExpiresAt.UTC()
+		if !expires.After(now) {
+			return banner, fmt.Errorf("%w: expires_at must be in the future", ErrInvalid)
+		}
+		banner.ExpiresAt = &expires
+	}
+
+	if err := b.prune(now); err != nil {
+		return banner, err
+	}
+
+	var err error
+	if banner.ID, err = b.store.NextID(bucket); err != nil {
+		return banner, err
+	}
+	banner.CreatedAt = now.UTC()
+	return banner, b.store.Put(bucket, banner.ID, banner)
+}
+
+// Remove takes a banner down
+func (b *Board) Remove(id string) error {
+	var banner models.Banner
+	ok, err := b.store.Get(bucket, id, &banner)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrNotFound
+	}
+	return b.store.Delete(bucket, id)
+}
+
+// prune deletes banners that expired before now
+func (b *Board) prune(now time.Time) error {
+	all, err := store.List[models.Banner](b.store, bucket)
+	if err != nil {
+		return err
+	}
+	var expired []string
+	for _, banner := range all {
+		if !banner.Active(now) {
+			expired = append(expired, banner.ID)
+		}
+	}
+	if len(expired) == 0 {
+		return nil
+	}
+	return b.store.DeleteKeys(bucket, expired)
+}
@@ -0,0 +1,537 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package camarchive copies printer camera media to an S3-compatible bucket:
+// periodic snapshots, clips of the frames leading up to a failed print, and
+// OctoPrint's rendered timelapses. Objects are expired per kind, so the bucket
+// needs no lifecycle configuration of its own.
+package camarchive
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/octoapi"
+	"github.com/wmarchesi123/octodash/internal/s3"
+)
+
+const (
+	// timelapseInterval is how often OctoPrint is checked for new timelapses
+	timelapseInterval = 10 * time.Minute
+	// expireInterval is how often expired objects are removed
+	expireInterval = time.Hour
+	// maxFrameSize bounds a single camera snapshot
+	maxFrameSize = 8 << 20
+)
+
+var (
+	// ErrUnknownPrinter is returned for a printer without a camera here
+	ErrUnknownPrinter = errors.New("unknown printer")
+
+	// ErrNoFrames is returned when a clip is asked for before any frames were captured
+	ErrNoFrames = errors.New("no frames captured")
+)
+
+// Bucket is where media is archived
+type Bucket interface {
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	PutStream(ctx context.Context, key, contentType string, r io.Reader, size int64) error
+	List(ctx context.Context, prefix string) ([]s3.Object, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// Timelapses is OctoPrint's timelapse API
+type Timelapses interface {
+	Timelapses() ([]octoapi.Timelapse, error)
+	OpenTimelapse(ctx context.Context, name string) (io.ReadCloser, int64, error)
+	DeleteTimelapse(name string) error
+}
+
+// Camera is a printer whose media is archived
+type Camera struct {
+	ID          string
+	Name        string
+	SnapshotURL string
+	Timelapses  Timelapses
+}
+
+// Options tunes the archiver
+type Options struct {
+	// SnapshotInterval between stored snapshots while printing; zero stores none
+	SnapshotInterval time.Duration
+	// ClipInterval between frames kept for clips while printing
+	ClipInterval time.Duration
+	// ClipFrames is how many recent frames a clip holds; zero saves no clips
+	ClipFrames int
+	// Timelapses copies OctoPrint's rendered timelapses
+	Timelapses bool
+	// DeleteTimelapses removes timelapses from OctoPrint once they are archived
+	DeleteTimelapses bool
+	// Retention is how long each kind of media is kept; missing or zero keeps it
+	Retention map[string]time.Duration
+}
+
+// Archiver captures camera frames while printers print and uploads media to the bucket
+type Archiver struct {
+	bucket  Bucket
+	cameras []Camera
+	opts    Options
+	now     func() time.Time
+	logger  *log.Logger
+	client  *http.Client
+	clips   chan clip
+
+	mu    sync.Mutex
+	state map[string]*cameraState
+}
+
+type frame struct {
+	at   time.Time
+	data []byte
+}
+
+type cameraState struct {
+	printing bool
+	file     string
+	// frames holds the most recent captures, oldest first
+	frames []frame
+
+	lastSnapshot  time.Time
+	lastClip      time.Time
+	lastTimelapse time.Time
+	uploaded      int64
+	lastErr       string
+}
+
+// clip is a set of frames waiting to be uploaded
+type clip struct {
+	camera Camera
+	reason string
+	file   string
+	at     time.Time
+	frames []frame
+}
+
+// New creates an archiver; call Run to start it
+func New(bucket Bucket, cameras []Camera, opts Options, now func() time.Time, logger *log.Logger) *Archiver {
+	a := &Archiver{
+		bucket:  bucket,
+		cameras: cameras,
+		opts:    opts,
+		now:     now,
+		logger:  logger,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		clips:   make(chan clip, len(cameras)),
+		state:   make(map[string]*cameraState),
+	}
+	for _, c := range cameras {
+		a.state[c.ID] = &cameraState{}
+	}
+	return a
+}
+
+// Observe records which printers are printing, so Run knows whose cameras to
+// capture. Frames from an earlier job are dropped when a new one starts.
+func (a *Archiver) Observe(printers []*models.PrinterStatus) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, p := range printers {
+		st, ok := a.state[p.ID]
+		if !ok {
+			continue
+		}
+		st.printing = p.Status == "printing"
+		if st.printing && p.Progress != nil && p.Progress.FileName != st.file {
+			st.file = p.Progress.FileName
+			st.frames = nil
+		}
+	}
+}
+
+// SaveClip queues the printer's recent frames to be stored as a clip
+func (a *Archiver) SaveClip(printerID, reason string) error {
+	c, ok := a.camera(printerID)
+	if !ok {
+		return ErrUnknownPrinter
+	}
+
+	a.mu.Lock()
+	st := a.state[printerID]
+	pending := clip{
+		camera: c,
+		reason: reason,
+		file:   st.file,
+		at:     a.now().UTC(),
+		frames: st.frames,
+	}
+	// Later captures start a fresh slice, so the clip keeps these frames
+	st.frames = nil
+	a.mu.Unlock()
+
+	if len(pending.frames) == 0 {
+		return ErrNoFrames
+	}
+
+	select {
+	case a.clips <- pending:
+		return nil
+	default:
+		return fmt.Errorf("too many clips waiting to upload")
+	}
+}
+
+// Status reports each camera's archive activity
+func (a *Archiver) Status() []models.CameraArchiveStatus {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	statuses := make([]models.CameraArchiveStatus, 0, len(a.cameras))
+	for _, c := range a.cameras {
+		st := a.state[c.ID]
+		statuses = append(statuses, models.CameraArchiveStatus{
+			PrinterID:     c.ID,
+			Name:          c.Name,
+			Capturing:     st.printing && a.capturing(),
+			Frames:        len(st.frames),
+			LastSnapshot:  timePtr(st.lastSnapshot),
+			LastClip:      timePtr(st.lastClip),
+			LastTimelapse: timePtr(st.lastTimelapse),
+			Uploaded:      st.uploaded,
+			LastError:     st.lastErr,
+		})
+	}
+	return statuses
+}
+
+// Run captures frames, uploads clips and snapshots, copies timelapses and
+// expires old media until ctx is cancelled
+func (a *Archiver) Run(ctx context.Context) {
+	go a.maintain(ctx)
+
+	var tick <-chan time.Time
+	if a.capturing() {
+		ticker := time.NewTicker(a.captureInterval())
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-tick:
+			a.capture(ctx)
+		case c := <-a.clips:
+			a.upload(ctx, c)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// maintain copies timelapses and expires media, both of which can take a
+// while, away from frame capture
+func (a *Archiver) maintain(ctx context.Context) {
+	timelapses := time.NewTicker(timelapseInterval)
+	defer timelapses.Stop()
+	expiry := time.NewTicker(expireInterval)
+	defer expiry.Stop()
+
+	a.copyTimelapses(ctx)
+	a.expire(ctx)
+	for {
+		select {
+		case <-timelapses.C:
+			a.copyTimelapses(ctx)
+		case <-expiry.C:
+			a.expire(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// capturing reports whether frames are grabbed at all
+func (a *Archiver) capturing() bool {
+	return a.opts.SnapshotInterval > 0 || a.opts.ClipFrames > 0
+}
+
+// captureInterval is how often frames are grabbed: every ClipInterval when
+// clips are kept, otherwise only as often as snapshots are stored
+func (a *Archiver) captureInterval() time.Duration {
+	if a.opts.ClipFrames > 0 {
+		return a.opts.ClipInterval
+	}
+	return a.opts.SnapshotInterval
+}
+
+// capture grabs a frame from every printing camera, keeping it for clips and
+// storing it as a snapshot when one is due
+func (a *Archiver) capture(ctx context.Context) {
+	for _, c := range a.cameras {
+		a.mu.Lock()
+		printing := a.state[c.ID].printing
+		a.mu.Unlock()
+		if !printing {
+			continue
+		}
+
+		data, err := a.snapshot(ctx, c.SnapshotURL)
+		if err != nil {
+			a.fail(c, fmt.Errorf("snapshot: %w", err))
+			continue
+		}
+		now := a.now().UTC()
+
+		a.mu.Lock()
+		st := a.state[c.ID]
+		if a.opts.ClipFrames > 0 {
+			st.frames = append(st.frames, frame{at: now, data: data})
+			if extra := len(st.frames) - a.opts.ClipFrames; extra > 0 {
+				st.frames = append([]frame(nil), st.frames[extra:]...)
+			}
+		}
+		due := a.opts.SnapshotInterval > 0 && now.Sub(st.lastSnapshot) >= a.opts.SnapshotInterval
+		a.mu.Unlock()
+
+		if due {
+			key := path.Join(models.MediaSnapshot, c.ID, now.Format("2006-01-02"), now.Format("150405")+".jpg")
+			if a.put(ctx, c, key, "image/jpeg", data) {
+				a.mu.Lock()
+				a.state[c.ID].lastSnapshot = now
+				a.mu.Unlock()
+			}
+		}
+	}
+}
+
+// snapshot fetches one JPEG from a camera
+func (a *Archiver) snapshot(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFrameSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxFrameSize {
+		return nil, fmt.Errorf("snapshot larger than %d bytes", maxFrameSize)
+	}
+	return data, nil
+}
+
+// upload stores a clip's frames, then the clip.json describing them
+func (a *Archiver) upload(ctx context.Context, c clip) {
+	dir := path.Join(models.MediaClip, c.camera.ID, c.at.Format("20060102-150405")+"-"+c.reason)
+	manifest := models.CameraClip{
+		PrinterID: c.camera.ID,
+		Name:      c.camera.Name,
+		Reason:    c.reason,
+		File:      c.file,
+		SavedAt:   c.at,
+	}
+	for i, f := range c.frames {
+		key := path.Join(dir, fmt.Sprintf("frame-%03d.jpg", i))
+		if !a.put(ctx, c.camera, key, "image/jpeg", f.data) {
+			return
+		}
+		manifest.Frames = append(manifest.Frames, models.ClipFrame{Key: key, Time: f.at})
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		a.fail(c.camera, err)
+		return
+	}
+	if a.put(ctx, c.camera, path.Join(dir, "clip.json"), "application/json", data) {
+		a.mu.Lock()
+		a.state[c.camera.ID].lastClip = c.at
+		a.mu.Unlock()
+		a.logger.Printf("Saved %s clip of %s (%d frames)", c.reason, c.camera.Name, len(c.frames))
+	}
+}
+
+// copyTimelapses uploads timelapses the bucket doesn't have yet, then removes
+// archived ones from OctoPrint when asked to
+func (a *Archiver) copyTimelapses(ctx context.Context) {
+	if !a.opts.Timelapses {
+		return
+	}
+	for _, c := range a.cameras {
+		if c.Timelapses == nil || ctx.Err() != nil {
+			continue
+		}
+		if err := a.copyTimelapsesOf(ctx, c); err != nil {
+			a.fail(c, fmt.Errorf("timelapses: %w", err))
+		}
+	}
+}
+
+func (a *Archiver) copyTimelapsesOf(ctx context.Context, c Camera) error {
+	remote, err := c.Timelapses.Timelapses()
+	if err != nil {
+		return err
+	}
+	if len(remote) == 0 {
+		return nil
+	}
+
+	dir := path.Join(models.MediaTimelapse, c.ID)
+	stored, err := a.bucket.List(ctx, dir+"/")
+	if err != nil {
+		return err
+	}
+	sizes := make(map[string]int64, len(stored))
+	for _, o := range stored {
+		sizes[o.Key] = o.Size
+	}
+
+	for _, t := range remote {
+		key := path.Join(dir, t.Name)
+		if size, ok := sizes[key]; !ok || size != t.Bytes {
+			if err := a.copyTimelapse(ctx, c, t.Name, key); err != nil {
+				return fmt.Errorf("copy %s: %w", t.Name, err)
+			}
+		}
+		if a.opts.DeleteTimelapses {
+			if err := c.Timelapses.DeleteTimelapse(t.Name); err != nil {
+				return fmt.Errorf("delete %s: %w", t.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// copyTimelapse streams one timelapse from OctoPrint into the bucket
+func (a *Archiver) copyTimelapse(ctx context.Context, c Camera, name, key string) error {
+	body, size, err := c.Timelapses.OpenTimelapse(ctx, name)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	contentType := mime.TypeByExtension(path.Ext(name))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	if err := a.bucket.PutStream(ctx, key, contentType, body, size); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	st := a.state[c.ID]
+	st.lastTimelapse = a.now().UTC()
+	st.uploaded += size
+	a.mu.Unlock()
+	a.logger.Printf("Archived timelapse %s of %s (%d bytes)", name, c.Name, size)
+	return nil
+}
+
+// expire removes media older than its kind's retention
+func (a *Archiver) expire(ctx context.Context) {
+	now := a.now()
+	for _, kind := range models.MediaKinds {
+		keep := a.opts.Retention[kind]
+		if keep <= 0 {
+			continue
+		}
+
+		objects, err := a.bucket.List(ctx, kind+"/")
+		if err != nil {
+			a.logger.Printf("Listing archived %s failed: %v", kind, err)
+			continue
+		}
+		removed := 0
+		for _, o := range objects {
+			if now.Sub(o.LastModified) < keep {
+				continue
+			}
+			if err := a.bucket.Delete(ctx, o.Key); err != nil {
+				a.logger.Printf("Expiring %s failed: %v", o.Key, err)
+				continue
+			}
+			removed++
+		}
+		if removed > 0 {
+			a.logger.Printf("Expired %d archived %s", removed, kind)
+		}
+	}
+}
+
+// put uploads one object, recording the outcome against the camera
+func (a *Archiver) put(ctx context.Context, c Camera, key, contentType string, data []byte) bool {
+	if err := a.bucket.Put(ctx, key, contentType, data); err != nil {
+		a.fail(c, fmt.Errorf("upload %s: %w", key, err))
+		return false
+	}
+
+	a.mu.Lock()
+	st := a.state[c.ID]
+	st.uploaded += int64(len(data))
+	st.lastErr = ""
+	a.mu.Unlock()
+	return true
+}
+
+// fail records an error against the camera, logging it unless it repeats the last one
+func (a *Archiver) fail(c Camera, err error) {
+	a.mu.Lock()
+	st := a.state[c.ID]
+	repeated := st.lastErr == err.Error()
+	st.lastErr = err.Error()
+	a.mu.Unlock()
+
+	if !repeated {
+		a.logger.Printf("Camera archive of %s: %v", c.Name, err)
+	}
+}
+
+func (a *Archiver) camera(id string) (Camera, bool) {
+	for _, c := range a.cameras {
+		if c.ID == id {
+			return c, true
+		}
+	}
+	return Camera{}, false
+}
+
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,126 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package camarchive
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/octoapi"
+	"github.com/wmarchesi123/octodash/internal/s3"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestArchiver(t *testing.T) {
+	op := testutil.NewOctoPrint(t)
+	server := testutil.NewS3(t)
+	bucket := &s3.Client{Endpoint: server.URL, Region: "us-east-1", Bucket: "farm", AccessKey: "key", SecretKey: "secret"}
+
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	clock := func() time.Time { return now }
+	a := New(bucket, []Camera{{
+		ID:          "printer-1",
+		Name:        "Mini",
+		SnapshotURL: op.URL + "/webcam/?action=snapshot",
+		Timelapses:  octoapi.NewClient(op.URL, ""),
+	}}, Options{
+		SnapshotInterval: time.Hour,
+		ClipInterval:     10 * time.Second,
+		ClipFrames:       2,
+		Timelapses:       true,
+		DeleteTimelapses: true,
+		Retention:        map[string]time.Duration{models.MediaSnapshot: 24 * time.Hour},
+	}, clock, log.New(io.Discard, "", 0))
+	ctx := context.Background()
+
+	// Idle printers aren't captured
+	a.capture(ctx)
+	if keys := server.Keys(); len(keys) != 0 {
+		t.Fatalf("captured while idle: %v", keys)
+	}
+
+	a.Observe([]*models.PrinterStatus{{ID: "printer-1", Status: "printing", Progress: &models.ProgressInfo{FileName: "gear.gcode"}}})
+	for i := 0; i < 3; i++ {
+		a.capture(ctx)
+		now = now.Add(10 * time.Second)
+	}
+	if keys := server.Keys(); !slices.Equal(keys, []string{"farm/snapshots/printer-1/2025-01-02/030405.jpg"}) {
+		t.Errorf("snapshots = %v, want one per hour", keys)
+	}
+	if st := a.Status()[0]; st.Frames != 2 || !st.Capturing || st.LastSnapshot == nil || st.LastError != "" {
+		t.Errorf("status = %+v, want two frames kept and a snapshot", st)
+	}
+
+	if err := a.SaveClip("printer-1", models.ClipFailed); err != nil {
+		t.Fatalf("SaveClip: %v", err)
+	}
+	a.upload(ctx, <-a.clips)
+	dir := "farm/clips/printer-1/20250102-030435-failed/"
+	for _, name := range []string{"frame-000.jpg", "frame-001.jpg"} {
+		if o, ok := server.Object(dir + name); !ok || string(o.Data) != string(testutil.Snapshot) {
+			t.Errorf("%s not stored", name)
+		}
+	}
+	o, _ := server.Object(dir + "clip.json")
+	var manifest models.CameraClip
+	if err := json.Unmarshal(o.Data, &manifest); err != nil || manifest.Reason != models.ClipFailed || manifest.File != "gear.gcode" || len(manifest.Frames) != 2 {
+		t.Errorf("clip.json = %s (%v)", o.Data, err)
+	}
+
+	if err := a.SaveClip("printer-1", models.ClipManual); !errors.Is(err, ErrNoFrames) {
+		t.Errorf("second SaveClip = %v, want ErrNoFrames", err)
+	}
+	if err := a.SaveClip("printer-9", models.ClipManual); !errors.Is(err, ErrUnknownPrinter) {
+		t.Errorf("SaveClip(unknown) = %v, want ErrUnknownPrinter", err)
+	}
+
+	op.SetTimelapse("gear_20250102.mp4", []byte("timelapse video"))
+	a.copyTimelapses(ctx)
+	if o, ok := server.Object("farm/timelapses/printer-1/gear_20250102.mp4"); !ok || string(o.Data) != "timelapse video" || o.ContentType != "video/mp4" {
+		t.Errorf("timelapse = %+v, %v", o, ok)
+	}
+	if left := op.Timelapses(); len(left) != 0 {
+		t.Errorf("OctoPrint still holds %v after archiving", left)
+	}
+
+	// Only snapshots have a retention, and only the old one has passed it
+	old := now.Add(-48 * time.Hour)
+	server.SetObject("farm/snapshots/printer-1/2024-12-31/030405.jpg", []byte("old"), old)
+	server.SetObject("farm/clips/printer-1/20241231-030405-failed/clip.json", []byte("{}"), old)
+	a.expire(ctx)
+	for _, key := range server.Keys() {
+		if strings.HasPrefix(key, "farm/snapshots/printer-1/2024-12-31/") {
+			t.Errorf("%s not expired", key)
+		}
+	}
+	if _, ok := server.Object("farm/clips/printer-1/20241231-030405-failed/clip.json"); !ok {
+		t.Error("clip expired without a clip retention")
+	}
+	if _, ok := server.Object("farm/snapshots/printer-1/2025-01-02/030405.jpg"); !ok {
+		t.Error("recent snapshot expired")
+	}
+}
@@ -0,0 +1,249 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/tui"
+	"github.com/wmarchesi123/octodash/pkg/client"
+)
+
+// options holds the connection flags shared by every client command
+type options struct {
+	server string
+	token  string
+}
+
+func (o *options) client() *client.Client {
+	return client.New(o.server, o.token)
+}
+
+// AddCommands registers the API client subcommands on root
+func AddCommands(root *cobra.Command) {
+	opts := &options{}
+
+	server := os.Getenv("OCTODASH_URL")
+	if server == "" {
+		server = "http://localhost:8080"
+	}
+	root.PersistentFlags().StringVar(&opts.server, "server", server, "OctoDash server URL (env OCTODASH_URL)")
+	root.PersistentFlags().StringVar(&opts.token, "token", os.Getenv("OCTODASH_TOKEN"), "API token (env OCTODASH_TOKEN)")
+
+	root.AddCommand(
+		statusCommand(opts),
+		jobCommand(opts, "pause", "Pause the print on a printer"),
+		jobCommand(opts, "resume", "Resume a paused print"),
+		jobCommand(opts, "cancel", "Cancel the print on a printer"),
+		queueCommand(opts),
+		tuiCommand(opts),
+	)
+}
+
+func statusCommand(opts *options) *cobra.Command {
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the status of every printer",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			status, err := opts.client().Status(cmd.Context())
+			if err != nil {
+				return err
+			}
+			printers := status.Printers
+
+			if asJSON {
+				return printJSON(printers)
+			}
+
+			tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(tw, "ID\tNAME\tSTATUS\tPROGRESS\tLEFT\tFILE")
+			for _, p := range printers {
+				progress, left, file := "-", "-", "-"
+				if p.Progress != nil {
+					progress = fmt.Sprintf("%.0f%%", p.Progress.Completion)
+					left = models.FormatDuration(p.Progress.PrintTimeLeft)
+					file = p.Progress.FileName
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", p.ID, p.Name, p.Status, progress, left, file)
+			}
+			return tw.Flush()
+		},
+	}
+
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print raw JSON")
+	return cmd
+}
+
+func jobCommand(opts *options, action, short string) *cobra.Command {
+	return &cobra.Command{
+		Use:   action + " <printer>",
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client := opts.client()
+
+			printer, err := client.ResolvePrinter(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+
+			if err := client.PrinterAction(cmd.Context(), printer.ID, action); err != nil {
+				return err
+			}
+
+			fmt.Printf("%s: %s sent\n", printer.Name, action)
+			return nil
+		},
+	}
+}
+
+func queueCommand(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "queue",
+		Short: "Manage the print queue",
+	}
+
+	var printer string
+	add := &cobra.Command{
+		Use:   "add <file>",
+		Short: "Queue a file stored on OctoPrint",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client := opts.client()
+
+			printerID := ""
+			if printer != "" {
+				p, err := client.ResolvePrinter(cmd.Context(), printer)
+				if err != nil {
+					return err
+				}
+				printerID = p.ID
+			}
+
+			entry, err := client.QueueAdd(cmd.Context(), args[0], printerID)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("Queued %s as %s\n", entry.File, entry.ID)
+			return nil
+		},
+	}
+	add.Flags().StringVar(&printer, "printer", "", "Pin the job to a printer (ID or name)")
+
+	upload := &cobra.Command{
+		Use:   "upload <model>",
+		Short: "Queue an STL or 3MF model for slicing",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client := opts.client()
+
+			printerID := ""
+			if printer != "" {
+				p, err := client.ResolvePrinter(cmd.Context(), printer)
+				if err != nil {
+					return err
+				}
+				printerID = p.ID
+			}
+
+			f, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			entry, err := client.QueueUploadModel(cmd.Context(), filepath.Base(args[0]), f, printerID)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("Queued %s as %s, waiting for slicing\n", entry.File, entry.ID)
+			return nil
+		},
+	}
+	upload.Flags().StringVar(&printer, "printer", "", "Pin the job to a printer (ID or name)")
+
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List queued jobs",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entries, err := opts.client().QueueList(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(tw, "ID\tFILE\tPRINTER\tSTATUS\tSUBMITTED BY")
+			for _, e := range entries {
+				printerID := e.PrinterID
+				if printerID == "" {
+					printerID = "any"
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", e.ID, e.File, printerID, e.Status, e.SubmittedBy)
+			}
+			return tw.Flush()
+		},
+	}
+
+	remove := &cobra.Command{
+		Use:   "remove <id>",
+		Short: "Remove a job from the queue",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.client().QueueRemove(cmd.Context(), args[0])
+		},
+	}
+
+	cmd.AddCommand(add, upload, list, remove)
+	return cmd
+}
+
+func tuiCommand(opts *options) *cobra.Command {
+	var interval time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "tui",
+		Short: "Monitor the farm in the terminal",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return tui.Run(opts.client(), interval)
+		},
+	}
+
+	cmd.Flags().DurationVar(&interval, "interval", 5*time.Second, "Refresh interval")
+	return cmd
+}
+
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
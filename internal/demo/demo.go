@@ -0,0 +1,114 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package demo
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/wmarchesi123/go-3dprint-client/config"
+	"github.com/wmarchesi123/octodash/internal/settings"
+)
+
+// tickInterval is how often the simulation advances in real time
+const tickInterval = 500 * time.Millisecond
+
+var printerNames = []string{
+	"Prusa MK4", "Voron 2.4", "Ender 3 V2", "Prusa Mini",
+	"Voron Trident", "Anycubic Kobra", "RatRig V-Core", "Sovol SV06",
+	"Creality K1", "Prusa XL",
+}
+
+// Farm is a set of simulated OctoPrint instances and a Spoolman server
+// listening on a local port
+type Farm struct {
+	cfg      *config.Config
+	server   *http.Server
+	printers []*printer
+	done     chan struct{}
+}
+
+// Start launches the simulated farm described by s
+func Start(s settings.DemoSettings) (*Farm, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	baseURL := "http://" + listener.Addr().String()
+
+	spools := newSpoolStore()
+	mux := http.NewServeMux()
+	mux.Handle("/spoolman/", http.StripPrefix("/spoolman", spools.handler()))
+
+	f := &Farm{
+		cfg:    &config.Config{SpoolmanURL: baseURL + "/spoolman"},
+		server: &http.Server{Handler: mux},
+		done:   make(chan struct{}),
+	}
+
+	for i := 0; i < s.Printers; i++ {
+		id := settings.PrinterID(i + 1)
+		p := newPrinter(id, printerNames[i%len(printerNames)], int64(i+1), spools, len(spools.spools)-i%len(spools.spools))
+		f.printers = append(f.printers, p)
+
+		prefix := "/octoprint/" + id
+		mux.Handle(prefix+"/", http.StripPrefix(prefix, p.handler()))
+
+		f.cfg.Printers = append(f.cfg.Printers, config.Printer{
+			ID:           id,
+			Name:         p.name,
+			OctoPrintURL: baseURL + prefix,
+			APIKey:       "demo",
+		})
+	}
+
+	go f.server.Serve(listener)
+	go f.run(s.Speed)
+
+	return f, nil
+}
+
+// Config returns printer config pointing at the simulated servers
+func (f *Farm) Config() *config.Config {
+	return f.cfg
+}
+
+// Close stops the simulation and its server
+func (f *Farm) Close() error {
+	close(f.done)
+	return f.server.Close()
+}
+
+func (f *Farm) run(speed float64) {
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+
+	dt := tickInterval.Seconds() * speed
+	for {
+		select {
+		case <-ticker.C:
+			for _, p := range f.printers {
+				p.step(dt)
+			}
+		case <-f.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package demo
+
+// demoFile is a sliced file the simulated printers can print
+type demoFile struct {
+	name       string
+	estimate   float64
+	filamentMM float64
+	hotend     float64
+	bed        float64
+	color      [3]uint8
+}
+
+var files = []demoFile{
+	{"benchy.gcode", 1.6 * 3600, 4200, 215, 60, [3]uint8{0xe6, 0x4a, 0x19}},
+	{"calibration_cube.gcode", 0.4 * 3600, 1100, 215, 60, [3]uint8{0x9e, 0x9e, 0x9e}},
+	{"cable_clips_x20.gcode", 1.1 * 3600, 3000, 240, 80, [3]uint8{0x21, 0x21, 0x21}},
+	{"planter_large.gcode", 7.5 * 3600, 38000, 215, 60, [3]uint8{0x43, 0xa0, 0x47}},
+	{"gridfinity_bins_3x2.gcode", 4.2 * 3600, 21000, 240, 80, [3]uint8{0x1e, 0x88, 0xe5}},
+	{"phone_stand.gcode", 2.3 * 3600, 8600, 215, 60, [3]uint8{0xfd, 0xd8, 0x35}},
+	{"voron_skirt_left.gcode", 3.1 * 3600, 14500, 250, 100, [3]uint8{0xd3, 0x2f, 0x2f}},
+	{"lithophane_moon.gcode", 5.8 * 3600, 17000, 215, 60, [3]uint8{0xfa, 0xfa, 0xfa}},
+	{"tpu_phone_case.gcode", 1.9 * 3600, 5200, 230, 50, [3]uint8{0x8e, 0x24, 0xaa}},
+}
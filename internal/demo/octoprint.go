@@ -0,0 +1,355 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package demo
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// handler serves the subset of the OctoPrint API OctoDash uses
+func (p *printer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/printer", p.handleState)
+	mux.HandleFunc("POST /api/printer/tool", p.handleTemperature)
+	mux.HandleFunc("POST /api/printer/bed", p.handleTemperature)
+	mux.HandleFunc("POST /api/printer/command", p.handleCommand)
+	mux.HandleFunc("GET /api/job", p.handleJob)
+	mux.HandleFunc("POST /api/job", p.handleJobCommand)
+	mux.HandleFunc("GET /api/connection", p.handleConnection)
+	mux.HandleFunc("POST /api/connection", p.handleConnect)
+	mux.HandleFunc("GET /api/files", p.handleFiles)
+	mux.HandleFunc("POST /api/files/local/{path...}", p.handleFileCommand)
+	mux.HandleFunc("POST /api/plugin/spoolman_api", p.handleSpoolman)
+	mux.HandleFunc("GET /plugin/prusaslicerthumbnails/thumbnail/{file}", p.handleThumbnail)
+	mux.HandleFunc("GET /plugin/softwareupdate/check", p.handleSoftwareUpdates)
+	mux.HandleFunc("GET /api/system/info", p.handleSystemInfo)
+	mux.HandleFunc("GET /plugin/pluginmanager/plugins", p.handlePlugins)
+	return mux
+}
+
+func temperature(h heater) map[string]float64 {
+	return map[string]float64{
+		"actual": float64(int(h.actual*10)) / 10,
+		"target": h.target,
+		"offset": 0,
+	}
+}
+
+func (p *printer) handleState(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// OctoPrint answers 409 when the printer is not connected
+	if p.phase == phaseOffline {
+		http.Error(w, "Printer is not operational", http.StatusConflict)
+		return
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"state": map[string]interface{}{
+			"text":  p.stateText(),
+			"flags": p.flags(),
+		},
+		"temperature": map[string]interface{}{
+			"tool0": temperature(p.hotend),
+			"bed":   temperature(p.bed),
+		},
+	})
+}
+
+func (p *printer) handleJob(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	response := map[string]interface{}{
+		"state": p.stateText(),
+		"job": map[string]interface{}{
+			"file": map[string]interface{}{},
+		},
+		"progress": map[string]interface{}{},
+	}
+
+	if j := p.job; j != nil {
+		response["job"] = map[string]interface{}{
+			"file": map[string]interface{}{
+				"name":    j.file.name,
+				"display": j.file.name,
+				"path":    j.file.name,
+				"origin":  "local",
+			},
+			"estimatedPrintTime": j.file.estimate,
+			"filament": map[string]interface{}{
+				"tool0": map[string]float64{"length": j.file.filamentMM},
+			},
+		}
+		response["progress"] = map[string]interface{}{
+			"completion":          j.completion(),
+			"printTime":           int(j.elapsed),
+			"printTimeLeft":       int(j.duration - j.elapsed),
+			"printTimeLeftOrigin": "linear",
+		}
+	}
+
+	writeJSON(w, response)
+}
+
+func (p *printer) handleJobCommand(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Command string `json:"command"`
+		Action  string `json:"action"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid body", http.StatusBadRequest)
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch {
+	case body.Command == "cancel" && p.job != nil:
+		p.finish()
+	case body.Command == "pause" && body.Action == "resume" && p.phase == phasePaused:
+		p.setPhase(phasePrinting)
+	case body.Command == "pause" && body.Action != "resume" && p.phase == phasePrinting:
+		p.setPhase(phasePaused)
+	default:
+		http.Error(w, "Printer is not in a state to "+body.Command, http.StatusConflict)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleFileCommand supports selecting a file to print, as the queue does
+func (p *printer) handleFileCommand(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Command string `json:"command"`
+		Print   bool   `json:"print"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Command != "select" {
+		http.Error(w, "Invalid body", http.StatusBadRequest)
+		return
+	}
+
+	var file *demoFile
+	for i := range files {
+		if files[i].name == r.PathValue("path") {
+			file = &files[i]
+		}
+	}
+	if file == nil {
+		http.Error(w, "File not found", http.StatusNotFound)
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if body.Print {
+		if p.phase != phaseIdle {
+			http.Error(w, "Printer is not operational", http.StatusConflict)
+			return
+		}
+		p.startFile(*file)
+		p.setPhase(phaseHeating)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (p *printer) handleTemperature(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Targets map[string]float64 `json:"targets"`
+		Target  *float64           `json:"target"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid body", http.StatusBadRequest)
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if t, ok := body.Targets["tool0"]; ok {
+		p.hotend.target = t
+	}
+	if body.Target != nil {
+		p.bed.target = *body.Target
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (p *printer) handleCommand(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Command  string   `json:"command"`
+		Commands []string `json:"commands"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid body", http.StatusBadRequest)
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, cmd := range append(body.Commands, body.Command) {
+		if strings.HasPrefix(strings.ToUpper(strings.TrimSpace(cmd)), "M112") {
+			p.fail("Error: Printer halted. kill() called!")
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (p *printer) handleConnection(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	state := p.stateText()
+	if p.phase == phaseOffline {
+		state = "Closed"
+	}
+	writeJSON(w, map[string]interface{}{
+		"current": map[string]interface{}{"state": state},
+	})
+}
+
+func (p *printer) handleConnect(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.phase == phaseOffline || p.phase == phaseError {
+		p.errorText = ""
+		p.nextStart = p.idleTime()
+		p.setPhase(phaseIdle)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (p *printer) handleFiles(w http.ResponseWriter, r *http.Request) {
+	entries := make([]map[string]interface{}, 0, len(files))
+	for i, f := range files {
+		entries = append(entries, map[string]interface{}{
+			"name":    f.name,
+			"display": f.name,
+			"path":    f.name,
+			"origin":  "local",
+			"type":    "machinecode",
+			"size":    int64(f.filamentMM * 90),
+			"date":    1735689600 + int64(i)*86400,
+		})
+	}
+	writeJSON(w, map[string]interface{}{"files": entries})
+}
+
+func (p *printer) handleSpoolman(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Command string `json:"command"`
+		SpoolID string `json:"spool_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid body", http.StatusBadRequest)
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch body.Command {
+	case "get_current_spool":
+		writeJSON(w, map[string]interface{}{"success": true, "spool_id": strconv.Itoa(p.spoolID)})
+	case "set_spool":
+		id, err := strconv.Atoi(body.SpoolID)
+		if err != nil {
+			writeJSON(w, map[string]interface{}{"success": false, "error": "invalid spool_id"})
+			return
+		}
+		p.spoolID = id
+		writeJSON(w, map[string]interface{}{"success": true})
+	default:
+		writeJSON(w, map[string]interface{}{"success": false, "error": "unknown command"})
+	}
+}
+
+func (p *printer) handleSoftwareUpdates(w http.ResponseWriter, r *http.Request) {
+	installed, latest := "1.10.3", "1.10.3"
+	if p.outdated {
+		installed = "1.10.2"
+	}
+	writeJSON(w, map[string]interface{}{
+		"status": "current",
+		"busy":   false,
+		"information": map[string]interface{}{
+			"octoprint": map[string]interface{}{
+				"displayName":     "OctoPrint",
+				"displayVersion":  installed,
+				"updateAvailable": installed != latest,
+				"information": map[string]interface{}{
+					"local":  map[string]string{"name": installed, "value": installed},
+					"remote": map[string]string{"name": latest, "value": latest},
+				},
+			},
+		},
+	})
+}
+
+func (p *printer) handleSystemInfo(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]interface{}{
+		"systeminfo": map[string]interface{}{"printer.firmware": "Marlin 2.1.2.4"},
+	})
+}
+
+func (p *printer) handlePlugins(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]interface{}{
+		"plugins": []map[string]interface{}{
+			{"key": "spoolman_api", "name": "Spoolman API", "version": "1.0.0", "enabled": true},
+			{"key": "prusaslicerthumbnails", "name": "Slicer Thumbnails", "version": "1.0.7", "enabled": true},
+			{"key": "DisplayLayerProgress", "name": "DisplayLayerProgress", "version": "1.28.0", "enabled": true},
+		},
+	})
+}
+
+// handleThumbnail renders a flat swatch in the file's filament color
+func (p *printer) handleThumbnail(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimSuffix(r.PathValue("file"), ".png") + ".gcode"
+
+	c := color.RGBA{0x60, 0x60, 0x60, 0xff}
+	for _, f := range files {
+		if f.name == name {
+			c = color.RGBA{f.color[0], f.color[1], f.color[2], 0xff}
+		}
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	for y := 8; y < 56; y++ {
+		for x := 8; x < 56; x++ {
+			img.Set(x, y, c)
+		}
+	}
+
+	var buf bytes.Buffer
+	png.Encode(&buf, img)
+	w.Header().Set("Content-Type", "image/png")
+	w.Write(buf.Bytes())
+}
@@ -0,0 +1,258 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package demo
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+)
+
+const ambient = 22.0
+
+type phase int
+
+const (
+	phaseIdle phase = iota
+	phaseHeating
+	phasePrinting
+	phasePaused
+	phaseCooling
+	phaseError
+	phaseOffline
+)
+
+// heater is a first-order model of a hotend or bed
+type heater struct {
+	actual float64
+	target float64
+	tau    float64
+}
+
+func (h *heater) step(dt float64, rng *rand.Rand) {
+	goal := h.target
+	if goal <= 0 {
+		goal = ambient
+	}
+	h.actual += (goal - h.actual) * (1 - math.Exp(-dt/h.tau))
+	h.actual += rng.NormFloat64() * 0.15
+}
+
+func (h *heater) settled() bool {
+	return math.Abs(h.actual-h.target) < 2
+}
+
+// job is the file being printed and how far along it is
+type job struct {
+	file     demoFile
+	elapsed  float64
+	duration float64
+}
+
+// completion follows file position, which runs a little ahead of and behind
+// elapsed time the way real G-code does
+func (j *job) completion() float64 {
+	t := math.Min(j.elapsed/j.duration, 1)
+	return 100 * (t - 0.05*math.Sin(2*math.Pi*t))
+}
+
+// printer is one simulated OctoPrint instance
+type printer struct {
+	mu sync.Mutex
+
+	id      string
+	name    string
+	rng     *rand.Rand
+	spools  *spoolStore
+	spoolID int
+
+	phase      phase
+	phaseTime  float64
+	errorText  string
+	hotend     heater
+	bed        heater
+	job        *job
+	nextStart  float64
+	lastFileID int
+
+	// outdated printers report an OctoPrint update so the demo shows update badges
+	outdated bool
+}
+
+func newPrinter(id, name string, seed int64, spools *spoolStore, spoolID int) *printer {
+	p := &printer{
+		id:       id,
+		name:     name,
+		rng:      rand.New(rand.NewSource(seed)),
+		spools:   spools,
+		spoolID:  spoolID,
+		hotend:   heater{actual: ambient, tau: 25},
+		bed:      heater{actual: ambient, tau: 90},
+		outdated: seed%3 == 2,
+	}
+	p.nextStart = p.idleTime()
+
+	// Start most printers mid-print so the demo looks busy immediately
+	if p.rng.Float64() < 0.7 {
+		p.startJob()
+		p.hotend.actual, p.bed.actual = p.hotend.target, p.bed.target
+		p.job.elapsed = p.job.duration * p.rng.Float64() * 0.9
+		p.setPhase(phasePrinting)
+	}
+	return p
+}
+
+func (p *printer) idleTime() float64 {
+	return 120 + p.rng.Float64()*600
+}
+
+func (p *printer) setPhase(ph phase) {
+	p.phase = ph
+	p.phaseTime = 0
+}
+
+// step advances the simulation by dt simulated seconds
+func (p *printer) step(dt float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.phaseTime += dt
+	p.hotend.step(dt, p.rng)
+	p.bed.step(dt, p.rng)
+
+	switch p.phase {
+	case phaseIdle:
+		if p.phaseTime >= p.nextStart {
+			p.startJob()
+			p.setPhase(phaseHeating)
+		}
+
+	case phaseHeating:
+		if p.hotend.settled() && p.bed.settled() {
+			p.setPhase(phasePrinting)
+		}
+
+	case phasePrinting:
+		before := p.job.completion()
+		p.job.elapsed += dt
+		used := (p.job.completion() - before) / 100 * p.job.file.filamentMM
+		p.spools.consume(p.spoolID, used)
+
+		switch {
+		case p.job.elapsed >= p.job.duration:
+			p.finish()
+		case p.rng.Float64() < dt/(6*3600):
+			// Roughly one filament runout every six hours of printing
+			p.setPhase(phasePaused)
+		case p.rng.Float64() < dt/(48*3600):
+			p.fail("Error: Thermal Runaway")
+		}
+
+	case phasePaused:
+		if p.phaseTime > 300 {
+			p.setPhase(phasePrinting)
+		}
+
+	case phaseCooling:
+		if p.hotend.actual < 50 {
+			p.nextStart = p.idleTime()
+			p.setPhase(phaseIdle)
+		}
+
+	case phaseError:
+		if p.phaseTime > 900 {
+			p.errorText = ""
+			p.setPhase(phaseOffline)
+		}
+
+	case phaseOffline:
+		if p.phaseTime > 300 {
+			p.nextStart = p.idleTime()
+			p.setPhase(phaseIdle)
+		}
+	}
+}
+
+func (p *printer) startJob() {
+	// Avoid printing the same file twice in a row
+	i := p.rng.Intn(len(files))
+	if i == p.lastFileID {
+		i = (i + 1) % len(files)
+	}
+	p.lastFileID = i
+	p.startFile(files[i])
+}
+
+// startFile begins printing f, heating up first
+func (p *printer) startFile(f demoFile) {
+	p.job = &job{
+		file:     f,
+		duration: f.estimate * (0.9 + p.rng.Float64()*0.25),
+	}
+	p.hotend.target = f.hotend
+	p.bed.target = f.bed
+}
+
+func (p *printer) finish() {
+	p.job = nil
+	p.hotend.target = 0
+	p.bed.target = 0
+	p.setPhase(phaseCooling)
+}
+
+func (p *printer) fail(text string) {
+	p.job = nil
+	p.hotend.target = 0
+	p.bed.target = 0
+	p.errorText = text
+	p.setPhase(phaseError)
+}
+
+// stateText and flags mirror what OctoPrint reports in /api/printer
+func (p *printer) stateText() string {
+	switch p.phase {
+	case phaseHeating, phasePrinting:
+		return "Printing"
+	case phasePaused:
+		return "Paused"
+	case phaseError:
+		return p.errorText
+	case phaseOffline:
+		return "Offline"
+	default:
+		return "Operational"
+	}
+}
+
+func (p *printer) flags() map[string]bool {
+	printing := p.phase == phaseHeating || p.phase == phasePrinting
+	closed := p.phase == phaseError || p.phase == phaseOffline
+
+	return map[string]bool{
+		"operational":   !closed,
+		"printing":      printing,
+		"paused":        p.phase == phasePaused,
+		"pausing":       false,
+		"cancelling":    false,
+		"sdReady":       false,
+		"error":         p.phase == phaseError,
+		"ready":         p.phase == phaseIdle || p.phase == phaseCooling,
+		"closedOrError": closed,
+	}
+}
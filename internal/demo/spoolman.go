@@ -0,0 +1,127 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package demo
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/wmarchesi123/go-3dprint-client/spoolman"
+)
+
+// gramsPerMM is 1.75mm PLA at 1.24 g/cm³
+const gramsPerMM = 0.00298
+
+// spoolStore is the simulated Spoolman inventory
+type spoolStore struct {
+	mu     sync.Mutex
+	spools []spoolman.Spool
+}
+
+func newSpoolStore() *spoolStore {
+	entries := []struct {
+		vendor, name, material, color, location string
+		remaining                               float64
+	}{
+		{"Prusament", "Galaxy Black", "PLA", "2b2b2b", "Rack 1", 640},
+		{"Polymaker", "PolyLite Blue", "PLA", "1e88e5", "Rack 1", 910},
+		{"eSun", "Fire Engine Red", "PETG", "d32f2f", "Rack 2", 320},
+		{"Overture", "Forest Green", "PLA", "43a047", "Rack 2", 780},
+		{"Sunlu", "Transparent", "PETG", "e0f2f1", "Dry box", 450},
+		{"Prusament", "Jet Black", "ASA", "212121", "Rack 3", 120},
+		{"NinjaTek", "Midnight Purple", "TPU", "8e24aa", "Dry box", 530},
+		{"Polymaker", "Sunrise Yellow", "PLA", "fdd835", "Shelf", 1000},
+	}
+
+	store := &spoolStore{}
+	for i, e := range entries {
+		store.spools = append(store.spools, spoolman.Spool{
+			ID:              i + 1,
+			Registered:      "2025-01-01T00:00:00Z",
+			InitialWeight:   1000,
+			SpoolWeight:     200,
+			RemainingWeight: e.remaining,
+			UsedWeight:      1000 - e.remaining,
+			RemainingLength: e.remaining / gramsPerMM,
+			Location:        e.location,
+			Filament: spoolman.Filament{
+				ID:       i + 1,
+				Name:     e.name,
+				Material: e.material,
+				Diameter: 1.75,
+				Weight:   1000,
+				ColorHex: e.color,
+				Vendor:   spoolman.Vendor{ID: i + 1, Name: e.vendor},
+			},
+		})
+	}
+	return store
+}
+
+// consume deducts printed filament from a spool
+func (s *spoolStore) consume(id int, mm float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.spools {
+		if s.spools[i].ID != id {
+			continue
+		}
+		grams := mm * gramsPerMM
+		sp := &s.spools[i]
+		sp.RemainingWeight = math.Max(0, sp.RemainingWeight-grams)
+		sp.UsedWeight = sp.InitialWeight - sp.RemainingWeight
+		sp.RemainingLength = sp.RemainingWeight / gramsPerMM
+		sp.UsedLength += mm
+	}
+}
+
+func (s *spoolStore) handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /api/v1/spool", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		writeJSON(w, s.spools)
+	})
+
+	mux.HandleFunc("GET /api/v1/spool/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, _ := strconv.Atoi(r.PathValue("id"))
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for _, sp := range s.spools {
+			if sp.ID == id {
+				writeJSON(w, sp)
+				return
+			}
+		}
+		http.Error(w, `{"message":"Spool not found"}`, http.StatusNotFound)
+	})
+
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,289 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package devices pairs kiosks and wall displays: a device shows a short code,
+// an admin approves it, and the device receives a long-lived read-only token
+package devices
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/store"
+)
+
+// bucket holds paired devices keyed by the SHA-256 of their token
+const bucket = "devices"
+
+// CodeTTL is how long a pairing code waits for approval
+const CodeTTL = 10 * time.Minute
+
+// maxPending bounds the requests waiting for approval; past it the oldest is dropped
+const maxPending = 20
+
+// maxPendingPerAddress bounds the unapproved requests one address can open,
+// so a single client cannot crowd everyone else out
+const maxPendingPerAddress = 5
+
+// codeAlphabet leaves out characters easily confused on a TV across the room
+const codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// UserPrefix starts the user name every device signs in as
+const UserPrefix = "device/"
+
+var (
+	// ErrNotFound is returned for unknown devices and unknown or expired codes
+	ErrNotFound = errors.New("pairing code not found or expired")
+	// ErrInvalidName is returned for device names that are not short slugs
+	ErrInvalidName = errors.New("device names may only use letters, digits, '.', '-' and '_'")
+	// ErrBusy is returned when too many pairing requests from one address are waiting
+	ErrBusy = errors.New("too many devices waiting to pair; try again later")
+)
+
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// Devices is the persistent set of paired devices plus the requests waiting
+// for approval, which only live in memory
+type Devices struct {
+	store   *store.Store
+	mu      sync.Mutex
+	pending map[string]*request // by code
+}
+
+type request struct {
+	models.PairingRequest
+	secret string
+	token  string
+	addr   string
+}
+
+// New creates a Devices backed by the given store
+func New(s *store.Store) *Devices {
+	return &Devices{store: s, pending: make(map[string]*request)}
+}
+
+// Request opens a pairing request for a device at addr, returning the code to
+// show and the secret the device polls with. When too many are waiting the
+// oldest unapproved request makes way.
+func (d *Devices) Request(name, addr string, now time.Time) (models.PairingRequest, string, error) {
+	if !validName.MatchString(name) {
+		return models.PairingRequest{}, "", ErrInvalidName
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.expire(now)
+	if d.waiting(addr) >= maxPendingPerAddress {
+		return models.PairingRequest{}, "", ErrBusy
+	}
+	if len(d.pending) >= maxPending && !d.evictOldest() {
+		return models.PairingRequest{}, "", ErrBusy
+	}
+
+	code := newCode()
+	for d.pending[code] != nil {
+		code = newCode()
+	}
+	req := &request{
+		PairingRequest: models.PairingRequest{Code: code, Name: name, ExpiresAt: now.UTC().Add(CodeTTL)},
+		secret:         randomHex(),
+		addr:           addr,
+	}
+	d.pending[code] = req
+	return req.PairingRequest, req.secret, nil
+}
+
+// Poll reports whether the request with the given secret was approved,
+// returning the device's token exactly once when it has been
+func (d *Devices) Poll(secret string, now time.Time) (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.expire(now)
+
+	for code, req := range d.pending {
+		if subtle.ConstantTimeCompare([]byte(req.secret), []byte(secret)) != 1 {
+			continue
+		}
+		if req.token != "" {
+			delete(d.pending, code)
+		}
+		return req.token, nil
+	}
+	return "", ErrNotFound
+}
+
+// Pending returns the requests waiting for approval
+func (d *Devices) Pending(now time.Time) []models.PairingRequest {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.expire(now)
+
+	list := []models.PairingRequest{}
+	for _, req := range d.pending {
+		if req.token == "" {
+			list = append(list, req.PairingRequest)
+		}
+	}
+	return list
+}
+
+// Approve pairs the device showing code, recording who approved it. Codes
+// are matched ignoring case, spaces and dashes.
+func (d *Devices) Approve(code, by string, now time.Time) (models.Device, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.expire(now)
+
+	req := d.pending[NormalizeCode(code)]
+	if req == nil || req.token != "" {
+		return models.Device{}, ErrNotFound
+	}
+
+	token := randomHex()
+	hash := hashToken(token)
+	device := models.Device{
+		ID:       hash[:12],
+		Name:     req.Name,
+		User:     UserPrefix + req.Name,
+		PairedBy: by,
+		PairedAt: now.UTC(),
+	}
+	if err := d.store.Put(bucket, hash, device); err != nil {
+		return device, err
+	}
+	req.token = token
+	return device, nil
+}
+
+// List returns every paired device
+func (d *Devices) List() ([]models.Device, error) {
+	list, err := store.List[models.Device](d.store, bucket)
+	if list == nil {
+		list = []models.Device{}
+	}
+	return list, err
+}
+
+// Revoke unpairs a device, invalidating its token
+func (d *Devices) Revoke(id string) (models.Device, error) {
+	var found models.Device
+	var key string
+	err := d.store.Scan(bucket, func(k string, _ []byte) error {
+		if k[:12] == id {
+			key = k
+		}
+		return nil
+	})
+	if err != nil {
+		return found, err
+	}
+	if key == "" {
+		return found, ErrNotFound
+	}
+	if _, err := d.store.Get(bucket, key, &found); err != nil {
+		return found, err
+	}
+	return found, d.store.Delete(bucket, key)
+}
+
+// Authenticate returns the device a token belongs to
+func (d *Devices) Authenticate(token string) (models.Device, bool) {
+	var device models.Device
+	if token == "" {
+		return device, false
+	}
+	ok, err := d.store.Get(bucket, hashToken(token), &device)
+	return device, ok && err == nil
+}
+
+// NormalizeCode puts a code typed by hand into the form Request issued it in
+func NormalizeCode(code string) string {
+	code = strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+	if len(code) == 6 {
+		code = code[:3] + "-" + code[3:]
+	}
+	return code
+}
+
+// expire drops requests past their deadline; callers hold d.mu
+func (d *Devices) expire(now time.Time) {
+	for code, req := range d.pending {
+		if now.After(req.ExpiresAt) {
+			delete(d.pending, code)
+		}
+	}
+}
+
+// waiting counts the unapproved requests from addr; callers hold d.mu
+func (d *Devices) waiting(addr string) int {
+	n := 0
+	for _, req := range d.pending {
+		if req.token == "" && req.addr == addr {
+			n++
+		}
+	}
+	return n
+}
+
+// evictOldest drops the unapproved request closest to expiry, leaving approved
+// ones for their devices to collect; callers hold d.mu
+func (d *Devices) evictOldest() bool {
+	var oldest *request
+	for _, req := range d.pending {
+		if req.token == "" && (oldest == nil || req.ExpiresAt.Before(oldest.ExpiresAt)) {
+			oldest = req
+		}
+	}
+	if oldest == nil {
+		return false
+	}
+	delete(d.pending, oldest.Code)
+	return true
+}
+
+func newCode() string {
+	buf := make([]byte, 6)
+	rand.Read(buf)
+	code := make([]byte, 0, 7)
+	for i, b := range buf {
+		if i == 3 {
+			code = append(code, '-')
+		}
+		code = append(code, codeAlphabet[int(b)%len(codeAlphabet)])
+	}
+	return string(code)
+}
+
+func randomHex() string {
+	buf := make([]byte, 32)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
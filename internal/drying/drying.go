@@ -0,0 +1,175 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package drying keeps a log of filament drying sessions per spool
+package drying
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/store"
+)
+
+// ErrActive is returned when starting a session for a spool that is already drying
+var ErrActive = errors.New("spool is already drying")
+
+// ErrNotDrying is returned when stopping a spool that has no running session
+var ErrNotDrying = errors.New("spool is not drying")
+
+// Log is the persistent drying history, one bucket per spool
+type Log struct {
+	store *store.Store
+
+	// mu guards latest, the newest session per spool; a nil entry means none
+	mu     sync.Mutex
+	latest map[string]*models.DryingSession
+}
+
+// New creates a Log backed by the given store
+func New(s *store.Store) *Log {
+	return &Log{store: s, latest: make(map[string]*models.DryingSession)}
+}
+
+func bucket(spoolman, spoolID string) string {
+	return "drying:" + spoolman + ":" + spoolID
+}
+
+// Start opens a session at session.StartedAt that runs until Stop
+func (l *Log) Start(session models.DryingSession) (models.DryingSession, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	last, err := l.last(session.Spoolman, session.SpoolID)
+	if err != nil {
+		return session, err
+	}
+	if last != nil && last.EndedAt == nil {
+		return session, ErrActive
+	}
+
+	session.EndedAt = nil
+	session.DurationMinutes = 0
+	return session, l.put(&session)
+}
+
+// Stop closes a spool's running session at the given time
+func (l *Log) Stop(spoolman, spoolID string, at time.Time) (models.DryingSession, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	last, err := l.last(spoolman, spoolID)
+	if err != nil {
+		return models.DryingSession{}, err
+	}
+	if last == nil || last.EndedAt != nil {
+		return models.DryingSession{}, ErrNotDrying
+	}
+
+	session := *last
+	ended := at.UTC().Truncate(time.Second)
+	session.EndedAt = &ended
+	session.DurationMinutes = int(ended.Sub(session.StartedAt).Minutes())
+	if err := l.store.Put(bucket(spoolman, spoolID), session.ID, session); err != nil {
+		return session, err
+	}
+	l.latest[spoolman+":"+spoolID] = &session
+	return session, nil
+}
+
+// Record stores a session that has already finished, lasting DurationMinutes from StartedAt
+func (l *Log) Record(session models.DryingSession) (models.DryingSession, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	last, err := l.last(session.Spoolman, session.SpoolID)
+	if err != nil {
+		return session, err
+	}
+	if last != nil && last.EndedAt == nil {
+		return session, ErrActive
+	}
+
+	ended := session.StartedAt.Add(time.Duration(session.DurationMinutes) * time.Minute)
+	session.EndedAt = &ended
+	return session, l.put(&session)
+}
+
+// List returns a spool's sessions newest first
+func (l *Log) List(spoolman, spoolID string) ([]models.DryingSession, error) {
+	sessions, err := store.List[models.DryingSession](l.store, bucket(spoolman, spoolID))
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(sessions, func(i, j int) bool { return sessions[i].ID > sessions[j].ID })
+	if sessions == nil {
+		sessions = []models.DryingSession{}
+	}
+	return sessions, nil
+}
+
+// Latest returns a spool's newest session, or nil if it has never been dried
+func (l *Log) Latest(spoolman, spoolID string) (*models.DryingSession, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.last(spoolman, spoolID)
+}
+
+// last reads the newest session through the cache; callers hold mu
+func (l *Log) last(spoolman, spoolID string) (*models.DryingSession, error) {
+	key := spoolman + ":" + spoolID
+	if session, ok := l.latest[key]; ok {
+		return session, nil
+	}
+
+	sessions, err := l.List(spoolman, spoolID)
+	if err != nil {
+		return nil, err
+	}
+	var session *models.DryingSession
+	if len(sessions) > 0 {
+		session = &sessions[0]
+	}
+	l.latest[key] = session
+	return session, nil
+}
+
+// put assigns the session an ID and saves it as the spool's newest; callers hold mu
+func (l *Log) put(session *models.DryingSession) error {
+	b := bucket(session.Spoolman, session.SpoolID)
+	id, err := l.store.NextID(b)
+	if err != nil {
+		return err
+	}
+
+	session.ID = id
+	session.StartedAt = session.StartedAt.UTC().Truncate(time.Second)
+	if session.EndedAt != nil {
+		ended := session.EndedAt.UTC().Truncate(time.Second)
+		session.EndedAt = &ended
+	}
+	if err := l.store.Put(b, id, session); err != nil {
+		return err
+	}
+	saved := *session
+	l.latest[session.Spoolman+":"+session.SpoolID] = &saved
+	return nil
+}
@@ -0,0 +1,128 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package eink renders simple grayscale screens on the server for e-ink and
+// other low-power displays that cannot run a browser. Screens are drawn on an
+// 8-bit canvas with a built-in bitmap font and quantized to the panel's depth
+// only when encoded.
+package eink
+
+import (
+	"image"
+	"image/color"
+)
+
+// Shades used for drawing; each survives quantizing to any supported depth
+var (
+	Black = color.Gray{Y: 0x00}
+	Dark  = color.Gray{Y: 0x55}
+	Light = color.Gray{Y: 0xaa}
+	White = color.Gray{Y: 0xff}
+)
+
+// Canvas is a white 8-bit grayscale drawing surface
+type Canvas struct {
+	img *image.Gray
+}
+
+// NewCanvas creates a white canvas of the given size
+func NewCanvas(width, height int) *Canvas {
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for i := range img.Pix {
+		img.Pix[i] = White.Y
+	}
+	return &Canvas{img: img}
+}
+
+// Width is the canvas width in pixels
+func (c *Canvas) Width() int { return c.img.Rect.Dx() }
+
+// Height is the canvas height in pixels
+func (c *Canvas) Height() int { return c.img.Rect.Dy() }
+
+// Image returns the canvas as drawn, before quantizing
+func (c *Canvas) Image() *image.Gray { return c.img }
+
+// Fill paints a rectangle, clipped to the canvas
+func (c *Canvas) Fill(x, y, w, h int, shade color.Gray) {
+	r := image.Rect(x, y, x+w, y+h).Intersect(c.img.Rect)
+	for py := r.Min.Y; py < r.Max.Y; py++ {
+		row := c.img.Pix[py*c.img.Stride:]
+		for px := r.Min.X; px < r.Max.X; px++ {
+			row[px] = shade.Y
+		}
+	}
+}
+
+// Rect outlines a rectangle with lines of the given thickness
+func (c *Canvas) Rect(x, y, w, h, thickness int, shade color.Gray) {
+	c.Fill(x, y, w, thickness, shade)
+	c.Fill(x, y+h-thickness, w, thickness, shade)
+	c.Fill(x, y, thickness, h, shade)
+	c.Fill(x+w-thickness, y, thickness, h, shade)
+}
+
+// TextWidth is the width s takes up when drawn at scale
+func TextWidth(s string, scale int) int {
+	n := len([]rune(s))
+	if n == 0 {
+		return 0
+	}
+	return (n*cellWidth - 1) * scale
+}
+
+// TextHeight is the height of a line of text drawn at scale
+func TextHeight(scale int) int {
+	return glyphHeight * scale
+}
+
+// Fit shortens s so it is no wider than width at scale, marking the cut with ..
+func Fit(s string, scale, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	runes := []rune(s)
+	max := (width/scale + 1) / cellWidth
+	if len(runes) <= max {
+		return s
+	}
+	if max <= 2 {
+		return string(runes[:max])
+	}
+	return string(runes[:max-2]) + ".."
+}
+
+// Text draws s with its top left corner at x, y, each font pixel scale pixels square
+func (c *Canvas) Text(x, y int, s string, scale int, shade color.Gray) {
+	for _, r := range s {
+		g := glyph(r)
+		for col, bits := range g {
+			for row := 0; row < glyphHeight; row++ {
+				if bits&(1<<row) != 0 {
+					c.Fill(x+col*scale, y+row*scale, scale, scale, shade)
+				}
+			}
+		}
+		x += cellWidth * scale
+	}
+}
+
+// TextRight draws s so that it ends at x
+func (c *Canvas) TextRight(x, y int, s string, scale int, shade color.Gray) {
+	c.Text(x-TextWidth(s, scale), y, s, scale, shade)
+}
@@ -0,0 +1,139 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eink
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+)
+
+// Depths lists the supported bits per pixel
+var Depths = []int{1, 2, 4, 8}
+
+// Formats lists the supported image formats
+var Formats = []string{"png", "bmp"}
+
+// ValidDepth reports whether depth is one of Depths
+func ValidDepth(depth int) bool {
+	for _, d := range Depths {
+		if d == depth {
+			return true
+		}
+	}
+	return false
+}
+
+// ContentType is the MIME type for format
+func ContentType(format string) string {
+	if format == "bmp" {
+		return "image/bmp"
+	}
+	return "image/png"
+}
+
+// Quantize maps the canvas onto 2^depth evenly spaced grays, from black to white
+func Quantize(img *image.Gray, depth int) *image.Paletted {
+	levels := 1 << depth
+	palette := make(color.Palette, levels)
+	for i := range palette {
+		palette[i] = color.Gray{Y: uint8(i * 255 / (levels - 1))}
+	}
+
+	out := image.NewPaletted(img.Rect, palette)
+	for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+		for x := img.Rect.Min.X; x < img.Rect.Max.X; x++ {
+			v := int(img.GrayAt(x, y).Y)
+			out.SetColorIndex(x, y, uint8((v*(levels-1)+127)/255))
+		}
+	}
+	return out
+}
+
+// Encode writes the canvas at depth bits per pixel as a PNG or BMP
+func Encode(w io.Writer, c *Canvas, depth int, format string) error {
+	if !ValidDepth(depth) {
+		return fmt.Errorf("unsupported depth %d: want 1, 2, 4 or 8", depth)
+	}
+	img := Quantize(c.img, depth)
+
+	switch format {
+	case "png":
+		// The encoder packs paletted images into 1, 2, 4 or 8 bits to match the palette
+		return png.Encode(w, img)
+	case "bmp":
+		return encodeBMP(w, img, depth)
+	default:
+		return fmt.Errorf("unsupported format %q: want png or bmp", format)
+	}
+}
+
+// encodeBMP writes an uncompressed bottom-up Windows bitmap with a gray
+// palette. BMP has no standard 2-bit form, so those images are stored in 4
+// bits with a four-entry palette, which panel libraries read like any other.
+func encodeBMP(w io.Writer, img *image.Paletted, depth int) error {
+	bpp := depth
+	if bpp == 2 {
+		bpp = 4
+	}
+	width, height := img.Rect.Dx(), img.Rect.Dy()
+	stride := (width*bpp + 31) / 32 * 4
+	colors := len(img.Palette)
+	offset := 14 + 40 + 4*colors
+
+	bw := bufio.NewWriter(w)
+	le := binary.LittleEndian
+
+	header := make([]byte, offset)
+	copy(header, "BM")
+	le.PutUint32(header[2:], uint32(offset+stride*height))
+	le.PutUint32(header[10:], uint32(offset))
+	le.PutUint32(header[14:], 40)
+	le.PutUint32(header[18:], uint32(width))
+	le.PutUint32(header[22:], uint32(height))
+	le.PutUint16(header[26:], 1)
+	le.PutUint16(header[28:], uint16(bpp))
+	le.PutUint32(header[34:], uint32(stride*height))
+	le.PutUint32(header[38:], 2835) // 72 DPI
+	le.PutUint32(header[42:], 2835)
+	le.PutUint32(header[46:], uint32(colors))
+	for i, c := range img.Palette {
+		y := c.(color.Gray).Y
+		copy(header[54+4*i:], []byte{y, y, y, 0})
+	}
+	bw.Write(header)
+
+	row := make([]byte, stride)
+	for y := height - 1; y >= 0; y-- {
+		for i := range row {
+			row[i] = 0
+		}
+		for x := 0; x < width; x++ {
+			idx := img.ColorIndexAt(img.Rect.Min.X+x, img.Rect.Min.Y+y)
+			bit := x * bpp
+			row[bit/8] |= idx << (8 - bpp - bit%8)
+		}
+		bw.Write(row)
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,64 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package energy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Reading is a single energy sample taken from a smart plug
+type Reading struct {
+	Watts    float64
+	TotalKWh float64
+}
+
+// Reader reads the current power draw and lifetime energy of a smart plug
+type Reader interface {
+	Read(ctx context.Context) (Reading, error)
+}
+
+// NewReader returns a Reader for the given plug type and address
+func NewReader(plugType, address string) (Reader, error) {
+	httpClient := &http.Client{Timeout: 5 * time.Second}
+
+	switch plugType {
+	case "tasmota":
+		return &tasmotaReader{baseURL: baseURL(address), httpClient: httpClient}, nil
+	case "shelly":
+		return &shellyReader{baseURL: baseURL(address), httpClient: httpClient}, nil
+	case "shelly-gen2":
+		return &shellyGen2Reader{baseURL: baseURL(address), httpClient: httpClient}, nil
+	case "kasa", "tplink":
+		return &kasaReader{address: kasaAddress(address)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported plug type %q", plugType)
+	}
+}
+
+func baseURL(address string) string {
+	address = strings.TrimSuffix(address, "/")
+	if !strings.Contains(address, "://") {
+		address = "http://" + address
+	}
+	return address
+}
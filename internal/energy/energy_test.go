@@ -0,0 +1,174 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package energy
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func serveJSON(t *testing.T, path, body string) string {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.RequestURI() != path {
+			http.NotFound(w, r)
+			return
+		}
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(srv.Close)
+	return strings.TrimPrefix(srv.URL, "http://")
+}
+
+func read(t *testing.T, plugType, address string) Reading {
+	t.Helper()
+	reader, err := NewReader(plugType, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reading, err := reader.Read(context.Background())
+	if err != nil {
+		t.Fatalf("%s: %v", plugType, err)
+	}
+	return reading
+}
+
+func TestHTTPPlugs(t *testing.T) {
+	tests := []struct {
+		plugType, path, body string
+		want                 Reading
+	}{
+		{"tasmota", "/cm?cmnd=Status%208", `{"StatusSNS": {"ENERGY": {"Total": 12.345, "Power": 87}}}`, Reading{Watts: 87, TotalKWh: 12.345}},
+		// Gen 1 Shellies count watt-minutes
+		{"shelly", "/status", `{"meters": [{"power": 120.5, "total": 600000}]}`, Reading{Watts: 120.5, TotalKWh: 10}},
+		{"shelly-gen2", "/rpc/Switch.GetStatus?id=0", `{"apower": 95.2, "aenergy": {"total": 2500}}`, Reading{Watts: 95.2, TotalKWh: 2.5}},
+	}
+	for _, tt := range tests {
+		if got := read(t, tt.plugType, serveJSON(t, tt.path, tt.body)); got != tt.want {
+			t.Errorf("%s reading = %+v, want %+v", tt.plugType, got, tt.want)
+		}
+	}
+
+	reader, _ := NewReader("shelly", serveJSON(t, "/status", `{"meters": []}`))
+	if _, err := reader.Read(context.Background()); err == nil {
+		t.Error("shelly without meters read without error")
+	}
+	reader, _ = NewReader("tasmota", serveJSON(t, "/other", ""))
+	if _, err := reader.Read(context.Background()); err == nil {
+		t.Error("tasmota 404 read without error")
+	}
+}
+
+// serveKasa answers one get_realtime request the way a Kasa plug does
+func serveKasa(t *testing.T, response string) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var length uint32
+		binary.Read(conn, binary.BigEndian, &length)
+		request := make([]byte, length)
+		io.ReadFull(conn, request)
+		if !strings.Contains(string(kasaDecrypt(request)), "get_realtime") {
+			t.Errorf("kasa request = %s", kasaDecrypt(request))
+			return
+		}
+		conn.Write(kasaEncrypt([]byte(response)))
+	}()
+	return ln.Addr().String()
+}
+
+func TestKasa(t *testing.T) {
+	tests := []struct {
+		name, response string
+		want           Reading
+	}{
+		{"current", `{"emeter": {"get_realtime": {"err_code": 0, "power_mw": 64500, "total_wh": 3200}}}`, Reading{Watts: 64.5, TotalKWh: 3.2}},
+		{"older hardware", `{"emeter": {"get_realtime": {"err_code": 0, "power": 64.5, "total": 3.2}}}`, Reading{Watts: 64.5, TotalKWh: 3.2}},
+	}
+	for _, tt := range tests {
+		if got := read(t, "kasa", serveKasa(t, tt.response)); got != tt.want {
+			t.Errorf("%s reading = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+
+	reader, _ := NewReader("kasa", serveKasa(t, `{"emeter": {"get_realtime": {"err_code": -1}}}`))
+	if _, err := reader.Read(context.Background()); err == nil {
+		t.Error("kasa error code read without error")
+	}
+}
+
+func TestKasaCipherRoundTrip(t *testing.T) {
+	plain := `{"system": {"get_sysinfo": {}}}`
+	if got := string(kasaDecrypt(kasaEncrypt([]byte(plain))[4:])); got != plain {
+		t.Errorf("round trip = %q", got)
+	}
+	if got := kasaAddress("10.0.0.5"); got != "10.0.0.5:9999" {
+		t.Errorf("kasaAddress without a port = %q", got)
+	}
+}
+
+type stubReader struct{ reading Reading }
+
+func (s *stubReader) Read(context.Context) (Reading, error) { return s.reading, nil }
+
+func TestMonitorJobEnergy(t *testing.T) {
+	plug := &stubReader{Reading{Watts: 100, TotalKWh: 5}}
+	m := NewMonitor(map[string]Reader{"mini": plug}, 0)
+
+	m.poll(context.Background())
+	if got := m.JobEnergy("mini", "benchy.gcode"); got != 0 {
+		t.Errorf("energy at job start = %v", got)
+	}
+	plug.reading.TotalKWh = 5.75
+	m.poll(context.Background())
+	if got := m.JobEnergy("mini", "benchy.gcode"); got != 0.75 {
+		t.Errorf("energy mid-job = %v, want 0.75", got)
+	}
+	if got := m.JobEnergy("mini", ""); got != 0.75 {
+		t.Errorf("energy when the job couldn't be identified = %v, want 0.75", got)
+	}
+
+	// The job stops: its total is still there to record until the baseline is cleared
+	plug.reading.TotalKWh = 6
+	m.poll(context.Background())
+	if got := m.LastJobEnergy("mini"); got != 1 {
+		t.Errorf("LastJobEnergy = %v, want 1", got)
+	}
+	m.EndJob("mini")
+	if got := m.LastJobEnergy("mini"); got != 0 {
+		t.Errorf("LastJobEnergy after the baseline cleared = %v", got)
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package energy
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Sample is the latest reading for a printer's plug along with when it was taken
+type Sample struct {
+	Reading
+	Time  time.Time
+	Error error
+}
+
+// Monitor polls smart plugs in the background and tracks energy used per job
+type Monitor struct {
+	readers  map[string]Reader
+	interval time.Duration
+
+	mu      sync.RWMutex
+	samples map[string]Sample
+	jobs    map[string]jobBaseline
+}
+
+type jobBaseline struct {
+	key      string
+	startKWh float64
+}
+
+// NewMonitor creates a Monitor for the given readers, keyed by printer ID
+func NewMonitor(readers map[string]Reader, interval time.Duration) *Monitor {
+	return &Monitor{
+		readers:  readers,
+		interval: interval,
+		samples:  make(map[string]Sample),
+		jobs:     make(map[string]jobBaseline),
+	}
+}
+
+// Run polls every plug until the context is cancelled
+func (m *Monitor) Run(ctx context.Context) {
+	if len(m.readers) == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		m.poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *Monitor) poll(ctx context.Context) {
+	var wg sync.WaitGroup
+	for id, reader := range m.readers {
+		wg.Add(1)
+		go func(id string, reader Reader) {
+			defer wg.Done()
+
+			reading, err := reader.Read(ctx)
+			if err != nil {
+				log.Printf("Error reading plug for %s: %v", id, err)
+			}
+
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			if err != nil {
+				// Keep the last good reading so job totals survive a missed poll
+				sample := m.samples[id]
+				sample.Error = err
+				m.samples[id] = sample
+				return
+			}
+			m.samples[id] = Sample{Reading: reading, Time: time.Now().UTC()}
+		}(id, reader)
+	}
+	wg.Wait()
+}
+
+// Latest returns the most recent sample for a printer, if it has a plug
+func (m *Monitor) Latest(printerID string) (Sample, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	sample, ok := m.samples[printerID]
+	return sample, ok && !sample.Time.IsZero()
+}
+
+// JobEnergy returns the kWh used since the job identified by key started. An
+// empty key is a job still running that couldn't be identified this time,
+// which keeps whatever baseline the printer has.
+func (m *Monitor) JobEnergy(printerID, key string) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sample, ok := m.samples[printerID]
+	if !ok || sample.Time.IsZero() {
+		return 0
+	}
+
+	baseline, ok := m.jobs[printerID]
+	if key == "" {
+		if !ok {
+			return 0
+		}
+		key = baseline.key
+	}
+	if !ok || baseline.key != key {
+		baseline = jobBaseline{key: key, startKWh: sample.TotalKWh}
+		m.jobs[printerID] = baseline
+	}
+
+	used := sample.TotalKWh - baseline.startKWh
+	if used < 0 {
+		// Plug counter was reset mid-job; restart the baseline
+		m.jobs[printerID] = jobBaseline{key: key, startKWh: sample.TotalKWh}
+		return 0
+	}
+	return used
+}
+
+// EndJob clears a printer's baseline once it has left its job
+func (m *Monitor) EndJob(printerID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.jobs, printerID)
+}
+
+// LastJobEnergy returns the kWh used by the job a printer has just stopped,
+// for recording before EndJob clears its baseline
+func (m *Monitor) LastJobEnergy(printerID string) float64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	baseline, ok := m.jobs[printerID]
+	sample := m.samples[printerID]
+	if !ok || sample.Time.IsZero() {
+		return 0
+	}
+	return max(sample.TotalKWh-baseline.startKWh, 0)
+}
@@ -0,0 +1,97 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package energy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// shellyReader reads first generation Shelly plugs, which report totals in watt-minutes
+type shellyReader struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+func (r *shellyReader) Read(ctx context.Context) (Reading, error) {
+	var response struct {
+		Meters []struct {
+			Power float64 `json:"power"`
+			Total float64 `json:"total"`
+		} `json:"meters"`
+	}
+
+	if err := getJSON(ctx, r.httpClient, r.baseURL+"/status", &response); err != nil {
+		return Reading{}, err
+	}
+
+	if len(response.Meters) == 0 {
+		return Reading{}, fmt.Errorf("shelly reported no meters")
+	}
+
+	return Reading{
+		Watts:    response.Meters[0].Power,
+		TotalKWh: response.Meters[0].Total / 60 / 1000,
+	}, nil
+}
+
+// shellyGen2Reader reads Gen2+ Shelly plugs through the RPC API
+type shellyGen2Reader struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+func (r *shellyGen2Reader) Read(ctx context.Context) (Reading, error) {
+	var response struct {
+		APower  float64 `json:"apower"`
+		AEnergy struct {
+			Total float64 `json:"total"`
+		} `json:"aenergy"`
+	}
+
+	if err := getJSON(ctx, r.httpClient, r.baseURL+"/rpc/Switch.GetStatus?id=0", &response); err != nil {
+		return Reading{}, err
+	}
+
+	return Reading{
+		Watts:    response.APower,
+		TotalKWh: response.AEnergy.Total / 1000,
+	}, nil
+}
+
+func getJSON(ctx context.Context, client *http.Client, url string, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
@@ -0,0 +1,50 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package energy
+
+import (
+	"context"
+	"net/http"
+)
+
+// tasmotaReader reads Tasmota plugs through the Status 8 sensor command
+type tasmotaReader struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+func (r *tasmotaReader) Read(ctx context.Context) (Reading, error) {
+	var response struct {
+		StatusSNS struct {
+			Energy struct {
+				Power float64 `json:"Power"`
+				Total float64 `json:"Total"`
+			} `json:"ENERGY"`
+		} `json:"StatusSNS"`
+	}
+
+	if err := getJSON(ctx, r.httpClient, r.baseURL+"/cm?cmnd=Status%208", &response); err != nil {
+		return Reading{}, err
+	}
+
+	return Reading{
+		Watts:    response.StatusSNS.Energy.Power,
+		TotalKWh: response.StatusSNS.Energy.Total,
+	}, nil
+}
@@ -0,0 +1,130 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package errcodes turns the terse error strings firmware and OctoPrint
+// report into explanations an operator can act on
+package errcodes
+
+import (
+	"strings"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+)
+
+// entry describes one known error. Patterns are matched case-insensitively
+// as substrings of the printer's state text.
+type entry struct {
+	code     string
+	patterns []string
+	summary  string
+	action   string
+}
+
+// table lists known errors, most specific first: firmware prefixes many of
+// these with a generic "Printer halted", so the halt entry comes last
+var table = []entry{
+	{
+		code:     "mintemp",
+		patterns: []string{"mintemp"},
+		summary:  "A thermistor reads below its minimum temperature, usually because it or its wiring is disconnected or broken",
+		action:   "Power the printer off, check the thermistor plug and wiring at the heater and the board, and replace the thermistor if it reads open",
+	},
+	{
+		code:     "maxtemp",
+		patterns: []string{"maxtemp"},
+		summary:  "A thermistor reads above its maximum temperature, from a shorted thermistor or a heater stuck on",
+		action:   "Power the printer off now, let it cool, and check for shorted thermistor wires or a failed heater MOSFET before restarting",
+	},
+	{
+		code:     "thermal_runaway",
+		patterns: []string{"thermal runaway"},
+		summary:  "A heater drifted away from its target, so the firmware cut power in case the thermistor has come loose",
+		action:   "Check the thermistor is seated in the heater block, the heater cartridge is secure and the part fan isn't blowing on the block, then reset the printer",
+	},
+	{
+		code:     "heating_failed",
+		patterns: []string{"heating failed", "heating_failed"},
+		summary:  "A heater didn't reach its target in the time the firmware allows",
+		action:   "Check the heater cartridge and its wiring and connector, and that the power supply is delivering full voltage",
+	},
+	{
+		code:     "probing_failed",
+		patterns: []string{"probing failed", "probe failed", "bltouch", "probe triggered before move"},
+		summary:  "The bed probe didn't trigger when expected during homing or mesh leveling",
+		action:   "Clean the nozzle and bed, check the probe and its cable, then home and start the print again",
+	},
+	{
+		code:     "homing_failed",
+		patterns: []string{"homing failed", "failed to home", "endstop"},
+		summary:  "An axis didn't reach its endstop while homing",
+		action:   "Make sure the axis moves freely by hand and check the endstop switch or sensorless homing sensitivity",
+	},
+	{
+		code:     "serial_not_found",
+		patterns: []string{"failed to autodetect serial port", "no more candidates", "serial port not found"},
+		summary:  "OctoPrint couldn't find the printer's serial port",
+		action:   "Check the USB cable is plugged in at both ends and the printer is powered on, then reconnect from OctoPrint",
+	},
+	{
+		code:     "serial_open",
+		patterns: []string{"could not open serial", "permission denied"},
+		summary:  "OctoPrint found the serial port but couldn't open it, often because another program holds it",
+		action:   "Close other programs using the port, check the OctoPrint user can access it, then reconnect",
+	},
+	{
+		code:     "serial_timeout",
+		patterns: []string{"too many consecutive timeouts", "communication timeout", "no response from printer"},
+		summary:  "The printer stopped answering OctoPrint over serial",
+		action:   "Check the printer's display for a firmware error, reseat or replace the USB cable, then power cycle and reconnect",
+	},
+	{
+		code:     "serial_checksum",
+		patterns: []string{"checksum mismatch", "line number is not last line number"},
+		summary:  "Commands are arriving corrupted over serial",
+		action:   "Replace the USB cable with a shorter shielded one, route it away from motor wires, or lower the baud rate",
+	},
+	{
+		code:     "connection_lost",
+		patterns: []string{"offline after error", "connection error", "connection closed", "serial exception"},
+		summary:  "The serial connection between OctoPrint and the printer closed after an error",
+		action:   "Check the OctoPrint terminal for the last firmware message, fix that cause, then reconnect",
+	},
+	{
+		code:     "halted",
+		patterns: []string{"printer halted", "kill() called", "printer stopped due to errors"},
+		summary:  "The firmware halted the printer and won't accept commands until it is reset",
+		action:   "Read the error on the printer's display, fix the cause, then reset or power cycle the printer and reconnect",
+	},
+}
+
+// Decode explains an error reported by firmware or OctoPrint, or returns nil
+// when the text matches nothing known
+func Decode(text string) *models.ErrorDiagnosis {
+	text = strings.ToLower(text)
+	if text == "" {
+		return nil
+	}
+	for _, e := range table {
+		for _, p := range e.patterns {
+			if strings.Contains(text, p) {
+				return &models.ErrorDiagnosis{Code: e.code, Summary: e.summary, Action: e.action}
+			}
+		}
+	}
+	return nil
+}
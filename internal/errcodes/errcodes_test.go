@@ -0,0 +1,67 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errcodes
+
+import "testing"
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Error: MINTEMP triggered, system stopped! Heater_ID: bed", "mintemp"},
+		{"Error: Printer halted. kill() called! MAXTEMP triggered", "maxtemp"},
+		{"Error: Thermal Runaway, system stopped! Heater_ID: 0", "thermal_runaway"},
+		{"Error: Heating failed, system stopped!", "heating_failed"},
+		{"Error: Probing Failed", "probing_failed"},
+		{"Error: Failed to autodetect serial port, please set it manually.", "serial_not_found"},
+		{"Error: Too many consecutive timeouts, printer still connected?", "serial_timeout"},
+		{"Offline after error", "connection_lost"},
+		{"Error: Printer halted. kill() called!", "halted"},
+		{"Error: something nobody has seen before", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		got := Decode(tt.text)
+		code := ""
+		if got != nil {
+			code = got.Code
+			if got.Summary == "" || got.Action == "" {
+				t.Errorf("Decode(%q) = %+v, want a summary and an action", tt.text, got)
+			}
+		}
+		if code != tt.want {
+			t.Errorf("Decode(%q) code = %q, want %q", tt.text, code, tt.want)
+		}
+	}
+}
+
+func TestTableCodesUnique(t *testing.T) {
+	seen := map[string]bool{}
+	for _, e := range table {
+		if seen[e.code] {
+			t.Errorf("code %q listed twice", e.code)
+		}
+		seen[e.code] = true
+		if len(e.patterns) == 0 {
+			t.Errorf("code %q has no patterns", e.code)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package eventbus publishes OctoDash's domain events to NATS or Redis Streams
+// for consumers that would rather subscribe than receive webhooks.
+//
+// Every event is the same JSON envelope sent to webhooks:
+//
+//	{"id": "0000000042", "type": "job.finished", "time": "2025-03-01T09:00:00Z",
+//	 "printer_id": "printer-1", "data": {...}}
+//
+// IDs increase with every event. The data for each type is:
+//
+//	printer.status    the status transition: id, printer_id, time, from, to, and state, file, error when known
+//	job.started       file
+//	job.finished      file, result (completed, cancelled or failed), completion, print_time
+//	alert.raised      kind (error, interrupted or rule), with error and file, the interrupted print, or the rule's id, name and when
+//	queue.dispatched  the queue entry as returned by /api/queue
+//
+// On NATS each event is published to the subject <topic>.<type>, such as
+// octodash.job.finished, so consumers can subscribe to octodash.> or to one
+// type. On Redis each event is added to the <topic> stream with the fields
+// id, type, printer_id, time and event, the last holding the envelope.
+//
+// Delivery is at most once: events are buffered in memory while the broker is
+// unreachable and dropped once the buffer fills or OctoDash restarts. Use
+// webhooks where every event must arrive.
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/settings"
+)
+
+const (
+	// buffered is how many events can wait for the broker before new ones are dropped
+	buffered = 1000
+	// sendAttempts is how many connections an event is tried on before it is dropped
+	sendAttempts = 3
+	// retryWait is the pause before reconnecting after a failure
+	retryWait = 2 * time.Second
+	// ioTimeout bounds each exchange with the broker
+	ioTimeout = 10 * time.Second
+)
+
+// conn is an open connection to a broker
+type conn interface {
+	publish(e models.Event, payload []byte) error
+	Close() error
+}
+
+// Bus publishes events to the configured broker from a background loop
+type Bus struct {
+	dial   func(ctx context.Context) (conn, error)
+	logger *log.Logger
+	events chan models.Event
+	conn   conn
+}
+
+// New creates a Bus for the broker at cfg.URL; call Run to start publishing
+func New(cfg settings.EventBusSettings, logger *log.Logger) (*Bus, error) {
+	b := &Bus{logger: logger, events: make(chan models.Event, buffered)}
+
+	switch cfg.URL.Scheme {
+	case "nats":
+		b.dial = func(ctx context.Context) (conn, error) { return dialNATS(ctx, cfg.URL, cfg.Topic) }
+	case "redis", "rediss":
+		b.dial = func(ctx context.Context) (conn, error) { return dialRedis(ctx, cfg.URL, cfg.Topic, cfg.MaxLen) }
+	default:
+		return nil, fmt.Errorf("unsupported event bus scheme %q", cfg.URL.Scheme)
+	}
+	return b, nil
+}
+
+// Publish queues an event without waiting for the broker, dropping it if the queue is full
+func (b *Bus) Publish(e models.Event) {
+	select {
+	case b.events <- e:
+	default:
+		b.logger.Printf("Event bus is backed up; dropped %s event %s", e.Type, e.ID)
+	}
+}
+
+// Run publishes queued events until ctx is cancelled
+func (b *Bus) Run(ctx context.Context) {
+	defer func() {
+		if b.conn != nil {
+			b.conn.Close()
+		}
+	}()
+
+	for {
+		select {
+		case e := <-b.events:
+			b.send(ctx, e)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// send publishes one event, reconnecting between attempts
+func (b *Bus) send(ctx context.Context, e models.Event) {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		b.logger.Printf("Failed to encode %s event %s: %v", e.Type, e.ID, err)
+		return
+	}
+
+	for attempt := 1; ; attempt++ {
+		if err = b.publish(ctx, e, payload); err == nil {
+			return
+		}
+		if attempt == sendAttempts {
+			b.logger.Printf("Dropped %s event %s after %d attempts: %v", e.Type, e.ID, attempt, err)
+			return
+		}
+
+		select {
+		case <-time.After(retryWait):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (b *Bus) publish(ctx context.Context, e models.Event, payload []byte) error {
+	if b.conn == nil {
+		c, err := b.dial(ctx)
+		if err != nil {
+			return err
+		}
+		b.conn = c
+	}
+
+	if err := b.conn.publish(e, payload); err != nil {
+		b.conn.Close()
+		b.conn = nil
+		return err
+	}
+	return nil
+}
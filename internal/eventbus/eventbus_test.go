@@ -0,0 +1,159 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventbus
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/settings"
+)
+
+// fakeBroker accepts one connection and hands it to serve
+func fakeBroker(t *testing.T, serve func(r *bufio.Reader, w io.Writer)) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		c, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		serve(bufio.NewReader(c), c)
+	}()
+	return l.Addr().String()
+}
+
+func publishOne(t *testing.T, rawURL string) {
+	t.Helper()
+	u, _ := url.Parse(rawURL)
+	bus, err := New(settings.EventBusSettings{URL: u, Topic: "octodash", MaxLen: 500}, log.New(io.Discard, "", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go bus.Run(ctx)
+
+	bus.Publish(models.Event{
+		ID:        "0000000007",
+		Type:      models.EventJobFinished,
+		Time:      time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC),
+		PrinterID: "printer-1",
+		Data:      map[string]interface{}{"file": "gear.gcode"},
+	})
+}
+
+func TestNATS(t *testing.T) {
+	got := make(chan string, 2)
+	addr := fakeBroker(t, func(r *bufio.Reader, w io.Writer) {
+		io.WriteString(w, "INFO {\"server_id\":\"fake\"}\r\n")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			fields := strings.Fields(line)
+			switch fields[0] {
+			case "CONNECT":
+				got <- strings.TrimSpace(line)
+			case "PING":
+				io.WriteString(w, "PONG\r\n")
+			case "PUB":
+				n, _ := strconv.Atoi(fields[2])
+				payload := make([]byte, n+2)
+				io.ReadFull(r, payload)
+				got <- fields[1] + " " + string(payload[:n])
+			}
+		}
+	})
+	publishOne(t, "nats://octo:pw@"+addr)
+
+	if connect := <-got; !strings.Contains(connect, `"user":"octo"`) || !strings.Contains(connect, `"pass":"pw"`) {
+		t.Errorf("CONNECT = %s", connect)
+	}
+	select {
+	case pub := <-got:
+		if !strings.HasPrefix(pub, "octodash.job.finished {") || !strings.Contains(pub, `"data":{"file":"gear.gcode"}`) {
+			t.Errorf("PUB = %s", pub)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("nothing published")
+	}
+}
+
+func TestRedisStreams(t *testing.T) {
+	got := make(chan []string, 3)
+	addr := fakeBroker(t, func(r *bufio.Reader, w io.Writer) {
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			n, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+			args := make([]string, n)
+			for i := range args {
+				header, _ := r.ReadString('\n')
+				size, _ := strconv.Atoi(strings.TrimSpace(header[1:]))
+				buf := make([]byte, size+2)
+				io.ReadFull(r, buf)
+				args[i] = string(buf[:size])
+			}
+			got <- args
+			if args[0] == "XADD" {
+				fmt.Fprintf(w, "$15\r\n1740819600000-0\r\n")
+			} else {
+				io.WriteString(w, "+OK\r\n")
+			}
+		}
+	})
+	publishOne(t, "redis://:pw@"+addr+"/2")
+
+	var commands []string
+	for range 3 {
+		select {
+		case args := <-got:
+			commands = append(commands, strings.Join(args[:min(len(args), 7)], " "))
+			if args[0] == "XADD" && !strings.Contains(args[len(args)-1], `"type":"job.finished"`) {
+				t.Errorf("event field = %s", args[len(args)-1])
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("got %v before timing out", commands)
+		}
+	}
+	want := "AUTH pw,SELECT 2,XADD octodash MAXLEN ~ 500 * id"
+	if s := strings.Join(commands, ","); s != want {
+		t.Errorf("commands = %s, want %s", s, want)
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventbus
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/version"
+)
+
+// natsConn speaks just enough of the NATS client protocol to publish
+type natsConn struct {
+	net.Conn
+	r      *bufio.Reader
+	prefix string
+}
+
+// dialNATS connects and authenticates with the user and password, or the
+// token, in u
+func dialNATS(ctx context.Context, u *url.URL, prefix string) (*natsConn, error) {
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(u.Hostname(), "4222")
+	}
+
+	var d net.Dialer
+	nc, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	c := &natsConn{Conn: nc, r: bufio.NewReader(nc), prefix: prefix}
+	c.SetDeadline(time.Now().Add(ioTimeout))
+
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "INFO ") {
+		c.Close()
+		return nil, fmt.Errorf("unexpected NATS greeting %q", strings.TrimSpace(line))
+	}
+
+	opts := map[string]interface{}{
+		"verbose":  false,
+		"pedantic": false,
+		"name":     "octodash",
+		"lang":     "go",
+		"version":  version.String(),
+	}
+	if u.User != nil {
+		if pass, ok := u.User.Password(); ok {
+			opts["user"], opts["pass"] = u.User.Username(), pass
+		} else {
+			opts["auth_token"] = u.User.Username()
+		}
+	}
+	connect, _ := json.Marshal(opts)
+	if _, err := fmt.Fprintf(c, "CONNECT %s\r\nPING\r\n", connect); err != nil {
+		c.Close()
+		return nil, err
+	}
+	if err := c.awaitPong(); err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// publish sends the event to <prefix>.<type> and pings so a rejected publish
+// surfaces as an error
+func (c *natsConn) publish(e models.Event, payload []byte) error {
+	c.SetDeadline(time.Now().Add(ioTimeout))
+	if _, err := fmt.Fprintf(c, "PUB %s.%s %d\r\n%s\r\nPING\r\n", c.prefix, e.Type, len(payload), payload); err != nil {
+		return err
+	}
+	return c.awaitPong()
+}
+
+// awaitPong reads until the server answers a PING, answering its own PINGs
+func (c *natsConn) awaitPong() error {
+	for {
+		line, err := c.r.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "PONG":
+			return nil
+		case line == "PING":
+			if _, err := c.Write([]byte("PONG\r\n")); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			return fmt.Errorf("NATS: %s", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+	}
+}
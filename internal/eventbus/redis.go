@@ -0,0 +1,147 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventbus
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+)
+
+// redisConn speaks just enough RESP to append to a stream
+type redisConn struct {
+	net.Conn
+	r      *bufio.Reader
+	stream string
+	maxLen int
+}
+
+// dialRedis connects, authenticates with the password and optional ACL user
+// in u, and selects the database named by its path
+func dialRedis(ctx context.Context, u *url.URL, stream string, maxLen int) (*redisConn, error) {
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+
+	var d net.Dialer
+	nc, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "rediss" {
+		tc := tls.Client(nc, &tls.Config{ServerName: u.Hostname()})
+		if err := tc.HandshakeContext(ctx); err != nil {
+			nc.Close()
+			return nil, err
+		}
+		nc = tc
+	}
+	c := &redisConn{Conn: nc, r: bufio.NewReader(nc), stream: stream, maxLen: maxLen}
+
+	if pass, ok := u.User.Password(); ok {
+		args := []string{"AUTH", pass}
+		if user := u.User.Username(); user != "" {
+			args = []string{"AUTH", user, pass}
+		}
+		if _, err := c.do(args...); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+	if db := strings.Trim(u.Path, "/"); db != "" && db != "0" {
+		if _, err := strconv.Atoi(db); err != nil {
+			c.Close()
+			return nil, fmt.Errorf("invalid Redis database %q", db)
+		}
+		if _, err := c.do("SELECT", db); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// publish appends the event to the stream, trimming it to about maxLen entries
+func (c *redisConn) publish(e models.Event, payload []byte) error {
+	args := []string{"XADD", c.stream}
+	if c.maxLen > 0 {
+		args = append(args, "MAXLEN", "~", strconv.Itoa(c.maxLen))
+	}
+	args = append(args, "*",
+		"id", e.ID,
+		"type", e.Type,
+		"printer_id", e.PrinterID,
+		"time", e.Time.Format(time.RFC3339),
+		"event", string(payload))
+	_, err := c.do(args...)
+	return err
+}
+
+// do sends a command and reads a simple, integer or bulk string reply
+func (c *redisConn) do(args ...string) (string, error) {
+	c.SetDeadline(time.Now().Add(ioTimeout))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, a := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(a), a)
+	}
+	if _, err := c.Write([]byte(b.String())); err != nil {
+		return "", err
+	}
+
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return "", errors.New("empty Redis reply")
+	}
+
+	switch line[0] {
+	case '+', ':':
+		return line[1:], nil
+	case '-':
+		return "", fmt.Errorf("Redis %s: %s", args[0], line[1:])
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			return "", err
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(c.r, buf); err != nil {
+			return "", err
+		}
+		return string(buf[:n]), nil
+	default:
+		return "", fmt.Errorf("unexpected Redis reply %q", line)
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/store"
+)
+
+// Log is a rolling, per-printer history of status transitions
+type Log struct {
+	store  *store.Store
+	retain int
+
+	mu   sync.Mutex
+	last map[string]string
+}
+
+// New creates a Log keeping at most retain events per printer
+func New(s *store.Store, retain int) *Log {
+	return &Log{store: s, retain: retain, last: make(map[string]string)}
+}
+
+// BucketPrefix starts the name of each printer's event bucket
+const BucketPrefix = "events:"
+
+func bucket(printerID string) string {
+	return BucketPrefix + printerID
+}
+
+// Observe records an event if the printer's status differs from the last one
+// seen, returning the event recorded or nil when the status is unchanged
+func (l *Log) Observe(p *models.PrinterStatus, at time.Time) (*models.StatusEvent, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	prev, ok := l.last[p.ID]
+	if !ok {
+		// Pick up where the persisted log left off across restarts
+		recent, err := l.List(p.ID, time.Time{}, 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(recent) > 0 {
+			prev = recent[0].To
+		}
+	}
+	l.last[p.ID] = p.Status
+
+	if prev == p.Status {
+		return nil, nil
+	}
+
+	id, err := l.store.NextID(bucket(p.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	event := models.StatusEvent{
+		ID:        id,
+		PrinterID: p.ID,
+		Time:      at.UTC().Truncate(time.Second),
+		From:      prev,
+		To:        p.Status,
+		State:     p.State,
+		Error:     p.Error,
+	}
+	if p.Progress != nil {
+		event.File = p.Progress.FileName
+	}
+
+	if err := l.store.Put(bucket(p.ID), id, event); err != nil {
+		return nil, err
+	}
+	return &event, l.store.Trim(bucket(p.ID), l.retain)
+}
+
+// List returns a printer's events newest first, optionally only those after since, up to limit (0 for all)
+func (l *Log) List(printerID string, since time.Time, limit int) ([]models.StatusEvent, error) {
+	all, err := store.List[models.StatusEvent](l.store, bucket(printerID))
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(all, func(i, j int) bool { return all[i].ID > all[j].ID })
+
+	events := []models.StatusEvent{}
+	for _, e := range all {
+		if !since.IsZero() && !e.Time.After(since) {
+			break
+		}
+		if limit > 0 && len(events) >= limit {
+			break
+		}
+		events = append(events, e)
+	}
+	return events, nil
+}
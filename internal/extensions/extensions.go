@@ -0,0 +1,121 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package extensions lets site-specific integrations plug into OctoDash
+// without changing the handler. Extensions are compiled in: a package in this
+// module calls Register from an init function and the server binary imports it
+// for that side effect (see cmd/server/extensions.go). Each extension implements any of
+// Enricher, RouteProvider, CardProvider and Starter for what it adds.
+package extensions
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"regexp"
+	"sort"
+	"sync"
+
+	"github.com/wmarchesi123/go-3dprint-client/config"
+	"github.com/wmarchesi123/octodash/internal/auth"
+	"github.com/wmarchesi123/octodash/internal/models"
+)
+
+// Extension is a named integration. The name keys its status data and
+// prefixes its routes, and its settings belong in EXT_<NAME>_ variables.
+type Extension interface {
+	Name() string
+}
+
+// Enricher adds data to every printer's status, shown under extensions.<name>.
+// It runs on each poll, so it should answer from its own cache rather than
+// call out; a nil result adds nothing.
+type Enricher interface {
+	Enrich(ctx context.Context, status *models.PrinterStatus) (interface{}, error)
+}
+
+// Route is an endpoint served at /api/ext/<name>/<Path>. Path may use mux
+// wildcards; Role is the lowest role allowed, with zero leaving it open like
+// the dashboard's read-only API.
+type Route struct {
+	Method  string
+	Path    string
+	Role    auth.Role
+	Handler http.HandlerFunc
+}
+
+// RouteProvider adds API routes
+type RouteProvider interface {
+	Routes() []Route
+}
+
+// CardProvider adds a section to each printer card: an Alpine.js fragment
+// shown when printer.extensions[name] holds enriched data for that printer
+type CardProvider interface {
+	CardSection() template.HTML
+}
+
+// Host is what OctoDash shares with extensions as they start
+type Host struct {
+	Printers []config.Printer
+	Logger   *log.Logger
+}
+
+// Starter is implemented by extensions with setup or background work; ctx is
+// cancelled when OctoDash shuts down
+type Starter interface {
+	Start(ctx context.Context, host Host) error
+}
+
+var validName = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+var (
+	mu         sync.Mutex
+	registered = make(map[string]Extension)
+)
+
+// Register makes an extension available, panicking on a malformed or
+// duplicate name as http.Handle does for patterns
+func Register(e Extension) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	name := e.Name()
+	if !validName.MatchString(name) {
+		panic(fmt.Sprintf("extensions: invalid name %q, want lower-case letters, digits and dashes", name))
+	}
+	if _, ok := registered[name]; ok {
+		panic(fmt.Sprintf("extensions: %s registered twice", name))
+	}
+	registered[name] = e
+}
+
+// All returns the registered extensions sorted by name
+func All() []Extension {
+	mu.Lock()
+	defer mu.Unlock()
+
+	all := make([]Extension, 0, len(registered))
+	for _, e := range registered {
+		all = append(all, e)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Name() < all[j].Name() })
+	return all
+}
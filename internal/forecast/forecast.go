@@ -0,0 +1,213 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package forecast projects filament run-out dates from Spoolman's usage records
+package forecast
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wmarchesi123/go-3dprint-client/spoolman"
+	"github.com/wmarchesi123/octodash/internal/models"
+)
+
+const day = 24 * time.Hour
+
+// Inventory is the spools held by one Spoolman instance
+type Inventory struct {
+	Name   string
+	Spools []spoolman.Spool
+}
+
+// Options tune the forecast. A spool counts towards its material's rate only
+// if it was used within Window; materials running out within ReorderWithin
+// are flagged for reordering.
+type Options struct {
+	Window        time.Duration
+	ReorderWithin time.Duration
+}
+
+// Build forecasts every unarchived spool and material in the inventories.
+// A spool's rate is its used weight spread over the time since it was first
+// used; a material's rate is the sum over its recently used spools.
+func Build(inventories []Inventory, now time.Time, opts Options) models.Forecast {
+	f := models.Forecast{
+		GeneratedAt: now.UTC().Truncate(time.Second),
+		Materials:   []models.MaterialForecast{},
+		Spools:      []models.SpoolForecast{},
+		Reorder:     []models.MaterialForecast{},
+	}
+
+	materials := make(map[string]*models.MaterialForecast)
+	stock := make(map[string]float64) // initial weight across a material's spools
+	for _, inv := range inventories {
+		for i := range inv.Spools {
+			sp := &inv.Spools[i]
+			if sp.Archived {
+				continue
+			}
+
+			rate := dailyUse(sp, now, opts.Window)
+			remaining := math.Max(sp.RemainingWeight, 0)
+			info := spoolman.FormatSpoolInfo(sp)
+			spool := models.SpoolForecast{
+				ID:              sp.ID,
+				Name:            info["name"].(string),
+				Material:        material(sp),
+				Vendor:          sp.Filament.Vendor.Name,
+				Color:           info["color"].(string),
+				RemainingWeight: remaining,
+				DailyUse:        round(rate),
+			}
+			if len(inventories) > 1 {
+				spool.Spoolman = inv.Name
+			}
+			spool.DaysLeft, spool.RunsOutAt = project(remaining, rate, now)
+			f.Spools = append(f.Spools, spool)
+
+			m := materials[spool.Material]
+			if m == nil {
+				m = &models.MaterialForecast{Material: spool.Material}
+				materials[spool.Material] = m
+			}
+			m.Spools++
+			m.RemainingWeight += remaining
+			m.DailyUse += rate
+			stock[spool.Material] += sp.InitialWeight
+		}
+	}
+
+	for name, m := range materials {
+		m.DaysLeft, m.RunsOutAt = project(m.RemainingWeight, m.DailyUse, now)
+		m.Reorder = m.DaysLeft != nil && *m.DaysLeft <= opts.ReorderWithin.Hours()/24
+		if m.Reorder {
+			m.OrderSpools = orderSpools(m, stock[name]/float64(m.Spools), opts.Window)
+		}
+		m.DailyUse = round(m.DailyUse)
+		f.Materials = append(f.Materials, *m)
+	}
+
+	// Soonest to run out first; materials and spools not in use go last
+	sort.Slice(f.Materials, func(i, j int) bool {
+		a, b := f.Materials[i], f.Materials[j]
+		return sooner(a.DaysLeft, b.DaysLeft, a.Material, b.Material)
+	})
+	sort.Slice(f.Spools, func(i, j int) bool {
+		a, b := f.Spools[i], f.Spools[j]
+		return sooner(a.DaysLeft, b.DaysLeft, a.Name, b.Name)
+	})
+	for _, m := range f.Materials {
+		if m.Reorder {
+			f.Reorder = append(f.Reorder, m)
+		}
+	}
+
+	return f
+}
+
+// ShoppingList writes the materials due for reordering as CSV
+func ShoppingList(w io.Writer, f models.Forecast) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"material", "spools_to_order", "remaining_g", "daily_use_g", "days_left", "runs_out"})
+	for _, m := range f.Reorder {
+		cw.Write([]string{
+			m.Material,
+			fmt.Sprint(m.OrderSpools),
+			fmt.Sprintf("%.0f", m.RemainingWeight),
+			fmt.Sprintf("%.1f", m.DailyUse),
+			fmt.Sprintf("%.1f", *m.DaysLeft),
+			m.RunsOutAt.Format("2006-01-02"),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// dailyUse is the grams per day a spool has used since it was first used,
+// or zero if it is unused or has sat idle for longer than window
+func dailyUse(sp *spoolman.Spool, now time.Time, window time.Duration) float64 {
+	first, ok := parseTime(sp.FirstUsed)
+	if !ok || sp.UsedWeight <= 0 {
+		return 0
+	}
+	if last, ok := parseTime(sp.LastUsed); ok && window > 0 && now.Sub(last) > window {
+		return 0
+	}
+
+	days := math.Max(now.Sub(first).Hours()/24, 1)
+	return sp.UsedWeight / days
+}
+
+// project turns a remaining weight and daily rate into days left and a run-out date
+func project(remaining, rate float64, now time.Time) (*float64, *time.Time) {
+	if rate <= 0 {
+		return nil, nil
+	}
+	days := round(remaining / rate)
+	at := now.UTC().Add(time.Duration(days * float64(day))).Truncate(time.Second)
+	return &days, &at
+}
+
+// orderSpools suggests enough spools of the material's usual size to cover another window of use
+func orderSpools(m *models.MaterialForecast, spoolWeight float64, window time.Duration) int {
+	if spoolWeight <= 0 {
+		spoolWeight = 1000
+	}
+	need := m.DailyUse*window.Hours()/24 - m.RemainingWeight
+	return max(int(math.Ceil(need/spoolWeight)), 1)
+}
+
+func material(sp *spoolman.Spool) string {
+	if m := strings.TrimSpace(sp.Filament.Material); m != "" {
+		return m
+	}
+	return "Unknown"
+}
+
+// parseTime reads Spoolman's timestamps, which may omit the zone (UTC)
+func parseTime(s *string) (time.Time, bool) {
+	if s == nil || *s == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999"} {
+		if t, err := time.Parse(layout, *s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func sooner(a, b *float64, nameA, nameB string) bool {
+	switch {
+	case a != nil && b != nil && *a != *b:
+		return *a < *b
+	case (a == nil) != (b == nil):
+		return a != nil
+	}
+	return nameA < nameB
+}
+
+func round(v float64) float64 {
+	return math.Round(v*10) / 10
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forecast
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wmarchesi123/go-3dprint-client/spoolman"
+)
+
+func usedSpool(id int, material string, remaining, used float64, first, last time.Time) spoolman.Spool {
+	firstUsed, lastUsed := first.Format(time.RFC3339), last.Format("2006-01-02T15:04:05")
+	return spoolman.Spool{
+		ID:              id,
+		InitialWeight:   1000,
+		RemainingWeight: remaining,
+		UsedWeight:      used,
+		FirstUsed:       &firstUsed,
+		LastUsed:        &lastUsed,
+		Filament:        spoolman.Filament{Material: material, Vendor: spoolman.Vendor{Name: "Acme"}},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	ago := func(days int) time.Time { return now.Add(-time.Duration(days) * day) }
+
+	archived := usedSpool(5, "PETG", 0, 1000, ago(90), ago(40))
+	archived.Archived = true
+	inv := []Inventory{{Name: "default", Spools: []spoolman.Spool{
+		usedSpool(1, "PLA", 400, 300, ago(10), ago(1)),
+		{ID: 2, InitialWeight: 1000, RemainingWeight: 1000, Filament: spoolman.Filament{Material: "PLA"}},
+		usedSpool(3, "PETG", 50, 200, ago(20), ago(0)),
+		usedSpool(4, "TPU", 300, 200, ago(90), ago(60)),
+		archived,
+	}}}
+
+	f := Build(inv, now, Options{Window: 30 * day, ReorderWithin: 14 * day})
+
+	if len(f.Materials) != 3 {
+		t.Fatalf("got %d materials, want 3: %+v", len(f.Materials), f.Materials)
+	}
+	petg, pla, tpu := f.Materials[0], f.Materials[1], f.Materials[2]
+	if petg.Material != "PETG" || pla.Material != "PLA" || tpu.Material != "TPU" {
+		t.Fatalf("materials in order %s, %s, %s; want PETG, PLA, TPU", petg.Material, pla.Material, tpu.Material)
+	}
+
+	if petg.DailyUse != 10 || petg.DaysLeft == nil || *petg.DaysLeft != 5 || !petg.Reorder || petg.OrderSpools != 1 {
+		t.Errorf("PETG = %+v, want 10 g/day, 5 days left, reorder 1 spool", petg)
+	}
+	if pla.Spools != 2 || pla.RemainingWeight != 1400 || pla.DailyUse != 30 || pla.Reorder {
+		t.Errorf("PLA = %+v, want 2 spools, 1400g at 30 g/day, no reorder", pla)
+	}
+	if tpu.DailyUse != 0 || tpu.DaysLeft != nil {
+		t.Errorf("TPU = %+v, want no forecast for a spool idle past the window", tpu)
+	}
+
+	if len(f.Spools) != 4 || f.Spools[0].ID != 3 {
+		t.Errorf("spools = %+v, want 4 with PETG spool 3 first", f.Spools)
+	}
+	if len(f.Reorder) != 1 || f.Reorder[0].Material != "PETG" {
+		t.Errorf("reorder = %+v, want PETG only", f.Reorder)
+	}
+
+	var buf bytes.Buffer
+	if err := ShoppingList(&buf, f); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || lines[1] != "PETG,1,50,10.0,5.0,2025-06-06" {
+		t.Errorf("shopping list = %q", buf.String())
+	}
+}
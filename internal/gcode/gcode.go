@@ -0,0 +1,110 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gcode reads the print estimates slicers leave in gcode comments
+package gcode
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Estimate is what the slicer expects a file to take
+type Estimate struct {
+	PrintTime int     // seconds
+	Filament  float64 // meters
+	Weight    float64 // grams, when the slicer knows the material density
+}
+
+// Found reports whether any estimate was present
+func (e Estimate) Found() bool {
+	return e.PrintTime > 0 || e.Filament > 0 || e.Weight > 0
+}
+
+// Read scans gcode for PrusaSlicer/SuperSlicer/OrcaSlicer and Cura estimate
+// comments. Files without any give a zero Estimate.
+func Read(r io.Reader) (Estimate, error) {
+	var e Estimate
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, ";") {
+			continue
+		}
+		comment := strings.TrimSpace(strings.TrimPrefix(line, ";"))
+
+		if key, value, ok := strings.Cut(comment, "="); ok {
+			key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+			switch key {
+			case "estimated printing time (normal mode)":
+				e.PrintTime = parseDuration(value)
+			case "filament used [mm]":
+				e.Filament = sum(value) / 1000
+			case "filament used [g]", "total filament used [g]":
+				e.Weight = sum(value)
+			}
+			continue
+		}
+
+		// Cura writes ;TIME:3600 and ;Filament used: 1.23m
+		if key, value, ok := strings.Cut(comment, ":"); ok {
+			value = strings.TrimSpace(value)
+			switch key {
+			case "TIME":
+				if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+					e.PrintTime = int(seconds)
+				}
+			case "Filament used":
+				e.Filament = sum(strings.ReplaceAll(value, "m", ""))
+			}
+		}
+	}
+	return e, scanner.Err()
+}
+
+// sum adds up a comma-separated list of per-extruder numbers
+func sum(value string) float64 {
+	var total float64
+	for _, part := range strings.Split(value, ",") {
+		if n, err := strconv.ParseFloat(strings.TrimSpace(part), 64); err == nil {
+			total += n
+		}
+	}
+	return total
+}
+
+// parseDuration reads slicer durations like "1d 2h 3m 4s"
+func parseDuration(value string) int {
+	units := map[byte]int{'d': 86400, 'h': 3600, 'm': 60, 's': 1}
+	total := 0
+	for _, field := range strings.Fields(value) {
+		if len(field) < 2 {
+			continue
+		}
+		unit, ok := units[field[len(field)-1]]
+		n, err := strconv.Atoi(field[:len(field)-1])
+		if !ok || err != nil {
+			continue
+		}
+		total += n * unit
+	}
+	return total
+}
@@ -0,0 +1,138 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcode
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRead(t *testing.T) {
+	cases := map[string]struct {
+		gcode string
+		want  Estimate
+	}{
+		"prusaslicer": {
+			gcode: "G28\nG1 X10\n; filament used [mm] = 1520.50, 479.50\n; filament used [g] = 4.52\n; estimated printing time (normal mode) = 1h 2m 3s\n",
+			want:  Estimate{PrintTime: 3723, Filament: 2, Weight: 4.52},
+		},
+		"cura": {
+			gcode: ";FLAVOR:Marlin\n;TIME:5400\n;Filament used: 3.25m\n;Layer height: 0.2\nG28\n",
+			want:  Estimate{PrintTime: 5400, Filament: 3.25},
+		},
+		"days": {
+			gcode: "; estimated printing time (normal mode) = 1d 0h 30m 0s\n",
+			want:  Estimate{PrintTime: 88200},
+		},
+		"none": {
+			gcode: "G28\nG1 X10 Y10\n",
+		},
+	}
+
+	for name, tc := range cases {
+		got, err := Read(strings.NewReader(tc.gcode))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if got != tc.want {
+			t.Errorf("%s: got %+v, want %+v", name, got, tc.want)
+		}
+		if got.Found() != (tc.want != Estimate{}) {
+			t.Errorf("%s: Found() = %v", name, got.Found())
+		}
+	}
+}
+
+func TestObjects(t *testing.T) {
+	file := strings.Join([]string{
+		"G28",
+		"M486 T2",
+		`M486 S0 A"Gear id:0 copy 0"`,
+		"G1 X1",
+		"M486 S-1",
+		"M486 S1",
+		"M486 ABracket",
+		"G1 X2",
+		"M486 S0",
+		"G1 X3",
+		"M486 S-1 ; end of layer",
+		"G1 Z10",
+		"",
+	}, "\n")
+
+	objects, err := Objects(strings.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objects) != 2 || objects[0].Name != "Gear id:0 copy 0" || objects[1].Name != "Bracket" {
+		t.Fatalf("objects = %+v", objects)
+	}
+
+	gear := objects[0]
+	if len(gear.Spans) != 2 || gear.Size() != int64(len("G1 X1\n")+len("G1 X3\n")) {
+		t.Errorf("gear spans = %+v", gear.Spans)
+	}
+	if got := gear.Printed(gear.Spans[0].End); got != int64(len("G1 X1\n")) {
+		t.Errorf("printed after first layer = %d", got)
+	}
+	if got := gear.Printed(int64(len(file))); got != gear.Size() {
+		t.Errorf("printed at end = %d, want %d", got, gear.Size())
+	}
+
+	none, err := Objects(strings.NewReader("G28\nG1 X10\n"))
+	if err != nil || len(none) != 0 {
+		t.Errorf("unlabelled file = %+v, %v", none, err)
+	}
+}
+
+func TestKlipperObjects(t *testing.T) {
+	file := strings.Join([]string{
+		"EXCLUDE_OBJECT_DEFINE NAME=gear CENTER=15,15 POLYGON=[[10,10],[20,10],[20,20],[10,20]]",
+		"EXCLUDE_OBJECT_DEFINE NAME=bracket CENTER=50,50",
+		"M83",
+		"EXCLUDE_OBJECT_START NAME=gear",
+		"G1 X12 Y11 F9000",
+		"G1 X12 Y12 E0.5",
+		"EXCLUDE_OBJECT_END NAME=gear",
+		"EXCLUDE_OBJECT_START NAME=bracket",
+		"G1 X40 Y45 F9000",
+		"G1 X60 Y45 E1.2",
+		"G1 X60 Y55 E1.2",
+		"EXCLUDE_OBJECT_END NAME=bracket",
+		"",
+	}, "\n")
+
+	objects, err := Objects(strings.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objects) != 2 || objects[0].Label != "gear" || objects[1].ID != 1 || objects[1].Label != "bracket" {
+		t.Fatalf("objects = %+v", objects)
+	}
+
+	want := Bounds{MinX: 10, MinY: 10, MaxX: 20, MaxY: 20, known: true}
+	if objects[0].Bounds != want {
+		t.Errorf("gear bounds = %+v, want the defined polygon", objects[0].Bounds)
+	}
+	// The travel move in doesn't count, only what was extruded
+	want = Bounds{MinX: 40, MinY: 45, MaxX: 60, MaxY: 55, known: true}
+	if objects[1].Bounds != want {
+		t.Errorf("bracket bounds = %+v, want %+v", objects[1].Bounds, want)
+	}
+}
@@ -0,0 +1,269 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcode
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Object is one labelled object on a plate, with the spans of the file that print it
+type Object struct {
+	ID     int
+	Name   string
+	Label  string // Klipper's EXCLUDE_OBJECT name; empty for M486 objects
+	Spans  []Span
+	Bounds Bounds
+}
+
+// Span is a byte range [Start, End) of a gcode file
+type Span struct {
+	Start int64
+	End   int64
+}
+
+// Bounds is the XY rectangle an object covers, in mm
+type Bounds struct {
+	MinX, MinY float64
+	MaxX, MaxY float64
+	known      bool
+}
+
+// Known reports whether anything was found to bound the object
+func (b Bounds) Known() bool {
+	return b.known
+}
+
+func (b *Bounds) add(x, y float64) {
+	if !b.known {
+		*b = Bounds{MinX: x, MinY: y, MaxX: x, MaxY: y, known: true}
+		return
+	}
+	b.MinX, b.MinY = math.Min(b.MinX, x), math.Min(b.MinY, y)
+	b.MaxX, b.MaxY = math.Max(b.MaxX, x), math.Max(b.MaxY, y)
+}
+
+// Size is how many bytes of the file print the object
+func (o Object) Size() int64 {
+	var n int64
+	for _, s := range o.Spans {
+		n += s.End - s.Start
+	}
+	return n
+}
+
+// Printed is how many of the object's bytes lie before file position pos
+func (o Object) Printed(pos int64) int64 {
+	var n int64
+	for _, s := range o.Spans {
+		n += min(max(pos, s.Start), s.End) - s.Start
+	}
+	return n
+}
+
+// Objects finds the labelled objects on a plate: M486 markers, as
+// PrusaSlicer, SuperSlicer and OrcaSlicer write them for Marlin and Prusa
+// firmware, or Klipper's EXCLUDE_OBJECT_DEFINE/START/END. An object's bounds
+// come from its defined polygon and the extrusions inside it. Files without
+// labels give no objects.
+func Objects(r io.Reader) ([]Object, error) {
+	p := objectParser{byID: make(map[string]int), byLabel: make(map[string]int), current: -1}
+
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadString('\n')
+		p.line(line)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	p.switchTo(-1, 0)
+
+	return p.objects, nil
+}
+
+// objectParser follows a file line by line, tracking which object is printing
+// and where the nozzle is
+type objectParser struct {
+	objects []Object
+	byID    map[string]int
+	byLabel map[string]int
+
+	current int // index into objects, or -1 between objects
+	started int64
+	pos     int64
+
+	x, y      float64
+	e         float64
+	relativeE bool
+}
+
+func (p *objectParser) line(line string) {
+	code := line
+	if i := strings.IndexByte(code, ';'); i >= 0 && !strings.Contains(code[:i], `"`) {
+		code = code[:i]
+	}
+	code = strings.TrimSpace(code)
+	next := p.pos + int64(len(line))
+	command, rest, _ := strings.Cut(code, " ")
+
+	switch strings.ToUpper(command) {
+	case "M486":
+		id, switched, name := parseM486(rest)
+		if switched {
+			i := -1
+			if id >= 0 {
+				i = p.object(p.byID, strconv.Itoa(id), id, "")
+			}
+			p.switchTo(i, next)
+		}
+		if name != "" && p.current >= 0 {
+			p.objects[p.current].Name = name
+		}
+	case "EXCLUDE_OBJECT_DEFINE":
+		params := klipperParams(rest)
+		i := p.object(p.byLabel, params["NAME"], len(p.objects), params["NAME"])
+		var polygon [][]float64
+		if json.Unmarshal([]byte(params["POLYGON"]), &polygon) == nil {
+			for _, point := range polygon {
+				if len(point) == 2 {
+					p.objects[i].Bounds.add(point[0], point[1])
+				}
+			}
+		}
+	case "EXCLUDE_OBJECT_START":
+		name := klipperParams(rest)["NAME"]
+		p.switchTo(p.object(p.byLabel, name, len(p.objects), name), next)
+	case "EXCLUDE_OBJECT_END":
+		p.switchTo(-1, next)
+	case "M82":
+		p.relativeE = false
+	case "M83":
+		p.relativeE = true
+	case "G92":
+		if e, ok := word(rest, 'E'); ok {
+			p.e = e
+		}
+	case "G0", "G1", "G2", "G3":
+		p.move(rest)
+	}
+	p.pos = next
+}
+
+// object returns the index of the object with the given key, adding it if new
+func (p *objectParser) object(index map[string]int, key string, id int, label string) int {
+	if i, ok := index[key]; ok {
+		return i
+	}
+	i := len(p.objects)
+	index[key] = i
+	name := label
+	if name == "" {
+		name = "Object " + strconv.Itoa(id)
+	}
+	p.objects = append(p.objects, Object{ID: id, Name: name, Label: label})
+	return i
+}
+
+// switchTo ends the current object's span and, for i >= 0, starts one for object i at pos
+func (p *objectParser) switchTo(i int, pos int64) {
+	if i == p.current {
+		return
+	}
+	if p.current >= 0 && p.pos > p.started {
+		o := &p.objects[p.current]
+		o.Spans = append(o.Spans, Span{Start: p.started, End: p.pos})
+	}
+	p.current, p.started = i, pos
+}
+
+// move follows a G0-G3 move, growing the current object's bounds when it extrudes
+func (p *objectParser) move(params string) {
+	fromX, fromY := p.x, p.y
+	if x, ok := word(params, 'X'); ok {
+		p.x = x
+	}
+	if y, ok := word(params, 'Y'); ok {
+		p.y = y
+	}
+
+	e, ok := word(params, 'E')
+	extruding := ok && (p.relativeE && e > 0 || !p.relativeE && e > p.e)
+	if ok && !p.relativeE {
+		p.e = e
+	}
+	if extruding && p.current >= 0 {
+		b := &p.objects[p.current].Bounds
+		b.add(fromX, fromY)
+		b.add(p.x, p.y)
+	}
+}
+
+// word returns the value of one letter parameter, like the X in "X10.5 Y3"
+func word(params string, letter byte) (float64, bool) {
+	for _, field := range strings.Fields(params) {
+		if field[0] == letter || field[0] == letter+'a'-'A' {
+			n, err := strconv.ParseFloat(field[1:], 64)
+			return n, err == nil
+		}
+	}
+	return 0, false
+}
+
+// klipperParams splits Klipper's KEY=value parameters
+func klipperParams(params string) map[string]string {
+	values := make(map[string]string)
+	for _, field := range strings.Fields(params) {
+		if key, value, ok := strings.Cut(field, "="); ok {
+			values[strings.ToUpper(key)] = strings.Trim(value, `"`)
+		}
+	}
+	return values
+}
+
+// parseM486 reads M486's parameters: S<id> switches to object id (-1 for moves
+// outside any object) and A<name> names it, on the same line as PrusaSlicer
+// writes them or on a line of its own after the S line as Marlin expects.
+func parseM486(rest string) (id int, switched bool, name string) {
+	for rest = strings.TrimSpace(rest); rest != ""; rest = strings.TrimSpace(rest) {
+		switch rest[0] {
+		case 'A':
+			// The name runs to the end of the line, optionally quoted
+			return id, switched, strings.Trim(strings.TrimSpace(rest[1:]), `"`)
+		case 'S':
+			field, tail, _ := strings.Cut(rest[1:], " ")
+			n, err := strconv.Atoi(field)
+			if err != nil {
+				return 0, false, ""
+			}
+			id, switched, rest = n, true, tail
+		default:
+			_, rest, _ = strings.Cut(rest, " ")
+		}
+	}
+	return id, switched, ""
+}
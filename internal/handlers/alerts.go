@@ -0,0 +1,80 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"sort"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/store"
+)
+
+const (
+	alertsBucket = "alerts"
+	// maxAlerts bounds the alert log kept for handover reports
+	maxAlerts = 1000
+)
+
+// recordAlert keeps a raised alert for handover reports, logging rather than
+// failing on errors
+func (h *Handler) recordAlert(printerID string, data interface{}) {
+	id, err := h.store.NextID(alertsBucket)
+	if err != nil {
+		h.logger.Printf("Failed to number alert: %v", err)
+		return
+	}
+	fields, _ := data.(map[string]interface{})
+	kind, _ := fields["kind"].(string)
+	severity, _ := fields["severity"].(string)
+	message, _ := h.announcementText(models.EventAlertRaised, printerID, data)
+
+	alert := models.Alert{
+		ID:        id,
+		Time:      h.clock.Now().UTC().Truncate(time.Second),
+		PrinterID: printerID,
+		Kind:      kind,
+		Severity:  severity,
+		Message:   message,
+	}
+	if err := h.store.Put(alertsBucket, id, alert); err != nil {
+		h.logger.Printf("Failed to record alert: %v", err)
+		return
+	}
+	if err := h.store.Trim(alertsBucket, maxAlerts); err != nil {
+		h.logger.Printf("Failed to trim alerts: %v", err)
+	}
+}
+
+// alertsSince returns alerts raised after since, oldest first
+func (h *Handler) alertsSince(since time.Time) ([]models.Alert, error) {
+	all, err := store.List[models.Alert](h.store, alertsBucket)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(all, func(i, j int) bool { return all[i].ID < all[j].ID })
+
+	alerts := []models.Alert{}
+	for _, a := range all {
+		if a.Time.After(since) {
+			alerts = append(alerts, a)
+		}
+	}
+	return alerts, nil
+}
@@ -0,0 +1,253 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/thermal"
+	"github.com/wmarchesi123/octodash/internal/voice"
+)
+
+const (
+	// announcementsBucket numbers announcements so displays can resume after reconnecting
+	announcementsBucket = "announcements"
+	// streamHeartbeat keeps idle announcement streams from being closed by proxies
+	streamHeartbeat = 25 * time.Second
+	// maxAnnouncement caps the length of an announcement made through the API
+	maxAnnouncement = 200
+)
+
+// announceEvent reads out a domain event on kiosk displays and speakers when
+// ANNOUNCE_EVENTS includes its type
+func (h *Handler) announceEvent(eventType, printerID string, data interface{}) {
+	if !slices.Contains(h.settings.Announce.Events, eventType) {
+		return
+	}
+	if text, level := h.announcementText(eventType, printerID, data); text != "" {
+		h.announce(eventType, printerID, level, text)
+	}
+}
+
+// announce numbers an announcement and sends it to displays and the speech service
+func (h *Handler) announce(kind, printerID, level, text string) (models.Announcement, error) {
+	id, err := h.store.NextID(announcementsBucket)
+	if err != nil {
+		h.logger.Printf("Failed to number announcement: %v", err)
+		return models.Announcement{}, err
+	}
+	a := models.Announcement{
+		ID:        id,
+		Time:      h.clock.Now().UTC().Truncate(time.Second),
+		Type:      kind,
+		PrinterID: printerID,
+		Level:     level,
+		Text:      text,
+	}
+	h.announcer.Publish(a)
+	if h.speaker != nil {
+		h.speaker.Say(a)
+	}
+	return a, nil
+}
+
+// announcementText phrases an event to be read aloud, naming the printer and
+// the part without its file extension
+func (h *Handler) announcementText(eventType, printerID string, data interface{}) (text, level string) {
+	name := printerID
+	if printer, ok := h.findPrinter(printerID); ok {
+		name = printer.Name
+	}
+	fields, _ := data.(map[string]interface{})
+	file, _ := fields["file"].(string)
+	part := spokenFile(file)
+
+	switch eventType {
+	case models.EventJobFinished:
+		switch fields["result"] {
+		case "completed":
+			return fmt.Sprintf("%s finished %s", name, part), models.AnnounceInfo
+		case "cancelled":
+			return fmt.Sprintf("%s cancelled %s", name, part), models.AnnounceInfo
+		}
+		return fmt.Sprintf("%s failed printing %s", name, part), models.AnnounceAlert
+	case models.EventJobStarted:
+		return fmt.Sprintf("%s started %s", name, part), models.AnnounceInfo
+	case models.EventAlertRaised:
+		switch fields["kind"] {
+		case "interrupted":
+			if ip, ok := fields["interrupted"].(models.InterruptedPrint); ok {
+				part = spokenFile(ip.File)
+			}
+			return fmt.Sprintf("%s was interrupted printing %s", name, part), models.AnnounceAlert
+		case "rule":
+			return fmt.Sprintf("%s: %s", name, fields["name"]), models.AnnounceAlert
+		case "first_layer":
+			return fmt.Sprintf("%s has been on the first layer of %s for %v minutes", name, part, fields["minutes"]), models.AnnounceAlert
+		case "stall":
+			return fmt.Sprintf("%s has stopped making progress on %s", name, part), models.AnnounceAlert
+		case "thermal":
+			if f, ok := fields["fault"].(thermal.Fault); ok {
+				return fmt.Sprintf("%s heater fault: %s", name, f.Message), models.AnnounceAlert
+			}
+		}
+		if msg, _ := fields["error"].(string); msg != "" {
+			return fmt.Sprintf("%s needs attention: %s", name, msg), models.AnnounceAlert
+		}
+		return fmt.Sprintf("%s needs attention", name), models.AnnounceAlert
+	case models.EventQueueDispatched:
+		if entry, ok := data.(models.QueueEntry); ok {
+			return fmt.Sprintf("%s started %s from the queue", name, spokenFile(entry.File)), models.AnnounceInfo
+		}
+	case models.EventPrinterStatus:
+		if e, ok := data.(models.StatusEvent); ok {
+			return fmt.Sprintf("%s is now %s", name, e.To), models.AnnounceInfo
+		}
+	}
+	return "", ""
+}
+
+// spokenFile names a print for reading aloud, falling back when the file is unknown
+func spokenFile(file string) string {
+	if file == "" {
+		return "its print"
+	}
+	return voice.File(file)
+}
+
+// visibleAnnouncements drops announcements about printers outside the caller's teams
+func (h *Handler) visibleAnnouncements(r *http.Request, list []models.Announcement) []models.Announcement {
+	t := h.tenancy(r)
+	return slices.DeleteFunc(list, func(a models.Announcement) bool {
+		return a.PrinterID != "" && !t.printer(a.PrinterID)
+	})
+}
+
+// handleAnnouncements returns recent announcements after ?since=, oldest
+// first, for displays that poll rather than stream
+func (h *Handler) handleAnnouncements(w http.ResponseWriter, r *http.Request) {
+	list := h.visibleAnnouncements(r, h.announcer.Recent(r.URL.Query().Get("since")))
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":        "ok",
+		"announcements": list,
+	})
+}
+
+// handleAnnouncementStream streams announcements as server-sent events. A
+// display reconnecting with Last-Event-ID, or ?since=, first gets the ones it
+// missed.
+func (h *Handler) handleAnnouncementStream(w http.ResponseWriter, r *http.Request) {
+	// The stream outlives the server's write timeout
+	rc := http.NewResponseController(w)
+	rc.SetWriteDeadline(time.Time{})
+
+	since := r.Header.Get("Last-Event-ID")
+	if since == "" {
+		since = r.URL.Query().Get("since")
+	}
+	missed, ch, cancel := h.announcer.Subscribe(since)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	send := func(a models.Announcement) error {
+		if len(h.visibleAnnouncements(r, []models.Announcement{a})) == 0 {
+			return nil
+		}
+		payload, err := json.Marshal(a)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "id: %s\nevent: announcement\ndata: %s\n\n", a.ID, payload); err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+
+	for _, a := range missed {
+		if send(a) != nil {
+			return
+		}
+	}
+	fmt.Fprint(w, ": connected\n\n")
+	rc.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case a := <-ch:
+			if send(a) != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil || rc.Flush() != nil {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		case <-h.streams.Done():
+			return
+		}
+	}
+}
+
+// handleAnnounce makes an announcement by hand, such as a shop-wide message
+func (h *Handler) handleAnnounce(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Text  string `json:"text"`
+		Level string `json:"level"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	req.Text = strings.TrimSpace(req.Text)
+	if req.Text == "" || len(req.Text) > maxAnnouncement {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Text must be 1 to %d characters", maxAnnouncement))
+		return
+	}
+	if req.Level == "" {
+		req.Level = models.AnnounceInfo
+	}
+	if req.Level != models.AnnounceInfo && req.Level != models.AnnounceAlert {
+		writeError(w, http.StatusBadRequest, "Level must be info or alert")
+		return
+	}
+
+	a, err := h.announce(models.AnnounceManual, "", req.Level, req.Text)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.audit(r, "announce", "", req.Text)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":       "ok",
+		"announcement": a,
+	})
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestAnnouncements(t *testing.T) {
+	spoken := make(chan string, 10)
+	tts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		spoken <- r.URL.Path
+	}))
+	defer tts.Close()
+	t.Setenv("ANNOUNCE_TTS_URL", tts.URL+"/sayall/{text}/en-us")
+
+	op := testutil.NewOctoPrint(t)
+	op.SetPrinting("gear_v2.gcode", 98, 60)
+	h := newTestHandler(t, testutil.NewSpoolman(t), testutil.Printer{Name: "Mini", Server: op})
+
+	getStatus(t, h)
+	op.SetFinished("gear_v2.gcode")
+	getStatus(t, h)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/api/announcements", nil))
+	var response struct {
+		Announcements []models.Announcement `json:"announcements"`
+	}
+	json.NewDecoder(rec.Body).Decode(&response)
+	if len(response.Announcements) != 1 || response.Announcements[0].Text != "Mini finished gear v2" {
+		t.Fatalf("announcements = %+v, want Mini finishing gear v2", response.Announcements)
+	}
+	first := response.Announcements[0]
+
+	select {
+	case path := <-spoken:
+		if path != "/sayall/Mini finished gear v2/en-us" {
+			t.Errorf("speech service got %q", path)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("announcement never reached the speech service")
+	}
+
+	// Manual announcements need text and a known level
+	if rec := postJSON(h, "/api/announcements", `{"text": "  "}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("empty announcement = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if rec := postJSON(h, "/api/announcements", `{"text": "Lunch", "level": "loud"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown level = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if rec := postJSON(h, "/api/announcements", `{"text": "Shop closes in ten minutes"}`); rec.Code != http.StatusOK {
+		t.Fatalf("POST /api/announcements = %d: %s", rec.Code, rec.Body)
+	}
+
+	// A display reconnecting after the first announcement catches up on the rest
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", srv.URL+"/api/announcements/stream", nil)
+	req.Header.Set("Last-Event-ID", first.ID)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("opening stream: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("stream Content-Type = %q", ct)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		var a models.Announcement
+		json.Unmarshal([]byte(data), &a)
+		if a.Text != "Shop closes in ten minutes" || a.Type != models.AnnounceManual {
+			t.Errorf("streamed %+v, want the manual announcement", a)
+		}
+		return
+	}
+	t.Fatalf("stream ended without an announcement: %v", scanner.Err())
+}
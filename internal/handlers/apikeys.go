@@ -0,0 +1,174 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/wmarchesi123/go-3dprint-client/config"
+	"github.com/wmarchesi123/go-3dprint-client/octoprint"
+	"github.com/wmarchesi123/octodash/internal/auth"
+	"github.com/wmarchesi123/octodash/internal/octoapi"
+)
+
+const apiKeysBucket = "api_keys"
+
+// clientSet holds the OctoPrint clients for every printer. It is never
+// modified once published; rotating a key swaps in a new set.
+type clientSet struct {
+	status  map[string]PrinterClient
+	control map[string]ControlClient
+	keys    map[string]string // current API key per printer, for the proxy
+}
+
+// apiKeyRecord is a key set through the API, which takes precedence over PRINTER_N_KEY
+type apiKeyRecord struct {
+	PrinterID string `json:"printer_id"`
+	APIKey    string `json:"api_key"`
+}
+
+// printerClient returns the status client for a printer, or nil if it has none
+func (h *Handler) printerClient(id string) PrinterClient {
+	return h.clients.Load().status[id]
+}
+
+// controlClient returns the control client for a printer, or nil if it has none
+func (h *Handler) controlClient(id string) ControlClient {
+	return h.clients.Load().control[id]
+}
+
+func (h *Handler) newControlClient(printer config.Printer) *octoapi.Client {
+	client := octoapi.NewClient(printer.OctoPrintURL, printer.APIKey)
+	if timeout, ok := h.settings.Upstream.PrinterTimeouts[printer.ID]; ok {
+		client.SetTimeout(timeout)
+	}
+	return client
+}
+
+// handleRotateAPIKey replaces a printer's OctoPrint API key without a restart.
+// The new key must be accepted by /api/version before any client switches to it.
+func (h *Handler) handleRotateAPIKey(w http.ResponseWriter, r *http.Request) {
+	printer, ok := h.findPrinter(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Printer not found")
+		return
+	}
+
+	var req struct {
+		APIKey string `json:"api_key"`
+	}
+	if err := json.NewDecoder(io.LimitReader(r.Body, 4096)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	printer.APIKey = strings.TrimSpace(req.APIKey)
+	if printer.APIKey == "" {
+		writeError(w, http.StatusBadRequest, "api_key is required")
+		return
+	}
+
+	control := h.newControlClient(printer)
+	version, err := control.Version()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "OctoPrint rejected the key: "+err.Error())
+		return
+	}
+
+	if err := h.store.Put(apiKeysBucket, printer.ID, apiKeyRecord{PrinterID: printer.ID, APIKey: printer.APIKey}); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.rotateMu.Lock()
+	current := h.clients.Load()
+	next := &clientSet{
+		status:  make(map[string]PrinterClient, len(current.status)),
+		control: make(map[string]ControlClient, len(current.control)),
+		keys:    make(map[string]string, len(current.keys)),
+	}
+	for id, c := range current.status {
+		next.status[id] = c
+	}
+	for id, c := range current.control {
+		next.control[id] = c
+	}
+	for id, k := range current.keys {
+		next.keys[id] = k
+	}
+	next.status[printer.ID] = octoprint.NewClient(printer.OctoPrintURL, printer.APIKey)
+	next.control[printer.ID] = control
+	next.keys[printer.ID] = printer.APIKey
+	h.clients.Store(next)
+	h.rotateMu.Unlock()
+
+	h.logger.Printf("API key for %s rotated by %s", printer.Name, auth.UserFromContext(r.Context()).Name)
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":            "ok",
+		"printer_id":        printer.ID,
+		"octoprint_version": version.Server,
+	})
+}
+
+// liveControl looks up a printer's control client on every call, so
+// long-running workers pick up rotated keys
+type liveControl struct {
+	h  *Handler
+	id string
+}
+
+func (c liveControl) CreateBackup() (string, error) {
+	return c.h.controlClient(c.id).CreateBackup()
+}
+
+func (c liveControl) BackupState() (octoapi.BackupState, error) {
+	return c.h.controlClient(c.id).BackupState()
+}
+
+func (c liveControl) DownloadBackup(ctx context.Context, name string, w io.Writer) (int64, error) {
+	return c.h.controlClient(c.id).DownloadBackup(ctx, name, w)
+}
+
+func (c liveControl) DeleteBackup(name string) error {
+	return c.h.controlClient(c.id).DeleteBackup(name)
+}
+
+func (c liveControl) Timelapses() ([]octoapi.Timelapse, error) {
+	return c.h.controlClient(c.id).Timelapses()
+}
+
+func (c liveControl) OpenTimelapse(ctx context.Context, name string) (io.ReadCloser, int64, error) {
+	return c.h.controlClient(c.id).OpenTimelapse(ctx, name)
+}
+
+func (c liveControl) DeleteTimelapse(name string) error {
+	return c.h.controlClient(c.id).DeleteTimelapse(name)
+}
+
+func (c liveControl) SoftwareUpdates() ([]octoapi.SoftwareUpdate, error) {
+	return c.h.controlClient(c.id).SoftwareUpdates()
+}
+
+func (c liveControl) FirmwareVersion() (string, error) {
+	return c.h.controlClient(c.id).FirmwareVersion()
+}
@@ -0,0 +1,89 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/wmarchesi123/octodash/internal/settings"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestRotateAPIKey(t *testing.T) {
+	sm := testutil.NewSpoolman(t)
+	op := testutil.NewOctoPrint(t)
+	op.SetAPIKey("rotated-key")
+	cfg := testutil.Config(sm, testutil.Printer{Name: "Mini", Server: op})
+
+	s, err := settings.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.DataDir = t.TempDir()
+	h, err := NewHandler(cfg, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The configured key has been revoked
+	if p := getStatus(t, h)["printer-1"]; p.Status != "offline" {
+		t.Fatalf("status with revoked key = %s, want offline", p.Status)
+	}
+
+	rotate := func(key string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		body := strings.NewReader(`{"api_key": "` + key + `"}`)
+		h.ServeHTTP(rec, httptest.NewRequest("PUT", "/api/admin/printers/printer-1/api-key", body))
+		return rec
+	}
+
+	if rec := rotate("wrong-key"); rec.Code != http.StatusBadRequest {
+		t.Errorf("rotating to a rejected key = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+
+	rec := rotate("rotated-key")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("rotating key = %d: %s", rec.Code, rec.Body)
+	}
+	var response struct {
+		Version string `json:"octoprint_version"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil || response.Version != "1.10.2" {
+		t.Errorf("rotation response version = %q (%v)", response.Version, err)
+	}
+
+	if p := getStatus(t, h)["printer-1"]; p.Status != "idle" {
+		t.Errorf("status after rotation = %s (%s), want idle", p.Status, p.Error)
+	}
+
+	// The rotated key outlives a restart
+	h.Close()
+	h, err = NewHandler(cfg, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+	if p := getStatus(t, h)["printer-1"]; p.Status != "idle" {
+		t.Errorf("status after restart = %s (%s), want idle", p.Status, p.Error)
+	}
+}
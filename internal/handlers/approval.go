@@ -0,0 +1,190 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/wmarchesi123/go-3dprint-client/config"
+	"github.com/wmarchesi123/octodash/internal/auth"
+	"github.com/wmarchesi123/octodash/internal/gcode"
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/octoapi"
+	"github.com/wmarchesi123/octodash/internal/queue"
+)
+
+// queueSubmitRole is the least role that may add to the queue; with
+// QUEUE_APPROVAL viewers can too, but their jobs wait for an operator
+func (h *Handler) queueSubmitRole() auth.Role {
+	if h.settings.Queue.Approval {
+		return auth.RoleViewer
+	}
+	return auth.RoleOperator
+}
+
+// approvalFor returns the approval state a new entry from this caller starts in
+func (h *Handler) approvalFor(r *http.Request) string {
+	if h.settings.Queue.Approval && auth.UserFromContext(r.Context()).Role < auth.RoleOperator {
+		return models.ApprovalPending
+	}
+	return ""
+}
+
+// handleQueuePending lists entries awaiting approval with their previews and estimates
+func (h *Handler) handleQueuePending(w http.ResponseWriter, r *http.Request) {
+	entries, err := h.queue.List()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	scope := h.tenancy(r)
+	files := make(map[string][]octoapi.File)
+	pending := []models.QueueEntry{}
+	for _, e := range entries {
+		if e.Approval == models.ApprovalPending && scope.team(e.Team) {
+			pending = append(pending, h.withEstimate(r, e, files))
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "ok",
+		"queue":  pending,
+	})
+}
+
+// withEstimate fills in OctoPrint's analysis and thumbnail for entries naming a
+// file already on a printer. Sliced entries carry the slicer's own estimate.
+// files caches each printer's listing across calls.
+func (h *Handler) withEstimate(r *http.Request, e models.QueueEntry, files map[string][]octoapi.File) models.QueueEntry {
+	if e.Estimate != nil || e.Gcode != "" || e.Model != "" {
+		return e
+	}
+
+	printers := h.printersMatching(r, "")
+	if e.PrinterID != "" {
+		printer, _ := h.findPrinter(e.PrinterID)
+		printers = []config.Printer{printer}
+	}
+	for _, p := range printers {
+		listing, ok := files[p.ID]
+		if !ok {
+			var err error
+			if listing, err = h.controlClient(p.ID).ListFiles(); err != nil {
+				h.logger.Printf("Failed to list files on %s: %v", p.Name, err)
+			}
+			files[p.ID] = listing
+		}
+		for _, f := range listing {
+			if f.Path != e.File && f.Name != e.File {
+				continue
+			}
+			if f.Analysis != nil {
+				estimate := &models.JobEstimate{PrintTime: int(f.Analysis.EstimatedPrintTime)}
+				for _, tool := range f.Analysis.Filament {
+					estimate.Filament += tool.Length / 1000
+				}
+				e.Estimate = estimate
+			}
+			if e.PreviewURL == "" {
+				e.PreviewURL = octoapi.ThumbnailURL(p.OctoPrintURL, f.Path)
+			}
+			return e
+		}
+	}
+	return e
+}
+
+// gcodeEstimate reads the slicer's estimate from gcode OctoDash sliced
+func (h *Handler) gcodeEstimate(name string) *models.JobEstimate {
+	f, err := os.Open(filepath.Join(h.settings.Queue.ModelDir, name))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	estimate, err := gcode.Read(f)
+	if err != nil || !estimate.Found() {
+		return nil
+	}
+	return &models.JobEstimate{PrintTime: estimate.PrintTime, Filament: estimate.Filament, Weight: estimate.Weight}
+}
+
+// handleQueueApprove lets a pending entry be dispatched
+func (h *Handler) handleQueueApprove(w http.ResponseWriter, r *http.Request) {
+	h.queueMu.Lock()
+	defer h.queueMu.Unlock()
+
+	entry, ok := h.pendingEntry(w, r)
+	if !ok {
+		return
+	}
+	entry.Approval = models.ApprovalApproved
+	entry.ApprovedBy = auth.UserFromContext(r.Context()).Name
+	if err := h.queue.Update(entry); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.logger.Printf("%s approved %s from %s", entry.ApprovedBy, entry.File, entry.SubmittedBy)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "ok",
+		"entry":  entry,
+	})
+}
+
+// handleQueueReject drops a pending entry
+func (h *Handler) handleQueueReject(w http.ResponseWriter, r *http.Request) {
+	h.queueMu.Lock()
+	defer h.queueMu.Unlock()
+
+	entry, ok := h.pendingEntry(w, r)
+	if !ok {
+		return
+	}
+	if err := h.queue.Remove(entry.ID); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.removeModel(entry)
+
+	h.logger.Printf("%s rejected %s from %s", auth.UserFromContext(r.Context()).Name, entry.File, entry.SubmittedBy)
+	writeJSON(w, http.StatusOK, map[string]interface{}{"status": "ok"})
+}
+
+// pendingEntry loads the entry in the path, answering for it unless it awaits approval
+func (h *Handler) pendingEntry(w http.ResponseWriter, r *http.Request) (models.QueueEntry, bool) {
+	entry, err := h.queueEntry(r, r.PathValue("id"))
+	if errors.Is(err, queue.ErrNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return entry, false
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return entry, false
+	}
+	if entry.Approval != models.ApprovalPending {
+		writeError(w, http.StatusConflict, "Entry is not awaiting approval")
+		return entry, false
+	}
+	return entry, true
+}
@@ -0,0 +1,106 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestQueueApproval(t *testing.T) {
+	t.Setenv("QUEUE_APPROVAL", "true")
+	t.Setenv("AUTH_USERS", "student:viewer:student-token,tech:operator:tech-token")
+	op := testutil.NewOctoPrint(t)
+	h := slicerHandler(t, "cp {profile} {output}", op)
+
+	// The "slicer" copies the profile, so its comments stand in for a slicer's estimate
+	estimate := "; filament used [g] = 12.5\n; estimated printing time (normal mode) = 1h 0m 0s\n"
+	if err := os.WriteFile(filepath.Join(h.settings.Slicer.ProfileDir, "est.ini"), []byte(estimate), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var response struct {
+		Entry models.QueueEntry   `json:"entry"`
+		Queue []models.QueueEntry `json:"queue"`
+	}
+
+	rec := doAs(h, "student-token", "POST", "/api/queue/models?file=bracket.stl&profile=est", tetrahedronSTL)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("viewer upload = %d: %s", rec.Code, rec.Body)
+	}
+	json.NewDecoder(rec.Body).Decode(&response)
+	if response.Entry.Approval != models.ApprovalPending {
+		t.Errorf("viewer entry approval = %q, want pending", response.Entry.Approval)
+	}
+	entry := waitSliced(t, h, response.Entry.ID)
+	if entry.Estimate == nil || entry.Estimate.PrintTime != 3600 || entry.Estimate.Weight != 12.5 {
+		t.Errorf("estimate = %+v, want 1h and 12.5 g", entry.Estimate)
+	}
+
+	h.dispatchOnce()
+	if printed := op.Printed(); len(printed) != 0 {
+		t.Fatalf("printed %v before approval", printed)
+	}
+
+	if rec := doAs(h, "student-token", "GET", "/api/queue/pending", ""); rec.Code != http.StatusForbidden {
+		t.Errorf("viewer listing pending = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+	if rec := doAs(h, "student-token", "POST", "/api/queue/"+entry.ID+"/approve", ""); rec.Code != http.StatusForbidden {
+		t.Errorf("viewer approving = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+	rec = doAs(h, "tech-token", "GET", "/api/queue/pending", "")
+	json.NewDecoder(rec.Body).Decode(&response)
+	if len(response.Queue) != 1 || response.Queue[0].PreviewURL == "" {
+		t.Errorf("pending = %+v, want the bracket with a preview", response.Queue)
+	}
+
+	if rec := doAs(h, "tech-token", "POST", "/api/queue/"+entry.ID+"/approve", ""); rec.Code != http.StatusOK {
+		t.Fatalf("approve = %d: %s", rec.Code, rec.Body)
+	}
+	if rec := doAs(h, "tech-token", "POST", "/api/queue/"+entry.ID+"/approve", ""); rec.Code != http.StatusConflict {
+		t.Errorf("second approve = %d, want %d", rec.Code, http.StatusConflict)
+	}
+	h.dispatchOnce()
+	if printed := op.Printed(); !slices.Equal(printed, []string{"bracket.gcode"}) {
+		t.Errorf("printed = %v after approval", printed)
+	}
+
+	// Operators skip approval; rejected entries leave the queue
+	rec = doAs(h, "tech-token", "POST", "/api/queue", `{"file": "jig.gcode"}`)
+	response.Entry = models.QueueEntry{}
+	json.NewDecoder(rec.Body).Decode(&response)
+	if response.Entry.Approval != "" {
+		t.Errorf("operator entry approval = %q, want none", response.Entry.Approval)
+	}
+	rec = doAs(h, "student-token", "POST", "/api/queue", `{"file": "keychain.gcode"}`)
+	json.NewDecoder(rec.Body).Decode(&response)
+	if rec := doAs(h, "tech-token", "POST", "/api/queue/"+response.Entry.ID+"/reject", ""); rec.Code != http.StatusOK {
+		t.Errorf("reject = %d", rec.Code)
+	}
+	if _, err := h.queue.Get(response.Entry.ID); err == nil {
+		t.Error("rejected entry is still queued")
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/backup"
+	"github.com/wmarchesi123/octodash/internal/s3"
+	"github.com/wmarchesi123/octodash/internal/settings"
+	"github.com/wmarchesi123/octodash/internal/store"
+)
+
+// maxRestoreSize bounds uploaded backup archives
+const maxRestoreSize = 512 << 20
+
+func (h *Handler) backupSource() backup.Source {
+	return backup.Source{
+		Store:   h.store,
+		DataDir: h.settings.DataDir,
+		Environ: settings.Environ(),
+		// Tailscale node state is tied to this machine; a restored copy would clash with it
+		Exclude: []string{h.settings.Backup.Dir, h.settings.OctoPrintBackup.Dir, h.settings.Tailscale.StateDir},
+	}
+}
+
+// startBackups runs scheduled backups when BACKUP_INTERVAL is set
+func (h *Handler) startBackups(ctx context.Context) {
+	b := h.settings.Backup
+	if b.Interval <= 0 {
+		return
+	}
+
+	targets := []backup.Target{&backup.LocalTarget{Dir: b.Dir, Keep: b.Keep}}
+	if b.S3.Bucket != "" {
+		targets = append(targets, &backup.S3Target{Client: s3Client(b.S3)})
+	}
+
+	go backup.NewScheduler(h.backupSource(), targets, b.Interval, h.logger).Run(ctx)
+}
+
+// s3Client connects to the bucket described by BACKUP_S3_ or CAMERA_ARCHIVE_S3_ settings
+func s3Client(s settings.S3Settings) s3.Client {
+	return s3.Client{
+		Endpoint:  s.Endpoint,
+		Region:    s.Region,
+		Bucket:    s.Bucket,
+		Prefix:    s.Prefix,
+		AccessKey: s.AccessKey,
+		SecretKey: s.SecretKey,
+	}
+}
+
+func (h *Handler) handleBackup(w http.ResponseWriter, r *http.Request) {
+	now := h.clock.Now()
+
+	// A large data directory takes longer than the server's write timeout
+	http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", backup.FileName(now)))
+
+	if err := backup.Write(w, h.backupSource(), now); err != nil {
+		// Headers are gone by now; abort so the client sees a truncated download
+		h.logger.Printf("Backup failed: %v", err)
+		panic(http.ErrAbortHandler)
+	}
+}
+
+func (h *Handler) handleRestore(w http.ResponseWriter, r *http.Request) {
+	body := http.MaxBytesReader(w, r.Body, maxRestoreSize)
+
+	manifest, err := backup.Restore(body, h.backupSource())
+	if errors.Is(err, store.ErrUnavailable) {
+		h.logger.Printf("Restore failed, restart OctoDash: %v", err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.logger.Printf("Restored backup from %s (version %s)", manifest.CreatedAt.Format("2006-01-02 15:04:05"), manifest.Version)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":   "ok",
+		"manifest": manifest,
+	})
+}
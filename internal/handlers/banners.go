@@ -0,0 +1,106 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/auth"
+	"github.com/wmarchesi123/octodash/internal/banners"
+	"github.com/wmarchesi123/octodash/internal/models"
+)
+
+// activeBanners lists the banners every status response carries, logging
+// rather than failing the poll when they can't be read
+func (h *Handler) activeBanners() []models.Banner {
+	list, err := h.banners.Active(h.clock.Now())
+	if err != nil {
+		h.logger.Printf("Failed to load banners: %v", err)
+	}
+	return list
+}
+
+// handleBannerList returns the banners showing now
+func (h *Handler) handleBannerList(w http.ResponseWriter, r *http.Request) {
+	list, err := h.banners.Active(h.clock.Now())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "ok",
+		"banners": list,
+	})
+}
+
+// handleBannerPost puts up a banner on every dashboard until expires_at, or
+// until removed when it has none
+func (h *Handler) handleBannerPost(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Message   string     `json:"message"`
+		Severity  string     `json:"severity"`
+		ExpiresAt *time.Time `json:"expires_at"`
+	}
+	if err := json.NewDecoder(io.LimitReader(r.Body, 4096)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	banner, err := h.banners.Post(models.Banner{
+		Message:   req.Message,
+		Severity:  req.Severity,
+		CreatedBy: auth.UserFromContext(r.Context()).Name,
+		ExpiresAt: req.ExpiresAt,
+	}, h.clock.Now())
+	switch {
+	case errors.Is(err, banners.ErrInvalid):
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.audit(r, "banner-post", "", banner.Message)
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"status": "ok",
+		"banner": banner,
+	})
+}
+
+// handleBannerRemove takes a banner down before it expires
+func (h *Handler) handleBannerRemove(w http.ResponseWriter, r *http.Request) {
+	err := h.banners.Remove(r.PathValue("id"))
+	switch {
+	case errors.Is(err, banners.ErrNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.audit(r, "banner-remove", "", r.PathValue("id"))
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"status": "ok"})
+}
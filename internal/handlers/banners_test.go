@@ -0,0 +1,93 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestBanners(t *testing.T) {
+	t.Setenv("AUTH_USERS", "olga:operator:olga-token,ada:admin:ada-token")
+	clock := &stepClock{now: time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)}
+	sm := testutil.NewSpoolman(t)
+	h := newHandlerWithConfig(t, testutil.Config(sm, testutil.Printer{Name: "Mini", Server: testutil.NewOctoPrint(t)}), WithClock(clock))
+
+	statusBanners := func() []models.Banner {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", "/api/status", nil))
+		var resp models.StatusResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp.Banners
+	}
+
+	if rec := doAs(h, "olga-token", "POST", "/api/admin/banners", `{"message": "Resin room closed Friday"}`); rec.Code != http.StatusForbidden {
+		t.Errorf("operator post = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+	for _, body := range []string{`{"message": " "}`, `{"message": "x", "severity": "urgent"}`, `{"message": "x", "expires_at": "2025-03-01T08:00:00Z"}`} {
+		if rec := doAs(h, "ada-token", "POST", "/api/admin/banners", body); rec.Code != http.StatusBadRequest {
+			t.Errorf("post %s = %d, want %d", body, rec.Code, http.StatusBadRequest)
+		}
+	}
+
+	if rec := doAs(h, "ada-token", "POST", "/api/admin/banners", `{"message": "PETG restock arriving Tuesday"}`); rec.Code != http.StatusCreated {
+		t.Fatalf("post = %d: %s", rec.Code, rec.Body)
+	}
+	rec := doAs(h, "ada-token", "POST", "/api/admin/banners", `{"message": "Resin room closed", "severity": "warning", "expires_at": "2025-03-01T17:00:00Z"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("post = %d: %s", rec.Code, rec.Body)
+	}
+	var posted struct {
+		Banner models.Banner `json:"banner"`
+	}
+	json.NewDecoder(rec.Body).Decode(&posted)
+	if posted.Banner.CreatedBy != "ada" {
+		t.Errorf("created by %q, want ada", posted.Banner.CreatedBy)
+	}
+
+	// Every dashboard's status poll carries the banners, most severe first
+	got := statusBanners()
+	if len(got) != 2 || got[0].Message != "Resin room closed" || got[1].Severity != models.BannerInfo {
+		t.Fatalf("banners = %+v, want the warning then the info banner", got)
+	}
+
+	clock.set(time.Date(2025, 3, 1, 17, 0, 0, 0, time.UTC))
+	got = statusBanners()
+	if len(got) != 1 || got[0].Message != "PETG restock arriving Tuesday" {
+		t.Fatalf("banners after expiry = %+v", got)
+	}
+
+	if rec := doAs(h, "ada-token", "DELETE", "/api/admin/banners/"+got[0].ID, ""); rec.Code != http.StatusOK {
+		t.Fatalf("remove = %d", rec.Code)
+	}
+	if rec := doAs(h, "ada-token", "DELETE", "/api/admin/banners/"+got[0].ID, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("removing twice = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	if got := statusBanners(); len(got) != 0 {
+		t.Errorf("banners after removal = %+v", got)
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/wmarchesi123/go-3dprint-client/config"
+	"github.com/wmarchesi123/octodash/internal/auth"
+	"github.com/wmarchesi123/octodash/internal/models"
+)
+
+// bulkRequest is the body accepted by the bulk action endpoints
+type bulkRequest struct {
+	Group  string  `json:"group"`
+	Tags   string  `json:"tags"`
+	Hotend float64 `json:"hotend"`
+	Bed    float64 `json:"bed"`
+}
+
+// bulkAction describes a batch operation: which printers it applies to and what it does
+type bulkAction struct {
+	// eligible returns an empty string if the printer should be acted on, or the reason it is skipped
+	eligible func(h *Handler, p config.Printer) string
+	run      func(status PrinterClient, control ControlClient, req bulkRequest) error
+}
+
+var bulkActions = map[string]bulkAction{
+	"preheat": {
+		eligible: requireStatus("idle"),
+		run: func(status PrinterClient, control ControlClient, req bulkRequest) error {
+			if err := status.SetToolTemperature(0, req.Hotend); err != nil {
+				return err
+			}
+			return status.SetBedTemperature(req.Bed)
+		},
+	},
+	"cooldown": {
+		eligible: func(h *Handler, p config.Printer) string {
+			if status := h.currentStatus(p); status == "offline" {
+				return "printer is offline"
+			}
+			return ""
+		},
+		run: func(status PrinterClient, control ControlClient, req bulkRequest) error {
+			if err := status.SetToolTemperature(0, 0); err != nil {
+				return err
+			}
+			return status.SetBedTemperature(0)
+		},
+	},
+	"connect": {
+		eligible: func(h *Handler, p config.Printer) string {
+			state, err := h.controlClient(p.ID).ConnectionState()
+			if err != nil {
+				return fmt.Sprintf("OctoPrint unreachable: %v", err)
+			}
+			if state != "Closed" && state != "Offline" && state != "Error" {
+				return fmt.Sprintf("already %s", state)
+			}
+			return ""
+		},
+		run: func(status PrinterClient, control ControlClient, req bulkRequest) error {
+			return control.Connect()
+		},
+	},
+	"pause": {
+		eligible: requireStatus("printing"),
+		run: func(status PrinterClient, control ControlClient, req bulkRequest) error {
+			return control.Pause()
+		},
+	},
+}
+
+func requireStatus(want string) func(h *Handler, p config.Printer) string {
+	return func(h *Handler, p config.Printer) string {
+		if status := h.currentStatus(p); status != want {
+			return fmt.Sprintf("printer is %s", status)
+		}
+		return ""
+	}
+}
+
+// currentStatus fetches only the printer state, without job or spool details
+func (h *Handler) currentStatus(p config.Printer) string {
+	resp, err := h.printerClient(p.ID).GetPrinterState()
+	if err != nil {
+		return "offline"
+	}
+	return dashboardStatus(resp)
+}
+
+func (h *Handler) handleBulkAction(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("action")
+	action, ok := bulkActions[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Unknown bulk action")
+		return
+	}
+
+	req := bulkRequest{
+		Hotend: h.settings.Preheat.Hotend,
+		Bed:    h.settings.Preheat.Bed,
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	var printers []config.Printer
+	for _, p := range h.printersMatching(r, req.Tags) {
+		if req.Group == "" || h.settings.Printers[p.ID].Group == req.Group {
+			printers = append(printers, p)
+		}
+	}
+
+	user := auth.UserFromContext(r.Context())
+	h.logger.Printf("Bulk %s requested by %s for %d printers", name, user.Name, len(printers))
+
+	// Check eligibility concurrently, then act only on eligible printers
+	skipped := make([]string, len(printers))
+	var wg sync.WaitGroup
+	for i, p := range printers {
+		wg.Add(1)
+		go func(i int, p config.Printer) {
+			defer wg.Done()
+			skipped[i] = action.eligible(h, p)
+		}(i, p)
+	}
+	wg.Wait()
+
+	var targets []config.Printer
+	for i, p := range printers {
+		if skipped[i] == "" {
+			targets = append(targets, p)
+		}
+	}
+
+	if isDryRun(r) {
+		report := make([]models.DryRunTarget, 0, len(printers))
+		for i, p := range printers {
+			target := models.DryRunTarget{PrinterID: p.ID, Name: p.Name, Skipped: skipped[i]}
+			if skipped[i] == "" {
+				target.Upstream, _ = h.previewPrinter(p, func(status PrinterClient, control ControlClient) error {
+					return action.run(status, control, req)
+				})
+			}
+			report = append(report, target)
+		}
+		writeDryRun(w, r, "bulk "+name, 0, report)
+		return
+	}
+
+	results := h.runAction(targets, func(p config.Printer) error {
+		_, err := h.commands.run(p.ID, name, fmt.Sprint(req.Hotend, req.Bed), user.Name, func() error {
+			return action.run(h.printerClient(p.ID), h.controlClient(p.ID), req)
+		})
+		return err
+	})
+	for i, p := range printers {
+		if skipped[i] != "" {
+			results = append(results, models.ActionResult{PrinterID: p.ID, Name: p.Name, Skipped: skipped[i]})
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "ok",
+		"action":  name,
+		"results": results,
+	})
+}
@@ -0,0 +1,179 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/wmarchesi123/go-3dprint-client/config"
+	"github.com/wmarchesi123/octodash/internal/camarchive"
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/settings"
+)
+
+// newCameraArchive sets up archiving camera media to CAMERA_ARCHIVE_S3_BUCKET;
+// it returns nil when no bucket is configured
+func (h *Handler) newCameraArchive(cfg *config.Config, s *settings.Settings) *camarchive.Archiver {
+	c := s.CameraArchive
+	if c.S3.Bucket == "" {
+		return nil
+	}
+
+	cameras := make([]camarchive.Camera, 0, len(cfg.Printers))
+	for _, p := range cfg.Printers {
+		snapshotURL, _ := cameraSources(p, s.Printers[p.ID])
+		cameras = append(cameras, camarchive.Camera{
+			ID:          p.ID,
+			Name:        p.Name,
+			SnapshotURL: snapshotURL,
+			Timelapses:  liveControl{h, p.ID},
+		})
+	}
+
+	bucket := s3Client(c.S3)
+	return camarchive.New(&bucket, cameras, camarchive.Options{
+		SnapshotInterval: c.SnapshotInterval,
+		ClipInterval:     c.ClipInterval,
+		ClipFrames:       c.ClipFrames,
+		Timelapses:       c.Timelapses,
+		DeleteTimelapses: c.DeleteTimelapses,
+		Retention: map[string]time.Duration{
+			models.MediaSnapshot:  days(c.SnapshotDays),
+			models.MediaClip:      days(c.ClipDays),
+			models.MediaTimelapse: days(c.TimelapseDays),
+		},
+	}, h.clock.Now, h.logger)
+}
+
+// cameraSources returns where a printer's camera serves snapshots and its
+// MJPEG stream, defaulting to OctoPi's webcam next to OctoPrint
+func cameraSources(p config.Printer, ps settings.PrinterSettings) (snapshot, stream string) {
+	base := strings.TrimSuffix(p.OctoPrintURL, "/")
+	snapshot, stream = ps.SnapshotURL, ps.StreamURL
+	if snapshot == "" {
+		snapshot = base + "/webcam/?action=snapshot"
+	}
+	if stream == "" {
+		stream = base + "/webcam/?action=stream"
+	}
+	return snapshot, stream
+}
+
+func days(n int) time.Duration {
+	return time.Duration(n) * 24 * time.Hour
+}
+
+// observeCameras tells the camera archive which printers are printing
+func (h *Handler) observeCameras(printers []*models.PrinterStatus) {
+	if h.cameras != nil {
+		h.cameras.Observe(printers)
+	}
+}
+
+// archiveEvent saves a clip of the frames leading up to a failed, cancelled
+// or interrupted print, or a printer error
+func (h *Handler) archiveEvent(eventType, printerID string, data interface{}) {
+	if h.cameras == nil {
+		return
+	}
+	fields, _ := data.(map[string]interface{})
+
+	var reason string
+	switch eventType {
+	case models.EventJobFinished:
+		switch fields["result"] {
+		case "completed":
+			return
+		case "cancelled":
+			reason = models.ClipCancelled
+		default:
+			reason = models.ClipFailed
+		}
+	case models.EventAlertRaised:
+		switch fields["kind"] {
+		case "error", "thermal":
+			reason = models.ClipError
+		case "interrupted":
+			reason = models.ClipInterrupted
+		default:
+			return
+		}
+	default:
+		return
+	}
+
+	err := h.cameras.SaveClip(printerID, reason)
+	if err != nil && !errors.Is(err, camarchive.ErrNoFrames) {
+		h.logger.Printf("Saving %s clip of %s failed: %v", reason, printerID, err)
+	}
+}
+
+// handleCameraArchive reports what has been archived from each camera
+func (h *Handler) handleCameraArchive(w http.ResponseWriter, r *http.Request) {
+	if h.cameras == nil {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"status":   "ok",
+			"enabled":  false,
+			"printers": []models.CameraArchiveStatus{},
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":   "ok",
+		"enabled":  true,
+		"bucket":   h.settings.CameraArchive.S3.Bucket,
+		"printers": h.cameras.Status(),
+	})
+}
+
+// handleSaveClip stores a printer's recent camera frames as a clip now
+func (h *Handler) handleSaveClip(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if _, ok := h.findPrinter(id); !ok || !h.tenancy(r).printer(id) {
+		writeError(w, http.StatusNotFound, "Printer not found")
+		return
+	}
+	if h.cameras == nil {
+		writeError(w, http.StatusConflict, "Camera archive is not configured")
+		return
+	}
+
+	err := h.cameras.SaveClip(id, models.ClipManual)
+	switch {
+	case errors.Is(err, camarchive.ErrUnknownPrinter):
+		writeError(w, http.StatusNotFound, "Printer not found")
+		return
+	case errors.Is(err, camarchive.ErrNoFrames):
+		writeError(w, http.StatusConflict, "No camera frames captured yet")
+		return
+	case err != nil:
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	h.audit(r, "camera.clip", id, "")
+
+	writeJSON(w, http.StatusAccepted, map[string]interface{}{
+		"status":     "ok",
+		"printer_id": id,
+	})
+}
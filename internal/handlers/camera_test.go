@@ -0,0 +1,94 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestCameraArchive(t *testing.T) {
+	server := testutil.NewS3(t)
+	t.Setenv("CAMERA_ARCHIVE_S3_ENDPOINT", server.URL)
+	t.Setenv("CAMERA_ARCHIVE_S3_BUCKET", "farm")
+	t.Setenv("CAMERA_ARCHIVE_S3_ACCESS_KEY", "key")
+	t.Setenv("CAMERA_ARCHIVE_S3_SECRET_KEY", "secret")
+	t.Setenv("CAMERA_ARCHIVE_CLIP_INTERVAL", "20ms")
+	t.Setenv("CAMERA_ARCHIVE_TIMELAPSES", "false")
+	op := testutil.NewOctoPrint(t)
+	h := newTestHandler(t, testutil.NewSpoolman(t), testutil.Printer{Name: "Mini", Server: op})
+
+	// waitKey polls until an archived key contains part
+	waitKey := func(part string) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for !strings.Contains(strings.Join(server.Keys(), "\n"), part) {
+			if time.Now().After(deadline) {
+				t.Fatalf("nothing archived matching %q; got %v", part, server.Keys())
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	getStatus(t, h)
+	op.SetPrinting("gear.gcode", 10, 600)
+	getStatus(t, h)
+	waitKey("farm/snapshots/printer-1/")
+
+	// A printer error saves the frames leading up to it
+	op.SetError("Error: Thermal Runaway")
+	getStatus(t, h)
+	waitKey("-error/clip.json")
+
+	if rec := postJSON(h, "/api/printers/printer-1/clip", ""); rec.Code != http.StatusConflict {
+		t.Errorf("clip without frames = %d, want %d", rec.Code, http.StatusConflict)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/api/admin/camera-archive", nil))
+	var response struct {
+		Enabled  bool                         `json:"enabled"`
+		Printers []models.CameraArchiveStatus `json:"printers"`
+	}
+	json.NewDecoder(rec.Body).Decode(&response)
+	if !response.Enabled || len(response.Printers) != 1 {
+		t.Fatalf("GET /api/admin/camera-archive = %d %s", rec.Code, rec.Body)
+	}
+	if st := response.Printers[0]; st.LastClip == nil || st.LastSnapshot == nil || st.Uploaded == 0 {
+		t.Errorf("status = %+v, want a snapshot and a clip", st)
+	}
+}
+
+func TestCameraArchiveDisabled(t *testing.T) {
+	h := newTestHandler(t, testutil.NewSpoolman(t), testutil.Printer{Name: "Mini", Server: testutil.NewOctoPrint(t)})
+
+	if rec := postJSON(h, "/api/printers/printer-1/clip", ""); rec.Code != http.StatusConflict {
+		t.Errorf("clip without an archive = %d, want %d", rec.Code, http.StatusConflict)
+	}
+	if rec := postJSON(h, "/api/printers/printer-9/clip", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("clip of unknown printer = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
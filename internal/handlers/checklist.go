@@ -0,0 +1,205 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/auth"
+	"github.com/wmarchesi123/octodash/internal/models"
+)
+
+const checklistsBucket = "checklists"
+
+// checklist returns a printer's configured checklist with the ticks recorded
+// so far; ticks for items no longer configured are ignored
+func (h *Handler) checklist(printerID string) (models.Checklist, error) {
+	list := models.Checklist{PrinterID: printerID, Items: []models.ChecklistItem{}}
+
+	var saved models.Checklist
+	if _, err := h.store.Get(checklistsBucket, printerID, &saved); err != nil {
+		return list, err
+	}
+
+	list.Complete = true
+	for _, name := range h.settings.Printers[printerID].Checklist {
+		item := models.ChecklistItem{Name: name}
+		if i := slices.IndexFunc(saved.Items, func(s models.ChecklistItem) bool { return s.Name == name }); i >= 0 {
+			item = saved.Items[i]
+		}
+		list.Complete = list.Complete && item.Checked
+		list.Items = append(list.Items, item)
+	}
+	return list, nil
+}
+
+// checklistPassed reports whether a printer may start a queued job: it has no
+// checklist, or every item on it is ticked
+func (h *Handler) checklistPassed(printerID string) bool {
+	if len(h.settings.Printers[printerID].Checklist) == 0 {
+		return true
+	}
+	list, err := h.checklist(printerID)
+	if err != nil {
+		h.logger.Printf("Failed to read checklist for %s: %v", printerID, err)
+		return false
+	}
+	return list.Complete
+}
+
+// resetChecklist clears a printer's ticks once a job has started
+func (h *Handler) resetChecklist(printerID string) {
+	if len(h.settings.Printers[printerID].Checklist) == 0 {
+		return
+	}
+	if err := h.store.Delete(checklistsBucket, printerID); err != nil {
+		h.logger.Printf("Failed to reset checklist for %s: %v", printerID, err)
+	}
+}
+
+// addChecklists shows each printer's checklist on its card
+func (h *Handler) addChecklists(printers []*models.PrinterStatus) {
+	for _, p := range printers {
+		p.Checklist = nil
+		if len(h.settings.Printers[p.ID].Checklist) == 0 {
+			continue
+		}
+		if list, err := h.checklist(p.ID); err == nil {
+			p.Checklist = &list
+		}
+	}
+}
+
+// audit records an action in the audit trail, logging rather than failing on errors
+func (h *Handler) audit(r *http.Request, action, printerID, detail string) {
+	user := "octodash"
+	if r != nil {
+		user = auth.UserFromContext(r.Context()).Name
+	}
+	entry := models.AuditEntry{User: user, Action: action, PrinterID: printerID, Detail: detail}
+	if err := h.auditTrail.Record(entry, h.clock.Now()); err != nil {
+		h.logger.Printf("Failed to record %s in the audit trail: %v", action, err)
+	}
+}
+
+func (h *Handler) handleChecklistGet(w http.ResponseWriter, r *http.Request) {
+	printer, ok := h.findPrinter(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Printer not found")
+		return
+	}
+
+	list, err := h.checklist(printer.ID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":    "ok",
+		"checklist": list,
+	})
+}
+
+// handleChecklistTick ticks or unticks one item on a printer's checklist
+func (h *Handler) handleChecklistTick(w http.ResponseWriter, r *http.Request) {
+	printer, ok := h.findPrinter(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Printer not found")
+		return
+	}
+
+	var req struct {
+		Item    string `json:"item"`
+		Checked bool   `json:"checked"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	h.checklistMu.Lock()
+	defer h.checklistMu.Unlock()
+
+	list, err := h.checklist(printer.ID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	i := slices.IndexFunc(list.Items, func(item models.ChecklistItem) bool { return item.Name == req.Item })
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "Checklist item not found")
+		return
+	}
+
+	item := models.ChecklistItem{Name: req.Item, Checked: req.Checked}
+	if req.Checked {
+		at := h.clock.Now().UTC().Truncate(time.Second)
+		item.By, item.At = auth.UserFromContext(r.Context()).Name, &at
+	}
+	list.Items[i] = item
+
+	list.Complete = true
+	for _, item := range list.Items {
+		list.Complete = list.Complete && item.Checked
+	}
+	if err := h.store.Put(checklistsBucket, printer.ID, list); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	action := "checklist.check"
+	if !req.Checked {
+		action = "checklist.uncheck"
+	}
+	h.audit(r, action, printer.ID, req.Item)
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":    "ok",
+		"checklist": list,
+	})
+}
+
+// handleAudit returns the audit trail newest first, optionally for one ?printer_id=
+func (h *Handler) handleAudit(w http.ResponseWriter, r *http.Request) {
+	limit := 100
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+		limit = n
+	}
+
+	entries, err := h.auditTrail.List(r.URL.Query().Get("printer_id"), limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "ok",
+		"audit":  entries,
+	})
+}
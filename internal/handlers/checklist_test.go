@@ -0,0 +1,91 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestChecklist(t *testing.T) {
+	t.Setenv("AUTH_USERS", "vic:viewer:vic-token,olga:operator:olga-token,ada:admin:ada-token")
+	t.Setenv("PRINTER_1_CHECKLIST", "Bed clean, Filament dried")
+	op := testutil.NewOctoPrint(t)
+	h := newTestHandler(t, testutil.NewSpoolman(t), testutil.Printer{Name: "Mini", Server: op})
+
+	if rec := doAs(h, "olga-token", "POST", "/api/queue", `{"file": "gear.gcode"}`); rec.Code != http.StatusCreated {
+		t.Fatalf("queue add = %d", rec.Code)
+	}
+	h.dispatchOnce()
+	if got := op.Printed(); len(got) != 0 {
+		t.Fatalf("printed %v before the checklist was ticked", got)
+	}
+
+	if rec := doAs(h, "vic-token", "POST", "/api/printers/printer-1/checklist", `{"item": "Bed clean", "checked": true}`); rec.Code != http.StatusForbidden {
+		t.Errorf("viewer tick = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+	if rec := doAs(h, "olga-token", "POST", "/api/printers/printer-1/checklist", `{"item": "Nozzle wiped", "checked": true}`); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown item = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	for _, item := range []string{"Bed clean", "Filament dried"} {
+		if rec := doAs(h, "olga-token", "POST", "/api/printers/printer-1/checklist", `{"item": "`+item+`", "checked": true}`); rec.Code != http.StatusOK {
+			t.Fatalf("tick %s = %d: %s", item, rec.Code, rec.Body)
+		}
+	}
+
+	list := getStatus(t, h)["printer-1"].Checklist
+	if list == nil || !list.Complete || list.Items[0].By != "olga" {
+		t.Fatalf("checklist = %+v, want complete and ticked by olga", list)
+	}
+
+	h.dispatchOnce()
+	if got := op.Printed(); len(got) != 1 || got[0] != "gear.gcode" {
+		t.Fatalf("printed %v, want gear.gcode once the checklist is done", got)
+	}
+
+	// Every job needs a fresh check
+	var resp struct {
+		Checklist models.Checklist `json:"checklist"`
+	}
+	json.NewDecoder(doAs(h, "vic-token", "GET", "/api/printers/printer-1/checklist", "").Body).Decode(&resp)
+	if resp.Checklist.Complete || resp.Checklist.Items[0].Checked {
+		t.Errorf("checklist after dispatch = %+v, want reset", resp.Checklist)
+	}
+
+	if rec := doAs(h, "olga-token", "GET", "/api/admin/audit", ""); rec.Code != http.StatusForbidden {
+		t.Errorf("operator audit = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+	var trail struct {
+		Audit []models.AuditEntry `json:"audit"`
+	}
+	json.NewDecoder(doAs(h, "ada-token", "GET", "/api/admin/audit?printer_id=printer-1", "").Body).Decode(&trail)
+	var actions []string
+	for _, e := range trail.Audit {
+		actions = append(actions, e.User+" "+e.Action)
+	}
+	want := "octodash queue.dispatch,olga checklist.check,olga checklist.check"
+	if got := strings.Join(actions, ","); got != want {
+		t.Errorf("audit = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,276 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+)
+
+const (
+	// commandRetention is how long finished commands stay available to report on
+	commandRetention = 10 * time.Minute
+	// commandConfirmTimeout is how long a sent command may wait for the printer
+	// status it expects before it's reported unconfirmed
+	commandConfirmTimeout = 30 * time.Second
+)
+
+// commandOutcomes is the printer status that shows an action took effect
+var commandOutcomes = map[string]string{
+	"pause":  "paused",
+	"resume": "printing",
+	"cancel": "idle",
+	"print":  "printing",
+}
+
+// errSuperseded fails commands still waiting when an emergency stop is sent
+var errSuperseded = errors.New("superseded by emergency stop")
+
+// commandQueue sends control requests to each printer one at a time, in the
+// order they arrive, so a pause, a temperature change and a macro can't race
+// each other against OctoPrint. A request identical to one still waiting or
+// being sent joins it instead of being sent twice.
+type commandQueue struct {
+	clock    Clock
+	mu       sync.Mutex
+	printers map[string]*printerCommands
+	finished []*queuedCommand
+	byID     map[string]*queuedCommand
+}
+
+// printerCommands are a printer's waiting commands, oldest first
+type printerCommands struct {
+	pending  []*queuedCommand
+	draining bool // a worker is sending them
+}
+
+type queuedCommand struct {
+	models.Command
+	key  string
+	run  func() error
+	err  error
+	done chan struct{}
+}
+
+func newCommandQueue(clock Clock) *commandQueue {
+	return &commandQueue{
+		clock:    clock,
+		printers: make(map[string]*printerCommands),
+		byID:     make(map[string]*queuedCommand),
+	}
+}
+
+// run queues fn as action on a printer and waits for it to be sent. args
+// distinguishes otherwise identical actions, such as different gcode.
+func (q *commandQueue) run(printerID, action, args, user string, fn func() error) (models.Command, error) {
+	c := q.submit(printerID, action, args, user, fn)
+	<-c.done
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return c.Command, c.err
+}
+
+func (q *commandQueue) submit(printerID, action, args, user string, fn func() error) *queuedCommand {
+	key := action + "\x00" + args
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	p := q.printers[printerID]
+	if p == nil {
+		p = &printerCommands{}
+		q.printers[printerID] = p
+	}
+	for _, c := range p.pending {
+		if c.key == key {
+			return c
+		}
+	}
+
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	c := &queuedCommand{
+		Command: models.Command{
+			ID:          hex.EncodeToString(buf),
+			PrinterID:   printerID,
+			Action:      action,
+			Status:      models.CommandAccepted,
+			Expects:     commandOutcomes[action],
+			RequestedBy: user,
+			AcceptedAt:  q.clock.Now().UTC(),
+		},
+		key:  key,
+		run:  fn,
+		done: make(chan struct{}),
+	}
+	p.pending = append(p.pending, c)
+	q.byID[c.ID] = c
+
+	if !p.draining {
+		p.draining = true
+		go q.drain(printerID, p)
+	}
+	return c
+}
+
+// drain sends a printer's commands in turn, exiting once none are left
+func (q *commandQueue) drain(printerID string, p *printerCommands) {
+	for {
+		q.mu.Lock()
+		if len(p.pending) == 0 {
+			p.draining = false
+			delete(q.printers, printerID)
+			q.mu.Unlock()
+			return
+		}
+		c := p.pending[0]
+		c.Status = models.CommandSending
+		q.mu.Unlock()
+
+		err := c.run()
+
+		q.mu.Lock()
+		p.pending = p.pending[1:]
+		q.finish(c, err)
+		q.mu.Unlock()
+	}
+}
+
+// clear fails the commands waiting behind the one being sent, for an
+// emergency stop that must not be followed by anything already queued
+func (q *commandQueue) clear(printerID string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	p := q.printers[printerID]
+	if p == nil {
+		return
+	}
+	keep := 0
+	if len(p.pending) > 0 && p.pending[0].Status == models.CommandSending {
+		keep = 1
+	}
+	for _, c := range p.pending[keep:] {
+		q.finish(c, errSuperseded)
+	}
+	p.pending = p.pending[:keep]
+}
+
+// finish records a command's outcome and wakes its callers; q.mu must be held
+func (q *commandQueue) finish(c *queuedCommand, err error) {
+	now := q.clock.Now().UTC()
+	c.err = err
+	if err != nil {
+		c.Status = models.CommandFailed
+		c.Error = err.Error()
+	} else {
+		c.Status = models.CommandSent
+		c.SentAt = &now
+	}
+	close(c.done)
+
+	// Drop commands nobody will ask about any more while we hold the lock
+	cutoff := now.Add(-commandRetention)
+	for len(q.finished) > 0 && q.finished[0].AcceptedAt.Before(cutoff) {
+		delete(q.byID, q.finished[0].ID)
+		q.finished = q.finished[1:]
+	}
+	q.finished = append(q.finished, c)
+}
+
+// get returns a command that is queued or finished recently
+func (q *commandQueue) get(id string) (models.Command, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	c, ok := q.byID[id]
+	if !ok {
+		return models.Command{}, false
+	}
+	q.expire(c, q.clock.Now())
+	return c.Command, true
+}
+
+// observe confirms sent commands whose printer now shows the status they
+// expect. polledAt is when the poll began, so a status fetched before a
+// command was sent can't confirm it.
+func (q *commandQueue) observe(printers []*models.PrinterStatus, polledAt time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.clock.Now()
+	for _, c := range q.finished {
+		if c.Status != models.CommandSent || c.Expects == "" {
+			continue
+		}
+		for _, p := range printers {
+			if p.ID == c.PrinterID && p.Status == c.Expects && c.SentAt.Before(polledAt) {
+				confirmed := now.UTC()
+				c.Status = models.CommandConfirmed
+				c.ConfirmedAt = &confirmed
+			}
+		}
+		q.expire(c, now)
+	}
+}
+
+// expire gives up waiting for a sent command's expected status; q.mu must be held
+func (q *commandQueue) expire(c *queuedCommand, now time.Time) {
+	if c.Status == models.CommandSent && c.Expects != "" && now.Sub(*c.SentAt) > commandConfirmTimeout {
+		c.Status = models.CommandUnconfirmed
+	}
+}
+
+// awaitCommand waits for a queued command to be sent and returns the status
+// code to answer with. Callers sending Prefer: respond-async get 202 at once
+// and follow the command at the Location given.
+func (h *Handler) awaitCommand(w http.ResponseWriter, r *http.Request, c *queuedCommand) (int, models.Command, error) {
+	if strings.Contains(r.Header.Get("Prefer"), "respond-async") {
+		h.commands.mu.Lock()
+		defer h.commands.mu.Unlock()
+		w.Header().Set("Location", "/api/commands/"+c.ID)
+		return http.StatusAccepted, c.Command, nil
+	}
+
+	<-c.done
+	h.commands.mu.Lock()
+	defer h.commands.mu.Unlock()
+	return http.StatusOK, c.Command, c.err
+}
+
+func (h *Handler) handleCommandGet(w http.ResponseWriter, r *http.Request) {
+	cmd, ok := h.commands.get(r.PathValue("id"))
+	if !ok || !h.tenancy(r).printer(cmd.PrinterID) {
+		writeError(w, http.StatusNotFound, "Command not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "ok",
+		"command": cmd,
+	})
+}
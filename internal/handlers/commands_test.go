@@ -0,0 +1,229 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestCommandQueue(t *testing.T) {
+	q := newCommandQueue(systemClock{})
+
+	var mu sync.Mutex
+	var sent []string
+	running, maxRunning := 0, 0
+	release := make(chan struct{})
+	send := func(name string) func() error {
+		return func() error {
+			mu.Lock()
+			running++
+			maxRunning = max(maxRunning, running)
+			mu.Unlock()
+			if name == "pause" {
+				<-release
+			}
+			mu.Lock()
+			running--
+			sent = append(sent, name)
+			mu.Unlock()
+			return nil
+		}
+	}
+
+	pause := q.submit("printer-1", "pause", "", "olga", send("pause"))
+	waitForStatus(t, q, pause, models.CommandSending)
+
+	macro := q.submit("printer-1", "macro", "G28", "olga", send("macro"))
+	again := q.submit("printer-1", "macro", "G28", "sam", send("macro again"))
+	heat := q.submit("printer-1", "preheat", "215 60", "olga", send("preheat"))
+	other := q.submit("printer-2", "macro", "G28", "olga", send("other printer"))
+	if again != macro {
+		t.Error("identical waiting command was queued twice")
+	}
+	<-other.done
+
+	close(release)
+	for _, c := range []*queuedCommand{pause, macro, heat} {
+		<-c.done
+		if c.Status != models.CommandSent || c.SentAt == nil {
+			t.Errorf("%s ended %s", c.Action, c.Status)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if want := []string{"other printer", "pause", "macro", "preheat"}; !slices.Equal(sent, want) {
+		t.Errorf("sent %v, want %v", sent, want)
+	}
+	if maxRunning != 2 {
+		t.Errorf("%d commands ran at once, want one per printer", maxRunning)
+	}
+}
+
+func TestCommandQueueEmergencyStop(t *testing.T) {
+	q := newCommandQueue(systemClock{})
+	release := make(chan struct{})
+
+	pause := q.submit("printer-1", "pause", "", "olga", func() error { <-release; return nil })
+	waitForStatus(t, q, pause, models.CommandSending)
+	heat := q.submit("printer-1", "preheat", "215 60", "olga", func() error {
+		t.Error("queued command was sent after an emergency stop")
+		return nil
+	})
+
+	q.clear("printer-1")
+	<-heat.done
+	if heat.Status != models.CommandFailed || heat.err != errSuperseded {
+		t.Errorf("queued command ended %s: %v", heat.Status, heat.err)
+	}
+
+	close(release)
+	if _, err := q.run("printer-1", "resume", "", "olga", func() error { return nil }); err != nil {
+		t.Errorf("command after the emergency stop: %v", err)
+	}
+	if pause.Status != models.CommandSent {
+		t.Errorf("in-flight command ended %s", pause.Status)
+	}
+}
+
+func TestJobActionReportsCommand(t *testing.T) {
+	op := testutil.NewOctoPrint(t)
+	op.SetPrinting("gear.gcode", 40, 1200)
+	h := newTestHandler(t, testutil.NewSpoolman(t), testutil.Printer{Name: "MK4", Server: op})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("POST", "/api/printers/printer-1/pause", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("pause = %d: %s", rec.Code, rec.Body)
+	}
+
+	var resp struct {
+		Command models.Command `json:"command"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if c := resp.Command; c.ID == "" || c.Action != "pause" || c.PrinterID != "printer-1" || c.Status != models.CommandSent {
+		t.Errorf("command = %+v", c)
+	}
+}
+
+func waitForStatus(t *testing.T, q *commandQueue, c *queuedCommand, status string) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		q.mu.Lock()
+		got := c.Status
+		q.mu.Unlock()
+		if got == status {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("%s never reached %s", c.Action, status)
+}
+
+func TestCommandStatus(t *testing.T) {
+	op := testutil.NewOctoPrint(t)
+	op.SetPrinting("gear.gcode", 40, 1200)
+	clock := &stepClock{now: time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)}
+	h := newHandlerWithConfig(t, testutil.Config(testutil.NewSpoolman(t), testutil.Printer{Name: "MK4", Server: op}), WithClock(clock))
+
+	post := func(path string, async bool) (*httptest.ResponseRecorder, models.Command) {
+		req := httptest.NewRequest("POST", path, nil)
+		if async {
+			req.Header.Set("Prefer", "respond-async")
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		var resp struct {
+			Command models.Command `json:"command"`
+		}
+		json.NewDecoder(rec.Body).Decode(&resp)
+		return rec, resp.Command
+	}
+	get := func(id string) models.Command {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", "/api/commands/"+id, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET command = %d: %s", rec.Code, rec.Body)
+		}
+		var resp struct {
+			Command models.Command `json:"command"`
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp.Command
+	}
+
+	_, pause := post("/api/printers/printer-1/pause", false)
+	if c := get(pause.ID); c.Status != models.CommandSent || c.Expects != "paused" {
+		t.Fatalf("sent pause = %+v", c)
+	}
+	getStatus(t, h)
+	if c := get(pause.ID); c.Status != models.CommandSent {
+		t.Errorf("pause %s before the printer paused", c.Status)
+	}
+
+	clock.advance(time.Second)
+	op.SetPaused()
+	if p := getStatus(t, h)["printer-1"]; p.Status != "paused" {
+		t.Fatalf("paused printer reports %s", p.Status)
+	}
+	if c := get(pause.ID); c.Status != models.CommandConfirmed || c.ConfirmedAt == nil {
+		t.Errorf("pause after the printer paused = %+v", c)
+	}
+
+	// The fake printer stays paused, so a resume never takes effect
+	_, resume := post("/api/printers/printer-1/resume", false)
+	clock.advance(commandConfirmTimeout + time.Second)
+	getStatus(t, h)
+	if c := get(resume.ID); c.Status != models.CommandUnconfirmed {
+		t.Errorf("resume that never took effect = %s", c.Status)
+	}
+
+	rec, cancel := post("/api/printers/printer-1/cancel", true)
+	if rec.Code != http.StatusAccepted || rec.Header().Get("Location") != "/api/commands/"+cancel.ID {
+		t.Fatalf("async cancel = %d, Location %q", rec.Code, rec.Header().Get("Location"))
+	}
+	deadline := time.Now().Add(time.Second)
+	for get(cancel.ID).Status != models.CommandSent {
+		if time.Now().After(deadline) {
+			t.Fatal("async cancel was never sent")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	missing := httptest.NewRecorder()
+	h.ServeHTTP(missing, httptest.NewRequest("GET", "/api/commands/nope", nil))
+	if missing.Code != http.StatusNotFound {
+		t.Errorf("unknown command = %d", missing.Code)
+	}
+}
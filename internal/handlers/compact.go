@@ -0,0 +1,99 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+)
+
+// sectionAliases lets clients use short names in ?fields=
+var sectionAliases = map[string]string{
+	"temps":     "temperatures",
+	"spool":     "current_spool",
+	"thumbnail": "thumbnail_url",
+	"url":       "octoprint_url",
+}
+
+// payloadOptions describes how much of the status payload a client asked for
+type payloadOptions struct {
+	compact  bool
+	sections map[string]bool
+}
+
+// parsePayloadOptions reads ?compact=1 and ?fields=a,b. Without either, the full payload is sent.
+func parsePayloadOptions(r *http.Request) (payloadOptions, error) {
+	q := r.URL.Query()
+	opts := payloadOptions{compact: q.Get("compact") == "1" || q.Get("compact") == "true"}
+
+	fields := q.Get("fields")
+	if fields == "" {
+		return opts, nil
+	}
+
+	known := make(map[string]bool)
+	for _, s := range models.StatusSections {
+		known[s] = true
+	}
+
+	opts.sections = make(map[string]bool)
+	for _, f := range strings.Split(fields, ",") {
+		f = strings.ToLower(strings.TrimSpace(f))
+		if alias, ok := sectionAliases[f]; ok {
+			f = alias
+		}
+		if f == "" {
+			continue
+		}
+		if !known[f] {
+			return opts, fmt.Errorf("unknown field %q", f)
+		}
+		opts.sections[f] = true
+	}
+
+	return opts, nil
+}
+
+// trimming reports whether statuses should be cut down at all
+func (o payloadOptions) trimming() bool {
+	return o.compact || o.sections != nil
+}
+
+// apply trims each status and, in compact mode, drops empty envelope entries
+func (o payloadOptions) apply(envelope *models.StatusResponse, printers []*models.PrinterStatus) {
+	if !o.trimming() {
+		envelope.Printers = printers
+		return
+	}
+
+	trimmed := make([]*models.PrinterStatus, len(printers))
+	for i, p := range printers {
+		trimmed[i] = p.Trimmed(o.sections)
+	}
+	envelope.Printers = trimmed
+
+	if o.compact {
+		envelope.Server = nil
+		envelope.Units = nil
+		envelope.Timezone = ""
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/wmarchesi123/go-3dprint-client/config"
+	"github.com/wmarchesi123/octodash/internal/auth"
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/store"
+)
+
+const completionsBucket = "completions"
+
+// completionTracker notices prints that run to 100% and holds the printer in
+// the "completed" state until someone confirms the part was removed or the
+// next job starts. Pending completions are persisted so a restart keeps them.
+type completionTracker struct {
+	store *store.Store
+
+	mu       sync.Mutex
+	printing map[string]bool
+	pending  map[string]models.CompletedJob
+}
+
+func newCompletionTracker(s *store.Store) (*completionTracker, error) {
+	jobs, err := store.List[models.CompletedJob](s, completionsBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &completionTracker{
+		store:    s,
+		printing: make(map[string]bool),
+		pending:  make(map[string]models.CompletedJob),
+	}
+	for _, job := range jobs {
+		c.pending[job.PrinterID] = job
+	}
+	return c, nil
+}
+
+// observe records a printer's OctoPrint status, reporting whether it just went
+// from printing or paused to idle. A new print clears any pending completion.
+func (c *completionTracker) observe(id, status string) (stopped bool, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stopped = c.printing[id] && status == "idle"
+	c.printing[id] = onJob(status)
+
+	if _, ok := c.pending[id]; ok && status == "printing" {
+		delete(c.pending, id)
+		err = c.store.Delete(completionsBucket, id)
+	}
+	return stopped, err
+}
+
+// complete marks a printer's job as finished and awaiting removal
+func (c *completionTracker) complete(job models.CompletedJob) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pending[job.PrinterID] = job
+	return c.store.Put(completionsBucket, job.PrinterID, job)
+}
+
+// get returns the printer's finished job, if its part hasn't been removed
+func (c *completionTracker) get(id string) (models.CompletedJob, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	job, ok := c.pending[id]
+	return job, ok
+}
+
+// acknowledge clears a printer's finished job, reporting whether there was one
+func (c *completionTracker) acknowledge(id string) (models.CompletedJob, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	job, ok := c.pending[id]
+	if !ok {
+		return job, false, nil
+	}
+	delete(c.pending, id)
+	return job, true, c.store.Delete(completionsBucket, id)
+}
+
+// trackCompletion updates the completed state from a fresh OctoPrint status.
+// When a print stops, the job is fetched once more to tell a finished print
+// from a cancelled one, to charge it to its submitter and to label the part.
+// Printers that clear their own bed never wait; continuous ones cool down and
+// eject instead.
+func (h *Handler) trackCompletion(printer config.Printer, client PrinterClient, status *models.PrinterStatus) {
+	stopped, err := h.completions.observe(printer.ID, status.Status)
+	if err != nil {
+		h.logger.Printf("Failed to clear completed job for %s: %v", printer.Name, err)
+	}
+
+	if stopped {
+		job, err := client.GetJob()
+		if err == nil && job != nil {
+			kwh := h.energyMonitor.LastJobEnergy(printer.ID)
+			h.recordUsage(printer, job, kwh)
+			rec := h.recordJob(printer, status.Status, job, kwh)
+			h.emitFinished(printer.ID, status.Status, job)
+			if rec.Result == models.JobCompleted {
+				h.printPartLabel(printer, rec)
+			}
+		}
+		clears := h.settings.Printers[printer.ID].AutoClear || h.settings.Printers[printer.ID].Continuous
+		if err == nil && job != nil && job.Progress.Completion >= 100 && !clears {
+			done := models.CompletedJob{
+				PrinterID:  printer.ID,
+				File:       job.Job.File.Display,
+				PrintTime:  job.Progress.PrintTime,
+				FinishedAt: h.clock.Now().UTC().Truncate(time.Second),
+			}
+			if err := h.completions.complete(done); err != nil {
+				h.logger.Printf("Failed to record completed job for %s: %v", printer.Name, err)
+			}
+		}
+	}
+	h.trackEject(printer, stopped, status.Status)
+
+	if job, ok := h.completions.get(printer.ID); ok && status.Status == "idle" {
+		status.Status = "completed"
+		status.Completed = &job
+	}
+}
+
+func (h *Handler) handleAcknowledge(w http.ResponseWriter, r *http.Request) {
+	printer, ok := h.findPrinter(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Printer not found")
+		return
+	}
+
+	job, ok, err := h.completions.acknowledge(printer.ID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !ok {
+		writeError(w, http.StatusConflict, "No finished job awaiting removal")
+		return
+	}
+
+	h.logger.Printf("%s confirmed %s was removed from %s", auth.UserFromContext(r.Context()).Name, job.File, printer.Name)
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":    "ok",
+		"completed": job,
+	})
+}
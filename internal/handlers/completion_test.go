@@ -0,0 +1,106 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestJobCompletion(t *testing.T) {
+	sm := testutil.NewSpoolman(t)
+	op := testutil.NewOctoPrint(t)
+	op.SetPrinting("benchy.gcode", 97, 60)
+	h := newTestHandler(t, sm, testutil.Printer{Name: "Mini", Server: op})
+
+	getStatus(t, h)
+
+	op.SetFinished("benchy.gcode")
+	p := getStatus(t, h)["printer-1"]
+	if p.Status != "completed" || p.Completed == nil || p.Completed.File != "benchy.gcode" {
+		t.Fatalf("finished printer = %s, completed %+v", p.Status, p.Completed)
+	}
+
+	// The state sticks across polls until someone clears the bed
+	if p = getStatus(t, h)["printer-1"]; p.Status != "completed" {
+		t.Errorf("second poll after finishing = %s, want completed", p.Status)
+	}
+
+	acknowledge := func() int {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("POST", "/api/printers/printer-1/acknowledge", nil))
+		return rec.Code
+	}
+	if code := acknowledge(); code != http.StatusOK {
+		t.Fatalf("acknowledge = %d", code)
+	}
+	if p = getStatus(t, h)["printer-1"]; p.Status != "idle" || p.Completed != nil {
+		t.Errorf("status after acknowledge = %s, completed %+v", p.Status, p.Completed)
+	}
+	if code := acknowledge(); code != http.StatusConflict {
+		t.Errorf("second acknowledge = %d, want %d", code, http.StatusConflict)
+	}
+}
+
+func TestJobCompletionClearedByNextJob(t *testing.T) {
+	sm := testutil.NewSpoolman(t)
+	op := testutil.NewOctoPrint(t)
+	op.SetPrinting("benchy.gcode", 97, 60)
+	h := newTestHandler(t, sm, testutil.Printer{Name: "Mini", Server: op})
+
+	getStatus(t, h)
+	op.SetFinished("benchy.gcode")
+	getStatus(t, h)
+
+	op.SetPrinting("gear.gcode", 1, 3000)
+	getStatus(t, h)
+	op.SetIdle()
+	if p := getStatus(t, h)["printer-1"]; p.Status != "idle" {
+		t.Errorf("status after a cancelled follow-up job = %s, want idle", p.Status)
+	}
+}
+
+func TestJobCancelledWhilePaused(t *testing.T) {
+	op := testutil.NewOctoPrint(t)
+	op.SetPrinting("benchy.gcode", 40, 1800)
+	h := newTestHandler(t, testutil.NewSpoolman(t), testutil.Printer{Name: "Mini", Server: op})
+
+	getStatus(t, h)
+	op.SetPaused()
+	if p := getStatus(t, h)["printer-1"]; p.Status != "paused" {
+		t.Fatalf("status after pausing = %s, want paused", p.Status)
+	}
+	op.SetCancelled()
+	if p := getStatus(t, h)["printer-1"]; p.Status != "idle" || p.Completed != nil {
+		t.Errorf("status after cancelling from pause = %s, completed %+v", p.Status, p.Completed)
+	}
+
+	var history struct {
+		Jobs []models.JobRecord `json:"jobs"`
+	}
+	json.Unmarshal(doAs(h, "", "GET", "/api/jobs", "").Body.Bytes(), &history)
+	if len(history.Jobs) != 1 || history.Jobs[0].File != "benchy.gcode" || history.Jobs[0].Result != models.JobCancelled {
+		t.Errorf("job history = %+v, want benchy.gcode cancelled", history.Jobs)
+	}
+}
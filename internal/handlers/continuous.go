@@ -0,0 +1,160 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wmarchesi123/go-3dprint-client/config"
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/store"
+)
+
+const ejectsBucket = "ejects"
+
+// pendingEject is a continuous-mode printer waiting to eject its last print
+type pendingEject struct {
+	PrinterID string    `json:"printer_id"`
+	Due       time.Time `json:"due"`
+}
+
+// ejectTracker holds continuous-mode printers between a stopped print and
+// their eject sequence, so nothing starts on a bed that hasn't cooled and
+// been cleared. Pending ejects are persisted so a restart can't skip one.
+type ejectTracker struct {
+	store *store.Store
+
+	mu      sync.Mutex
+	pending map[string]pendingEject
+}
+
+func newEjectTracker(s *store.Store) (*ejectTracker, error) {
+	ejects, err := store.List[pendingEject](s, ejectsBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &ejectTracker{store: s, pending: make(map[string]pendingEject)}
+	for _, e := range ejects {
+		t.pending[e.PrinterID] = e
+	}
+	return t, nil
+}
+
+// hold keeps a printer from taking jobs until its eject runs at due
+func (t *ejectTracker) hold(id string, due time.Time) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	e := pendingEject{PrinterID: id, Due: due.UTC()}
+	t.pending[id] = e
+	return t.store.Put(ejectsBucket, id, e)
+}
+
+// holding reports whether a printer is waiting to eject
+func (t *ejectTracker) holding(id string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	_, ok := t.pending[id]
+	return ok
+}
+
+// due returns the printers whose cooldown has run out
+func (t *ejectTracker) due(now time.Time) []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var ids []string
+	for id, e := range t.pending {
+		if !now.Before(e.Due) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// release lets a printer take jobs again
+func (t *ejectTracker) release(id string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.pending[id]; !ok {
+		return nil
+	}
+	delete(t.pending, id)
+	return t.store.Delete(ejectsBucket, id)
+}
+
+// trackEject starts a continuous-mode printer's cooldown when its print stops,
+// finished or not, and drops it if someone starts a print by hand meanwhile
+func (h *Handler) trackEject(printer config.Printer, stopped bool, status string) {
+	if !h.settings.Printers[printer.ID].Continuous {
+		return
+	}
+
+	var err error
+	switch {
+	case stopped:
+		cooldown := h.settings.Printers[printer.ID].Cooldown
+		err = h.ejects.hold(printer.ID, h.clock.Now().Add(cooldown))
+	case status == "printing":
+		err = h.ejects.release(printer.ID)
+	}
+	if err != nil {
+		h.logger.Printf("Failed to update eject state for %s: %v", printer.Name, err)
+	}
+}
+
+// addEjects shows printers still cooling before their eject
+func (h *Handler) addEjects(printers []*models.PrinterStatus) {
+	for _, p := range printers {
+		if p.Status == "idle" && h.ejects.holding(p.ID) {
+			p.Status = "cooldown"
+		}
+	}
+}
+
+// runEjects sends the eject sequence to every printer whose cooldown is over,
+// freeing it for the next queued job. A failed eject is retried next time.
+func (h *Handler) runEjects(now time.Time) {
+	for _, id := range h.ejects.due(now) {
+		printer, ok := h.findPrinter(id)
+		if !ok {
+			h.ejects.release(id)
+			continue
+		}
+
+		if commands := h.settings.Printers[id].EjectGcode; len(commands) > 0 {
+			_, err := h.commands.run(id, "eject", strings.Join(commands, "\n"), "octodash", func() error {
+				return h.controlClient(id).SendCommands(commands...)
+			})
+			if err != nil {
+				h.logger.Printf("Failed to eject on %s: %v", printer.Name, err)
+				continue
+			}
+			h.logger.Printf("Ejected the last print on %s", printer.Name)
+		}
+		if err := h.ejects.release(id); err != nil {
+			h.logger.Printf("Failed to clear eject state for %s: %v", printer.Name, err)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestContinuousMode(t *testing.T) {
+	t.Setenv("PRINTER_1_CONTINUOUS", "true")
+	t.Setenv("PRINTER_1_COOLDOWN", "10m")
+	t.Setenv("PRINTER_1_EJECT_GCODE", "G1 Z180 F600, EJECT_PART")
+	op := testutil.NewOctoPrint(t)
+	op.SetPrinting("gear.gcode", 98, 60)
+	clock := &stepClock{now: time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)}
+	h := newHandlerWithConfig(t, testutil.Config(testutil.NewSpoolman(t), testutil.Printer{Name: "Belt", Server: op}), WithClock(clock))
+
+	if rec := postJSON(h, "/api/queue", `{"file": "next.gcode"}`); rec.Code != http.StatusCreated {
+		t.Fatalf("queue add = %d", rec.Code)
+	}
+	getStatus(t, h)
+
+	// Finished, but the bed has to cool before the part comes off
+	op.SetFinished("gear.gcode")
+	if p := getStatus(t, h)["printer-1"]; p.Status != "cooldown" || p.Completed != nil {
+		t.Fatalf("status after finishing = %s, completed %+v", p.Status, p.Completed)
+	}
+	h.dispatchOnce()
+	if got := op.Printed(); len(got) != 0 || len(op.Commands()) != 0 {
+		t.Fatalf("printed %v, sent %v during cooldown", got, op.Commands())
+	}
+
+	clock.advance(10 * time.Minute)
+	h.dispatchOnce()
+	cmds := op.Commands()
+	if len(cmds) != 1 || !strings.Contains(cmds[0], `"G1 Z180 F600","EJECT_PART"`) {
+		t.Fatalf("commands = %v, want the eject sequence", cmds)
+	}
+	if got := op.Printed(); len(got) != 1 || got[0] != "next.gcode" {
+		t.Errorf("printed %v, want the next job straight after ejecting", got)
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/wmarchesi123/go-3dprint-client/config"
+	"github.com/wmarchesi123/octodash/internal/auth"
+)
+
+// jobActions are the per-printer job controls exposed at /api/printers/{id}/{action}
+var jobActions = map[string]func(ControlClient) error{
+	"pause":  ControlClient.Pause,
+	"resume": ControlClient.Resume,
+	"cancel": ControlClient.Cancel,
+}
+
+func (h *Handler) handleJobAction(w http.ResponseWriter, r *http.Request) {
+	printer, ok := h.findPrinter(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Printer not found")
+		return
+	}
+
+	name := r.PathValue("action")
+	action, ok := jobActions[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Unknown action")
+		return
+	}
+
+	if isDryRun(r) {
+		h.dryRunPrinters(w, r, name, 0, []config.Printer{printer}, func(_ config.Printer, _ PrinterClient, c ControlClient) error {
+			return action(c)
+		})
+		return
+	}
+
+	user := auth.UserFromContext(r.Context())
+	h.logger.Printf("%s requested by %s for %s", name, user.Name, printer.Name)
+
+	code, cmd, err := h.awaitCommand(w, r, h.commands.submit(printer.ID, name, "", user.Name, func() error {
+		return action(h.controlClient(printer.ID))
+	}))
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+
+	writeJSON(w, code, map[string]interface{}{
+		"status":  "ok",
+		"command": cmd,
+	})
+}
@@ -0,0 +1,88 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestDashboardConfigEscaping(t *testing.T) {
+	names := []string{
+		`Ada's "Mini"`,
+		`<b>Voron</b> & friends`,
+		`</script><script>alert(1)</script>`,
+		`<!-- MK4 <script>`,
+	}
+	var printers []testutil.Printer
+	for _, name := range names {
+		printers = append(printers, testutil.Printer{Name: name, Server: testutil.NewOctoPrint(t)})
+	}
+	t.Setenv("MACRO_1_NAME", `Park </script>`)
+	t.Setenv("MACRO_1_GCODE", "G27")
+	h := newTestHandler(t, testutil.NewSpoolman(t), printers...)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	body := rec.Body.String()
+
+	for _, raw := range []string{"<script>alert", "<b>Voron", "<!-- MK4"} {
+		if strings.Contains(body, raw) {
+			t.Errorf("dashboard contains unescaped %q", raw)
+		}
+	}
+
+	// The data block must survive the HTML parser intact and parse as JSON
+	block := regexp.MustCompile(`(?s)<script type="application/json" id="dashboard-config">(.*?)</script>`).FindStringSubmatch(body)
+	if block == nil {
+		t.Fatal("dashboard has no config block")
+	}
+	var config struct {
+		Printers []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"printers"`
+		Macros []struct {
+			Name string `json:"name"`
+		} `json:"macros"`
+		Timezone string `json:"timezone"`
+	}
+	if err := json.Unmarshal([]byte(block[1]), &config); err != nil {
+		t.Fatalf("config block is not JSON: %v\n%s", err, block[1])
+	}
+	if len(config.Printers) != len(names) {
+		t.Fatalf("config has %d printers, want %d", len(config.Printers), len(names))
+	}
+	for i, name := range names {
+		if config.Printers[i].Name != name {
+			t.Errorf("printer %d name = %q, want %q", i, config.Printers[i].Name, name)
+		}
+	}
+	if len(config.Macros) != 1 || config.Macros[0].Name != `Park </script>` {
+		t.Errorf("macros = %+v", config.Macros)
+	}
+	if config.Timezone == "" {
+		t.Error("config has no timezone")
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+)
+
+const (
+	// maintenanceBucket keeps the latest database check and compaction across restarts
+	maintenanceBucket = "maintenance"
+	// maintenanceCheck is how often maintainDatabase looks for work that is due, at most
+	maintenanceCheck = time.Hour
+)
+
+// maintainDatabase runs integrity checks and compactions when they fall due,
+// including straight after a restart that missed one. A database never checked
+// is checked at once; one never compacted waits a full interval.
+func (h *Handler) maintainDatabase(ctx context.Context) {
+	db := h.settings.Database
+	every := maintenanceCheck
+	for _, d := range []time.Duration{db.CheckInterval, db.CompactInterval} {
+		if d > 0 {
+			every = min(every, d)
+		}
+	}
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		if h.maintenanceDue("check", db.CheckInterval, time.Time{}) {
+			h.checkDatabase()
+		}
+		if h.maintenanceDue("compact", db.CompactInterval, h.meta.server.StartedAt) {
+			h.compactDatabase()
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// maintenanceDue reports whether a task hasn't run for interval, counting
+// from first when it has never run
+func (h *Handler) maintenanceDue(task string, interval time.Duration, first time.Time) bool {
+	if interval <= 0 {
+		return false
+	}
+	if last := h.lastMaintenance(task); last != nil {
+		first = last.Time
+	}
+	return h.clock.Now().Sub(first) >= interval
+}
+
+func (h *Handler) lastMaintenance(task string) *models.MaintenanceRun {
+	var run models.MaintenanceRun
+	if ok, err := h.store.Get(maintenanceBucket, task, &run); err != nil || !ok {
+		return nil
+	}
+	return &run
+}
+
+// checkDatabase looks for inconsistencies in the store and records the outcome
+func (h *Handler) checkDatabase() models.MaintenanceRun {
+	h.maintainMu.Lock()
+	defer h.maintainMu.Unlock()
+
+	start := time.Now()
+	problems, err := h.store.Check()
+	run := models.MaintenanceRun{
+		Time:       h.clock.Now().UTC(),
+		DurationMS: milliseconds(time.Since(start)),
+		OK:         err == nil && len(problems) == 0,
+		Problems:   problems,
+	}
+	if err != nil {
+		run.Error = err.Error()
+	}
+
+	if run.OK {
+		h.logger.Printf("Database check passed in %.0f ms", run.DurationMS)
+	} else {
+		h.logger.Printf("Database check FAILED: %d problems, %s %v", len(problems), run.Error, problems)
+	}
+	h.recordMaintenance("check", run)
+	return run
+}
+
+// compactDatabase rewrites the store without free pages, unless the last check
+// found it inconsistent; copying a damaged database could lose more of it
+func (h *Handler) compactDatabase() models.MaintenanceRun {
+	h.maintainMu.Lock()
+	defer h.maintainMu.Unlock()
+
+	run := models.MaintenanceRun{Time: h.clock.Now().UTC()}
+	if check := h.lastMaintenance("check"); check != nil && !check.OK {
+		run.Error = "skipped: the last integrity check found problems"
+		h.logger.Printf("Database compaction %s", run.Error)
+		h.recordMaintenance("compact", run)
+		return run
+	}
+
+	start := time.Now()
+	before, after, err := h.store.Compact()
+	run.DurationMS = milliseconds(time.Since(start))
+	run.BytesBefore, run.BytesAfter = before, after
+	run.OK = err == nil
+	if err != nil {
+		run.Error = err.Error()
+		h.logger.Printf("Database compaction failed: %v", err)
+	} else {
+		h.logger.Printf("Database compacted from %d to %d bytes in %.0f ms", before, after, run.DurationMS)
+	}
+	h.recordMaintenance("compact", run)
+	return run
+}
+
+func (h *Handler) recordMaintenance(task string, run models.MaintenanceRun) {
+	if err := h.store.Put(maintenanceBucket, task, run); err != nil {
+		h.logger.Printf("Failed to record database %s: %v", task, err)
+	}
+}
+
+// databaseHealth reports the store's size and its latest maintenance for diagnostics
+func (h *Handler) databaseHealth() models.DatabaseHealth {
+	health := models.DatabaseHealth{
+		LastCheck:   h.lastMaintenance("check"),
+		LastCompact: h.lastMaintenance("compact"),
+	}
+	var err error
+	if health.SizeBytes, health.FreeBytes, err = h.store.Size(); err != nil {
+		health.Error = err.Error()
+	}
+	return health
+}
+
+// handleDatabaseCheck runs an integrity check now
+func (h *Handler) handleDatabaseCheck(w http.ResponseWriter, r *http.Request) {
+	run := h.checkDatabase()
+	h.audit(r, "database.check", "", "")
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "ok",
+		"check":  run,
+	})
+}
+
+// handleDatabaseCompact compacts the database now
+func (h *Handler) handleDatabaseCompact(w http.ResponseWriter, r *http.Request) {
+	run := h.compactDatabase()
+	h.audit(r, "database.compact", "", "")
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "ok",
+		"compact": run,
+	})
+}
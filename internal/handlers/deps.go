@@ -0,0 +1,139 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"context"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/wmarchesi123/go-3dprint-client/octoprint"
+	"github.com/wmarchesi123/go-3dprint-client/spoolman"
+	"github.com/wmarchesi123/octodash/internal/energy"
+	"github.com/wmarchesi123/octodash/internal/extensions"
+	"github.com/wmarchesi123/octodash/internal/octoapi"
+	"github.com/wmarchesi123/octodash/internal/store"
+)
+
+// PrinterClient is the go-3dprint-client OctoPrint API used for status and temperatures
+type PrinterClient interface {
+	GetPrinterState() (*octoprint.PrinterResponse, error)
+	GetJob() (*octoprint.JobResponse, error)
+	GetCurrentSpool(tool int) (string, error)
+	SetActiveSpool(spoolID string, tool int) error
+	SetToolTemperature(tool int, target float64) error
+	SetBedTemperature(target float64) error
+}
+
+// ControlClient is the OctoPrint API used for job control, files, backups, job history and maintenance checks
+type ControlClient interface {
+	SendCommands(commands ...string) error
+	EmergencyStop() error
+	Pause() error
+	Resume() error
+	Cancel() error
+	ConnectionState() (string, error)
+	Connect() error
+	ListFiles() ([]octoapi.File, error)
+	PrintFile(path string) error
+	UploadFile(ctx context.Context, name string, r io.Reader) error
+	DownloadFile(ctx context.Context, path string, w io.Writer) (int64, error)
+	CancelObject(id int) error
+	ExcludeObject(name string) error
+	ExcludeRegion(id string, minX, minY, maxX, maxY float64) error
+	CreateBackup() (string, error)
+	BackupState() (octoapi.BackupState, error)
+	DownloadBackup(ctx context.Context, name string, w io.Writer) (int64, error)
+	DeleteBackup(name string) error
+	Timelapses() ([]octoapi.Timelapse, error)
+	OpenTimelapse(ctx context.Context, name string) (io.ReadCloser, int64, error)
+	DeleteTimelapse(name string) error
+	SoftwareUpdates() ([]octoapi.SoftwareUpdate, error)
+	FirmwareVersion() (string, error)
+	Plugins() ([]octoapi.Plugin, error)
+	LayerProgress() (octoapi.LayerProgress, error)
+	JobHistory() ([]octoapi.HistoricJob, string, error)
+	RequestAppKey(app string) (octoapi.AppKeyRequest, error)
+	AppKeyDecision(token string) (string, error)
+}
+
+// SpoolmanClient is the Spoolman API used for spool lookups
+type SpoolmanClient interface {
+	GetSpool(id string) (*spoolman.Spool, error)
+	GetAllSpools() ([]spoolman.Spool, error)
+}
+
+// Clock supplies the current time
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// Option customizes a Handler built by NewHandler
+type Option func(*Handler)
+
+// WithPrinterClient overrides the status client for one printer
+func WithPrinterClient(printerID string, client PrinterClient) Option {
+	return func(h *Handler) { h.clients.Load().status[printerID] = client }
+}
+
+// WithControlClient overrides the control client for one printer
+func WithControlClient(printerID string, client ControlClient) Option {
+	return func(h *Handler) { h.clients.Load().control[printerID] = client }
+}
+
+// WithSpoolmanClient overrides the client for the Spoolman at SPOOLMAN_URL
+func WithSpoolmanClient(client SpoolmanClient) Option {
+	return func(h *Handler) { h.spoolmanClient = client }
+}
+
+// WithStore uses an already-open store instead of opening one in DataDir.
+// The handler takes ownership and closes it in Close.
+func WithStore(db *store.Store) Option {
+	return func(h *Handler) { h.store = db }
+}
+
+// WithEnergyReaders overrides the smart plug readers built from settings
+func WithEnergyReaders(readers map[string]energy.Reader) Option {
+	return func(h *Handler) { h.energyReaders = readers }
+}
+
+// WithExtensions uses the given extensions instead of every registered one
+func WithExtensions(exts ...extensions.Extension) Option {
+	return func(h *Handler) { h.extensions = append([]extensions.Extension{}, exts...) }
+}
+
+// WithClock overrides the time source
+func WithClock(clock Clock) Option {
+	return func(h *Handler) { h.clock = clock }
+}
+
+// WithLogger overrides the logger
+func WithLogger(logger *log.Logger) Option {
+	return func(h *Handler) { h.logger = logger }
+}
+
+func defaultLogger() *log.Logger {
+	return log.New(os.Stderr, "", log.LstdFlags)
+}
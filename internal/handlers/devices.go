@@ -0,0 +1,194 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+
+	"github.com/wmarchesi123/octodash/internal/auth"
+	"github.com/wmarchesi123/octodash/internal/devices"
+)
+
+// deviceUser signs paired devices in as viewers
+func (h *Handler) deviceUser(token string) (*auth.User, bool) {
+	device, ok := h.devices.Authenticate(token)
+	if !ok {
+		return nil, false
+	}
+	return &auth.User{Name: device.User, Role: auth.RoleViewer}, true
+}
+
+// handlePairRequest starts pairing a kiosk: it gets a code to show on screen
+// and a secret to poll with until an admin approves the code
+func (h *Handler) handlePairRequest(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Pairing is open to anyone, so limit how many codes each address holds
+	addr, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		addr = r.RemoteAddr
+	}
+	pairing, secret, err := h.devices.Request(req.Name, addr, h.clock.Now())
+	switch {
+	case errors.Is(err, devices.ErrInvalidName):
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	case errors.Is(err, devices.ErrBusy):
+		writeError(w, http.StatusTooManyRequests, err.Error())
+		return
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"status":     "ok",
+		"code":       pairing.Code,
+		"request":    secret,
+		"expires_at": pairing.ExpiresAt,
+	})
+}
+
+// handlePairPoll tells a waiting kiosk whether its code was approved, handing
+// over its token the first time it asks afterwards
+func (h *Handler) handlePairPoll(w http.ResponseWriter, r *http.Request) {
+	token, err := h.devices.Poll(r.PathValue("request"), h.clock.Now())
+	if errors.Is(err, devices.ErrNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if token == "" {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"status": "ok", "paired": false})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "ok",
+		"paired": true,
+		"token":  token,
+	})
+}
+
+// handleDevices lists paired devices and the codes waiting for approval
+func (h *Handler) handleDevices(w http.ResponseWriter, r *http.Request) {
+	list, err := h.devices.List()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "ok",
+		"devices": list,
+		"pending": h.devices.Pending(h.clock.Now()),
+	})
+}
+
+// handleDeviceApprove pairs the device showing the code in the request body
+func (h *Handler) handleDeviceApprove(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Code string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	user := auth.UserFromContext(r.Context())
+	device, err := h.devices.Approve(req.Code, user.Name, h.clock.Now())
+	if errors.Is(err, devices.ErrNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.audit(r, "device.pair", "", device.Name+" ("+device.ID+")")
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "ok",
+		"device": device,
+	})
+}
+
+// handleDeviceRevoke unpairs a device so its token stops working
+func (h *Handler) handleDeviceRevoke(w http.ResponseWriter, r *http.Request) {
+	device, err := h.devices.Revoke(r.PathValue("id"))
+	if errors.Is(err, devices.ErrNotFound) {
+		writeError(w, http.StatusNotFound, "Device not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.audit(r, "device.revoke", "", device.Name+" ("+device.ID+")")
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"status": "ok"})
+}
+
+// handlePairPage shows a pairing code on a kiosk and stores the token once
+// an admin approves it; admins approve codes from the same page
+func (h *Handler) handlePairPage(w http.ResponseWriter, r *http.Request) {
+	h.renderPage(w, "pair", pairTemplate, nil)
+}
+
+const pairTemplate = `<!DOCTYPE html>
+<html>
+<head>
+    <title>Pair a display - OctoDash</title>
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+    <link rel="stylesheet" href="{{asset "style.css"}}">
+</head>
+<body class="pair-page">
+    <section id="pair-device">
+        <h1>Pair this display</h1>
+        <form id="pair-start">
+            <label>Display name <input type="text" id="pair-name" value="display" pattern="[A-Za-z0-9][A-Za-z0-9._\-]*" required></label>
+            <button type="submit">Show pairing code</button>
+        </form>
+        <p class="pair-code" id="pair-code"></p>
+        <p id="pair-status"></p>
+    </section>
+    <section id="pair-approve">
+        <h2>Approve a display</h2>
+        <form id="pair-approve-form">
+            <label>Code shown on the display <input type="text" id="pair-approve-code" autocomplete="off" required></label>
+            <button type="submit">Approve</button>
+        </form>
+        <p id="pair-approve-result"></p>
+    </section>
+    <script src="{{asset "pair.js"}}"></script>
+</body>
+</html>
+`
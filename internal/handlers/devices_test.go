@@ -0,0 +1,174 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestDevicePairing(t *testing.T) {
+	t.Setenv("AUTH_USERS", "ada:admin:ada-token")
+	op := testutil.NewOctoPrint(t)
+	h := newTestHandler(t, testutil.NewSpoolman(t), testutil.Printer{Name: "Mini", Server: op})
+
+	do := func(token, method, path, body string) (*httptest.ResponseRecorder, map[string]interface{}) {
+		t.Helper()
+		rec := doAs(h, token, method, path, body)
+		var response map[string]interface{}
+		json.Unmarshal(rec.Body.Bytes(), &response)
+		return rec, response
+	}
+
+	if rec, _ := do("", "GET", "/pair", ""); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "/static/pair.") {
+		t.Errorf("pair page = %d", rec.Code)
+	}
+	if rec, _ := do("", "POST", "/api/pair", `{"name": "lobby tv"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("bad name = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	rec, pairing := do("", "POST", "/api/pair", `{"name": "lobby-tv"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("pair = %d: %s", rec.Code, rec.Body)
+	}
+	code, secret := pairing["code"].(string), pairing["request"].(string)
+
+	if _, poll := do("", "GET", "/api/pair/"+secret, ""); poll["paired"] != false {
+		t.Errorf("poll before approval = %v", poll)
+	}
+	if rec, _ := do("", "POST", "/api/admin/devices/pair", `{"code": "`+code+`"}`); rec.Code != http.StatusUnauthorized {
+		t.Errorf("anonymous approval = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+	typed := strings.ToLower(strings.ReplaceAll(code, "-", ""))
+	if rec, _ := do("ada-token", "POST", "/api/admin/devices/pair", `{"code": "`+typed+`"}`); rec.Code != http.StatusOK {
+		t.Fatalf("approve = %d: %s", rec.Code, rec.Body)
+	}
+
+	_, poll := do("", "GET", "/api/pair/"+secret, "")
+	token, _ := poll["token"].(string)
+	if poll["paired"] != true || token == "" {
+		t.Fatalf("poll after approval = %v", poll)
+	}
+	if rec, _ := do("", "GET", "/api/pair/"+secret, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("second poll = %d, want the token handed over only once", rec.Code)
+	}
+
+	if _, me := do(token, "GET", "/api/me", ""); me["user"] != "device/lobby-tv" || me["role"] != "viewer" {
+		t.Errorf("device identity = %v", me)
+	}
+	if rec, _ := do(token, "POST", "/api/printers/printer-1/pause", ""); rec.Code != http.StatusForbidden {
+		t.Errorf("device pause = %d, want read-only", rec.Code)
+	}
+
+	_, list := do("ada-token", "GET", "/api/admin/devices", "")
+	devices := list["devices"].([]interface{})
+	if len(devices) != 1 {
+		t.Fatalf("devices = %v", list)
+	}
+	id := devices[0].(map[string]interface{})["id"].(string)
+	if rec, _ := do("ada-token", "DELETE", "/api/admin/devices/"+id, ""); rec.Code != http.StatusOK {
+		t.Fatalf("revoke = %d: %s", rec.Code, rec.Body)
+	}
+	if _, me := do(token, "GET", "/api/me", ""); me["authenticated"] != false {
+		t.Errorf("revoked device still signs in: %v", me)
+	}
+}
+
+func TestPairingRequestsBounded(t *testing.T) {
+	t.Setenv("AUTH_USERS", "ada:admin:ada-token")
+	op := testutil.NewOctoPrint(t)
+	h := newTestHandler(t, testutil.NewSpoolman(t), testutil.Printer{Name: "Mini", Server: op})
+
+	pair := func(addr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/pair", strings.NewReader(`{"name": "kiosk"}`))
+		req.RemoteAddr = addr + ":40000"
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for i := 0; i < 5; i++ {
+		if rec := pair("198.51.100.1"); rec.Code != http.StatusCreated {
+			t.Fatalf("request %d = %d: %s", i, rec.Code, rec.Body)
+		}
+	}
+	if rec := pair("198.51.100.1"); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("sixth request from one address = %d, want 429", rec.Code)
+	}
+
+	// A flood from many addresses pushes out the oldest codes rather than
+	// locking out the next kiosk
+	for i := 0; i < 30; i++ {
+		if rec := pair(fmt.Sprintf("203.0.113.%d", i)); rec.Code != http.StatusCreated {
+			t.Fatalf("request from address %d = %d: %s", i, rec.Code, rec.Body)
+		}
+	}
+	var pending struct {
+		Pending []map[string]interface{} `json:"pending"`
+	}
+	json.Unmarshal(doAs(h, "ada-token", "GET", "/api/admin/devices", "").Body.Bytes(), &pending)
+	if len(pending.Pending) != 20 {
+		t.Errorf("%d requests pending, want 20", len(pending.Pending))
+	}
+	if rec := pair("198.51.100.1"); rec.Code != http.StatusCreated {
+		t.Errorf("request after the first address's codes were evicted = %d", rec.Code)
+	}
+}
+
+func TestPairedDeviceJoinsTeam(t *testing.T) {
+	t.Setenv("TEAMS_ENABLED", "true")
+	t.Setenv("AUTH_USERS", "ada:admin:ada-token")
+	h := newTestHandler(t, testutil.NewSpoolman(t),
+		testutil.Printer{Name: "Mini", Server: testutil.NewOctoPrint(t)},
+		testutil.Printer{Name: "MK4", Server: testutil.NewOctoPrint(t)})
+
+	var pairing struct {
+		Code    string `json:"code"`
+		Request string `json:"request"`
+	}
+	json.Unmarshal(doAs(h, "", "POST", "/api/pair", `{"name": "lab-tv"}`).Body.Bytes(), &pairing)
+	if rec := doAs(h, "ada-token", "PUT", "/api/admin/teams/robotics", `{"printers": ["printer-1"], "members": ["device/lab-tv"]}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("team with a device not yet paired = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if rec := doAs(h, "ada-token", "POST", "/api/admin/devices/pair", `{"code": "`+pairing.Code+`"}`); rec.Code != http.StatusOK {
+		t.Fatalf("approve = %d: %s", rec.Code, rec.Body)
+	}
+	var poll struct {
+		Token string `json:"token"`
+	}
+	json.Unmarshal(doAs(h, "", "GET", "/api/pair/"+pairing.Request, "").Body.Bytes(), &poll)
+
+	if rec := doAs(h, "ada-token", "PUT", "/api/admin/teams/robotics", `{"printers": ["printer-1"], "members": ["device/lab-tv"]}`); rec.Code != http.StatusOK {
+		t.Fatalf("team with a paired device = %d: %s", rec.Code, rec.Body)
+	}
+	var status struct {
+		Printers []struct {
+			ID string `json:"id"`
+		} `json:"printers"`
+	}
+	json.Unmarshal(doAs(h, poll.Token, "GET", "/api/status", "").Body.Bytes(), &status)
+	if len(status.Printers) != 1 || status.Printers[0].ID != "printer-1" {
+		t.Errorf("device sees %+v, want only its team's printer", status.Printers)
+	}
+}
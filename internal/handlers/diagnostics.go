@@ -0,0 +1,159 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/wmarchesi123/go-3dprint-client/config"
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/octoapi"
+)
+
+// pluginRequirement is an OctoPrint plugin an OctoDash feature depends on
+type pluginRequirement struct {
+	id       string
+	key      string
+	name     string
+	feature  string
+	required bool
+}
+
+// pluginRequirements lists the plugins checked by /api/printers/{id}/diagnostics
+var pluginRequirements = []pluginRequirement{
+	{id: "spoolman", key: "spoolman_api", name: "Spoolman connector", feature: "current spool and filament tracking", required: true},
+	{id: "thumbnails", key: "prusaslicerthumbnails", name: "Slicer Thumbnails", feature: "print thumbnails", required: true},
+	{id: "layer_progress", key: "DisplayLayerProgress", name: "DisplayLayerProgress", feature: "layer progress"},
+	{id: "exclude_region", key: "excluderegion", name: "Exclude Region", feature: "cancelling single objects on firmware without M486"},
+}
+
+func (h *Handler) handlePrinterDiagnostics(w http.ResponseWriter, r *http.Request) {
+	printer, ok := h.findPrinter(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Printer not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":      "ok",
+		"diagnostics": h.diagnose(printer),
+	})
+}
+
+// diagnose checks that OctoPrint is reachable and has the plugins OctoDash relies on
+func (h *Handler) diagnose(printer config.Printer) models.PrinterDiagnostics {
+	d := models.PrinterDiagnostics{PrinterID: printer.ID, Name: printer.Name, Healthy: true}
+	add := func(c models.DiagnosticCheck) {
+		if c.Required && c.Status != models.CheckOK && c.Status != models.CheckUnknown {
+			d.Healthy = false
+		}
+		d.Checks = append(d.Checks, c)
+	}
+
+	control := h.controlClient(printer.ID)
+
+	// Nothing else can be checked if OctoPrint itself is unreachable
+	state, err := control.ConnectionState()
+	if err != nil {
+		add(models.DiagnosticCheck{
+			ID:       "octoprint",
+			Name:     "OctoPrint API",
+			Required: true,
+			Status:   models.CheckError,
+			Detail:   err.Error(),
+			Fix:      fmt.Sprintf("Check that %s is reachable and that the API key is valid", printer.OctoPrintURL),
+		})
+		return d
+	}
+	add(models.DiagnosticCheck{ID: "octoprint", Name: "OctoPrint API", Required: true, Status: models.CheckOK})
+
+	connection := models.DiagnosticCheck{ID: "printer_connection", Name: "Printer connection", Status: models.CheckOK, Detail: state}
+	if state == "Closed" || state == "" {
+		connection.Status = models.CheckError
+		connection.Fix = "Connect the printer from OctoPrint's Connection panel"
+	}
+	add(connection)
+
+	plugins, err := control.Plugins()
+	if err != nil {
+		add(models.DiagnosticCheck{
+			ID:     "plugin_manager",
+			Name:   "Plugin list",
+			Status: models.CheckError,
+			Detail: err.Error(),
+			Fix:    "Use an API key with the Plugin Management permission so plugins can be checked",
+		})
+	}
+
+	installed := make(map[string]octoapi.Plugin, len(plugins))
+	for _, p := range plugins {
+		installed[p.Key] = p
+	}
+
+	for _, req := range pluginRequirements {
+		check := models.DiagnosticCheck{ID: req.id, Name: req.name, Plugin: req.key, Required: req.required, Status: models.CheckOK}
+
+		p, found := installed[req.key]
+		switch {
+		case err != nil:
+			check.Status = models.CheckUnknown
+			check.Detail = "Plugin list unavailable"
+		case !found:
+			check.Status = models.CheckMissing
+			check.Fix = fmt.Sprintf("Install the %s plugin from OctoPrint's Plugin Manager to enable %s", req.name, req.feature)
+		case p.Incompatible:
+			check.Status = models.CheckError
+			check.Version = p.Version
+			check.Detail = "Plugin is incompatible with this OctoPrint version"
+			check.Fix = fmt.Sprintf("Update the %s plugin or OctoPrint", req.name)
+		case !p.Enabled:
+			check.Status = models.CheckDisabled
+			check.Version = p.Version
+			check.Fix = fmt.Sprintf("Enable the %s plugin in OctoPrint's Plugin Manager to enable %s", req.name, req.feature)
+		default:
+			check.Version = p.Version
+		}
+
+		if req.id == "spoolman" && (check.Status == models.CheckOK || check.Status == models.CheckUnknown) {
+			h.probeSpoolman(printer, &check)
+		}
+		add(check)
+	}
+
+	return d
+}
+
+// probeSpoolman asks the connector for the current spool, which fails when it cannot reach Spoolman
+func (h *Handler) probeSpoolman(printer config.Printer, check *models.DiagnosticCheck) {
+	client := h.printerClient(printer.ID)
+	if client == nil {
+		return
+	}
+	if _, err := client.GetCurrentSpool(0); err != nil {
+		check.Status = models.CheckError
+		check.Detail = err.Error()
+		check.Fix = "Check the Spoolman URL in the connector plugin's settings and that Spoolman is running"
+		return
+	}
+
+	// A working connector answers for the plugin even when the list is unavailable
+	check.Status = models.CheckOK
+	check.Detail = ""
+}
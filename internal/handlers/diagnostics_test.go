@@ -0,0 +1,125 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/octoapi"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func getDiagnostics(t *testing.T, h *Handler, id string) (models.PrinterDiagnostics, map[string]models.DiagnosticCheck) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/api/printers/"+id+"/diagnostics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET diagnostics = %d: %s", rec.Code, rec.Body)
+	}
+
+	var response struct {
+		Diagnostics models.PrinterDiagnostics `json:"diagnostics"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+
+	checks := make(map[string]models.DiagnosticCheck)
+	for _, c := range response.Diagnostics.Checks {
+		checks[c.ID] = c
+	}
+	return response.Diagnostics, checks
+}
+
+func TestDiagnosticsHealthy(t *testing.T) {
+	sm := testutil.NewSpoolman(t)
+	op := testutil.NewOctoPrint(t)
+	h := newTestHandler(t, sm, testutil.Printer{Name: "Prusa", Server: op})
+
+	d, checks := getDiagnostics(t, h, "printer-1")
+	if !d.Healthy {
+		t.Errorf("diagnostics unhealthy: %+v", d.Checks)
+	}
+	for _, id := range []string{"octoprint", "printer_connection", "spoolman", "thumbnails", "layer_progress"} {
+		if checks[id].Status != models.CheckOK {
+			t.Errorf("check %s = %+v, want ok", id, checks[id])
+		}
+	}
+}
+
+func TestDiagnosticsReportsPluginProblems(t *testing.T) {
+	sm := testutil.NewSpoolman(t)
+	op := testutil.NewOctoPrint(t)
+	op.SetPlugins(
+		octoapi.Plugin{Key: "spoolman_api", Name: "Spoolman API", Enabled: true},
+		octoapi.Plugin{Key: "DisplayLayerProgress", Name: "DisplayLayerProgress", Enabled: false},
+	)
+	op.SetSpoolmanError("Spoolman is not reachable")
+	h := newTestHandler(t, sm, testutil.Printer{Name: "Prusa", Server: op})
+
+	d, checks := getDiagnostics(t, h, "printer-1")
+	if d.Healthy {
+		t.Error("diagnostics healthy despite missing plugins")
+	}
+
+	want := map[string]string{
+		"spoolman":       models.CheckError,
+		"thumbnails":     models.CheckMissing,
+		"layer_progress": models.CheckDisabled,
+	}
+	for id, status := range want {
+		c := checks[id]
+		if c.Status != status {
+			t.Errorf("check %s status = %q, want %q", id, c.Status, status)
+		}
+		if c.Fix == "" {
+			t.Errorf("check %s has no fix", id)
+		}
+	}
+	if checks["spoolman"].Detail == "" {
+		t.Error("spoolman check does not say why it failed")
+	}
+}
+
+func TestDiagnosticsUnreachable(t *testing.T) {
+	sm := testutil.NewSpoolman(t)
+	op := testutil.NewOctoPrint(t)
+	op.SetFault(testutil.Fault{StatusCode: http.StatusForbidden})
+	h := newTestHandler(t, sm, testutil.Printer{Name: "Prusa", Server: op})
+
+	d, checks := getDiagnostics(t, h, "printer-1")
+	if d.Healthy || len(d.Checks) != 1 || checks["octoprint"].Status != models.CheckError {
+		t.Errorf("diagnostics = %+v, want a single failed OctoPrint check", d)
+	}
+}
+
+func TestDiagnosticsUnknownPrinter(t *testing.T) {
+	h := newTestHandler(t, testutil.NewSpoolman(t))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/api/printers/printer-9/diagnostics", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("GET diagnostics for unknown printer = %d, want 404", rec.Code)
+	}
+}
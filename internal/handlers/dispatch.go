@@ -0,0 +1,172 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+)
+
+// dispatchQueue starts queued jobs on idle printers every interval
+func (h *Handler) dispatchQueue(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			h.dispatchOnce()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// dispatchOnce hands queued jobs, in queue order, to idle printers that fit their
+// print profile once their scheduled start comes round. A printer showing a
+// finished job isn't idle, so it gets nothing until someone confirms its bed is
+// clear; continuous-mode printers become idle again once they have ejected.
+// Printers with a pre-print checklist also wait for it to be ticked off.
+func (h *Handler) dispatchOnce() {
+	now := h.clock.Now()
+	h.runEjects(now)
+
+	entries, err := h.queue.List()
+	if err != nil {
+		h.logger.Printf("Failed to read queue: %v", err)
+		return
+	}
+
+	var waiting []models.QueueEntry
+	for _, e := range entries {
+		if e.Status == models.QueueQueued && e.Approval != models.ApprovalPending && h.startable(e, now) {
+			waiting = append(waiting, e)
+		}
+	}
+	if len(waiting) == 0 {
+		return
+	}
+
+	ready := make(map[string]bool)
+	statuses := make(map[string]*models.PrinterStatus)
+	for _, p := range h.collectStatuses(h.config.Printers) {
+		statuses[p.ID] = p
+		if p.Status == "idle" && h.checklistPassed(p.ID) {
+			ready[p.ID] = true
+		}
+	}
+
+	// With tenancy on, team entries only run on their own team's printers
+	var owners map[string]string
+	if h.settings.Teams.Enabled {
+		var err error
+		if owners, err = h.teams.Owners(); err != nil {
+			h.logger.Printf("Failed to load teams: %v", err)
+			return
+		}
+	}
+
+	// A reserved printer only takes jobs from the user holding it
+	holders, err := h.reservations.Holders(now)
+	if err != nil {
+		h.logger.Printf("Failed to load reservations: %v", err)
+		return
+	}
+
+	for _, e := range waiting {
+		allowed := func(id string) bool {
+			if holder := holders[id]; holder != "" && holder != e.SubmittedBy {
+				return false
+			}
+			return e.Team == "" || owners[id] == e.Team
+		}
+		fits := allowed
+		if e.PrintProfile != "" {
+			profile, err := h.profiles.Get(e.PrintProfile)
+			if err != nil {
+				h.logger.Printf("Skipping queue entry %s: print profile %s: %v", e.ID, e.PrintProfile, err)
+				continue
+			}
+			fits = func(id string) bool { return allowed(id) && h.profileFits(profile, id, statuses[id]) }
+		}
+
+		id := e.PrinterID
+		if id == "" {
+			id = h.firstReady(ready, fits)
+		}
+		if id == "" || !ready[id] || !fits(id) {
+			continue
+		}
+
+		delete(ready, id)
+		printer, _ := h.findPrinter(id)
+		if e.Gcode != "" {
+			if err := h.uploadGcode(id, e); err != nil {
+				h.logger.Printf("Failed to upload %s to %s: %v", e.File, printer.Name, err)
+				continue
+			}
+		}
+		_, err := h.commands.run(id, "print", e.File, "octodash", func() error {
+			return h.controlClient(id).PrintFile(e.File)
+		})
+		if err != nil {
+			h.logger.Printf("Failed to start %s on %s: %v", e.File, printer.Name, err)
+			continue
+		}
+		dispatched, err := h.queue.Dispatch(e.ID, id, h.clock.Now())
+		if err != nil {
+			h.logger.Printf("Failed to mark queue entry %s dispatched: %v", e.ID, err)
+		} else {
+			h.emit(models.EventQueueDispatched, id, dispatched)
+		}
+		h.logger.Printf("Started queued job %s on %s", e.File, printer.Name)
+		if len(h.settings.Printers[id].Checklist) > 0 {
+			h.audit(nil, "queue.dispatch", id, fmt.Sprintf("Started %s after the pre-print checklist", e.File))
+			h.resetChecklist(id)
+		}
+	}
+}
+
+// firstReady returns the first ready printer that fits, in configuration order
+func (h *Handler) firstReady(ready map[string]bool, fits func(id string) bool) string {
+	for _, p := range h.config.Printers {
+		if ready[p.ID] && fits(p.ID) {
+			return p.ID
+		}
+	}
+	return ""
+}
+
+// uploadGcode sends gcode OctoDash sliced itself to the printer about to run it
+func (h *Handler) uploadGcode(printerID string, e models.QueueEntry) error {
+	f, err := os.Open(filepath.Join(h.settings.Queue.ModelDir, e.Gcode))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	ctx, cancel := context.WithTimeout(h.ctx, 5*time.Minute)
+	defer cancel()
+	return h.controlClient(printerID).UploadFile(ctx, e.File, f)
+}
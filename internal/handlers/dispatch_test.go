@@ -0,0 +1,102 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestDispatchWaitsForBedClear(t *testing.T) {
+	sm := testutil.NewSpoolman(t)
+	op := testutil.NewOctoPrint(t)
+	op.SetPrinting("benchy.gcode", 97, 60)
+	h := newTestHandler(t, sm, testutil.Printer{Name: "Mini", Server: op})
+
+	getStatus(t, h)
+	op.SetFinished("benchy.gcode")
+	getStatus(t, h)
+
+	first, err := h.queue.Add(models.QueueEntry{File: "gear.gcode"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.queue.Add(models.QueueEntry{File: "clip.gcode", PrinterID: "printer-1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The finished part is still on the bed
+	h.dispatchOnce()
+	if printed := op.Printed(); len(printed) != 0 {
+		t.Fatalf("dispatched %v before the bed was cleared", printed)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("POST", "/api/printers/printer-1/acknowledge", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("acknowledge = %d", rec.Code)
+	}
+
+	// One job per idle printer, oldest first
+	h.dispatchOnce()
+	if printed := op.Printed(); !slices.Equal(printed, []string{"gear.gcode"}) {
+		t.Fatalf("printed = %v, want [gear.gcode]", printed)
+	}
+	entry, err := h.queue.Get(first.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Status != models.QueueDispatched || entry.DispatchedTo != "printer-1" || entry.DispatchedAt == nil {
+		t.Errorf("dispatched entry = %+v", entry)
+	}
+
+	// Busy printing, so the second job waits
+	h.dispatchOnce()
+	if printed := op.Printed(); len(printed) != 1 {
+		t.Errorf("printed = %v while the printer was busy", printed)
+	}
+}
+
+func TestDispatchAutoClear(t *testing.T) {
+	t.Setenv("PRINTER_1_AUTO_CLEAR", "true")
+
+	sm := testutil.NewSpoolman(t)
+	op := testutil.NewOctoPrint(t)
+	op.SetPrinting("belt_parts.gcode", 97, 60)
+	h := newTestHandler(t, sm, testutil.Printer{Name: "Belt", Server: op})
+
+	getStatus(t, h)
+	op.SetFinished("belt_parts.gcode")
+	if p := getStatus(t, h)["printer-1"]; p.Status != "idle" {
+		t.Errorf("auto-clear printer after finishing = %s, want idle", p.Status)
+	}
+
+	if _, err := h.queue.Add(models.QueueEntry{File: "belt_parts.gcode"}); err != nil {
+		t.Fatal(err)
+	}
+	h.dispatchOnce()
+	if printed := op.Printed(); len(printed) != 1 {
+		t.Errorf("printed = %v, want the queued job started", printed)
+	}
+}
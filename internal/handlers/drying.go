@@ -0,0 +1,190 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/wmarchesi123/go-3dprint-client/spoolman"
+	"github.com/wmarchesi123/octodash/internal/auth"
+	"github.com/wmarchesi123/octodash/internal/drying"
+	"github.com/wmarchesi123/octodash/internal/models"
+)
+
+// hygroscopic reports whether a material is one whose drying is tracked
+func (h *Handler) hygroscopic(material string) bool {
+	material = strings.ToUpper(strings.TrimSpace(material))
+	for _, prefix := range h.settings.Drying.Materials {
+		if strings.HasPrefix(material, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// addDryingInfo marks a hygroscopic spool's API info with when it was last dried
+func (h *Handler) addDryingInfo(info map[string]interface{}, src spoolSource, spool *spoolman.Spool) {
+	if !h.hygroscopic(spool.Filament.Material) {
+		return
+	}
+	info["hygroscopic"] = true
+
+	last, err := h.drying.Latest(src.name, info["id"].(string))
+	if err != nil || last == nil {
+		return
+	}
+	if last.EndedAt == nil {
+		info["drying"] = true
+		return
+	}
+	info["last_dried"] = *last.EndedAt
+}
+
+// dryingSpool resolves the spool named by the path and ?spoolman=, writing an error if it doesn't exist
+func (h *Handler) dryingSpool(w http.ResponseWriter, id, instance string) (spoolSource, bool) {
+	src, ok := h.spoolSource(instance)
+	if !ok {
+		writeError(w, http.StatusNotFound, "Unknown Spoolman instance")
+		return src, false
+	}
+	if _, err := src.client.GetSpool(id); err != nil {
+		writeError(w, http.StatusNotFound, "Spool not found")
+		return src, false
+	}
+	return src, true
+}
+
+func (h *Handler) handleDryingList(w http.ResponseWriter, r *http.Request) {
+	src, ok := h.spoolSource(r.URL.Query().Get("spoolman"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Unknown Spoolman instance")
+		return
+	}
+
+	sessions, err := h.drying.List(src.name, r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":   "ok",
+		"sessions": sessions,
+	})
+}
+
+// handleDryingStart starts a drying session, or records a finished one when
+// duration_minutes is given (optionally with started_at)
+func (h *Handler) handleDryingStart(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Spoolman        string     `json:"spoolman"`
+		Temperature     float64    `json:"temperature"`
+		DurationMinutes int        `json:"duration_minutes"`
+		StartedAt       *time.Time `json:"started_at"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Temperature <= 0 || req.Temperature > 150 {
+		writeError(w, http.StatusBadRequest, "Temperature must be between 0 and 150")
+		return
+	}
+	if req.DurationMinutes < 0 {
+		writeError(w, http.StatusBadRequest, "Invalid duration")
+		return
+	}
+
+	id := r.PathValue("id")
+	src, ok := h.dryingSpool(w, id, req.Spoolman)
+	if !ok {
+		return
+	}
+
+	now := h.clock.Now().UTC()
+	session := models.DryingSession{
+		Spoolman:    src.name,
+		SpoolID:     id,
+		Temperature: req.Temperature,
+		StartedAt:   now,
+		RecordedBy:  auth.UserFromContext(r.Context()).Name,
+	}
+
+	var err error
+	if req.DurationMinutes > 0 {
+		session.DurationMinutes = req.DurationMinutes
+		session.StartedAt = now.Add(-time.Duration(req.DurationMinutes) * time.Minute)
+		if req.StartedAt != nil {
+			session.StartedAt = *req.StartedAt
+		}
+		session, err = h.drying.Record(session)
+	} else {
+		session, err = h.drying.Start(session)
+	}
+	if errors.Is(err, drying.ErrActive) {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"status":  "ok",
+		"session": session,
+	})
+}
+
+func (h *Handler) handleDryingStop(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Spoolman string `json:"spoolman"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	}
+
+	src, ok := h.spoolSource(req.Spoolman)
+	if !ok {
+		writeError(w, http.StatusNotFound, "Unknown Spoolman instance")
+		return
+	}
+
+	session, err := h.drying.Stop(src.name, r.PathValue("id"), h.clock.Now())
+	if errors.Is(err, drying.ErrNotDrying) {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "ok",
+		"session": session,
+	})
+}
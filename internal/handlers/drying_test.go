@@ -0,0 +1,94 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestDryingLog(t *testing.T) {
+	sm := testutil.NewSpoolman(t, testutil.Spool(3, "Prusament", "PETG", "ff0000", 700))
+	op := testutil.NewOctoPrint(t)
+	op.SetSpool("3")
+	clock := &stepClock{now: time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)}
+	h := newHandlerWithConfig(t, testutil.Config(sm, testutil.Printer{Name: "Mini", Server: op}), WithClock(clock))
+
+	post := func(path, body string) int {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("POST", path, strings.NewReader(body)))
+		return rec.Code
+	}
+	// Each poll moves the clock on a minute
+	spool := func() map[string]interface{} {
+		clock.advance(time.Minute)
+		return getStatus(t, h)["printer-1"].CurrentSpool
+	}
+
+	if s := spool(); s["hygroscopic"] != true || s["last_dried"] != nil || s["drying"] != nil {
+		t.Errorf("spool before drying = %v, want hygroscopic and never dried", s)
+	}
+
+	if code := post("/api/spools/3/drying", `{"temperature": 65}`); code != http.StatusCreated {
+		t.Fatalf("start drying = %d", code)
+	}
+	if code := post("/api/spools/3/drying", `{"temperature": 65}`); code != http.StatusConflict {
+		t.Errorf("second start = %d, want %d", code, http.StatusConflict)
+	}
+	if s := spool(); s["drying"] != true {
+		t.Errorf("spool while drying = %v", s)
+	}
+
+	clock.advance(4 * time.Hour)
+	if code := post("/api/spools/3/drying/stop", ""); code != http.StatusOK {
+		t.Fatalf("stop drying = %d", code)
+	}
+	if code := post("/api/spools/3/drying/stop", ""); code != http.StatusConflict {
+		t.Errorf("second stop = %d, want %d", code, http.StatusConflict)
+	}
+	if s := spool(); s["drying"] != nil || s["last_dried"] != "2025-03-01T13:02:00Z" {
+		t.Errorf("spool after drying = %v", s)
+	}
+
+	if code := post("/api/spools/3/drying", `{"temperature": 60, "duration_minutes": 120}`); code != http.StatusCreated {
+		t.Fatalf("record drying = %d", code)
+	}
+	if code := post("/api/spools/99/drying", `{"temperature": 60}`); code != http.StatusNotFound {
+		t.Errorf("drying an unknown spool = %d, want %d", code, http.StatusNotFound)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/api/spools/3/drying", nil))
+	var response struct {
+		Sessions []models.DryingSession `json:"sessions"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Sessions) != 2 || response.Sessions[0].DurationMinutes != 120 || response.Sessions[1].DurationMinutes != 241 {
+		t.Errorf("sessions = %+v, want the recorded 120 minute session before the 241 minute one", response.Sessions)
+	}
+}
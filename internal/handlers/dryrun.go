@@ -0,0 +1,255 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/wmarchesi123/go-3dprint-client/config"
+	"github.com/wmarchesi123/octodash/internal/auth"
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/octoapi"
+)
+
+// isDryRun reports whether a request asks with ?dry_run=1 to be checked and
+// described rather than carried out
+func isDryRun(r *http.Request) bool {
+	v := r.URL.Query().Get("dry_run")
+	return v == "1" || v == "true"
+}
+
+// writeDryRun answers a dry run. required is the role the action needs when
+// it is stricter than the route's own, or 0 to use the route's.
+func writeDryRun(w http.ResponseWriter, r *http.Request, action string, required auth.Role, targets []models.DryRunTarget) {
+	user := auth.UserFromContext(r.Context())
+	if required == 0 {
+		required = auth.RequiredRole(r.Context())
+	}
+
+	report := models.DryRun{
+		Action:  action,
+		User:    user.Name,
+		Role:    user.Role.String(),
+		Allowed: user.Role >= required,
+		Targets: targets,
+	}
+	if !report.Allowed && len(targets) > 0 {
+		// Printer grants count when every target is a printer the user has
+		report.Allowed = true
+		for _, t := range targets {
+			report.Allowed = report.Allowed && user.Allowed(required, t.PrinterID)
+		}
+	}
+	if required > 0 {
+		report.RequiredRole = required.String()
+	}
+	if report.Targets == nil {
+		report.Targets = []models.DryRunTarget{}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "ok",
+		"dry_run": report,
+	})
+}
+
+// dryRunPrinters previews fn on each printer and answers with the requests it
+// would send them. Printers where fn fails are reported as skipped.
+func (h *Handler) dryRunPrinters(w http.ResponseWriter, r *http.Request, action string, required auth.Role, printers []config.Printer, fn func(config.Printer, PrinterClient, ControlClient) error) {
+	targets := make([]models.DryRunTarget, 0, len(printers))
+	for _, p := range printers {
+		calls, err := h.previewPrinter(p, func(status PrinterClient, control ControlClient) error {
+			return fn(p, status, control)
+		})
+		target := models.DryRunTarget{PrinterID: p.ID, Name: p.Name, Upstream: calls}
+		if err != nil {
+			target.Skipped = err.Error()
+		}
+		targets = append(targets, target)
+	}
+	writeDryRun(w, r, action, required, targets)
+}
+
+// previewPrinter runs fn against stand-ins for a printer's clients that pass
+// reads through to OctoPrint but only record changes, returning the requests
+// fn would have sent along with its error
+func (h *Handler) previewPrinter(p config.Printer, fn func(PrinterClient, ControlClient) error) ([]string, error) {
+	rec := &octoapi.Recorder{}
+	base := strings.TrimRight(p.OctoPrintURL, "/")
+	status := previewStatus{PrinterClient: h.printerClient(p.ID), base: base, rec: rec}
+	control := previewControl{reads: h.controlClient(p.ID), writes: octoapi.NewRecording(base, rec)}
+
+	err := fn(status, control)
+	return rec.Calls(), err
+}
+
+// previewStatus records the temperature and spool changes a PrinterClient would make
+type previewStatus struct {
+	PrinterClient
+	base string
+	rec  *octoapi.Recorder
+}
+
+func (p previewStatus) record(path string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	p.rec.Record("POST " + p.base + path + " " + string(body))
+	return nil
+}
+
+func (p previewStatus) SetActiveSpool(spoolID string, tool int) error {
+	return p.record("/api/plugin/spoolman_api", map[string]interface{}{"command": "set_spool", "spool_id": spoolID, "tool": tool})
+}
+
+func (p previewStatus) SetToolTemperature(tool int, target float64) error {
+	return p.record("/api/printer/tool", map[string]interface{}{"command": "target", "targets": map[string]float64{fmt.Sprintf("tool%d", tool): target}})
+}
+
+func (p previewStatus) SetBedTemperature(target float64) error {
+	return p.record("/api/printer/bed", map[string]interface{}{"command": "target", "target": target})
+}
+
+// previewControl sends a ControlClient's changes to a recording client while
+// reads still go to the printer. Every method is spelled out rather than
+// embedding the live client so a method added to ControlClient cannot reach
+// the printer from a preview unnoticed.
+type previewControl struct {
+	reads  ControlClient
+	writes *octoapi.Client
+}
+
+func (p previewControl) ConnectionState() (string, error) {
+	return p.reads.ConnectionState()
+}
+
+func (p previewControl) ListFiles() ([]octoapi.File, error) {
+	return p.reads.ListFiles()
+}
+
+func (p previewControl) DownloadFile(ctx context.Context, path string, w io.Writer) (int64, error) {
+	return p.reads.DownloadFile(ctx, path, w)
+}
+
+func (p previewControl) BackupState() (octoapi.BackupState, error) {
+	return p.reads.BackupState()
+}
+
+func (p previewControl) DownloadBackup(ctx context.Context, name string, w io.Writer) (int64, error) {
+	return p.reads.DownloadBackup(ctx, name, w)
+}
+
+func (p previewControl) Timelapses() ([]octoapi.Timelapse, error) {
+	return p.reads.Timelapses()
+}
+
+func (p previewControl) OpenTimelapse(ctx context.Context, name string) (io.ReadCloser, int64, error) {
+	return p.reads.OpenTimelapse(ctx, name)
+}
+
+func (p previewControl) SoftwareUpdates() ([]octoapi.SoftwareUpdate, error) {
+	return p.reads.SoftwareUpdates()
+}
+
+func (p previewControl) FirmwareVersion() (string, error) {
+	return p.reads.FirmwareVersion()
+}
+
+func (p previewControl) Plugins() ([]octoapi.Plugin, error) {
+	return p.reads.Plugins()
+}
+
+func (p previewControl) LayerProgress() (octoapi.LayerProgress, error) {
+	return p.reads.LayerProgress()
+}
+
+func (p previewControl) JobHistory() ([]octoapi.HistoricJob, string, error) {
+	return p.reads.JobHistory()
+}
+
+func (p previewControl) SendCommands(commands ...string) error {
+	return p.writes.SendCommands(commands...)
+}
+
+func (p previewControl) EmergencyStop() error {
+	return p.writes.EmergencyStop()
+}
+
+func (p previewControl) Pause() error {
+	return p.writes.Pause()
+}
+
+func (p previewControl) Resume() error {
+	return p.writes.Resume()
+}
+
+func (p previewControl) Cancel() error {
+	return p.writes.Cancel()
+}
+
+func (p previewControl) Connect() error {
+	return p.writes.Connect()
+}
+
+func (p previewControl) PrintFile(path string) error {
+	return p.writes.PrintFile(path)
+}
+
+func (p previewControl) CancelObject(id int) error {
+	return p.writes.CancelObject(id)
+}
+
+func (p previewControl) ExcludeObject(name string) error {
+	return p.writes.ExcludeObject(name)
+}
+
+func (p previewControl) CreateBackup() (string, error) {
+	return p.writes.CreateBackup()
+}
+
+func (p previewControl) DeleteBackup(name string) error {
+	return p.writes.DeleteBackup(name)
+}
+
+func (p previewControl) DeleteTimelapse(name string) error {
+	return p.writes.DeleteTimelapse(name)
+}
+
+func (p previewControl) UploadFile(ctx context.Context, name string, r io.Reader) error {
+	return p.writes.UploadFile(ctx, name, r)
+}
+
+func (p previewControl) ExcludeRegion(id string, minX, minY, maxX, maxY float64) error {
+	return p.writes.ExcludeRegion(id, minX, minY, maxX, maxY)
+}
+
+func (p previewControl) RequestAppKey(app string) (octoapi.AppKeyRequest, error) {
+	return p.writes.RequestAppKey(app)
+}
+
+// AppKeyDecision is a read, but collecting an approved key uses it up
+func (p previewControl) AppKeyDecision(token string) (string, error) {
+	return p.writes.AppKeyDecision(token)
+}
@@ -0,0 +1,132 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestDryRun(t *testing.T) {
+	op := testutil.NewOctoPrint(t)
+	idle := testutil.NewOctoPrint(t)
+	h := newTestHandler(t, testutil.NewSpoolman(t),
+		testutil.Printer{Name: "Mini", Server: op},
+		testutil.Printer{Name: "Ender", Server: idle})
+	op.SetPrinting("gear.gcode", 40, 1200)
+
+	preview := func(rec *httptest.ResponseRecorder) models.DryRun {
+		t.Helper()
+		if rec.Code != http.StatusOK {
+			t.Fatalf("dry run = %d: %s", rec.Code, rec.Body)
+		}
+		var response struct {
+			DryRun models.DryRun `json:"dry_run"`
+		}
+		json.NewDecoder(rec.Body).Decode(&response)
+		return response.DryRun
+	}
+
+	report := preview(postJSON(h, "/api/printers/printer-1/pause?dry_run=1", ""))
+	if !report.Allowed || report.RequiredRole != "operator" || len(report.Targets) != 1 {
+		t.Fatalf("pause report = %+v", report)
+	}
+	if calls := report.Targets[0].Upstream; len(calls) != 1 || !strings.HasPrefix(calls[0], "POST ") ||
+		!strings.Contains(calls[0], "/api/job ") || !strings.Contains(calls[0], `"command":"pause"`) {
+		t.Errorf("pause upstream = %v", calls)
+	}
+	if n := op.Requests("POST /api/job"); n != 0 {
+		t.Errorf("dry run sent %d job commands", n)
+	}
+
+	report = preview(postJSON(h, "/api/bulk/preheat?dry_run=1", `{"hotend": 215, "bed": 60}`))
+	if len(report.Targets) != 2 {
+		t.Fatalf("preheat targets = %+v", report.Targets)
+	}
+	if report.Targets[0].Skipped == "" {
+		t.Errorf("printing printer was not skipped: %+v", report.Targets[0])
+	}
+	if calls := report.Targets[1].Upstream; len(calls) != 2 || !strings.Contains(calls[0], `"tool0":215`) || !strings.Contains(calls[1], `"target":60`) {
+		t.Errorf("preheat upstream = %v", calls)
+	}
+	if n := idle.Requests("POST /api/printer/tool") + idle.Requests("POST /api/printer/bed"); n != 0 {
+		t.Errorf("dry run sent %d temperature commands", n)
+	}
+
+	// No confirmation token is needed just to look
+	report = preview(postJSON(h, "/api/emergency-stop?dry_run=1", ""))
+	if len(report.Targets) != 2 || len(op.Commands()) != 0 || len(idle.Commands()) != 0 {
+		t.Errorf("emergency stop report = %+v, commands %v %v", report, op.Commands(), idle.Commands())
+	}
+
+	entry, err := h.queue.Add(models.QueueEntry{File: "clip.gcode"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("DELETE", "/api/queue/"+entry.ID+"?dry_run=1", nil))
+	if report := preview(rec); len(report.Targets) != 1 || !strings.Contains(report.Targets[0].Change, entry.ID) {
+		t.Errorf("queue remove report = %+v", report)
+	}
+	if entries, _ := h.queue.List(); len(entries) != 1 {
+		t.Errorf("dry run removed the entry: %+v", entries)
+	}
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("DELETE", "/api/queue/missing?dry_run=1", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unknown entry dry run = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestPreviewControl(t *testing.T) {
+	op := testutil.NewOctoPrint(t)
+	h := newTestHandler(t, testutil.NewSpoolman(t), testutil.Printer{Name: "Mini", Server: op})
+
+	calls, err := h.previewPrinter(h.config.Printers[0], func(_ PrinterClient, control ControlClient) error {
+		if state, err := control.ConnectionState(); err != nil || state == "" {
+			t.Errorf("ConnectionState = %q, %v; want the printer's", state, err)
+		}
+		if _, err := control.Timelapses(); err != nil {
+			t.Errorf("Timelapses: %v", err)
+		}
+		control.RequestAppKey("OctoDash")
+		control.AppKeyDecision("app-token")
+		return control.Pause()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(calls) != 3 || !strings.HasPrefix(calls[0], "POST ") || !strings.Contains(calls[0], "/plugin/appkeys/request") ||
+		!strings.HasPrefix(calls[1], "GET ") || !strings.HasPrefix(calls[2], "POST ") {
+		t.Errorf("recorded calls = %q", calls)
+	}
+	if n := op.Requests("GET /api/connection") + op.Requests("GET /api/timelapse"); n < 2 {
+		t.Errorf("%d reads reached the printer, want both", n)
+	}
+	if n := op.Requests("POST /plugin/appkeys/request") + op.Requests("GET /plugin/appkeys/request/app-token") + op.Requests("POST /api/job"); n != 0 {
+		t.Errorf("%d writes reached the printer from a preview", n)
+	}
+}
@@ -0,0 +1,232 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/eink"
+	"github.com/wmarchesi123/octodash/internal/models"
+)
+
+// maxEinkSize bounds each side of the image so a bad request can't allocate much
+const maxEinkSize = 2048
+
+// handleEink renders the farm summary as a grayscale PNG or BMP for e-ink
+// panels and microcontrollers that can fetch an image but can't run a
+// browser. width, height, depth and format override the EINK_* defaults,
+// and tags filters printers like the other summary endpoints.
+func (h *Handler) handleEink(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	cfg := h.settings.Eink
+
+	width, height, depth := cfg.Width, cfg.Height, cfg.Depth
+	for _, param := range []struct {
+		name string
+		dst  *int
+	}{{"width", &width}, {"height", &height}, {"depth", &depth}} {
+		raw := q.Get(param.name)
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid %s", param.name))
+			return
+		}
+		*param.dst = n
+	}
+	if width < 16 || height < 16 || width > maxEinkSize || height > maxEinkSize {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Width and height must be between 16 and %d", maxEinkSize))
+		return
+	}
+	if !eink.ValidDepth(depth) {
+		writeError(w, http.StatusBadRequest, "Depth must be 1, 2, 4 or 8")
+		return
+	}
+	format := cfg.Format
+	if f := q.Get("format"); f != "" {
+		format = f
+	}
+	if format != "png" && format != "bmp" {
+		writeError(w, http.StatusBadRequest, "Format must be png or bmp")
+		return
+	}
+
+	printers := h.collectStatuses(h.printersMatching(r, q.Get("tags")))
+	canvas := eink.NewCanvas(width, height)
+	drawFarm(canvas, printers, h.clock.Now().In(h.settings.Timezone))
+
+	var buf bytes.Buffer
+	if err := eink.Encode(&buf, canvas, depth, format); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// Displays poll on their own schedule; never let a proxy hand back a stale frame
+	w.Header().Set("Content-Type", eink.ContentType(format))
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write(buf.Bytes())
+}
+
+// drawFarm lays out a header with the farm's totals and one row per printer,
+// scaling text to fill the canvas from small badges up to 7.5" panels
+func drawFarm(c *eink.Canvas, printers []*models.PrinterStatus, now time.Time) {
+	width, height := c.Width(), c.Height()
+	margin := clamp(width/80, 2, 12)
+
+	// Header: title on the left, printing count and clock on the right
+	hs := clamp(min(width/200, height/120), 1, 4)
+	printing := 0
+	for _, p := range printers {
+		if p.Status == "printing" {
+			printing++
+		}
+	}
+	right := fmt.Sprintf("%d/%d printing  %s", printing, len(printers), now.Format("15:04"))
+	c.TextRight(width-margin, margin, right, hs, eink.Black)
+	title := eink.Fit("OctoDash", hs, width-3*margin-eink.TextWidth(right, hs))
+	c.Text(margin, margin, title, hs, eink.Black)
+
+	y := margin + eink.TextHeight(hs) + margin
+	rule := max(1, hs/2)
+	c.Fill(0, y, width, rule, eink.Black)
+	y += rule
+
+	if len(printers) == 0 {
+		c.Text(margin, y+margin, "No printers", hs, eink.Dark)
+		return
+	}
+
+	// Rows get two lines (status, then progress) when there's room, else
+	// one; printers that still don't fit are summed up on the last row
+	avail := height - y
+	rows := len(printers)
+	if limit := avail / (eink.TextHeight(1) + 2); rows > limit {
+		rows = max(limit, 1)
+	}
+	shown := printers
+	if rows < len(printers) {
+		shown = printers[:rows-1]
+	}
+	rowH := avail / rows
+	twoLine := rowH >= 20
+	lines := 1
+	if twoLine {
+		lines = 2
+	}
+	// Keep room for about 24 characters across, enough for a name and a status
+	s := clamp(min(rowH/(10*lines), width/(24*6)), 1, 4)
+
+	for i, p := range shown {
+		top := y + i*rowH
+		if i > 0 {
+			c.Fill(margin, top, width-2*margin, 1, eink.Dark)
+		}
+		line := top + (rowH-eink.TextHeight(s))/2
+		if twoLine {
+			line = top + (rowH/2-eink.TextHeight(s))/2 + s
+		}
+		drawPrinterLine(c, p, margin, line, s)
+		if twoLine {
+			drawPrinterDetail(c, p, margin, top+rowH/2+(rowH/2-eink.TextHeight(s))/2-s, s)
+		}
+	}
+
+	if rest := len(printers) - len(shown); rest > 0 {
+		top := y + len(shown)*rowH
+		if len(shown) > 0 {
+			c.Fill(margin, top, width-2*margin, 1, eink.Dark)
+		}
+		c.Text(margin, top+(rowH-eink.TextHeight(s))/2, fmt.Sprintf("+%d more", rest), s, eink.Dark)
+	}
+}
+
+// drawPrinterLine draws a printer's name and status, with errors inverted so
+// they stand out at a glance
+func drawPrinterLine(c *eink.Canvas, p *models.PrinterStatus, x, y, s int) {
+	width := c.Width()
+	status := strings.ToUpper(p.Status)
+	if p.Progress != nil && (p.Status == "printing" || p.Status == "paused") {
+		status = fmt.Sprintf("%s %.0f%%", status, p.Progress.Completion)
+	}
+	statusW := eink.TextWidth(status, s)
+
+	if p.Status == "error" {
+		pad := s
+		c.Fill(width-x-statusW-2*pad, y-pad, statusW+2*pad, eink.TextHeight(s)+2*pad, eink.Black)
+		c.TextRight(width-x-pad, y, status, s, eink.White)
+		statusW += 2 * pad
+	} else {
+		c.TextRight(width-x, y, status, s, eink.Black)
+	}
+
+	c.Text(x, y, eink.Fit(p.Name, s, width-3*x-statusW), s, eink.Black)
+}
+
+// drawPrinterDetail draws a progress bar and time left for running jobs, or
+// the error or finished part otherwise
+func drawPrinterDetail(c *eink.Canvas, p *models.PrinterStatus, x, y, s int) {
+	width := c.Width()
+	if p.Progress != nil && (p.Status == "printing" || p.Status == "paused") {
+		left := einkDuration(p.Progress.PrintTimeLeft)
+		leftW := 0
+		if left != "" {
+			c.TextRight(width-x, y, left, s, eink.Black)
+			leftW = eink.TextWidth(left, s) + x + 2*s
+		}
+
+		barW := width - 2*x - leftW
+		barH := eink.TextHeight(s)
+		fill := int(float64(barW-2) * min(max(p.Progress.Completion/100, 0), 1))
+		c.Rect(x, y, barW, barH, 1, eink.Black)
+		c.Fill(x+1, y+1, fill, barH-2, eink.Dark)
+		return
+	}
+
+	detail := p.Error
+	if detail == "" && p.Completed != nil {
+		detail = "Done: " + p.Completed.File
+	}
+	if detail != "" {
+		c.Text(x, y, eink.Fit(detail, s, width-2*x), s, eink.Dark)
+	}
+}
+
+// einkDuration is a compact time left like 2h05m; panels refresh too rarely for seconds
+func einkDuration(seconds int) string {
+	if seconds <= 0 {
+		return ""
+	}
+	d := time.Duration(seconds) * time.Second
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+func clamp(v, lo, hi int) int {
+	return min(max(v, lo), hi)
+}
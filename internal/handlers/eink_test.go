@@ -0,0 +1,102 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestEink(t *testing.T) {
+	t.Setenv("EINK_WIDTH", "400")
+	t.Setenv("EINK_HEIGHT", "300")
+	op := testutil.NewOctoPrint(t)
+	op.SetPrinting("gear.gcode", 42, 600)
+	h := newTestHandler(t, testutil.NewSpoolman(t), testutil.Printer{Name: "Mini", Server: op})
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		return rec
+	}
+
+	// Defaults come from EINK_*: 1-bit PNG
+	rec := get("/api/eink")
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "image/png" {
+		t.Fatalf("GET /api/eink = %d %s: %s", rec.Code, rec.Header().Get("Content-Type"), rec.Body)
+	}
+	img, err := png.Decode(rec.Body)
+	if err != nil {
+		t.Fatalf("decoding PNG: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 400 || b.Dy() != 300 {
+		t.Errorf("default size = %v, want 400x300", b)
+	}
+
+	// A 2-bit image uses only the four grays, including the progress bar's dark fill
+	rec = get("/api/eink?width=296&height=128&depth=2")
+	img, err = png.Decode(rec.Body)
+	if err != nil {
+		t.Fatalf("decoding 2-bit PNG: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 296 || b.Dy() != 128 {
+		t.Errorf("size = %v, want 296x128", b)
+	}
+	seen := map[uint8]bool{}
+	for y := 0; y < 128; y++ {
+		for x := 0; x < 296; x++ {
+			seen[color.GrayModel.Convert(img.At(x, y)).(color.Gray).Y] = true
+		}
+	}
+	for shade := range seen {
+		if shade != 0x00 && shade != 0x55 && shade != 0xaa && shade != 0xff {
+			t.Errorf("unexpected gray %#x in 2-bit image", shade)
+		}
+	}
+	if !seen[0x00] || !seen[0x55] || !seen[0xff] {
+		t.Errorf("grays = %v, want black text, dark progress and white background", seen)
+	}
+
+	// BMPs are bottom-up with rows padded to four bytes
+	rec = get("/api/eink?width=250&height=122&format=bmp")
+	body := rec.Body.Bytes()
+	if rec.Header().Get("Content-Type") != "image/bmp" || !bytes.HasPrefix(body, []byte("BM")) {
+		t.Fatalf("BMP response = %s %q", rec.Header().Get("Content-Type"), body[:min(len(body), 2)])
+	}
+	le := binary.LittleEndian
+	if w, h, bpp := le.Uint32(body[18:]), le.Uint32(body[22:]), le.Uint16(body[28:]); w != 250 || h != 122 || bpp != 1 {
+		t.Errorf("BMP header = %dx%d at %d bpp, want 250x122 at 1", w, h, bpp)
+	}
+	if want := 14 + 40 + 2*4 + 32*122; len(body) != want || int(le.Uint32(body[2:])) != want {
+		t.Errorf("BMP size = %d (header says %d), want %d", len(body), le.Uint32(body[2:]), want)
+	}
+
+	for _, path := range []string{"/api/eink?depth=3", "/api/eink?width=5000", "/api/eink?format=gif", "/api/eink?height=tall"} {
+		if rec := get(path); rec.Code != http.StatusBadRequest {
+			t.Errorf("GET %s = %d, want %d", path, rec.Code, http.StatusBadRequest)
+		}
+	}
+}
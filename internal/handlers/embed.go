@@ -0,0 +1,134 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"html/template"
+	"net/http"
+	"strconv"
+
+	"github.com/wmarchesi123/go-3dprint-client/config"
+)
+
+// embedSizes are the widget size presets, as width x height in pixels
+var embedSizes = map[string][2]int{
+	"small":  {240, 120},
+	"medium": {320, 200},
+	"large":  {480, 320},
+}
+
+const embedTemplate = `<!DOCTYPE html>
+<html>
+<head>
+    <title>{{.Name}} - OctoDash</title>
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+    <link rel="stylesheet" href="{{asset "embed.css"}}">
+</head>
+<body class="embed-{{.Size}}{{if .Transparent}} embed-transparent{{end}}" style="width: {{.Width}}px; height: {{.Height}}px;">
+    <div class="embed-widget" id="widget" data-printer="{{.ID}}" data-refresh="{{.Refresh}}" data-units="{{.Units}}">
+        <div class="embed-header">
+            <span class="embed-name">{{.Name}}</span>
+            <span class="embed-status" id="status">...</span>
+        </div>
+        <div class="embed-progress" id="progress-section" hidden>
+            <div class="embed-bar"><div class="embed-fill" id="progress-fill"></div></div>
+            <div class="embed-row"><span id="progress-text"></span><span id="time-left"></span></div>
+        </div>
+        <div class="embed-row embed-temps" id="temps"></div>
+    </div>
+    <script src="{{asset "embed.js"}}"></script>
+</body>
+</html>
+`
+
+// handleEmbed serves a minimal single-printer widget for iframes in other dashboards.
+// Query options: size=small|medium|large, transparent=1, refresh=<seconds>.
+func (h *Handler) handleEmbed(w http.ResponseWriter, r *http.Request) {
+	printer, ok := h.findPrinter(r.PathValue("id"))
+	if !ok {
+		http.Error(w, "Printer not found", http.StatusNotFound)
+		return
+	}
+
+	q := r.URL.Query()
+	size := q.Get("size")
+	dims, ok := embedSizes[size]
+	if !ok {
+		size = "medium"
+		dims = embedSizes[size]
+	}
+
+	refresh, err := strconv.Atoi(q.Get("refresh"))
+	if err != nil || refresh < 1 {
+		refresh = 5
+	}
+
+	tmpl, err := template.New("embed").Funcs(template.FuncMap{"asset": h.assets.URL}).Parse(embedTemplate)
+	if err != nil {
+		http.Error(w, "Template error", http.StatusInternalServerError)
+		return
+	}
+
+	data := struct {
+		ID          string
+		Name        string
+		Size        string
+		Width       int
+		Height      int
+		Transparent bool
+		Refresh     int
+		Units       string
+	}{
+		ID:          printer.ID,
+		Name:        printer.Name,
+		Size:        size,
+		Width:       dims[0],
+		Height:      dims[1],
+		Transparent: q.Get("transparent") == "1" || q.Get("transparent") == "true",
+		Refresh:     refresh,
+		Units:       h.settings.Units.Temperature,
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	tmpl.Execute(w, data)
+}
+
+func (h *Handler) handlePrinterStatus(w http.ResponseWriter, r *http.Request) {
+	printer, ok := h.findPrinter(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Printer not found")
+		return
+	}
+
+	opts, err := parsePayloadOptions(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	status := h.collectStatuses([]config.Printer{printer})[0]
+	if opts.trimming() {
+		status = status.Trimmed(opts.sections)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "ok",
+		"printer": status,
+	})
+}
@@ -0,0 +1,154 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/wmarchesi123/go-3dprint-client/config"
+	"github.com/wmarchesi123/octodash/internal/auth"
+)
+
+// confirmationTTL is how long an emergency stop confirmation token stays valid
+const confirmationTTL = 30 * time.Second
+
+// confirmations hands out single-use tokens that must be echoed back to run a dangerous action
+type confirmations struct {
+	clock  Clock
+	mu     sync.Mutex
+	tokens map[string]confirmation
+}
+
+type confirmation struct {
+	target  string
+	user    string
+	expires time.Time
+}
+
+func newConfirmations(clock Clock) *confirmations {
+	return &confirmations{clock: clock, tokens: make(map[string]confirmation)}
+}
+
+func (c *confirmations) issue(target, user string) (string, time.Time) {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	token := hex.EncodeToString(buf)
+	expires := c.clock.Now().UTC().Add(confirmationTTL)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Drop stale tokens while we hold the lock
+	for t, conf := range c.tokens {
+		if c.clock.Now().After(conf.expires) {
+			delete(c.tokens, t)
+		}
+	}
+
+	c.tokens[token] = confirmation{target: target, user: user, expires: expires}
+	return token, expires
+}
+
+func (c *confirmations) consume(token, target, user string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	conf, ok := c.tokens[token]
+	if !ok {
+		return false
+	}
+	delete(c.tokens, token)
+
+	return conf.target == target && conf.user == user && c.clock.Now().Before(conf.expires)
+}
+
+func (h *Handler) handlePrinterEmergencyStop(w http.ResponseWriter, r *http.Request) {
+	printer, ok := h.findPrinter(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Printer not found")
+		return
+	}
+
+	h.emergencyStop(w, r, printer.ID, []config.Printer{printer})
+}
+
+func (h *Handler) handleFarmEmergencyStop(w http.ResponseWriter, r *http.Request) {
+	h.emergencyStop(w, r, "*", h.printersMatching(r, ""))
+}
+
+// emergencyStop runs the two-step flow: the first request returns a confirmation
+// token, and only a second request carrying that token sends M112
+func (h *Handler) emergencyStop(w http.ResponseWriter, r *http.Request, target string, printers []config.Printer) {
+	// A dry run needs no confirmation since nothing is sent
+	if isDryRun(r) {
+		h.dryRunPrinters(w, r, "emergency-stop", 0, printers, func(_ config.Printer, _ PrinterClient, c ControlClient) error {
+			return c.EmergencyStop()
+		})
+		return
+	}
+
+	var req struct {
+		ConfirmToken string `json:"confirm_token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	user := auth.UserFromContext(r.Context())
+
+	if req.ConfirmToken == "" {
+		token, expires := h.confirmations.issue(target, user.Name)
+		writeJSON(w, http.StatusAccepted, map[string]interface{}{
+			"status":        "confirm",
+			"confirm_token": token,
+			"expires_at":    expires,
+		})
+		return
+	}
+
+	if !h.confirmations.consume(req.ConfirmToken, target, user.Name) {
+		writeError(w, http.StatusConflict, "Confirmation token invalid or expired")
+		return
+	}
+
+	h.logger.Printf("Emergency stop requested by %s for %s", user.Name, target)
+
+	results := h.runAction(printers, func(p config.Printer) error {
+		return h.emergencyStopPrinter(p.ID)
+	})
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "ok",
+		"results": results,
+	})
+}
+
+// emergencyStopPrinter sends M112 straight away rather than behind whatever is
+// queued for the printer, and drops the queued commands
+func (h *Handler) emergencyStopPrinter(id string) error {
+	h.commands.clear(id)
+	return h.controlClient(id).EmergencyStop()
+}
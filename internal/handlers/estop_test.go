@@ -0,0 +1,93 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestEmergencyStopConfirmation(t *testing.T) {
+	t.Setenv("AUTH_USERS", "olga:operator:olga-token,otto:operator:otto-token")
+	mini, voron := testutil.NewOctoPrint(t), testutil.NewOctoPrint(t)
+	clock := &stepClock{now: time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)}
+	h := newHandlerWithConfig(t, testutil.Config(testutil.NewSpoolman(t),
+		testutil.Printer{Name: "Mini", Server: mini},
+		testutil.Printer{Name: "Voron", Server: voron}), WithClock(clock))
+
+	// issue runs olga's first step against path and returns the token handed out
+	issue := func(path string) string {
+		t.Helper()
+		rec := doAs(h, "olga-token", "POST", path, "")
+		if rec.Code != http.StatusAccepted {
+			t.Fatalf("first step on %s = %d: %s", path, rec.Code, rec.Body)
+		}
+		var pending struct {
+			Token string `json:"confirm_token"`
+		}
+		json.NewDecoder(rec.Body).Decode(&pending)
+		return pending.Token
+	}
+	confirm := func(token, path, userToken string) int {
+		return doAs(h, userToken, "POST", path, `{"confirm_token": "`+token+`"}`).Code
+	}
+
+	const mini1 = "/api/printers/printer-1/emergency-stop"
+	if code := confirm(issue(mini1), mini1, "otto-token"); code != http.StatusConflict {
+		t.Errorf("token confirmed by another user = %d, want %d", code, http.StatusConflict)
+	}
+	if code := confirm(issue(mini1), "/api/printers/printer-2/emergency-stop", "olga-token"); code != http.StatusConflict {
+		t.Errorf("token confirmed on another printer = %d, want %d", code, http.StatusConflict)
+	}
+	if code := confirm(issue(mini1), "/api/emergency-stop", "olga-token"); code != http.StatusConflict {
+		t.Errorf("printer token confirmed for the farm = %d, want %d", code, http.StatusConflict)
+	}
+
+	expired := issue(mini1)
+	clock.advance(confirmationTTL + time.Second)
+	if code := confirm(expired, mini1, "olga-token"); code != http.StatusConflict {
+		t.Errorf("expired token = %d, want %d", code, http.StatusConflict)
+	}
+	if len(mini.Commands()) != 0 || len(voron.Commands()) != 0 {
+		t.Fatalf("rejected confirmations sent %v %v", mini.Commands(), voron.Commands())
+	}
+
+	token := issue(mini1)
+	clock.advance(confirmationTTL - time.Second)
+	if code := confirm(token, mini1, "olga-token"); code != http.StatusOK {
+		t.Fatalf("confirmed stop = %d, want %d", code, http.StatusOK)
+	}
+	if cmds := mini.Commands(); len(cmds) != 1 || !strings.Contains(cmds[0], "M112") {
+		t.Errorf("commands after emergency stop = %v", cmds)
+	}
+	if len(voron.Commands()) != 0 {
+		t.Errorf("other printer got %v", voron.Commands())
+	}
+	if code := confirm(token, mini1, "olga-token"); code != http.StatusConflict {
+		t.Errorf("reused token = %d, want %d", code, http.StatusConflict)
+	}
+	if len(mini.Commands()) != 1 {
+		t.Errorf("reused token sent %v", mini.Commands())
+	}
+}
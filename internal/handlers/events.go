@@ -0,0 +1,95 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// maxEventsLimit caps how many events a single request can return
+const maxEventsLimit = 1000
+
+// watchStatuses polls the printers no client has polled within interval, so
+// transitions are logged even with no dashboard open
+func (h *Handler) watchStatuses(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			h.pollUnpolled(interval)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// pollUnpolled collects the statuses of printers left out of every poll
+// within interval, such as those outside a kiosk's tag filter
+func (h *Handler) pollUnpolled(interval time.Duration) {
+	if stale := h.meta.unpolled(h.config.Printers, h.clock.Now(), interval); len(stale) > 0 {
+		h.collectStatuses(stale)
+	}
+}
+
+func (h *Handler) handlePrinterEvents(w http.ResponseWriter, r *http.Request) {
+	printer, ok := h.findPrinter(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Printer not found")
+		return
+	}
+
+	q := r.URL.Query()
+
+	limit := 100
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+		limit = min(n, maxEventsLimit)
+	}
+
+	var since time.Time
+	if v := q.Get("since"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid since, expected RFC 3339")
+			return
+		}
+		since = t
+	}
+
+	events, err := h.events.List(printer.ID, since, limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":     "ok",
+		"printer_id": printer.ID,
+		"events":     events,
+	})
+}
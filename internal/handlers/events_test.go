@@ -0,0 +1,108 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestPrinterEventsRecordTransitions(t *testing.T) {
+	sm := testutil.NewSpoolman(t)
+	op := testutil.NewOctoPrint(t)
+	h := newTestHandler(t, sm, testutil.Printer{Name: "Voron", Server: op})
+
+	getStatus(t, h)
+	op.SetPrinting("skirt.gcode", 10, 3600)
+	getStatus(t, h)
+	getStatus(t, h) // no change, no event
+	op.SetFault(testutil.Fault{StatusCode: http.StatusUnauthorized})
+	getStatus(t, h)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/api/printers/printer-1/events", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET events = %d: %s", rec.Code, rec.Body)
+	}
+
+	var response struct {
+		Events []models.StatusEvent `json:"events"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct{ from, to string }{
+		{"printing", "offline"},
+		{"idle", "printing"},
+		{"", "idle"},
+	}
+	if len(response.Events) != len(want) {
+		t.Fatalf("got %d events, want %d: %+v", len(response.Events), len(want), response.Events)
+	}
+	for i, w := range want {
+		e := response.Events[i]
+		if e.From != w.from || e.To != w.to {
+			t.Errorf("event %d = %s→%s, want %s→%s", i, e.From, e.To, w.from, w.to)
+		}
+	}
+	if response.Events[1].File != "skirt.gcode" {
+		t.Errorf("printing event file = %q", response.Events[1].File)
+	}
+	if response.Events[0].Error == "" {
+		t.Error("offline event has no error")
+	}
+}
+
+func TestFilteredPollsDontHoldBackOtherPrinters(t *testing.T) {
+	t.Setenv("PRINTER_1_TAGS", "room=lab")
+	mini, voron := testutil.NewOctoPrint(t), testutil.NewOctoPrint(t)
+	clock := &stepClock{now: time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)}
+	h := newHandlerWithConfig(t, testutil.Config(testutil.NewSpoolman(t),
+		testutil.Printer{Name: "Mini", Server: mini},
+		testutil.Printer{Name: "Voron", Server: voron}), WithClock(clock))
+	getStatus(t, h)
+
+	// A kiosk keeps polling the lab's printer while the other one starts a job
+	voron.SetPrinting("skirt.gcode", 10, 3600)
+	for i := 0; i < 3; i++ {
+		clock.advance(10 * time.Second)
+		if rec := doAs(h, "", "GET", "/api/status?tags=room=lab", ""); rec.Code != http.StatusOK {
+			t.Fatalf("filtered poll = %d", rec.Code)
+		}
+	}
+	h.pollUnpolled(30 * time.Second)
+
+	var response struct {
+		Events []models.StatusEvent `json:"events"`
+	}
+	json.NewDecoder(doAs(h, "", "GET", "/api/printers/printer-2/events", "").Body).Decode(&response)
+	if len(response.Events) == 0 || response.Events[0].To != "printing" {
+		t.Errorf("events of the printer outside the filter = %+v, want it seen printing", response.Events)
+	}
+	if n := mini.Requests("GET /api/printer"); n != 4 {
+		t.Errorf("printer inside the filter polled %d times, want 4", n)
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/extensions"
+	"github.com/wmarchesi123/octodash/internal/models"
+)
+
+// enrichTimeout bounds all extension enrichment for one poll
+const enrichTimeout = 2 * time.Second
+
+// startExtensions drops disabled extensions and starts the rest
+func (h *Handler) startExtensions(ctx context.Context) error {
+	if h.extensions == nil {
+		h.extensions = extensions.All()
+	}
+
+	enabled := h.extensions[:0:0]
+	for _, e := range h.extensions {
+		if slices.Contains(h.settings.Extensions.Disabled, e.Name()) {
+			continue
+		}
+		if s, ok := e.(extensions.Starter); ok {
+			host := extensions.Host{Printers: h.config.Printers, Logger: h.logger}
+			if err := s.Start(ctx, host); err != nil {
+				return fmt.Errorf("starting extension %s: %w", e.Name(), err)
+			}
+		}
+		enabled = append(enabled, e)
+	}
+	h.extensions = enabled
+	return nil
+}
+
+// extensionRoutes mounts each extension's routes under /api/ext/<name>/
+func (h *Handler) extensionRoutes() {
+	for _, e := range h.extensions {
+		p, ok := e.(extensions.RouteProvider)
+		if !ok {
+			continue
+		}
+		for _, route := range p.Routes() {
+			pattern := fmt.Sprintf("%s /api/ext/%s/%s", route.Method, e.Name(), strings.TrimPrefix(route.Path, "/"))
+			handler := route.Handler
+			if route.Role != 0 {
+				handler = h.auth.Require(route.Role, handler)
+			}
+			h.mux.HandleFunc(pattern, handler)
+		}
+	}
+}
+
+// addExtensions collects each enricher's data for every printer
+func (h *Handler) addExtensions(printers []*models.PrinterStatus) {
+	ctx, cancel := context.WithTimeout(h.ctx, enrichTimeout)
+	defer cancel()
+
+	for _, p := range printers {
+		p.Extensions = nil
+		for _, e := range h.extensions {
+			enricher, ok := e.(extensions.Enricher)
+			if !ok {
+				continue
+			}
+			data, err := enricher.Enrich(ctx, p)
+			if err != nil {
+				h.logger.Printf("Extension %s failed to enrich %s: %v", e.Name(), p.Name, err)
+				continue
+			}
+			if data == nil {
+				continue
+			}
+			if p.Extensions == nil {
+				p.Extensions = make(map[string]interface{})
+			}
+			p.Extensions[e.Name()] = data
+		}
+	}
+}
+
+// cardSection is an extension's fragment on the printer cards
+type cardSection struct {
+	Name string
+	HTML template.HTML
+}
+
+// cardSections lists the fragments extensions add to printer cards
+func (h *Handler) cardSections() []cardSection {
+	var sections []cardSection
+	for _, e := range h.extensions {
+		if p, ok := e.(extensions.CardProvider); ok {
+			sections = append(sections, cardSection{Name: e.Name(), HTML: p.CardSection()})
+		}
+	}
+	return sections
+}
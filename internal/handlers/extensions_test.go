@@ -0,0 +1,110 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"html/template"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/wmarchesi123/octodash/internal/auth"
+	"github.com/wmarchesi123/octodash/internal/extensions"
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+// badges is a site extension tagging printers with the room they stand in
+type badges struct {
+	started bool
+}
+
+func (b *badges) Name() string { return "badges" }
+
+func (b *badges) Start(ctx context.Context, host extensions.Host) error {
+	b.started = len(host.Printers) == 1
+	return nil
+}
+
+func (b *badges) Enrich(ctx context.Context, status *models.PrinterStatus) (interface{}, error) {
+	return map[string]string{"room": "Lab " + status.ID}, nil
+}
+
+func (b *badges) Routes() []extensions.Route {
+	return []extensions.Route{
+		{Method: "GET", Path: "rooms", Handler: func(w http.ResponseWriter, r *http.Request) { io.WriteString(w, "lab") }},
+		{Method: "POST", Path: "rooms/{id}", Role: auth.RoleAdmin, Handler: func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, "moved "+r.PathValue("id"))
+		}},
+	}
+}
+
+func (b *badges) CardSection() template.HTML {
+	return `<span class="room" x-text="printer.extensions.badges.room"></span>`
+}
+
+// muted is registered but turned off in settings
+type muted struct{}
+
+func (muted) Name() string { return "muted" }
+
+func (muted) Enrich(ctx context.Context, status *models.PrinterStatus) (interface{}, error) {
+	return "should not appear", nil
+}
+
+func TestExtensions(t *testing.T) {
+	t.Setenv("AUTH_USERS", "olga:operator:olga-token")
+	t.Setenv("EXTENSIONS_DISABLED", "muted")
+	ext := &badges{}
+	h := newHandlerWithConfig(t, testutil.Config(testutil.NewSpoolman(t), testutil.Printer{Name: "Mini", Server: testutil.NewOctoPrint(t)}),
+		WithExtensions(ext, muted{}))
+
+	if !ext.started {
+		t.Error("extension was not started with the printers")
+	}
+
+	p := getStatus(t, h)["printer-1"]
+	data, _ := json.Marshal(p.Extensions)
+	if string(data) != `{"badges":{"room":"Lab printer-1"}}` {
+		t.Errorf("extensions = %s", data)
+	}
+
+	do := func(method, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer olga-token")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+	if rec := do("GET", "/api/ext/badges/rooms"); rec.Body.String() != "lab" {
+		t.Errorf("open route = %d %q", rec.Code, rec.Body)
+	}
+	if rec := do("POST", "/api/ext/badges/rooms/printer-1"); rec.Code != http.StatusForbidden {
+		t.Errorf("admin route as operator = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+
+	rec := do("GET", "/")
+	if page := rec.Body.String(); !strings.Contains(page, `x-show="printer.extensions?.['badges']"><span class="room"`) {
+		t.Error("dashboard is missing the extension's card section")
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/wmarchesi123/octodash/internal/settings"
+)
+
+// flapGuard debounces a printer's reachability so a noisy network doesn't
+// flip its card between offline and online on every poll. A printer is only
+// marked offline after offlineAfter consecutive failed polls, showing its last
+// good status until then, and only back online after onlineAfter successes.
+type flapGuard struct {
+	offlineAfter int
+	onlineAfter  int
+
+	mu       sync.Mutex
+	printers map[string]*reachability
+}
+
+// reachability is the debounced view of one printer
+type reachability struct {
+	online    bool
+	failures  int
+	successes int
+	last      statusSlot
+}
+
+func newFlapGuard(s settings.PollSettings) *flapGuard {
+	return &flapGuard{
+		offlineAfter: s.OfflineAfter,
+		onlineAfter:  s.OnlineAfter,
+		printers:     make(map[string]*reachability),
+	}
+}
+
+// apply settles a freshly fetched status, reached reporting whether OctoPrint answered
+func (g *flapGuard) apply(id string, slot *statusSlot, reached bool) {
+	if g.offlineAfter <= 1 && g.onlineAfter <= 1 {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	r, ok := g.printers[id]
+	if !ok {
+		// Nothing to debounce against yet, so the first poll is taken as is
+		r = &reachability{online: reached}
+		g.printers[id] = r
+	}
+
+	if reached {
+		r.failures = 0
+		r.successes++
+		if !r.online && r.successes < g.onlineAfter {
+			slot.status.Status = "offline"
+			slot.status.Error = fmt.Sprintf("Reconnecting (%d/%d)", r.successes, g.onlineAfter)
+			slot.status.Temperatures = nil
+			slot.status.Progress = nil
+			slot.status.ThumbnailURL = ""
+			slot.status.CurrentSpool = nil
+			return
+		}
+		r.online = true
+		r.last.copyFrom(slot)
+		return
+	}
+
+	r.successes = 0
+	r.failures++
+	if r.online && r.failures < g.offlineAfter {
+		slot.copyFrom(&r.last)
+		return
+	}
+	r.online = false
+}
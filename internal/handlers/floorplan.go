@@ -0,0 +1,222 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/wmarchesi123/octodash/internal/auth"
+	"github.com/wmarchesi123/octodash/internal/models"
+)
+
+const floorPlanBucket = "floorplan"
+
+// maxFloorPlanImage bounds uploaded floor plan pictures
+const maxFloorPlanImage = 10 << 20
+
+// floorPlanImageTypes are the picture formats browsers can show as a floor plan
+var floorPlanImageTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+const floorPlanTemplate = `<!DOCTYPE html>
+<html>
+<head>
+    <title>Floor plan - OctoDash</title>
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+    <link rel="stylesheet" href="{{asset "style.css"}}">
+</head>
+<body class="floorplan-page">
+    <div class="floorplan" id="floorplan">
+        <img id="floorplan-image" alt="Floor plan" hidden>
+        <p class="floorplan-empty" id="floorplan-empty" hidden>No floor plan has been uploaded yet.</p>
+    </div>
+    <script src="{{asset "floorplan.js"}}"></script>
+</body>
+</html>
+`
+
+// floorPlanImage is the uploaded picture, stored apart from the layout so
+// reading positions does not load the whole image
+type floorPlanImage struct {
+	ContentType string `json:"content_type"`
+	Data        []byte `json:"data"`
+}
+
+// loadFloorPlan returns the stored layout, or an empty one if none was saved
+func (h *Handler) loadFloorPlan() (models.FloorPlan, error) {
+	var plan models.FloorPlan
+	if _, err := h.store.Get(floorPlanBucket, "layout", &plan); err != nil {
+		return plan, err
+	}
+	if plan.Positions == nil {
+		plan.Positions = map[string]models.FloorPosition{}
+	}
+	return plan, nil
+}
+
+// floorPlanVersion identifies the current picture in its URL
+func floorPlanVersion(plan models.FloorPlan) string {
+	return strconv.FormatInt(plan.UpdatedAt.Unix(), 10)
+}
+
+// handleFloorPlanPage serves the floor plan view with live status dots
+func (h *Handler) handleFloorPlanPage(w http.ResponseWriter, r *http.Request) {
+	h.renderPage(w, "floorplan", floorPlanTemplate, nil)
+}
+
+// handleFloorPlan returns the layout and where to fetch the picture from
+func (h *Handler) handleFloorPlan(w http.ResponseWriter, r *http.Request) {
+	plan, err := h.loadFloorPlan()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	imageURL := ""
+	if plan.ImageType != "" {
+		// The version busts caches whenever a new picture is uploaded
+		imageURL = "/floorplan/image?v=" + floorPlanVersion(plan)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":    "ok",
+		"floorplan": plan,
+		"image_url": imageURL,
+	})
+}
+
+// handleFloorPlanImage serves the uploaded picture of the room
+func (h *Handler) handleFloorPlanImage(w http.ResponseWriter, r *http.Request) {
+	plan, err := h.loadFloorPlan()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	var img floorPlanImage
+	ok, err := h.store.Get(floorPlanBucket, "image", &img)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", img.ContentType)
+	if r.URL.Query().Get("v") == floorPlanVersion(plan) {
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	} else {
+		w.Header().Set("Cache-Control", "no-cache")
+	}
+	w.Write(img.Data)
+}
+
+// handleFloorPlanUpload stores a new picture of the room from the raw request body
+func (h *Handler) handleFloorPlanUpload(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxFloorPlanImage))
+	if err != nil {
+		writeError(w, http.StatusRequestEntityTooLarge, "Image is larger than 10 MB")
+		return
+	}
+
+	// Sniff rather than trust Content-Type, since the picture is served back as-is
+	contentType := http.DetectContentType(data)
+	if !floorPlanImageTypes[contentType] {
+		writeError(w, http.StatusBadRequest, "Floor plan must be a PNG, JPEG, GIF or WebP image")
+		return
+	}
+
+	plan, err := h.loadFloorPlan()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if err := h.store.Put(floorPlanBucket, "image", floorPlanImage{ContentType: contentType, Data: data}); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	plan.ImageType = contentType
+	plan.UpdatedAt = h.clock.Now().UTC()
+	if err := h.store.Put(floorPlanBucket, "layout", plan); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.logger.Printf("Floor plan image replaced by %s (%d bytes)", auth.UserFromContext(r.Context()).Name, len(data))
+	writeJSON(w, http.StatusOK, map[string]interface{}{"status": "ok", "floorplan": plan})
+}
+
+// handleFloorPlanLayout replaces every printer position on the floor plan.
+// Printers left out of the request are not shown.
+func (h *Handler) handleFloorPlanLayout(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Positions map[string]models.FloorPosition `json:"positions"`
+	}
+	if err := json.NewDecoder(io.LimitReader(r.Body, 64<<10)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	for id, pos := range req.Positions {
+		if _, ok := h.findPrinter(id); !ok {
+			writeError(w, http.StatusBadRequest, "Unknown printer "+id)
+			return
+		}
+		if pos.X < 0 || pos.X > 1 || pos.Y < 0 || pos.Y > 1 {
+			writeError(w, http.StatusBadRequest, "Position for "+id+" must be between 0 and 1")
+			return
+		}
+	}
+
+	plan, err := h.loadFloorPlan()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	plan.Positions = req.Positions
+	if plan.Positions == nil {
+		plan.Positions = map[string]models.FloorPosition{}
+	}
+	plan.UpdatedAt = h.clock.Now().UTC()
+	if err := h.store.Put(floorPlanBucket, "layout", plan); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"status": "ok", "floorplan": plan})
+}
+
+// handleFloorPlanDelete removes the picture and every position
+func (h *Handler) handleFloorPlanDelete(w http.ResponseWriter, r *http.Request) {
+	for _, key := range []string{"image", "layout"} {
+		if err := h.store.Delete(floorPlanBucket, key); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"status": "ok"})
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestFloorPlan(t *testing.T) {
+	h := newTestHandler(t, testutil.NewSpoolman(t), testutil.Printer{Name: "Mini", Server: testutil.NewOctoPrint(t)})
+
+	do := func(method, path string, body []byte) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(method, path, bytes.NewReader(body)))
+		return rec
+	}
+	layout := func() (models.FloorPlan, string) {
+		var response struct {
+			FloorPlan models.FloorPlan `json:"floorplan"`
+			ImageURL  string           `json:"image_url"`
+		}
+		if err := json.NewDecoder(do("GET", "/api/floorplan", nil).Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		return response.FloorPlan, response.ImageURL
+	}
+
+	if plan, url := layout(); url != "" || len(plan.Positions) != 0 {
+		t.Errorf("empty floor plan = %+v, %q", plan, url)
+	}
+
+	if rec := do("PUT", "/api/admin/floorplan/image", []byte("<svg></svg>")); rec.Code != http.StatusBadRequest {
+		t.Errorf("SVG upload = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	var img bytes.Buffer
+	png.Encode(&img, image.NewRGBA(image.Rect(0, 0, 4, 4)))
+	if rec := do("PUT", "/api/admin/floorplan/image", img.Bytes()); rec.Code != http.StatusOK {
+		t.Fatalf("PNG upload = %d: %s", rec.Code, rec.Body)
+	}
+
+	if rec := do("PUT", "/api/admin/floorplan", []byte(`{"positions": {"printer-9": {"x": 0.5, "y": 0.5}}}`)); rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown printer = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if rec := do("PUT", "/api/admin/floorplan", []byte(`{"positions": {"printer-1": {"x": 1.5, "y": 0.5}}}`)); rec.Code != http.StatusBadRequest {
+		t.Errorf("off-image position = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if rec := do("PUT", "/api/admin/floorplan", []byte(`{"positions": {"printer-1": {"x": 0.25, "y": 0.75}}}`)); rec.Code != http.StatusOK {
+		t.Fatalf("set layout = %d: %s", rec.Code, rec.Body)
+	}
+
+	plan, url := layout()
+	if plan.Positions["printer-1"] != (models.FloorPosition{X: 0.25, Y: 0.75}) || plan.ImageType != "image/png" {
+		t.Errorf("floor plan = %+v", plan)
+	}
+	if !strings.HasPrefix(url, "/floorplan/image?v=") {
+		t.Errorf("image URL = %q", url)
+	}
+	if rec := do("GET", url, nil); rec.Header().Get("Content-Type") != "image/png" || !bytes.Equal(rec.Body.Bytes(), img.Bytes()) {
+		t.Errorf("GET %s = %s, %d bytes", url, rec.Header().Get("Content-Type"), rec.Body.Len())
+	} else if !strings.Contains(rec.Header().Get("Cache-Control"), "immutable") {
+		t.Errorf("versioned image Cache-Control = %q", rec.Header().Get("Cache-Control"))
+	}
+	if rec := do("GET", "/floorplan/image?v=1", nil); rec.Header().Get("Cache-Control") != "no-cache" {
+		t.Errorf("stale version Cache-Control = %q, want no-cache", rec.Header().Get("Cache-Control"))
+	}
+
+	if rec := do("DELETE", "/api/admin/floorplan", nil); rec.Code != http.StatusOK {
+		t.Fatalf("delete = %d", rec.Code)
+	}
+	if rec := do("GET", "/floorplan/image", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("image after delete = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
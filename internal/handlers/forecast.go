@@ -0,0 +1,84 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	"github.com/wmarchesi123/octodash/internal/forecast"
+	"github.com/wmarchesi123/octodash/internal/models"
+)
+
+// buildForecast reads every Spoolman instance the caller's teams print from
+// and projects run-out dates. Instances that can't be read are left out and
+// reported in errs.
+func (h *Handler) buildForecast(r *http.Request) (f models.Forecast, errs []string) {
+	sources := h.visibleSpoolSources(r)
+	inventories := make([]forecast.Inventory, 0, len(sources))
+	for _, src := range sources {
+		spools, err := src.client.GetAllSpools()
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", h.spoolmanLabel(src), err))
+			continue
+		}
+		inventories = append(inventories, forecast.Inventory{Name: src.name, Spools: spools})
+	}
+
+	opts := forecast.Options{
+		Window:        h.settings.Forecast.Window,
+		ReorderWithin: h.settings.Forecast.ReorderWithin,
+	}
+	return forecast.Build(inventories, h.clock.Now(), opts), errs
+}
+
+func (h *Handler) handleForecast(w http.ResponseWriter, r *http.Request) {
+	f, errs := h.buildForecast(r)
+
+	resp := map[string]interface{}{
+		"status":   "ok",
+		"forecast": f,
+	}
+	if len(errs) > 0 {
+		resp["errors"] = errs
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleShoppingList exports the reorder list as CSV. A partial inventory
+// would understate what to buy, so it fails if any Spoolman can't be read.
+func (h *Handler) handleShoppingList(w http.ResponseWriter, r *http.Request) {
+	f, errs := h.buildForecast(r)
+	if len(errs) > 0 {
+		writeError(w, http.StatusBadGateway, errs[0])
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := forecast.ShoppingList(&buf, f); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	name := fmt.Sprintf("shopping-list-%s.csv", f.GeneratedAt.In(h.settings.Timezone).Format("2006-01-02"))
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	w.Write(buf.Bytes())
+}
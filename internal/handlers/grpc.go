@@ -0,0 +1,261 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/wmarchesi123/go-3dprint-client/config"
+	"github.com/wmarchesi123/octodash/internal/auth"
+	"github.com/wmarchesi123/octodash/internal/models"
+	octodashv1 "github.com/wmarchesi123/octodash/pkg/proto/octodash/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultStreamInterval = 5 * time.Second
+	minStreamInterval     = time.Second
+)
+
+// grpcActions maps the API's job actions onto the names jobActions uses
+var grpcActions = map[octodashv1.Action]string{
+	octodashv1.Action_ACTION_PAUSE:  "pause",
+	octodashv1.Action_ACTION_RESUME: "resume",
+	octodashv1.Action_ACTION_CANCEL: "cancel",
+}
+
+// GRPCServer returns a server for the gRPC API in pkg/proto. Callers send the
+// same bearer tokens as over HTTP in the "authorization" metadata.
+func (h *Handler) GRPCServer() *grpc.Server {
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(h.grpcUnaryAuth),
+		grpc.StreamInterceptor(h.grpcStreamAuth),
+	)
+	octodashv1.RegisterOctoDashServer(srv, &grpcService{h: h})
+	return srv
+}
+
+// grpcIdentify attaches the caller to the context like auth.Identify, leaving
+// rejection to the methods that need a role
+func (h *Handler) grpcIdentify(ctx context.Context) context.Context {
+	var token string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for _, value := range md.Get("authorization") {
+			if t, ok := strings.CutPrefix(value, "Bearer "); ok {
+				token = strings.TrimSpace(t)
+			}
+		}
+	}
+	if user, ok := h.auth.AuthenticateToken(token); ok {
+		return auth.WithUser(ctx, user)
+	}
+	return ctx
+}
+
+func (h *Handler) grpcUnaryAuth(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, next grpc.UnaryHandler) (interface{}, error) {
+	return next(h.grpcIdentify(ctx), req)
+}
+
+func (h *Handler) grpcStreamAuth(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, next grpc.StreamHandler) error {
+	return next(srv, identifiedStream{ServerStream: ss, ctx: h.grpcIdentify(ss.Context())})
+}
+
+// identifiedStream carries the context grpcIdentify attached the caller to
+type identifiedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s identifiedStream) Context() context.Context { return s.ctx }
+
+// grpcService implements the gRPC API on top of the same polling, auth and
+// controls as the HTTP handlers
+type grpcService struct {
+	octodashv1.UnimplementedOctoDashServer
+	h *Handler
+}
+
+func (s *grpcService) GetStatus(ctx context.Context, req *octodashv1.GetStatusRequest) (*octodashv1.StatusSnapshot, error) {
+	return s.snapshot(s.printers(ctx, req.GetPrinterIds())), nil
+}
+
+func (s *grpcService) StreamStatus(req *octodashv1.StreamStatusRequest, stream octodashv1.OctoDash_StreamStatusServer) error {
+	interval := defaultStreamInterval
+	if req.GetInterval() != nil {
+		interval = req.GetInterval().AsDuration()
+		if interval < minStreamInterval {
+			return status.Errorf(codes.InvalidArgument, "interval must be at least %s", minStreamInterval)
+		}
+	}
+
+	ctx := stream.Context()
+	printers := s.printers(ctx, req.GetPrinterIds())
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := stream.Send(s.snapshot(printers)); err != nil {
+			return err
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		case <-s.h.streams.Done():
+			return nil
+		}
+	}
+}
+
+func (s *grpcService) PrinterAction(ctx context.Context, req *octodashv1.PrinterActionRequest) (*octodashv1.PrinterActionResponse, error) {
+	name, ok := grpcActions[req.GetAction()]
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "unknown action")
+	}
+	printer, user, err := s.control(ctx, req.GetPrinterId())
+	if err != nil {
+		return nil, err
+	}
+
+	s.h.logger.Printf("%s requested by %s for %s", name, user.Name, printer.Name)
+
+	_, err = s.h.commands.run(printer.ID, name, "", user.Name, func() error {
+		return jobActions[name](s.h.controlClient(printer.ID))
+	})
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	return &octodashv1.PrinterActionResponse{}, nil
+}
+
+func (s *grpcService) EmergencyStop(ctx context.Context, req *octodashv1.EmergencyStopRequest) (*octodashv1.EmergencyStopResponse, error) {
+	printer, user, err := s.control(ctx, req.GetPrinterId())
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetConfirmToken() == "" {
+		token, expires := s.h.confirmations.issue(printer.ID, user.Name)
+		return &octodashv1.EmergencyStopResponse{
+			ConfirmToken: token,
+			ExpiresAt:    timestamppb.New(expires),
+		}, nil
+	}
+
+	if !s.h.confirmations.consume(req.GetConfirmToken(), printer.ID, user.Name) {
+		return nil, status.Error(codes.FailedPrecondition, "confirmation token invalid or expired")
+	}
+
+	s.h.logger.Printf("Emergency stop requested by %s for %s", user.Name, printer.ID)
+
+	if err := s.h.emergencyStopPrinter(printer.ID); err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	return &octodashv1.EmergencyStopResponse{Stopped: true}, nil
+}
+
+// control resolves the printer a control call targets and checks the caller
+// may operate it, mirroring RequirePrinter plus the tenancy scope
+func (s *grpcService) control(ctx context.Context, id string) (config.Printer, *auth.User, error) {
+	user, ok := auth.IdentifiedUser(ctx)
+	if !ok {
+		return config.Printer{}, nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+	printer, found := s.h.findPrinter(id)
+	if !found || !s.h.tenancyFor(ctx).printer(id) {
+		return config.Printer{}, nil, status.Error(codes.NotFound, "printer not found")
+	}
+	if !user.Allowed(auth.RoleOperator, id) {
+		return config.Printer{}, nil, status.Error(codes.PermissionDenied, "insufficient role")
+	}
+	return printer, user, nil
+}
+
+// printers returns the configured printers the caller may see, narrowed to
+// ids when any are given
+func (s *grpcService) printers(ctx context.Context, ids []string) []config.Printer {
+	scope := s.h.tenancyFor(ctx)
+	want := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		want[id] = true
+	}
+
+	var printers []config.Printer
+	for _, p := range s.h.config.Printers {
+		if scope.printer(p.ID) && (len(want) == 0 || want[p.ID]) {
+			printers = append(printers, p)
+		}
+	}
+	return printers
+}
+
+func (s *grpcService) snapshot(printers []config.Printer) *octodashv1.StatusSnapshot {
+	batch := s.h.pollStatuses(printers)
+	defer batch.release()
+
+	snap := &octodashv1.StatusSnapshot{
+		PolledAt: timestamppb.New(s.h.clock.Now().UTC().Truncate(time.Second)),
+		Sequence: s.h.meta.next(),
+		Printers: make([]*octodashv1.PrinterStatus, 0, len(batch.printers)),
+	}
+	for _, p := range batch.printers {
+		snap.Printers = append(snap.Printers, protoStatus(p))
+	}
+	return snap
+}
+
+// protoStatus copies the sections the gRPC API carries out of a pooled status
+func protoStatus(p *models.PrinterStatus) *octodashv1.PrinterStatus {
+	out := &octodashv1.PrinterStatus{
+		Id:     p.ID,
+		Name:   p.Name,
+		Status: p.Status,
+		State:  p.State,
+		Error:  p.Error,
+	}
+	if p.LastSeen != nil {
+		out.LastSeen = timestamppb.New(*p.LastSeen)
+	}
+	if p.Progress != nil {
+		out.Progress = &octodashv1.Progress{
+			Completion:           p.Progress.Completion,
+			PrintTimeSeconds:     int32(p.Progress.PrintTime),
+			PrintTimeLeftSeconds: int32(p.Progress.PrintTimeLeft),
+			FileName:             p.Progress.FileName,
+		}
+		if p.Progress.ETA != nil {
+			out.Progress.Eta = timestamppb.New(*p.Progress.ETA)
+		}
+	}
+	if p.Temperatures != nil {
+		out.Temperatures = &octodashv1.Temperatures{
+			BedActual:    p.Temperatures.BedActual,
+			BedTarget:    p.Temperatures.BedTarget,
+			HotendActual: p.Temperatures.HotendActual,
+			HotendTarget: p.Temperatures.HotendTarget,
+		}
+	}
+	return out
+}
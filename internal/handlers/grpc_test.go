@@ -0,0 +1,157 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/testutil"
+	octodashv1 "github.com/wmarchesi123/octodash/pkg/proto/octodash/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// dialGRPC serves h's gRPC API over an in-memory listener
+func dialGRPC(t *testing.T, h *Handler) octodashv1.OctoDashClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	srv := h.GRPCServer()
+	go srv.Serve(listener)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dialing: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return octodashv1.NewOctoDashClient(conn)
+}
+
+func withToken(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func TestGRPCStatus(t *testing.T) {
+	op := testutil.NewOctoPrint(t)
+	op.SetPrinting("gear.gcode", 40, 1200)
+	op.SetTemperatures(215, 215, 60, 60)
+	h := newTestHandler(t, testutil.NewSpoolman(t),
+		testutil.Printer{Name: "MK4", Server: op},
+		testutil.Printer{Name: "Mini", Server: testutil.NewOctoPrint(t)})
+	client := dialGRPC(t, h)
+
+	snap, err := client.GetStatus(context.Background(), &octodashv1.GetStatusRequest{PrinterIds: []string{"printer-1"}})
+	if err != nil {
+		t.Fatalf("GetStatus: %v", err)
+	}
+	if len(snap.Printers) != 1 {
+		t.Fatalf("got %d printers, want 1", len(snap.Printers))
+	}
+	p := snap.Printers[0]
+	if p.Id != "printer-1" || p.Status != "printing" || p.Progress.GetFileName() != "gear.gcode" || p.Temperatures.GetHotendActual() != 215 {
+		t.Errorf("status = %v", p)
+	}
+
+	stream, err := client.StreamStatus(context.Background(), &octodashv1.StreamStatusRequest{Interval: durationpb.New(time.Second)})
+	if err != nil {
+		t.Fatalf("StreamStatus: %v", err)
+	}
+	first, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("first snapshot: %v", err)
+	}
+	second, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("second snapshot: %v", err)
+	}
+	if len(first.Printers) != 2 || second.Sequence <= first.Sequence {
+		t.Errorf("snapshots %d then %d with %d printers", first.Sequence, second.Sequence, len(first.Printers))
+	}
+
+	tooFast, err := client.StreamStatus(context.Background(), &octodashv1.StreamStatusRequest{Interval: durationpb.New(time.Millisecond)})
+	if err == nil {
+		_, err = tooFast.Recv()
+	}
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("10ms interval = %v, want InvalidArgument", err)
+	}
+}
+
+func TestGRPCControl(t *testing.T) {
+	t.Setenv("AUTH_USERS", "vic:viewer:vic-token,olga:operator:olga-token")
+	op := testutil.NewOctoPrint(t)
+	op.SetPrinting("gear.gcode", 40, 1200)
+	h := newTestHandler(t, testutil.NewSpoolman(t), testutil.Printer{Name: "MK4", Server: op})
+	client := dialGRPC(t, h)
+
+	pause := &octodashv1.PrinterActionRequest{PrinterId: "printer-1", Action: octodashv1.Action_ACTION_PAUSE}
+	if _, err := client.PrinterAction(context.Background(), pause); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("anonymous pause = %v, want Unauthenticated", err)
+	}
+	if _, err := client.PrinterAction(withToken("vic-token"), pause); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("viewer pause = %v, want PermissionDenied", err)
+	}
+	if len(op.Commands()) != 0 {
+		t.Fatalf("rejected calls sent %v", op.Commands())
+	}
+	if _, err := client.PrinterAction(withToken("olga-token"), pause); err != nil {
+		t.Fatalf("operator pause: %v", err)
+	}
+	if cmds := op.Commands(); len(cmds) != 1 || !strings.Contains(cmds[0], "pause") {
+		t.Errorf("commands after pause = %v", cmds)
+	}
+
+	missing := &octodashv1.PrinterActionRequest{PrinterId: "printer-9", Action: octodashv1.Action_ACTION_CANCEL}
+	if _, err := client.PrinterAction(withToken("olga-token"), missing); status.Code(err) != codes.NotFound {
+		t.Errorf("unknown printer = %v, want NotFound", err)
+	}
+
+	ctx := withToken("olga-token")
+	first, err := client.EmergencyStop(ctx, &octodashv1.EmergencyStopRequest{PrinterId: "printer-1"})
+	if err != nil || first.ConfirmToken == "" || first.Stopped {
+		t.Fatalf("first emergency stop = %v, %v", first, err)
+	}
+	if len(op.Commands()) != 1 {
+		t.Fatalf("emergency stop sent before confirming: %v", op.Commands())
+	}
+	if _, err := client.EmergencyStop(ctx, &octodashv1.EmergencyStopRequest{PrinterId: "printer-1", ConfirmToken: "bogus"}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("bogus token = %v, want FailedPrecondition", err)
+	}
+	second, err := client.EmergencyStop(ctx, &octodashv1.EmergencyStopRequest{PrinterId: "printer-1", ConfirmToken: first.ConfirmToken})
+	if err != nil || !second.Stopped {
+		t.Fatalf("confirmed emergency stop = %v, %v", second, err)
+	}
+	if cmds := op.Commands(); len(cmds) != 2 || !strings.Contains(cmds[1], "M112") {
+		t.Errorf("commands after emergency stop = %v", cmds)
+	}
+}
@@ -19,65 +19,550 @@
 package handlers
 
 import (
-	"encoding/json"
+	"context"
+	"fmt"
 	"html/template"
 	"log"
 	"net/http"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/wmarchesi123/go-3dprint-client/config"
 	"github.com/wmarchesi123/go-3dprint-client/octoprint"
-	"github.com/wmarchesi123/go-3dprint-client/spoolman"
+	"github.com/wmarchesi123/octodash/internal/announce"
+	"github.com/wmarchesi123/octodash/internal/assets"
+	"github.com/wmarchesi123/octodash/internal/audit"
+	"github.com/wmarchesi123/octodash/internal/auth"
+	"github.com/wmarchesi123/octodash/internal/banners"
+	"github.com/wmarchesi123/octodash/internal/camarchive"
+	"github.com/wmarchesi123/octodash/internal/devices"
+	"github.com/wmarchesi123/octodash/internal/drying"
+	"github.com/wmarchesi123/octodash/internal/energy"
+	"github.com/wmarchesi123/octodash/internal/errcodes"
+	"github.com/wmarchesi123/octodash/internal/eventbus"
+	"github.com/wmarchesi123/octodash/internal/events"
+	"github.com/wmarchesi123/octodash/internal/extensions"
+	"github.com/wmarchesi123/octodash/internal/jobs"
+	"github.com/wmarchesi123/octodash/internal/lights"
+	"github.com/wmarchesi123/octodash/internal/middleware"
 	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/octoapi"
+	"github.com/wmarchesi123/octodash/internal/octobackup"
+	"github.com/wmarchesi123/octodash/internal/partlabel"
+	"github.com/wmarchesi123/octodash/internal/profiles"
+	"github.com/wmarchesi123/octodash/internal/profiling"
+	"github.com/wmarchesi123/octodash/internal/queue"
+	"github.com/wmarchesi123/octodash/internal/reservations"
+	"github.com/wmarchesi123/octodash/internal/retention"
+	"github.com/wmarchesi123/octodash/internal/rules"
+	"github.com/wmarchesi123/octodash/internal/settings"
+	"github.com/wmarchesi123/octodash/internal/slicer"
+	"github.com/wmarchesi123/octodash/internal/store"
+	"github.com/wmarchesi123/octodash/internal/teams"
+	"github.com/wmarchesi123/octodash/internal/thermal"
+	"github.com/wmarchesi123/octodash/internal/updates"
+	"github.com/wmarchesi123/octodash/internal/usage"
+	"github.com/wmarchesi123/octodash/internal/views"
+	"github.com/wmarchesi123/octodash/internal/watchdog"
+	"github.com/wmarchesi123/octodash/internal/webhooks"
+	"github.com/wmarchesi123/octodash/web"
 )
 
 type Handler struct {
-	config           *config.Config
-	mux              *http.ServeMux
-	octoprintClients map[string]*octoprint.Client
-	spoolmanClient   *spoolman.Client
+	config         *config.Config
+	settings       *settings.Settings
+	mux            *http.ServeMux
+	handler        http.Handler
+	assets         *assets.Assets
+	libraries      map[string]libraryScript
+	clients        atomic.Pointer[clientSet]
+	rotateMu       sync.Mutex // serializes API key rotations
+	spoolmanClient SpoolmanClient
+	spoolSources   []spoolSource
+	energyReaders  map[string]energy.Reader
+	energyMonitor  *energy.Monitor
+	auth           *auth.Authenticator
+	confirmations  *confirmations
+	commands       *commandQueue
+	idempotency    *idempotencyKeys
+	idle           *idleTracker
+	store          *store.Store
+	queue          *queue.Queue
+	slicer         *slicer.Slicer
+	profiles       *profiles.Library
+	views          *views.Views
+	teams          *teams.Teams
+	devices        *devices.Devices
+	usage          *usage.Ledger
+	jobHistory     *jobs.History
+	reservations   *reservations.Book
+	banners        *banners.Board
+	queueMu        sync.Mutex     // serializes read-modify-write updates to queue entries
+	jobs           sync.WaitGroup // background slicer runs
+	drying         *drying.Log
+	clock          Clock
+	logger         *log.Logger
+	meta           *statusMeta
+	events         *events.Log
+	polling        *pollPolicy
+	flaps          *flapGuard
+	completions    *completionTracker
+	recovery       *recoveryTracker
+	ejects         *ejectTracker
+	macros         []macro
+	auditTrail     *audit.Trail
+	webhooks       *webhooks.Outbox
+	bus            *eventbus.Bus
+	extensions     []extensions.Extension
+	rules          *rules.Engine
+	thermal        *thermal.Watch
+	firstLayer     *watchdog.FirstLayer
+	stalls         *watchdog.Stall
+	lights         *lights.Controller
+	announcer      *announce.Hub
+	speaker        *announce.Speaker
+	partLabels     *partlabel.Printer
+	cameras        *camarchive.Archiver
+	cameraKey      []byte // signs camera URLs
+	janitor        *retention.Janitor
+	instanceMu     sync.Mutex // serializes creating the Home Assistant instance ID
+	checklistMu    sync.Mutex // serializes checklist ticks
+	maintainMu     sync.Mutex // serializes database checks and compactions
+	importMu       sync.Mutex // serializes job history imports
+	plates         *plateTracker
+	octoBackups    *octobackup.Orchestrator
+	updates        *updates.Checker
+	ctx            context.Context
+	stop           context.CancelFunc
+	streams        context.Context // ended by Drain, or with ctx
+	endStreams     context.CancelFunc
 }
 
-func NewHandler() *Handler {
-	cfg, err := config.LoadConfig()
+// NewHandler builds a Handler for the printers in cfg. Clients, storage,
+// clock and logger default to real implementations built from cfg and s;
+// opts replace any of them.
+func NewHandler(cfg *config.Config, s *settings.Settings, opts ...Option) (*Handler, error) {
+	staticAssets, err := assets.New(web.Static())
 	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
+		return nil, fmt.Errorf("loading static assets: %w", err)
+	}
+
+	authenticator, err := auth.New(s.Auth)
+	if err != nil {
+		return nil, fmt.Errorf("configuring auth: %w", err)
 	}
 
 	h := &Handler{
-		config:           cfg,
-		mux:              http.NewServeMux(),
-		octoprintClients: make(map[string]*octoprint.Client),
-		spoolmanClient:   spoolman.NewClient(cfg.SpoolmanURL),
+		config:   cfg,
+		settings: s,
+		mux:      http.NewServeMux(),
+		assets:   staticAssets,
+		auth:     authenticator,
+		clock:    systemClock{},
+		logger:   defaultLogger(),
+	}
+	h.clients.Store(&clientSet{
+		status:  make(map[string]PrinterClient),
+		control: make(map[string]ControlClient),
+		keys:    make(map[string]string),
+	})
+	for _, opt := range opts {
+		opt(h)
+	}
+	if err := h.resolveLibraries(); err != nil {
+		return nil, fmt.Errorf("loading frontend libraries: %w", err)
+	}
+
+	// Instances may sit on a subpath or nonstandard port; drop trailing slashes
+	// so every URL built from the base comes out the same
+	for i, printer := range cfg.Printers {
+		if printer.OctoPrintURL == "" {
+			continue
+		}
+		if cfg.Printers[i].OctoPrintURL, err = octoapi.NormalizeBaseURL(printer.OctoPrintURL); err != nil {
+			return nil, fmt.Errorf("invalid OctoPrint URL for %s: %w", printer.Name, err)
+		}
+	}
+
+	if h.store == nil {
+		if h.store, err = store.Open(s.DataDir); err != nil {
+			return nil, fmt.Errorf("opening data store: %w", err)
+		}
 	}
 
-	// Initialize OctoPrint clients for each printer
+	// Fill in real implementations for anything not injected, using keys
+	// rotated at runtime over the configured ones
+	keys, err := store.List[apiKeyRecord](h.store, apiKeysBucket)
+	if err != nil {
+		return nil, fmt.Errorf("loading API keys: %w", err)
+	}
+	rotated := make(map[string]string)
+	for _, k := range keys {
+		rotated[k.PrinterID] = k.APIKey
+	}
+	clients := h.clients.Load()
 	for _, printer := range cfg.Printers {
-		h.octoprintClients[printer.ID] = octoprint.NewClient(printer.OctoPrintURL, printer.APIKey)
+		if key, ok := rotated[printer.ID]; ok {
+			printer.APIKey = key
+		}
+		clients.keys[printer.ID] = printer.APIKey
+		if _, ok := clients.status[printer.ID]; !ok {
+			clients.status[printer.ID] = octoprint.NewClient(printer.OctoPrintURL, printer.APIKey)
+		}
+		if _, ok := clients.control[printer.ID]; !ok {
+			clients.control[printer.ID] = h.newControlClient(printer)
+		}
+	}
+	h.spoolSources = h.newSpoolSources(cfg, s.Spoolman)
+	if h.energyReaders == nil {
+		if h.energyReaders, err = energyReaders(s.Energy); err != nil {
+			return nil, err
+		}
+	}
+	h.queue = queue.New(h.store)
+	h.slicer = slicer.New(s.Slicer)
+	h.profiles = profiles.New(h.store)
+	h.views = views.New(h.store)
+	h.teams = teams.New(h.store)
+	h.devices = devices.New(h.store)
+	h.auth.AddSource(h.deviceUser)
+	h.usage = usage.New(h.store)
+	h.jobHistory = jobs.New(h.store)
+	h.reservations = reservations.New(h.store)
+	h.banners = banners.New(h.store)
+	h.failInterruptedSlicing()
+	h.drying = drying.New(h.store)
+	h.events = events.New(h.store, s.Events.Retain)
+	h.auditTrail = audit.New(h.store)
+	h.janitor = retention.New(h.store, retentionDatasets(), s.Retention, h.clock.Now, h.logger)
+	h.janitor.Publish()
+	h.webhooks = webhooks.New(h.store, s.Webhooks, h.logger)
+	if s.EventBus.URL != nil {
+		if h.bus, err = eventbus.New(s.EventBus, h.logger); err != nil {
+			return nil, fmt.Errorf("configuring event bus: %w", err)
+		}
+	}
+	if h.completions, err = newCompletionTracker(h.store); err != nil {
+		return nil, fmt.Errorf("loading completed jobs: %w", err)
+	}
+	if h.recovery, err = newRecoveryTracker(h.store); err != nil {
+		return nil, fmt.Errorf("loading print checkpoints: %w", err)
+	}
+	if h.ejects, err = newEjectTracker(h.store); err != nil {
+		return nil, fmt.Errorf("loading pending ejects: %w", err)
+	}
+	if h.macros, err = newMacros(cfg, s.Macros); err != nil {
+		return nil, fmt.Errorf("configuring macros: %w", err)
+	}
+	if h.rules, err = newRules(cfg, s.Rules); err != nil {
+		return nil, fmt.Errorf("configuring rules: %w", err)
+	}
+	if s.Thermal.Enabled {
+		h.thermal = thermal.New(s.Thermal, s.Units.Temperature)
+	}
+	if s.FirstLayer.Timeout > 0 {
+		h.firstLayer = watchdog.NewFirstLayer(s.FirstLayer.Percent, s.FirstLayer.Timeout)
+	}
+	if s.Stall.Window > 0 {
+		h.stalls = watchdog.NewStall(s.Stall.Window)
+	}
+	h.announcer = announce.NewHub()
+	if s.Announce.TTSURL != "" {
+		h.speaker = announce.NewSpeaker(s.Announce, h.logger)
+	}
+	if s.PartLabels.Address != "" {
+		h.partLabels = partlabel.NewPrinter(s.PartLabels, s.Timezone, h.logger)
 	}
+	if h.lights, err = h.newLights(cfg, s.Lights); err != nil {
+		return nil, fmt.Errorf("configuring status lights: %w", err)
+	}
+	h.cameras = h.newCameraArchive(cfg, s)
+	if h.cameraKey, err = h.cameraSigningKey(); err != nil {
+		return nil, fmt.Errorf("loading camera signing key: %w", err)
+	}
+	h.confirmations = newConfirmations(h.clock)
+	h.commands = newCommandQueue(h.clock)
+	h.idempotency = newIdempotencyKeys(h.clock)
+	h.idle = newIdleTracker(s.Idle.After, s.Idle.Mode, h.clock)
+	h.meta = newStatusMeta(h.clock.Now())
+	h.polling = newPollPolicy(s.Poll)
+	h.flaps = newFlapGuard(s.Poll)
+	h.plates = newPlateTracker()
+
+	// Start polling smart plugs for printers that have one
+	ctx, stop := context.WithCancel(context.Background())
+	h.ctx, h.stop = ctx, stop
+	h.streams, h.endStreams = context.WithCancel(ctx)
+	if err := h.startExtensions(ctx); err != nil {
+		stop()
+		return nil, err
+	}
+	h.energyMonitor = energy.NewMonitor(h.energyReaders, s.Energy.PollInterval)
+	go h.energyMonitor.Run(ctx)
+
+	h.startBackups(ctx)
+	h.startOctoPrintBackups(ctx)
+	h.startUpdateChecks(ctx)
+
+	// Keep the event log current even when no dashboard is open
+	if s.Events.PollInterval > 0 {
+		go h.watchStatuses(ctx, s.Events.PollInterval)
+	}
+	if s.Queue.DispatchInterval > 0 {
+		go h.dispatchQueue(ctx, s.Queue.DispatchInterval)
+	}
+	if h.webhooks.Enabled() {
+		go h.webhooks.Run(ctx, h.clock.Now)
+	}
+	if h.bus != nil {
+		go h.bus.Run(ctx)
+	}
+	if h.lights != nil {
+		go h.lights.Run(ctx)
+	}
+	if h.speaker != nil {
+		go h.speaker.Run(ctx)
+	}
+	if h.partLabels != nil {
+		go h.partLabels.Run(ctx)
+	}
+	if h.cameras != nil {
+		go h.cameras.Run(ctx)
+	}
+	go h.janitor.Run(ctx)
+	go h.idempotency.run(ctx)
+	go h.maintainDatabase(ctx)
 
 	h.setupRoutes()
-	return h
+	h.setupMiddleware()
+	return h, nil
+}
+
+// energyReaders builds a reader for every configured smart plug
+func energyReaders(e settings.EnergySettings) (map[string]energy.Reader, error) {
+	readers := make(map[string]energy.Reader)
+	for id, plug := range e.Plugs {
+		reader, err := energy.NewReader(plug.Type, plug.Address)
+		if err != nil {
+			return nil, fmt.Errorf("configuring plug for %s: %w", id, err)
+		}
+		readers[id] = reader
+	}
+	return readers, nil
+}
+
+// Close releases resources held by the handler
+func (h *Handler) Close() error {
+	h.stop()
+	h.jobs.Wait()
+	return h.store.Close()
+}
+
+// Drain ends long-lived streams such as announcements, so a graceful
+// shutdown doesn't wait for displays to hang up
+func (h *Handler) Drain() {
+	h.endStreams()
 }
 
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	// CORS headers
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	h.handler.ServeHTTP(w, r)
+}
 
-	if r.Method == "OPTIONS" {
-		w.WriteHeader(http.StatusOK)
-		return
+// setupMiddleware wraps the mux; the first middleware listed runs first
+func (h *Handler) setupMiddleware() {
+	chain := []middleware.Middleware{
+		middleware.RequestIDs,
+		middleware.Recover(h.logger),
+	}
+	if h.settings.LogRequests {
+		chain = append(chain, middleware.Logging(h.logger))
 	}
+	chain = append(chain,
+		middleware.CORS,
+		h.securityHeaders(),
+		h.auth.Identify,
+		h.signedCameras,
+		h.scopePrinters,
+		middleware.Gzip,
+		h.idempotent,
+	)
 
-	h.mux.ServeHTTP(w, r)
+	h.handler = middleware.Chain(h.mux, chain...)
 }
 
 func (h *Handler) setupRoutes() {
-	h.mux.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.Dir("web/static"))))
+	h.mux.Handle("/static/", h.assets.Handler())
 	h.mux.HandleFunc("/", h.handleDashboard)
+	h.mux.HandleFunc("GET /manifest.webmanifest", h.handleManifest)
+	h.mux.HandleFunc("GET /sw.js", h.handleServiceWorker)
+	h.mux.HandleFunc("GET /diagnostics", h.handleDiagnosticsPage)
+	h.mux.HandleFunc("GET /api/diagnostics", h.auth.Require(auth.RoleAdmin, h.handleDiagnostics))
 	h.mux.HandleFunc("/api/status", h.handleStatus)
+	h.mux.HandleFunc("GET /api/search", h.handleSearch)
+	h.mux.HandleFunc("GET /api/printers/{id}/status", h.handlePrinterStatus)
+	h.mux.HandleFunc("GET /api/printers/{id}/events", h.handlePrinterEvents)
+	h.mux.HandleFunc("GET /api/printers/{id}/diagnostics", h.handlePrinterDiagnostics)
+	h.mux.HandleFunc("GET /api/printers/{id}/objects", h.handlePrinterObjects)
+	h.mux.HandleFunc("GET /api/cameras", h.auth.Require(auth.RoleViewer, h.handleCameras))
+	h.mux.HandleFunc("GET /api/printers/{id}/camera/snapshot", h.auth.Require(auth.RoleViewer, h.handleCameraSnapshot))
+	h.mux.HandleFunc("GET /api/printers/{id}/camera/stream", h.auth.Require(auth.RoleViewer, h.handleCameraStream))
+	h.mux.HandleFunc("GET /embed/{id}", h.handleEmbed)
+	h.mux.HandleFunc("GET /api/widget", h.handleWidget)
+	h.mux.HandleFunc("GET /api/widget/{id}", h.handlePrinterWidget)
+	h.mux.HandleFunc("GET /api/eink", h.handleEink)
+	h.mux.HandleFunc("GET /api/lights", h.handleLights)
+	h.mux.HandleFunc("GET /api/announcements", h.handleAnnouncements)
+	h.mux.HandleFunc("GET /api/banners", h.handleBannerList)
+	h.mux.HandleFunc("GET /api/announcements/stream", h.handleAnnouncementStream)
+	h.mux.HandleFunc("POST /api/announcements", h.auth.Require(auth.RoleOperator, h.handleAnnounce))
+	h.mux.HandleFunc("GET /api/intents", h.handleIntentList)
+	h.mux.HandleFunc("POST /api/intents", h.auth.Require(auth.RoleViewer, h.handleIntent))
+	h.mux.HandleFunc("GET /api/triggers/{type}", h.handleTriggerFeed)
+	h.mux.HandleFunc("GET /ifttt/v1/status", h.requireIFTTT(h.handleIFTTTStatus))
+	h.mux.HandleFunc("POST /ifttt/v1/test/setup", h.requireIFTTT(h.handleIFTTTSetup))
+	h.mux.HandleFunc("POST /ifttt/v1/triggers/{slug}", h.requireIFTTT(h.handleIFTTTTrigger))
+	h.mux.HandleFunc("GET /api/homeassistant", h.requireHomeAssistant(h.handleHomeAssistant))
+	h.mux.HandleFunc("GET /api/homeassistant/states", h.requireHomeAssistant(h.handleHomeAssistantStates))
+	h.mux.HandleFunc("GET /api/homeassistant/rest.yaml", h.requireHomeAssistant(h.handleHomeAssistantYAML))
+	h.mux.HandleFunc("POST /api/emergency-stop", h.auth.Require(auth.RoleOperator, h.handleFarmEmergencyStop))
+	h.mux.HandleFunc("POST /api/printers/{id}/emergency-stop", h.auth.RequirePrinter(auth.RoleOperator, "id", h.handlePrinterEmergencyStop))
+	h.mux.HandleFunc("POST /api/bulk/{action}", h.auth.Require(auth.RoleOperator, h.handleBulkAction))
+	h.mux.HandleFunc("POST /api/printers/{id}/acknowledge", h.auth.RequirePrinter(auth.RoleOperator, "id", h.handleAcknowledge))
+	h.mux.HandleFunc("GET /api/printers/{id}/checklist", h.handleChecklistGet)
+	h.mux.HandleFunc("POST /api/printers/{id}/checklist", h.auth.RequirePrinter(auth.RoleOperator, "id", h.handleChecklistTick))
+	h.mux.HandleFunc("POST /api/printers/{id}/clip", h.auth.RequirePrinter(auth.RoleOperator, "id", h.handleSaveClip))
+	h.mux.HandleFunc("DELETE /api/printers/{id}/interrupted", h.auth.RequirePrinter(auth.RoleOperator, "id", h.handleInterruptedDismiss))
+	h.mux.HandleFunc("POST /api/printers/{id}/spool", h.auth.RequirePrinter(auth.RoleOperator, "id", h.handleLoadSpool))
+	h.mux.HandleFunc("GET /api/printers/{id}/macros", h.handlePrinterMacros)
+	h.mux.HandleFunc("POST /api/printers/{id}/macros/{macro}", h.auth.Require(auth.RoleViewer, h.handleMacroRun))
+	h.mux.HandleFunc("POST /api/printers/{id}/objects/{object}/cancel", h.auth.RequirePrinter(auth.RoleOperator, "id", h.handleObjectCancel))
+	h.mux.HandleFunc("GET /api/commands/{id}", h.auth.Require(auth.RoleViewer, h.handleCommandGet))
+	h.mux.HandleFunc("POST /api/printers/{id}/{action}", h.auth.RequirePrinter(auth.RoleOperator, "id", h.handleJobAction))
+	h.mux.HandleFunc("GET /api/updates", h.handleUpdates)
+	h.mux.HandleFunc("POST /api/updates/check", h.auth.Require(auth.RoleOperator, h.handleUpdateCheck))
+	h.mux.HandleFunc("GET /labels/spools", h.handleSpoolLabels)
+	h.mux.HandleFunc("GET /api/spools/{id}/qr.png", h.handleSpoolQR)
+	h.mux.HandleFunc("GET /load", h.handleLoadPage)
+	h.mux.HandleFunc("GET /jobs/{id...}", h.handleJobPage)
+	h.mux.HandleFunc("GET /api/spools/{id}/drying", h.handleDryingList)
+	h.mux.HandleFunc("POST /api/spools/{id}/drying", h.auth.Require(auth.RoleOperator, h.handleDryingStart))
+	h.mux.HandleFunc("POST /api/spools/{id}/drying/stop", h.auth.Require(auth.RoleOperator, h.handleDryingStop))
+	h.mux.HandleFunc("GET /api/materials/legend", h.handleMaterialLegend)
+	h.mux.HandleFunc("GET /api/forecast", h.handleForecast)
+	h.mux.HandleFunc("GET /api/forecast/shopping-list", h.handleShoppingList)
+	h.mux.HandleFunc("GET /floorplan", h.handleFloorPlanPage)
+	h.mux.HandleFunc("GET /floorplan/image", h.handleFloorPlanImage)
+	h.mux.HandleFunc("GET /api/floorplan", h.handleFloorPlan)
+	h.mux.HandleFunc("GET /api/queue", h.handleQueueList)
+	h.mux.HandleFunc("POST /api/queue", h.auth.Require(h.queueSubmitRole(), h.handleQueueAdd))
+	h.mux.HandleFunc("POST /api/queue/models", h.auth.Require(h.queueSubmitRole(), h.handleQueueModelUpload))
+	h.mux.HandleFunc("PATCH /api/queue/reorder", h.auth.Require(auth.RoleOperator, h.handleQueueReorder))
+	h.mux.HandleFunc("PATCH /api/queue/{id}", h.auth.Require(auth.RoleOperator, h.handleQueueUpdate))
+	h.mux.HandleFunc("GET /api/queue/pending", h.auth.Require(auth.RoleOperator, h.handleQueuePending))
+	h.mux.HandleFunc("POST /api/queue/{id}/approve", h.auth.Require(auth.RoleOperator, h.handleQueueApprove))
+	h.mux.HandleFunc("POST /api/queue/{id}/reject", h.auth.Require(auth.RoleOperator, h.handleQueueReject))
+	h.mux.HandleFunc("GET /api/queue/{id}/preview.png", h.handleQueuePreview)
+	h.mux.HandleFunc("POST /api/queue/{id}/slice", h.auth.Require(auth.RoleOperator, h.handleQueueSlice))
+	h.mux.HandleFunc("GET /api/slicer/profiles", h.handleSlicerProfiles)
+	h.mux.HandleFunc("GET /queue", h.handleQueuePage)
+	h.mux.HandleFunc("GET /api/views", h.auth.Require(auth.RoleViewer, h.handleViewList))
+	h.mux.HandleFunc("POST /api/views", h.auth.Require(auth.RoleViewer, h.handleViewCreate))
+	h.mux.HandleFunc("PUT /api/views/default", h.auth.Require(auth.RoleViewer, h.handleViewDefault))
+	h.mux.HandleFunc("PUT /api/views/{id}", h.auth.Require(auth.RoleViewer, h.handleViewUpdate))
+	h.mux.HandleFunc("DELETE /api/views/{id}", h.auth.Require(auth.RoleViewer, h.handleViewDelete))
+	h.mux.HandleFunc("GET /api/teams", h.handleTeams)
+	h.mux.HandleFunc("GET /api/me", h.handleMe)
+	h.mux.HandleFunc("GET /pair", h.handlePairPage)
+	h.mux.HandleFunc("POST /api/pair", h.handlePairRequest)
+	h.mux.HandleFunc("GET /api/pair/{request}", h.handlePairPoll)
+	h.mux.HandleFunc("GET /api/reservations", h.handleReservationList)
+	h.mux.HandleFunc("POST /api/reservations", h.auth.Require(auth.RoleOperator, h.handleReservationCreate))
+	h.mux.HandleFunc("DELETE /api/reservations/{id}", h.auth.Require(auth.RoleOperator, h.handleReservationCancel))
+	h.mux.HandleFunc("GET /calendar.ics", h.handleCalendar)
+	h.mux.HandleFunc("GET /api/usage", h.auth.Require(auth.RoleViewer, h.handleUsage))
+	h.mux.HandleFunc("GET /api/jobs", h.auth.Require(auth.RoleViewer, h.handleJobs))
+	h.mux.HandleFunc("GET /api/jobs/stats", h.auth.Require(auth.RoleViewer, h.handleJobStats))
+	h.mux.HandleFunc("GET /api/reports/handover", h.auth.Require(auth.RoleViewer, h.handleHandoverReport))
+	h.mux.HandleFunc("GET /api/profiles", h.handleProfileList)
+	h.mux.HandleFunc("GET /api/profiles/{name}", h.handleProfileGet)
+	h.mux.HandleFunc("PUT /api/profiles/{name}", h.auth.Require(auth.RoleAdmin, h.handleProfilePut))
+	h.mux.HandleFunc("DELETE /api/profiles/{name}", h.auth.Require(auth.RoleAdmin, h.handleProfileDelete))
+	h.mux.HandleFunc("DELETE /api/queue/{id}", h.auth.Require(auth.RoleOperator, h.handleQueueRemove))
+	h.mux.HandleFunc("POST /api/admin/banners", h.auth.Require(auth.RoleAdmin, h.handleBannerPost))
+	h.mux.HandleFunc("DELETE /api/admin/banners/{id}", h.auth.Require(auth.RoleAdmin, h.handleBannerRemove))
+	h.mux.HandleFunc("PUT /api/admin/floorplan", h.auth.Require(auth.RoleAdmin, h.handleFloorPlanLayout))
+	h.mux.HandleFunc("PUT /api/admin/floorplan/image", h.auth.Require(auth.RoleAdmin, h.handleFloorPlanUpload))
+	h.mux.HandleFunc("DELETE /api/admin/floorplan", h.auth.Require(auth.RoleAdmin, h.handleFloorPlanDelete))
+	h.mux.HandleFunc("PUT /api/admin/teams/{name}", h.auth.Require(auth.RoleAdmin, h.handleTeamPut))
+	h.mux.HandleFunc("DELETE /api/admin/teams/{name}", h.auth.Require(auth.RoleAdmin, h.handleTeamDelete))
+	h.mux.HandleFunc("GET /api/admin/devices", h.auth.Require(auth.RoleAdmin, h.handleDevices))
+	h.mux.HandleFunc("POST /api/admin/devices/pair", h.auth.Require(auth.RoleAdmin, h.handleDeviceApprove))
+	h.mux.HandleFunc("DELETE /api/admin/devices/{id}", h.auth.Require(auth.RoleAdmin, h.handleDeviceRevoke))
+	h.mux.HandleFunc("GET /api/admin/webhooks/deliveries", h.auth.Require(auth.RoleAdmin, h.handleWebhookDeliveries))
+	h.mux.HandleFunc("POST /api/admin/webhooks/deliveries/{id}/retry", h.auth.Require(auth.RoleAdmin, h.handleWebhookRetry))
+	h.mux.HandleFunc("GET /api/admin/audit", h.auth.Require(auth.RoleAdmin, h.handleAudit))
+	h.mux.HandleFunc("GET /api/admin/usage", h.auth.Require(auth.RoleAdmin, h.handleUsageReport))
+	h.mux.HandleFunc("GET /api/admin/jobs/import", h.auth.Require(auth.RoleAdmin, h.handleJobImports))
+	h.mux.HandleFunc("POST /api/admin/jobs/import", h.auth.Require(auth.RoleAdmin, h.handleJobImport))
+	h.mux.HandleFunc("GET /api/admin/backup", h.auth.Require(auth.RoleAdmin, h.handleBackup))
+	h.mux.HandleFunc("GET /api/admin/export", h.auth.Require(auth.RoleAdmin, h.handleExport))
+	h.mux.HandleFunc("POST /api/admin/import", h.auth.Require(auth.RoleAdmin, h.handleImport))
+	h.mux.HandleFunc("POST /api/admin/restore", h.auth.Require(auth.RoleAdmin, h.handleRestore))
+	if h.settings.Profiling.Enabled && h.settings.Profiling.Addr == "" {
+		h.mux.HandleFunc("/debug/", h.auth.Require(auth.RoleAdmin, profiling.Handler().ServeHTTP))
+	}
+	h.mux.HandleFunc("PUT /api/admin/printers/{id}/api-key", h.auth.Require(auth.RoleAdmin, h.handleRotateAPIKey))
+	h.mux.HandleFunc("POST /api/admin/database/check", h.auth.Require(auth.RoleAdmin, h.handleDatabaseCheck))
+	h.mux.HandleFunc("POST /api/admin/database/compact", h.auth.Require(auth.RoleAdmin, h.handleDatabaseCompact))
+	h.mux.HandleFunc("GET /api/admin/retention", h.auth.Require(auth.RoleAdmin, h.handleRetention))
+	h.mux.HandleFunc("POST /api/admin/retention/run", h.auth.Require(auth.RoleAdmin, h.handleRetentionRun))
+	h.mux.HandleFunc("GET /api/admin/camera-archive", h.auth.Require(auth.RoleAdmin, h.handleCameraArchive))
+	h.mux.HandleFunc("GET /api/admin/octoprint-backups", h.auth.Require(auth.RoleAdmin, h.handleOctoPrintBackups))
+	h.mux.HandleFunc("POST /api/admin/octoprint-backups/{id}", h.auth.Require(auth.RoleAdmin, h.handleOctoPrintBackupTrigger))
+	h.mux.HandleFunc("GET /api/admin/octoprint-backups/{id}/{name}", h.auth.Require(auth.RoleAdmin, h.handleOctoPrintBackupDownload))
+	if h.settings.SSO.Enabled {
+		h.mux.HandleFunc("POST /api/printers/{id}/sso", h.auth.RequirePrinter(auth.RoleOperator, "id", h.handleSSOLink))
+		h.mux.HandleFunc("POST /api/printers/{id}/sso/session", h.auth.RequirePrinter(auth.RoleOperator, "id", h.handleSSOSession))
+		h.mux.HandleFunc("DELETE /api/printers/{id}/sso", h.auth.RequirePrinter(auth.RoleOperator, "id", h.handleSSOUnlink))
+	}
+	if h.settings.Proxy.Enabled {
+		h.mux.HandleFunc("/proxy/{id}/{path...}", h.auth.RequirePrinter(auth.RoleOperator, "id", h.handleProxy))
+	}
+	h.extensionRoutes()
+}
+
+// runAction applies fn to every printer concurrently and collects per-printer results
+func (h *Handler) runAction(printers []config.Printer, fn func(config.Printer) error) []models.ActionResult {
+	results := make([]models.ActionResult, len(printers))
+
+	var wg sync.WaitGroup
+	for i, printer := range printers {
+		wg.Add(1)
+		go func(i int, p config.Printer) {
+			defer wg.Done()
+
+			result := models.ActionResult{PrinterID: p.ID, Name: p.Name, Success: true}
+			if err := fn(p); err != nil {
+				h.logger.Printf("Action failed for %s: %v", p.Name, err)
+				result.Success = false
+				result.Error = err.Error()
+			}
+			results[i] = result
+		}(i, printer)
+	}
+	wg.Wait()
+
+	return results
+}
+
+func (h *Handler) findPrinter(id string) (config.Printer, bool) {
+	for _, p := range h.config.Printers {
+		if p.ID == id {
+			return p, true
+		}
+	}
+	return config.Printer{}, false
 }
 
 func (h *Handler) handleDashboard(w http.ResponseWriter, r *http.Request) {
@@ -87,11 +572,15 @@ func (h *Handler) handleDashboard(w http.ResponseWriter, r *http.Request) {
 <head>
     <title>OctoDash - Printer Dashboard</title>
     <meta name="viewport" content="width=device-width, initial-scale=1">
-    <link rel="stylesheet" href="/static/style.css">
-    <script src="//unpkg.com/alpinejs" defer></script>
+    <meta name="theme-color" content="#ff6b00">
+    <link rel="manifest" href="/manifest.webmanifest">
+    <link rel="apple-touch-icon" href="{{asset "icon-192.png"}}">
+    <link rel="stylesheet" href="{{asset "style.css"}}">
+    {{with library "alpinejs"}}<script src="{{.Src}}"{{if .Integrity}} integrity="{{.Integrity}}"{{end}} crossorigin="anonymous" defer></script>{{end}}
 </head>
 <body>
-    <div class="dashboard" x-data="dashboard" x-init="init()">
+    <div class="dashboard" x-data="dashboard" x-init="init()"
+         :class="screen?.idle ? 'idle-' + screen.mode : ''">
         <!-- Loading State -->
         <div x-show="loading" class="loading-overlay">
             <div class="loading-spinner"></div>
@@ -103,25 +592,155 @@ func (h *Handler) handleDashboard(w http.ResponseWriter, r *http.Request) {
             <p x-text="error"></p>
         </div>
 
+        <!-- Farm-wide banners posted by admins -->
+        <template x-for="banner in banners" :key="banner.id">
+            <div class="farm-banner" :class="'farm-banner-' + banner.severity" x-text="banner.message"></div>
+        </template>
+
+        <!-- Search -->
+        <div x-show="!loading" class="search-box" @click.outside="searchResults = null">
+            <input type="search" placeholder="Search files, jobs, spools..."
+                   x-model="searchQuery"
+                   @input.debounce.400ms="search()"
+                   @keydown.escape.stop="searchQuery = ''; searchResults = null">
+            <div x-show="searchResults" class="search-results">
+                <template x-for="job in searchResults?.jobs || []" :key="'job-' + job.printer_id">
+                    <div class="search-result">
+                        <span class="search-kind">Printing</span>
+                        <span x-text="job.file_name"></span>
+                        <span class="search-meta" x-text="job.printer_name + ' · ' + Math.round(job.completion) + '%'"></span>
+                    </div>
+                </template>
+                <template x-for="past in searchResults?.history || []" :key="'history-' + past.id">
+                    <div class="search-result">
+                        <span class="search-kind">Printed</span>
+                        <span x-text="past.file_name"></span>
+                        <span class="search-meta" x-text="past.printer_name + ' · ' + past.result + ' ' + new Date(past.finished_at).toLocaleDateString()"></span>
+                    </div>
+                </template>
+                <template x-for="file in searchResults?.files || []" :key="'file-' + file.printer_id + file.path">
+                    <div class="search-result">
+                        <span class="search-kind">File</span>
+                        <span x-text="file.name"></span>
+                        <span class="search-meta" x-text="file.printer_name"></span>
+                    </div>
+                </template>
+                <template x-for="spool in searchResults?.spools || []" :key="'spool-' + (spool.spoolman || '') + '-' + spool.id">
+                    <div class="search-result">
+                        <span class="spool-color-dot" :style="'background-color: ' + spool.color"></span>
+                        <span x-text="spool.name + ' | ' + spool.material"></span>
+                        <span class="search-meta" x-text="formatWeight(spool.remaining) + ' left'"></span>
+                    </div>
+                </template>
+                <div x-show="searchResults && !searchResults.jobs.length && !searchResults.history.length && !searchResults.files.length && !searchResults.spools.length"
+                     class="search-empty">No matches</div>
+            </div>
+        </div>
+
+        <!-- Saved Views -->
+        <div x-show="!loading && views.length" class="view-picker">
+            <select x-model="activeViewId">
+                <option value="">All printers</option>
+                <template x-for="view in views" :key="view.id">
+                    <option :value="view.id" x-text="view.name" :selected="view.id === activeViewId"></option>
+                </template>
+            </select>
+            <button x-show="activeViewId !== defaultViewId" @click="makeDefaultView()">Make default</button>
+        </div>
+
         <!-- Printer Grid -->
-        <div x-show="!loading && !error" class="printer-grid" :class="'printers-' + printers.length">
-            <template x-for="printer in printers" :key="printer.id">
-                <div class="printer-card" @click="openPrinter(printer)">
+        <div x-show="!loading && !error" class="printer-grid"
+             :class="['printers-' + visiblePrinters.length, activeView?.density === 'compact' ? 'density-compact' : '']"
+             :style="screen?.idle ? idleShiftStyle : ''">
+            <template x-for="printer in visiblePrinters" :key="printer.id">
+                <div class="printer-card" @click="openPrinter(printer)"
+                     :style="printer.current_spool?.material_color ? 'border-color: ' + printer.current_spool.material_color : ''">
                     <h2 class="printer-name" x-text="printer.name"></h2>
+                    <div class="printer-tags" x-show="printer.tags">
+                        <template x-for="[key, value] in Object.entries(printer.tags || {})" :key="key">
+                            <span class="printer-tag" x-text="value ? key + '=' + value : key"></span>
+                        </template>
+                    </div>
+                    <div class="update-badge" x-show="printer.updates?.available"
+                         :title="updatesTitle(printer.updates)"
+                         x-text="printer.updates?.available + (printer.updates?.available === 1 ? ' update' : ' updates') + ' available'"></div>
+                    <button class="estop-button" x-show="printer.status !== 'offline' && canControl(printer)"
+                            @click.stop="emergencyStop(printer)">E-STOP</button>
                     
                     <!-- Printer Image Area -->
                     <div class="printer-image">
                         <!-- Show thumbnail if printing, otherwise stock image -->
-                        <img :src="printer.thumbnail_url || '/static/prusa-mk4s.png'"
+                        <img x-show="!liveCamera[printer.id]" :src="printer.thumbnail_url || '{{asset "prusa-mk4s.png"}}'"
                              :alt="printer.name"
-                             @error="$event.target.src = '/static/prusa-mk4s.png'">
+                             @error="$event.target.src = '{{asset "prusa-mk4s.png"}}'">
+                        <img x-show="liveCamera[printer.id]" :src="liveCamera[printer.id] || ''" :alt="printer.name + ' camera'">
+                        <button class="camera-button" x-show="cameras[printer.id]" @click.stop="toggleCamera(printer)"
+                                x-text="liveCamera[printer.id] ? 'Picture' : 'Camera'"></button>
                     </div>
                     
                     <!-- Status Area -->
-                    <div class="printer-status" :class="'status-' + printer.status">
+                    <div class="printer-status" :class="'status-' + displayStatus(printer)">
                         <div class="status-text">
                             <span class="status-label">Status:</span>
-                            <span class="status-value" x-text="formatStatus(printer.status)"></span>
+                            <span class="status-value" :class="{ 'status-pending': pendingCommands[printer.id] }"
+                                  x-text="formatStatus(displayStatus(printer))"></span>
+                        </div>
+
+                        <!-- Job controls; the status shows the expected state until a poll confirms it -->
+                        <div x-show="canControl(printer) && ['printing', 'paused'].includes(displayStatus(printer))" class="macro-buttons">
+                            <button class="macro-button" x-show="displayStatus(printer) === 'printing'" @click.stop="jobAction(printer, 'pause')">Pause</button>
+                            <button class="macro-button" x-show="displayStatus(printer) === 'paused'" @click.stop="jobAction(printer, 'resume')">Resume</button>
+                            <button class="macro-button" @click.stop="jobAction(printer, 'cancel')">Cancel</button>
+                        </div>
+
+                        <!-- Finished job still on the bed -->
+                        <div x-show="printer.completed" class="completed-info">
+                            <span class="completed-file" x-text="printer.completed?.file"></span>
+                            <button class="ack-button" x-show="canControl(printer)" @click.stop="acknowledge(printer)">Bed cleared</button>
+                        </div>
+
+                        <!-- Firmware or connection error explained -->
+                        <div x-show="printer.diagnosis" class="interrupted-info">
+                            <span class="interrupted-text" x-text="diagnosisLabel(printer.diagnosis)"></span>
+                        </div>
+
+                        <!-- File position frozen while still printing -->
+                        <div x-show="printer.stall" class="interrupted-info">
+                            <span class="interrupted-text" x-text="stallLabel(printer.stall)"></span>
+                        </div>
+
+                        <!-- Job that vanished mid-print, likely a power loss -->
+                        <div x-show="printer.interrupted" class="interrupted-info">
+                            <span class="interrupted-text" x-text="interruptedLabel(printer.interrupted)"></span>
+                            <button class="ack-button" x-show="canControl(printer)" @click.stop="dismissInterrupted(printer)">Dismiss</button>
+                        </div>
+
+                        <!-- Pre-print checklist gating the queue -->
+                        <div x-show="printer.checklist && printer.status === 'idle'" class="checklist">
+                            <template x-for="item in printer.checklist?.items || []" :key="item.name">
+                                <label class="checklist-item" @click.stop>
+                                    <input type="checkbox" :checked="item.checked" :disabled="!canControl(printer)" @change="tickChecklist(printer, item, $event.target.checked)">
+                                    <span x-text="item.name"></span>
+                                </label>
+                            </template>
+                        </div>
+
+                        <!-- Configured gcode macros -->
+                        <div x-show="macrosFor(printer).length && printer.status !== 'offline'" class="macro-buttons">
+                            <template x-for="macro in macrosFor(printer)" :key="macro.id">
+                                <button class="macro-button" @click.stop="runMacro(printer, macro)" x-text="macro.name"></button>
+                            </template>
+                        </div>
+
+                        <!-- Sections added by extensions -->
+                        {{range .CardSections}}
+                        <div class="extension-section" x-show="printer.extensions?.['{{.Name}}']">{{.HTML}}</div>
+                        {{end}}
+
+                        <!-- Running or next reservation -->
+                        <div class="reservation-info">
+                            <span class="reservation-text" x-show="printer.reservation" x-text="reservationLabel(printer.reservation)"></span>
+                            <button class="reserve-button" x-show="!printer.reservation" @click.stop="reserve(printer)">Reserve</button>
                         </div>
                         
                         <!-- Progress Bar (if printing) -->
@@ -131,6 +750,23 @@ func (h *Handler) handleDashboard(w http.ResponseWriter, r *http.Request) {
                             </div>
                             <div class="progress-text">
                                 <span x-text="Math.round(printer.progress?.completion || 0) + '%'"></span>
+                                <span class="filament-length" x-show="printer.progress?.filament_length"
+                                      x-text="' · ' + formatLength(printer.progress?.filament_length) + ' filament'"></span>
+                            </div>
+                            <button class="objects-button" @click.stop="toggleObjects(printer)"
+                                    x-text="plateObjects[printer.id] ? 'Hide objects' : 'Objects'"></button>
+
+                            <!-- Labelled objects on the plate, each cancellable on its own -->
+                            <div x-show="plateObjects[printer.id]" class="plate-objects">
+                                <div x-show="plateObjects[printer.id]?.length === 0" class="plate-object">No labelled objects in this file</div>
+                                <template x-for="object in plateObjects[printer.id] || []" :key="object.id">
+                                    <div class="plate-object" :class="'object-' + object.state">
+                                        <span class="object-name" x-text="object.name"></span>
+                                        <span class="object-state" x-text="object.state === 'cancelled' ? 'cancelled' : Math.round(object.completion) + '%'"></span>
+                                        <button class="object-cancel" x-show="(object.state === 'pending' || object.state === 'printing') && canControl(printer)"
+                                                @click.stop="cancelObject(printer, object)">Cancel</button>
+                                    </div>
+                                </template>
                             </div>
                         </div>
 
@@ -142,9 +778,12 @@ func (h *Handler) handleDashboard(w http.ResponseWriter, r *http.Request) {
 								<div class="spool-title">
 									<div class="spool-name">
 										<span x-text="printer.current_spool?.name || 'Unknown'"></span>
-										<span class="spool-material" x-text="' | ' + (printer.current_spool?.material || '')"></span>
+										<span class="spool-material" x-text="' | ' + (printer.current_spool?.material || '')"
+											  :style="printer.current_spool?.material_color ? 'color: ' + printer.current_spool.material_color : ''"></span>
 									</div>
 									<div class="spool-vendor" x-text="printer.current_spool?.vendor"></div>
+									<div class="spool-dried" x-show="printer.current_spool?.hygroscopic"
+										 x-text="formatDried(printer.current_spool)"></div>
 									<div class="spool-stats">
 										<span class="stat-item">
 											<span class="stat-label">Total Weight</span>
@@ -185,6 +824,7 @@ func (h *Handler) handleDashboard(w http.ResponseWriter, r *http.Request) {
                             <div class="time-item">
                                 <span class="time-label">Remaining:</span>
                                 <span x-text="formatTime(printer.progress?.print_time_left)"></span>
+                                <span class="eta" x-show="printer.progress?.eta" x-text="'ETA ' + formatClock(printer.progress?.eta)"></span>
                             </div>
                         </div>
                         
@@ -199,11 +839,60 @@ func (h *Handler) handleDashboard(w http.ResponseWriter, r *http.Request) {
                                 <span x-text="formatTemp(printer.temperatures?.bed_actual, printer.temperatures?.bed_target)"></span>
                             </div>
                         </div>
+
+                        <!-- Power Info -->
+                        <div x-show="printer.power" class="temp-info power-info">
+                            <div class="temp-item">
+                                <span class="temp-label">Power:</span>
+                                <span x-text="formatPower(printer.power?.watts)"></span>
+                            </div>
+                            <div class="temp-item" x-show="printer.power?.job_energy_kwh">
+                                <span class="temp-label">This Job:</span>
+                                <span x-text="formatEnergy(printer.power)"></span>
+                            </div>
+                        </div>
                     </div>
                 </div>
             </template>
         </div>
 
+        <!-- Material color legend -->
+        <div x-show="!loading && legend.length" class="material-legend">
+            <template x-for="entry in legend" :key="entry.material">
+                <span class="material-legend-item">
+                    <span class="spool-color-dot" :style="'background-color: ' + entry.color"></span>
+                    <span x-text="entry.material"></span>
+                </span>
+            </template>
+        </div>
+
+        <!-- Filament running low -->
+        <div x-show="!loading && reorder.length" class="reorder-banner">
+            <span class="search-kind">Reorder soon</span>
+            <template x-for="m in reorder" :key="m.material">
+                <span class="reorder-item" :title="formatWeight(m.remaining_weight) + ' left'"
+                      x-text="m.material + ' (' + Math.ceil(m.days_left) + 'd)'"></span>
+            </template>
+            <a href="/api/forecast/shopping-list">Shopping list</a>
+        </div>
+
+        <!-- Farm-wide Emergency Stop -->
+        <button x-show="!loading" class="estop-button estop-farm" @click="emergencyStop(null)">
+            STOP ALL PRINTERS
+        </button>
+
+        <!-- Announcements -->
+        <div x-show="announcement" class="announcement" :class="'announcement-' + announcement?.level"
+             x-text="announcement?.text" style="display: none;"></div>
+        <button x-show="!loading" class="announce-toggle" @click="toggleAnnounce()"
+                x-text="announce ? 'Sound on' : 'Sound off'"></button>
+
+        <!-- Idle Screen Clock -->
+        <div x-show="screen?.idle && screen?.mode === 'clock'" class="idle-clock-overlay" :style="idleShiftStyle">
+            <div class="idle-clock-time" x-text="clock"></div>
+            <div class="idle-clock-subtitle">All printers idle</div>
+        </div>
+
         <!-- Return Overlay (hidden by default) -->
         <div x-show="showReturnOverlay" class="return-overlay" style="display: none;">
             <button @click="returnToDashboard()" class="return-button">
@@ -212,18 +901,16 @@ func (h *Handler) handleDashboard(w http.ResponseWriter, r *http.Request) {
         </div>
     </div>
 
-    <script>
-        // Configuration passed from server
-        const PRINTERS = {{.PrintersJSON}};
-    </script>
-    <script src="/static/app.js"></script>
+    <script type="application/json" id="dashboard-config">{{.Config}}</script>
+    <script src="{{asset "app.js"}}"></script>
 </body>
 </html>
 `
 
 	// Prepare printer data for the template
-	printers := make([]map[string]string, len(h.config.Printers))
-	for i, p := range h.config.Printers {
+	configured := h.printersMatching(r, r.URL.Query().Get("tags"))
+	printers := make([]map[string]string, len(configured))
+	for i, p := range configured {
 		printers[i] = map[string]string{
 			"id":            p.ID,
 			"name":          p.Name,
@@ -231,124 +918,292 @@ func (h *Handler) handleDashboard(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	printersJSON, _ := json.Marshal(printers)
+	// Each card shows the macros offered on its printer
+	macros := make([]map[string]interface{}, len(h.macros))
+	for i, m := range h.macros {
+		macros[i] = map[string]interface{}{
+			"id":       m.ID,
+			"name":     m.Name,
+			"confirm":  m.Confirm,
+			"printers": m.Printers,
+		}
+	}
 
-	tmpl, err := template.New("dashboard").Parse(tmplStr)
+	tmpl, err := template.New("dashboard").Funcs(template.FuncMap{"asset": h.assets.URL, "library": h.library}).Parse(tmplStr)
 	if err != nil {
 		http.Error(w, "Template error", http.StatusInternalServerError)
 		return
 	}
 
+	// html/template encodes Config as JSON with <, > and & escaped, so names
+	// can't close the block they're rendered into
 	data := struct {
-		PrintersJSON template.JS
+		Config       map[string]interface{}
+		CardSections []cardSection
 	}{
-		PrintersJSON: template.JS(printersJSON),
+		Config: map[string]interface{}{
+			"printers": printers,
+			"units":    h.settings.Units,
+			"timezone": h.settings.Timezone.String(),
+			"macros":   macros,
+		},
+		CardSections: h.cardSections(),
 	}
 
+	// Revalidated every load so a deploy's new asset names are picked up
 	w.Header().Set("Content-Type", "text/html")
+	w.Header().Set("Cache-Control", "no-cache")
 	tmpl.Execute(w, data)
 }
 
 func (h *Handler) handleStatus(w http.ResponseWriter, r *http.Request) {
-	var wg sync.WaitGroup
-	statusChan := make(chan *models.PrinterStatus, len(h.config.Printers))
+	opts, err := parsePayloadOptions(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tags := r.URL.Query().Get("tags")
+	batch := h.pollStatuses(h.printersMatching(r, tags))
+	defer batch.release()
+
+	response := models.StatusResponse{
+		Status:   "ok",
+		Server:   &h.meta.server,
+		PolledAt: h.clock.Now().UTC().Truncate(time.Second),
+		Sequence: h.meta.next(),
+		Units:    &h.settings.Units,
+		Timezone: h.settings.Timezone.String(),
+		Screen:   h.idle.observe(batch.printers),
+		Banners:  h.activeBanners(),
+	}
+	opts.apply(&response, batch.printers)
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// collectStatuses fetches the status of every given printer concurrently
+func (h *Handler) collectStatuses(configured []config.Printer) []*models.PrinterStatus {
+	return h.pollStatuses(configured).printers
+}
 
-	// Fetch status for all printers concurrently
-	for _, printer := range h.config.Printers {
+// pollStatuses fetches the status of every given printer concurrently into a
+// pooled batch, in configuration order. Callers that finish with the statuses
+// before returning should release the batch.
+func (h *Handler) pollStatuses(configured []config.Printer) *statusBatch {
+	start := time.Now()
+	polledAt := h.clock.Now()
+	batch := acquireBatch(len(configured))
+
+	var wg sync.WaitGroup
+	for i, printer := range configured {
 		wg.Add(1)
-		go func(p config.Printer) {
+		go func(slot *statusSlot, p config.Printer) {
 			defer wg.Done()
-			status := h.fetchPrinterStatus(p)
-			statusChan <- status
-		}(printer)
+			fetchStart := time.Now()
+			h.fillPrinterStatus(p, slot)
+			h.meta.fetched(p.ID, time.Since(fetchStart))
+		}(&batch.slots[i], printer)
 	}
-
 	wg.Wait()
-	close(statusChan)
 
-	// Collect results
-	printers := make([]*models.PrinterStatus, 0, len(h.config.Printers))
-	for status := range statusChan {
-		printers = append(printers, status)
+	printers := batch.printers
+	now := h.clock.Now().UTC()
+	h.meta.annotate(batch.slots, now)
+	h.meta.polled(printers, now)
+	h.meta.timed(time.Since(start))
+	h.addReservations(printers, now)
+	h.addEjects(printers)
+	h.addChecklists(printers)
+	h.addExtensions(printers)
+	h.runRules(printers, now)
+	h.watchHeaters(printers)
+	h.watchProgress(printers, now)
+	h.updateLights(printers)
+	h.observeCameras(printers)
+	h.commands.observe(printers, polledAt)
+	for _, p := range printers {
+		event, err := h.events.Observe(p, now)
+		if err != nil {
+			h.logger.Printf("Failed to record event for %s: %v", p.Name, err)
+		}
+		if event != nil {
+			h.emitTransition(*event)
+		}
 	}
-
-	// Send response
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"status":   "ok",
-		"printers": printers,
-	})
+	return batch
 }
 
 func (h *Handler) fetchPrinterStatus(printer config.Printer) *models.PrinterStatus {
-	status := &models.PrinterStatus{
+	return h.fillPrinterStatus(printer, new(statusSlot))
+}
+
+// fillPrinterStatus fetches a printer's status into slot, whose fields back
+// every section, contacting OctoPrint as little as the poll policy allows
+func (h *Handler) fillPrinterStatus(printer config.Printer, slot *statusSlot) *models.PrinterStatus {
+	now := h.clock.Now()
+	if h.polling.cached(printer.ID, now, slot) {
+		// Power and update badges are local, so keep them current even when OctoPrint is skipped
+		slot.status.Updates = h.updates.Badge(printer.ID)
+		slot.status.Power = nil
+		h.addPowerInfo(&slot.status)
+		return &slot.status
+	}
+
+	reached := h.fetchInto(printer, slot, h.polling.backedOff(printer.ID, now))
+	h.flaps.apply(printer.ID, slot, reached)
+	h.polling.record(printer.ID, slot, now)
+	return &slot.status
+}
+
+// fetchInto contacts OctoPrint for a printer's status, reporting whether it
+// answered. A light fetch of a printer that is still quiet requests only its
+// state and reuses the last spool.
+func (h *Handler) fetchInto(printer config.Printer, slot *statusSlot, light bool) bool {
+	status := &slot.status
+	*status = models.PrinterStatus{
 		ID:           printer.ID,
 		Name:         printer.Name,
 		OctoPrintURL: printer.OctoPrintURL,
+		Tags:         h.settings.Printers[printer.ID].Tags,
 		Status:       "offline",
+		Updates:      h.updates.Badge(printer.ID),
 	}
 
-	client, ok := h.octoprintClients[printer.ID]
-	if !ok {
+	client := h.printerClient(printer.ID)
+	if client == nil {
 		status.Error = "No client configured"
-		return status
+		return false
 	}
 
 	// Get printer state
 	printerResp, err := client.GetPrinterState()
 	if err != nil {
-		log.Printf("Error fetching printer state for %s: %v", printer.Name, err)
+		h.logger.Printf("Error fetching printer state for %s: %v", printer.Name, err)
 		status.Error = err.Error()
-		return status
+		h.meta.failed(printer.ID, status.Error, h.clock.Now().UTC())
+		return false
 	}
 
-	// Determine status
-	if printerResp.State.Flags.Printing {
-		status.Status = "printing"
-	} else if printerResp.State.Flags.Ready {
-		status.Status = "idle"
-	} else if printerResp.State.Flags.Error {
-		status.Status = "error"
-	}
+	h.meta.seen(printer.ID, h.clock.Now().UTC())
 
+	status.Status = dashboardStatus(printerResp)
 	status.State = printerResp.State.Text
+	if status.Status == "error" {
+		status.Diagnosis = errcodes.Decode(status.State)
+	}
 
 	// Set temperature info
-	status.Temperatures = &models.TemperatureInfo{
+	slot.temps = models.TemperatureInfo{
 		BedActual:    printerResp.Temperature.Bed.Actual,
 		BedTarget:    printerResp.Temperature.Bed.Target,
 		HotendActual: printerResp.Temperature.Tool0.Actual,
 		HotendTarget: printerResp.Temperature.Tool0.Target,
 	}
+	status.Temperatures = &slot.temps
+
+	h.trackCompletion(printer, client, status)
+	h.trackRecovery(printer, client, status)
+
+	if light && quiet(status.Status) {
+		status.CurrentSpool = h.polling.lastSpool(printer.ID)
+		h.addPowerInfo(status)
+		return true
+	}
 
 	// Get job info if printing
-	if status.Status == "printing" {
+	if onJob(status.Status) {
 		jobResp, err := client.GetJob()
 		if err == nil && jobResp != nil {
-			status.Progress = &models.ProgressInfo{
+			slot.progress = models.ProgressInfo{
 				Completion:     jobResp.Progress.Completion,
 				PrintTime:      jobResp.Progress.PrintTime,
 				PrintTimeLeft:  jobResp.Progress.PrintTimeLeft,
 				EstimatedTotal: int(jobResp.Job.EstimatedPrintTime),
 				FileName:       jobResp.Job.File.Display,
 				FilamentLength: jobResp.Job.Filament.Tool0.Length,
+				Filepos:        jobResp.Progress.Filepos,
 			}
-
-			// Get thumbnail URL
-			if jobResp.Job.File.Path != "" {
-				status.ThumbnailURL = client.GetThumbnail(jobResp.Job.File.Path)
+			status.Progress = &slot.progress
+			if jobResp.Progress.PrintTimeLeft > 0 {
+				slot.eta = h.clock.Now().UTC().Add(time.Duration(jobResp.Progress.PrintTimeLeft) * time.Second).Truncate(time.Second)
+				status.Progress.ETA = &slot.eta
 			}
+
+			status.ThumbnailURL = octoapi.ThumbnailURL(printer.OctoPrintURL, jobResp.Job.File.Path)
+			h.checkpoint(printer, jobResp)
 		}
 	}
 
+	h.addPowerInfo(status)
+
 	// Fetch current spool
 	spoolID, err := client.GetCurrentSpool(0)
 	if err == nil && spoolID != "" {
-		spool, err := h.spoolmanClient.GetSpool(spoolID)
+		src := h.spoolSourceFor(printer.ID)
+		spool, err := src.client.GetSpool(spoolID)
 		if err == nil && spool != nil {
-			status.CurrentSpool = spoolman.FormatSpoolInfo(spool)
+			status.CurrentSpool = h.spoolInfo(src, spool)
+			h.addDryingInfo(status.CurrentSpool, src, spool)
 		}
 	}
+	return true
+}
+
+// dashboardStatus maps OctoPrint's state flags onto the dashboard's status values
+func dashboardStatus(resp *octoprint.PrinterResponse) string {
+	if resp.State.Flags.Printing {
+		return "printing"
+	} else if resp.State.Flags.Paused {
+		return "paused"
+	} else if resp.State.Flags.Ready {
+		return "idle"
+	} else if resp.State.Flags.Error {
+		return "error"
+	}
+	return "offline"
+}
+
+// onJob reports whether a printer status means a job is under way, paused
+// ones included
+func onJob(status string) bool {
+	return status == "printing" || status == "paused"
+}
+
+// jobKey identifies the running job so energy can be attributed to it, or is
+// empty when the job couldn't be fetched
+func jobKey(status *models.PrinterStatus) string {
+	if status.Progress == nil {
+		return ""
+	}
+	return status.Progress.FileName
+}
+
+// addPowerInfo attaches the printer's plug reading and the energy its job has
+// used so far. The job's baseline is kept through pauses and missed job
+// fetches, and only cleared once the printer leaves the job.
+func (h *Handler) addPowerInfo(status *models.PrinterStatus) {
+	sample, ok := h.energyMonitor.Latest(status.ID)
+	if !ok {
+		return
+	}
+
+	power := &models.PowerInfo{
+		Watts:    sample.Watts,
+		TotalKWh: sample.TotalKWh,
+	}
+	if onJob(status.Status) {
+		power.JobEnergyKWh = h.energyMonitor.JobEnergy(status.ID, jobKey(status))
+	} else {
+		h.energyMonitor.EndJob(status.ID)
+	}
+	if sample.Error != nil {
+		power.Error = sample.Error.Error()
+	}
+	if h.settings.Energy.PricePerKWh > 0 {
+		power.JobCost = power.JobEnergyKWh * h.settings.Energy.PricePerKWh
+		power.Currency = h.settings.Energy.Currency
+	}
 
-	return status
+	status.Power = power
 }
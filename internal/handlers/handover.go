@@ -0,0 +1,176 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/handover"
+	"github.com/wmarchesi123/octodash/internal/models"
+)
+
+// handoverFormats maps each ?format= to the content type it is served as
+var handoverFormats = map[string]string{
+	"html":     "text/html; charset=utf-8",
+	"markdown": "text/markdown; charset=utf-8",
+	"pdf":      "application/pdf",
+	"json":     "application/json",
+}
+
+// handleHandoverReport sums up a shift for the operator taking over, as HTML
+// unless ?format= asks for markdown, pdf or json. ?since= takes a time or a
+// duration back from now and defaults to HANDOVER_SHIFT.
+func (h *Handler) handleHandoverReport(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	format := q.Get("format")
+	if format == "" {
+		format = "html"
+	}
+	contentType, ok := handoverFormats[format]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "format must be html, markdown, pdf or json")
+		return
+	}
+
+	now := h.clock.Now()
+	since := now.Add(-h.settings.Handover.Shift)
+	if v := q.Get("since"); v != "" {
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			since = t
+		} else if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			since = now.Add(-d)
+		} else {
+			writeError(w, http.StatusBadRequest, "since must be an RFC 3339 time or a duration like 8h")
+			return
+		}
+	}
+
+	report, err := h.handoverReport(r, since, now)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if format == "json" {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"status": "ok",
+			"report": report,
+		})
+		return
+	}
+
+	loc := h.settings.Timezone
+	var buf bytes.Buffer
+	switch format {
+	case "html":
+		err = handover.HTML(&buf, report, loc)
+	case "markdown":
+		err = handover.Markdown(&buf, report, loc)
+	case "pdf":
+		err = handover.PDF(&buf, report, loc)
+		name := fmt.Sprintf("handover-%s.pdf", now.In(loc).Format("2006-01-02-1504"))
+		w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", name))
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write(buf.Bytes())
+}
+
+// handoverReport gathers a report over the printers the caller may see.
+// Spools come from the Spoolman instances those printers use; one that does not
+// answer is logged rather than failing the report.
+func (h *Handler) handoverReport(r *http.Request, since, now time.Time) (models.HandoverReport, error) {
+	report := models.HandoverReport{
+		GeneratedAt: now.UTC().Truncate(time.Second),
+		Since:       since.UTC().Truncate(time.Second),
+		Printers:    make(map[string]string),
+		Running:     []models.HandoverJob{},
+		Completed:   []models.JobRecord{},
+		Failed:      []models.JobRecord{},
+		LowSpools:   []models.SpoolForecast{},
+		Units:       h.settings.Units,
+	}
+
+	printers := h.printersMatching(r, "")
+	for _, p := range printers {
+		report.Printers[p.ID] = p.Name
+	}
+
+	for _, p := range h.collectStatuses(printers) {
+		if (p.Status != "printing" && p.Status != "paused") || p.Progress == nil {
+			continue
+		}
+		report.Running = append(report.Running, models.HandoverJob{
+			PrinterID:  p.ID,
+			Status:     p.Status,
+			File:       p.Progress.FileName,
+			Completion: p.Progress.Completion,
+			ETA:        p.Progress.ETA,
+		})
+	}
+
+	jobs, err := h.jobHistory.List("", since, 0)
+	if err != nil {
+		return report, err
+	}
+	for _, j := range jobs {
+		if _, ok := report.Printers[j.PrinterID]; !ok {
+			continue
+		}
+		switch j.Result {
+		case models.JobCompleted:
+			report.Completed = append(report.Completed, j)
+		case models.JobFailed:
+			report.Failed = append(report.Failed, j)
+		}
+	}
+
+	f, errs := h.buildForecast(r)
+	for _, e := range errs {
+		h.logger.Printf("Handover report is missing spools from %s", e)
+	}
+	for _, s := range f.Spools {
+		if s.RemainingWeight < h.settings.Handover.LowSpool {
+			report.LowSpools = append(report.LowSpools, s)
+		}
+	}
+	sort.SliceStable(report.LowSpools, func(i, j int) bool {
+		return report.LowSpools[i].RemainingWeight < report.LowSpools[j].RemainingWeight
+	})
+
+	alerts, err := h.alertsSince(since)
+	if err != nil {
+		return report, err
+	}
+	report.Alerts = alerts[:0]
+	for _, a := range alerts {
+		if _, ok := report.Printers[a.PrinterID]; ok {
+			report.Alerts = append(report.Alerts, a)
+		}
+	}
+	return report, nil
+}
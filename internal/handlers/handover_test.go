@@ -0,0 +1,122 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestHandoverReport(t *testing.T) {
+	t.Setenv("UNITS_WEIGHT", "oz")
+	now := time.Date(2025, 3, 1, 14, 0, 0, 0, time.UTC)
+	clock := &stepClock{now: now}
+	sm := testutil.NewSpoolman(t,
+		testutil.Spool(1, "Prusament", "PLA", "ff0000", 80),
+		testutil.Spool(2, "Polymaker", "PETG", "00ff00", 900),
+	)
+	busy := testutil.NewOctoPrint(t)
+	busy.SetPrinting("gear.gcode", 40, 1800)
+	idle := testutil.NewOctoPrint(t)
+	h := newHandlerWithConfig(t, testutil.Config(sm,
+		testutil.Printer{Name: "MK4", Server: busy},
+		testutil.Printer{Name: "Mini", Server: idle},
+	), WithClock(clock))
+
+	for i, j := range []models.JobRecord{
+		{PrinterID: "printer-2", File: "clip.gcode", Result: models.JobCompleted, FinishedAt: now.Add(-2 * time.Hour), PrintTime: 3900},
+		{PrinterID: "printer-2", File: "hook.gcode", Result: models.JobFailed, FinishedAt: now.Add(-time.Hour), PrintTime: 600},
+		{PrinterID: "printer-2", File: "old.gcode", Result: models.JobCompleted, FinishedAt: now.Add(-10 * time.Hour)},
+	} {
+		if _, err := h.jobHistory.Record(j, string(rune('a'+i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	clock.set(now.Add(-30 * time.Minute))
+	h.emit(models.EventAlertRaised, "printer-2", map[string]interface{}{"kind": "rule", "name": "Bed too hot", "severity": models.SeverityHigh})
+	clock.set(now)
+
+	get := func(query string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", "/api/reports/handover"+query, nil))
+		return rec
+	}
+
+	var resp struct {
+		Report models.HandoverReport `json:"report"`
+	}
+	rec := get("?format=json")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("json report = %d: %s", rec.Code, rec.Body)
+	}
+	json.NewDecoder(rec.Body).Decode(&resp)
+	report := resp.Report
+	if len(report.Running) != 1 || report.Running[0].File != "gear.gcode" || report.Running[0].ETA == nil {
+		t.Errorf("running = %+v, want gear.gcode with an ETA", report.Running)
+	}
+	if len(report.Completed) != 1 || report.Completed[0].File != "clip.gcode" {
+		t.Errorf("completed = %+v, want only clip.gcode within the shift", report.Completed)
+	}
+	if len(report.Failed) != 1 || report.Failed[0].File != "hook.gcode" {
+		t.Errorf("failed = %+v, want hook.gcode", report.Failed)
+	}
+	if len(report.LowSpools) != 1 || report.LowSpools[0].ID != 1 {
+		t.Errorf("low spools = %+v, want spool 1", report.LowSpools)
+	}
+	if len(report.Alerts) != 1 || report.Alerts[0].Message != "Mini: Bed too hot" {
+		t.Errorf("alerts = %+v", report.Alerts)
+	}
+
+	// A longer window reaches back to the older job
+	json.NewDecoder(get("?format=json&since=12h").Body).Decode(&resp)
+	if len(resp.Report.Completed) != 2 {
+		t.Errorf("completed over 12h = %d jobs, want 2", len(resp.Report.Completed))
+	}
+
+	rec = get("?format=markdown")
+	for _, want := range []string{"## Running jobs", "| MK4 | printing | gear.gcode | 40% |", "| Mini | clip.gcode | Sat 12:00 | 1h 05m |", "| PLA | 2.8oz |"} {
+		if !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("markdown report is missing %q:\n%s", want, rec.Body)
+		}
+	}
+
+	rec = get("")
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") || !strings.Contains(rec.Body.String(), "<td>hook.gcode</td>") {
+		t.Errorf("html report = %s:\n%s", ct, rec.Body)
+	}
+
+	rec = get("?format=pdf")
+	if rec.Header().Get("Content-Type") != "application/pdf" || !bytes.HasPrefix(rec.Body.Bytes(), []byte("%PDF-")) {
+		t.Errorf("pdf report = %s, starts %q", rec.Header().Get("Content-Type"), rec.Body.Bytes()[:min(8, rec.Body.Len())])
+	}
+
+	for _, query := range []string{"?format=docx", "?since=yesterday"} {
+		if rec := get(query); rec.Code != http.StatusBadRequest {
+			t.Errorf("report%s = %d, want %d", query, rec.Code, http.StatusBadRequest)
+		}
+	}
+}
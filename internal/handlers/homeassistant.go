@@ -0,0 +1,307 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/wmarchesi123/go-3dprint-client/config"
+	"github.com/wmarchesi123/octodash/internal/auth"
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/version"
+)
+
+const homeAssistantBucket = "homeassistant"
+
+// haSensor is a per-printer Home Assistant entity and how to read its state;
+// a nil state shows as unknown
+type haSensor struct {
+	key, name, platform, deviceClass, unit, stateClass, icon string
+
+	state func(p *models.PrinterStatus) interface{}
+}
+
+// Temperatures stay in Celsius; Home Assistant converts for display itself
+var haSensors = []haSensor{
+	{key: "status", name: "Status", platform: "sensor", deviceClass: "enum", icon: "mdi:printer-3d",
+		state: func(p *models.PrinterStatus) interface{} { return p.Status }},
+	{key: "printing", name: "Printing", platform: "binary_sensor", deviceClass: "running",
+		state: func(p *models.PrinterStatus) interface{} { return p.Status == "printing" }},
+	{key: "online", name: "Online", platform: "binary_sensor", deviceClass: "connectivity",
+		state: func(p *models.PrinterStatus) interface{} { return p.Status != "offline" }},
+	{key: "progress", name: "Progress", platform: "sensor", unit: "%", stateClass: "measurement", icon: "mdi:progress-clock",
+		state: func(p *models.PrinterStatus) interface{} {
+			if p.Progress == nil {
+				return nil
+			}
+			return math.Round(p.Progress.Completion*10) / 10
+		}},
+	{key: "time_remaining", name: "Time remaining", platform: "sensor", deviceClass: "duration", unit: "s",
+		state: func(p *models.PrinterStatus) interface{} {
+			if p.Progress == nil {
+				return nil
+			}
+			return p.Progress.PrintTimeLeft
+		}},
+	{key: "file", name: "File", platform: "sensor", icon: "mdi:file",
+		state: func(p *models.PrinterStatus) interface{} {
+			if p.Progress == nil {
+				return nil
+			}
+			return p.Progress.FileName
+		}},
+	{key: "bed_temperature", name: "Bed temperature", platform: "sensor", deviceClass: "temperature", unit: "°C", stateClass: "measurement",
+		state: func(p *models.PrinterStatus) interface{} {
+			if p.Temperatures == nil {
+				return nil
+			}
+			return math.Round(p.Temperatures.BedActual*10) / 10
+		}},
+	{key: "hotend_temperature", name: "Hotend temperature", platform: "sensor", deviceClass: "temperature", unit: "°C", stateClass: "measurement",
+		state: func(p *models.PrinterStatus) interface{} {
+			if p.Temperatures == nil {
+				return nil
+			}
+			return math.Round(p.Temperatures.HotendActual*10) / 10
+		}},
+	{key: "power", name: "Power", platform: "sensor", deviceClass: "power", unit: "W", stateClass: "measurement",
+		state: func(p *models.PrinterStatus) interface{} {
+			if p.Power == nil || p.Power.Error != "" {
+				return nil
+			}
+			return p.Power.Watts
+		}},
+}
+
+// haSlug makes a name safe for a Home Assistant object ID
+func haSlug(s string) string {
+	return strings.Trim(strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		if r >= 'A' && r <= 'Z' {
+			return r + ('a' - 'A')
+		}
+		return '_'
+	}, s), "_")
+}
+
+// requireHomeAssistant admits the long-lived HOMEASSISTANT_TOKEN as well as
+// any viewer
+func (h *Handler) requireHomeAssistant(next http.HandlerFunc) http.HandlerFunc {
+	viewer := h.auth.Require(auth.RoleViewer, next)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if h.homeAssistantToken(r) {
+			next(w, r)
+			return
+		}
+		viewer(w, r)
+	}
+}
+
+// homeAssistantToken reports whether the request carries HOMEASSISTANT_TOKEN
+func (h *Handler) homeAssistantToken(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	want := h.settings.HomeAssistant.Token
+	return ok && want != "" && subtle.ConstantTimeCompare([]byte(token), []byte(want)) == 1
+}
+
+// homeAssistantPrinters is every printer for Home Assistant's own token, or
+// those the caller may see otherwise
+func (h *Handler) homeAssistantPrinters(r *http.Request) []config.Printer {
+	if h.homeAssistantToken(r) {
+		return h.config.Printers
+	}
+	return h.printersMatching(r, "")
+}
+
+// homeAssistantID is a random ID for this installation, kept so entity unique
+// IDs stay the same across restarts and renames
+func (h *Handler) homeAssistantID() (string, error) {
+	h.instanceMu.Lock()
+	defer h.instanceMu.Unlock()
+
+	var id string
+	ok, err := h.store.Get(homeAssistantBucket, "instance", &id)
+	if err != nil || ok {
+		return id, err
+	}
+
+	buf := make([]byte, 8)
+	rand.Read(buf)
+	id = hex.EncodeToString(buf)
+	return id, h.store.Put(homeAssistantBucket, "instance", id)
+}
+
+// homeAssistantEntities lists the farm-wide entities and every printer's
+// entities, with the function reading each one's state
+func (h *Handler) homeAssistantEntities(instance string, printers []config.Printer) ([]models.HomeAssistantEntity, map[string]func(map[string]*models.PrinterStatus) interface{}) {
+	name := h.settings.HomeAssistant.Name
+	prefix := haSlug(name)
+
+	entities := []models.HomeAssistantEntity{
+		{UniqueID: instance + "_printing", ObjectID: prefix + "_printing", Name: name + " printing", Platform: "sensor", StateClass: "measurement", Icon: "mdi:printer-3d-nozzle"},
+		{UniqueID: instance + "_printers", ObjectID: prefix + "_printers", Name: name + " printers", Platform: "sensor", Icon: "mdi:printer-3d"},
+	}
+	states := map[string]func(map[string]*models.PrinterStatus) interface{}{
+		prefix + "_printing": func(all map[string]*models.PrinterStatus) interface{} {
+			n := 0
+			for _, p := range all {
+				if p.Status == "printing" {
+					n++
+				}
+			}
+			return n
+		},
+		prefix + "_printers": func(all map[string]*models.PrinterStatus) interface{} { return len(all) },
+	}
+
+	for _, printer := range printers {
+		for _, s := range haSensors {
+			e := models.HomeAssistantEntity{
+				UniqueID:    fmt.Sprintf("%s_%s_%s", instance, printer.ID, s.key),
+				ObjectID:    fmt.Sprintf("%s_%s_%s", prefix, haSlug(printer.ID), s.key),
+				Name:        printer.Name + " " + s.name,
+				Platform:    s.platform,
+				Device:      printer.ID,
+				DeviceClass: s.deviceClass,
+				Unit:        s.unit,
+				StateClass:  s.stateClass,
+				Icon:        s.icon,
+			}
+			entities = append(entities, e)
+
+			id, state := printer.ID, s.state
+			states[e.ObjectID] = func(all map[string]*models.PrinterStatus) interface{} {
+				if p, ok := all[id]; ok {
+					return state(p)
+				}
+				return nil
+			}
+		}
+	}
+	return entities, states
+}
+
+// handleHomeAssistant describes the installation, its devices and entities,
+// for an integration's config flow to validate a URL and token against
+func (h *Handler) handleHomeAssistant(w http.ResponseWriter, r *http.Request) {
+	instance, err := h.homeAssistantID()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	printers := h.homeAssistantPrinters(r)
+	devices := make([]models.HomeAssistantDevice, 0, len(printers))
+	for _, p := range printers {
+		devices = append(devices, models.HomeAssistantDevice{
+			ID:               p.ID,
+			Identifier:       instance + "_" + p.ID,
+			Name:             p.Name,
+			Manufacturer:     "OctoDash",
+			Model:            "OctoPrint printer",
+			ConfigurationURL: p.OctoPrintURL,
+		})
+	}
+	entities, _ := h.homeAssistantEntities(instance, printers)
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":    "ok",
+		"unique_id": instance,
+		"name":      h.settings.HomeAssistant.Name,
+		"version":   version.String(),
+		"devices":   devices,
+		"entities":  entities,
+	})
+}
+
+// handleHomeAssistantStates returns every entity's state in one call, keyed by
+// object ID, for RESTful sensors sharing a single resource
+func (h *Handler) handleHomeAssistantStates(w http.ResponseWriter, r *http.Request) {
+	instance, err := h.homeAssistantID()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	printers := h.homeAssistantPrinters(r)
+	byID := make(map[string]*models.PrinterStatus)
+	for _, p := range h.collectStatuses(printers) {
+		byID[p.ID] = p
+	}
+
+	_, readers := h.homeAssistantEntities(instance, printers)
+	states := make(map[string]interface{}, len(readers))
+	for objectID, read := range readers {
+		states[objectID] = read(byID)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "ok",
+		"updated": h.clock.Now().UTC().Truncate(time.Second),
+		"states":  states,
+	})
+}
+
+// handleHomeAssistantYAML writes a ready-made RESTful sensor configuration
+// reading every entity from /api/homeassistant/states
+func (h *Handler) handleHomeAssistantYAML(w http.ResponseWriter, r *http.Request) {
+	instance, err := h.homeAssistantID()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	entities, _ := h.homeAssistantEntities(instance, h.homeAssistantPrinters(r))
+
+	var b strings.Builder
+	b.WriteString("# OctoDash RESTful sensors for Home Assistant's configuration.yaml.\n")
+	b.WriteString("# Add octodash_token to secrets.yaml as \"Bearer <HOMEASSISTANT_TOKEN>\".\n")
+	b.WriteString("rest:\n")
+	fmt.Fprintf(&b, "  - resource: %s/api/homeassistant/states\n", h.publicBase(r))
+	b.WriteString("    headers:\n      Authorization: !secret octodash_token\n")
+	b.WriteString("    scan_interval: 30\n")
+
+	for _, platform := range []string{"sensor", "binary_sensor"} {
+		fmt.Fprintf(&b, "    %s:\n", platform)
+		for _, e := range entities {
+			if e.Platform != platform {
+				continue
+			}
+			fmt.Fprintf(&b, "      - name: %q\n", e.Name)
+			fmt.Fprintf(&b, "        unique_id: %s\n", e.UniqueID)
+			fmt.Fprintf(&b, "        value_template: \"{{ value_json.states.%s }}\"\n", e.ObjectID)
+			for _, field := range [][2]string{{"device_class", e.DeviceClass}, {"unit_of_measurement", e.Unit}, {"state_class", e.StateClass}, {"icon", e.Icon}} {
+				if field[1] != "" {
+					fmt.Fprintf(&b, "        %s: %q\n", field[0], field[1])
+				}
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/yaml; charset=utf-8")
+	w.Write([]byte(b.String()))
+}
@@ -0,0 +1,107 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestHomeAssistant(t *testing.T) {
+	t.Setenv("AUTH_USERS", "ada:admin:ada-token")
+	t.Setenv("HOMEASSISTANT_TOKEN", "ha-token")
+	mini, voron := testutil.NewOctoPrint(t), testutil.NewOctoPrint(t)
+	mini.SetPrinting("gear.gcode", 42.25, 600)
+	mini.SetTemperatures(215, 215, 60.04, 60)
+	h := newTestHandler(t, testutil.NewSpoolman(t),
+		testutil.Printer{Name: "Mini", Server: mini},
+		testutil.Printer{Name: "Voron", Server: voron})
+
+	get := func(token, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := get("", "/api/homeassistant/states"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("no token = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+	if rec := get("ha-token", "/api/admin/audit"); rec.Code != http.StatusUnauthorized {
+		t.Error("the Home Assistant token opened an unrelated endpoint")
+	}
+
+	var info struct {
+		UniqueID string                       `json:"unique_id"`
+		Devices  []models.HomeAssistantDevice `json:"devices"`
+		Entities []models.HomeAssistantEntity `json:"entities"`
+	}
+	json.NewDecoder(get("ha-token", "/api/homeassistant").Body).Decode(&info)
+	if info.UniqueID == "" || len(info.Devices) != 2 || len(info.Entities) != 2+2*len(haSensors) {
+		t.Fatalf("info = %+v", info)
+	}
+
+	var again struct {
+		UniqueID string `json:"unique_id"`
+	}
+	json.NewDecoder(get("ada-token", "/api/homeassistant").Body).Decode(&again)
+	if again.UniqueID != info.UniqueID {
+		t.Errorf("instance ID changed from %s to %s", info.UniqueID, again.UniqueID)
+	}
+
+	var resp struct {
+		States map[string]interface{} `json:"states"`
+	}
+	json.NewDecoder(get("ha-token", "/api/homeassistant/states").Body).Decode(&resp)
+	for id, want := range map[string]interface{}{
+		"octodash_printing":                  1.0,
+		"octodash_printers":                  2.0,
+		"octodash_printer_1_status":          "printing",
+		"octodash_printer_1_printing":        true,
+		"octodash_printer_1_progress":        42.3,
+		"octodash_printer_1_bed_temperature": 60.0,
+		"octodash_printer_1_file":            "gear.gcode",
+		"octodash_printer_2_printing":        false,
+		"octodash_printer_2_time_remaining":  nil,
+	} {
+		if got, ok := resp.States[id]; !ok || got != want {
+			t.Errorf("%s = %v, want %v", id, got, want)
+		}
+	}
+
+	yaml := get("ha-token", "/api/homeassistant/rest.yaml").Body.String()
+	for _, want := range []string{
+		"resource: http://example.com/api/homeassistant/states",
+		`value_template: "{{ value_json.states.octodash_printer_1_progress }}"`,
+		`device_class: "running"`,
+	} {
+		if !strings.Contains(yaml, want) {
+			t.Errorf("rest.yaml is missing %s:\n%s", want, yaml)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/auth"
+)
+
+// idempotencyTTL is how long a response is kept for replay to a retry
+const idempotencyTTL = 24 * time.Hour
+
+// maxIdempotencyKey bounds the length of an Idempotency-Key header
+const maxIdempotencyKey = 255
+
+// maxIdempotencyEntries bounds how many keyed responses are kept; past it the
+// one closest to expiring makes room
+const maxIdempotencyEntries = 10000
+
+// maxIdempotencyBody bounds how much of a request body is read to fingerprint
+// it; keys on larger requests, such as model uploads, are ignored
+const maxIdempotencyBody = 1 << 20
+
+// idempotencyPrune is how often expired responses are dropped
+const idempotencyPrune = 10 * time.Minute
+
+// idempotentPrefixes are the paths whose POSTs honor an Idempotency-Key
+var idempotentPrefixes = []string{"/api/printers/", "/api/bulk/", "/api/emergency-stop", "/api/queue"}
+
+// idempotencyKeys remembers the responses to keyed requests so a retry gets
+// the first answer instead of running the action again
+type idempotencyKeys struct {
+	clock   Clock
+	mu      sync.Mutex
+	entries map[string]*keyedResponse
+}
+
+// keyedResponse is a request claimed by a key, and its response once done
+type keyedResponse struct {
+	request string
+	done    bool
+	code    int
+	header  http.Header
+	body    []byte
+	expires time.Time
+}
+
+func newIdempotencyKeys(clock Clock) *idempotencyKeys {
+	return &idempotencyKeys{clock: clock, entries: make(map[string]*keyedResponse)}
+}
+
+// claim reserves key for request. It returns nil when the caller should go
+// ahead, or the entry already holding the key; ok is false when every slot is
+// held by a request still in progress.
+func (k *idempotencyKeys) claim(key, request string) (prior *keyedResponse, ok bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if entry, found := k.entries[key]; found {
+		copied := *entry
+		return &copied, true
+	}
+	if len(k.entries) >= maxIdempotencyEntries && !k.evict() {
+		return nil, false
+	}
+	k.entries[key] = &keyedResponse{request: request}
+	return nil, true
+}
+
+// evict drops the finished response closest to expiring, reporting whether
+// there was one. The caller holds k.mu.
+func (k *idempotencyKeys) evict() bool {
+	oldest := ""
+	for key, entry := range k.entries {
+		if entry.done && (oldest == "" || entry.expires.Before(k.entries[oldest].expires)) {
+			oldest = key
+		}
+	}
+	if oldest == "" {
+		return false
+	}
+	delete(k.entries, oldest)
+	return true
+}
+
+// prune drops expired responses
+func (k *idempotencyKeys) prune() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	now := k.clock.Now()
+	for key, entry := range k.entries {
+		if entry.done && now.After(entry.expires) {
+			delete(k.entries, key)
+		}
+	}
+}
+
+// run prunes expired responses until ctx is cancelled
+func (k *idempotencyKeys) run(ctx context.Context) {
+	ticker := time.NewTicker(idempotencyPrune)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			k.prune()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// finish stores the response to a claimed key. Server errors, panics and
+// refusals on authorization release the key instead, so a retry runs the
+// action again.
+func (k *idempotencyKeys) finish(key string, rec *recordingWriter) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if rec == nil || rec.code >= http.StatusInternalServerError || rec.code == http.StatusUnauthorized || rec.code == http.StatusForbidden {
+		delete(k.entries, key)
+		return
+	}
+	entry := k.entries[key]
+	entry.done = true
+	entry.code = rec.code
+	entry.header = rec.header
+	entry.body = rec.body.Bytes()
+	entry.expires = k.clock.Now().Add(idempotencyTTL)
+}
+
+// idempotent replays the stored response when a control or queue POST is
+// retried with the same Idempotency-Key, so a double tap or a retry over a
+// flaky connection doesn't run the action twice. Keys are scoped to the
+// user, and one key can't be reused for a different request, body included.
+// Callers without a valid token get no replay; Require turns them away.
+func (h *Handler) idempotent(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Idempotency-Key")
+		user, identified := auth.IdentifiedUser(r.Context())
+		if header == "" || !identified || r.Method != http.MethodPost || !idempotentPath(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(header) > maxIdempotencyKey {
+			writeError(w, http.StatusBadRequest, "Idempotency-Key is too long")
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxIdempotencyBody+1))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if len(body) > maxIdempotencyBody {
+			r.Body = readCloser{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+			next.ServeHTTP(w, r)
+			return
+		}
+		r.Body = readCloser{bytes.NewReader(body), r.Body}
+
+		key := user.Name + "\x00" + header
+		sum := sha256.Sum256(body)
+		request := r.Method + " " + r.URL.RequestURI() + " " + hex.EncodeToString(sum[:])
+
+		prior, ok := h.idempotency.claim(key, request)
+		switch {
+		case !ok:
+			writeError(w, http.StatusServiceUnavailable, "Too many keyed requests in progress")
+			return
+		case prior == nil:
+		case prior.request != request:
+			writeError(w, http.StatusUnprocessableEntity, "Idempotency-Key was already used for a different request")
+			return
+		case !prior.done:
+			writeError(w, http.StatusConflict, "A request with this Idempotency-Key is still in progress")
+			return
+		default:
+			for name, values := range prior.header {
+				w.Header()[name] = values
+			}
+			w.Header().Set("Idempotent-Replayed", "true")
+			w.WriteHeader(prior.code)
+			w.Write(prior.body)
+			return
+		}
+
+		var finished *recordingWriter
+		defer func() { h.idempotency.finish(key, finished) }()
+
+		rec := &recordingWriter{ResponseWriter: w, code: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		finished = rec
+	})
+}
+
+func idempotentPath(path string) bool {
+	for _, prefix := range idempotentPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// readCloser puts a buffered body back in front of the original's Close
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// recordingWriter passes a response through while keeping a copy of it
+type recordingWriter struct {
+	http.ResponseWriter
+	code        int
+	header      http.Header
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+func (rw *recordingWriter) WriteHeader(code int) {
+	if rw.wroteHeader {
+		return
+	}
+	rw.wroteHeader = true
+	rw.code = code
+	rw.header = rw.Header().Clone()
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *recordingWriter) Write(b []byte) (int, error) {
+	if !rw.wroteHeader {
+		rw.WriteHeader(http.StatusOK)
+	}
+	rw.body.Write(b)
+	return rw.ResponseWriter.Write(b)
+}
+
+func (rw *recordingWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
@@ -0,0 +1,155 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	op := testutil.NewOctoPrint(t)
+	h := newTestHandler(t, testutil.NewSpoolman(t), testutil.Printer{Name: "Mini", Server: op})
+	op.SetPrinting("gear.gcode", 40, 1200)
+
+	post := func(path, body, key string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", path, strings.NewReader(body))
+		req.Header.Set("Idempotency-Key", key)
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := post("/api/printers/printer-1/cancel", "", "tap-1")
+	if first.Code != http.StatusOK {
+		t.Fatalf("cancel = %d: %s", first.Code, first.Body)
+	}
+	second := post("/api/printers/printer-1/cancel", "", "tap-1")
+	if second.Code != first.Code || second.Body.String() != first.Body.String() || second.Header().Get("Idempotent-Replayed") != "true" {
+		t.Errorf("retry = %d %q %v, want the first response replayed", second.Code, second.Body, second.Header())
+	}
+	if n := op.Requests("POST /api/job"); n != 1 {
+		t.Errorf("cancel sent %d times, want once", n)
+	}
+	if rec := post("/api/printers/printer-1/pause", "", "tap-1"); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("reused key = %d, want %d", rec.Code, http.StatusUnprocessableEntity)
+	}
+
+	for i := 0; i < 2; i++ {
+		if rec := post("/api/queue", `{"file": "clip.gcode"}`, "enqueue-1"); rec.Code != http.StatusCreated {
+			t.Fatalf("enqueue = %d: %s", rec.Code, rec.Body)
+		}
+	}
+	if rec := post("/api/queue", `{"file": "clip.gcode"}`, "enqueue-2"); rec.Code != http.StatusCreated {
+		t.Fatalf("enqueue with a new key = %d: %s", rec.Code, rec.Body)
+	}
+	if entries, _ := h.queue.List(); len(entries) != 2 {
+		t.Errorf("queue has %d entries, want 2", len(entries))
+	}
+
+	// The body is part of the request, so a key can't replay across bodies
+	if rec := post("/api/queue", `{"file": "gear.gcode"}`, "enqueue-1"); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("key reused with another body = %d, want %d", rec.Code, http.StatusUnprocessableEntity)
+	}
+	stop := post("/api/emergency-stop", "", "stop-1")
+	var pending struct {
+		Token string `json:"confirm_token"`
+	}
+	json.NewDecoder(stop.Body).Decode(&pending)
+	if rec := post("/api/emergency-stop", `{"confirm_token": "`+pending.Token+`"}`, "stop-1"); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("confirmation under the first step's key = %d, want %d", rec.Code, http.StatusUnprocessableEntity)
+	}
+	if rec := post("/api/emergency-stop", `{"confirm_token": "`+pending.Token+`"}`, "stop-2"); rec.Code != http.StatusOK {
+		t.Errorf("confirmation under its own key = %d: %s", rec.Code, rec.Body)
+	}
+
+	// Requests without a key run every time
+	postJSON(h, "/api/printers/printer-1/pause", "")
+	postJSON(h, "/api/printers/printer-1/pause", "")
+	if n := op.Requests("POST /api/job"); n != 3 {
+		t.Errorf("job commands = %d, want 3", n)
+	}
+}
+
+func TestIdempotencyKeyNeedsIdentifiedCaller(t *testing.T) {
+	t.Setenv("AUTH_USERS", "vic:viewer:vic-token,olga:operator:olga-token")
+	op := testutil.NewOctoPrint(t)
+	h := newTestHandler(t, testutil.NewSpoolman(t), testutil.Printer{Name: "Mini", Server: op})
+	op.SetPrinting("gear.gcode", 40, 1200)
+
+	post := func(token, key string) int {
+		req := httptest.NewRequest("POST", "/api/printers/printer-1/pause", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		req.Header.Set("Idempotency-Key", key)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	for i := 0; i < 3; i++ {
+		if code := post("", fmt.Sprintf("anon-%d", i)); code != http.StatusUnauthorized {
+			t.Fatalf("anonymous pause = %d, want %d", code, http.StatusUnauthorized)
+		}
+	}
+	if code := post("vic-token", "tap-1"); code != http.StatusForbidden {
+		t.Fatalf("viewer pause = %d, want %d", code, http.StatusForbidden)
+	}
+	if n := len(h.idempotency.entries); n != 0 {
+		t.Errorf("refused requests left %d keys behind", n)
+	}
+
+	// A refusal isn't replayed once the key is used by someone allowed
+	if code := post("olga-token", "tap-1"); code != http.StatusOK {
+		t.Errorf("operator pause = %d, want %d", code, http.StatusOK)
+	}
+}
+
+func TestIdempotencyKeysBounded(t *testing.T) {
+	clock := &stepClock{now: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)}
+	k := newIdempotencyKeys(clock)
+	done := &recordingWriter{code: http.StatusOK}
+
+	for i := 0; i < maxIdempotencyEntries; i++ {
+		key := fmt.Sprint(i)
+		k.claim(key, "POST /api/queue")
+		k.finish(key, done)
+		clock.advance(time.Millisecond)
+	}
+	if _, ok := k.claim("new", "POST /api/queue"); !ok || len(k.entries) != maxIdempotencyEntries {
+		t.Fatalf("claim when full = %v with %d entries", ok, len(k.entries))
+	}
+	if _, kept := k.entries["0"]; kept {
+		t.Error("the response closest to expiring was not evicted")
+	}
+
+	clock.advance(idempotencyTTL + time.Second)
+	k.prune()
+	if len(k.entries) != 1 {
+		t.Errorf("after expiry %d entries remain, want only the one in progress", len(k.entries))
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"hash/fnv"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+)
+
+// maxIdleViews bounds how many printer sets the idle tracker remembers; past
+// it the one polled longest ago is forgotten
+const maxIdleViews = 64
+
+// idleTracker decides when the dashboard should switch to its idle screen.
+// Any change in printer status resets the timer, so screens wake on the next poll.
+type idleTracker struct {
+	after time.Duration
+	mode  string
+	clock Clock
+
+	mu     sync.Mutex
+	states map[string]idleState
+}
+
+type idleState struct {
+	signature uint64
+	since     time.Time
+	seen      time.Time
+}
+
+func newIdleTracker(after time.Duration, mode string, clock Clock) *idleTracker {
+	return &idleTracker{
+		after:  after,
+		mode:   mode,
+		clock:  clock,
+		states: make(map[string]idleState),
+	}
+}
+
+// observe records the latest statuses for a view and returns the screen state
+// clients should display. Views are keyed by the printers they show, so
+// screens showing the same printers share a timer however they filtered them.
+func (t *idleTracker) observe(printers []*models.PrinterStatus) *models.ScreenInfo {
+	if t.after <= 0 {
+		return nil
+	}
+
+	now := t.clock.Now().UTC()
+	signature, quiet := statusSignature(printers)
+	ids := make([]string, len(printers))
+	for i, p := range printers {
+		ids[i] = p.ID
+	}
+	slices.Sort(ids)
+	view := strings.Join(ids, ",")
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	state, ok := t.states[view]
+	if !ok && len(t.states) >= maxIdleViews {
+		t.forgetStalest()
+	}
+	if !ok || state.signature != signature {
+		state = idleState{signature: signature, since: now}
+	}
+	state.seen = now
+	t.states[view] = state
+
+	screen := &models.ScreenInfo{Mode: t.mode}
+	if quiet && now.Sub(state.since) >= t.after {
+		since := state.since
+		screen.Idle = true
+		screen.IdleSince = &since
+	}
+	return screen
+}
+
+// forgetStalest drops the view polled longest ago. The caller holds t.mu.
+func (t *idleTracker) forgetStalest() {
+	var stalest string
+	var seen time.Time
+	for view, state := range t.states {
+		if seen.IsZero() || state.seen.Before(seen) {
+			stalest, seen = view, state.seen
+		}
+	}
+	delete(t.states, stalest)
+}
+
+// statusSignature hashes printer states, which arrive in configuration order,
+// and reports whether none need attention
+func statusSignature(printers []*models.PrinterStatus) (uint64, bool) {
+	quiet := true
+	hash := fnv.New64a()
+	for _, p := range printers {
+		hash.Write([]byte(p.ID))
+		hash.Write([]byte{'='})
+		hash.Write([]byte(p.Status))
+		hash.Write([]byte{','})
+		if p.Status != "idle" && p.Status != "offline" {
+			quiet = false
+		}
+	}
+	return hash.Sum64(), quiet
+}
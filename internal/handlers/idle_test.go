@@ -0,0 +1,56 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+)
+
+func TestIdleTracker(t *testing.T) {
+	clock := &stepClock{now: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)}
+	tracker := newIdleTracker(10*time.Minute, "clock", clock)
+	status := func(id, s string) *models.PrinterStatus { return &models.PrinterStatus{ID: id, Status: s} }
+
+	teamA := []*models.PrinterStatus{status("printer-1", "idle"), status("printer-2", "idle")}
+	teamB := []*models.PrinterStatus{status("printer-3", "printing")}
+	tracker.observe(teamA)
+	tracker.observe(teamB)
+
+	// Another team's busy printers don't keep this team's screens awake
+	clock.advance(11 * time.Minute)
+	tracker.observe(teamB)
+	if screen := tracker.observe(teamA); !screen.Idle {
+		t.Error("quiet printers are not idle after the timeout")
+	}
+	if screen := tracker.observe(teamB); screen.Idle {
+		t.Error("a printing printer went idle")
+	}
+
+	// Views are bounded however many printer sets are asked for
+	for i := 0; i < 2*maxIdleViews; i++ {
+		tracker.observe([]*models.PrinterStatus{status(fmt.Sprintf("printer-%d", i), "idle")})
+	}
+	if n := len(tracker.states); n > maxIdleViews {
+		t.Errorf("tracker remembers %d views, want at most %d", n, maxIdleViews)
+	}
+}
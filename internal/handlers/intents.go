@@ -0,0 +1,345 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/wmarchesi123/go-3dprint-client/config"
+	"github.com/wmarchesi123/octodash/internal/auth"
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/voice"
+)
+
+// intentInfo describes an intent for whoever builds a skill on top of the API
+type intentInfo struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Slots       []string `json:"slots"`
+	Role        string   `json:"role"`
+	Examples    []string `json:"examples"`
+}
+
+// intentCatalog lists every intent. There is deliberately no cancel: a
+// misheard word shouldn't be able to throw away a long print.
+var intentCatalog = []intentInfo{
+	{voice.FarmStatus, "Summary of the whole farm", []string{}, "viewer",
+		[]string{"how's the farm", "printer status"}},
+	{voice.PrinterStatus, "What one printer is doing", []string{"printer"}, "viewer",
+		[]string{"status of printer four", "what is the Voron doing"}},
+	{voice.TimeRemaining, "Time left on one printer, or on the job finishing next", []string{"printer?"}, "viewer",
+		[]string{"how long is left on the Prusa", "what's finishing next"}},
+	{voice.Temperatures, "Hotend and bed temperatures", []string{"printer"}, "viewer",
+		[]string{"how hot is printer two"}},
+	{voice.PausePrinter, "Pause a running print", []string{"printer"}, "operator",
+		[]string{"pause printer three"}},
+	{voice.ResumePrinter, "Resume a paused print", []string{"printer"}, "operator",
+		[]string{"resume printer three"}},
+}
+
+// intentReply is what an assistant should say back
+type intentReply struct {
+	speech    string
+	fulfilled bool
+	printerID string
+}
+
+// say builds a reply that did what was asked
+func say(printerID, format string, args ...interface{}) intentReply {
+	return intentReply{speech: fmt.Sprintf(format, args...), fulfilled: true, printerID: printerID}
+}
+
+// decline builds a reply explaining why nothing was done
+func decline(format string, args ...interface{}) intentReply {
+	return intentReply{speech: fmt.Sprintf(format, args...)}
+}
+
+// handleIntentList describes the intents, their slots and example phrases
+func (h *Handler) handleIntentList(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "ok",
+		"intents": intentCatalog,
+	})
+}
+
+// handleIntent answers an intent with a short sentence for a voice assistant
+// to read out. Skills that resolve intents themselves send the intent and its
+// slots; others can send the raw text. Requests that are understood but can't
+// be carried out, such as naming an unknown printer, still succeed with
+// fulfilled false and a spoken explanation.
+func (h *Handler) handleIntent(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Intent string            `json:"intent"`
+		Slots  map[string]string `json:"slots"`
+		Text   string            `json:"text"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	var reply intentReply
+	switch {
+	case req.Intent != "":
+		if !knownIntent(req.Intent) {
+			writeError(w, http.StatusBadRequest, "Unknown intent")
+			return
+		}
+		reply = h.fulfilIntent(r, req.Intent, req.Slots)
+	case req.Text != "":
+		intent, slots, ok := voice.Parse(req.Text)
+		if !ok {
+			reply = decline("Sorry, I don't know how to help with that.")
+			break
+		}
+		req.Intent = intent
+		reply = h.fulfilIntent(r, intent, slots)
+	default:
+		writeError(w, http.StatusBadRequest, "Send an intent or text")
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":    "ok",
+		"intent":    req.Intent,
+		"speech":    reply.speech,
+		"fulfilled": reply.fulfilled,
+	}
+	if reply.printerID != "" {
+		response["printer_id"] = reply.printerID
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func knownIntent(name string) bool {
+	for _, info := range intentCatalog {
+		if info.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// fulfilIntent carries out an intent against the printers the caller may see
+func (h *Handler) fulfilIntent(r *http.Request, intent string, slots map[string]string) intentReply {
+	visible := h.printersMatching(r, "")
+
+	switch intent {
+	case voice.FarmStatus:
+		return h.farmReply(h.collectStatuses(visible))
+	case voice.TimeRemaining:
+		if slots["printer"] == "" {
+			return h.nextDoneReply(h.collectStatuses(visible))
+		}
+	}
+
+	printer, reply, ok := resolvePrinter(visible, slots["printer"])
+	if !ok {
+		return reply
+	}
+	p := h.fetchPrinterStatus(printer)
+
+	switch intent {
+	case voice.PrinterStatus:
+		return say(p.ID, "%s", describePrinter(p))
+	case voice.TimeRemaining:
+		if p.Status != "printing" || p.Progress == nil {
+			return say(p.ID, "%s isn't printing right now.", p.Name)
+		}
+		return say(p.ID, "%s has %s left on %s.", p.Name, voice.Duration(p.Progress.PrintTimeLeft), voice.File(p.Progress.FileName))
+	case voice.Temperatures:
+		return h.temperatureReply(p)
+	case voice.PausePrinter, voice.ResumePrinter:
+		return h.controlReply(r, intent, p)
+	}
+	return decline("Sorry, I don't know how to help with that.")
+}
+
+// resolvePrinter finds the printer a spoken name refers to
+func resolvePrinter(printers []config.Printer, name string) (config.Printer, intentReply, bool) {
+	if name == "" {
+		return config.Printer{}, decline("Which printer?"), false
+	}
+	names := make([]string, len(printers))
+	for i, p := range printers {
+		names[i] = p.Name
+		if strings.EqualFold(p.ID, name) {
+			return p, intentReply{}, true
+		}
+	}
+	if i, ok := voice.Match(name, names); ok {
+		return printers[i], intentReply{}, true
+	}
+	return config.Printer{}, decline("I couldn't find a printer called %s.", name), false
+}
+
+// describePrinter says what a printer is doing in a sentence
+func describePrinter(p *models.PrinterStatus) string {
+	file := ""
+	if p.Progress != nil {
+		file = voice.File(p.Progress.FileName)
+	}
+
+	switch p.Status {
+	case "printing":
+		if p.Progress == nil {
+			return fmt.Sprintf("%s is printing.", p.Name)
+		}
+		return fmt.Sprintf("%s is printing %s, %.0f percent done, with %s left.",
+			p.Name, file, p.Progress.Completion, voice.Duration(p.Progress.PrintTimeLeft))
+	case "paused":
+		if p.Progress == nil {
+			return fmt.Sprintf("%s is paused.", p.Name)
+		}
+		return fmt.Sprintf("%s is paused at %.0f percent on %s.", p.Name, p.Progress.Completion, file)
+	case "error":
+		if p.Error != "" {
+			return fmt.Sprintf("%s has an error: %s.", p.Name, strings.TrimRight(p.Error, "."))
+		}
+		return fmt.Sprintf("%s has an error.", p.Name)
+	case "completed":
+		if p.Completed != nil {
+			return fmt.Sprintf("%s finished %s and is waiting for the bed to be cleared.", p.Name, voice.File(p.Completed.File))
+		}
+		return fmt.Sprintf("%s is waiting for the bed to be cleared.", p.Name)
+	case "cooldown":
+		return fmt.Sprintf("%s is cooling down.", p.Name)
+	case "offline":
+		return fmt.Sprintf("%s is offline.", p.Name)
+	}
+	return fmt.Sprintf("%s is %s.", p.Name, p.Status)
+}
+
+// farmReply sums up the farm: how much is printing, what finishes next and
+// what needs a person
+func (h *Handler) farmReply(printers []*models.PrinterStatus) intentReply {
+	if len(printers) == 0 {
+		return say("", "There are no printers set up.")
+	}
+
+	var printing int
+	var errored, waiting, offline []string
+	for _, p := range printers {
+		switch p.Status {
+		case "printing":
+			printing++
+		case "error":
+			errored = append(errored, p.Name)
+		case "completed":
+			waiting = append(waiting, p.Name)
+		case "offline":
+			offline = append(offline, p.Name)
+		}
+	}
+
+	var sentences []string
+	switch {
+	case printing == 0:
+		sentences = append(sentences, "Nothing is printing.")
+	case printing == len(printers):
+		sentences = append(sentences, fmt.Sprintf("All %s are printing.", voice.Count(len(printers), "printer")))
+	case printing == 1:
+		sentences = append(sentences, fmt.Sprintf("1 of %d printers is printing.", len(printers)))
+	default:
+		sentences = append(sentences, fmt.Sprintf("%d of %d printers are printing.", printing, len(printers)))
+	}
+	if next := h.nextDoneReply(printers); printing > 0 && next.printerID != "" {
+		sentences = append(sentences, next.speech)
+	}
+	if len(errored) > 0 {
+		sentences = append(sentences, fmt.Sprintf("%s %s.", voice.List(errored), pick(len(errored), "has an error", "have errors")))
+	}
+	if len(waiting) > 0 {
+		sentences = append(sentences, fmt.Sprintf("%s %s.", voice.List(waiting), pick(len(waiting), "has a finished part on the bed", "have finished parts on the bed")))
+	}
+	if len(offline) > 0 {
+		sentences = append(sentences, fmt.Sprintf("%s %s.", voice.List(offline), pick(len(offline), "is offline", "are offline")))
+	}
+	return say("", "%s", strings.Join(sentences, " "))
+}
+
+// nextDoneReply names the running job that finishes soonest
+func (h *Handler) nextDoneReply(printers []*models.PrinterStatus) intentReply {
+	var next *models.PrinterStatus
+	for _, p := range printers {
+		if p.Status == "printing" && p.Progress != nil && p.Progress.PrintTimeLeft > 0 &&
+			(next == nil || p.Progress.PrintTimeLeft < next.Progress.PrintTimeLeft) {
+			next = p
+		}
+	}
+	if next == nil {
+		return say("", "Nothing is printing right now.")
+	}
+	return say(next.ID, "%s finishes next, in %s.", next.Name, voice.Duration(next.Progress.PrintTimeLeft))
+}
+
+// temperatureReply reads out the hotend and bed, with targets while heating
+func (h *Handler) temperatureReply(p *models.PrinterStatus) intentReply {
+	if p.Temperatures == nil {
+		return intentReply{speech: fmt.Sprintf("I can't read %s's temperatures right now.", p.Name), printerID: p.ID}
+	}
+	unit := h.settings.Units.Temperature
+	reading := func(part string, actual, target float64) string {
+		s := fmt.Sprintf("%s is at %.0f degrees", part, models.ConvertTemperature(actual, unit))
+		if target > 0 && target-actual > 2 {
+			s += fmt.Sprintf(", heating to %.0f", models.ConvertTemperature(target, unit))
+		}
+		return s
+	}
+	t := p.Temperatures
+	return say(p.ID, "%s's %s, and the %s.", p.Name,
+		reading("hotend", t.HotendActual, t.HotendTarget), reading("bed", t.BedActual, t.BedTarget))
+}
+
+// controlReply pauses or resumes a print for callers allowed to
+func (h *Handler) controlReply(r *http.Request, intent string, p *models.PrinterStatus) intentReply {
+	user := auth.UserFromContext(r.Context())
+	action, verb, want := "pause", "Pausing", "printing"
+	if intent == voice.ResumePrinter {
+		action, verb, want = "resume", "Resuming", "paused"
+	}
+
+	if !user.Allowed(auth.RoleOperator, p.ID) {
+		return decline("You need operator access to %s %s.", action, p.Name)
+	}
+	if p.Status != want {
+		return intentReply{speech: fmt.Sprintf("%s isn't %s, so there's nothing to %s.", p.Name, want, action), printerID: p.ID}
+	}
+
+	h.logger.Printf("%s requested by voice by %s for %s", action, user.Name, p.Name)
+	_, err := h.commands.run(p.ID, action, "", user.Name, func() error {
+		return jobActions[action](h.controlClient(p.ID))
+	})
+	if err != nil {
+		h.logger.Printf("Voice %s on %s failed: %v", action, p.Name, err)
+		return intentReply{speech: fmt.Sprintf("I couldn't reach %s to %s it.", p.Name, action), printerID: p.ID}
+	}
+	return say(p.ID, "%s %s.", verb, p.Name)
+}
+
+// pick chooses the singular or plural phrase for n
+func pick(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
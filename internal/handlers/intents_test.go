@@ -0,0 +1,90 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestIntents(t *testing.T) {
+	t.Setenv("AUTH_USERS", "vic:viewer:vic-token,olga:operator:olga-token")
+	mini := testutil.NewOctoPrint(t)
+	mini.SetPrinting("gear_v2.gcode", 42, 754)
+	voron := testutil.NewOctoPrint(t)
+	h := newTestHandler(t, testutil.NewSpoolman(t),
+		testutil.Printer{Name: "Mini", Server: mini}, testutil.Printer{Name: "Voron 2.4", Server: voron})
+
+	ask := func(token, body string) (int, map[string]interface{}) {
+		t.Helper()
+		req := httptest.NewRequest("POST", "/api/intents", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		var reply map[string]interface{}
+		json.NewDecoder(rec.Body).Decode(&reply)
+		return rec.Code, reply
+	}
+
+	_, reply := ask("vic-token", `{"text": "How's the farm?"}`)
+	if reply["intent"] != "FarmStatus" || reply["speech"] != "1 of 2 printers is printing. Mini finishes next, in about 13 minutes." {
+		t.Errorf("farm status = %v", reply)
+	}
+
+	_, reply = ask("vic-token", `{"intent": "PrinterStatus", "slots": {"printer": "mini"}}`)
+	if reply["speech"] != "Mini is printing gear v2, 42 percent done, with about 13 minutes left." || reply["printer_id"] != "printer-1" {
+		t.Errorf("printer status = %v", reply)
+	}
+
+	_, reply = ask("vic-token", `{"text": "what is the status of the voron"}`)
+	if reply["speech"] != "Voron 2.4 is idle." {
+		t.Errorf("idle printer status = %v", reply)
+	}
+
+	_, reply = ask("vic-token", `{"intent": "PrinterStatus", "slots": {"printer": "ender"}}`)
+	if reply["fulfilled"] != false || reply["speech"] != "I couldn't find a printer called ender." {
+		t.Errorf("unknown printer = %v", reply)
+	}
+
+	// Pausing needs an operator and a running print
+	_, reply = ask("vic-token", `{"text": "pause the mini"}`)
+	if reply["fulfilled"] != false || len(mini.Commands()) != 0 {
+		t.Errorf("viewer pause = %v, commands %v", reply, mini.Commands())
+	}
+	_, reply = ask("olga-token", `{"text": "pause the voron"}`)
+	if reply["fulfilled"] != false || reply["speech"] != "Voron 2.4 isn't printing, so there's nothing to pause." {
+		t.Errorf("pausing an idle printer = %v", reply)
+	}
+	_, reply = ask("olga-token", `{"text": "pause the mini"}`)
+	if reply["fulfilled"] != true || reply["speech"] != "Pausing Mini." || !strings.Contains(strings.Join(mini.Commands(), ""), "pause") {
+		t.Errorf("operator pause = %v, commands %v", reply, mini.Commands())
+	}
+
+	if code, _ := ask("vic-token", `{"intent": "CancelPrinter"}`); code != http.StatusBadRequest {
+		t.Errorf("unknown intent = %d, want %d", code, http.StatusBadRequest)
+	}
+	if code, _ := ask("", `{"text": "status"}`); code != http.StatusUnauthorized {
+		t.Errorf("no token = %d, want %d", code, http.StatusUnauthorized)
+	}
+}
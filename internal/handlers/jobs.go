@@ -0,0 +1,261 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wmarchesi123/go-3dprint-client/config"
+	"github.com/wmarchesi123/go-3dprint-client/octoprint"
+	"github.com/wmarchesi123/octodash/internal/jobs"
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/octoapi"
+)
+
+// maxJobsLimit caps how many jobs a single request can return
+const maxJobsLimit = 1000
+
+// recordJob adds a stopped job and the kWh it used to the farm's job history, returning the record
+func (h *Handler) recordJob(printer config.Printer, status string, job *octoprint.JobResponse, kwh float64) models.JobRecord {
+	now := h.clock.Now().UTC()
+	rec := models.JobRecord{
+		PrinterID:  printer.ID,
+		File:       job.Job.File.Display,
+		Result:     jobResult(status, job),
+		FinishedAt: now,
+		PrintTime:  job.Progress.PrintTime,
+		Filament:   job.Job.Filament.Tool0.Length / 1000 * min(max(job.Progress.Completion/100, 0), 1),
+		EnergyKWh:  kwh,
+		Source:     models.JobSourceDashboard,
+	}
+	if rec.PrintTime > 0 {
+		started := now.Add(-time.Duration(rec.PrintTime) * time.Second).Truncate(time.Second)
+		rec.StartedAt = &started
+	}
+	if _, err := h.jobHistory.Record(rec, job.Job.File.Path); err != nil {
+		h.logger.Printf("Failed to record job %s on %s: %v", rec.File, printer.Name, err)
+	}
+	rec.ID = jobs.Key(rec, job.Job.File.Path)
+	return rec
+}
+
+// jobsQuery reads ?printer= and ?since= shared by the job history endpoints
+func (h *Handler) jobsQuery(w http.ResponseWriter, r *http.Request) (printerID string, since time.Time, ok bool) {
+	q := r.URL.Query()
+
+	if printerID = q.Get("printer"); printerID != "" {
+		if _, found := h.findPrinter(printerID); !found || !h.tenancy(r).printer(printerID) {
+			writeError(w, http.StatusNotFound, "Printer not found")
+			return "", since, false
+		}
+	}
+
+	if v := q.Get("since"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid since, expected RFC 3339")
+			return "", since, false
+		}
+		since = t
+	}
+	return printerID, since, true
+}
+
+// visibleJobs lists the history the caller's teams can see
+func (h *Handler) visibleJobs(r *http.Request, printerID string, since time.Time) ([]models.JobRecord, error) {
+	all, err := h.jobHistory.List(printerID, since, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	t := h.tenancy(r)
+	visible := []models.JobRecord{}
+	for _, j := range all {
+		if _, ok := h.findPrinter(j.PrinterID); ok && t.printer(j.PrinterID) {
+			visible = append(visible, j)
+		}
+	}
+	return visible, nil
+}
+
+// handleJobs returns finished jobs newest first, optionally filtered by
+// ?printer=, ?since= and ?result=
+func (h *Handler) handleJobs(w http.ResponseWriter, r *http.Request) {
+	printerID, since, ok := h.jobsQuery(w, r)
+	if !ok {
+		return
+	}
+
+	limit := 100
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+		limit = min(n, maxJobsLimit)
+	}
+
+	all, err := h.visibleJobs(r, printerID, since)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	result := r.URL.Query().Get("result")
+	list := []models.JobRecord{}
+	for _, j := range all {
+		if len(list) >= limit {
+			break
+		}
+		if result == "" || j.Result == result {
+			list = append(list, j)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "ok",
+		"jobs":   list,
+	})
+}
+
+// handleJobStats totals finished jobs for the farm and each printer
+func (h *Handler) handleJobStats(w http.ResponseWriter, r *http.Request) {
+	printerID, since, ok := h.jobsQuery(w, r)
+	if !ok {
+		return
+	}
+
+	all, err := h.visibleJobs(r, printerID, since)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	farm, printers := jobs.Totals(all)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":   "ok",
+		"farm":     farm,
+		"printers": printers,
+	})
+}
+
+func (h *Handler) handleJobImports(w http.ResponseWriter, r *http.Request) {
+	imports, err := h.jobHistory.Imports()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "ok",
+		"imports": imports,
+	})
+}
+
+// handleJobImport pulls past jobs from each printer's OctoPrint into the job
+// history. Printers already imported are left alone unless "force" is set;
+// importing again never duplicates jobs.
+func (h *Handler) handleJobImport(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Printers []string `json:"printers"`
+		Force    bool     `json:"force"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	printers := h.config.Printers
+	if len(req.Printers) > 0 {
+		printers = nil
+		for _, id := range req.Printers {
+			p, ok := h.findPrinter(id)
+			if !ok {
+				writeError(w, http.StatusNotFound, "Printer not found: "+id)
+				return
+			}
+			printers = append(printers, p)
+		}
+	}
+
+	h.importMu.Lock()
+	defer h.importMu.Unlock()
+
+	var targets []config.Printer
+	for _, p := range printers {
+		done, err := h.jobHistory.Imported(p.ID)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if req.Force || !done {
+			targets = append(targets, p)
+		}
+	}
+
+	// Fetch concurrently since large histories are slow, then record in turn
+	type fetched struct {
+		past   []octoapi.HistoricJob
+		source string
+		err    error
+	}
+	results := make([]fetched, len(targets))
+	var wg sync.WaitGroup
+	for i, p := range targets {
+		wg.Add(1)
+		go func(i int, p config.Printer) {
+			defer wg.Done()
+			results[i].past, results[i].source, results[i].err = h.controlClient(p.ID).JobHistory()
+		}(i, p)
+	}
+	wg.Wait()
+
+	imports := []models.JobImport{}
+	var names []string
+	for i, p := range targets {
+		res := results[i]
+		if res.err != nil {
+			h.logger.Printf("Failed to fetch job history from %s: %v", p.Name, res.err)
+			imports = append(imports, models.JobImport{PrinterID: p.ID, Time: h.clock.Now().UTC().Truncate(time.Second), Error: res.err.Error()})
+			continue
+		}
+
+		imp, err := h.jobHistory.Import(p.ID, res.source, res.past, h.clock.Now())
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		h.logger.Printf("Imported %d past jobs for %s from %s", imp.Imported, p.Name, imp.Source)
+		imports = append(imports, imp)
+		names = append(names, p.ID)
+	}
+	h.audit(r, "jobs.import", "", strings.Join(names, ","))
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "ok",
+		"imports": imports,
+	})
+}
@@ -0,0 +1,126 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestJobHistoryImport(t *testing.T) {
+	mk3 := testutil.NewOctoPrint(t)
+	mk3.SetHistory("PrintJobHistory", map[string]interface{}{
+		"totalItemCount": 2,
+		"allPrintJobs": []map[string]interface{}{
+			{"databaseId": 7, "fileName": "bracket.gcode", "printStartDateTime": "02.03.2025 10:00", "printEndDateTime": "02.03.2025 11:00", "duration": 3600, "statusResult": "success", "filamentModels": []map[string]interface{}{{"usedLength": 2500}}},
+			{"databaseId": 8, "fileName": "gear.gcode", "printStartDateTime": "03.03.2025 09:00", "printEndDateTime": "03.03.2025 09:10", "duration": 600, "statusResult": "canceled"},
+		},
+	})
+	mini := testutil.NewOctoPrint(t)
+	mini.SetHistory("printhistory", map[string]interface{}{
+		"history": []map[string]interface{}{
+			{"key": "a1", "fileName": "clip.gcode", "timestamp": 1740000000, "printTime": 1200, "success": false},
+		},
+	})
+	bare := testutil.NewOctoPrint(t)
+	h := newTestHandler(t, testutil.NewSpoolman(t),
+		testutil.Printer{Name: "MK3", Server: mk3},
+		testutil.Printer{Name: "Mini", Server: mini},
+		testutil.Printer{Name: "Bare", Server: bare},
+	)
+
+	importJobs := func(body string) []models.JobImport {
+		t.Helper()
+		rec := postJSON(h, "/api/admin/jobs/import", body)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("import = %d: %s", rec.Code, rec.Body)
+		}
+		var response struct {
+			Imports []models.JobImport `json:"imports"`
+		}
+		json.NewDecoder(rec.Body).Decode(&response)
+		return response.Imports
+	}
+
+	imports := importJobs("")
+	if len(imports) != 3 {
+		t.Fatalf("imports = %+v, want one per printer", imports)
+	}
+	if imp := imports[0]; imp.Source != "printjobhistory" || imp.Imported != 2 {
+		t.Errorf("MK3 import = %+v, want 2 jobs from PrintJobHistory", imp)
+	}
+	if imp := imports[1]; imp.Source != "printhistory" || imp.Imported != 1 {
+		t.Errorf("Mini import = %+v, want 1 job from printhistory", imp)
+	}
+	if imp := imports[2]; imp.Error == "" {
+		t.Errorf("Bare import = %+v, want an error with no history source", imp)
+	}
+
+	// Imported printers are left alone, and forcing never duplicates jobs
+	if imports := importJobs(""); len(imports) != 1 || imports[0].PrinterID != "printer-3" {
+		t.Errorf("second import = %+v, want only the failed printer retried", imports)
+	}
+	if imports := importJobs(`{"printers": ["printer-1"], "force": true}`); len(imports) != 1 || imports[0].Imported != 0 || imports[0].Skipped != 2 {
+		t.Errorf("forced import = %+v, want both jobs skipped", imports)
+	}
+
+	// Jobs that finish from now on join the imported ones
+	mk3.SetPrinting("bracket.gcode", 50, 1800)
+	getStatus(t, h)
+	mk3.SetFinished("bracket.gcode")
+	getStatus(t, h)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/api/jobs?printer=printer-1", nil))
+	var list struct {
+		Jobs []models.JobRecord `json:"jobs"`
+	}
+	json.NewDecoder(rec.Body).Decode(&list)
+	if len(list.Jobs) != 3 || list.Jobs[0].Source != models.JobSourceDashboard || list.Jobs[0].Result != models.JobCompleted {
+		t.Fatalf("MK3 jobs = %+v, want the live job first, then two imported", list.Jobs)
+	}
+	if j := list.Jobs[2]; j.File != "bracket.gcode" || j.PrintTime != 3600 || j.Filament != 2.5 || j.StartedAt == nil {
+		t.Errorf("imported job = %+v", j)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/api/jobs/stats", nil))
+	var stats struct {
+		Farm     models.JobStats   `json:"farm"`
+		Printers []models.JobStats `json:"printers"`
+	}
+	json.NewDecoder(rec.Body).Decode(&stats)
+	if stats.Farm.Jobs != 4 || stats.Farm.Completed != 2 || stats.Farm.Cancelled != 1 || stats.Farm.Failed != 1 || stats.Farm.SuccessRate != 0.5 {
+		t.Errorf("farm stats = %+v", stats.Farm)
+	}
+	if len(stats.Printers) != 2 || stats.Printers[1].PrinterID != "printer-2" || stats.Printers[1].PrintTime != 1200 {
+		t.Errorf("printer stats = %+v", stats.Printers)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/api/jobs?printer=printer-9", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unknown printer = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,332 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/skip2/go-qrcode"
+	"github.com/wmarchesi123/go-3dprint-client/config"
+	"github.com/wmarchesi123/go-3dprint-client/spoolman"
+	"github.com/wmarchesi123/octodash/internal/auth"
+	"github.com/wmarchesi123/octodash/internal/models"
+)
+
+// maxTool is the highest extruder index OctoPrint reports temperatures for
+const maxTool = 4
+
+const labelsTemplate = `<!DOCTYPE html>
+<html>
+<head>
+    <title>Spool labels - OctoDash</title>
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+    <link rel="stylesheet" href="{{asset "style.css"}}">
+</head>
+<body class="labels-page">
+    <p class="labels-hint">Print this page and stick a label on each spool. Scanning a label opens a page to load the spool on a printer.</p>
+    <div class="labels">
+        {{range .}}
+        <div class="spool-label">
+            <img src="{{.QR}}" alt="QR code for spool {{.ID}}">
+            <div class="spool-label-text">
+                <div class="spool-label-id">#{{.ID}}{{if .Spoolman}} · {{.Spoolman}}{{end}}</div>
+                <div class="spool-label-name">{{.Name}}</div>
+                <div><span class="spool-color-dot" style="background-color: {{.Color}}"></span> {{.Material}}</div>
+                <div>{{.Vendor}}</div>
+            </div>
+        </div>
+        {{else}}
+        <p>No spools to label.</p>
+        {{end}}
+    </div>
+</body>
+</html>
+`
+
+const loadTemplate = `<!DOCTYPE html>
+<html>
+<head>
+    <title>Load spool #{{.ID}} - OctoDash</title>
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+    <link rel="stylesheet" href="{{asset "style.css"}}">
+</head>
+<body class="load-page">
+    <div class="load-spool">
+        <span class="spool-color-dot" style="background-color: {{.Color}}"></span>
+        <div>
+            <h1>{{.Name}}</h1>
+            <p>#{{.ID}} · {{.Material}} · {{.Vendor}}{{if .Remaining}} · {{.Remaining}}g left{{end}}</p>
+        </div>
+    </div>
+    <form id="load-form" data-spool="{{.ID}}" data-spoolman="{{.Spoolman}}">
+        <label>Tool
+            <select id="load-tool">
+                {{range .Tools}}<option value="{{.}}">T{{.}}</option>{{end}}
+            </select>
+        </label>
+        {{range .Printers}}
+        <button type="button" class="load-printer" data-printer="{{.ID}}">Load on {{.Name}}</button>
+        {{else}}
+        <p>No printer uses the Spoolman instance this spool belongs to.</p>
+        {{end}}
+    </form>
+    <div class="load-drying">
+        <h2>Drying</h2>
+        <p>{{if .Drying}}Drying now{{else if .LastDried}}Last dried {{.LastDried}}{{else}}No drying recorded{{end}}</p>
+        <label>Dryer temperature (°C) <input type="number" id="drying-temp" value="55" min="1" max="150"></label>
+        <button type="button" id="drying-start">Start drying</button>
+        <button type="button" id="drying-stop">Stop drying</button>
+    </div>
+    <p class="load-result" id="load-result"></p>
+    <script src="{{asset "load.js"}}"></script>
+</body>
+</html>
+`
+
+// spoolLabel is one label on the printable sheet
+type spoolLabel struct {
+	ID       int
+	Name     string
+	Material string
+	Vendor   string
+	Color    string
+	Spoolman string
+	QR       string
+}
+
+// handleSpoolLabels serves a printable sheet of QR labels for unarchived spools,
+// optionally limited to ?ids=1,2,3 from the instance named by ?spoolman=
+func (h *Handler) handleSpoolLabels(w http.ResponseWriter, r *http.Request) {
+	src, ok := h.spoolSource(r.URL.Query().Get("spoolman"))
+	if !ok {
+		http.Error(w, "Unknown Spoolman instance", http.StatusNotFound)
+		return
+	}
+
+	wanted := make(map[int]bool)
+	for _, field := range strings.Split(r.URL.Query().Get("ids"), ",") {
+		if id, err := strconv.Atoi(strings.TrimSpace(field)); err == nil {
+			wanted[id] = true
+		}
+	}
+
+	spools, err := src.client.GetAllSpools()
+	if err != nil {
+		http.Error(w, "Failed to read spools: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	labels := []spoolLabel{}
+	for i := range spools {
+		sp := &spools[i]
+		if sp.Archived || (len(wanted) > 0 && !wanted[sp.ID]) {
+			continue
+		}
+		info := h.spoolInfo(src, sp)
+		label := spoolLabel{
+			ID:       sp.ID,
+			Name:     info["name"].(string),
+			Material: sp.Filament.Material,
+			Vendor:   sp.Filament.Vendor.Name,
+			Color:    info["color"].(string),
+			QR:       "/api/spools/" + strconv.Itoa(sp.ID) + "/qr.png",
+		}
+		if name, ok := info["spoolman"].(string); ok {
+			label.Spoolman = name
+			label.QR += "?" + url.Values{"spoolman": {name}}.Encode()
+		}
+		labels = append(labels, label)
+	}
+
+	h.renderPage(w, "labels", labelsTemplate, labels)
+}
+
+// handleSpoolQR returns a PNG QR code linking to the spool's load page
+func (h *Handler) handleSpoolQR(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid spool ID")
+		return
+	}
+	src, ok := h.spoolSource(r.URL.Query().Get("spoolman"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Unknown Spoolman instance")
+		return
+	}
+
+	png, err := qrcode.Encode(h.loadURL(r, src, id), qrcode.Medium, 256)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	w.Write(png)
+}
+
+// handleLoadPage is where a scanned label lands: it shows the spool and
+// offers every printer that tracks spools in the same Spoolman
+func (h *Handler) handleLoadPage(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	src, ok := h.spoolSource(q.Get("spoolman"))
+	if !ok {
+		http.Error(w, "Unknown Spoolman instance", http.StatusNotFound)
+		return
+	}
+	spool, err := src.client.GetSpool(q.Get("spool"))
+	if err != nil {
+		http.Error(w, "Spool not found: "+err.Error(), http.StatusNotFound)
+		return
+	}
+
+	info := spoolman.FormatSpoolInfo(spool)
+	data := struct {
+		ID        int
+		Name      string
+		Material  string
+		Vendor    string
+		Color     string
+		Remaining int
+		Spoolman  string
+		Drying    bool
+		LastDried string
+		Tools     []int
+		Printers  []struct{ ID, Name string }
+	}{
+		ID:        spool.ID,
+		Name:      info["name"].(string),
+		Material:  spool.Filament.Material,
+		Vendor:    spool.Filament.Vendor.Name,
+		Color:     info["color"].(string),
+		Remaining: int(spool.RemainingWeight),
+		Spoolman:  q.Get("spoolman"),
+	}
+	if last, err := h.drying.Latest(src.name, strconv.Itoa(spool.ID)); err == nil && last != nil {
+		data.Drying = last.EndedAt == nil
+		if last.EndedAt != nil {
+			data.LastDried = models.FormatTimestamp(*last.EndedAt, h.settings.Timezone)
+		}
+	}
+	for tool := 0; tool <= maxTool; tool++ {
+		data.Tools = append(data.Tools, tool)
+	}
+	for _, p := range h.config.Printers {
+		if h.spoolSourceFor(p.ID).name == src.name {
+			data.Printers = append(data.Printers, struct{ ID, Name string }{p.ID, p.Name})
+		}
+	}
+
+	h.renderPage(w, "load", loadTemplate, data)
+}
+
+// handleLoadSpool makes a spool the active one for a printer's tool via the
+// OctoPrint Spoolman plugin
+func (h *Handler) handleLoadSpool(w http.ResponseWriter, r *http.Request) {
+	printer, ok := h.findPrinter(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Printer not found")
+		return
+	}
+
+	var req struct {
+		SpoolID  string `json:"spool_id"`
+		Tool     int    `json:"tool"`
+		Spoolman string `json:"spoolman"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.SpoolID == "" {
+		writeError(w, http.StatusBadRequest, "spool_id is required")
+		return
+	}
+	if req.Tool < 0 || req.Tool > maxTool {
+		writeError(w, http.StatusBadRequest, "Invalid tool")
+		return
+	}
+
+	// A spool ID only means something to the Spoolman the printer's plugin talks to
+	src := h.spoolSourceFor(printer.ID)
+	if req.Spoolman != "" && req.Spoolman != src.name {
+		writeError(w, http.StatusConflict, printer.Name+" does not use Spoolman "+req.Spoolman)
+		return
+	}
+	if _, err := src.client.GetSpool(req.SpoolID); err != nil {
+		writeError(w, http.StatusNotFound, "Spool not found")
+		return
+	}
+
+	if isDryRun(r) {
+		h.dryRunPrinters(w, r, "load spool "+req.SpoolID, 0, []config.Printer{printer}, func(_ config.Printer, c PrinterClient, _ ControlClient) error {
+			return c.SetActiveSpool(req.SpoolID, req.Tool)
+		})
+		return
+	}
+
+	h.logger.Printf("Spool %s loaded on %s T%d by %s", req.SpoolID, printer.Name, req.Tool, auth.UserFromContext(r.Context()).Name)
+
+	if err := h.printerClient(printer.ID).SetActiveSpool(req.SpoolID, req.Tool); err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"status": "ok"})
+}
+
+// loadURL is the address a spool's QR label points at
+func (h *Handler) loadURL(r *http.Request, src spoolSource, id int) string {
+	q := url.Values{"spool": {strconv.Itoa(id)}}
+	if len(h.spoolSources) > 1 {
+		q.Set("spoolman", src.name)
+	}
+	return h.publicBase(r) + "/load?" + q.Encode()
+}
+
+// publicBase is the address other devices reach the dashboard at: PUBLIC_URL,
+// or the scheme and host this request came in on
+func (h *Handler) publicBase(r *http.Request) string {
+	if h.settings.PublicURL != "" {
+		return h.settings.PublicURL
+	}
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+// renderPage executes a standalone HTML page template
+func (h *Handler) renderPage(w http.ResponseWriter, name, text string, data interface{}) {
+	tmpl, err := template.New(name).Funcs(template.FuncMap{"asset": h.assets.URL}).Parse(text)
+	if err != nil {
+		http.Error(w, "Template error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	w.Header().Set("Cache-Control", "no-cache")
+	tmpl.Execute(w, data)
+}
@@ -0,0 +1,81 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestSpoolLabelsAndLoad(t *testing.T) {
+	sm := testutil.NewSpoolman(t,
+		testutil.Spool(3, "Polymaker", "PLA", "0000ff", 500),
+		testutil.Spool(4, "Prusament", "PETG", "ff0000", 900),
+	)
+	op := testutil.NewOctoPrint(t)
+	h := newTestHandler(t, sm, testutil.Printer{Name: "Mini", Server: op})
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s = %d: %s", path, rec.Code, rec.Body)
+		}
+		return rec
+	}
+
+	labels := get("/labels/spools?ids=3").Body.String()
+	if !strings.Contains(labels, `src="/api/spools/3/qr.png"`) || strings.Contains(labels, "/api/spools/4/") {
+		t.Errorf("label sheet for ids=3 = %s", labels)
+	}
+
+	if !strings.Contains(labels, "background-color: #0000ff") {
+		t.Errorf("label sheet is missing the spool color")
+	}
+
+	qr := get("/api/spools/3/qr.png")
+	if ct := qr.Header().Get("Content-Type"); ct != "image/png" || !bytes.HasPrefix(qr.Body.Bytes(), []byte("\x89PNG")) {
+		t.Errorf("QR code is %s, want a PNG", ct)
+	}
+
+	page := get("/load?spool=3").Body.String()
+	if !strings.Contains(page, `data-printer="printer-1"`) || !strings.Contains(page, "Polymaker") {
+		t.Errorf("load page = %s", page)
+	}
+
+	load := func(body string) int {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("POST", "/api/printers/printer-1/spool", strings.NewReader(body)))
+		return rec.Code
+	}
+	if code := load(`{"spool_id": "99"}`); code != http.StatusNotFound {
+		t.Errorf("loading an unknown spool = %d, want %d", code, http.StatusNotFound)
+	}
+	if code := load(`{"spool_id": "3", "tool": 0}`); code != http.StatusOK {
+		t.Fatalf("loading spool 3 = %d", code)
+	}
+	if spool := getStatus(t, h)["printer-1"].CurrentSpool; spool == nil || spool["id"] != "3" {
+		t.Errorf("current spool after loading = %v, want 3", spool)
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/wmarchesi123/octodash/web"
+)
+
+// libraryScript is where pages load a third-party library from
+type libraryScript struct {
+	Src       string
+	Integrity string
+}
+
+// resolveLibraries serves each library from the binary when it is vendored,
+// falling back to its upstream URL unless assets must all be local
+func (h *Handler) resolveLibraries() error {
+	h.libraries = make(map[string]libraryScript)
+	for _, lib := range web.Libraries {
+		vendored, err := lib.Verify(h.assets.FS())
+		if err != nil {
+			return err
+		}
+		switch {
+		case vendored:
+			h.libraries[lib.Name] = libraryScript{Src: h.assets.URL(lib.File), Integrity: lib.Integrity}
+		case h.settings.LocalAssets:
+			return fmt.Errorf("%s is not vendored; run go generate ./web or unset ASSETS_LOCAL_ONLY", lib.Name)
+		default:
+			h.logger.Printf("%s is not vendored; loading it from %s", lib.Name, lib.Source)
+			h.libraries[lib.Name] = libraryScript{Src: lib.Source, Integrity: lib.Integrity}
+		}
+	}
+	return nil
+}
+
+// library is the template function that looks up a library's script
+func (h *Handler) library(name string) libraryScript {
+	return h.libraries[name]
+}
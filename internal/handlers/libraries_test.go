@@ -0,0 +1,76 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/wmarchesi123/octodash/internal/settings"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+	"github.com/wmarchesi123/octodash/web"
+)
+
+func TestLibraries(t *testing.T) {
+	sm := testutil.NewSpoolman(t)
+	cfg := testutil.Config(sm, testutil.Printer{Name: "Mini", Server: testutil.NewOctoPrint(t)})
+	dashboard := func(h *Handler) string {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+		return rec.Body.String()
+	}
+
+	for _, lib := range web.Libraries {
+		vendored, err := lib.Verify(web.Static())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		page := dashboard(newHandlerWithConfig(t, cfg))
+		want := `src="` + lib.Source + `"`
+		if vendored {
+			want = `src="/static/vendor/`
+		}
+		if !strings.Contains(page, want) {
+			t.Errorf("%s: dashboard has no %s", lib.Name, want)
+		}
+		if vendored && !strings.Contains(page, `integrity="`+lib.Integrity+`"`) {
+			t.Errorf("%s: dashboard has no integrity attribute", lib.Name)
+		}
+
+		// Local-only refuses to fall back to the CDN
+		t.Setenv("ASSETS_LOCAL_ONLY", "true")
+		s, err := settings.Load()
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.DataDir = t.TempDir()
+		h, err := NewHandler(cfg, s)
+		if vendored && err != nil {
+			t.Errorf("%s: local-only with vendored copy: %v", lib.Name, err)
+		}
+		if !vendored && (err == nil || !strings.Contains(err.Error(), lib.Name)) {
+			t.Errorf("%s: local-only without vendored copy = %v, want error", lib.Name, err)
+		}
+		if h != nil {
+			h.Close()
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/wmarchesi123/go-3dprint-client/config"
+	"github.com/wmarchesi123/octodash/internal/lights"
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/settings"
+)
+
+// newLights sets up the status lights, checking the printers they name; it
+// returns nil when none are configured
+func (h *Handler) newLights(cfg *config.Config, configured []settings.LightSettings) (*lights.Controller, error) {
+	if len(configured) == 0 {
+		return nil, nil
+	}
+	for _, l := range configured {
+		for _, id := range append([]string{l.Printer}, l.Printers...) {
+			if id != "" && !slices.ContainsFunc(cfg.Printers, func(p config.Printer) bool { return p.ID == id }) {
+				return nil, fmt.Errorf("light %s: unknown printer %q", l.ID, id)
+			}
+		}
+	}
+
+	gcode := func(printerID string, commands ...string) error {
+		_, err := h.commands.run(printerID, "lights", strings.Join(commands, "\n"), "octodash", func() error {
+			return h.controlClient(printerID).SendCommands(commands...)
+		})
+		return err
+	}
+	return lights.New(configured, gcode, h.clock.Now, h.logger), nil
+}
+
+// updateLights passes the latest statuses to the status lights
+func (h *Handler) updateLights(printers []*models.PrinterStatus) {
+	if h.lights != nil {
+		h.lights.Update(printers)
+	}
+}
+
+// handleLights reports what each status light is showing
+func (h *Handler) handleLights(w http.ResponseWriter, r *http.Request) {
+	list := []models.LightStatus{}
+	if h.lights != nil {
+		list = h.lights.List()
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "ok",
+		"lights": list,
+	})
+}
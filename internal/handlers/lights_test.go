@@ -0,0 +1,69 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestStatusLights(t *testing.T) {
+	t.Setenv("LIGHT_1_PRINTER", "printer-1")
+	t.Setenv("LIGHT_MAPPING", "error=red:pulse,printing=blue,idle=green")
+	op := testutil.NewOctoPrint(t)
+	h := newTestHandler(t, testutil.NewSpoolman(t), testutil.Printer{Name: "Mini", Server: op})
+
+	// waitCommand polls until the printer has been sent cmd
+	waitCommand := func(cmd string) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for !strings.Contains(strings.Join(op.Commands(), "\n"), cmd) {
+			if time.Now().After(deadline) {
+				t.Fatalf("printer never sent %q; got %v", cmd, op.Commands())
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	getStatus(t, h)
+	waitCommand("M150 R0 U255 B0 P255")
+
+	op.SetPrinting("gear.gcode", 10, 600)
+	getStatus(t, h)
+	waitCommand("M150 R0 U0 B255 P255")
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/api/lights", nil))
+	var response struct {
+		Lights []models.LightStatus `json:"lights"`
+	}
+	json.NewDecoder(rec.Body).Decode(&response)
+	if len(response.Lights) != 1 {
+		t.Fatalf("GET /api/lights = %d %s", rec.Code, rec.Body)
+	}
+	if l := response.Lights[0]; l.ID != "light-1" || l.State != models.LightPrinting || l.Color != "#0000ff" || !l.Applied {
+		t.Errorf("light = %+v, want printing in blue", l)
+	}
+}
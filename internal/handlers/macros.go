@@ -0,0 +1,158 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/wmarchesi123/go-3dprint-client/config"
+	"github.com/wmarchesi123/octodash/internal/auth"
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/settings"
+)
+
+// macro is a configured gcode macro with its role resolved
+type macro struct {
+	settings.MacroSettings
+	role auth.Role
+}
+
+// newMacros checks the configured macros' roles and printers
+func newMacros(cfg *config.Config, configured []settings.MacroSettings) ([]macro, error) {
+	macros := make([]macro, 0, len(configured))
+	for _, m := range configured {
+		role, err := auth.ParseRole(m.Role)
+		if err != nil {
+			return nil, fmt.Errorf("macro %s: %w", m.Name, err)
+		}
+		for _, id := range m.Printers {
+			if !slices.ContainsFunc(cfg.Printers, func(p config.Printer) bool { return p.ID == id }) {
+				return nil, fmt.Errorf("macro %s: unknown printer %q", m.Name, id)
+			}
+		}
+		macros = append(macros, macro{MacroSettings: m, role: role})
+	}
+	return macros, nil
+}
+
+// offered reports whether the macro is available on a printer
+func (m macro) offered(printerID string) bool {
+	return len(m.Printers) == 0 || slices.Contains(m.Printers, printerID)
+}
+
+func (m macro) info() models.Macro {
+	return models.Macro{ID: m.ID, Name: m.Name, Role: m.role.String(), Confirm: m.Confirm}
+}
+
+// handlePrinterMacros lists the macros offered on a printer
+func (h *Handler) handlePrinterMacros(w http.ResponseWriter, r *http.Request) {
+	printer, ok := h.findPrinter(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Printer not found")
+		return
+	}
+
+	list := []models.Macro{}
+	for _, m := range h.macros {
+		if m.offered(printer.ID) {
+			list = append(list, m.info())
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "ok",
+		"macros": list,
+	})
+}
+
+// handleMacroRun sends a macro's gcode to a printer. Macros flagged for
+// confirmation use the same two-step token flow as the emergency stop.
+func (h *Handler) handleMacroRun(w http.ResponseWriter, r *http.Request) {
+	printer, ok := h.findPrinter(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Printer not found")
+		return
+	}
+
+	i := slices.IndexFunc(h.macros, func(m macro) bool { return m.ID == r.PathValue("macro") && m.offered(printer.ID) })
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "Macro not found")
+		return
+	}
+	m := h.macros[i]
+
+	if isDryRun(r) {
+		h.dryRunPrinters(w, r, "macro "+m.ID, m.role, []config.Printer{printer}, func(_ config.Printer, _ PrinterClient, c ControlClient) error {
+			return c.SendCommands(m.Gcode...)
+		})
+		return
+	}
+
+	user := auth.UserFromContext(r.Context())
+	if !user.Allowed(m.role, printer.ID) {
+		writeError(w, http.StatusForbidden, "Insufficient permissions")
+		return
+	}
+
+	if m.Confirm {
+		var req struct {
+			ConfirmToken string `json:"confirm_token"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+			writeError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+
+		target := "macro:" + printer.ID + ":" + m.ID
+		if req.ConfirmToken == "" {
+			token, expires := h.confirmations.issue(target, user.Name)
+			writeJSON(w, http.StatusAccepted, map[string]interface{}{
+				"status":        "confirm",
+				"confirm_token": token,
+				"expires_at":    expires,
+			})
+			return
+		}
+		if !h.confirmations.consume(req.ConfirmToken, target, user.Name) {
+			writeError(w, http.StatusConflict, "Confirmation token invalid or expired")
+			return
+		}
+	}
+
+	h.logger.Printf("Macro %q requested by %s for %s", m.Name, user.Name, printer.Name)
+
+	code, cmd, err := h.awaitCommand(w, r, h.commands.submit(printer.ID, "macro", strings.Join(m.Gcode, "\n"), user.Name, func() error {
+		return h.controlClient(printer.ID).SendCommands(m.Gcode...)
+	}))
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+
+	writeJSON(w, code, map[string]interface{}{
+		"status":  "ok",
+		"macro":   m.info(),
+		"command": cmd,
+	})
+}
@@ -0,0 +1,84 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestMacros(t *testing.T) {
+	t.Setenv("AUTH_USERS", "vic:viewer:vic-token,olga:operator:olga-token,ada:admin:ada-token")
+	t.Setenv("MACRO_1_NAME", "Park")
+	t.Setenv("MACRO_1_GCODE", "G27")
+	t.Setenv("MACRO_2_NAME", "Clean nozzle")
+	t.Setenv("MACRO_2_GCODE", "G28, CLEAN_NOZZLE")
+	t.Setenv("MACRO_2_PRINTERS", "printer-2")
+	t.Setenv("MACRO_2_ROLE", "admin")
+	t.Setenv("MACRO_2_CONFIRM", "true")
+	mini, voron := testutil.NewOctoPrint(t), testutil.NewOctoPrint(t)
+	h := newTestHandler(t, testutil.NewSpoolman(t),
+		testutil.Printer{Name: "Mini", Server: mini},
+		testutil.Printer{Name: "Voron", Server: voron})
+
+	var listed struct {
+		Macros []models.Macro `json:"macros"`
+	}
+	json.NewDecoder(doAs(h, "vic-token", "GET", "/api/printers/printer-1/macros", "").Body).Decode(&listed)
+	if len(listed.Macros) != 1 || listed.Macros[0].Name != "Park" {
+		t.Errorf("printer-1 macros = %+v, want only Park", listed.Macros)
+	}
+
+	if rec := doAs(h, "vic-token", "POST", "/api/printers/printer-1/macros/macro-1", ""); rec.Code != http.StatusForbidden {
+		t.Errorf("viewer run = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+	if rec := doAs(h, "olga-token", "POST", "/api/printers/printer-1/macros/macro-1", ""); rec.Code != http.StatusOK {
+		t.Fatalf("operator run = %d: %s", rec.Code, rec.Body)
+	}
+	if cmds := mini.Commands(); len(cmds) != 1 || !strings.Contains(cmds[0], `"G27"`) {
+		t.Errorf("commands = %v", cmds)
+	}
+	if rec := doAs(h, "ada-token", "POST", "/api/printers/printer-1/macros/macro-2", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("macro on a printer it isn't offered on = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+
+	// The confirm flag makes the first request hand out a token instead
+	rec := doAs(h, "ada-token", "POST", "/api/printers/printer-2/macros/macro-2", "")
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("first confirmed run = %d", rec.Code)
+	}
+	if len(voron.Commands()) != 0 {
+		t.Fatalf("macro sent before confirmation: %v", voron.Commands())
+	}
+	var pending struct {
+		Token string `json:"confirm_token"`
+	}
+	json.NewDecoder(rec.Body).Decode(&pending)
+	if rec := doAs(h, "ada-token", "POST", "/api/printers/printer-2/macros/macro-2", `{"confirm_token": "`+pending.Token+`"}`); rec.Code != http.StatusOK {
+		t.Fatalf("confirmed run = %d: %s", rec.Code, rec.Body)
+	}
+	if cmds := voron.Commands(); len(cmds) != 1 || !strings.Contains(cmds[0], `"G28","CLEAN_NOZZLE"`) {
+		t.Errorf("commands = %v", cmds)
+	}
+}
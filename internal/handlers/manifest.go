@@ -0,0 +1,439 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/wmarchesi123/octodash/internal/auth"
+	"github.com/wmarchesi123/octodash/internal/manifest"
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/profiles"
+)
+
+// maxManifestSize caps an imported YAML document
+const maxManifestSize = 4 << 20
+
+// handleExport writes the farm's configuration as YAML, limited to the
+// ?sections= listed (printers, profiles, queue; all by default)
+func (h *Handler) handleExport(w http.ResponseWriter, r *http.Request) {
+	sections, err := manifestSections(r.URL.Query().Get("sections"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var doc manifest.Document
+	if slices.Contains(sections, manifest.SectionPrinters) {
+		printers := h.exportPrinters()
+		doc.Printers = &printers
+	}
+	if slices.Contains(sections, manifest.SectionProfiles) {
+		list, err := h.profiles.List()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		exported := make([]manifest.Profile, 0, len(list))
+		for _, p := range list {
+			exported = append(exported, manifest.Profile{Name: p.Name, Material: p.Material, Nozzle: p.Nozzle, Quality: p.Quality, Slicer: p.Slicer, Tags: p.Tags})
+		}
+		doc.Profiles = &exported
+	}
+	if slices.Contains(sections, manifest.SectionQueue) {
+		entries, err := h.queue.List()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		jobs := []manifest.Job{}
+		for _, e := range entries {
+			if portableEntry(e) {
+				jobs = append(jobs, manifest.Job{ID: e.ID, File: e.File, Printer: e.PrinterID, PrintProfile: e.PrintProfile, Team: e.Team, Priority: e.Priority, NotBefore: e.NotBefore, OffPeak: e.OffPeak, SubmittedBy: e.SubmittedBy})
+			}
+		}
+		doc.Queue = &jobs
+	}
+
+	data, err := manifest.Marshal(doc)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/yaml; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="octodash.yaml"`)
+	w.Write(data)
+}
+
+func manifestSections(v string) ([]string, error) {
+	if v == "" {
+		return manifest.Sections, nil
+	}
+	var sections []string
+	for _, s := range strings.Split(v, ",") {
+		s = strings.TrimSpace(s)
+		if !slices.Contains(manifest.Sections, s) {
+			return nil, fmt.Errorf("unknown section %q, expected %s", s, strings.Join(manifest.Sections, ", "))
+		}
+		sections = append(sections, s)
+	}
+	return sections, nil
+}
+
+func (h *Handler) exportPrinters() []manifest.Printer {
+	printers := make([]manifest.Printer, 0, len(h.config.Printers))
+	for _, p := range h.config.Printers {
+		ps := h.settings.Printers[p.ID]
+		printers = append(printers, manifest.Printer{ID: p.ID, Name: p.Name, URL: p.OctoPrintURL, Group: ps.Group, Tags: ps.Tags, AutoClear: ps.AutoClear, Continuous: ps.Continuous})
+	}
+	return printers
+}
+
+// portableEntry reports whether a queue entry belongs in an exported
+// document: one still waiting on a plain file, as uploaded models and sliced
+// gcode live in this instance's data directory
+func portableEntry(e models.QueueEntry) bool {
+	return e.Status == models.QueueQueued && e.Model == "" && e.Gcode == ""
+}
+
+// importPlan is a validated document, ready to apply
+type importPlan struct {
+	profiles []models.PrintProfile
+	queue    []models.QueueEntry
+	warnings []string
+}
+
+// handleImport applies a YAML document from handleExport. The whole document
+// is checked before anything changes. Profiles are created or replaced and
+// queue jobs added, or updated when their ID matches a waiting entry, then
+// put in the document's order. With ?prune=1, profiles and waiting entries
+// missing from a section are removed. Printers are only compared. With
+// ?dry_run=1 the changes are listed but not made.
+func (h *Handler) handleImport(w http.ResponseWriter, r *http.Request) {
+	doc, err := manifest.Parse(http.MaxBytesReader(w, r.Body, maxManifestSize))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid document: "+err.Error())
+		return
+	}
+	prune := r.URL.Query().Get("prune") == "1"
+
+	h.queueMu.Lock()
+	defer h.queueMu.Unlock()
+
+	plan, err := h.planImport(r, doc, prune)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	summary, changes, err := h.applyImport(plan, doc, prune, isDryRun(r))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if isDryRun(r) {
+		writeDryRun(w, r, "import", 0, changes)
+		return
+	}
+	h.audit(r, "config.import", "", fmt.Sprintf("%d profiles, %d queue entries", len(plan.profiles), len(plan.queue)))
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":   "ok",
+		"profiles": summary["profiles"],
+		"queue":    summary["queue"],
+		"warnings": plan.warnings,
+	})
+}
+
+// planImport validates a document against the farm as it is now
+func (h *Handler) planImport(r *http.Request, doc manifest.Document, prune bool) (importPlan, error) {
+	plan := importPlan{warnings: []string{}}
+
+	if doc.Printers != nil {
+		plan.warnings = h.printerDrift(*doc.Printers)
+	}
+
+	// Queue jobs are checked against the profiles they will see after import
+	library := make(map[string]models.PrintProfile)
+	existing, err := h.profiles.List()
+	if err != nil {
+		return plan, err
+	}
+	for _, p := range existing {
+		library[p.Name] = p
+	}
+	if doc.Profiles != nil {
+		if prune {
+			library = make(map[string]models.PrintProfile)
+		}
+		listed := make(map[string]bool)
+		for _, p := range *doc.Profiles {
+			if !profiles.ValidName(p.Name) {
+				return plan, fmt.Errorf("profile %q: %v", p.Name, profiles.ErrInvalidName)
+			}
+			if listed[p.Name] {
+				return plan, fmt.Errorf("profile %q is listed twice", p.Name)
+			}
+			listed[p.Name] = true
+			if p.Nozzle < 0 {
+				return plan, fmt.Errorf("profile %q: nozzle must be positive", p.Name)
+			}
+			if p.Slicer != "" && !h.slicer.HasProfile(p.Slicer) {
+				return plan, fmt.Errorf("profile %q: unknown slicer profile %s", p.Name, p.Slicer)
+			}
+			profile := models.PrintProfile{Name: p.Name, Material: p.Material, Nozzle: p.Nozzle, Quality: p.Quality, Slicer: p.Slicer, Tags: make(map[string]string, len(p.Tags))}
+			for key, value := range p.Tags {
+				profile.Tags[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+			}
+			library[p.Name] = profile
+			plan.profiles = append(plan.profiles, profile)
+		}
+	}
+
+	if doc.Queue == nil {
+		return plan, h.checkProfilesInUse(library, nil)
+	}
+
+	seen := make(map[string]bool)
+	for i, job := range *doc.Queue {
+		where := fmt.Sprintf("queue job %d (%s)", i+1, job.File)
+		entry := models.QueueEntry{ID: job.ID, SubmittedBy: job.SubmittedBy, Approval: h.approvalFor(r)}
+		if job.ID != "" {
+			if seen[job.ID] {
+				return plan, fmt.Errorf("%s: entry %s is listed twice", where, job.ID)
+			}
+			seen[job.ID] = true
+			current, err := h.queue.Get(job.ID)
+			if err != nil || !portableEntry(current) {
+				return plan, fmt.Errorf("%s: no waiting entry %s", where, job.ID)
+			}
+			entry = current
+		}
+
+		entry.File = strings.TrimSpace(job.File)
+		if entry.File == "" {
+			return plan, fmt.Errorf("%s: file is required", where)
+		}
+		if job.Printer != "" {
+			if _, ok := h.findPrinter(job.Printer); !ok {
+				return plan, fmt.Errorf("%s: unknown printer %s", where, job.Printer)
+			}
+		}
+		if err := h.checkPriority(r, job.Priority); err != nil {
+			return plan, fmt.Errorf("%s: %v", where, err)
+		}
+		if job.PrintProfile != "" {
+			profile, ok := library[job.PrintProfile]
+			if !ok {
+				return plan, fmt.Errorf("%s: unknown print profile %q", where, job.PrintProfile)
+			}
+			if job.Printer != "" && !h.profileFits(profile, job.Printer, nil) {
+				return plan, fmt.Errorf("%s: %s does not fit print profile %q", where, job.Printer, job.PrintProfile)
+			}
+		}
+		team, err := h.entryTeam(r, job.Team, job.Printer)
+		if err != nil {
+			return plan, fmt.Errorf("%s: %v", where, err)
+		}
+
+		entry.PrinterID = job.Printer
+		entry.PrintProfile = job.PrintProfile
+		entry.Team = team
+		entry.Priority = job.Priority
+		entry.NotBefore = job.NotBefore
+		entry.OffPeak = job.OffPeak
+		if entry.SubmittedBy == "" {
+			entry.SubmittedBy = auth.UserFromContext(r.Context()).Name
+		}
+		plan.queue = append(plan.queue, entry)
+	}
+	return plan, h.checkProfilesInUse(library, seen)
+}
+
+// checkProfilesInUse makes sure pruning profiles leaves none of the entries
+// that stay behind without theirs. kept lists the waiting entries the
+// document keeps, or is nil when the queue is left alone.
+func (h *Handler) checkProfilesInUse(library map[string]models.PrintProfile, kept map[string]bool) error {
+	entries, err := h.queue.List()
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.PrintProfile == "" || e.Status == models.QueueDispatched {
+			continue
+		}
+		if kept != nil && portableEntry(e) && !kept[e.ID] {
+			continue
+		}
+		if _, ok := library[e.PrintProfile]; !ok {
+			return fmt.Errorf("profile %q is used by queue entry %s", e.PrintProfile, e.ID)
+		}
+	}
+	return nil
+}
+
+// printerDrift describes how a document's printers differ from the ones configured
+func (h *Handler) printerDrift(printers []manifest.Printer) []string {
+	warnings := []string{}
+	configured := make(map[string]manifest.Printer)
+	for _, p := range h.exportPrinters() {
+		configured[p.ID] = p
+	}
+
+	listed := make(map[string]bool)
+	for _, p := range printers {
+		listed[p.ID] = true
+		current, ok := configured[p.ID]
+		switch {
+		case !ok:
+			warnings = append(warnings, fmt.Sprintf("printer %s is not configured", p.ID))
+		case p.Name != current.Name || p.URL != current.URL || p.Group != current.Group:
+			warnings = append(warnings, fmt.Sprintf("printer %s differs from its PRINTER_N_* settings", p.ID))
+		case !mapsEqual(p.Tags, current.Tags) || p.AutoClear != current.AutoClear || p.Continuous != current.Continuous:
+			warnings = append(warnings, fmt.Sprintf("printer %s differs from its PRINTER_N_* settings", p.ID))
+		}
+	}
+	for _, p := range h.config.Printers {
+		if !listed[p.ID] {
+			warnings = append(warnings, fmt.Sprintf("printer %s is missing from the document", p.ID))
+		}
+	}
+	return warnings
+}
+
+func mapsEqual(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if b[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// applyImport makes the changes a plan describes, counting them per section
+// and describing each one. A dry run only counts and describes them.
+func (h *Handler) applyImport(plan importPlan, doc manifest.Document, prune, dryRun bool) (map[string]map[string]int, []models.DryRunTarget, error) {
+	summary := map[string]map[string]int{
+		"profiles": {"created": 0, "updated": 0, "deleted": 0},
+		"queue":    {"added": 0, "updated": 0, "removed": 0},
+	}
+	changes := []models.DryRunTarget{}
+	change := func(section, kind, what string) {
+		summary[section][kind]++
+		changes = append(changes, models.DryRunTarget{Change: kind + " " + what})
+	}
+	now := h.clock.Now()
+
+	if doc.Profiles != nil {
+		keep := make(map[string]bool)
+		for _, p := range plan.profiles {
+			keep[p.Name] = true
+			_, getErr := h.profiles.Get(p.Name)
+			if getErr != nil && !errors.Is(getErr, profiles.ErrNotFound) {
+				return summary, changes, getErr
+			}
+			if !dryRun {
+				if _, err := h.profiles.Put(p, now); err != nil {
+					return summary, changes, err
+				}
+			}
+			if getErr == nil {
+				change("profiles", "updated", "profile "+p.Name)
+			} else {
+				change("profiles", "created", "profile "+p.Name)
+			}
+		}
+		if prune {
+			existing, err := h.profiles.List()
+			if err != nil {
+				return summary, changes, err
+			}
+			for _, p := range existing {
+				if keep[p.Name] {
+					continue
+				}
+				if !dryRun {
+					if err := h.profiles.Delete(p.Name); err != nil {
+						return summary, changes, err
+					}
+				}
+				change("profiles", "deleted", "profile "+p.Name)
+			}
+		}
+	}
+
+	if doc.Queue == nil {
+		return summary, changes, nil
+	}
+
+	ids := make([]string, 0, len(plan.queue))
+	keep := make(map[string]bool)
+	for _, entry := range plan.queue {
+		switch {
+		case entry.ID != "":
+			if !dryRun {
+				if err := h.queue.Update(entry); err != nil {
+					return summary, changes, err
+				}
+			}
+			change("queue", "updated", "queue entry "+entry.ID+" ("+entry.File+")")
+		case dryRun:
+			change("queue", "added", "queue entry for "+entry.File)
+		default:
+			added, err := h.queue.Add(entry)
+			if err != nil {
+				return summary, changes, err
+			}
+			entry = added
+			change("queue", "added", "queue entry "+entry.ID+" ("+entry.File+")")
+		}
+		ids = append(ids, entry.ID)
+		keep[entry.ID] = true
+	}
+
+	if prune {
+		entries, err := h.queue.List()
+		if err != nil {
+			return summary, changes, err
+		}
+		for _, e := range entries {
+			if !portableEntry(e) || keep[e.ID] {
+				continue
+			}
+			if !dryRun {
+				if err := h.queue.Remove(e.ID); err != nil {
+					return summary, changes, err
+				}
+			}
+			change("queue", "removed", "queue entry "+e.ID+" ("+e.File+")")
+		}
+	}
+	if dryRun {
+		return summary, changes, nil
+	}
+	return summary, changes, h.queue.Reorder(ids)
+}
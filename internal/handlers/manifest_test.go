@@ -0,0 +1,144 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestManifestExportImport(t *testing.T) {
+	t.Setenv("PRINTER_1_TAGS", "nozzle=0.4")
+	h := newTestHandler(t, testutil.NewSpoolman(t), testutil.Printer{Name: "MK3", Server: testutil.NewOctoPrint(t)})
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return rec
+	}
+	queue := func() []models.QueueEntry {
+		var response struct {
+			Queue []models.QueueEntry `json:"queue"`
+		}
+		json.NewDecoder(do("GET", "/api/queue", "").Body).Decode(&response)
+		return response.Queue
+	}
+
+	do("PUT", "/api/profiles/pla-04", `{"material": "PLA", "nozzle": 0.4}`)
+	do("PUT", "/api/profiles/old", `{"material": "ABS"}`)
+	do("POST", "/api/queue", `{"file": "bracket.gcode", "print_profile": "pla-04"}`)
+	do("POST", "/api/queue", `{"file": "gear.gcode", "printer_id": "printer-1"}`)
+	do("POST", "/api/queue", `{"file": "spare.gcode"}`)
+
+	rec := do("GET", "/api/admin/export", "")
+	exported := rec.Body.String()
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "text/yaml; charset=utf-8" {
+		t.Fatalf("export = %d %s: %s", rec.Code, rec.Header().Get("Content-Type"), exported)
+	}
+	for _, want := range []string{"version: 1", "id: printer-1", "nozzle: \"0.4\"", "name: pla-04", "file: gear.gcode", "id: \"0000000001\""} {
+		if !strings.Contains(exported, want) {
+			t.Errorf("export is missing %q:\n%s", want, exported)
+		}
+	}
+	if strings.Contains(exported, "api_key") || strings.Contains(exported, "key:") {
+		t.Errorf("export includes API keys:\n%s", exported)
+	}
+
+	// Nothing changes when any part of a document is wrong
+	bad := `version: 1
+profiles:
+  - name: petg-06
+queue:
+  - file: gear.gcode
+    print_profile: missing
+`
+	if rec := do("POST", "/api/admin/import", bad); rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "unknown print profile") {
+		t.Errorf("invalid import = %d %s, want %d", rec.Code, rec.Body, http.StatusBadRequest)
+	}
+	if rec := do("GET", "/api/profiles/petg-06", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("profile from rejected import = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	if rec := do("POST", "/api/admin/import", "version: 1\nqueue:\n  - fil: typo.gcode\n"); rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown field = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+
+	doc := `version: 1
+printers:
+  - id: printer-1
+    name: Renamed
+    url: http://example.invalid
+profiles:
+  - name: pla-04
+    material: PLA
+    nozzle: 0.4
+  - name: petg-04
+    material: PETG
+    tags: {Nozzle: "0.4"}
+queue:
+  - id: "0000000002"
+    file: gear.gcode
+    printer: printer-1
+    print_profile: petg-04
+  - file: clip.gcode
+    priority: high
+  - id: "0000000001"
+    file: bracket.gcode
+    print_profile: pla-04
+`
+	rec = do("POST", "/api/admin/import?prune=1", doc)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("import = %d: %s", rec.Code, rec.Body)
+	}
+	var summary struct {
+		Profiles map[string]int `json:"profiles"`
+		Queue    map[string]int `json:"queue"`
+		Warnings []string       `json:"warnings"`
+	}
+	json.NewDecoder(rec.Body).Decode(&summary)
+	if summary.Profiles["created"] != 1 || summary.Profiles["updated"] != 1 || summary.Profiles["deleted"] != 1 {
+		t.Errorf("profiles = %v, want petg-04 created, pla-04 updated and old deleted", summary.Profiles)
+	}
+	if summary.Queue["added"] != 1 || summary.Queue["updated"] != 2 || summary.Queue["removed"] != 1 {
+		t.Errorf("queue = %v, want clip added, two updated and spare removed", summary.Queue)
+	}
+	if len(summary.Warnings) != 1 || !strings.Contains(summary.Warnings[0], "printer-1") {
+		t.Errorf("warnings = %v, want printer-1 reported as drifted", summary.Warnings)
+	}
+
+	var files []string
+	for _, e := range queue() {
+		files = append(files, e.File)
+	}
+	if strings.Join(files, ",") != "clip.gcode,gear.gcode,bracket.gcode" {
+		t.Errorf("queue = %v, want the high priority job first, then the document's order", files)
+	}
+
+	// Exporting again round-trips the imported document
+	rec = do("POST", "/api/admin/import", do("GET", "/api/admin/export?sections=profiles,queue", "").Body.String())
+	json.NewDecoder(rec.Body).Decode(&summary)
+	if rec.Code != http.StatusOK || summary.Queue["added"] != 0 || summary.Queue["updated"] != 3 {
+		t.Errorf("re-import = %d %v, want every entry matched", rec.Code, summary.Queue)
+	}
+}
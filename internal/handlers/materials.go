@@ -0,0 +1,57 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// materialColor is the accent configured for a material, matching the
+// longest configured prefix so PETG-CF picks up PETG's color
+func (h *Handler) materialColor(material string) string {
+	material = strings.ToUpper(strings.TrimSpace(material))
+	best, color := 0, ""
+	for name, c := range h.settings.MaterialColors {
+		if len(name) > best && strings.HasPrefix(material, name) {
+			best, color = len(name), c
+		}
+	}
+	return color
+}
+
+// handleMaterialLegend lists the material accent colors for a wall legend
+func (h *Handler) handleMaterialLegend(w http.ResponseWriter, r *http.Request) {
+	type entry struct {
+		Material string `json:"material"`
+		Color    string `json:"color"`
+	}
+
+	legend := make([]entry, 0, len(h.settings.MaterialColors))
+	for material, color := range h.settings.MaterialColors {
+		legend = append(legend, entry{Material: material, Color: color})
+	}
+	sort.Slice(legend, func(i, j int) bool { return legend[i].Material < legend[j].Material })
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "ok",
+		"legend": legend,
+	})
+}
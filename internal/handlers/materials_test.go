@@ -0,0 +1,62 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestMaterialColors(t *testing.T) {
+	t.Setenv("MATERIAL_COLORS", "petg=#2196F3, PETG-CF=#333333, PLA=#4caf50")
+	sm := testutil.NewSpoolman(t,
+		testutil.Spool(1, "Prusament", "PETG", "ff0000", 700),
+		testutil.Spool(2, "Bambu", "PETG-CF", "000000", 700),
+		testutil.Spool(3, "Fillamentum", "TPU", "ffffff", 700),
+	)
+	var printers []testutil.Printer
+	for _, id := range []string{"1", "2", "3"} {
+		op := testutil.NewOctoPrint(t)
+		op.SetSpool(id)
+		printers = append(printers, testutil.Printer{Name: "P" + id, Server: op})
+	}
+	h := newTestHandler(t, sm, printers...)
+	got := getStatus(t, h)
+
+	for id, want := range map[string]interface{}{"printer-1": "#2196f3", "printer-2": "#333333", "printer-3": nil} {
+		if c := got[id].CurrentSpool["material_color"]; c != want {
+			t.Errorf("%s material color = %v, want %v", id, c, want)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/api/materials/legend", nil))
+	var response struct {
+		Legend []struct{ Material, Color string } `json:"legend"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Legend) != 3 || response.Legend[0].Material != "PETG" || response.Legend[2].Color != "#4caf50" {
+		t.Errorf("legend = %+v, want PETG, PETG-CF, PLA in order", response.Legend)
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/wmarchesi123/go-3dprint-client/config"
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/version"
+)
+
+// statusMeta tracks the metadata clients use to spot stale or skipped status updates
+type statusMeta struct {
+	server   models.ServerInfo
+	sequence atomic.Uint64
+	lastPoll atomic.Int64
+
+	mu         sync.Mutex
+	lastSeen   map[string]time.Time
+	lastError  map[string]errorRecord
+	lastFetch  map[string]time.Duration
+	lastPolled map[string]time.Time
+	polls      pollTiming
+}
+
+// errorRecord is the most recent failure contacting a printer
+type errorRecord struct {
+	message string
+	at      time.Time
+}
+
+// pollTiming accumulates how long collecting every printer's status takes
+type pollTiming struct {
+	count uint64
+	last  time.Duration
+	max   time.Duration
+	total time.Duration
+}
+
+func newStatusMeta(startedAt time.Time) *statusMeta {
+	return &statusMeta{
+		server:     models.ServerInfo{Version: version.String(), StartedAt: startedAt.UTC().Truncate(time.Second)},
+		lastSeen:   make(map[string]time.Time),
+		lastError:  make(map[string]errorRecord),
+		lastFetch:  make(map[string]time.Duration),
+		lastPolled: make(map[string]time.Time),
+	}
+}
+
+// next returns the sequence number for a new poll
+func (m *statusMeta) next() uint64 {
+	return m.sequence.Add(1)
+}
+
+// polled records that the given printers' statuses were just collected
+func (m *statusMeta) polled(printers []*models.PrinterStatus, at time.Time) {
+	m.lastPoll.Store(at.UnixNano())
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, p := range printers {
+		m.lastPolled[p.ID] = at
+	}
+}
+
+// unpolled returns the printers whose statuses weren't collected within
+// interval, so a poll of some printers doesn't hold back the rest
+func (m *statusMeta) unpolled(configured []config.Printer, now time.Time, interval time.Duration) []config.Printer {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var stale []config.Printer
+	for _, p := range configured {
+		if now.Sub(m.lastPolled[p.ID]) >= interval {
+			stale = append(stale, p)
+		}
+	}
+	return stale
+}
+
+// seen records a successful contact with a printer
+func (m *statusMeta) seen(printerID string, at time.Time) {
+	m.mu.Lock()
+	m.lastSeen[printerID] = at
+	m.mu.Unlock()
+}
+
+// failed records an unsuccessful contact with a printer
+func (m *statusMeta) failed(printerID, message string, at time.Time) {
+	m.mu.Lock()
+	m.lastError[printerID] = errorRecord{message: message, at: at}
+	m.mu.Unlock()
+}
+
+// fetched records how long fetching one printer's status took
+func (m *statusMeta) fetched(printerID string, d time.Duration) {
+	m.mu.Lock()
+	m.lastFetch[printerID] = d
+	m.mu.Unlock()
+}
+
+// timed records how long a whole poll took
+func (m *statusMeta) timed(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.polls.count++
+	m.polls.last = d
+	m.polls.total += d
+	m.polls.max = max(m.polls.max, d)
+}
+
+// pollerStats summarizes poll timing for diagnostics
+func (m *statusMeta) pollerStats() models.PollerStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := models.PollerStats{
+		Polls:          m.polls.count,
+		LastDurationMS: milliseconds(m.polls.last),
+		MaxDurationMS:  milliseconds(m.polls.max),
+	}
+	if m.polls.count > 0 {
+		stats.AvgDurationMS = milliseconds(m.polls.total / time.Duration(m.polls.count))
+		lastPoll := time.Unix(0, m.lastPoll.Load()).UTC().Truncate(time.Second)
+		stats.LastPoll = &lastPoll
+	}
+	return stats
+}
+
+// printerHealth reports when a printer last answered, last failed and how long it last took
+func (m *statusMeta) printerHealth(printerID, name string) models.PrinterHealth {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	health := models.PrinterHealth{PrinterID: printerID, Name: name}
+	if at, ok := m.lastSeen[printerID]; ok {
+		at = at.Truncate(time.Second)
+		health.LastSeen = &at
+	}
+	if rec, ok := m.lastError[printerID]; ok {
+		at := rec.at.Truncate(time.Second)
+		health.LastError = rec.message
+		health.LastErrorAt = &at
+	}
+	if d, ok := m.lastFetch[printerID]; ok {
+		health.LastFetchMS = milliseconds(d)
+	}
+	return health
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// annotate sets when each printer last answered and how old that data is,
+// storing the values in the slots so no per-printer allocation is needed
+func (m *statusMeta) annotate(slots []statusSlot, now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range slots {
+		slot := &slots[i]
+		at, ok := m.lastSeen[slot.status.ID]
+		if !ok {
+			continue
+		}
+		slot.lastSeen = at.Truncate(time.Second)
+		slot.dataAge = int(now.Sub(at).Seconds())
+		slot.status.LastSeen = &slot.lastSeen
+		slot.status.DataAge = &slot.dataAge
+	}
+}
@@ -0,0 +1,312 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/wmarchesi123/go-3dprint-client/config"
+	"github.com/wmarchesi123/go-3dprint-client/octoprint"
+	"github.com/wmarchesi123/octodash/internal/auth"
+	"github.com/wmarchesi123/octodash/internal/gcode"
+	"github.com/wmarchesi123/octodash/internal/models"
+)
+
+// plate is the job a printer is running, with the objects labelled in its file
+type plate struct {
+	file      string
+	date      int64
+	filepos   int64
+	objects   []gcode.Object
+	cancelled map[int]bool
+}
+
+// plateTracker remembers each printer's plate so its file is read once per
+// job, along with the objects cancelled since the job started
+type plateTracker struct {
+	mu     sync.Mutex
+	plates map[string]*plate
+}
+
+func newPlateTracker() *plateTracker {
+	return &plateTracker{plates: make(map[string]*plate)}
+}
+
+// lookup returns the printer's plate if it is still running the same job.
+// A file position going backwards means the file was started again.
+func (t *plateTracker) lookup(id string, file octoprint.JobFile, filepos int64) (plate, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p, ok := t.plates[id]
+	if !ok || p.file != file.Path || p.date != file.Date || filepos < p.filepos {
+		return plate{}, false
+	}
+	p.filepos = filepos
+	return p.snapshot(), true
+}
+
+// set records a newly started job's plate
+func (t *plateTracker) set(id string, p plate) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.plates[id] = &p
+}
+
+// drop forgets a printer's plate once its job has ended
+func (t *plateTracker) drop(id string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.plates, id)
+}
+
+// cancel marks an object on the printer's plate as cancelled
+func (t *plateTracker) cancel(id string, object int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if p, ok := t.plates[id]; ok {
+		p.cancelled[object] = true
+	}
+}
+
+func (p *plate) snapshot() plate {
+	c := *p
+	c.cancelled = make(map[int]bool, len(p.cancelled))
+	for id := range p.cancelled {
+		c.cancelled[id] = true
+	}
+	return c
+}
+
+func (p plate) object(id int) gcode.Object {
+	for _, o := range p.objects {
+		if o.ID == id {
+			return o
+		}
+	}
+	return gcode.Object{ID: id}
+}
+
+// jobRunning reports whether OctoPrint has a job underway, paused or not
+func jobRunning(job *octoprint.JobResponse) bool {
+	return job.Job.File.Path != "" &&
+		(strings.HasPrefix(job.State, "Printing") || strings.HasPrefix(job.State, "Paus") || job.State == "Resuming")
+}
+
+// currentPlate returns the plate a printer is running, reading the objects
+// from its file the first time the job is seen. ok is false when idle.
+func (h *Handler) currentPlate(ctx context.Context, printerID string) (p plate, ok bool, err error) {
+	job, err := h.printerClient(printerID).GetJob()
+	if err != nil {
+		return plate{}, false, err
+	}
+	if !jobRunning(job) {
+		h.plates.drop(printerID)
+		return plate{}, false, nil
+	}
+
+	file := job.Job.File
+	if p, ok := h.plates.lookup(printerID, file, job.Progress.Filepos); ok {
+		return p, true, nil
+	}
+
+	// Scan the file as it downloads rather than holding it all in memory
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := h.controlClient(printerID).DownloadFile(ctx, file.Path, pw)
+		pw.CloseWithError(err)
+	}()
+	objects, err := gcode.Objects(pr)
+	pr.Close()
+	if err != nil {
+		return plate{}, false, err
+	}
+
+	p = plate{
+		file:      file.Path,
+		date:      file.Date,
+		filepos:   job.Progress.Filepos,
+		objects:   objects,
+		cancelled: make(map[int]bool),
+	}
+	h.plates.set(printerID, p)
+	return p, true, nil
+}
+
+// plateObjects reports how far through each object the job has got
+func plateObjects(p plate) []models.PlateObject {
+	list := []models.PlateObject{}
+	for _, o := range p.objects {
+		size, printed := o.Size(), o.Printed(p.filepos)
+		obj := models.PlateObject{ID: o.ID, Name: o.Name, State: models.ObjectPending}
+		if size > 0 {
+			obj.Completion = math.Round(float64(printed)/float64(size)*1000) / 10
+		}
+
+		switch {
+		case p.cancelled[o.ID]:
+			obj.State = models.ObjectCancelled
+		case printed == size:
+			obj.State = models.ObjectDone
+		case printed > 0:
+			obj.State = models.ObjectPrinting
+		}
+		list = append(list, obj)
+	}
+	return list
+}
+
+// handlePrinterObjects lists the objects labelled in the printer's current job
+func (h *Handler) handlePrinterObjects(w http.ResponseWriter, r *http.Request) {
+	printer, ok := h.findPrinter(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Printer not found")
+		return
+	}
+
+	p, running, err := h.currentPlate(r.Context(), printer.ID)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "ok",
+		"file":    p.file,
+		"running": running,
+		"objects": plateObjects(p),
+	})
+}
+
+// handleObjectCancel skips one object for the rest of the print, leaving the
+// rest of the plate running
+func (h *Handler) handleObjectCancel(w http.ResponseWriter, r *http.Request) {
+	printer, ok := h.findPrinter(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Printer not found")
+		return
+	}
+	objectID, err := strconv.Atoi(r.PathValue("object"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, "Object not found")
+		return
+	}
+
+	p, running, err := h.currentPlate(r.Context(), printer.ID)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	if !running {
+		writeError(w, http.StatusConflict, "No print in progress")
+		return
+	}
+
+	var object *models.PlateObject
+	objects := plateObjects(p)
+	for i := range objects {
+		if objects[i].ID == objectID {
+			object = &objects[i]
+		}
+	}
+	switch {
+	case object == nil:
+		writeError(w, http.StatusNotFound, "Object not found")
+		return
+	case object.State == models.ObjectCancelled:
+		writeError(w, http.StatusConflict, "Object already cancelled")
+		return
+	case object.State == models.ObjectDone:
+		writeError(w, http.StatusConflict, "Object already printed")
+		return
+	}
+
+	if isDryRun(r) {
+		h.dryRunPrinters(w, r, "cancel object "+object.Name, 0, []config.Printer{printer}, func(_ config.Printer, _ PrinterClient, c ControlClient) error {
+			_, err := h.cancelObject(c, p.object(objectID))
+			return err
+		})
+		return
+	}
+
+	user := auth.UserFromContext(r.Context())
+	h.logger.Printf("Cancel of object %q requested by %s for %s", object.Name, user.Name, printer.Name)
+
+	var method string
+	_, err = h.commands.run(printer.ID, "cancel-object", strconv.Itoa(objectID), user.Name, func() (err error) {
+		method, err = h.cancelObject(h.controlClient(printer.ID), p.object(objectID))
+		return err
+	})
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	h.plates.cancel(printer.ID, objectID)
+	object.State = models.ObjectCancelled
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "ok",
+		"object": object,
+		"method": method,
+	})
+}
+
+// regionMargin widens an object's bounds so the Exclude Region plugin also
+// catches the outside of its perimeters, in mm
+const regionMargin = 0.5
+
+// cancelObject skips one object using the best mechanism the printer has:
+// Klipper's exclude_object for files labelled for it, the Exclude Region
+// plugin when it is enabled and the object's footprint is known, and M486
+// otherwise. It returns the mechanism used.
+func (h *Handler) cancelObject(client ControlClient, o gcode.Object) (string, error) {
+	if o.Label != "" {
+		return "exclude_object", client.ExcludeObject(o.Label)
+	}
+
+	if o.Bounds.Known() && h.excludeRegionEnabled(client) {
+		b := o.Bounds
+		id := fmt.Sprintf("octodash-object-%d", o.ID)
+		err := client.ExcludeRegion(id, b.MinX-regionMargin, b.MinY-regionMargin, b.MaxX+regionMargin, b.MaxY+regionMargin)
+		return "exclude_region", err
+	}
+
+	return "m486", client.CancelObject(o.ID)
+}
+
+// excludeRegionEnabled reports whether the instance runs the Exclude Region plugin
+func (h *Handler) excludeRegionEnabled(client ControlClient) bool {
+	plugins, err := client.Plugins()
+	if err != nil {
+		return false
+	}
+	for _, p := range plugins {
+		if p.Key == "excluderegion" {
+			return p.Enabled
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/octoapi"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestPlateObjects(t *testing.T) {
+	op := testutil.NewOctoPrint(t)
+	h := newTestHandler(t, testutil.NewSpoolman(t), testutil.Printer{Name: "Mini", Server: op})
+
+	// Two objects printed one after the other, each half the file
+	body := strings.Repeat("G1 X1 Y1\n", 50)
+	op.SetFile("plate.gcode", []byte("M486 S0 A\"Gear\"\n"+body+"M486 S1 A\"Bracket\"\n"+body+"M486 S-1\n"))
+	op.SetPrinting("plate.gcode", 25, 1800)
+
+	list := func() []models.PlateObject {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", "/api/printers/printer-1/objects", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("objects = %d: %s", rec.Code, rec.Body)
+		}
+		var response struct {
+			Objects []models.PlateObject `json:"objects"`
+		}
+		json.NewDecoder(rec.Body).Decode(&response)
+		return response.Objects
+	}
+
+	objects := list()
+	if len(objects) != 2 || objects[0].State != models.ObjectPrinting || objects[1].State != models.ObjectPending {
+		t.Fatalf("objects = %+v", objects)
+	}
+	if c := objects[0].Completion; c < 40 || c > 60 {
+		t.Errorf("gear completion = %v, want about half", c)
+	}
+
+	if rec := postJSON(h, "/api/printers/printer-1/objects/7/cancel", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("cancel unknown object = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	if rec := postJSON(h, "/api/printers/printer-1/objects/1/cancel", ""); rec.Code != http.StatusOK {
+		t.Fatalf("cancel = %d: %s", rec.Code, rec.Body)
+	}
+	if cmds := op.Commands(); len(cmds) != 1 || !strings.Contains(cmds[0], "M486 P1") {
+		t.Errorf("commands = %v, want M486 P1", cmds)
+	}
+	if rec := postJSON(h, "/api/printers/printer-1/objects/1/cancel", ""); rec.Code != http.StatusConflict {
+		t.Errorf("second cancel = %d, want %d", rec.Code, http.StatusConflict)
+	}
+
+	op.SetPrinting("plate.gcode", 75, 900)
+	if objects := list(); objects[0].State != models.ObjectDone || objects[1].State != models.ObjectCancelled {
+		t.Errorf("later objects = %+v", objects)
+	}
+
+	op.SetIdle()
+	if objects := list(); len(objects) != 0 {
+		t.Errorf("idle objects = %+v", objects)
+	}
+	if rec := postJSON(h, "/api/printers/printer-1/objects/0/cancel", ""); rec.Code != http.StatusConflict {
+		t.Errorf("cancel while idle = %d, want %d", rec.Code, http.StatusConflict)
+	}
+	if n := op.Requests("GET /downloads/files/local/plate.gcode"); n != 1 {
+		t.Errorf("downloaded the file %d times, want once per job", n)
+	}
+}
+
+func TestObjectCancelMethods(t *testing.T) {
+	klipper := testutil.NewOctoPrint(t)
+	marlin := testutil.NewOctoPrint(t)
+	h := newTestHandler(t, testutil.NewSpoolman(t),
+		testutil.Printer{Name: "Voron", Server: klipper},
+		testutil.Printer{Name: "Ender", Server: marlin})
+
+	klipper.SetFile("plate.gcode", []byte("EXCLUDE_OBJECT_DEFINE NAME=gear CENTER=15,15\n"+
+		"EXCLUDE_OBJECT_START NAME=gear\nG1 X10 Y10 E1\nEXCLUDE_OBJECT_END NAME=gear\n"))
+	klipper.SetPrinting("plate.gcode", 0, 3600)
+
+	// Stock Marlin ignores M486, so the Exclude Region plugin does the skipping
+	marlin.SetPlugins(octoapi.Plugin{Key: "excluderegion", Name: "Exclude Region", Enabled: true})
+	marlin.SetFile("plate.gcode", []byte("M83\nM486 S0\nG1 X10 Y10\nG1 X20 Y30 E1\nM486 S-1\n"))
+	marlin.SetPrinting("plate.gcode", 0, 3600)
+
+	if rec := postJSON(h, "/api/printers/printer-1/objects/0/cancel", ""); rec.Code != http.StatusOK {
+		t.Fatalf("klipper cancel = %d: %s", rec.Code, rec.Body)
+	}
+	if cmds := klipper.Commands(); len(cmds) != 1 || !strings.Contains(cmds[0], "EXCLUDE_OBJECT NAME=gear") {
+		t.Errorf("klipper commands = %v", cmds)
+	}
+
+	if rec := postJSON(h, "/api/printers/printer-2/objects/0/cancel", ""); rec.Code != http.StatusOK {
+		t.Fatalf("exclude region cancel = %d: %s", rec.Code, rec.Body)
+	}
+	cmds := marlin.Commands()
+	if len(cmds) != 1 {
+		t.Fatalf("marlin commands = %v", cmds)
+	}
+	var region map[string]interface{}
+	json.Unmarshal([]byte(cmds[0]), &region)
+	if region["command"] != "addExcludeRegion" || region["x1"] != 9.5 || region["y2"] != 30.5 {
+		t.Errorf("exclude region = %v, want the object's footprint plus margin", region)
+	}
+}
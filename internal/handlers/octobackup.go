@@ -0,0 +1,105 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/octobackup"
+)
+
+// startOctoPrintBackups collects OctoPrint backups on OCTOPRINT_BACKUP_INTERVAL and on request
+func (h *Handler) startOctoPrintBackups(ctx context.Context) {
+	s := h.settings.OctoPrintBackup
+
+	printers := make([]octobackup.Printer, 0, len(h.config.Printers))
+	for _, p := range h.config.Printers {
+		printers = append(printers, octobackup.Printer{ID: p.ID, Name: p.Name, Client: liveControl{h, p.ID}})
+	}
+
+	h.octoBackups = octobackup.New(printers, &octobackup.Archive{Dir: s.Dir, Keep: s.Keep}, octobackup.Options{
+		Interval:     s.Interval,
+		Timeout:      s.Timeout,
+		DeleteRemote: s.DeleteRemote,
+	}, h.logger)
+	go h.octoBackups.Run(ctx)
+}
+
+func (h *Handler) handleOctoPrintBackups(w http.ResponseWriter, r *http.Request) {
+	statuses, err := h.octoBackups.Status()
+	if err != nil {
+		h.logger.Printf("Listing OctoPrint backups failed: %v", err)
+		writeError(w, http.StatusInternalServerError, "Failed to list backups")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":   "ok",
+		"printers": statuses,
+	})
+}
+
+func (h *Handler) handleOctoPrintBackupTrigger(w http.ResponseWriter, r *http.Request) {
+	err := h.octoBackups.Trigger(r.PathValue("id"))
+	switch {
+	case errors.Is(err, octobackup.ErrUnknownPrinter):
+		writeError(w, http.StatusNotFound, "Printer not found")
+		return
+	case errors.Is(err, octobackup.ErrBusy):
+		writeError(w, http.StatusConflict, "Backup already in progress")
+		return
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, map[string]interface{}{
+		"status":     "ok",
+		"printer_id": r.PathValue("id"),
+	})
+}
+
+func (h *Handler) handleOctoPrintBackupDownload(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+
+	f, err := h.octoBackups.Archive().Open(r.PathValue("id"), name)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "Backup not found")
+		return
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Failed to read backup")
+		return
+	}
+
+	// OctoPrint backups with uploads and timelapses take longer than the
+	// server's write timeout to download
+	http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	http.ServeContent(w, r, name, info.ModTime(), f)
+}
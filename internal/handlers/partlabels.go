@@ -0,0 +1,112 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/wmarchesi123/go-3dprint-client/config"
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/partlabel"
+)
+
+const jobTemplate = `<!DOCTYPE html>
+<html>
+<head>
+    <title>{{.File}} - OctoDash</title>
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+    <link rel="stylesheet" href="{{asset "style.css"}}">
+</head>
+<body class="load-page job-page">
+    <h1>{{.File}}</h1>
+    <dl class="job-details">
+        <dt>Printer</dt><dd>{{.Printer}}</dd>
+        <dt>Result</dt><dd>{{.Result}}</dd>
+        {{if .Started}}<dt>Started</dt><dd>{{.Started}}</dd>{{end}}
+        <dt>Finished</dt><dd>{{.Finished}}</dd>
+        {{if .PrintTime}}<dt>Print time</dt><dd>{{.PrintTime}}</dd>{{end}}
+        {{if .Filament}}<dt>Filament</dt><dd>{{.Filament}}</dd>{{end}}
+    </dl>
+</body>
+</html>
+`
+
+// printPartLabel queues a label for a finished part when a label printer is
+// configured. Its QR code opens the job's history entry.
+func (h *Handler) printPartLabel(printer config.Printer, rec models.JobRecord) {
+	if h.partLabels == nil {
+		return
+	}
+	h.partLabels.Print(partlabel.Label{
+		File:       rec.File,
+		Printer:    printer.Name,
+		FinishedAt: rec.FinishedAt,
+		URL:        h.settings.PublicURL + "/jobs/" + jobPath(rec.ID),
+	})
+}
+
+// jobPath escapes a job ID for /jobs/, keeping the slashes between its parts
+func jobPath(id string) string {
+	parts := strings.Split(id, "/")
+	for i, p := range parts {
+		parts[i] = url.PathEscape(p)
+	}
+	return strings.Join(parts, "/")
+}
+
+// handleJobPage is where a scanned part label lands: the job history entry
+// for the print that made the part
+func (h *Handler) handleJobPage(w http.ResponseWriter, r *http.Request) {
+	job, ok, err := h.jobHistory.Get(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Failed to read job history: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !ok {
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return
+	}
+
+	data := struct {
+		File, Printer, Result, Started, Finished, PrintTime, Filament string
+	}{
+		File:     job.File,
+		Printer:  job.PrinterID,
+		Result:   job.Result,
+		Finished: models.FormatTimestamp(job.FinishedAt, h.settings.Timezone),
+	}
+	if p, ok := h.findPrinter(job.PrinterID); ok {
+		data.Printer = p.Name
+	}
+	if job.StartedAt != nil {
+		data.Started = models.FormatTimestamp(*job.StartedAt, h.settings.Timezone)
+	}
+	if job.PrintTime > 0 {
+		data.PrintTime = (time.Duration(job.PrintTime) * time.Second).String()
+	}
+	if job.Filament > 0 {
+		data.Filament = fmt.Sprintf("%.1f m", job.Filament)
+	}
+
+	h.renderPage(w, "job", jobTemplate, data)
+}
@@ -0,0 +1,89 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestPartLabel(t *testing.T) {
+	zebra, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zebra.Close()
+	labels := make(chan string, 1)
+	go func() {
+		conn, err := zebra.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		zpl, _ := io.ReadAll(conn)
+		labels <- string(zpl)
+	}()
+
+	t.Setenv("PUBLIC_URL", "http://octodash.local")
+	t.Setenv("PART_LABEL_ADDRESS", zebra.Addr().String())
+	op := testutil.NewOctoPrint(t)
+	op.SetPrinting("order_1042.gcode", 97, 60)
+	h := newTestHandler(t, testutil.NewSpoolman(t), testutil.Printer{Name: "MK4", Server: op})
+
+	getStatus(t, h)
+	op.SetFinished("order_1042.gcode")
+	getStatus(t, h)
+
+	var zpl string
+	select {
+	case zpl = <-labels:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no label was sent to the printer")
+	}
+	for _, want := range []string{"^XA", "order_5F1042.gcode", "^FDMK4^FS", "^XZ"} {
+		if !strings.Contains(zpl, want) {
+			t.Errorf("label is missing %q:\n%s", want, zpl)
+		}
+	}
+
+	// The QR code opens the job's history entry
+	m := regexp.MustCompile(`\^FDMA,http://octodash\.local(/jobs/\S+)\^FS`).FindStringSubmatch(zpl)
+	if m == nil {
+		t.Fatalf("label has no QR code linking to a job:\n%s", zpl)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", m[1], nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "<h1>order_1042.gcode</h1>") {
+		t.Errorf("job page %s = %d:\n%s", m[1], rec.Code, rec.Body)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/jobs/printer-1/nope", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unknown job page = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
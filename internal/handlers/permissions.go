@@ -0,0 +1,55 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/wmarchesi123/octodash/internal/auth"
+)
+
+// handleMe tells the caller who they are and which printers they may
+// control, so the dashboard can hide buttons that would only be refused
+func (h *Handler) handleMe(w http.ResponseWriter, r *http.Request) {
+	user, ok := auth.IdentifiedUser(r.Context())
+	if !ok {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"status":        "ok",
+			"authenticated": false,
+			"printers":      []string{},
+		})
+		return
+	}
+
+	printers := []string{}
+	for _, p := range h.printersMatching(r, "") {
+		if user.Allowed(auth.RoleOperator, p.ID) {
+			printers = append(printers, p.ID)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":        "ok",
+		"authenticated": true,
+		"user":          user.Name,
+		"role":          user.Role.String(),
+		"printers":      printers,
+		"sso":           h.settings.SSO.Enabled,
+	})
+}
@@ -0,0 +1,82 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/wmarchesi123/octodash/internal/settings"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestPrinterGrants(t *testing.T) {
+	t.Setenv("AUTH_USERS", "sam:viewer:sam-token,olga:operator:olga-token")
+	t.Setenv("PRINTER_1_OPERATORS", "sam")
+	t.Setenv("PRINTER_2_OPERATORS", "sam")
+	prusa := testutil.NewOctoPrint(t)
+	classroom := testutil.NewOctoPrint(t)
+	voron := testutil.NewOctoPrint(t)
+	h := newTestHandler(t, testutil.NewSpoolman(t),
+		testutil.Printer{Name: "MK4", Server: prusa},
+		testutil.Printer{Name: "Mini", Server: classroom},
+		testutil.Printer{Name: "Voron", Server: voron})
+	for _, op := range []*testutil.OctoPrint{prusa, classroom, voron} {
+		op.SetPrinting("gear.gcode", 40, 1200)
+	}
+
+	if rec := doAs(h, "sam-token", "POST", "/api/printers/printer-1/pause", ""); rec.Code != http.StatusOK {
+		t.Errorf("granted pause = %d: %s", rec.Code, rec.Body)
+	}
+	if rec := doAs(h, "sam-token", "POST", "/api/printers/printer-3/pause", ""); rec.Code != http.StatusForbidden {
+		t.Errorf("ungranted pause = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+	if rec := doAs(h, "sam-token", "POST", "/api/bulk/cooldown", ""); rec.Code != http.StatusForbidden {
+		t.Errorf("bulk action = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+	if rec := doAs(h, "olga-token", "POST", "/api/printers/printer-3/pause", ""); rec.Code != http.StatusOK {
+		t.Errorf("operator pause = %d: %s", rec.Code, rec.Body)
+	}
+	if prusa.Requests("POST /api/job") != 1 || voron.Requests("POST /api/job") != 1 {
+		t.Errorf("job commands = %d and %d, want one each", prusa.Requests("POST /api/job"), voron.Requests("POST /api/job"))
+	}
+
+	me := func(token string) map[string]interface{} {
+		t.Helper()
+		rec := doAs(h, token, "GET", "/api/me", "")
+		var response map[string]interface{}
+		json.NewDecoder(rec.Body).Decode(&response)
+		return response
+	}
+	if got := me("sam-token"); got["role"] != "viewer" || len(got["printers"].([]interface{})) != 2 {
+		t.Errorf("sam = %v, want viewer controlling printer-1 and printer-2", got)
+	}
+	if got := me("olga-token"); len(got["printers"].([]interface{})) != 3 {
+		t.Errorf("olga = %v, want every printer", got)
+	}
+	if got := me(""); got["authenticated"] != false {
+		t.Errorf("anonymous = %v", got)
+	}
+
+	t.Setenv("PRINTER_3_OPERATORS", "nobody")
+	if _, err := settings.Load(); err == nil {
+		t.Error("loaded a grant for an unknown user")
+	}
+}
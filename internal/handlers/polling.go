@@ -0,0 +1,126 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"sync"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/settings"
+)
+
+// pollPolicy implements adaptive polling. A printer that has sat idle or
+// offline for idleAfter is contacted at most once per idleInterval and only
+// for its state; the job, spool and thumbnail calls are skipped. Any status
+// change restores full-rate polling.
+type pollPolicy struct {
+	adaptive     bool
+	idleAfter    time.Duration
+	idleInterval time.Duration
+
+	mu       sync.Mutex
+	printers map[string]*polledPrinter
+}
+
+// polledPrinter is what the policy remembers about a printer's last fetch
+type polledPrinter struct {
+	quietSince time.Time
+	fetchedAt  time.Time
+	last       statusSlot
+}
+
+func newPollPolicy(s settings.PollSettings) *pollPolicy {
+	return &pollPolicy{
+		adaptive:     s.Policy == "adaptive",
+		idleAfter:    s.IdleAfter,
+		idleInterval: s.IdleInterval,
+		printers:     make(map[string]*polledPrinter),
+	}
+}
+
+// quiet reports whether a status lets the printer be polled less often
+func quiet(status string) bool {
+	return status == "idle" || status == "completed" || status == "offline"
+}
+
+// backedOff reports whether the printer has been quiet long enough to poll slowly
+func (p *pollPolicy) backedOff(id string, now time.Time) bool {
+	if !p.adaptive {
+		return false
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pp, ok := p.printers[id]
+	return ok && !pp.quietSince.IsZero() && now.Sub(pp.quietSince) >= p.idleAfter
+}
+
+// cached copies the last status into slot when a backed-off printer is not yet due
+func (p *pollPolicy) cached(id string, now time.Time, slot *statusSlot) bool {
+	if !p.backedOff(id, now) {
+		return false
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pp := p.printers[id]
+	if now.Sub(pp.fetchedAt) >= p.idleInterval {
+		return false
+	}
+	slot.copyFrom(&pp.last)
+	return true
+}
+
+// lastSpool returns the spool seen on the last full fetch
+func (p *pollPolicy) lastSpool(id string) map[string]interface{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if pp, ok := p.printers[id]; ok {
+		return pp.last.status.CurrentSpool
+	}
+	return nil
+}
+
+// record remembers a freshly fetched status
+func (p *pollPolicy) record(id string, slot *statusSlot, now time.Time) {
+	if !p.adaptive {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pp, ok := p.printers[id]
+	if !ok {
+		pp = &polledPrinter{}
+		p.printers[id] = pp
+	}
+
+	switch {
+	case !quiet(slot.status.Status):
+		pp.quietSince = time.Time{}
+	case !ok || pp.last.status.Status != slot.status.Status || pp.quietSince.IsZero():
+		pp.quietSince = now
+	}
+	pp.fetchedAt = now
+	pp.last.copyFrom(slot)
+}
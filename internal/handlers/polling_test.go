@@ -0,0 +1,129 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestAdaptivePolling(t *testing.T) {
+	t.Setenv("POLL_POLICY", "adaptive")
+	t.Setenv("POLL_IDLE_AFTER", "2m")
+	t.Setenv("POLL_IDLE_INTERVAL", "15s")
+	t.Setenv("UPDATES_CHECK_INTERVAL", "0")
+	t.Setenv("EVENTS_POLL_INTERVAL", "0")
+
+	sm := testutil.NewSpoolman(t, testutil.Spool(7, "Prusament", "PLA", "ff0000", 800))
+	op := testutil.NewOctoPrint(t)
+	op.SetSpool("7")
+	clock := &stepClock{now: time.Date(2025, 3, 1, 3, 0, 0, 0, time.UTC)}
+	h := newHandlerWithConfig(t, testutil.Config(sm, testutil.Printer{Name: "Mini", Server: op}), WithClock(clock))
+
+	calls := func() (state, spool, job int) {
+		return op.Requests("GET /api/printer"), op.Requests("POST /api/plugin/spoolman_api"), op.Requests("GET /api/job")
+	}
+
+	getStatus(t, h)
+	if state, spool, _ := calls(); state != 1 || spool != 1 {
+		t.Fatalf("first poll made %d state and %d spool calls, want 1 each", state, spool)
+	}
+
+	// Idle past the threshold: only the state is fetched and the spool is reused
+	clock.advance(3 * time.Minute)
+	p := getStatus(t, h)["printer-1"]
+	if state, spool, _ := calls(); state != 2 || spool != 1 {
+		t.Errorf("backed-off poll made %d state and %d spool calls, want 2 and 1", state, spool)
+	}
+	if p.CurrentSpool == nil {
+		t.Error("backed-off poll dropped the current spool")
+	}
+
+	// Within the idle interval nothing is fetched at all
+	clock.advance(5 * time.Second)
+	p = getStatus(t, h)["printer-1"]
+	if state, _, _ := calls(); state != 2 {
+		t.Errorf("cached poll made %d state calls, want 2", state)
+	}
+	if p.Status != "idle" || p.Temperatures == nil {
+		t.Errorf("cached status = %+v", p)
+	}
+
+	// A state change is picked up on the next slow poll and restores full rate
+	op.SetPrinting("gear.gcode", 40, 600)
+	clock.advance(15 * time.Second)
+	if p = getStatus(t, h)["printer-1"]; p.Status != "printing" || p.Progress == nil {
+		t.Fatalf("status after print started = %s, progress %v", p.Status, p.Progress)
+	}
+	clock.advance(time.Second)
+	getStatus(t, h)
+	if state, spool, job := calls(); state != 4 || spool != 3 || job != 2 {
+		t.Errorf("printing polls made %d state, %d spool and %d job calls, want 4, 3 and 2", state, spool, job)
+	}
+}
+
+func TestOfflineDebounce(t *testing.T) {
+	t.Setenv("POLL_OFFLINE_AFTER", "3")
+	t.Setenv("POLL_ONLINE_AFTER", "2")
+	t.Setenv("UPDATES_CHECK_INTERVAL", "0")
+	t.Setenv("EVENTS_POLL_INTERVAL", "0")
+
+	sm := testutil.NewSpoolman(t)
+	op := testutil.NewOctoPrint(t)
+	op.SetPrinting("gear.gcode", 40, 600)
+	h := newTestHandler(t, sm, testutil.Printer{Name: "Mini", Server: op})
+
+	status := func() models.PrinterStatus { return getStatus(t, h)["printer-1"] }
+
+	if p := status(); p.Status != "printing" {
+		t.Fatalf("initial status = %s, want printing", p.Status)
+	}
+
+	// Two dropped polls keep showing the last good status
+	op.SetFault(testutil.Fault{StatusCode: http.StatusBadGateway})
+	for i := 1; i <= 2; i++ {
+		if p := status(); p.Status != "printing" || p.Progress == nil || p.Progress.Completion != 40 {
+			t.Errorf("failed poll %d = %s, progress %v, want last good status", i, p.Status, p.Progress)
+		}
+	}
+	if p := status(); p.Status != "offline" {
+		t.Errorf("third failed poll = %s, want offline", p.Status)
+	}
+
+	// One good poll isn't enough to come back
+	op.SetFault(testutil.Fault{})
+	if p := status(); p.Status != "offline" || p.Temperatures != nil {
+		t.Errorf("first recovered poll = %s, temperatures %v, want still offline", p.Status, p.Temperatures)
+	}
+
+	// A failure resets the count
+	op.SetFault(testutil.Fault{StatusCode: http.StatusBadGateway})
+	status()
+	op.SetFault(testutil.Fault{})
+	if p := status(); p.Status != "offline" {
+		t.Errorf("poll after interrupted recovery = %s, want offline", p.Status)
+	}
+	if p := status(); p.Status != "printing" || p.Error != "" {
+		t.Errorf("second consecutive good poll = %s (%s), want printing", p.Status, p.Error)
+	}
+}
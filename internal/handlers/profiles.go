@@ -0,0 +1,161 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/profiles"
+)
+
+func (h *Handler) handleProfileList(w http.ResponseWriter, r *http.Request) {
+	list, err := h.profiles.List()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":   "ok",
+		"profiles": list,
+	})
+}
+
+// handleProfileGet returns a profile and the printers whose configuration fits it
+func (h *Handler) handleProfileGet(w http.ResponseWriter, r *http.Request) {
+	profile, err := h.profiles.Get(r.PathValue("name"))
+	if errors.Is(err, profiles.ErrNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	printers := []string{}
+	for _, p := range h.printersMatching(r, "") {
+		if h.profileFits(profile, p.ID, nil) {
+			printers = append(printers, p.ID)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":   "ok",
+		"profile":  profile,
+		"printers": printers,
+	})
+}
+
+// handleProfilePut creates or replaces the profile named in the path
+func (h *Handler) handleProfilePut(w http.ResponseWriter, r *http.Request) {
+	var profile models.PrintProfile
+	if err := json.NewDecoder(r.Body).Decode(&profile); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	profile.Name = r.PathValue("name")
+	if profile.Nozzle < 0 {
+		writeError(w, http.StatusBadRequest, "nozzle must be positive")
+		return
+	}
+	if profile.Slicer != "" && !h.slicer.HasProfile(profile.Slicer) {
+		writeError(w, http.StatusBadRequest, "Unknown slicer profile "+profile.Slicer)
+		return
+	}
+
+	profile, err := h.profiles.Put(profile, h.clock.Now())
+	if errors.Is(err, profiles.ErrInvalidName) {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "ok",
+		"profile": profile,
+	})
+}
+
+// handleProfileDelete removes a profile that no waiting queue entry uses
+func (h *Handler) handleProfileDelete(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	entries, err := h.queue.List()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	for _, e := range entries {
+		if e.PrintProfile == name && e.Status != models.QueueDispatched {
+			writeError(w, http.StatusConflict, "Profile is used by queue entry "+e.ID)
+			return
+		}
+	}
+
+	if isDryRun(r) {
+		_, err = h.profiles.Get(name)
+	} else {
+		err = h.profiles.Delete(name)
+	}
+	if errors.Is(err, profiles.ErrNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if isDryRun(r) {
+		writeDryRun(w, r, "profile.delete", 0, []models.DryRunTarget{{Change: "delete profile " + name}})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"status": "ok"})
+}
+
+// profileFits reports whether a printer can run jobs using a profile. Tags and
+// a "nozzle" tag come from configuration; when a status is given, the material
+// must also match the loaded spool if the printer reports one.
+func (h *Handler) profileFits(profile models.PrintProfile, printerID string, status *models.PrinterStatus) bool {
+	tags := h.settings.Printers[printerID].Tags
+	if !hasTags(tags, profile.Tags) {
+		return false
+	}
+	if nozzle, ok := tags["nozzle"]; ok && profile.Nozzle > 0 {
+		size, err := strconv.ParseFloat(strings.TrimSuffix(nozzle, "mm"), 64)
+		if err != nil || math.Abs(size-profile.Nozzle) > 0.001 {
+			return false
+		}
+	}
+	if status != nil && profile.Material != "" {
+		if loaded, _ := status.CurrentSpool["material"].(string); loaded != "" && !strings.EqualFold(loaded, profile.Material) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,100 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestPrintProfiles(t *testing.T) {
+	t.Setenv("PRINTER_1_TAGS", "nozzle=0.4")
+	t.Setenv("PRINTER_2_TAGS", "nozzle=0.6,enclosure=yes")
+	t.Setenv("PRINTER_3_TAGS", "nozzle=0.4mm")
+	sm := testutil.NewSpoolman(t,
+		testutil.Spool(1, "Prusament", "PLA", "ff0000", 700),
+		testutil.Spool(2, "Prusament", "PETG", "00ff00", 700),
+	)
+	var servers []*testutil.OctoPrint
+	var printers []testutil.Printer
+	for i, spool := range []string{"1", "2", "2"} {
+		op := testutil.NewOctoPrint(t)
+		op.SetSpool(spool)
+		servers = append(servers, op)
+		printers = append(printers, testutil.Printer{Name: fmt.Sprintf("P%d", i+1), Server: op})
+	}
+	h := newTestHandler(t, sm, printers...)
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return rec
+	}
+
+	if rec := do("PUT", "/api/profiles/bad%20name", `{}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid name = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if rec := do("PUT", "/api/profiles/petg-04", `{"material": "PETG", "nozzle": 0.4, "quality": "0.2mm SPEED"}`); rec.Code != http.StatusOK {
+		t.Fatalf("create profile = %d: %s", rec.Code, rec.Body)
+	}
+
+	var got struct {
+		Printers []string `json:"printers"`
+	}
+	json.NewDecoder(do("GET", "/api/profiles/petg-04", "").Body).Decode(&got)
+	if !slices.Equal(got.Printers, []string{"printer-1", "printer-3"}) {
+		t.Errorf("printers fitting petg-04 = %v, want the 0.4mm nozzles", got.Printers)
+	}
+
+	if rec := do("POST", "/api/queue", `{"file": "bracket.gcode", "print_profile": "petg-04", "printer_id": "printer-2"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("pinned to a 0.6mm nozzle = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if rec := do("POST", "/api/queue", `{"file": "bracket.gcode", "print_profile": "nope"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown profile = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if rec := do("POST", "/api/queue", `{"file": "bracket.gcode", "print_profile": "petg-04"}`); rec.Code != http.StatusCreated {
+		t.Fatalf("queue = %d: %s", rec.Code, rec.Body)
+	}
+	if rec := do("DELETE", "/api/profiles/petg-04", ""); rec.Code != http.StatusConflict {
+		t.Errorf("delete while queued = %d, want %d", rec.Code, http.StatusConflict)
+	}
+
+	// P1 has the right nozzle but PLA loaded, so the job goes to P3
+	h.dispatchOnce()
+	if printed := servers[0].Printed(); len(printed) != 0 {
+		t.Errorf("P1 printed %v with PLA loaded", printed)
+	}
+	if printed := servers[2].Printed(); !slices.Equal(printed, []string{"bracket.gcode"}) {
+		t.Errorf("P3 printed %v, want [bracket.gcode]", printed)
+	}
+
+	if rec := do("DELETE", "/api/profiles/petg-04", ""); rec.Code != http.StatusOK {
+		t.Errorf("delete = %d: %s", rec.Code, rec.Body)
+	}
+	if rec := do("GET", "/api/profiles/petg-04", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("get after delete = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
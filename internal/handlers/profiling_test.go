@@ -0,0 +1,95 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestProfilingEndpoints(t *testing.T) {
+	get := func(h *Handler, path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Setenv("AUTH_USERS", "ops:operator:op-token,root:admin:admin-token")
+
+	off := newTestHandler(t, testutil.NewSpoolman(t))
+	if rec := get(off, "/debug/vars", "admin-token"); strings.Contains(rec.Body.String(), "memstats") {
+		t.Error("GET /debug/vars served expvar with profiling disabled")
+	}
+
+	t.Setenv("PROFILING_ENABLED", "true")
+	on := newTestHandler(t, testutil.NewSpoolman(t))
+
+	cases := []struct {
+		path  string
+		token string
+		want  int
+	}{
+		{"/debug/vars", "", http.StatusUnauthorized},
+		{"/debug/vars", "op-token", http.StatusForbidden},
+		{"/debug/vars", "admin-token", http.StatusOK},
+		{"/debug/pprof/", "admin-token", http.StatusOK},
+		{"/debug/pprof/heap", "admin-token", http.StatusOK},
+	}
+	for _, c := range cases {
+		if rec := get(on, c.path, c.token); rec.Code != c.want {
+			t.Errorf("GET %s with %q = %d, want %d", c.path, c.token, rec.Code, c.want)
+		}
+	}
+	if rec := get(on, "/debug/vars", "admin-token"); !strings.Contains(rec.Body.String(), "goroutines") {
+		t.Errorf("expvar output missing goroutines: %s", rec.Body)
+	}
+}
+
+func TestProfileOutlivesWriteTimeout(t *testing.T) {
+	t.Setenv("AUTH_USERS", "root:admin:admin-token")
+	t.Setenv("PROFILING_ENABLED", "true")
+	h := newTestHandler(t, testutil.NewSpoolman(t))
+
+	srv := httptest.NewUnstartedServer(h)
+	srv.Config.WriteTimeout = 300 * time.Millisecond
+	srv.Start()
+	defer srv.Close()
+
+	req, _ := http.NewRequest("GET", srv.URL+"/debug/pprof/trace?seconds=1", nil)
+	req.Header.Set("Authorization", "Bearer admin-token")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("trace cut off: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil || resp.StatusCode != http.StatusOK || len(body) == 0 {
+		t.Errorf("trace = %d, %d bytes, %v", resp.StatusCode, len(body), err)
+	}
+}
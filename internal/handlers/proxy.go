@@ -0,0 +1,100 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// proxyPlugin returns the plugin a cleaned OctoPrint path belongs to, for
+// /plugin/<name>/... and /api/plugin/<name>. Nothing else is proxied: the core
+// API would let operators send G-code, delete files or cancel jobs around
+// OctoDash's own permissions, confirmations and audit trail.
+func proxyPlugin(p string) (string, bool) {
+	p = strings.ToLower(p)
+	if name, ok := strings.CutPrefix(p, "/api/plugin/"); ok && name != "" && !strings.Contains(name, "/") {
+		return name, true
+	}
+	if rest, ok := strings.CutPrefix(p, "/plugin/"); ok {
+		name, _, _ := strings.Cut(rest, "/")
+		return name, name != ""
+	}
+	return "", false
+}
+
+// handleProxy forwards a request under /proxy/{id}/ to the printer's
+// OctoPrint, swapping the caller's credentials for the printer's API key so
+// the configured plugins' endpoints are reachable without handing the key to
+// the browser. Plugins not listed as writable only take GET and HEAD.
+func (h *Handler) handleProxy(w http.ResponseWriter, r *http.Request) {
+	printer, ok := h.findPrinter(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Printer not found")
+		return
+	}
+	target, err := url.Parse(printer.OctoPrintURL)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, "Invalid OctoPrint URL")
+		return
+	}
+
+	p := path.Clean("/" + r.PathValue("path"))
+	if strings.HasSuffix(r.PathValue("path"), "/") && p != "/" {
+		p += "/"
+	}
+	name, ok := proxyPlugin(p)
+	writable, allowed := h.settings.Proxy.Plugins[name]
+	if !ok || !allowed {
+		writeError(w, http.StatusForbidden, p+" is not available through the proxy")
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		if !writable {
+			writeError(w, http.StatusForbidden, "The "+name+" plugin is read-only through the proxy")
+			return
+		}
+		h.audit(r, "proxy", printer.ID, r.Method+" "+p)
+	}
+	key := h.clients.Load().keys[printer.ID]
+
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.Out.URL.Path = p
+			pr.Out.URL.RawPath = ""
+			pr.SetURL(target)
+			// OctoDash's own session and tokens mean nothing to OctoPrint
+			pr.Out.Header.Del("Cookie")
+			pr.Out.Header.Del("Authorization")
+			pr.Out.Header.Set("X-Api-Key", key)
+		},
+		ModifyResponse: func(resp *http.Response) error {
+			resp.Header.Del("Set-Cookie")
+			return nil
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			h.logger.Printf("Proxy to %s failed: %v", printer.Name, err)
+			writeError(w, http.StatusBadGateway, "OctoPrint unreachable")
+		},
+	}
+	proxy.ServeHTTP(w, r)
+}
@@ -0,0 +1,105 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestProxy(t *testing.T) {
+	t.Setenv("PROXY_ENABLED", "true")
+	t.Setenv("PROXY_PLUGINS", "DisplayLayerProgress")
+	t.Setenv("PROXY_WRITE_PLUGINS", "excluderegion")
+	t.Setenv("AUTH_USERS", "vic:viewer:vic-token,olga:operator:olga-token")
+	op := testutil.NewOctoPrint(t)
+	op.SetAPIKey("test-key")
+	h := newTestHandler(t, testutil.NewSpoolman(t), testutil.Printer{Name: "Mini", Server: op})
+
+	get := func(token, path string) *httptest.ResponseRecorder {
+		return doAs(h, token, "GET", path, "")
+	}
+
+	const plugin = "/plugin/DisplayLayerProgress/values"
+	if rec := get("vic-token", "/proxy/printer-1"+plugin); rec.Code != http.StatusForbidden {
+		t.Errorf("viewer proxy = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+	if n := op.Requests("GET " + plugin); n != 0 {
+		t.Errorf("viewer request reached OctoPrint %d times", n)
+	}
+
+	// The caller's token is swapped for the printer's key
+	rec := get("olga-token", "/proxy/printer-1"+plugin)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("operator proxy = %d: %s", rec.Code, rec.Body)
+	}
+	if n := op.Requests("GET " + plugin); n != 1 {
+		t.Errorf("OctoPrint saw %d plugin requests, want 1", n)
+	}
+
+	// Only listed plugins are reachable; OctoPrint's own API never is
+	for _, path := range []string{"/api/settings", "/api/printer/command", "/api/files", "/api/files/local/gear.gcode", "/api/job", "/api%2Fusers", "/plugin/appkeys/probe", "/plugin/octoprint_excluderegion/"} {
+		if rec := get("olga-token", "/proxy/printer-1"+path); rec.Code != http.StatusForbidden {
+			t.Errorf("proxy %s = %d, want %d", path, rec.Code, http.StatusForbidden)
+		}
+	}
+	for _, req := range []string{"POST /api/printer/command", "POST /api/files/local", "DELETE /api/files/local/gear.gcode", "POST /api/job", "POST /plugin/DisplayLayerProgress/values"} {
+		method, path, _ := strings.Cut(req, " ")
+		if rec := doAs(h, "olga-token", method, "/proxy/printer-1"+path, `{"commands": ["M112"]}`); rec.Code != http.StatusForbidden {
+			t.Errorf("proxy %s = %d, want %d", req, rec.Code, http.StatusForbidden)
+		}
+		if n := op.Requests(req); n != 0 {
+			t.Errorf("%s reached OctoPrint %d times", req, n)
+		}
+	}
+
+	// Writable plugins take writes, which are audited
+	if rec := doAs(h, "olga-token", "POST", "/proxy/printer-1/api/plugin/excluderegion", `{"command": "excludeRegion"}`); rec.Code != http.StatusNoContent {
+		t.Errorf("writable plugin POST = %d: %s", rec.Code, rec.Body)
+	}
+	if n := op.Requests("POST /api/plugin/excluderegion"); n != 1 {
+		t.Errorf("OctoPrint saw %d writable plugin requests, want 1", n)
+	}
+
+	if rec := get("olga-token", "/proxy/printer-9"+plugin); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown printer = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestProxyPlugin(t *testing.T) {
+	tests := map[string]string{
+		"/plugin/DisplayLayerProgress/":      "displaylayerprogress",
+		"/plugin/octolapse/static/js/app.js": "octolapse",
+		"/api/plugin/octolapse":              "octolapse",
+		"/api/plugin/octolapse/x":            "",
+		"/api/plugin/":                       "",
+		"/plugin/":                           "",
+		"/api/printer/command":               "",
+		"/api/files":                         "",
+	}
+	for path, want := range tests {
+		if got, _ := proxyPlugin(path); got != want {
+			t.Errorf("proxyPlugin(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
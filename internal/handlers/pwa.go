@@ -0,0 +1,113 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+func (h *Handler) handleManifest(w http.ResponseWriter, r *http.Request) {
+	manifest := map[string]interface{}{
+		"name":             "OctoDash",
+		"short_name":       "OctoDash",
+		"description":      "Printer farm dashboard",
+		"start_url":        "/",
+		"scope":            "/",
+		"display":          "standalone",
+		"background_color": "#1a1a1a",
+		"theme_color":      "#ff6b00",
+		"icons": []map[string]string{
+			{"src": h.assets.URL("icon-192.png"), "sizes": "192x192", "type": "image/png"},
+			{"src": h.assets.URL("icon-512.png"), "sizes": "512x512", "type": "image/png", "purpose": "any maskable"},
+		},
+	}
+
+	w.Header().Set("Content-Type", "application/manifest+json")
+	w.Header().Set("Cache-Control", "no-cache")
+	json.NewEncoder(w).Encode(manifest)
+}
+
+// handleServiceWorker serves a worker whose cache name is tied to the asset
+// hashes, so every deploy with changed assets installs a fresh cache
+func (h *Handler) handleServiceWorker(w http.ResponseWriter, r *http.Request) {
+	shell := []string{"/"}
+	for _, name := range h.assets.Names() {
+		shell = append(shell, h.assets.URL(name))
+	}
+	shellJSON, _ := json.Marshal(shell)
+
+	w.Header().Set("Content-Type", "application/javascript")
+	w.Header().Set("Cache-Control", "no-cache")
+	fmt.Fprintf(w, serviceWorkerJS, h.assets.Version(), shellJSON)
+}
+
+const serviceWorkerJS = `// Generated by OctoDash
+const CACHE = 'octodash-%s';
+const SHELL = %s;
+
+self.addEventListener('install', (event) => {
+    event.waitUntil(caches.open(CACHE).then((cache) => cache.addAll(SHELL)));
+    self.skipWaiting();
+});
+
+self.addEventListener('activate', (event) => {
+    // Drop caches from previous asset versions
+    event.waitUntil(
+        caches.keys().then((keys) => Promise.all(
+            keys.filter((key) => key.startsWith('octodash-') && key !== CACHE)
+                .map((key) => caches.delete(key))
+        ))
+    );
+    self.clients.claim();
+});
+
+self.addEventListener('fetch', (event) => {
+    const url = new URL(event.request.url);
+
+    // Live data, proxied OctoPrint pages, embeds and profiling always go to the network
+    if (event.request.method !== 'GET' || url.origin !== location.origin ||
+        ['/api/', '/proxy/', '/embed/', '/debug/'].some((prefix) => url.pathname.startsWith(prefix))) {
+        return;
+    }
+
+    // Pages: network first, falling back to the cached shell during a blip.
+    // Only a good copy of the dashboard itself becomes the shell.
+    if (event.request.mode === 'navigate') {
+        event.respondWith(
+            fetch(event.request)
+                .then((response) => {
+                    if (url.pathname === '/' && response.ok) {
+                        const copy = response.clone();
+                        caches.open(CACHE).then((cache) => cache.put('/', copy));
+                    }
+                    return response;
+                })
+                .catch(() => caches.match('/'))
+        );
+        return;
+    }
+
+    // Versioned assets: cache first
+    event.respondWith(
+        caches.match(event.request).then((cached) => cached || fetch(event.request))
+    );
+});
+`
@@ -0,0 +1,165 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/wmarchesi123/octodash/internal/auth"
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/queue"
+)
+
+func (h *Handler) handleQueueList(w http.ResponseWriter, r *http.Request) {
+	entries, err := h.queue.List()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	scope := h.tenancy(r)
+	visible := []models.QueueEntry{}
+	for _, e := range entries {
+		if scope.team(e.Team) {
+			visible = append(visible, e)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "ok",
+		"queue":  visible,
+	})
+}
+
+func (h *Handler) handleQueueAdd(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		File         string `json:"file"`
+		PrinterID    string `json:"printer_id"`
+		PrintProfile string `json:"print_profile"`
+		Team         string `json:"team"`
+		Priority     string `json:"priority"`
+		NotBefore    string `json:"not_before"`
+		OffPeak      bool   `json:"off_peak"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	req.File = strings.TrimSpace(req.File)
+	if req.File == "" {
+		writeError(w, http.StatusBadRequest, "File is required")
+		return
+	}
+	if req.PrinterID != "" {
+		if _, ok := h.findPrinter(req.PrinterID); !ok {
+			writeError(w, http.StatusBadRequest, "Unknown printer")
+			return
+		}
+	}
+
+	if err := h.checkPriority(r, req.Priority); err != nil {
+		writePriorityError(w, err)
+		return
+	}
+	notBefore, err := h.parseSchedule(req.NotBefore, req.OffPeak)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := h.checkQuota(r); err != nil {
+		writeQuotaError(w, err)
+		return
+	}
+	team, err := h.entryTeam(r, req.Team, req.PrinterID)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if _, err := h.checkPrintProfile(req.PrintProfile, req.PrinterID); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	entry, err := h.queue.Add(models.QueueEntry{
+		File:         req.File,
+		PrinterID:    req.PrinterID,
+		PrintProfile: req.PrintProfile,
+		Team:         team,
+		Priority:     req.Priority,
+		NotBefore:    notBefore,
+		OffPeak:      req.OffPeak,
+		Approval:     h.approvalFor(r),
+		SubmittedBy:  auth.UserFromContext(r.Context()).Name,
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"status": "ok",
+		"entry":  entry,
+	})
+}
+
+func (h *Handler) handleQueueRemove(w http.ResponseWriter, r *http.Request) {
+	entry, err := h.queueEntry(r, r.PathValue("id"))
+	if err == nil && isDryRun(r) {
+		writeDryRun(w, r, "queue.remove", 0, []models.DryRunTarget{{
+			PrinterID: entry.PrinterID,
+			Change:    "remove queue entry " + entry.ID + " (" + entry.File + ")",
+		}})
+		return
+	}
+	if err == nil {
+		err = h.queue.Remove(entry.ID)
+	}
+	if errors.Is(err, queue.ErrNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.removeModel(entry)
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"status": "ok"})
+}
+
+// checkPrintProfile looks up the print profile an entry names, if any, and
+// makes sure a pinned printer is configured to run it
+func (h *Handler) checkPrintProfile(name, printerID string) (models.PrintProfile, error) {
+	if name == "" {
+		return models.PrintProfile{}, nil
+	}
+	profile, err := h.profiles.Get(name)
+	if err != nil {
+		return profile, fmt.Errorf("unknown print profile %q", name)
+	}
+	if printerID != "" && !h.profileFits(profile, printerID, nil) {
+		return profile, fmt.Errorf("%s does not fit print profile %q", printerID, name)
+	}
+	return profile, nil
+}
@@ -0,0 +1,214 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/wmarchesi123/octodash/internal/auth"
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/preview"
+	"github.com/wmarchesi123/octodash/internal/queue"
+	"github.com/wmarchesi123/octodash/internal/slicer"
+)
+
+// previewSize is the width and height of rendered model previews
+const previewSize = 256
+
+const queueTemplate = `<!DOCTYPE html>
+<html>
+<head>
+    <title>Print queue - OctoDash</title>
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+    <link rel="stylesheet" href="{{asset "style.css"}}">
+</head>
+<body class="queue-page">
+    <h1>Print queue</h1>
+    <div class="queue-list" id="queue-list"></div>
+    <script src="{{asset "queue.js"}}"></script>
+</body>
+</html>
+`
+
+// handleQueuePage serves the queue view, showing previews for uploaded models
+func (h *Handler) handleQueuePage(w http.ResponseWriter, r *http.Request) {
+	h.renderPage(w, "queue", queueTemplate, nil)
+}
+
+// handleQueueModelUpload queues an STL or 3MF model sent as the raw request
+// body, named by ?file= and optionally pinned with ?printer_id=. The entry
+// waits for slicing, with a preview rendered up front; naming a slicer
+// ?profile=, or a ?print_profile= that has one, starts slicing straight away.
+func (h *Handler) handleQueueModelUpload(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	name := filepath.Base(strings.TrimSpace(q.Get("file")))
+	if !preview.IsModel(name) {
+		writeError(w, http.StatusBadRequest, "file must name an .stl or .3mf model")
+		return
+	}
+	printerID := q.Get("printer_id")
+	if printerID != "" {
+		if _, ok := h.findPrinter(printerID); !ok {
+			writeError(w, http.StatusBadRequest, "Unknown printer")
+			return
+		}
+	}
+
+	if err := h.checkPriority(r, q.Get("priority")); err != nil {
+		writePriorityError(w, err)
+		return
+	}
+	offPeak := q.Get("off_peak") == "1" || q.Get("off_peak") == "true"
+	notBefore, err := h.parseSchedule(q.Get("not_before"), offPeak)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := h.checkQuota(r); err != nil {
+		writeQuotaError(w, err)
+		return
+	}
+	team, err := h.entryTeam(r, q.Get("team"), printerID)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	printProfile, err := h.checkPrintProfile(q.Get("print_profile"), printerID)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	// The print profile's slicer settings apply unless the upload picks others
+	profile := q.Get("profile")
+	if profile == "" && h.slicer.Enabled() {
+		profile = printProfile.Slicer
+	}
+	if profile != "" && !h.slicer.Enabled() {
+		writeError(w, http.StatusServiceUnavailable, slicer.ErrDisabled.Error())
+		return
+	}
+	if profile != "" && !h.slicer.HasProfile(profile) {
+		writeError(w, http.StatusBadRequest, slicer.ErrUnknownProfile.Error())
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, h.settings.Queue.MaxModelSize))
+	if err != nil {
+		writeError(w, http.StatusRequestEntityTooLarge, "Model is too large")
+		return
+	}
+	mesh, err := preview.Parse(name, data)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	png, err := preview.PNG(mesh, previewSize)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	entry, err := h.queue.Add(models.QueueEntry{
+		File:         name,
+		PrinterID:    printerID,
+		PrintProfile: printProfile.Name,
+		Team:         team,
+		Priority:     q.Get("priority"),
+		NotBefore:    notBefore,
+		OffPeak:      offPeak,
+		Approval:     h.approvalFor(r),
+		Status:       models.QueueNeedsSlicing,
+		SubmittedBy:  auth.UserFromContext(r.Context()).Name,
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	entry.Model = entry.ID + strings.ToLower(filepath.Ext(name))
+	entry.PreviewURL = "/api/queue/" + entry.ID + "/preview.png"
+	if err := h.saveModel(entry, data, png); err != nil {
+		h.queue.Remove(entry.ID)
+		h.removeModel(entry)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.logger.Printf("Queued model %s as %s (%d triangles)", name, entry.ID, len(mesh.Triangles))
+	if profile != "" {
+		if entry, err = h.startSlicing(entry.ID, profile); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"status": "ok",
+		"entry":  entry,
+	})
+}
+
+// saveModel writes an entry's model and preview to disk and records them on the entry
+func (h *Handler) saveModel(entry models.QueueEntry, data, png []byte) error {
+	dir := h.settings.Queue.ModelDir
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, entry.Model), data, 0o600); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, entry.ID+".png"), png, 0o600); err != nil {
+		return err
+	}
+	return h.queue.Update(entry)
+}
+
+// removeModel deletes the files kept for an uploaded model and its gcode, if any
+func (h *Handler) removeModel(entry models.QueueEntry) {
+	if entry.Model == "" {
+		return
+	}
+	dir := h.settings.Queue.ModelDir
+	for _, name := range []string{entry.Model, entry.ID + ".png", entry.ID + ".gcode"} {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			h.logger.Printf("Failed to remove %s: %v", name, err)
+		}
+	}
+}
+
+// handleQueuePreview serves the rendered preview of a queued model
+func (h *Handler) handleQueuePreview(w http.ResponseWriter, r *http.Request) {
+	entry, err := h.queueEntry(r, r.PathValue("id"))
+	if errors.Is(err, queue.ErrNotFound) || (err == nil && entry.PreviewURL == "") {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Previews are re-rendered in place, so revalidate against Last-Modified
+	w.Header().Set("Cache-Control", "private, no-cache")
+	http.ServeFile(w, r, filepath.Join(h.settings.Queue.ModelDir, entry.ID+".png"))
+}
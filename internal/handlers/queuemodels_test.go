@@ -0,0 +1,119 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+const tetrahedronSTL = `solid tetra
+facet normal 0 0 0
+outer loop
+vertex 0 0 0
+vertex 10 0 0
+vertex 0 10 0
+endloop
+endfacet
+facet normal 0 0 0
+outer loop
+vertex 0 0 0
+vertex 10 0 0
+vertex 0 0 10
+endloop
+endfacet
+facet normal 0 0 0
+outer loop
+vertex 0 0 0
+vertex 0 10 0
+vertex 0 0 10
+endloop
+endfacet
+facet normal 0 0 0
+outer loop
+vertex 10 0 0
+vertex 0 10 0
+vertex 0 0 10
+endloop
+endfacet
+endsolid tetra
+`
+
+func TestQueueModelUpload(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("QUEUE_MODEL_DIR", dir)
+	op := testutil.NewOctoPrint(t)
+	h := newTestHandler(t, testutil.NewSpoolman(t), testutil.Printer{Name: "Mini", Server: op})
+
+	upload := func(query, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("POST", "/api/queue/models?"+query, strings.NewReader(body)))
+		return rec
+	}
+
+	if rec := upload("file=part.gcode", tetrahedronSTL); rec.Code != http.StatusBadRequest {
+		t.Errorf("gcode upload = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if rec := upload("file=part.stl", "garbage"); rec.Code != http.StatusBadRequest {
+		t.Errorf("garbage upload = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+
+	rec := upload("file=bracket.stl&printer_id=printer-1", tetrahedronSTL)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("upload = %d: %s", rec.Code, rec.Body)
+	}
+	var response struct {
+		Entry models.QueueEntry `json:"entry"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	entry := response.Entry
+	if entry.Status != models.QueueNeedsSlicing || entry.File != "bracket.stl" || entry.PreviewURL == "" {
+		t.Fatalf("entry = %+v", entry)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", entry.PreviewURL, nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "image/png" {
+		t.Errorf("GET %s = %d %s", entry.PreviewURL, rec.Code, rec.Header().Get("Content-Type"))
+	}
+
+	// An unsliced model can't be sent to a printer
+	h.dispatchOnce()
+	if printed := op.Printed(); len(printed) != 0 {
+		t.Errorf("dispatched %v while the model was unsliced", printed)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("DELETE", "/api/queue/"+entry.ID, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("remove = %d", rec.Code)
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Errorf("files left after removing the entry: %v", files)
+	}
+}
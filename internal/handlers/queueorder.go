@@ -0,0 +1,152 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/auth"
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/queue"
+)
+
+// errPriorityForbidden stops users below operator from jumping the queue
+var errPriorityForbidden = errors.New("only operators can raise or lower a job's priority")
+
+// checkPriority validates a requested priority class for the caller
+func (h *Handler) checkPriority(r *http.Request, priority string) error {
+	if priority == "" {
+		return nil
+	}
+	if !slices.Contains(models.Priorities, priority) {
+		return fmt.Errorf("priority must be one of %s", strings.Join(models.Priorities, ", "))
+	}
+	if priority != "normal" && auth.UserFromContext(r.Context()).Role < auth.RoleOperator {
+		return errPriorityForbidden
+	}
+	return nil
+}
+
+// writePriorityError answers a failed priority check
+func writePriorityError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errPriorityForbidden) {
+		writeError(w, http.StatusForbidden, err.Error())
+		return
+	}
+	writeError(w, http.StatusBadRequest, err.Error())
+}
+
+// handleQueueUpdate changes a waiting entry's priority class or schedule;
+// fields left out keep their values and an empty not_before clears it
+func (h *Handler) handleQueueUpdate(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Priority  *string `json:"priority"`
+		NotBefore *string `json:"not_before"`
+		OffPeak   *bool   `json:"off_peak"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Priority != nil {
+		if err := h.checkPriority(r, *req.Priority); err != nil {
+			writePriorityError(w, err)
+			return
+		}
+	}
+	var notBefore *time.Time
+	if req.NotBefore != nil || req.OffPeak != nil {
+		var err error
+		if notBefore, err = h.parseSchedule(deref(req.NotBefore), deref(req.OffPeak)); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	h.queueMu.Lock()
+	defer h.queueMu.Unlock()
+
+	entry, err := h.queueEntry(r, r.PathValue("id"))
+	if errors.Is(err, queue.ErrNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if entry.Status == models.QueueDispatched {
+		writeError(w, http.StatusConflict, "Entry has already started")
+		return
+	}
+
+	if req.Priority != nil {
+		entry.Priority = *req.Priority
+	}
+	if req.NotBefore != nil {
+		entry.NotBefore = notBefore
+	}
+	if req.OffPeak != nil {
+		entry.OffPeak = *req.OffPeak
+	}
+	if err := h.queue.Update(entry); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "ok",
+		"entry":  entry,
+	})
+}
+
+// handleQueueReorder puts the listed entries in the given order, as after a
+// drag in the queue view. Priority still comes first when dispatching.
+func (h *Handler) handleQueueReorder(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		IDs []string `json:"ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.IDs) == 0 {
+		writeError(w, http.StatusBadRequest, "ids must list the entries in their new order")
+		return
+	}
+
+	h.queueMu.Lock()
+	defer h.queueMu.Unlock()
+
+	for _, id := range req.IDs {
+		if _, err := h.queueEntry(r, id); err != nil {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("%s: %v", id, err))
+			return
+		}
+	}
+	if err := h.queue.Reorder(req.IDs); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.logger.Printf("%s reordered %d queue entries", auth.UserFromContext(r.Context()).Name, len(req.IDs))
+	h.handleQueueList(w, r)
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestQueuePriorityAndReorder(t *testing.T) {
+	t.Setenv("QUEUE_APPROVAL", "true")
+	t.Setenv("AUTH_USERS", "student:viewer:student-token,tech:operator:tech-token")
+	op := testutil.NewOctoPrint(t)
+	h := newTestHandler(t, testutil.NewSpoolman(t), testutil.Printer{Name: "Mini", Server: op})
+
+	ids := make(map[string]string)
+	add := func(file, priority string) {
+		t.Helper()
+		rec := doAs(h, "tech-token", "POST", "/api/queue", `{"file": "`+file+`", "priority": "`+priority+`"}`)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("add %s = %d: %s", file, rec.Code, rec.Body)
+		}
+		var response struct {
+			Entry models.QueueEntry `json:"entry"`
+		}
+		json.NewDecoder(rec.Body).Decode(&response)
+		ids[file] = response.Entry.ID
+	}
+	order := func() []string {
+		var response struct {
+			Queue []models.QueueEntry `json:"queue"`
+		}
+		json.NewDecoder(doAs(h, "tech-token", "GET", "/api/queue", "").Body).Decode(&response)
+		files := []string{}
+		for _, e := range response.Queue {
+			files = append(files, e.File)
+		}
+		return files
+	}
+
+	add("a.gcode", "")
+	add("b.gcode", "")
+	add("c.gcode", "")
+	add("rush.gcode", "urgent")
+	if got := order(); !slices.Equal(got, []string{"rush.gcode", "a.gcode", "b.gcode", "c.gcode"}) {
+		t.Errorf("order = %v, want the urgent job first", got)
+	}
+
+	if rec := doAs(h, "tech-token", "POST", "/api/queue", `{"file": "x.gcode", "priority": "asap"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown priority = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if rec := doAs(h, "student-token", "POST", "/api/queue", `{"file": "x.gcode", "priority": "urgent"}`); rec.Code != http.StatusForbidden {
+		t.Errorf("viewer jumping the queue = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+
+	// Swapping c and a leaves b where it was
+	if rec := doAs(h, "tech-token", "PATCH", "/api/queue/reorder", `{"ids": ["`+ids["c.gcode"]+`", "`+ids["a.gcode"]+`"]}`); rec.Code != http.StatusOK {
+		t.Fatalf("reorder = %d: %s", rec.Code, rec.Body)
+	}
+	if got := order(); !slices.Equal(got, []string{"rush.gcode", "c.gcode", "b.gcode", "a.gcode"}) {
+		t.Errorf("order after reorder = %v", got)
+	}
+	if rec := doAs(h, "tech-token", "PATCH", "/api/queue/reorder", `{"ids": ["9999999999"]}`); rec.Code != http.StatusNotFound {
+		t.Errorf("reordering a missing entry = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+
+	if rec := doAs(h, "tech-token", "PATCH", "/api/queue/"+ids["c.gcode"], `{"priority": "low"}`); rec.Code != http.StatusOK {
+		t.Fatalf("set priority = %d: %s", rec.Code, rec.Body)
+	}
+	if got := order(); !slices.Equal(got, []string{"rush.gcode", "b.gcode", "a.gcode", "c.gcode"}) {
+		t.Errorf("order after lowering c = %v", got)
+	}
+
+	h.dispatchOnce()
+	if printed := op.Printed(); !slices.Equal(printed, []string{"rush.gcode"}) {
+		t.Errorf("printed = %v, want the urgent job", printed)
+	}
+}
@@ -0,0 +1,227 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/wmarchesi123/go-3dprint-client/config"
+	"github.com/wmarchesi123/go-3dprint-client/octoprint"
+	"github.com/wmarchesi123/octodash/internal/auth"
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/store"
+)
+
+const (
+	checkpointsBucket = "checkpoints"
+	interruptedBucket = "interrupted"
+
+	// checkpointEvery is how often a running print's position is saved
+	checkpointEvery = 30 * time.Second
+)
+
+// recoveryTracker checkpoints running prints and flags any whose job
+// disappears without finishing or being cancelled. OctoPrint keeps a stopped
+// job selected, so a printer that comes back idle with no job at all most
+// likely rebooted mid-print. Both are persisted to survive our own restarts.
+type recoveryTracker struct {
+	store *store.Store
+
+	mu          sync.Mutex
+	checkpoints map[string]models.PrintCheckpoint
+	interrupted map[string]models.InterruptedPrint
+}
+
+func newRecoveryTracker(s *store.Store) (*recoveryTracker, error) {
+	checkpoints, err := store.List[models.PrintCheckpoint](s, checkpointsBucket)
+	if err != nil {
+		return nil, err
+	}
+	interrupted, err := store.List[models.InterruptedPrint](s, interruptedBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &recoveryTracker{
+		store:       s,
+		checkpoints: make(map[string]models.PrintCheckpoint),
+		interrupted: make(map[string]models.InterruptedPrint),
+	}
+	for _, cp := range checkpoints {
+		t.checkpoints[cp.PrinterID] = cp
+	}
+	for _, ip := range interrupted {
+		t.interrupted[ip.PrinterID] = ip
+	}
+	return t, nil
+}
+
+// due reports whether a printer running path needs a fresh checkpoint
+func (t *recoveryTracker) due(id, path string, now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	cp, ok := t.checkpoints[id]
+	return !ok || cp.Path != path || now.Sub(cp.At) >= checkpointEvery
+}
+
+// save records a running print's position; a new print clears any old alert
+func (t *recoveryTracker) save(cp models.PrintCheckpoint) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.checkpoints[cp.PrinterID] = cp
+	if _, ok := t.interrupted[cp.PrinterID]; ok {
+		delete(t.interrupted, cp.PrinterID)
+		if err := t.store.Delete(interruptedBucket, cp.PrinterID); err != nil {
+			return err
+		}
+	}
+	return t.store.Put(checkpointsBucket, cp.PrinterID, cp)
+}
+
+// pending reports whether a printer has a checkpoint awaiting its job's outcome
+func (t *recoveryTracker) pending(id string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	_, ok := t.checkpoints[id]
+	return ok
+}
+
+// settle resolves a printer's checkpoint once it stops printing: a job that is
+// gone is flagged as interrupted, otherwise the checkpoint is just dropped
+func (t *recoveryTracker) settle(id string, gone bool, at time.Time) (models.InterruptedPrint, bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	cp, ok := t.checkpoints[id]
+	if !ok {
+		return models.InterruptedPrint{}, false, nil
+	}
+	delete(t.checkpoints, id)
+	if err := t.store.Delete(checkpointsBucket, id); err != nil {
+		return models.InterruptedPrint{}, false, err
+	}
+	if !gone {
+		return models.InterruptedPrint{}, false, nil
+	}
+
+	ip := models.InterruptedPrint{PrintCheckpoint: cp, DetectedAt: at.UTC().Truncate(time.Second)}
+	t.interrupted[id] = ip
+	return ip, true, t.store.Put(interruptedBucket, id, ip)
+}
+
+// get returns a printer's interrupted print, if it hasn't been dismissed
+func (t *recoveryTracker) get(id string) (models.InterruptedPrint, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ip, ok := t.interrupted[id]
+	return ip, ok
+}
+
+// dismiss clears a printer's interrupted print, reporting whether there was one
+func (t *recoveryTracker) dismiss(id string) (models.InterruptedPrint, bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ip, ok := t.interrupted[id]
+	if !ok {
+		return ip, false, nil
+	}
+	delete(t.interrupted, id)
+	return ip, true, t.store.Delete(interruptedBucket, id)
+}
+
+// checkpoint saves how far a running print has got, with the layer and
+// height from DisplayLayerProgress when it is installed
+func (h *Handler) checkpoint(printer config.Printer, job *octoprint.JobResponse) {
+	now := h.clock.Now().UTC()
+	if !h.recovery.due(printer.ID, job.Job.File.Path, now) {
+		return
+	}
+
+	cp := models.PrintCheckpoint{
+		PrinterID:  printer.ID,
+		File:       job.Job.File.Display,
+		Path:       job.Job.File.Path,
+		Completion: job.Progress.Completion,
+		Filepos:    job.Progress.Filepos,
+		PrintTime:  job.Progress.PrintTime,
+		At:         now.Truncate(time.Second),
+	}
+	if layer, err := h.controlClient(printer.ID).LayerProgress(); err == nil {
+		cp.Layer, cp.Layers, cp.Height = layer.Layer, layer.Layers, layer.Height
+	}
+
+	if err := h.recovery.save(cp); err != nil {
+		h.logger.Printf("Failed to checkpoint print on %s: %v", printer.Name, err)
+	}
+}
+
+// trackRecovery settles a printer's checkpoint once it is ready again, and
+// shows any interrupted print on its card. Errors and disconnects wait, since
+// the printer may be powered back up while OctoPrint stays running.
+func (h *Handler) trackRecovery(printer config.Printer, client PrinterClient, status *models.PrinterStatus) {
+	ready := status.Status == "idle" || status.Status == "completed"
+	if ready && h.recovery.pending(printer.ID) {
+		if job, err := client.GetJob(); err == nil && job != nil {
+			ip, interrupted, err := h.recovery.settle(printer.ID, job.Job.File.Path == "", h.clock.Now())
+			if err != nil {
+				h.logger.Printf("Failed to record interrupted print on %s: %v", printer.Name, err)
+			}
+			if interrupted {
+				h.logger.Printf("%s came back without its job; %s may have been interrupted at %.1f%%", printer.Name, ip.File, ip.Completion)
+				h.emit(models.EventAlertRaised, printer.ID, map[string]interface{}{"kind": "interrupted", "interrupted": ip})
+			}
+		}
+	}
+
+	if ip, ok := h.recovery.get(printer.ID); ok {
+		status.Interrupted = &ip
+	}
+}
+
+func (h *Handler) handleInterruptedDismiss(w http.ResponseWriter, r *http.Request) {
+	printer, ok := h.findPrinter(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Printer not found")
+		return
+	}
+
+	ip, ok, err := h.recovery.dismiss(printer.ID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !ok {
+		writeError(w, http.StatusConflict, "No interrupted print to dismiss")
+		return
+	}
+
+	h.logger.Printf("%s dismissed the interrupted print of %s on %s", auth.UserFromContext(r.Context()).Name, ip.File, printer.Name)
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":      "ok",
+		"interrupted": ip,
+	})
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestInterruptedPrint(t *testing.T) {
+	sm := testutil.NewSpoolman(t)
+	op := testutil.NewOctoPrint(t)
+	op.SetPrinting("benchy.gcode", 42, 1800)
+	op.SetLayer(57, 120, 11.4)
+	h := newTestHandler(t, sm, testutil.Printer{Name: "Mini", Server: op})
+
+	getStatus(t, h)
+
+	// The printer rebooted: OctoPrint is back, idle, with no job selected
+	op.SetIdle()
+	p := getStatus(t, h)["printer-1"]
+	if p.Interrupted == nil || p.Interrupted.File != "benchy.gcode" || p.Interrupted.Layer != 57 || p.Interrupted.Height != 11.4 {
+		t.Fatalf("interrupted = %+v", p.Interrupted)
+	}
+	if p.Interrupted.Completion != 42 {
+		t.Errorf("interrupted completion = %v, want 42", p.Interrupted.Completion)
+	}
+
+	dismiss := func() int {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("DELETE", "/api/printers/printer-1/interrupted", nil))
+		return rec.Code
+	}
+	if code := dismiss(); code != http.StatusOK {
+		t.Fatalf("dismiss = %d", code)
+	}
+	if p := getStatus(t, h)["printer-1"]; p.Interrupted != nil {
+		t.Errorf("interrupted after dismiss = %+v", p.Interrupted)
+	}
+	if code := dismiss(); code != http.StatusConflict {
+		t.Errorf("second dismiss = %d, want %d", code, http.StatusConflict)
+	}
+
+	// A print that runs to the end keeps its job selected and raises nothing
+	op.SetPrinting("gear.gcode", 90, 300)
+	getStatus(t, h)
+	op.SetFinished("gear.gcode")
+	if p := getStatus(t, h)["printer-1"]; p.Interrupted != nil {
+		t.Errorf("finished print flagged as interrupted: %+v", p.Interrupted)
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/auth"
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/reservations"
+)
+
+// handleReservationList returns upcoming reservations, optionally for one ?printer_id=
+func (h *Handler) handleReservationList(w http.ResponseWriter, r *http.Request) {
+	list, err := h.visibleReservations(r)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":       "ok",
+		"reservations": list,
+	})
+}
+
+// visibleReservations lists upcoming reservations on printers the caller may see
+func (h *Handler) visibleReservations(r *http.Request) ([]models.Reservation, error) {
+	upcoming, err := h.reservations.Upcoming(h.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	scope := h.tenancy(r)
+	printerID := r.URL.Query().Get("printer_id")
+	list := []models.Reservation{}
+	for _, res := range upcoming {
+		if scope.printer(res.PrinterID) && (printerID == "" || res.PrinterID == printerID) {
+			list = append(list, res)
+		}
+	}
+	return list, nil
+}
+
+// handleReservationCreate books a printer for the caller
+func (h *Handler) handleReservationCreate(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		PrinterID string    `json:"printer_id"`
+		Start     time.Time `json:"start"`
+		End       time.Time `json:"end"`
+		Note      string    `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if _, ok := h.findPrinter(req.PrinterID); !ok || !h.tenancy(r).printer(req.PrinterID) {
+		writeError(w, http.StatusBadRequest, "Unknown printer")
+		return
+	}
+
+	res, err := h.reservations.Add(models.Reservation{
+		PrinterID: req.PrinterID,
+		User:      auth.UserFromContext(r.Context()).Name,
+		Note:      strings.TrimSpace(req.Note),
+		Start:     req.Start,
+		End:       req.End,
+	}, h.clock.Now())
+	switch {
+	case errors.Is(err, reservations.ErrInvalidSlot):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, reservations.ErrConflict):
+		writeError(w, http.StatusConflict, err.Error())
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	default:
+		writeJSON(w, http.StatusCreated, map[string]interface{}{
+			"status":      "ok",
+			"reservation": res,
+		})
+	}
+}
+
+// handleReservationCancel cancels a reservation; only admins may cancel other people's
+func (h *Handler) handleReservationCancel(w http.ResponseWriter, r *http.Request) {
+	res, err := h.reservations.Get(r.PathValue("id"))
+	if errors.Is(err, reservations.ErrNotFound) || (err == nil && !h.tenancy(r).printer(res.PrinterID)) {
+		writeError(w, http.StatusNotFound, reservations.ErrNotFound.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	user := auth.UserFromContext(r.Context())
+	if res.User != user.Name && user.Role < auth.RoleAdmin {
+		writeError(w, http.StatusForbidden, "Only admins can cancel other users' reservations")
+		return
+	}
+	if err := h.reservations.Cancel(res.ID); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"status": "ok"})
+}
+
+// handleCalendar serves upcoming reservations as an iCalendar feed for calendar apps
+func (h *Handler) handleCalendar(w http.ResponseWriter, r *http.Request) {
+	list, err := h.visibleReservations(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	const stamp = "20060102T150405Z"
+	var b strings.Builder
+	line := func(format string, args ...interface{}) {
+		fmt.Fprintf(&b, format+"\r\n", args...)
+	}
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//OctoDash//Reservations//EN")
+	line("X-WR-CALNAME:Printer reservations")
+	for _, res := range list {
+		name := res.PrinterID
+		if printer, ok := h.findPrinter(res.PrinterID); ok {
+			name = printer.Name
+		}
+		line("BEGIN:VEVENT")
+		line("UID:reservation-%s@octodash", res.ID)
+		line("DTSTAMP:%s", res.CreatedAt.UTC().Format(stamp))
+		line("DTSTART:%s", res.Start.UTC().Format(stamp))
+		line("DTEND:%s", res.End.UTC().Format(stamp))
+		line("SUMMARY:%s", icalText(name+" reserved by "+res.User))
+		if res.Note != "" {
+			line("DESCRIPTION:%s", icalText(res.Note))
+		}
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Write([]byte(b.String()))
+}
+
+// icalText escapes a value for an iCalendar TEXT property
+var icalText = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace
+
+// addReservations shows each printer's running or next reservation on its card
+func (h *Handler) addReservations(printers []*models.PrinterStatus, now time.Time) {
+	upcoming, err := h.reservations.Upcoming(now)
+	if err != nil {
+		h.logger.Printf("Failed to load reservations: %v", err)
+		return
+	}
+	for _, p := range printers {
+		p.Reservation = nil
+		for i, res := range upcoming {
+			if res.PrinterID == p.ID {
+				p.Reservation = &upcoming[i]
+				break
+			}
+		}
+	}
+}
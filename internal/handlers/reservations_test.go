@@ -0,0 +1,99 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestReservations(t *testing.T) {
+	t.Setenv("AUTH_USERS", "ana:operator:ana-token,ben:operator:ben-token")
+	op := testutil.NewOctoPrint(t)
+	h := newTestHandler(t, testutil.NewSpoolman(t), testutil.Printer{Name: "Mini", Server: op})
+
+	book := func(token string, start, end time.Time) *httptest.ResponseRecorder {
+		body := fmt.Sprintf(`{"printer_id": "printer-1", "start": %q, "end": %q, "note": "robot arm"}`,
+			start.Format(time.RFC3339), end.Format(time.RFC3339))
+		return doAs(h, token, "POST", "/api/reservations", body)
+	}
+
+	now := time.Now()
+	rec := book("ana-token", now.Add(-time.Minute), now.Add(time.Hour))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("book = %d: %s", rec.Code, rec.Body)
+	}
+	var created struct {
+		Reservation models.Reservation `json:"reservation"`
+	}
+	json.NewDecoder(rec.Body).Decode(&created)
+
+	if rec := book("ben-token", now.Add(30*time.Minute), now.Add(2*time.Hour)); rec.Code != http.StatusConflict {
+		t.Errorf("overlapping booking = %d, want %d", rec.Code, http.StatusConflict)
+	}
+	if rec := book("ben-token", now.Add(2*time.Hour), now.Add(time.Hour)); rec.Code != http.StatusBadRequest {
+		t.Errorf("backwards booking = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if rec := book("ben-token", now.Add(time.Hour), now.Add(2*time.Hour)); rec.Code != http.StatusCreated {
+		t.Errorf("back-to-back booking = %d, want %d", rec.Code, http.StatusCreated)
+	}
+
+	if p := getStatus(t, h)["printer-1"]; p.Reservation == nil || p.Reservation.User != "ana" {
+		t.Errorf("card reservation = %+v, want ana's", p.Reservation)
+	}
+
+	rec = doAs(h, "ana-token", "GET", "/calendar.ics", "")
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/calendar") {
+		t.Errorf("calendar content type = %q", ct)
+	}
+	if body := rec.Body.String(); strings.Count(body, "BEGIN:VEVENT") != 2 || !strings.Contains(body, "SUMMARY:Mini reserved by ana") {
+		t.Errorf("calendar feed:\n%s", body)
+	}
+
+	// Ben's job waits while Ana holds the printer; hers goes straight on
+	if rec := doAs(h, "ben-token", "POST", "/api/queue", `{"file": "gear.gcode"}`); rec.Code != http.StatusCreated {
+		t.Fatalf("queue add = %d", rec.Code)
+	}
+	h.dispatchOnce()
+	if got := op.Printed(); len(got) != 0 {
+		t.Fatalf("printed %v during another user's reservation", got)
+	}
+	if rec := doAs(h, "ana-token", "POST", "/api/queue", `{"file": "arm.gcode"}`); rec.Code != http.StatusCreated {
+		t.Fatalf("queue add = %d", rec.Code)
+	}
+	h.dispatchOnce()
+	if got := op.Printed(); len(got) != 1 || got[0] != "arm.gcode" {
+		t.Errorf("printed %v, want the holder's arm.gcode", got)
+	}
+
+	if rec := doAs(h, "ben-token", "DELETE", "/api/reservations/"+created.Reservation.ID, ""); rec.Code != http.StatusForbidden {
+		t.Errorf("cancelling someone else's reservation = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+	if rec := doAs(h, "ana-token", "DELETE", "/api/reservations/"+created.Reservation.ID, ""); rec.Code != http.StatusOK {
+		t.Errorf("cancel = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/wmarchesi123/octodash/internal/audit"
+	"github.com/wmarchesi123/octodash/internal/events"
+	"github.com/wmarchesi123/octodash/internal/jobs"
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/retention"
+	"github.com/wmarchesi123/octodash/internal/usage"
+	"github.com/wmarchesi123/octodash/internal/webhooks"
+)
+
+// retentionDatasets says where each dataset in models.RetentionDatasets is stored
+func retentionDatasets() []retention.Dataset {
+	return []retention.Dataset{
+		{Name: models.DataHistory, Prefix: events.BucketPrefix, TimeField: "time"},
+		{Name: models.DataEvents, Bucket: eventsBucket, TimeField: "time"},
+		{Name: models.DataAudit, Bucket: audit.Bucket, TimeField: "time"},
+		{Name: models.DataDeliveries, Bucket: webhooks.Bucket, TimeField: "created_at", Keep: pendingDelivery},
+		{Name: models.DataUsage, Bucket: usage.Bucket, TimeField: "finished_at"},
+		{Name: models.DataJobs, Bucket: jobs.Bucket, TimeField: "finished_at"},
+	}
+}
+
+// pendingDelivery spares webhook deliveries that are still being retried
+func pendingDelivery(value []byte) bool {
+	var d models.WebhookDelivery
+	return json.Unmarshal(value, &d) == nil && d.Status == models.DeliveryPending
+}
+
+func (h *Handler) handleRetention(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":   "ok",
+		"interval": h.settings.Retention.Interval.String(),
+		"datasets": h.janitor.Status(),
+	})
+}
+
+// handleRetentionRun prunes now rather than waiting for RETENTION_INTERVAL
+func (h *Handler) handleRetentionRun(w http.ResponseWriter, r *http.Request) {
+	h.janitor.Prune()
+	h.audit(r, "retention.run", "", "")
+	h.handleRetention(w, r)
+}
@@ -0,0 +1,61 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestRetention(t *testing.T) {
+	t.Setenv("RETENTION_EVENTS_MAX_AGE", "24h")
+	h := newTestHandler(t, testutil.NewSpoolman(t), testutil.Printer{Name: "Mini", Server: testutil.NewOctoPrint(t)})
+
+	now := time.Now().UTC()
+	for i, at := range []time.Time{now.Add(-48 * time.Hour), now.Add(-time.Hour)} {
+		id, _ := h.store.NextID(eventsBucket)
+		h.store.Put(eventsBucket, id, models.Event{ID: id, Type: models.EventJobStarted, Time: at, Data: i})
+	}
+
+	rec := postJSON(h, "/api/admin/retention/run", "")
+	var response struct {
+		Datasets []models.RetentionStatus `json:"datasets"`
+	}
+	json.NewDecoder(rec.Body).Decode(&response)
+	if rec.Code != http.StatusOK || len(response.Datasets) != len(models.RetentionDatasets) {
+		t.Fatalf("POST /api/admin/retention/run = %d %s", rec.Code, rec.Body)
+	}
+	for _, st := range response.Datasets {
+		if st.Dataset == models.DataEvents && (st.Records != 1 || st.PrunedRecords < 1 || st.MaxAge != "24h0m0s") {
+			t.Errorf("events status = %+v, want the old event pruned", st)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/api/admin/retention", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("GET /api/admin/retention = %d", rec.Code)
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/wmarchesi123/go-3dprint-client/config"
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/rules"
+	"github.com/wmarchesi123/octodash/internal/settings"
+)
+
+// newRules compiles the automation rules, checking the printers they name
+func newRules(cfg *config.Config, configured []settings.RuleSettings) (*rules.Engine, error) {
+	for _, r := range configured {
+		for _, id := range r.Printers {
+			if !slices.ContainsFunc(cfg.Printers, func(p config.Printer) bool { return p.ID == id }) {
+				return nil, fmt.Errorf("rule %s: unknown printer %q", r.Name, id)
+			}
+		}
+	}
+	return rules.New(configured)
+}
+
+// runRules fires the rules whose conditions have held long enough on each printer
+func (h *Handler) runRules(printers []*models.PrinterStatus, now time.Time) {
+	for _, p := range printers {
+		firing, err := h.rules.Observe(p, now)
+		if err != nil {
+			h.logger.Printf("Failed to evaluate rules: %v", err)
+		}
+		for _, r := range firing {
+			h.fireRule(r, p)
+		}
+	}
+}
+
+// fireRule sends a rule's gcode and raises its alert
+func (h *Handler) fireRule(r rules.Rule, p *models.PrinterStatus) {
+	h.logger.Printf("Rule %s matched on %s: %s", r.Name, p.Name, r.When)
+
+	if len(r.Gcode) > 0 {
+		_, err := h.commands.run(p.ID, "rule", strings.Join(r.Gcode, "\n"), "octodash", func() error {
+			return h.controlClient(p.ID).SendCommands(r.Gcode...)
+		})
+		if err != nil {
+			h.logger.Printf("Rule %s failed to send gcode to %s: %v", r.Name, p.Name, err)
+		} else {
+			h.audit(nil, "rule.gcode", p.ID, fmt.Sprintf("%s sent %s", r.Name, strings.Join(r.Gcode, ", ")))
+		}
+	}
+	if r.Notify {
+		h.emit(models.EventAlertRaised, p.ID, map[string]interface{}{
+			"kind": "rule",
+			"rule": r.ID,
+			"name": r.Name,
+			"when": r.When.String(),
+		})
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestRules(t *testing.T) {
+	t.Setenv("RULE_1_NAME", "Cool idle bed")
+	t.Setenv("RULE_1_WHEN", "bed > 100 and status == idle")
+	t.Setenv("RULE_1_FOR", "10m")
+	t.Setenv("RULE_1_GCODE", "M140 S0, M104 S0")
+	op := testutil.NewOctoPrint(t)
+	op.SetTemperatures(25, 0, 105, 110)
+	clock := &stepClock{now: time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)}
+	h := newHandlerWithConfig(t, testutil.Config(testutil.NewSpoolman(t), testutil.Printer{Name: "Mini", Server: op}), WithClock(clock))
+
+	getStatus(t, h)
+	clock.advance(5 * time.Minute)
+	getStatus(t, h)
+	if cmds := op.Commands(); len(cmds) != 0 {
+		t.Fatalf("sent %v before the bed was hot for 10m", cmds)
+	}
+
+	clock.advance(5 * time.Minute)
+	getStatus(t, h)
+	cmds := op.Commands()
+	if len(cmds) != 1 || !strings.Contains(cmds[0], `"M140 S0","M104 S0"`) {
+		t.Fatalf("commands = %v, want the cooldown gcode once", cmds)
+	}
+
+	entries, err := h.auditTrail.List("printer-1", 0)
+	if err != nil || len(entries) != 1 || entries[0].Action != "rule.gcode" {
+		t.Errorf("audit = %+v, %v", entries, err)
+	}
+}
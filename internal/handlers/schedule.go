@@ -0,0 +1,63 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+)
+
+// parseSchedule reads an entry's "start no earlier than" time, in RFC 3339,
+// and checks off-peak hours are configured when an entry asks for them
+func (h *Handler) parseSchedule(notBefore string, offPeak bool) (*time.Time, error) {
+	if offPeak && h.settings.Queue.OffPeak == nil {
+		return nil, errors.New("off-peak hours are not configured (QUEUE_OFF_PEAK)")
+	}
+	if notBefore == "" {
+		return nil, nil
+	}
+	at, err := time.Parse(time.RFC3339, notBefore)
+	if err != nil {
+		return nil, errors.New("not_before must be an RFC 3339 time, e.g. 2025-06-01T22:00:00Z")
+	}
+	at = at.UTC()
+	return &at, nil
+}
+
+// deref returns the value a pointer holds, or the zero value for nil
+func deref[T any](p *T) T {
+	var zero T
+	if p == nil {
+		return zero
+	}
+	return *p
+}
+
+// startable reports whether an entry's schedule lets it start at now
+func (h *Handler) startable(e models.QueueEntry, now time.Time) bool {
+	if e.NotBefore != nil && now.Before(*e.NotBefore) {
+		return false
+	}
+	if e.OffPeak && h.settings.Queue.OffPeak != nil && !h.settings.Queue.OffPeak.Contains(now.In(h.settings.Timezone)) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,66 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestScheduledStarts(t *testing.T) {
+	t.Setenv("QUEUE_OFF_PEAK", "23:00-07:00")
+	op := testutil.NewOctoPrint(t)
+	clock := &stepClock{now: time.Date(2025, 3, 1, 20, 0, 0, 0, time.UTC)}
+	h := newHandlerWithConfig(t, testutil.Config(testutil.NewSpoolman(t), testutil.Printer{Name: "Mini", Server: op}), WithClock(clock))
+
+	if rec := postJSON(h, "/api/queue", `{"file": "x.gcode", "not_before": "tonight"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("bad start time = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	for _, body := range []string{
+		`{"file": "night.gcode", "off_peak": true}`,
+		`{"file": "later.gcode", "not_before": "2025-03-01T21:00:00Z"}`,
+	} {
+		if rec := postJSON(h, "/api/queue", body); rec.Code != http.StatusCreated {
+			t.Fatalf("add %s = %d: %s", body, rec.Code, rec.Body)
+		}
+	}
+
+	dispatchAt := func(hour, minute int) {
+		clock.set(time.Date(2025, 3, 1, hour, minute, 0, 0, time.UTC))
+		op.SetIdle()
+		h.dispatchOnce()
+	}
+
+	dispatchAt(20, 0)
+	if printed := op.Printed(); len(printed) != 0 {
+		t.Fatalf("printed %v before any start time", printed)
+	}
+	dispatchAt(21, 30)
+	if printed := op.Printed(); !slices.Equal(printed, []string{"later.gcode"}) {
+		t.Fatalf("printed %v at 21:30, want later.gcode", printed)
+	}
+	dispatchAt(23, 5)
+	if printed := op.Printed(); !slices.Equal(printed, []string{"later.gcode", "night.gcode"}) {
+		t.Errorf("printed %v in off-peak hours, want night.gcode too", printed)
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wmarchesi123/go-3dprint-client/config"
+	"github.com/wmarchesi123/go-3dprint-client/spoolman"
+	"github.com/wmarchesi123/octodash/internal/models"
+)
+
+// maxSearchResults caps each result section so a short query can't return the world
+const maxSearchResults = 25
+
+func (h *Handler) handleSearch(w http.ResponseWriter, r *http.Request) {
+	query := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("q")))
+	if query == "" {
+		writeError(w, http.StatusBadRequest, "Missing search query")
+		return
+	}
+
+	results := &models.SearchResults{
+		Files:   []models.FileMatch{},
+		Jobs:    []models.JobMatch{},
+		History: []models.HistoryMatch{},
+		Spools:  []map[string]interface{}{},
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, printer := range h.printersMatching(r, "") {
+		wg.Add(1)
+		go func(p config.Printer) {
+			defer wg.Done()
+
+			files, jobs, err := h.searchPrinter(p, query)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				results.Errors = append(results.Errors, fmt.Sprintf("%s: %v", p.Name, err))
+			}
+			results.Files = append(results.Files, files...)
+			results.Jobs = append(results.Jobs, jobs...)
+		}(printer)
+	}
+
+	for _, src := range h.visibleSpoolSources(r) {
+		wg.Add(1)
+		go func(src spoolSource) {
+			defer wg.Done()
+
+			spools, err := h.searchSpools(src, query)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				results.Errors = append(results.Errors, fmt.Sprintf("%s: %v", h.spoolmanLabel(src), err))
+			}
+			results.Spools = append(results.Spools, spools...)
+		}(src)
+	}
+
+	wg.Wait()
+
+	history, err := h.searchHistory(r, query)
+	if err != nil {
+		results.Errors = append(results.Errors, fmt.Sprintf("job history: %v", err))
+	}
+	results.History = history
+
+	if len(results.Files) > maxSearchResults {
+		results.Files = results.Files[:maxSearchResults]
+	}
+	if len(results.Spools) > maxSearchResults {
+		results.Spools = results.Spools[:maxSearchResults]
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "ok",
+		"query":   query,
+		"results": results,
+	})
+}
+
+func (h *Handler) searchPrinter(p config.Printer, query string) ([]models.FileMatch, []models.JobMatch, error) {
+	var jobs []models.JobMatch
+	if job, err := h.printerClient(p.ID).GetJob(); err == nil && job.Job.File.Display != "" {
+		if strings.Contains(strings.ToLower(job.Job.File.Display), query) {
+			jobs = append(jobs, models.JobMatch{
+				PrinterID:   p.ID,
+				PrinterName: p.Name,
+				FileName:    job.Job.File.Display,
+				Completion:  job.Progress.Completion,
+			})
+		}
+	}
+
+	files, err := h.controlClient(p.ID).ListFiles()
+	if err != nil {
+		return nil, jobs, err
+	}
+
+	var matches []models.FileMatch
+	for _, f := range files {
+		name := f.Display
+		if name == "" {
+			name = f.Name
+		}
+		if !strings.Contains(strings.ToLower(name), query) {
+			continue
+		}
+
+		matches = append(matches, models.FileMatch{
+			PrinterID:   p.ID,
+			PrinterName: p.Name,
+			Name:        name,
+			Path:        f.Path,
+			Origin:      f.Origin,
+			Size:        f.Size,
+			Date:        f.Date,
+		})
+	}
+
+	return matches, jobs, nil
+}
+
+// searchHistory finds finished jobs the caller's teams can see, newest first
+func (h *Handler) searchHistory(r *http.Request, query string) ([]models.HistoryMatch, error) {
+	jobs, err := h.visibleJobs(r, "", time.Time{})
+	if err != nil {
+		return []models.HistoryMatch{}, err
+	}
+
+	matches := []models.HistoryMatch{}
+	for _, j := range jobs {
+		if !strings.Contains(strings.ToLower(j.File), query) {
+			continue
+		}
+
+		printer, _ := h.findPrinter(j.PrinterID)
+		matches = append(matches, models.HistoryMatch{
+			ID:          j.ID,
+			PrinterID:   j.PrinterID,
+			PrinterName: printer.Name,
+			FileName:    j.File,
+			Result:      j.Result,
+			FinishedAt:  j.FinishedAt,
+		})
+		if len(matches) == maxSearchResults {
+			break
+		}
+	}
+	return matches, nil
+}
+
+func (h *Handler) searchSpools(src spoolSource, query string) ([]map[string]interface{}, error) {
+	spools, err := src.client.GetAllSpools()
+	if err != nil {
+		return []map[string]interface{}{}, err
+	}
+
+	matches := []map[string]interface{}{}
+	for i := range spools {
+		spool := &spools[i]
+		if spool.Archived || !spoolMatches(spool, query) {
+			continue
+		}
+
+		matches = append(matches, h.spoolInfo(src, spool))
+		if len(matches) == maxSearchResults {
+			break
+		}
+	}
+	return matches, nil
+}
+
+func spoolMatches(spool *spoolman.Spool, query string) bool {
+	fields := []string{
+		spool.Filament.Name,
+		spool.Filament.Material,
+		spool.Filament.Vendor.Name,
+		spool.Location,
+	}
+	for _, f := range fields {
+		if strings.Contains(strings.ToLower(f), query) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestSearch(t *testing.T) {
+	t.Setenv("TEAMS_ENABLED", "true")
+	t.Setenv("AUTH_USERS", "staff:admin:staff-token,ana:operator:ana-token,ben:operator:ben-token")
+	mini, mk4 := testutil.NewOctoPrint(t), testutil.NewOctoPrint(t)
+	h := newTestHandler(t, testutil.NewSpoolman(t, testutil.Spool(3, "Prusament", "PETG", "ff0000", 700)),
+		testutil.Printer{Name: "Mini", Server: mini},
+		testutil.Printer{Name: "MK4", Server: mk4})
+	doAs(h, "staff-token", "PUT", "/api/admin/teams/robotics", `{"printers": ["printer-1"], "members": ["ana"]}`)
+	doAs(h, "staff-token", "PUT", "/api/admin/teams/art", `{"printers": ["printer-2"], "members": ["ben"]}`)
+
+	// Anonymous callers see no printers once teams are on
+	poll := func() { doAs(h, "staff-token", "GET", "/api/status", "") }
+
+	// Both printers finish a bracket, then the Mini starts on a gear
+	for _, op := range []*testutil.OctoPrint{mini, mk4} {
+		op.SetPrinting("Bracket.gcode", 50, 600)
+	}
+	poll()
+	for _, op := range []*testutil.OctoPrint{mini, mk4} {
+		op.SetFinished("Bracket.gcode")
+	}
+	poll()
+	poll()
+	mini.SetPrinting("gear.gcode", 20, 900)
+	poll()
+
+	search := func(token, q string) models.SearchResults {
+		t.Helper()
+		rec := doAs(h, token, "GET", "/api/search?q="+q, "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("search %q = %d: %s", q, rec.Code, rec.Body)
+		}
+		var response struct {
+			Results models.SearchResults `json:"results"`
+		}
+		json.Unmarshal(rec.Body.Bytes(), &response)
+		return response.Results
+	}
+
+	got := search("staff-token", "bracket")
+	if len(got.History) != 2 {
+		t.Errorf("history matches = %+v, want the bracket from both printers", got.History)
+	}
+	for _, m := range got.History {
+		if m.FileName != "Bracket.gcode" || m.Result != models.JobCompleted || m.PrinterName == "" || m.ID == "" {
+			t.Errorf("history match = %+v", m)
+		}
+	}
+
+	got = search("ana-token", "BRACKET")
+	if len(got.History) != 1 || got.History[0].PrinterID != "printer-1" {
+		t.Errorf("history matches for a team member = %+v, want only their team's printer", got.History)
+	}
+
+	got = search("ana-token", "gear")
+	if len(got.Jobs) != 1 || got.Jobs[0].PrinterName != "Mini" || got.Jobs[0].Completion != 20 {
+		t.Errorf("running job matches = %+v", got.Jobs)
+	}
+	if len(got.History) != 0 {
+		t.Errorf("a running job matched history: %+v", got.History)
+	}
+	if got := search("ben-token", "gear"); len(got.Jobs) != 0 {
+		t.Errorf("other team's running job matched: %+v", got.Jobs)
+	}
+
+	got = search("staff-token", "petg")
+	if len(got.Spools) != 1 || got.Spools[0]["material"] != "PETG" {
+		t.Errorf("spool matches = %v", got.Spools)
+	}
+
+	if rec := doAs(h, "staff-token", "GET", "/api/search?q=+", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("blank query = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/wmarchesi123/octodash/internal/middleware"
+	"github.com/wmarchesi123/octodash/web"
+)
+
+// securityHeaders applies SECURITY_* settings; only the /embed pages may be
+// framed by other sites
+func (h *Handler) securityHeaders() middleware.Middleware {
+	s := h.settings.Security
+	policy := s.CSP
+	switch policy {
+	case "":
+		policy = h.defaultPolicy()
+	case "off":
+		policy = ""
+	}
+
+	return middleware.SecurityHeaders(middleware.Security{
+		Policy:         policy,
+		FramePaths:     []string{"/embed/"},
+		FrameAncestors: s.FrameAncestors,
+		ReferrerPolicy: s.ReferrerPolicy,
+		HSTSMaxAge:     s.HSTSMaxAge,
+	})
+}
+
+// defaultPolicy allows scripts from this server, but none inline, and eval,
+// which Alpine needs for its directives. Images
+// may come from anywhere since print thumbnails load straight from OctoPrint.
+// With SSO on, the browser logs in to each OctoPrint itself, so their origins
+// are allowed to connect.
+func (h *Handler) defaultPolicy() string {
+	scripts := []string{"'self'", "'unsafe-eval'"}
+	for _, lib := range web.Libraries {
+		// Libraries that aren't vendored load from their CDN
+		if u, err := url.Parse(h.libraries[lib.Name].Src); err == nil && u.Host != "" {
+			scripts = append(scripts, u.Scheme+"://"+u.Host)
+		}
+	}
+
+	connect := []string{"'self'"}
+	if h.settings.SSO.Enabled {
+		for _, p := range h.config.Printers {
+			if u, err := url.Parse(p.OctoPrintURL); err == nil && u.Host != "" {
+				if origin := u.Scheme + "://" + u.Host; !slices.Contains(connect, origin) {
+					connect = append(connect, origin)
+				}
+			}
+		}
+	}
+
+	return strings.Join([]string{
+		"default-src 'self'",
+		"script-src " + strings.Join(scripts, " "),
+		"style-src 'self' 'unsafe-inline'",
+		"img-src 'self' data: blob: http: https:",
+		"connect-src " + strings.Join(connect, " "),
+		"object-src 'none'",
+		"base-uri 'self'",
+		"form-action 'self'",
+	}, "; ")
+}
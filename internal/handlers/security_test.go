@@ -0,0 +1,87 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestSecurityHeaders(t *testing.T) {
+	printer := testutil.Printer{Name: "Mini", Server: testutil.NewOctoPrint(t)}
+	get := func(h *Handler, path string, header ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		for i := 0; i+1 < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	h := newTestHandler(t, testutil.NewSpoolman(t), printer)
+	rec := get(h, "/")
+	csp := rec.Header().Get("Content-Security-Policy")
+	scripts := regexp.MustCompile(`script-src [^;]*`).FindString(csp)
+	if !strings.HasPrefix(scripts, "script-src 'self'") || strings.Contains(scripts, "'unsafe-inline'") {
+		t.Errorf("CSP = %q, want scripts limited to this server", csp)
+	}
+	// The only inline block is data, which the policy doesn't need to allow
+	for _, tag := range regexp.MustCompile(`<script[^>]*>`).FindAllString(rec.Body.String(), -1) {
+		if !strings.Contains(tag, "src=") && !strings.Contains(tag, `type="application/json"`) {
+			t.Errorf("dashboard has inline script %s", tag)
+		}
+	}
+	if !strings.Contains(csp, "frame-ancestors 'none'") || rec.Header().Get("X-Frame-Options") != "DENY" {
+		t.Errorf("dashboard may be framed: CSP = %q, X-Frame-Options = %q", csp, rec.Header().Get("X-Frame-Options"))
+	}
+	if got := rec.Header().Get("Referrer-Policy"); got != "same-origin" {
+		t.Errorf("Referrer-Policy = %q", got)
+	}
+	if got := rec.Header().Get("Strict-Transport-Security"); got != "" {
+		t.Errorf("HSTS without being enabled = %q", got)
+	}
+
+	rec = get(h, "/embed/printer-1")
+	if !strings.Contains(rec.Header().Get("Content-Security-Policy"), "frame-ancestors *") || rec.Header().Get("X-Frame-Options") != "" {
+		t.Errorf("embed cannot be framed: CSP = %q, X-Frame-Options = %q",
+			rec.Header().Get("Content-Security-Policy"), rec.Header().Get("X-Frame-Options"))
+	}
+
+	t.Setenv("SECURITY_CSP", "off")
+	t.Setenv("SECURITY_FRAME_ANCESTORS", "https://wiki.example.com")
+	t.Setenv("SECURITY_HSTS_MAX_AGE", "24h")
+	h = newTestHandler(t, testutil.NewSpoolman(t), printer)
+	if got := get(h, "/").Header().Get("Content-Security-Policy"); got != "" {
+		t.Errorf("CSP when off = %q", got)
+	}
+	if got := get(h, "/", "X-Forwarded-Proto", "https").Header().Get("Strict-Transport-Security"); got != "max-age=86400" {
+		t.Errorf("HSTS over HTTPS = %q", got)
+	}
+	if got := get(h, "/").Header().Get("Strict-Transport-Security"); got != "" {
+		t.Errorf("HSTS over plain HTTP = %q", got)
+	}
+	if got := get(h, "/embed/printer-1").Header().Get("X-Frame-Options"); got != "" {
+		t.Errorf("embed X-Frame-Options = %q", got)
+	}
+}
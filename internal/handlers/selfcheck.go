@@ -0,0 +1,155 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"html/template"
+	"net/http"
+	"net/url"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/settings"
+)
+
+const diagnosticsTemplate = `<!DOCTYPE html>
+<html>
+<head>
+    <title>Diagnostics - OctoDash</title>
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+    <link rel="stylesheet" href="{{asset "style.css"}}">
+</head>
+<body class="diagnostics-page">
+    <h1>OctoDash diagnostics</h1>
+    <p class="diagnostics-status" id="diagnostics-status">Loading...</p>
+    <div id="diagnostics"></div>
+    <script src="{{asset "diagnostics.js"}}"></script>
+</body>
+</html>
+`
+
+// handleDiagnosticsPage serves the shell of the diagnostics view; the report
+// itself comes from /api/diagnostics so it stays behind the admin role
+func (h *Handler) handleDiagnosticsPage(w http.ResponseWriter, r *http.Request) {
+	tmpl, err := template.New("diagnostics").Funcs(template.FuncMap{"asset": h.assets.URL}).Parse(diagnosticsTemplate)
+	if err != nil {
+		http.Error(w, "Template error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	tmpl.Execute(w, nil)
+}
+
+func (h *Handler) handleDiagnostics(w http.ResponseWriter, r *http.Request) {
+	now := h.clock.Now().UTC()
+
+	report := models.ServerDiagnostics{
+		Server:        h.meta.server,
+		UptimeSeconds: int(now.Sub(h.meta.server.StartedAt).Seconds()),
+		Runtime:       runtimeStats(),
+		Poller:        h.meta.pollerStats(),
+		Upstreams:     h.checkUpstreams(),
+		Printers:      make([]models.PrinterHealth, 0, len(h.config.Printers)),
+		Database:      h.databaseHealth(),
+		Config: models.ConfigSummary{
+			Printers:    len(h.config.Printers),
+			DataDir:     h.settings.DataDir,
+			Demo:        h.settings.Demo.Enabled,
+			AuthEnabled: h.auth.Enabled(),
+			Environment: settings.RedactedEnviron(),
+		},
+	}
+	for _, p := range h.config.Printers {
+		report.Printers = append(report.Printers, h.meta.printerHealth(p.ID, p.Name))
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":      "ok",
+		"diagnostics": report,
+	})
+}
+
+// checkUpstreams contacts every OctoPrint and Spoolman concurrently and times the answers
+func (h *Handler) checkUpstreams() []models.UpstreamCheck {
+	checks := make([]models.UpstreamCheck, len(h.config.Printers)+len(h.spoolSources))
+
+	probe := func(i int, check models.UpstreamCheck, call func() error) {
+		start := time.Now()
+		err := call()
+		check.LatencyMS = milliseconds(time.Since(start))
+		check.Reachable = err == nil
+		if err != nil {
+			check.Error = err.Error()
+		}
+		checks[i] = check
+	}
+
+	var wg sync.WaitGroup
+	for i, p := range h.config.Printers {
+		wg.Add(1)
+		go func(i int, check models.UpstreamCheck, client ControlClient) {
+			defer wg.Done()
+			probe(i, check, func() error {
+				_, err := client.ConnectionState()
+				return err
+			})
+		}(i, models.UpstreamCheck{Name: p.Name, Kind: "octoprint", URL: redactURL(p.OctoPrintURL)}, h.controlClient(p.ID))
+	}
+
+	for i, src := range h.spoolSources {
+		wg.Add(1)
+		go func(i int, src spoolSource) {
+			defer wg.Done()
+			probe(len(h.config.Printers)+i, models.UpstreamCheck{Name: h.spoolmanLabel(src), Kind: "spoolman", URL: redactURL(src.url)}, func() error {
+				_, err := src.client.GetAllSpools()
+				return err
+			})
+		}(i, src)
+	}
+	wg.Wait()
+
+	return checks
+}
+
+func runtimeStats() models.RuntimeStats {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	return models.RuntimeStats{
+		GoVersion:     runtime.Version(),
+		Goroutines:    runtime.NumGoroutine(),
+		HeapAlloc:     mem.HeapAlloc,
+		HeapObjects:   mem.HeapObjects,
+		Sys:           mem.Sys,
+		NumGC:         mem.NumGC,
+		LastGCPauseMS: milliseconds(time.Duration(mem.PauseNs[(mem.NumGC+255)%256])),
+	}
+}
+
+// redactURL hides any credentials embedded in an upstream URL
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	return u.Redacted()
+}
@@ -0,0 +1,185 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/queue"
+	"github.com/wmarchesi123/octodash/internal/slicer"
+)
+
+// errNotSliceable is returned for entries that are not models waiting to be sliced
+var errNotSliceable = errors.New("entry is not a model waiting for slicing")
+
+// handleSlicerProfiles lists the profiles models can be sliced with
+func (h *Handler) handleSlicerProfiles(w http.ResponseWriter, r *http.Request) {
+	profiles, err := h.slicer.Profiles()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":   "ok",
+		"enabled":  h.slicer.Enabled(),
+		"profiles": profiles,
+	})
+}
+
+// handleQueueSlice slices a queued model with the profile named in the body,
+// or the one its print profile uses. Slicing runs in the background; the entry moves to queued when it is done.
+func (h *Handler) handleQueueSlice(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Profile string `json:"profile"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	entry, err := h.queueEntry(r, r.PathValue("id"))
+	if errors.Is(err, queue.ErrNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	// Without a profile, fall back to the one the entry's print profile names
+	if req.Profile == "" && err == nil && entry.PrintProfile != "" {
+		if printProfile, err := h.profiles.Get(entry.PrintProfile); err == nil {
+			req.Profile = printProfile.Slicer
+		}
+	}
+
+	entry, err = h.startSlicing(r.PathValue("id"), req.Profile)
+	switch {
+	case errors.Is(err, queue.ErrNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, slicer.ErrDisabled):
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+	case errors.Is(err, slicer.ErrUnknownProfile):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, errNotSliceable):
+		writeError(w, http.StatusConflict, err.Error())
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	default:
+		writeJSON(w, http.StatusAccepted, map[string]interface{}{
+			"status": "ok",
+			"entry":  entry,
+		})
+	}
+}
+
+// startSlicing marks a model entry as slicing and runs the slicer in the background.
+// Failed entries can be retried, perhaps with another profile.
+func (h *Handler) startSlicing(id, profile string) (models.QueueEntry, error) {
+	if !h.slicer.Enabled() {
+		return models.QueueEntry{}, slicer.ErrDisabled
+	}
+	if !h.slicer.HasProfile(profile) {
+		return models.QueueEntry{}, slicer.ErrUnknownProfile
+	}
+
+	h.queueMu.Lock()
+	defer h.queueMu.Unlock()
+
+	entry, err := h.queue.Get(id)
+	if err != nil {
+		return entry, err
+	}
+	if entry.Model == "" || (entry.Status != models.QueueNeedsSlicing && entry.Status != models.QueueSliceFailed) {
+		return entry, errNotSliceable
+	}
+
+	entry.Status = models.QueueSlicing
+	entry.Profile = profile
+	entry.Error = ""
+	if err := h.queue.Update(entry); err != nil {
+		return entry, err
+	}
+
+	h.jobs.Add(1)
+	go func() {
+		defer h.jobs.Done()
+		h.sliceEntry(entry)
+	}()
+	return entry, nil
+}
+
+// sliceEntry runs the slicer for one entry and records the outcome
+func (h *Handler) sliceEntry(entry models.QueueEntry) {
+	// Absolute paths, so a containerised slicer can mount the directory
+	dir, err := filepath.Abs(h.settings.Queue.ModelDir)
+	if err != nil {
+		dir = h.settings.Queue.ModelDir
+	}
+	gcode := entry.ID + ".gcode"
+	err = h.slicer.Slice(h.ctx, filepath.Join(dir, entry.Model), filepath.Join(dir, gcode), entry.Profile)
+
+	h.queueMu.Lock()
+	defer h.queueMu.Unlock()
+
+	// The entry may have been removed while the slicer ran
+	current, getErr := h.queue.Get(entry.ID)
+	if getErr != nil {
+		os.Remove(filepath.Join(dir, gcode))
+		return
+	}
+
+	if err != nil {
+		h.logger.Printf("Slicing %s with %s failed: %v", current.File, entry.Profile, err)
+		current.Status = models.QueueSliceFailed
+		current.Error = err.Error()
+	} else {
+		h.logger.Printf("Sliced %s with %s", current.File, entry.Profile)
+		current.Status = models.QueueQueued
+		current.Gcode = gcode
+		current.Estimate = h.gcodeEstimate(gcode)
+		current.File = strings.TrimSuffix(current.File, filepath.Ext(current.File)) + ".gcode"
+	}
+	if err := h.queue.Update(current); err != nil {
+		h.logger.Printf("Failed to update queue entry %s: %v", current.ID, err)
+	}
+}
+
+// failInterruptedSlicing marks entries left slicing by a restart as failed so they can be retried
+func (h *Handler) failInterruptedSlicing() {
+	entries, err := h.queue.List()
+	if err != nil {
+		h.logger.Printf("Failed to read queue: %v", err)
+		return
+	}
+	for _, e := range entries {
+		if e.Status != models.QueueSlicing {
+			continue
+		}
+		e.Status = models.QueueSliceFailed
+		e.Error = "interrupted by a restart"
+		if err := h.queue.Update(e); err != nil {
+			h.logger.Printf("Failed to update queue entry %s: %v", e.ID, err)
+		}
+	}
+}
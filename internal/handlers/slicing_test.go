@@ -0,0 +1,159 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+// slicerHandler builds a handler whose slicer runs command with a "pla" profile available
+func slicerHandler(t *testing.T, command string, op *testutil.OctoPrint) *Handler {
+	t.Helper()
+	profiles := t.TempDir()
+	if err := os.WriteFile(filepath.Join(profiles, "pla.ini"), []byte("layer_height = 0.2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("QUEUE_MODEL_DIR", t.TempDir())
+	t.Setenv("SLICER_PROFILE_DIR", profiles)
+	t.Setenv("SLICER_COMMAND", command)
+	return newTestHandler(t, testutil.NewSpoolman(t), testutil.Printer{Name: "Mini", Server: op})
+}
+
+// waitSliced polls a queue entry until the slicer has finished with it
+func waitSliced(t *testing.T, h *Handler, id string) models.QueueEntry {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		entry, err := h.queue.Get(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if entry.Status != models.QueueSlicing {
+			return entry
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("entry %s still slicing", id)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func postJSON(h *Handler, path, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("POST", path, strings.NewReader(body)))
+	return rec
+}
+
+func TestSliceOnUpload(t *testing.T) {
+	op := testutil.NewOctoPrint(t)
+	h := slicerHandler(t, "cp {input} {output}", op)
+
+	if rec := postJSON(h, "/api/queue/models?file=bracket.stl&profile=petg", tetrahedronSTL); rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown profile = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+
+	rec := postJSON(h, "/api/queue/models?file=bracket.stl&profile=pla", tetrahedronSTL)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("upload = %d: %s", rec.Code, rec.Body)
+	}
+	var response struct {
+		Entry models.QueueEntry `json:"entry"`
+	}
+	json.NewDecoder(rec.Body).Decode(&response)
+
+	entry := waitSliced(t, h, response.Entry.ID)
+	if entry.Status != models.QueueQueued || entry.File != "bracket.gcode" || entry.Profile != "pla" {
+		t.Fatalf("sliced entry = %+v", entry)
+	}
+
+	// The gcode only reaches OctoPrint once a printer takes the job
+	h.dispatchOnce()
+	if got := string(op.Uploaded("bracket.gcode")); got != tetrahedronSTL {
+		t.Errorf("uploaded gcode = %q", got)
+	}
+	if printed := op.Printed(); !slices.Equal(printed, []string{"bracket.gcode"}) {
+		t.Errorf("printed = %v, want [bracket.gcode]", printed)
+	}
+}
+
+func TestSliceFailure(t *testing.T) {
+	h := slicerHandler(t, "false {input}", testutil.NewOctoPrint(t))
+
+	rec := postJSON(h, "/api/queue/models?file=bracket.stl", tetrahedronSTL)
+	var response struct {
+		Entry models.QueueEntry `json:"entry"`
+	}
+	json.NewDecoder(rec.Body).Decode(&response)
+	id := response.Entry.ID
+
+	if rec := postJSON(h, "/api/queue/"+id+"/slice", `{"profile": "pla"}`); rec.Code != http.StatusAccepted {
+		t.Fatalf("slice = %d: %s", rec.Code, rec.Body)
+	}
+	entry := waitSliced(t, h, id)
+	if entry.Status != models.QueueSliceFailed || !strings.Contains(entry.Error, "slicer failed") {
+		t.Fatalf("entry after failed slice = %+v", entry)
+	}
+
+	// Failed entries can be retried, but gcode can't be sliced again
+	if rec := postJSON(h, "/api/queue/"+id+"/slice", `{"profile": "pla"}`); rec.Code != http.StatusAccepted {
+		t.Errorf("retry = %d, want %d", rec.Code, http.StatusAccepted)
+	}
+	waitSliced(t, h, id)
+	gcode, _ := h.queue.Add(models.QueueEntry{File: "clip.gcode"})
+	if rec := postJSON(h, "/api/queue/"+gcode.ID+"/slice", `{"profile": "pla"}`); rec.Code != http.StatusConflict {
+		t.Errorf("slicing gcode = %d, want %d", rec.Code, http.StatusConflict)
+	}
+}
+
+func TestSlicesRunOneAtATime(t *testing.T) {
+	// The script fails if another slice holds its lock directory
+	dir := t.TempDir()
+	script := filepath.Join(dir, "slice.sh")
+	body := "#!/bin/sh\nmkdir \"$(dirname \"$0\")/running\" || exit 1\nsleep 0.05\ncp \"$1\" \"$2\"\nrmdir \"$(dirname \"$0\")/running\"\n"
+	if err := os.WriteFile(script, []byte(body), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	h := slicerHandler(t, script+" {input} {output}", testutil.NewOctoPrint(t))
+
+	var ids []string
+	for _, name := range []string{"a.stl", "b.stl", "c.stl"} {
+		rec := postJSON(h, "/api/queue/models?file="+name+"&profile=pla", tetrahedronSTL)
+		var response struct {
+			Entry models.QueueEntry `json:"entry"`
+		}
+		json.NewDecoder(rec.Body).Decode(&response)
+		ids = append(ids, response.Entry.ID)
+	}
+	for _, id := range ids {
+		if entry := waitSliced(t, h, id); entry.Status != models.QueueQueued {
+			t.Errorf("entry %s = %s %q, want sliced in turn", id, entry.Status, entry.Error)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/wmarchesi123/go-3dprint-client/config"
+	"github.com/wmarchesi123/go-3dprint-client/spoolman"
+	"github.com/wmarchesi123/octodash/internal/settings"
+	"github.com/wmarchesi123/octodash/internal/spoolapi"
+)
+
+// spoolSource is one Spoolman instance and the client that reads it
+type spoolSource struct {
+	name   string
+	url    string
+	client SpoolmanClient
+}
+
+// newSpoolSources builds a source for SPOOLMAN_URL and every extra instance,
+// keeping an injected default client and checking real ones in the background
+func (h *Handler) newSpoolSources(cfg *config.Config, s settings.SpoolmanSettings) []spoolSource {
+	sources := []spoolSource{{name: s.Name, url: cfg.SpoolmanURL, client: h.spoolmanClient}}
+	if h.spoolmanClient == nil {
+		client := spoolapi.NewClient(cfg.SpoolmanURL, spoolapi.Credentials(s.SpoolmanCredentials))
+		sources[0].client = client
+		go h.checkSpoolman(s.Name, cfg.SpoolmanURL, client)
+	}
+
+	for _, inst := range s.Instances {
+		client := spoolapi.NewClient(inst.URL, spoolapi.Credentials(inst.SpoolmanCredentials))
+		sources = append(sources, spoolSource{name: inst.Name, url: inst.URL, client: client})
+		go h.checkSpoolman(inst.Name, inst.URL, client)
+	}
+	return sources
+}
+
+// checkSpoolman logs whether a Spoolman answers with the configured credentials,
+// so a wrong URL or token shows up at startup rather than as missing spools
+func (h *Handler) checkSpoolman(name, baseURL string, client *spoolapi.Client) {
+	if err := client.Check(); err != nil {
+		h.logger.Printf("Spoolman %s at %s is not usable: %v", name, redactURL(baseURL), err)
+		return
+	}
+	h.logger.Printf("Connected to Spoolman %s at %s", name, redactURL(baseURL))
+}
+
+// spoolSourceFor returns the Spoolman instance holding a printer's spools
+func (h *Handler) spoolSourceFor(printerID string) spoolSource {
+	if src, ok := h.spoolSource(h.settings.Printers[printerID].Spoolman); ok {
+		return src
+	}
+	return h.spoolSources[0]
+}
+
+// visibleSpoolSources returns the instances holding spools for printers the
+// caller's teams can see, or every instance when they can see the whole farm
+func (h *Handler) visibleSpoolSources(r *http.Request) []spoolSource {
+	if h.tenancy(r).all {
+		return h.spoolSources
+	}
+	var sources []spoolSource
+	for _, p := range h.printersMatching(r, "") {
+		src := h.spoolSourceFor(p.ID)
+		if !slices.ContainsFunc(sources, func(s spoolSource) bool { return s.name == src.name }) {
+			sources = append(sources, src)
+		}
+	}
+	return sources
+}
+
+// spoolSource finds an instance by name; empty names the first
+func (h *Handler) spoolSource(name string) (spoolSource, bool) {
+	if name == "" {
+		return h.spoolSources[0], true
+	}
+	for _, src := range h.spoolSources {
+		if src.name == name {
+			return src, true
+		}
+	}
+	return spoolSource{}, false
+}
+
+// spoolInfo formats a spool for the API with its material's accent color,
+// naming its instance when there is more than one
+func (h *Handler) spoolInfo(src spoolSource, spool *spoolman.Spool) map[string]interface{} {
+	info := spoolman.FormatSpoolInfo(spool)
+	if color := h.materialColor(spool.Filament.Material); color != "" {
+		info["material_color"] = color
+	}
+	if len(h.spoolSources) > 1 {
+		info["spoolman"] = src.name
+	}
+	return info
+}
+
+// spoolmanLabel names an instance in errors and checks; a lone instance is just "Spoolman"
+func (h *Handler) spoolmanLabel(src spoolSource) string {
+	if len(h.spoolSources) == 1 {
+		return "Spoolman"
+	}
+	return "Spoolman " + src.name
+}
@@ -0,0 +1,172 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/auth"
+	"github.com/wmarchesi123/octodash/internal/octoapi"
+)
+
+const ssoBucket = "sso_links"
+
+// ssoApp is the name OctoPrint shows in its key approval dialog
+const ssoApp = "OctoDash"
+
+// ssoLink is one user's application key for one printer, or their request
+// for it while OctoPrint waits for approval
+type ssoLink struct {
+	PrinterID  string    `json:"printer_id"`
+	User       string    `json:"user"`
+	AppToken   string    `json:"app_token,omitempty"`
+	AuthDialog string    `json:"auth_dialog,omitempty"`
+	APIKey     string    `json:"api_key,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func ssoKey(printerID, user string) string {
+	return printerID + "/" + user
+}
+
+// handleSSOLink asks OctoPrint for an application key on the caller's behalf.
+// They approve it in the returned dialog, logging in to OctoPrint one last time.
+func (h *Handler) handleSSOLink(w http.ResponseWriter, r *http.Request) {
+	printer, ok := h.findPrinter(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Printer not found")
+		return
+	}
+
+	request, err := h.controlClient(printer.ID).RequestAppKey(ssoApp)
+	if err != nil {
+		var status *octoapi.StatusError
+		if errors.As(err, &status) && status.Code == http.StatusNotFound {
+			writeError(w, http.StatusConflict, "The Application Keys plugin is not enabled on "+printer.Name)
+			return
+		}
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+
+	link := ssoLink{
+		PrinterID:  printer.ID,
+		User:       auth.UserFromContext(r.Context()).Name,
+		AppToken:   request.AppToken,
+		AuthDialog: request.AuthDialog,
+		CreatedAt:  h.clock.Now().UTC(),
+	}
+	if link.AuthDialog == "" {
+		link.AuthDialog = printer.OctoPrintURL
+	}
+	if err := h.store.Put(ssoBucket, ssoKey(link.PrinterID, link.User), link); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, map[string]interface{}{
+		"status":      "pending",
+		"auth_dialog": link.AuthDialog,
+	})
+}
+
+// handleSSOSession gives the caller their key for a printer, so the browser
+// can log in to OctoPrint passively before opening it. A pending request is
+// settled first; without a usable key the answer is 409 with the link state.
+func (h *Handler) handleSSOSession(w http.ResponseWriter, r *http.Request) {
+	printer, ok := h.findPrinter(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Printer not found")
+		return
+	}
+	key := ssoKey(printer.ID, auth.UserFromContext(r.Context()).Name)
+
+	var link ssoLink
+	found, err := h.store.Get(ssoBucket, key, &link)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !found {
+		writeJSON(w, http.StatusConflict, map[string]interface{}{
+			"status": "error",
+			"error":  "No OctoPrint login linked",
+			"link":   "none",
+		})
+		return
+	}
+
+	if link.APIKey == "" {
+		apiKey, err := h.controlClient(printer.ID).AppKeyDecision(link.AppToken)
+		var status *octoapi.StatusError
+		switch {
+		case errors.Is(err, octoapi.ErrPending):
+			writeJSON(w, http.StatusConflict, map[string]interface{}{
+				"status":      "error",
+				"error":       "OctoPrint login awaiting approval",
+				"link":        "pending",
+				"auth_dialog": link.AuthDialog,
+			})
+			return
+		case errors.As(err, &status) && status.Code == http.StatusNotFound:
+			h.store.Delete(ssoBucket, key)
+			writeJSON(w, http.StatusConflict, map[string]interface{}{
+				"status": "error",
+				"error":  "OctoPrint login was denied or expired",
+				"link":   "none",
+			})
+			return
+		case err != nil:
+			writeError(w, http.StatusBadGateway, err.Error())
+			return
+		}
+
+		link.APIKey, link.AppToken, link.AuthDialog = apiKey, "", ""
+		if err := h.store.Put(ssoBucket, key, link); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		h.audit(r, "sso-link", printer.ID, "")
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":    "ok",
+		"url":       printer.OctoPrintURL,
+		"login_url": strings.TrimRight(printer.OctoPrintURL, "/") + "/api/login",
+		"api_key":   link.APIKey,
+	})
+}
+
+// handleSSOUnlink forgets the caller's key for a printer. The key stays valid
+// in OctoPrint until revoked there.
+func (h *Handler) handleSSOUnlink(w http.ResponseWriter, r *http.Request) {
+	printer, ok := h.findPrinter(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Printer not found")
+		return
+	}
+	if err := h.store.Delete(ssoBucket, ssoKey(printer.ID, auth.UserFromContext(r.Context()).Name)); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"status": "ok"})
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestSSOLink(t *testing.T) {
+	t.Setenv("SSO_ENABLED", "true")
+	t.Setenv("AUTH_USERS", "vic:viewer:vic-token,olga:operator:olga-token,otto:operator:otto-token")
+	op := testutil.NewOctoPrint(t)
+	h := newTestHandler(t, testutil.NewSpoolman(t), testutil.Printer{Name: "Mini", Server: op})
+
+	type response struct {
+		Link       string `json:"link"`
+		AuthDialog string `json:"auth_dialog"`
+		LoginURL   string `json:"login_url"`
+		APIKey     string `json:"api_key"`
+	}
+	do := func(token, method, path string) (int, response) {
+		rec := doAs(h, token, method, path, "")
+		var resp response
+		json.NewDecoder(rec.Body).Decode(&resp)
+		return rec.Code, resp
+	}
+	const link, session = "/api/printers/printer-1/sso", "/api/printers/printer-1/sso/session"
+
+	if code, resp := do("olga-token", "POST", session); code != http.StatusConflict || resp.Link != "none" {
+		t.Errorf("session before linking = %d/%q, want %d/none", code, resp.Link, http.StatusConflict)
+	}
+	if code, _ := do("vic-token", "POST", link); code != http.StatusForbidden {
+		t.Errorf("viewer link = %d, want %d", code, http.StatusForbidden)
+	}
+
+	code, resp := do("olga-token", "POST", link)
+	if code != http.StatusAccepted || !strings.HasSuffix(resp.AuthDialog, "/plugin/appkeys/auth/app-token") {
+		t.Fatalf("link = %d, auth dialog %q", code, resp.AuthDialog)
+	}
+	if code, resp := do("olga-token", "POST", session); code != http.StatusConflict || resp.Link != "pending" || resp.AuthDialog == "" {
+		t.Errorf("session while pending = %d/%q/%q, want pending with the dialog", code, resp.Link, resp.AuthDialog)
+	}
+
+	op.ApproveAppKey("olga-key")
+	code, resp = do("olga-token", "POST", session)
+	if code != http.StatusOK || resp.APIKey != "olga-key" || resp.LoginURL != op.URL+"/api/login" {
+		t.Fatalf("session after approval = %d %+v", code, resp)
+	}
+
+	// Keys belong to the user who linked them
+	if code, resp := do("otto-token", "POST", session); code != http.StatusConflict || resp.Link != "none" {
+		t.Errorf("other user's session = %d/%q, want none", code, resp.Link)
+	}
+
+	do("otto-token", "POST", link)
+	op.DenyAppKey()
+	if code, resp := do("otto-token", "POST", session); code != http.StatusConflict || resp.Link != "none" {
+		t.Errorf("session after denial = %d/%q, want none", code, resp.Link)
+	}
+
+	// Olga's key survives other users' requests until she unlinks
+	if code, resp := do("olga-token", "POST", session); code != http.StatusOK || resp.APIKey != "olga-key" {
+		t.Errorf("linked session = %d %+v", code, resp)
+	}
+	if code, _ := do("olga-token", "DELETE", link); code != http.StatusOK {
+		t.Fatalf("unlink = %d", code)
+	}
+	if code, resp := do("olga-token", "POST", session); code != http.StatusConflict || resp.Link != "none" {
+		t.Errorf("session after unlink = %d/%q, want none", code, resp.Link)
+	}
+}
+
+func TestSSOConnectSrc(t *testing.T) {
+	op := testutil.NewOctoPrint(t)
+	connectSrc := func() string {
+		h := newTestHandler(t, testutil.NewSpoolman(t), testutil.Printer{Name: "Mini", Server: op})
+		csp := doAs(h, "", "GET", "/", "").Header().Get("Content-Security-Policy")
+		return regexp.MustCompile(`connect-src [^;]*`).FindString(csp)
+	}
+
+	if got := connectSrc(); got != "connect-src 'self'" {
+		t.Errorf("without SSO %q, want only this server", got)
+	}
+
+	// The browser logs in to OctoPrint itself, so the policy must let it
+	t.Setenv("SSO_ENABLED", "true")
+	if got, want := connectSrc(), "connect-src 'self' "+op.URL; got != want {
+		t.Errorf("with SSO %q, want %q", got, want)
+	}
+}
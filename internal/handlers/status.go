@@ -0,0 +1,84 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"sync"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+)
+
+// statusSlot holds a printer's status together with the sections it points
+// to, so one pooled value backs the whole status
+type statusSlot struct {
+	status   models.PrinterStatus
+	temps    models.TemperatureInfo
+	progress models.ProgressInfo
+	eta      time.Time
+	lastSeen time.Time
+	dataAge  int
+}
+
+// statusBatch is the result of one poll
+type statusBatch struct {
+	slots    []statusSlot
+	printers []*models.PrinterStatus
+}
+
+var statusBatches = sync.Pool{
+	New: func() interface{} { return new(statusBatch) },
+}
+
+// acquireBatch returns a batch with n empty slots
+func acquireBatch(n int) *statusBatch {
+	b := statusBatches.Get().(*statusBatch)
+	if cap(b.slots) < n {
+		b.slots = make([]statusSlot, n)
+		b.printers = make([]*models.PrinterStatus, n)
+	}
+	b.slots = b.slots[:n]
+	b.printers = b.printers[:n]
+	for i := range b.slots {
+		b.slots[i] = statusSlot{}
+		b.printers[i] = &b.slots[i].status
+	}
+	return b
+}
+
+// release returns the batch to the pool; no status from it may be used afterwards
+func (b *statusBatch) release() {
+	statusBatches.Put(b)
+}
+
+// copyFrom makes s a copy of src whose section pointers refer to s's own fields
+func (s *statusSlot) copyFrom(src *statusSlot) {
+	*s = *src
+	if src.status.Temperatures != nil {
+		s.status.Temperatures = &s.temps
+	}
+	if src.status.Progress != nil {
+		s.status.Progress = &s.progress
+		if src.progress.ETA != nil {
+			s.progress.ETA = &s.eta
+		}
+	}
+	s.status.LastSeen = nil
+	s.status.DataAge = nil
+}
@@ -0,0 +1,504 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wmarchesi123/go-3dprint-client/config"
+	"github.com/wmarchesi123/go-3dprint-client/octoprint"
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/settings"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+// newTestHandler builds a Handler wired to the given fake servers
+func newTestHandler(t *testing.T, sm *testutil.Spoolman, printers ...testutil.Printer) *Handler {
+	t.Helper()
+	return newHandlerWithConfig(t, testutil.Config(sm, printers...))
+}
+
+// doAs sends a request with the bearer token of a user from AUTH_USERS, or
+// anonymously when token is empty
+func doAs(h *Handler, token, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func newHandlerWithConfig(t testing.TB, cfg *config.Config, opts ...Option) *Handler {
+	t.Helper()
+
+	s, err := settings.Load()
+	if err != nil {
+		t.Fatalf("loading settings: %v", err)
+	}
+	s.DataDir = t.TempDir()
+
+	h, err := NewHandler(cfg, s, opts...)
+	if err != nil {
+		t.Fatalf("NewHandler: %v", err)
+	}
+	t.Cleanup(func() { h.Close() })
+	return h
+}
+
+// getStatus calls /api/status and returns the printers keyed by ID
+func getStatus(t *testing.T, h *Handler) map[string]models.PrinterStatus {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/api/status", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /api/status = %d: %s", rec.Code, rec.Body)
+	}
+
+	var response struct {
+		Status   string                 `json:"status"`
+		Printers []models.PrinterStatus `json:"printers"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("decoding status: %v", err)
+	}
+	if response.Status != "ok" {
+		t.Fatalf("status = %q, want ok", response.Status)
+	}
+
+	byID := make(map[string]models.PrinterStatus)
+	for _, p := range response.Printers {
+		byID[p.ID] = p
+	}
+	return byID
+}
+
+func TestStatusAggregatesPrinters(t *testing.T) {
+	sm := testutil.NewSpoolman(t, testutil.Spool(7, "Prusament", "PETG", "ff0000", 640))
+
+	printing := testutil.NewOctoPrint(t)
+	printing.SetPrinting("benchy.gcode", 42.5, 1800)
+	printing.SetTemperatures(214.8, 215, 59.9, 60)
+	printing.SetSpool("7")
+
+	idle := testutil.NewOctoPrint(t)
+
+	h := newTestHandler(t, sm,
+		testutil.Printer{Name: "Prusa", Server: printing},
+		testutil.Printer{Name: "Ender", Server: idle},
+	)
+	got := getStatus(t, h)
+
+	if len(got) != 2 {
+		t.Fatalf("got %d printers, want 2", len(got))
+	}
+
+	p := got["printer-1"]
+	if p.Name != "Prusa" || p.Status != "printing" {
+		t.Errorf("printer-1 = %s/%s, want Prusa/printing", p.Name, p.Status)
+	}
+	if p.Progress == nil || p.Progress.Completion != 42.5 || p.Progress.FileName != "benchy.gcode" {
+		t.Errorf("printer-1 progress = %+v", p.Progress)
+	}
+	if p.Progress != nil && p.Progress.ETA == nil {
+		t.Error("printer-1 has no ETA")
+	}
+	if p.Temperatures == nil || p.Temperatures.HotendTarget != 215 || p.Temperatures.BedActual != 59.9 {
+		t.Errorf("printer-1 temperatures = %+v", p.Temperatures)
+	}
+	if p.CurrentSpool == nil {
+		t.Error("printer-1 has no current spool")
+	}
+	if !strings.HasSuffix(p.ThumbnailURL, "/benchy.png") {
+		t.Errorf("printer-1 thumbnail = %q", p.ThumbnailURL)
+	}
+
+	e := got["printer-2"]
+	if e.Status != "idle" || e.Progress != nil {
+		t.Errorf("printer-2 = %s with progress %+v, want idle without progress", e.Status, e.Progress)
+	}
+	if idle.Requests("GET /api/job") != 0 {
+		t.Error("job was fetched for an idle printer")
+	}
+}
+
+func TestStatusUpstreamFaults(t *testing.T) {
+	tests := []struct {
+		name  string
+		fault testutil.Fault
+		want  string
+	}{
+		{"unauthorized", testutil.Fault{StatusCode: http.StatusUnauthorized}, "HTTP 401"},
+		{"server error", testutil.Fault{StatusCode: http.StatusInternalServerError}, "HTTP 500"},
+		{"partial json", testutil.Fault{PartialJSON: true}, "unexpected EOF"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sm := testutil.NewSpoolman(t)
+
+			broken := testutil.NewOctoPrint(t)
+			broken.SetFault(tt.fault)
+			healthy := testutil.NewOctoPrint(t)
+
+			h := newTestHandler(t, sm,
+				testutil.Printer{Name: "Broken", Server: broken},
+				testutil.Printer{Name: "Healthy", Server: healthy},
+			)
+			got := getStatus(t, h)
+
+			b := got["printer-1"]
+			if b.Status != "offline" {
+				t.Errorf("broken printer status = %q, want offline", b.Status)
+			}
+			if !strings.Contains(b.Error, tt.want) {
+				t.Errorf("broken printer error = %q, want it to contain %q", b.Error, tt.want)
+			}
+
+			if s := got["printer-2"].Status; s != "idle" {
+				t.Errorf("healthy printer status = %q, want idle", s)
+			}
+		})
+	}
+}
+
+func TestStatusErrorState(t *testing.T) {
+	sm := testutil.NewSpoolman(t)
+	op := testutil.NewOctoPrint(t)
+	op.SetError("Error: Thermal Runaway")
+
+	h := newTestHandler(t, sm, testutil.Printer{Name: "Voron", Server: op})
+	p := getStatus(t, h)["printer-1"]
+
+	if p.Status != "error" || p.State != "Error: Thermal Runaway" {
+		t.Errorf("status = %s/%q, want error/Error: Thermal Runaway", p.Status, p.State)
+	}
+	if p.Diagnosis == nil || p.Diagnosis.Code != "thermal_runaway" {
+		t.Errorf("diagnosis = %+v, want thermal_runaway", p.Diagnosis)
+	}
+}
+
+func TestStatusFetchesPrintersConcurrently(t *testing.T) {
+	const delay = 300 * time.Millisecond
+
+	sm := testutil.NewSpoolman(t)
+	var printers []testutil.Printer
+	for _, name := range []string{"A", "B", "C"} {
+		op := testutil.NewOctoPrint(t)
+		op.SetFault(testutil.Fault{Delay: delay})
+		printers = append(printers, testutil.Printer{Name: name, Server: op})
+	}
+
+	h := newTestHandler(t, sm, printers...)
+
+	start := time.Now()
+	got := getStatus(t, h)
+	elapsed := time.Since(start)
+
+	for id, p := range got {
+		if p.Status != "idle" {
+			t.Errorf("%s status = %q, want idle", id, p.Status)
+		}
+	}
+	// Each printer costs two slow round trips (state and spool); serially
+	// three printers would take at least six delays
+	if elapsed >= 4*delay {
+		t.Errorf("status took %v, want printers fetched concurrently", elapsed)
+	}
+}
+
+func TestStatusSpoolmanDown(t *testing.T) {
+	sm := testutil.NewSpoolman(t, testutil.Spool(1, "eSun", "PLA", "00ff00", 100))
+	sm.SetFault(testutil.Fault{StatusCode: http.StatusServiceUnavailable})
+
+	op := testutil.NewOctoPrint(t)
+	op.SetSpool("1")
+
+	h := newTestHandler(t, sm, testutil.Printer{Name: "Mini", Server: op})
+	p := getStatus(t, h)["printer-1"]
+
+	if p.Status != "idle" {
+		t.Errorf("status = %q, want idle", p.Status)
+	}
+	if p.CurrentSpool != nil {
+		t.Errorf("current spool = %v, want none while Spoolman is down", p.CurrentSpool)
+	}
+}
+
+func TestStatusSpoolmanAuth(t *testing.T) {
+	tests := []struct {
+		name      string
+		env       map[string]string
+		require   func(*testutil.Spoolman)
+		wantSpool bool
+	}{
+		{
+			name:      "bearer token",
+			env:       map[string]string{"SPOOLMAN_TOKEN": "s3cret"},
+			require:   func(sm *testutil.Spoolman) { sm.SetToken("s3cret") },
+			wantSpool: true,
+		},
+		{
+			name:      "basic auth",
+			env:       map[string]string{"SPOOLMAN_USERNAME": "octodash", "SPOOLMAN_PASSWORD": "hunter2"},
+			require:   func(sm *testutil.Spoolman) { sm.SetBasicAuth("octodash", "hunter2") },
+			wantSpool: true,
+		},
+		{
+			name:    "missing credentials",
+			require: func(sm *testutil.Spoolman) { sm.SetToken("s3cret") },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			sm := testutil.NewSpoolman(t, testutil.Spool(3, "Polymaker", "PLA", "0000ff", 500))
+			tt.require(sm)
+
+			op := testutil.NewOctoPrint(t)
+			op.SetSpool("3")
+
+			h := newTestHandler(t, sm, testutil.Printer{Name: "Mini", Server: op})
+			p := getStatus(t, h)["printer-1"]
+
+			if got := p.CurrentSpool != nil; got != tt.wantSpool {
+				t.Errorf("has current spool = %v, want %v", got, tt.wantSpool)
+			}
+		})
+	}
+}
+
+func TestStatusSpoolmanPerPrinter(t *testing.T) {
+	fdm := testutil.NewSpoolman(t, testutil.Spool(5, "Prusament", "PLA", "ff8800", 800))
+	resin := testutil.NewSpoolman(t, testutil.Spool(5, "Elegoo", "Resin", "cccccc", 450))
+	t.Setenv("SPOOLMAN_NAME", "fdm")
+	t.Setenv("SPOOLMAN_2_NAME", "resin")
+	t.Setenv("SPOOLMAN_2_URL", resin.URL)
+	t.Setenv("PRINTER_2_SPOOLMAN", "resin")
+
+	mk3 := testutil.NewOctoPrint(t)
+	mk3.SetSpool("5")
+	mars := testutil.NewOctoPrint(t)
+	mars.SetSpool("5")
+
+	h := newTestHandler(t, fdm,
+		testutil.Printer{Name: "MK3", Server: mk3},
+		testutil.Printer{Name: "Mars", Server: mars},
+	)
+	got := getStatus(t, h)
+
+	for id, want := range map[string][2]string{"printer-1": {"PLA", "fdm"}, "printer-2": {"Resin", "resin"}} {
+		spool := got[id].CurrentSpool
+		if spool == nil {
+			t.Errorf("%s has no current spool", id)
+			continue
+		}
+		if spool["material"] != want[0] || spool["spoolman"] != want[1] {
+			t.Errorf("%s spool = %v/%v, want %s from %s", id, spool["material"], spool["spoolman"], want[0], want[1])
+		}
+	}
+}
+
+// fakePrinter is an in-memory PrinterClient for tests that don't need HTTP
+type fakePrinter struct {
+	state octoprint.PrinterResponse
+	job   octoprint.JobResponse
+}
+
+func (f *fakePrinter) GetPrinterState() (*octoprint.PrinterResponse, error) { return &f.state, nil }
+func (f *fakePrinter) GetJob() (*octoprint.JobResponse, error)              { return &f.job, nil }
+func (f *fakePrinter) GetCurrentSpool(int) (string, error)                  { return "", nil }
+func (f *fakePrinter) SetActiveSpool(string, int) error                     { return nil }
+func (f *fakePrinter) SetToolTemperature(int, float64) error                { return nil }
+func (f *fakePrinter) SetBedTemperature(float64) error                      { return nil }
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+func TestStatusETAUsesClock(t *testing.T) {
+	fake := &fakePrinter{}
+	fake.state.State.Flags.Printing = true
+	fake.job.Job.File.Display = "gear.gcode"
+	fake.job.Progress.PrintTimeLeft = 90 * 60
+
+	cfg := &config.Config{Printers: []config.Printer{{ID: "printer-1", Name: "Fake"}}}
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	h := newHandlerWithConfig(t, cfg,
+		WithPrinterClient("printer-1", fake),
+		WithClock(fixedClock(now)),
+	)
+	p := getStatus(t, h)["printer-1"]
+
+	if p.Progress == nil || p.Progress.ETA == nil {
+		t.Fatalf("progress = %+v, want an ETA", p.Progress)
+	}
+	if want := now.Add(90 * time.Minute); !p.Progress.ETA.Equal(want) {
+		t.Errorf("ETA = %v, want %v", p.Progress.ETA, want)
+	}
+}
+
+// stepClock is a Clock a test moves by hand. It is locked because the
+// handler's background loops read it while the test runs.
+type stepClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *stepClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *stepClock) set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
+
+func (c *stepClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestStatusMetadata(t *testing.T) {
+	sm := testutil.NewSpoolman(t)
+	op := testutil.NewOctoPrint(t)
+	clock := &stepClock{now: time.Date(2025, 3, 1, 3, 0, 0, 0, time.UTC)}
+
+	h := newHandlerWithConfig(t, testutil.Config(sm, testutil.Printer{Name: "Mini", Server: op}), WithClock(clock))
+
+	poll := func() (uint64, models.PrinterStatus) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", "/api/status", nil))
+
+		var response struct {
+			Sequence uint64                 `json:"sequence"`
+			Server   models.ServerInfo      `json:"server"`
+			Printers []models.PrinterStatus `json:"printers"`
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+			t.Fatalf("decoding status: %v", err)
+		}
+		if response.Server.Version == "" {
+			t.Error("server version missing")
+		}
+		return response.Sequence, response.Printers[0]
+	}
+
+	seq1, p := poll()
+	if p.LastSeen == nil || !p.LastSeen.Equal(clock.Now()) || *p.DataAge != 0 {
+		t.Fatalf("online printer last_seen = %v, age = %v", p.LastSeen, p.DataAge)
+	}
+
+	// The printer drops off; its last contact stays put and the data ages
+	op.SetFault(testutil.Fault{StatusCode: http.StatusBadGateway})
+	seen := clock.Now()
+	clock.advance(5 * time.Minute)
+
+	seq2, p := poll()
+	if seq2 <= seq1 {
+		t.Errorf("sequence went from %d to %d, want increasing", seq1, seq2)
+	}
+	if p.Status != "offline" || p.LastSeen == nil || !p.LastSeen.Equal(seen) {
+		t.Errorf("offline printer = %s, last_seen %v, want offline since %v", p.Status, p.LastSeen, seen)
+	}
+	if p.DataAge == nil || *p.DataAge != 300 {
+		t.Errorf("data age = %v, want 300", p.DataAge)
+	}
+}
+
+// BenchmarkStatus measures a /api/status poll of a 20-printer farm, the Pi 3 workload
+func BenchmarkStatus(b *testing.B) {
+	b.Setenv("UPDATES_CHECK_INTERVAL", "0")
+	b.Setenv("EVENTS_POLL_INTERVAL", "0")
+
+	cfg := &config.Config{}
+	var opts []Option
+	for i := 1; i <= 20; i++ {
+		fake := &fakePrinter{}
+		fake.state.State.Text = "Printing"
+		fake.state.State.Flags.Printing = true
+		fake.job.Job.File.Display = "gear.gcode"
+		fake.job.Progress.Completion = 42
+		fake.job.Progress.PrintTimeLeft = 3600
+
+		id := fmt.Sprintf("printer-%d", i)
+		cfg.Printers = append(cfg.Printers, config.Printer{ID: id, Name: "Printer " + id})
+		opts = append(opts, WithPrinterClient(id, fake))
+	}
+	h := newHandlerWithConfig(b, cfg, opts...)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", "/api/status", nil))
+		if rec.Code != http.StatusOK {
+			b.Fatalf("GET /api/status = %d", rec.Code)
+		}
+	}
+}
+
+func TestStatusSubpathInstance(t *testing.T) {
+	sm := testutil.NewSpoolman(t)
+	op := testutil.NewOctoPrint(t)
+	op.SetPrinting("parts/big gear.gcode", 10, 3000)
+
+	// Two instances behind one reverse proxy, each under its own prefix
+	proxy := httptest.NewServer(http.StripPrefix("/printer1", op.Config.Handler))
+	t.Cleanup(proxy.Close)
+
+	cfg := testutil.Config(sm)
+	cfg.Printers = append(cfg.Printers, config.Printer{ID: "printer-1", Name: "Proxied", OctoPrintURL: proxy.URL + "/printer1/", APIKey: "test-key"})
+	h := newHandlerWithConfig(t, cfg)
+
+	p := getStatus(t, h)["printer-1"]
+	if p.Status != "printing" {
+		t.Fatalf("proxied printer = %s (%s)", p.Status, p.Error)
+	}
+	if p.OctoPrintURL != proxy.URL+"/printer1" {
+		t.Errorf("octoprint_url = %q, want trailing slash trimmed", p.OctoPrintURL)
+	}
+	if want := proxy.URL + "/printer1/plugin/prusaslicerthumbnails/thumbnail/parts/big%20gear.png"; p.ThumbnailURL != want {
+		t.Errorf("thumbnail_url = %q, want %q", p.ThumbnailURL, want)
+	}
+}
+
+func TestInvalidOctoPrintURL(t *testing.T) {
+	cfg := &config.Config{Printers: []config.Printer{{ID: "printer-1", Name: "Typo", OctoPrintURL: "octopi.local:5000"}}}
+	if _, err := NewHandler(cfg, &settings.Settings{}); err == nil {
+		t.Error("NewHandler accepted an OctoPrint URL without a scheme")
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/wmarchesi123/go-3dprint-client/config"
+	"github.com/wmarchesi123/octodash/internal/settings"
+)
+
+// printersMatching returns the printers the caller may see carrying every tag
+// in the filter. Filters use the PRINTER_N_TAGS syntax, e.g. "material=PETG,location=rack2";
+// a bare key matches any printer that has the tag at all.
+func (h *Handler) printersMatching(r *http.Request, filter string) []config.Printer {
+	want := settings.ParseTags(filter)
+	scope := h.tenancy(r)
+	if len(want) == 0 && scope.all {
+		return h.config.Printers
+	}
+
+	var printers []config.Printer
+	for _, p := range h.config.Printers {
+		if scope.printer(p.ID) && hasTags(h.settings.Printers[p.ID].Tags, want) {
+			printers = append(printers, p)
+		}
+	}
+	return printers
+}
+
+func hasTags(tags, want map[string]string) bool {
+	for key, value := range want {
+		have, ok := tags[key]
+		if !ok {
+			return false
+		}
+		if value != "" && !strings.EqualFold(have, value) {
+			return false
+		}
+	}
+	return true
+}
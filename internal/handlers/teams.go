@@ -0,0 +1,260 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/wmarchesi123/octodash/internal/auth"
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/queue"
+	"github.com/wmarchesi123/octodash/internal/settings"
+	"github.com/wmarchesi123/octodash/internal/teams"
+)
+
+// tenancy is what one caller may see when TEAMS_ENABLED is set: admins see
+// everything, members their teams' printers and queue entries, and callers
+// without a valid token nothing
+type tenancy struct {
+	all        bool
+	membership teams.Membership
+}
+
+// tenancy resolves the caller's view of the farm
+func (h *Handler) tenancy(r *http.Request) tenancy {
+	return h.tenancyFor(r.Context())
+}
+
+// tenancyFor is tenancy for the caller attached to ctx
+func (h *Handler) tenancyFor(ctx context.Context) tenancy {
+	if !h.settings.Teams.Enabled {
+		return tenancy{all: true}
+	}
+	user, ok := auth.IdentifiedUser(ctx)
+	if !ok {
+		return tenancy{}
+	}
+	if user.Role >= auth.RoleAdmin {
+		return tenancy{all: true}
+	}
+
+	membership, err := h.teams.For(user.Name)
+	if err != nil {
+		h.logger.Printf("Failed to load teams for %s: %v", user.Name, err)
+		return tenancy{}
+	}
+	return tenancy{membership: membership}
+}
+
+// printer reports whether the caller may see a printer
+func (t tenancy) printer(id string) bool {
+	return t.all || t.membership.Printers[id] != ""
+}
+
+// team reports whether the caller may see queue entries belonging to a team
+func (t tenancy) team(name string) bool {
+	return t.all || t.membership.Has(name)
+}
+
+// printerInPath returns the printer ID in routes scoped to a single printer
+func printerInPath(path string) string {
+	for _, prefix := range []string{"/api/printers/", "/embed/", "/api/widget/", "/proxy/"} {
+		if rest, ok := strings.CutPrefix(path, prefix); ok {
+			id, _, _ := strings.Cut(rest, "/")
+			return id
+		}
+	}
+	return ""
+}
+
+// scopePrinters answers 404 for printers outside the caller's teams, so other
+// teams' machines look the same as ones that do not exist
+func (h *Handler) scopePrinters(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id := printerInPath(r.URL.Path); id != "" && !h.tenancy(r).printer(id) {
+			writeError(w, http.StatusNotFound, "Printer not found")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// queueEntry loads a queue entry the caller's teams may see
+func (h *Handler) queueEntry(r *http.Request, id string) (models.QueueEntry, error) {
+	entry, err := h.queue.Get(id)
+	if err == nil && !h.tenancy(r).team(entry.Team) {
+		return models.QueueEntry{}, queue.ErrNotFound
+	}
+	return entry, err
+}
+
+// entryTeam picks the team a new queue entry belongs to: the owner of a pinned
+// printer, else the requested team, else the submitter's only team. Admins may
+// leave it empty to let the entry run anywhere.
+func (h *Handler) entryTeam(r *http.Request, requested, printerID string) (string, error) {
+	if !h.settings.Teams.Enabled {
+		return "", nil
+	}
+	scope := h.tenancy(r)
+
+	if printerID != "" {
+		if !scope.printer(printerID) {
+			return "", errors.New("unknown printer")
+		}
+		owners, err := h.teams.Owners()
+		if err != nil {
+			return "", err
+		}
+		if requested != "" && requested != owners[printerID] {
+			return "", fmt.Errorf("%s does not belong to team %s", printerID, requested)
+		}
+		return owners[printerID], nil
+	}
+
+	if requested != "" {
+		if _, err := h.teams.Get(requested); err != nil || !scope.team(requested) {
+			return "", fmt.Errorf("unknown team %q", requested)
+		}
+		return requested, nil
+	}
+	switch {
+	case scope.all:
+		return "", nil
+	case len(scope.membership.Teams) == 1:
+		return scope.membership.Teams[0], nil
+	case len(scope.membership.Teams) == 0:
+		return "", errors.New("you are not a member of any team")
+	default:
+		return "", errors.New("team is required for members of several teams")
+	}
+}
+
+// handleTeams lists the teams the caller belongs to, or every team for admins
+func (h *Handler) handleTeams(w http.ResponseWriter, r *http.Request) {
+	list, err := h.teams.List()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	scope := h.tenancy(r)
+	visible := []models.Team{}
+	for _, team := range list {
+		if scope.team(team.Name) {
+			visible = append(visible, team)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "ok",
+		"teams":  visible,
+	})
+}
+
+// handleTeamPut creates or replaces the team named in the path. Members are
+// configured users or paired devices, by their device/ user name.
+func (h *Handler) handleTeamPut(w http.ResponseWriter, r *http.Request) {
+	var team models.Team
+	if err := json.NewDecoder(r.Body).Decode(&team); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	team.Name = r.PathValue("name")
+
+	for _, id := range team.Printers {
+		if _, ok := h.findPrinter(id); !ok {
+			writeError(w, http.StatusBadRequest, "Unknown printer "+id)
+			return
+		}
+	}
+	paired, err := h.devices.List()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	for _, member := range team.Members {
+		configured := slices.ContainsFunc(h.settings.Auth.Users, func(u settings.UserSettings) bool { return u.Name == member })
+		device := slices.ContainsFunc(paired, func(d models.Device) bool { return d.User == member })
+		if !configured && !device {
+			writeError(w, http.StatusBadRequest, "Unknown user "+member)
+			return
+		}
+	}
+
+	team, err = h.teams.Put(team, h.clock.Now())
+	if errors.Is(err, teams.ErrInvalidName) {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if errors.Is(err, teams.ErrPrinterTaken) {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "ok",
+		"team":   team,
+	})
+}
+
+// handleTeamDelete removes a team that has no waiting queue entries
+func (h *Handler) handleTeamDelete(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	entries, err := h.queue.List()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	for _, e := range entries {
+		if e.Team == name && e.Status != models.QueueDispatched {
+			writeError(w, http.StatusConflict, "Team has queue entry "+e.ID)
+			return
+		}
+	}
+
+	if isDryRun(r) {
+		_, err = h.teams.Get(name)
+	} else {
+		err = h.teams.Delete(name)
+	}
+	if errors.Is(err, teams.ErrNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if isDryRun(r) {
+		writeDryRun(w, r, "team.delete", 0, []models.DryRunTarget{{Change: "delete team " + name}})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"status": "ok"})
+}
@@ -0,0 +1,195 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestTeams(t *testing.T) {
+	t.Setenv("TEAMS_ENABLED", "true")
+	t.Setenv("AUTH_USERS", "staff:admin:staff-token,ana:operator:ana-token,ben:operator:ben-token")
+	h := newTestHandler(t, testutil.NewSpoolman(t),
+		testutil.Printer{Name: "Mini", Server: testutil.NewOctoPrint(t)},
+		testutil.Printer{Name: "MK4", Server: testutil.NewOctoPrint(t)},
+		testutil.Printer{Name: "XL", Server: testutil.NewOctoPrint(t)},
+	)
+
+	printers := func(token string) []string {
+		var response struct {
+			Printers []models.PrinterStatus `json:"printers"`
+		}
+		json.NewDecoder(doAs(h, token, "GET", "/api/status", "").Body).Decode(&response)
+		ids := []string{}
+		for _, p := range response.Printers {
+			ids = append(ids, p.ID)
+		}
+		return ids
+	}
+	queued := func(token string) []models.QueueEntry {
+		var response struct {
+			Queue []models.QueueEntry `json:"queue"`
+		}
+		json.NewDecoder(doAs(h, token, "GET", "/api/queue", "").Body).Decode(&response)
+		return response.Queue
+	}
+
+	if rec := doAs(h, "ana-token", "PUT", "/api/admin/teams/robotics", `{"printers": ["printer-1"]}`); rec.Code != http.StatusForbidden {
+		t.Errorf("member managing teams = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+	if rec := doAs(h, "staff-token", "PUT", "/api/admin/teams/robotics", `{"printers": ["printer-1"], "members": ["ana"]}`); rec.Code != http.StatusOK {
+		t.Fatalf("create team = %d: %s", rec.Code, rec.Body)
+	}
+	if rec := doAs(h, "staff-token", "PUT", "/api/admin/teams/art", `{"printers": ["printer-1"]}`); rec.Code != http.StatusConflict {
+		t.Errorf("claiming another team's printer = %d, want %d", rec.Code, http.StatusConflict)
+	}
+	if rec := doAs(h, "staff-token", "PUT", "/api/admin/teams/art", `{"members": ["carol"]}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown member = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if rec := doAs(h, "staff-token", "PUT", "/api/admin/teams/art", `{"printers": ["printer-2"], "members": ["ben"]}`); rec.Code != http.StatusOK {
+		t.Fatalf("create team = %d: %s", rec.Code, rec.Body)
+	}
+
+	for token, want := range map[string][]string{
+		"":            {},
+		"ana-token":   {"printer-1"},
+		"ben-token":   {"printer-2"},
+		"staff-token": {"printer-1", "printer-2", "printer-3"},
+	} {
+		if got := printers(token); !slices.Equal(got, want) {
+			t.Errorf("printers for %q = %v, want %v", token, got, want)
+		}
+	}
+	if rec := doAs(h, "ana-token", "GET", "/api/printers/printer-2/status", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("other team's printer = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	if rec := doAs(h, "ana-token", "GET", "/api/printers/printer-1/status", ""); rec.Code != http.StatusOK {
+		t.Errorf("own printer = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	if rec := doAs(h, "ana-token", "POST", "/api/queue", `{"file": "arm.gcode", "printer_id": "printer-2"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("queueing on another team's printer = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	rec := doAs(h, "ana-token", "POST", "/api/queue", `{"file": "arm.gcode"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("queue add = %d: %s", rec.Code, rec.Body)
+	}
+	var added struct {
+		Entry models.QueueEntry `json:"entry"`
+	}
+	json.NewDecoder(rec.Body).Decode(&added)
+	if added.Entry.Team != "robotics" {
+		t.Errorf("entry team = %q, want robotics", added.Entry.Team)
+	}
+
+	if got := queued("ana-token"); len(got) != 1 {
+		t.Errorf("ana sees %d queue entries, want 1", len(got))
+	}
+	if got := queued("ben-token"); len(got) != 0 {
+		t.Errorf("ben sees %d queue entries, want 0", len(got))
+	}
+	if rec := doAs(h, "ben-token", "DELETE", "/api/queue/"+added.Entry.ID, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("removing another team's entry = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	if rec := doAs(h, "staff-token", "DELETE", "/api/admin/teams/robotics", ""); rec.Code != http.StatusConflict {
+		t.Errorf("deleting a team with queued work = %d, want %d", rec.Code, http.StatusConflict)
+	}
+}
+
+func TestTeamsDisabledShowsEverything(t *testing.T) {
+	t.Setenv("AUTH_USERS", "ana:operator:ana-token")
+	h := newTestHandler(t, testutil.NewSpoolman(t),
+		testutil.Printer{Name: "Mini", Server: testutil.NewOctoPrint(t)},
+		testutil.Printer{Name: "MK4", Server: testutil.NewOctoPrint(t)},
+	)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/api/status", nil))
+	var response struct {
+		Printers []models.PrinterStatus `json:"printers"`
+	}
+	json.NewDecoder(rec.Body).Decode(&response)
+	if len(response.Printers) != 2 {
+		t.Errorf("got %d printers without tenancy, want 2", len(response.Printers))
+	}
+}
+
+func TestTeamsScopeForecastAndUpdates(t *testing.T) {
+	t.Setenv("TEAMS_ENABLED", "true")
+	t.Setenv("AUTH_USERS", "staff:admin:staff-token,ana:operator:ana-token")
+	fdm := testutil.NewSpoolman(t, testutil.Spool(5, "Prusament", "PLA", "ff8800", 800))
+	resin := testutil.NewSpoolman(t, testutil.Spool(6, "Elegoo", "Resin", "cccccc", 450))
+	t.Setenv("SPOOLMAN_NAME", "fdm")
+	t.Setenv("SPOOLMAN_2_NAME", "resin")
+	t.Setenv("SPOOLMAN_2_URL", resin.URL)
+	t.Setenv("PRINTER_2_SPOOLMAN", "resin")
+	t.Setenv("UPDATES_CHECK_INTERVAL", "0")
+	h := newTestHandler(t, fdm,
+		testutil.Printer{Name: "MK3", Server: testutil.NewOctoPrint(t)},
+		testutil.Printer{Name: "Mars", Server: testutil.NewOctoPrint(t)},
+	)
+	doAs(h, "staff-token", "PUT", "/api/admin/teams/robotics", `{"printers": ["printer-1"], "members": ["ana"]}`)
+
+	materials := func(token string) []string {
+		var response struct {
+			Forecast models.Forecast `json:"forecast"`
+		}
+		json.NewDecoder(doAs(h, token, "GET", "/api/forecast", "").Body).Decode(&response)
+		got := []string{}
+		for _, m := range response.Forecast.Materials {
+			got = append(got, m.Material)
+		}
+		slices.Sort(got)
+		return got
+	}
+	if got := materials("staff-token"); !slices.Equal(got, []string{"PLA", "Resin"}) {
+		t.Errorf("admin forecast materials = %v, want both instances", got)
+	}
+	if got := materials("ana-token"); !slices.Equal(got, []string{"PLA"}) {
+		t.Errorf("team member forecast materials = %v, want only their printer's Spoolman", got)
+	}
+	if got := materials(""); len(got) != 0 {
+		t.Errorf("anonymous forecast materials = %v, want none", got)
+	}
+
+	updated := func(token string) []string {
+		var response struct {
+			Printers []models.PrinterUpdates `json:"printers"`
+		}
+		json.NewDecoder(doAs(h, token, "GET", "/api/updates", "").Body).Decode(&response)
+		ids := []string{}
+		for _, p := range response.Printers {
+			ids = append(ids, p.PrinterID)
+		}
+		return ids
+	}
+	if got := updated("staff-token"); !slices.Equal(got, []string{"printer-1", "printer-2"}) {
+		t.Errorf("admin updates = %v", got)
+	}
+	if got := updated("ana-token"); !slices.Equal(got, []string{"printer-1"}) {
+		t.Errorf("team member updates = %v, want only their printer", got)
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import "github.com/wmarchesi123/octodash/internal/models"
+
+// watchHeaters raises a high-severity alert for each heater fault in the
+// latest statuses, whatever the firmware's own protections make of it
+func (h *Handler) watchHeaters(printers []*models.PrinterStatus) {
+	if h.thermal == nil {
+		return
+	}
+	for _, p := range printers {
+		for _, f := range h.thermal.Observe(p) {
+			h.logger.Printf("Heater fault on %s: %s", p.Name, f.Message)
+			h.emit(models.EventAlertRaised, p.ID, map[string]interface{}{
+				"kind":     "thermal",
+				"severity": models.SeverityHigh,
+				"fault":    f,
+			})
+		}
+	}
+}
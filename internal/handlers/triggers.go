@@ -0,0 +1,264 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/store"
+)
+
+// maxTriggerLimit caps how many events one poll of a trigger feed returns
+const maxTriggerLimit = 200
+
+// triggersEnabled reports whether TRIGGERS_KEY turned the trigger feeds on
+func (h *Handler) triggersEnabled() bool {
+	return h.settings.Triggers.Key != ""
+}
+
+// recordTrigger keeps an event for the trigger feeds
+func (h *Handler) recordTrigger(e models.Event) {
+	if err := h.store.Put(eventsBucket, e.ID, e); err != nil {
+		h.logger.Printf("Failed to record %s event for triggers: %v", e.Type, err)
+		return
+	}
+	if err := h.store.Trim(eventsBucket, h.settings.Triggers.Retain); err != nil {
+		h.logger.Printf("Failed to trim trigger events: %v", err)
+	}
+}
+
+// triggerEvents returns recorded events newest first, of one type unless
+// eventType is "all", after the since ID and for one printer when given
+func (h *Handler) triggerEvents(eventType, since, printerID string, limit int) ([]models.Event, error) {
+	all, err := store.List[models.Event](h.store, eventsBucket)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(all, func(i, j int) bool { return all[i].ID > all[j].ID })
+
+	events := []models.Event{}
+	for _, e := range all {
+		if len(events) >= limit || (since != "" && e.ID <= since) {
+			break
+		}
+		if (eventType == "all" || e.Type == eventType) && (printerID == "" || e.PrinterID == printerID) {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+// flattenEvent turns an event into one level of fields, as the automation
+// services expect: nested data joins its keys with underscores
+func (h *Handler) flattenEvent(e models.Event) map[string]interface{} {
+	flat := map[string]interface{}{
+		"id":         e.ID,
+		"type":       e.Type,
+		"time":       e.Time,
+		"printer_id": e.PrinterID,
+	}
+	if printer, ok := h.findPrinter(e.PrinterID); ok {
+		flat["printer_name"] = printer.Name
+	}
+
+	var data interface{}
+	if raw, err := json.Marshal(e.Data); err == nil {
+		json.Unmarshal(raw, &data)
+	}
+	var walk func(prefix string, v interface{})
+	walk = func(prefix string, v interface{}) {
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			if _, taken := flat[prefix]; !taken && prefix != "" {
+				flat[prefix] = v
+			}
+			return
+		}
+		for k, child := range obj {
+			if prefix != "" {
+				k = prefix + "_" + k
+			}
+			walk(k, child)
+		}
+	}
+	walk("", data)
+	return flat
+}
+
+// triggerKey reports whether the request presents TRIGGERS_KEY, as Zapier's
+// X-API-Key header or ?key=, or as IFTTT's IFTTT-Service-Key header
+func (h *Handler) triggerKey(r *http.Request) bool {
+	for _, key := range []string{r.Header.Get("X-API-Key"), r.Header.Get("IFTTT-Service-Key"), r.URL.Query().Get("key")} {
+		if key != "" && subtle.ConstantTimeCompare([]byte(key), []byte(h.settings.Triggers.Key)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// handleTriggerFeed serves one event type (or "all") as a bare array, newest
+// first with a unique id, the shape Zapier polling triggers deduplicate on.
+// ?since=<id> returns only newer events.
+func (h *Handler) handleTriggerFeed(w http.ResponseWriter, r *http.Request) {
+	if !h.triggersEnabled() {
+		writeError(w, http.StatusNotFound, "Triggers are not enabled")
+		return
+	}
+	if !h.triggerKey(r) {
+		writeError(w, http.StatusUnauthorized, "Invalid trigger key")
+		return
+	}
+
+	eventType := r.PathValue("type")
+	if eventType != "all" && !slices.Contains(models.EventTypes, eventType) {
+		writeError(w, http.StatusNotFound, "Unknown event type")
+		return
+	}
+
+	q := r.URL.Query()
+	limit := 50
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+		limit = min(n, maxTriggerLimit)
+	}
+
+	events, err := h.triggerEvents(eventType, q.Get("since"), q.Get("printer_id"), limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	feed := make([]map[string]interface{}, len(events))
+	for i, e := range events {
+		feed[i] = h.flattenEvent(e)
+	}
+	writeJSON(w, http.StatusOK, feed)
+}
+
+// iftttError writes errors in the shape IFTTT's service protocol expects
+func iftttError(w http.ResponseWriter, code int, message string) {
+	writeJSON(w, code, map[string]interface{}{
+		"errors": []map[string]string{{"message": message}},
+	})
+}
+
+// requireIFTTT checks the service key IFTTT sends with every request
+func (h *Handler) requireIFTTT(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !h.triggersEnabled() {
+			iftttError(w, http.StatusServiceUnavailable, "Triggers are not enabled")
+			return
+		}
+		if r.Header.Get("IFTTT-Service-Key") == "" || !h.triggerKey(r) {
+			iftttError(w, http.StatusUnauthorized, "Invalid service key")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// handleIFTTTStatus is IFTTT's health check
+func (h *Handler) handleIFTTTStatus(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleIFTTTSetup gives IFTTT's endpoint tests sample trigger fields
+func (h *Handler) handleIFTTTSetup(w http.ResponseWriter, r *http.Request) {
+	triggers := make(map[string]interface{})
+	for _, t := range models.EventTypes {
+		triggers[iftttSlug(t)] = map[string]string{}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"data": map[string]interface{}{"samples": map[string]interface{}{"triggers": triggers}},
+	})
+}
+
+// iftttSlug is an event type as an IFTTT trigger slug, job.finished -> job_finished
+func iftttSlug(eventType string) string {
+	return strings.ReplaceAll(eventType, ".", "_")
+}
+
+// handleIFTTTTrigger answers IFTTT's trigger polls: events newest first with
+// string ingredients and the meta IFTTT deduplicates on
+func (h *Handler) handleIFTTTTrigger(w http.ResponseWriter, r *http.Request) {
+	eventType := ""
+	for _, t := range models.EventTypes {
+		if iftttSlug(t) == r.PathValue("slug") {
+			eventType = t
+		}
+	}
+	if eventType == "" {
+		iftttError(w, http.StatusNotFound, "Unknown trigger")
+		return
+	}
+
+	var req struct {
+		Limit         *int              `json:"limit"`
+		TriggerFields map[string]string `json:"triggerFields"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		iftttError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	limit := 50
+	if req.Limit != nil {
+		limit = min(max(*req.Limit, 0), maxTriggerLimit)
+	}
+
+	events, err := h.triggerEvents(eventType, "", req.TriggerFields["printer_id"], limit)
+	if err != nil {
+		iftttError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	data := make([]map[string]interface{}, len(events))
+	for i, e := range events {
+		item := make(map[string]interface{})
+		for k, v := range h.flattenEvent(e) {
+			item[k] = ingredient(v)
+		}
+		item["meta"] = map[string]interface{}{"id": e.ID, "timestamp": e.Time.Unix()}
+		data[i] = item
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": data})
+}
+
+// ingredient renders a field as the string IFTTT shows in applets
+func ingredient(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		raw, _ := json.Marshal(v)
+		return strings.Trim(string(raw), `"`)
+	}
+}
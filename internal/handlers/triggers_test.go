@@ -0,0 +1,83 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestTriggers(t *testing.T) {
+	t.Setenv("TRIGGERS_KEY", "zap-key")
+	op := testutil.NewOctoPrint(t)
+	op.SetPrinting("gear.gcode", 98, 60)
+	h := newTestHandler(t, testutil.NewSpoolman(t), testutil.Printer{Name: "Mini", Server: op})
+
+	getStatus(t, h)
+	op.SetFinished("gear.gcode")
+	getStatus(t, h)
+
+	do := func(method, path, body string, header ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		for i := 0; i+1 < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := do("GET", "/api/triggers/job.finished?key=wrong", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("wrong key = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+
+	var feed []map[string]interface{}
+	json.NewDecoder(do("GET", "/api/triggers/job.finished", "", "X-API-Key", "zap-key").Body).Decode(&feed)
+	if len(feed) != 1 || feed[0]["file"] != "gear.gcode" || feed[0]["result"] != "completed" || feed[0]["printer_name"] != "Mini" {
+		t.Fatalf("job.finished feed = %+v", feed)
+	}
+
+	var all []map[string]interface{}
+	json.NewDecoder(do("GET", "/api/triggers/all?key=zap-key", "").Body).Decode(&all)
+	if len(all) != 3 || all[0]["id"].(string) <= all[2]["id"].(string) {
+		t.Fatalf("all feed = %+v, want 3 events newest first", all)
+	}
+	var newer []map[string]interface{}
+	json.NewDecoder(do("GET", "/api/triggers/all?key=zap-key&since="+all[1]["id"].(string), "").Body).Decode(&newer)
+	if len(newer) != 1 || newer[0]["id"] != all[0]["id"] {
+		t.Errorf("since feed = %+v, want only the newest event", newer)
+	}
+
+	if rec := do("POST", "/ifttt/v1/triggers/job_finished", `{}`); rec.Code != http.StatusUnauthorized {
+		t.Errorf("IFTTT without service key = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+	var ifttt struct {
+		Data []map[string]interface{} `json:"data"`
+	}
+	rec := do("POST", "/ifttt/v1/triggers/job_finished", `{"limit": 5, "triggerFields": {}}`, "IFTTT-Service-Key", "zap-key")
+	json.NewDecoder(rec.Body).Decode(&ifttt)
+	if len(ifttt.Data) != 1 || ifttt.Data[0]["completion"] != "100" || ifttt.Data[0]["meta"].(map[string]interface{})["id"] != feed[0]["id"] {
+		t.Errorf("IFTTT trigger = %d %+v", rec.Code, ifttt.Data)
+	}
+}
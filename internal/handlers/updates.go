@@ -0,0 +1,65 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/updates"
+)
+
+// startUpdateChecks builds the update checker and runs it every UPDATES_CHECK_INTERVAL
+func (h *Handler) startUpdateChecks(ctx context.Context) {
+	printers := make([]updates.Printer, 0, len(h.config.Printers))
+	for _, p := range h.config.Printers {
+		printers = append(printers, updates.Printer{ID: p.ID, Name: p.Name, Client: liveControl{h, p.ID}})
+	}
+
+	h.updates = updates.New(printers, h.settings.Updates.CheckInterval, h.logger)
+	if h.settings.Updates.CheckInterval > 0 {
+		go h.updates.Run(ctx)
+	}
+}
+
+// handleUpdates reports pending updates for the printers the caller's teams can see
+func (h *Handler) handleUpdates(w http.ResponseWriter, r *http.Request) {
+	visible := make(map[string]bool)
+	for _, p := range h.printersMatching(r, "") {
+		visible[p.ID] = true
+	}
+	all := []models.PrinterUpdates{}
+	for _, u := range h.updates.All() {
+		if visible[u.PrinterID] {
+			all = append(all, u)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":   "ok",
+		"summary":  updates.Summarize(all),
+		"printers": all,
+	})
+}
+
+func (h *Handler) handleUpdateCheck(w http.ResponseWriter, r *http.Request) {
+	h.updates.Check()
+	h.handleUpdates(w, r)
+}
@@ -0,0 +1,109 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/octoapi"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestUpdatesSummaryAndBadges(t *testing.T) {
+	sm := testutil.NewSpoolman(t)
+	prusa := testutil.NewOctoPrint(t)
+	prusa.SetFirmware("Prusa-Firmware 3.13.3")
+	prusa.SetSoftwareUpdates(
+		octoapi.SoftwareUpdate{ID: "octoprint", Name: "OctoPrint", Installed: "1.9.3", Available: "1.10.2", UpdateAvailable: true},
+		octoapi.SoftwareUpdate{ID: "spoolman", Name: "Spoolman", Installed: "1.0.0", Available: "1.0.0"},
+	)
+	voron := testutil.NewOctoPrint(t)
+	voron.SetFirmware("Klipper v0.12.0")
+	voron.SetSoftwareUpdates(
+		octoapi.SoftwareUpdate{ID: "octoprint", Name: "OctoPrint", Installed: "1.10.2", Available: "1.10.2"},
+	)
+	h := newTestHandler(t, sm,
+		testutil.Printer{Name: "Prusa", Server: prusa},
+		testutil.Printer{Name: "Voron", Server: voron},
+	)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("POST", "/api/updates/check", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST /api/updates/check = %d: %s", rec.Code, rec.Body)
+	}
+
+	var response struct {
+		Summary  models.UpdateSummary    `json:"summary"`
+		Printers []models.PrinterUpdates `json:"printers"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+
+	summary := response.Summary
+	if summary.PrintersChecked != 2 || summary.PrintersWithUpdates != 1 {
+		t.Errorf("summary counts = %d checked, %d with updates", summary.PrintersChecked, summary.PrintersWithUpdates)
+	}
+	if len(summary.Components) != 1 || summary.Components[0].ID != "octoprint" ||
+		len(summary.Components[0].Printers) != 1 || summary.Components[0].Printers[0] != "printer-1" {
+		t.Errorf("summary components = %+v", summary.Components)
+	}
+	if got := summary.Firmware["Klipper v0.12.0"]; len(got) != 1 || got[0] != "printer-2" {
+		t.Errorf("firmware summary = %v", summary.Firmware)
+	}
+	if len(response.Printers) != 2 || response.Printers[0].Available != 1 || len(response.Printers[0].Components) != 2 {
+		t.Errorf("printers = %+v", response.Printers)
+	}
+
+	printers := getStatus(t, h)
+	badge := printers["printer-1"].Updates
+	if badge == nil || badge.Available != 1 || badge.Firmware != "Prusa-Firmware 3.13.3" {
+		t.Fatalf("printer-1 badge = %+v", badge)
+	}
+	if len(badge.Components) != 1 || badge.Components[0] != "OctoPrint" {
+		t.Errorf("printer-1 badge components = %v", badge.Components)
+	}
+	if badge := printers["printer-2"].Updates; badge == nil || badge.Available != 0 {
+		t.Errorf("printer-2 badge = %+v", badge)
+	}
+}
+
+func TestUpdateCheckFailureKeepsPreviousResults(t *testing.T) {
+	sm := testutil.NewSpoolman(t)
+	op := testutil.NewOctoPrint(t)
+	op.SetSoftwareUpdates(octoapi.SoftwareUpdate{ID: "octoprint", Name: "OctoPrint", Installed: "1.9.3", Available: "1.10.2", UpdateAvailable: true})
+	h := newTestHandler(t, sm, testutil.Printer{Name: "Prusa", Server: op})
+
+	h.updates.Check()
+	op.SetFault(testutil.Fault{StatusCode: http.StatusServiceUnavailable})
+	h.updates.Check()
+
+	result, ok := h.updates.Get("printer-1")
+	if !ok {
+		t.Fatal("printer-1 was never checked")
+	}
+	if result.Error == "" || result.Available != 1 {
+		t.Errorf("result = %+v, want the earlier update kept alongside the error", result)
+	}
+}
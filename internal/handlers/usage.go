@@ -0,0 +1,177 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/wmarchesi123/go-3dprint-client/config"
+	"github.com/wmarchesi123/go-3dprint-client/octoprint"
+	"github.com/wmarchesi123/octodash/internal/auth"
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/usage"
+)
+
+// errQuotaExceeded rejects new queue entries from users over their monthly quota
+var errQuotaExceeded = errors.New("monthly quota exceeded")
+
+// recordUsage charges a stopped job, including the kWh its printer's plug
+// measured, to the user who queued it. Jobs started outside the queue have no
+// submitter and are not charged.
+func (h *Handler) recordUsage(printer config.Printer, job *octoprint.JobResponse, kwh float64) {
+	entries, err := h.queue.List()
+	if err != nil {
+		h.logger.Printf("Failed to load queue for usage on %s: %v", printer.Name, err)
+		return
+	}
+
+	// The most recent dispatch of the file to this printer is the job that stopped
+	var entry *models.QueueEntry
+	file := job.Job.File
+	for i, e := range entries {
+		if e.Status != models.QueueDispatched || e.DispatchedTo != printer.ID {
+			continue
+		}
+		if e.File != file.Name && e.File != file.Display && e.File != file.Path {
+			continue
+		}
+		if entry == nil || e.DispatchedAt.After(*entry.DispatchedAt) {
+			entry = &entries[i]
+		}
+	}
+	if entry == nil || entry.SubmittedBy == "" {
+		return
+	}
+	if done, err := h.usage.Recorded(entry.ID); err != nil || done {
+		return
+	}
+
+	share := math.Min(math.Max(job.Progress.Completion/100, 0), 1)
+	rec := models.UsageRecord{
+		Entry:      entry.ID,
+		User:       entry.SubmittedBy,
+		PrinterID:  printer.ID,
+		File:       entry.File,
+		PrintTime:  job.Progress.PrintTime,
+		Filament:   job.Job.Filament.Tool0.Length / 1000 * share,
+		EnergyKWh:  kwh,
+		Cost:       kwh * h.settings.Energy.PricePerKWh,
+		Completed:  share >= 1,
+		FinishedAt: h.clock.Now(),
+	}
+	if err := h.usage.Record(rec); err != nil {
+		h.logger.Printf("Failed to record usage for %s on %s: %v", entry.File, printer.Name, err)
+	}
+}
+
+// quotaApplies reports whether monthly quotas limit a user
+func (h *Handler) quotaApplies(user *auth.User) bool {
+	q := h.settings.Quota
+	if !h.auth.Enabled() || (q.MonthlyHours <= 0 && q.MonthlyFilament <= 0) {
+		return false
+	}
+	return !slices.Contains(q.Exempt, user.Name)
+}
+
+// checkQuota refuses new work from a user who has used up this month's quota
+func (h *Handler) checkQuota(r *http.Request) error {
+	user := auth.UserFromContext(r.Context())
+	if !h.quotaApplies(user) {
+		return nil
+	}
+
+	u, err := h.usage.User(user.Name, h.clock.Now().In(h.settings.Timezone))
+	if err != nil {
+		return err
+	}
+	q := h.settings.Quota
+	if q.MonthlyHours > 0 && u.Hours() >= q.MonthlyHours {
+		return fmt.Errorf("%w: %.1f of %g print hours used", errQuotaExceeded, u.Hours(), q.MonthlyHours)
+	}
+	if q.MonthlyFilament > 0 && u.Filament >= q.MonthlyFilament {
+		return fmt.Errorf("%w: %.1f of %g m of filament used", errQuotaExceeded, u.Filament, q.MonthlyFilament)
+	}
+	return nil
+}
+
+// writeQuotaError answers a failed quota check
+func writeQuotaError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errQuotaExceeded) {
+		writeError(w, http.StatusForbidden, err.Error())
+		return
+	}
+	writeError(w, http.StatusInternalServerError, err.Error())
+}
+
+// handleUsage returns the caller's usage this month and the quota it counts against
+func (h *Handler) handleUsage(w http.ResponseWriter, r *http.Request) {
+	user := auth.UserFromContext(r.Context())
+	now := h.clock.Now().In(h.settings.Timezone)
+	u, err := h.usage.User(user.Name, now)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := map[string]interface{}{
+		"status": "ok",
+		"month":  now.Format("2006-01"),
+		"usage":  u,
+	}
+	if h.quotaApplies(user) {
+		response["quota"] = map[string]float64{
+			"hours":      h.settings.Quota.MonthlyHours,
+			"filament_m": h.settings.Quota.MonthlyFilament,
+		}
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleUsageReport totals every user's usage for ?month=YYYY-MM, this month by default
+func (h *Handler) handleUsageReport(w http.ResponseWriter, r *http.Request) {
+	month := h.clock.Now().In(h.settings.Timezone)
+	if v := r.URL.Query().Get("month"); v != "" {
+		var err error
+		if month, err = time.ParseInLocation("2006-01", v, h.settings.Timezone); err != nil {
+			writeError(w, http.StatusBadRequest, "month must look like 2006-01")
+			return
+		}
+	}
+
+	totals, err := h.usage.Month(month)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := map[string]interface{}{
+		"status": "ok",
+		"month":  month.Format("2006-01"),
+		"usage":  usage.Sorted(totals),
+	}
+	if h.settings.Energy.PricePerKWh > 0 {
+		response["currency"] = h.settings.Energy.Currency
+	}
+	writeJSON(w, http.StatusOK, response)
+}
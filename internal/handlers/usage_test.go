@@ -0,0 +1,223 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/energy"
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestUsageQuota(t *testing.T) {
+	t.Setenv("AUTH_USERS", "ana:operator:ana-token,staff:operator:staff-token")
+	t.Setenv("QUOTA_MONTHLY_HOURS", "1")
+	t.Setenv("QUOTA_EXEMPT", "staff")
+	op := testutil.NewOctoPrint(t)
+	h := newTestHandler(t, testutil.NewSpoolman(t), testutil.Printer{Name: "Mini", Server: op})
+
+	enqueue := func(token, file string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/queue", strings.NewReader(`{"file": "`+file+`"}`))
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := enqueue("ana-token", "bracket.gcode")
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("first job = %d: %s", rec.Code, rec.Body)
+	}
+	h.dispatchOnce()
+	if got := op.Printed(); len(got) != 1 || got[0] != "bracket.gcode" {
+		t.Fatalf("printed %v, want bracket.gcode", got)
+	}
+
+	// An hour-long print uses up the quota once it finishes
+	op.SetPrinting("bracket.gcode", 50, 1800)
+	getStatus(t, h)
+	op.SetFinished("bracket.gcode")
+	getStatus(t, h)
+	getStatus(t, h)
+
+	req := httptest.NewRequest("GET", "/api/usage", nil)
+	req.Header.Set("Authorization", "Bearer ana-token")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	var response struct {
+		Usage models.Usage       `json:"usage"`
+		Quota map[string]float64 `json:"quota"`
+	}
+	json.NewDecoder(rec.Body).Decode(&response)
+	if response.Usage.Jobs != 1 || response.Usage.PrintTime != 3600 {
+		t.Errorf("usage = %+v, want one hour-long job", response.Usage)
+	}
+	if response.Quota["hours"] != 1 {
+		t.Errorf("quota = %v, want 1 hour", response.Quota)
+	}
+
+	rec = enqueue("ana-token", "gear.gcode")
+	if rec.Code != http.StatusForbidden || !strings.Contains(rec.Body.String(), "quota exceeded") {
+		t.Errorf("job over quota = %d %s, want %d", rec.Code, rec.Body, http.StatusForbidden)
+	}
+	if rec := enqueue("staff-token", "gear.gcode"); rec.Code != http.StatusCreated {
+		t.Errorf("exempt user = %d, want %d", rec.Code, http.StatusCreated)
+	}
+}
+
+func TestUsageChargedForJobCancelledWhilePaused(t *testing.T) {
+	t.Setenv("AUTH_USERS", "ana:operator:ana-token")
+	op := testutil.NewOctoPrint(t)
+	h := newTestHandler(t, testutil.NewSpoolman(t), testutil.Printer{Name: "Mini", Server: op})
+
+	if rec := doAs(h, "ana-token", "POST", "/api/queue", `{"file": "bracket.gcode"}`); rec.Code != http.StatusCreated {
+		t.Fatalf("enqueue = %d: %s", rec.Code, rec.Body)
+	}
+	h.dispatchOnce()
+	op.SetPrinting("bracket.gcode", 50, 1800)
+	getStatus(t, h)
+	op.SetPaused()
+	getStatus(t, h)
+	op.SetCancelled()
+	getStatus(t, h)
+
+	var response struct {
+		Usage models.Usage `json:"usage"`
+	}
+	json.Unmarshal(doAs(h, "ana-token", "GET", "/api/usage", "").Body.Bytes(), &response)
+	if response.Usage.Jobs != 1 || response.Usage.PrintTime != 1800 {
+		t.Errorf("usage = %+v, want the half-hour before the cancel charged", response.Usage)
+	}
+}
+
+// meter is a smart plug whose running total a test can advance
+type meter struct {
+	mu  sync.Mutex
+	kwh float64
+}
+
+func (m *meter) Read(context.Context) (energy.Reading, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return energy.Reading{Watts: 100, TotalKWh: m.kwh}, nil
+}
+
+func (m *meter) set(kwh float64) {
+	m.mu.Lock()
+	m.kwh = kwh
+	m.mu.Unlock()
+}
+
+func TestJobEnergy(t *testing.T) {
+	t.Setenv("AUTH_USERS", "ada:admin:ada-token,ana:operator:ana-token")
+	t.Setenv("ENERGY_POLL_INTERVAL", "5ms")
+	t.Setenv("ENERGY_PRICE_PER_KWH", "0.30")
+	t.Setenv("ENERGY_CURRENCY", "EUR")
+	op := testutil.NewOctoPrint(t)
+	plug := &meter{kwh: 5}
+	h := newHandlerWithConfig(t, testutil.Config(testutil.NewSpoolman(t), testutil.Printer{Name: "Mini", Server: op}),
+		WithEnergyReaders(map[string]energy.Reader{"printer-1": plug}))
+
+	waitFor := func(kwh float64) { waitForReading(t, h, "printer-1", kwh) }
+
+	if rec := doAs(h, "ana-token", "POST", "/api/queue", `{"file": "bracket.gcode"}`); rec.Code != http.StatusCreated {
+		t.Fatalf("enqueue = %d: %s", rec.Code, rec.Body)
+	}
+	h.dispatchOnce()
+	waitFor(5)
+	op.SetPrinting("bracket.gcode", 50, 1800)
+	getStatus(t, h)
+
+	plug.set(6.5)
+	waitFor(6.5)
+	op.SetFinished("bracket.gcode")
+	getStatus(t, h)
+	getStatus(t, h)
+
+	var history struct {
+		Jobs []models.JobRecord `json:"jobs"`
+	}
+	json.Unmarshal(doAs(h, "ada-token", "GET", "/api/jobs", "").Body.Bytes(), &history)
+	if len(history.Jobs) != 1 || history.Jobs[0].EnergyKWh != 1.5 {
+		t.Errorf("job history = %+v, want one job using 1.5 kWh", history.Jobs)
+	}
+
+	var report struct {
+		Usage    []models.Usage `json:"usage"`
+		Currency string         `json:"currency"`
+	}
+	json.Unmarshal(doAs(h, "ada-token", "GET", "/api/admin/usage", "").Body.Bytes(), &report)
+	if len(report.Usage) != 1 || report.Usage[0].EnergyKWh != 1.5 || report.Usage[0].Cost < 0.449 || report.Usage[0].Cost > 0.451 || report.Currency != "EUR" {
+		t.Errorf("usage report = %+v %q, want 1.5 kWh costing 0.45 EUR", report.Usage, report.Currency)
+	}
+}
+
+func TestJobEnergyThroughPause(t *testing.T) {
+	t.Setenv("ENERGY_POLL_INTERVAL", "5ms")
+	op := testutil.NewOctoPrint(t)
+	plug := &meter{kwh: 5}
+	h := newHandlerWithConfig(t, testutil.Config(testutil.NewSpoolman(t), testutil.Printer{Name: "Mini", Server: op}),
+		WithEnergyReaders(map[string]energy.Reader{"printer-1": plug}))
+
+	waitForReading(t, h, "printer-1", 5)
+	op.SetPrinting("bracket.gcode", 20, 2400)
+	getStatus(t, h)
+
+	// A pause doesn't start the job's count over
+	plug.set(6)
+	waitForReading(t, h, "printer-1", 6)
+	op.SetPaused()
+	getStatus(t, h)
+
+	plug.set(6.5)
+	waitForReading(t, h, "printer-1", 6.5)
+	op.SetPrinting("bracket.gcode", 90, 300)
+	if p := getStatus(t, h)["printer-1"]; p.Power == nil || p.Power.JobEnergyKWh != 1.5 {
+		t.Errorf("power mid-job = %+v, want 1.5 kWh since the job started", p.Power)
+	}
+
+	op.SetFinished("bracket.gcode")
+	getStatus(t, h)
+	var history struct {
+		Jobs []models.JobRecord `json:"jobs"`
+	}
+	json.Unmarshal(doAs(h, "", "GET", "/api/jobs", "").Body.Bytes(), &history)
+	if len(history.Jobs) != 1 || history.Jobs[0].EnergyKWh != 1.5 {
+		t.Errorf("job history = %+v, want one job using 1.5 kWh", history.Jobs)
+	}
+}
+
+// waitForReading lets the energy monitor pick up a plug's latest total
+func waitForReading(t *testing.T, h *Handler, printerID string, kwh float64) {
+	t.Helper()
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		if sample, ok := h.energyMonitor.Latest(printerID); ok && sample.TotalKWh == kwh {
+			return
+		}
+	}
+	t.Fatalf("monitor never read %v kWh", kwh)
+}
@@ -0,0 +1,187 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/wmarchesi123/octodash/internal/auth"
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/views"
+)
+
+var viewSorts = map[string]bool{
+	models.ViewSortConfig:   true,
+	models.ViewSortName:     true,
+	models.ViewSortStatus:   true,
+	models.ViewSortProgress: true,
+}
+
+var viewDensities = map[string]bool{
+	models.ViewDensityComfortable: true,
+	models.ViewDensityCompact:     true,
+}
+
+// handleViewList returns the caller's saved views and which one is their default
+func (h *Handler) handleViewList(w http.ResponseWriter, r *http.Request) {
+	user := auth.UserFromContext(r.Context()).Name
+	list, err := h.views.List(user)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	def, err := h.views.Default(user)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "ok",
+		"views":   list,
+		"default": def,
+	})
+}
+
+func (h *Handler) handleViewCreate(w http.ResponseWriter, r *http.Request) {
+	view, ok := h.decodeView(w, r)
+	if !ok {
+		return
+	}
+
+	view, err := h.views.Create(auth.UserFromContext(r.Context()).Name, view, h.clock.Now())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"status": "ok",
+		"view":   view,
+	})
+}
+
+func (h *Handler) handleViewUpdate(w http.ResponseWriter, r *http.Request) {
+	view, ok := h.decodeView(w, r)
+	if !ok {
+		return
+	}
+	view.ID = r.PathValue("id")
+
+	view, err := h.views.Update(auth.UserFromContext(r.Context()).Name, view, h.clock.Now())
+	if errors.Is(err, views.ErrNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "ok",
+		"view":   view,
+	})
+}
+
+func (h *Handler) handleViewDelete(w http.ResponseWriter, r *http.Request) {
+	err := h.views.Delete(auth.UserFromContext(r.Context()).Name, r.PathValue("id"))
+	if errors.Is(err, views.ErrNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"status": "ok"})
+}
+
+// handleViewDefault sets the view the caller's dashboard opens with; an empty
+// view_id goes back to showing every printer
+func (h *Handler) handleViewDefault(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ViewID string `json:"view_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	err := h.views.SetDefault(auth.UserFromContext(r.Context()).Name, req.ViewID)
+	if errors.Is(err, views.ErrNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"status": "ok", "default": req.ViewID})
+}
+
+// decodeView reads and validates a view from the request body, writing an
+// error response if it is not usable
+func (h *Handler) decodeView(w http.ResponseWriter, r *http.Request) (models.View, bool) {
+	var view models.View
+	if err := json.NewDecoder(r.Body).Decode(&view); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return view, false
+	}
+	if err := h.validateView(&view); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return view, false
+	}
+	return view, true
+}
+
+// validateView checks a view and fills in the default sort and density
+func (h *Handler) validateView(view *models.View) error {
+	view.Name = strings.TrimSpace(view.Name)
+	if view.Name == "" || len(view.Name) > 64 {
+		return errors.New("name is required and may be at most 64 characters")
+	}
+	if view.Printers == nil {
+		view.Printers = []string{}
+	}
+	for _, id := range view.Printers {
+		if _, ok := h.findPrinter(id); !ok {
+			return fmt.Errorf("unknown printer %q", id)
+		}
+	}
+	if view.Sort == "" {
+		view.Sort = models.ViewSortConfig
+	}
+	if !viewSorts[view.Sort] {
+		return fmt.Errorf("unknown sort %q", view.Sort)
+	}
+	if view.Density == "" {
+		view.Density = models.ViewDensityComfortable
+	}
+	if !viewDensities[view.Density] {
+		return fmt.Errorf("unknown density %q", view.Density)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestSavedViews(t *testing.T) {
+	t.Setenv("AUTH_USERS", "manager:viewer:manager-token,tech:operator:tech-token")
+	h := newTestHandler(t, testutil.NewSpoolman(t),
+		testutil.Printer{Name: "Mini", Server: testutil.NewOctoPrint(t)},
+		testutil.Printer{Name: "MK4", Server: testutil.NewOctoPrint(t)},
+	)
+
+	list := func(token string) (views []models.View, def string) {
+		var response struct {
+			Views   []models.View `json:"views"`
+			Default string        `json:"default"`
+		}
+		rec := doAs(h, token, "GET", "/api/views", "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET /api/views = %d", rec.Code)
+		}
+		json.NewDecoder(rec.Body).Decode(&response)
+		return response.Views, response.Default
+	}
+
+	if rec := doAs(h, "", "GET", "/api/views", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("anonymous list = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+	for body, why := range map[string]string{
+		`{"name": ""}`: "missing name",
+		`{"name": "x", "printers": ["printer-9"]}`: "unknown printer",
+		`{"name": "x", "sort": "colour"}`:          "unknown sort",
+		`{"name": "x", "density": "tiny"}`:         "unknown density",
+	} {
+		if rec := doAs(h, "manager-token", "POST", "/api/views", body); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s = %d, want %d", why, rec.Code, http.StatusBadRequest)
+		}
+	}
+
+	rec := doAs(h, "manager-token", "POST", "/api/views", `{"name": "Overview", "sort": "status", "density": "compact"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create = %d: %s", rec.Code, rec.Body)
+	}
+	if rec := doAs(h, "tech-token", "POST", "/api/views", `{"name": "Bench", "printers": ["printer-2"]}`); rec.Code != http.StatusCreated {
+		t.Fatalf("create = %d: %s", rec.Code, rec.Body)
+	}
+
+	views, _ := list("manager-token")
+	if len(views) != 1 || views[0].Name != "Overview" || views[0].Density != "compact" {
+		t.Fatalf("manager's views = %+v", views)
+	}
+	views, _ = list("tech-token")
+	if len(views) != 1 || views[0].Name != "Bench" || views[0].Sort != "config" {
+		t.Fatalf("tech's views = %+v", views)
+	}
+	id := views[0].ID
+
+	if rec := doAs(h, "tech-token", "PUT", "/api/views/"+id, `{"name": "Bench", "printers": ["printer-1", "printer-2"]}`); rec.Code != http.StatusOK {
+		t.Errorf("update = %d: %s", rec.Code, rec.Body)
+	}
+	if rec := doAs(h, "tech-token", "PUT", "/api/views/0000000099", `{"name": "Missing"}`); rec.Code != http.StatusNotFound {
+		t.Errorf("updating a missing view = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+
+	if rec := doAs(h, "tech-token", "PUT", "/api/views/default", `{"view_id": "`+id+`"}`); rec.Code != http.StatusOK {
+		t.Fatalf("set default = %d: %s", rec.Code, rec.Body)
+	}
+	if _, def := list("tech-token"); def != id {
+		t.Errorf("default = %q, want %q", def, id)
+	}
+
+	if rec := doAs(h, "tech-token", "DELETE", "/api/views/"+id, ""); rec.Code != http.StatusOK {
+		t.Fatalf("delete = %d", rec.Code)
+	}
+	if views, def := list("tech-token"); len(views) != 0 || def != "" {
+		t.Errorf("after delete views = %+v, default = %q", views, def)
+	}
+}
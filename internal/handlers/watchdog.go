@@ -0,0 +1,72 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+)
+
+// watchProgress flags stalled prints and raises alerts for prints the
+// watchdogs find stuck
+func (h *Handler) watchProgress(printers []*models.PrinterStatus, now time.Time) {
+	for _, p := range printers {
+		h.watchStall(p, now)
+		if h.firstLayer == nil {
+			continue
+		}
+		if stuck, ok := h.firstLayer.Observe(p, now); ok {
+			h.logger.Printf("%s has been on the first layer of %s for %s", p.Name, p.Progress.FileName, stuck.Round(time.Minute))
+			h.emit(models.EventAlertRaised, p.ID, map[string]interface{}{
+				"kind":       "first_layer",
+				"severity":   models.SeverityWarning,
+				"file":       p.Progress.FileName,
+				"completion": p.Progress.Completion,
+				"minutes":    int(stuck.Minutes()),
+			})
+		}
+	}
+}
+
+// watchStall marks a print whose file position has stopped, and alerts once
+// per stall when STALL_NOTIFY is set
+func (h *Handler) watchStall(p *models.PrinterStatus, now time.Time) {
+	if h.stalls == nil {
+		return
+	}
+	stall, first := h.stalls.Observe(p, now)
+	if stall == nil {
+		return
+	}
+	p.Stall = stall
+	if !first {
+		return
+	}
+
+	h.logger.Printf("%s has not advanced through %s since %s", p.Name, p.Progress.FileName, stall.Since.Format(time.RFC3339))
+	if h.settings.Stall.Notify {
+		h.emit(models.EventAlertRaised, p.ID, map[string]interface{}{
+			"kind":     "stall",
+			"severity": models.SeverityHigh,
+			"file":     p.Progress.FileName,
+			"stall":    *stall,
+		})
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+// announcements returns the texts announced so far
+func announcements(t *testing.T, h *Handler) []string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/api/announcements", nil))
+	var response struct {
+		Announcements []models.Announcement `json:"announcements"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	var texts []string
+	for _, a := range response.Announcements {
+		texts = append(texts, a.Text)
+	}
+	return texts
+}
+
+func TestFirstLayerWatchdog(t *testing.T) {
+	t.Setenv("FIRST_LAYER_TIMEOUT", "15m")
+	op := testutil.NewOctoPrint(t)
+	op.SetPrinting("gear.gcode", 1, 3000)
+	clock := &stepClock{now: time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)}
+	h := newHandlerWithConfig(t, testutil.Config(testutil.NewSpoolman(t), testutil.Printer{Name: "Mini", Server: op}), WithClock(clock))
+
+	for i := 0; i < 4; i++ {
+		getStatus(t, h)
+		clock.advance(5 * time.Minute)
+	}
+	getStatus(t, h)
+
+	texts := announcements(t, h)
+	if len(texts) != 1 || texts[0] != "Mini has been on the first layer of gear for 15 minutes" {
+		t.Errorf("announcements = %q", texts)
+	}
+}
+
+func TestStallWatchdog(t *testing.T) {
+	t.Setenv("STALL_NOTIFY", "true")
+	op := testutil.NewOctoPrint(t)
+	op.SetFile("gear.gcode", make([]byte, 1000))
+	op.SetPrinting("gear.gcode", 10, 3000)
+	clock := &stepClock{now: time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)}
+	h := newHandlerWithConfig(t, testutil.Config(testutil.NewSpoolman(t), testutil.Printer{Name: "Mini", Server: op}), WithClock(clock))
+
+	getStatus(t, h)
+	clock.advance(6 * time.Minute)
+	op.SetPrinting("gear.gcode", 20, 2400)
+	moved := clock.Now()
+	getStatus(t, h)
+	clock.advance(6 * time.Minute)
+	if p := getStatus(t, h)["printer-1"]; p.Stall != nil {
+		t.Fatalf("flagged a stall after 6m: %+v", p.Stall)
+	}
+
+	clock.advance(5 * time.Minute)
+	p := getStatus(t, h)["printer-1"]
+	if p.Stall == nil || !p.Stall.Since.Equal(moved) || p.Stall.Filepos != 200 {
+		t.Fatalf("stall = %+v, want one since %s at byte 200", p.Stall, moved)
+	}
+	getStatus(t, h)
+	if texts := announcements(t, h); len(texts) != 1 || texts[0] != "Mini has stopped making progress on gear" {
+		t.Errorf("announcements = %q", texts)
+	}
+
+	op.SetPrinting("gear.gcode", 30, 2000)
+	if p := getStatus(t, h)["printer-1"]; p.Stall != nil {
+		t.Errorf("stall still flagged after the print moved on: %+v", p.Stall)
+	}
+}
@@ -0,0 +1,215 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/auth"
+	"github.com/wmarchesi123/octodash/internal/models"
+)
+
+const (
+	// cameraBucket keeps the key camera URLs are signed with across restarts
+	cameraBucket = "camera"
+	// cameraURLTTL is how long a signed camera URL can be opened for; a
+	// stream already open keeps running
+	cameraURLTTL = 15 * time.Minute
+	// cameraChunk is how much of a stream is relayed between flushes
+	cameraChunk = 32 << 10
+)
+
+// cameraSigningKey loads the key camera URLs are signed with, creating it
+// on first use
+func (h *Handler) cameraSigningKey() ([]byte, error) {
+	var key string
+	ok, err := h.store.Get(cameraBucket, "signing_key", &key)
+	if err != nil || ok {
+		return []byte(key), err
+	}
+
+	buf := make([]byte, 32)
+	rand.Read(buf)
+	key = hex.EncodeToString(buf)
+	return []byte(key), h.store.Put(cameraBucket, "signing_key", key)
+}
+
+// cameraSignature signs a user's access to one printer's camera until exp
+func (h *Handler) cameraSignature(printerID, user, role string, exp int64) string {
+	mac := hmac.New(sha256.New, h.cameraKey)
+	mac.Write([]byte(printerID + "\n" + user + "\n" + role + "\n" + strconv.FormatInt(exp, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// signedCameraURL returns path with a query letting the user open it without
+// headers, as <img> tags must
+func (h *Handler) signedCameraURL(path, printerID string, user *auth.User, expires time.Time) string {
+	exp := expires.Unix()
+	q := url.Values{}
+	q.Set("user", user.Name)
+	q.Set("role", user.Role.String())
+	q.Set("exp", strconv.FormatInt(exp, 10))
+	q.Set("sig", h.cameraSignature(printerID, user.Name, user.Role.String(), exp))
+	return path + "?" + q.Encode()
+}
+
+// signedCameras signs in the user a camera URL was signed for, with the role
+// they had when it was signed, so teams and roles apply as if they had sent
+// their token
+func (h *Handler) signedCameras(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("sig") == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		id, ok := cameraPath(r.URL.Path)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		user := q.Get("user")
+		exp, err := strconv.ParseInt(q.Get("exp"), 10, 64)
+		want := h.cameraSignature(id, user, q.Get("role"), exp)
+		if err != nil || !hmac.Equal([]byte(want), []byte(q.Get("sig"))) {
+			writeError(w, http.StatusForbidden, "Invalid camera signature")
+			return
+		}
+		role, err := auth.ParseRole(q.Get("role"))
+		if err != nil {
+			writeError(w, http.StatusForbidden, "Invalid camera signature")
+			return
+		}
+		if h.clock.Now().Unix() > exp {
+			writeError(w, http.StatusForbidden, "Camera URL expired")
+			return
+		}
+
+		signed := &auth.User{Name: user, Role: role}
+		next.ServeHTTP(w, r.WithContext(auth.WithUser(r.Context(), signed)))
+	})
+}
+
+// cameraPath returns the printer a camera proxy path is for
+func cameraPath(path string) (string, bool) {
+	rest, ok := strings.CutPrefix(path, "/api/printers/")
+	if !ok {
+		return "", false
+	}
+	id, feed, ok := strings.Cut(rest, "/camera/")
+	return id, ok && (feed == "snapshot" || feed == "stream")
+}
+
+// handleCameras hands out signed snapshot and stream URLs for every camera
+// the caller can see, for the dashboard to fetch on each page load
+func (h *Handler) handleCameras(w http.ResponseWriter, r *http.Request) {
+	user := auth.UserFromContext(r.Context())
+	expires := h.clock.Now().Add(cameraURLTTL).UTC()
+
+	cameras := []models.CameraURLs{}
+	for _, p := range h.printersMatching(r, "") {
+		base := "/api/printers/" + p.ID + "/camera/"
+		cameras = append(cameras, models.CameraURLs{
+			PrinterID:   p.ID,
+			SnapshotURL: h.signedCameraURL(base+"snapshot", p.ID, user, expires),
+			StreamURL:   h.signedCameraURL(base+"stream", p.ID, user, expires),
+			ExpiresAt:   expires,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "ok",
+		"cameras": cameras,
+	})
+}
+
+// handleCameraSnapshot relays one JPEG from a printer's camera
+func (h *Handler) handleCameraSnapshot(w http.ResponseWriter, r *http.Request) {
+	h.relayCamera(w, r, false)
+}
+
+// handleCameraStream relays a printer's MJPEG stream until the viewer leaves
+func (h *Handler) handleCameraStream(w http.ResponseWriter, r *http.Request) {
+	h.relayCamera(w, r, true)
+}
+
+func (h *Handler) relayCamera(w http.ResponseWriter, r *http.Request, stream bool) {
+	printer, ok := h.findPrinter(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Printer not found")
+		return
+	}
+	snapshot, source := cameraSources(printer, h.settings.Printers[printer.ID])
+	client := &http.Client{}
+	if !stream {
+		source = snapshot
+		client.Timeout = 10 * time.Second
+	}
+
+	req, err := http.NewRequestWithContext(r.Context(), "GET", source, nil)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, "Camera unreachable: "+err.Error())
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		writeError(w, http.StatusBadGateway, "Camera returned "+resp.Status)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", resp.Header.Get("Content-Type"))
+	if stream {
+		// The stream outlives the server's write timeout
+		rc.SetWriteDeadline(time.Time{})
+		w.Header().Set("Cache-Control", "no-store")
+	} else {
+		// Long enough to absorb a burst of reloads without showing a stale print
+		w.Header().Set("Cache-Control", "private, max-age=5")
+	}
+	w.WriteHeader(http.StatusOK)
+
+	buf := make([]byte, cameraChunk)
+	for {
+		n, err := resp.Body.Read(buf)
+		if n > 0 {
+			if _, werr := w.Write(buf[:n]); werr != nil {
+				return
+			}
+			rc.Flush()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
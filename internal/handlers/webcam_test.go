@@ -0,0 +1,163 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestSignedCameraURLs(t *testing.T) {
+	t.Setenv("AUTH_USERS", "vic:viewer:vic-token")
+	sm := testutil.NewSpoolman(t)
+	first, second := testutil.NewOctoPrint(t), testutil.NewOctoPrint(t)
+	clock := &stepClock{now: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)}
+	h := newHandlerWithConfig(t, testutil.Config(sm,
+		testutil.Printer{Name: "Mini", Server: first},
+		testutil.Printer{Name: "MK4", Server: second}), WithClock(clock))
+
+	get := func(path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := get("/api/printers/printer-1/camera/snapshot", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("unsigned snapshot = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+
+	rec := get("/api/cameras", "vic-token")
+	var response struct {
+		Cameras []models.CameraURLs `json:"cameras"`
+	}
+	json.NewDecoder(rec.Body).Decode(&response)
+	if len(response.Cameras) != 2 {
+		t.Fatalf("cameras = %d: %s", rec.Code, rec.Body)
+	}
+	signed := response.Cameras[0].SnapshotURL
+
+	rec = get(signed, "")
+	if rec.Code != http.StatusOK || rec.Body.String() != string(testutil.Snapshot) || rec.Header().Get("Content-Type") != "image/jpeg" {
+		t.Errorf("signed snapshot = %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if rec := get(strings.Replace(signed, "printer-1", "printer-2", 1), ""); rec.Code != http.StatusForbidden {
+		t.Errorf("signature reused for another printer = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+	if rec := get(strings.Replace(signed, "user=vic", "user=ada", 1), ""); rec.Code != http.StatusForbidden {
+		t.Errorf("signature reused for another user = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+
+	clock.advance(cameraURLTTL + time.Second)
+	if rec := get(signed, ""); rec.Code != http.StatusForbidden {
+		t.Errorf("expired snapshot = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
+
+func TestSignedCameraURLsUnderTeams(t *testing.T) {
+	t.Setenv("TEAMS_ENABLED", "true")
+	t.Setenv("AUTH_USERS", "staff:admin:staff-token,ana:operator:ana-token")
+	h := newTestHandler(t, testutil.NewSpoolman(t),
+		testutil.Printer{Name: "Mini", Server: testutil.NewOctoPrint(t)},
+		testutil.Printer{Name: "MK4", Server: testutil.NewOctoPrint(t)})
+	if rec := doAs(h, "staff-token", "PUT", "/api/admin/teams/robotics", `{"printers": ["printer-1"], "members": ["ana"]}`); rec.Code != http.StatusOK {
+		t.Fatalf("create team = %d: %s", rec.Code, rec.Body)
+	}
+
+	snapshots := func(token string) []string {
+		var response struct {
+			Cameras []models.CameraURLs `json:"cameras"`
+		}
+		json.NewDecoder(doAs(h, token, "GET", "/api/cameras", "").Body).Decode(&response)
+		urls := []string{}
+		for _, c := range response.Cameras {
+			urls = append(urls, c.SnapshotURL)
+		}
+		return urls
+	}
+
+	// Admins belong to no team but still see every camera
+	staff := snapshots("staff-token")
+	if len(staff) != 2 {
+		t.Fatalf("admin cameras = %v, want both printers", staff)
+	}
+	for _, signed := range staff {
+		if rec := doAs(h, "", "GET", signed, ""); rec.Code != http.StatusOK {
+			t.Errorf("admin snapshot %s = %d, want %d", signed, rec.Code, http.StatusOK)
+		}
+	}
+
+	ana := snapshots("ana-token")
+	if len(ana) != 1 {
+		t.Fatalf("member cameras = %v, want only the team's printer", ana)
+	}
+	if rec := doAs(h, "", "GET", ana[0], ""); rec.Code != http.StatusOK {
+		t.Errorf("member snapshot = %d, want %d", rec.Code, http.StatusOK)
+	}
+	raised := strings.Replace(strings.Replace(ana[0], "role=operator", "role=admin", 1), "printer-1", "printer-2", 1)
+	if rec := doAs(h, "", "GET", raised, ""); rec.Code != http.StatusForbidden {
+		t.Errorf("signature reused with a raised role = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
+
+func TestCameraStreamOutlivesWriteTimeout(t *testing.T) {
+	const frames = 20
+	camera := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "multipart/x-mixed-replace;boundary=frame")
+		for i := 0; i < frames; i++ {
+			fmt.Fprintf(w, "--frame\r\nContent-Type: image/jpeg\r\n\r\n%s\r\n", testutil.Snapshot)
+			w.(http.Flusher).Flush()
+			time.Sleep(20 * time.Millisecond)
+		}
+	}))
+	defer camera.Close()
+	t.Setenv("PRINTER_1_STREAM_URL", camera.URL)
+	h := newTestHandler(t, testutil.NewSpoolman(t), testutil.Printer{Name: "Mini", Server: testutil.NewOctoPrint(t)})
+
+	// The stream runs for twice the server's write timeout
+	srv := httptest.NewUnstartedServer(h)
+	srv.Config.WriteTimeout = frames * 20 * time.Millisecond / 2
+	srv.Start()
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/api/printers/printer-1/camera/stream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("stream cut off after %d frames: %v", strings.Count(string(body), "--frame"), err)
+	}
+	if n := strings.Count(string(body), "--frame"); n != frames {
+		t.Errorf("relayed %d frames, want %d", n, frames)
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/wmarchesi123/go-3dprint-client/octoprint"
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/webhooks"
+)
+
+// eventsBucket numbers domain events and, with triggers enabled, keeps the
+// latest of them for the trigger feeds
+const eventsBucket = "domain_events"
+
+// emit publishes a domain event to every configured webhook, the event bus
+// and the trigger feeds, and announces it when configured to
+func (h *Handler) emit(eventType, printerID string, data interface{}) {
+	h.announceEvent(eventType, printerID, data)
+	h.archiveEvent(eventType, printerID, data)
+	if eventType == models.EventAlertRaised {
+		h.recordAlert(printerID, data)
+	}
+	if !h.webhooks.Enabled() && h.bus == nil && !h.triggersEnabled() {
+		return
+	}
+
+	id, err := h.store.NextID(eventsBucket)
+	if err != nil {
+		h.logger.Printf("Failed to number %s event: %v", eventType, err)
+		return
+	}
+	event := models.Event{
+		ID:        id,
+		Type:      eventType,
+		Time:      h.clock.Now().UTC().Truncate(time.Second),
+		PrinterID: printerID,
+		Data:      data,
+	}
+	if h.webhooks.Enabled() {
+		if err := h.webhooks.Publish(event); err != nil {
+			h.logger.Printf("Failed to queue %s event for webhooks: %v", eventType, err)
+		}
+	}
+	if h.bus != nil {
+		h.bus.Publish(event)
+	}
+	if h.triggersEnabled() {
+		h.recordTrigger(event)
+	}
+}
+
+// emitTransition publishes a status change, along with a job start or an
+// alert when the new status is one. Resuming a paused print is not a new job,
+// and neither is a print found running when the log starts.
+func (h *Handler) emitTransition(e models.StatusEvent) {
+	h.emit(models.EventPrinterStatus, e.PrinterID, e)
+
+	switch {
+	case e.To == "printing" && e.From != "" && e.From != "paused":
+		h.emit(models.EventJobStarted, e.PrinterID, map[string]interface{}{"file": e.File})
+	case e.To == "error":
+		h.emit(models.EventAlertRaised, e.PrinterID, map[string]interface{}{"kind": "error", "error": e.Error, "file": e.File})
+	}
+}
+
+// emitFinished publishes the end of a print and how it ended
+func (h *Handler) emitFinished(printerID, status string, job *octoprint.JobResponse) {
+	h.emit(models.EventJobFinished, printerID, map[string]interface{}{
+		"file":       job.Job.File.Display,
+		"result":     jobResult(status, job),
+		"completion": job.Progress.Completion,
+		"print_time": job.Progress.PrintTime,
+	})
+}
+
+// jobResult says how a stopped job ended: failed on a printer error,
+// completed if it reached 100%, and cancelled otherwise
+func jobResult(status string, job *octoprint.JobResponse) string {
+	switch {
+	case status == "error":
+		return models.JobFailed
+	case job.Progress.Completion >= 100:
+		return models.JobCompleted
+	}
+	return models.JobCancelled
+}
+
+// handleWebhookDeliveries returns the delivery log newest first, optionally
+// filtered by ?webhook= and ?status=
+func (h *Handler) handleWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	limit := 100
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+		limit = n
+	}
+
+	status := q.Get("status")
+	if status != "" && !slices.Contains([]string{models.DeliveryPending, models.DeliveryDelivered, models.DeliveryFailed}, status) {
+		writeError(w, http.StatusBadRequest, "Invalid status")
+		return
+	}
+
+	deliveries, err := h.webhooks.List(q.Get("webhook"), status, limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":     "ok",
+		"deliveries": deliveries,
+	})
+}
+
+// handleWebhookRetry sends a delivery again on the next outbox run
+func (h *Handler) handleWebhookRetry(w http.ResponseWriter, r *http.Request) {
+	d, err := h.webhooks.Retry(r.PathValue("id"), h.clock.Now())
+	if errors.Is(err, webhooks.ErrNotFound) {
+		writeError(w, http.StatusNotFound, "Delivery not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":   "ok",
+		"delivery": d,
+	})
+}
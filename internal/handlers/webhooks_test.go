@@ -0,0 +1,114 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+	"github.com/wmarchesi123/octodash/internal/webhooks"
+)
+
+func TestWebhooks(t *testing.T) {
+	type received struct {
+		path, event, signature string
+		body                   []byte
+	}
+	var (
+		mu       sync.Mutex
+		got      []received
+		failNext = true
+	)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		if r.URL.Path == "/all" && failNext {
+			failNext = false
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		got = append(got, received{r.URL.Path, r.Header.Get("X-OctoDash-Event"), r.Header.Get("X-OctoDash-Signature"), body})
+	}))
+	t.Cleanup(receiver.Close)
+
+	t.Setenv("WEBHOOK_1_URL", receiver.URL+"/all")
+	t.Setenv("WEBHOOK_1_SECRET", "s3cret")
+	t.Setenv("WEBHOOK_2_URL", receiver.URL+"/finished")
+	t.Setenv("WEBHOOK_2_EVENTS", "job.finished")
+	op := testutil.NewOctoPrint(t)
+	op.SetPrinting("gear.gcode", 98, 60)
+	clock := &stepClock{now: time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)}
+	h := newHandlerWithConfig(t, testutil.Config(testutil.NewSpoolman(t), testutil.Printer{Name: "Mini", Server: op}), WithClock(clock))
+
+	getStatus(t, h)
+	op.SetFinished("gear.gcode")
+	getStatus(t, h)
+
+	// The first attempt to /all fails and waits out its backoff
+	h.webhooks.Deliver(context.Background(), clock.Now())
+	clock.advance(time.Minute)
+	h.webhooks.Deliver(context.Background(), clock.Now())
+
+	mu.Lock()
+	defer mu.Unlock()
+	var summary []string
+	for _, r := range got {
+		summary = append(summary, r.path+" "+r.event)
+		if r.path == "/all" && r.signature != webhooks.Sign("s3cret", r.body) {
+			t.Errorf("signature %q does not match the body", r.signature)
+		}
+		if r.path == "/finished" && r.signature != "" {
+			t.Errorf("unsigned webhook sent signature %q", r.signature)
+		}
+	}
+	want := "/all job.finished,/finished job.finished,/all printer.status,/all printer.status"
+	if s := strings.Join(summary, ","); s != want {
+		t.Fatalf("received %s, want %s", s, want)
+	}
+
+	var finished models.Event
+	json.Unmarshal(got[1].body, &finished)
+	if data, _ := finished.Data.(map[string]interface{}); finished.PrinterID != "printer-1" || data["result"] != "completed" || data["file"] != "gear.gcode" {
+		t.Errorf("job.finished = %+v", finished)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/api/admin/webhooks/deliveries?webhook=webhook-1", nil))
+	var log struct {
+		Deliveries []models.WebhookDelivery `json:"deliveries"`
+	}
+	json.NewDecoder(rec.Body).Decode(&log)
+	if len(log.Deliveries) != 3 {
+		t.Fatalf("deliveries = %+v, want 3 for webhook-1", log.Deliveries)
+	}
+	first := log.Deliveries[2]
+	if first.Status != models.DeliveryDelivered || first.Attempts != 2 || first.EventType != models.EventPrinterStatus {
+		t.Errorf("retried delivery = %+v, want delivered on the second attempt", first)
+	}
+}
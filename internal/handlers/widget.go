@@ -0,0 +1,107 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+)
+
+// handleWidget serves a flat farm summary for homelab dashboards (Homepage,
+// Dashy, Homarr) whose custom API widgets read top-level fields only
+func (h *Handler) handleWidget(w http.ResponseWriter, r *http.Request) {
+	printers := h.collectStatuses(h.printersMatching(r, r.URL.Query().Get("tags")))
+
+	summary := map[string]interface{}{
+		"total":     len(printers),
+		"printing":  0,
+		"idle":      0,
+		"completed": 0,
+		"cooldown":  0,
+		"error":     0,
+		"offline":   0,
+	}
+
+	// Report the job finishing soonest so a single tile can show "next done"
+	nextLeft := 0
+	nextName := ""
+	for _, p := range printers {
+		if n, ok := summary[p.Status].(int); ok {
+			summary[p.Status] = n + 1
+		}
+		if p.Progress != nil && p.Progress.PrintTimeLeft > 0 && (nextLeft == 0 || p.Progress.PrintTimeLeft < nextLeft) {
+			nextLeft = p.Progress.PrintTimeLeft
+			nextName = p.Name
+		}
+	}
+
+	summary["active"] = fmt.Sprintf("%d/%d", summary["printing"], len(printers))
+	summary["next_done_printer"] = nextName
+	summary["next_done_in"] = models.FormatDuration(nextLeft)
+	summary["next_done_seconds"] = nextLeft
+
+	writeJSON(w, http.StatusOK, summary)
+}
+
+// handlePrinterWidget serves the same flat shape for a single printer
+func (h *Handler) handlePrinterWidget(w http.ResponseWriter, r *http.Request) {
+	printer, ok := h.findPrinter(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Printer not found")
+		return
+	}
+
+	p := h.fetchPrinterStatus(printer)
+	units := h.settings.Units
+
+	flat := map[string]interface{}{
+		"name":      p.Name,
+		"status":    p.Status,
+		"state":     p.State,
+		"file":      "",
+		"progress":  0.0,
+		"time_left": models.FormatDuration(0),
+		"elapsed":   models.FormatDuration(0),
+		"hotend":    "",
+		"bed":       "",
+		"spool":     "",
+	}
+
+	if p.Progress != nil {
+		flat["file"] = p.Progress.FileName
+		flat["progress"] = math.Round(p.Progress.Completion*10) / 10
+		flat["time_left"] = models.FormatDuration(p.Progress.PrintTimeLeft)
+		flat["elapsed"] = models.FormatDuration(p.Progress.PrintTime)
+	}
+	if p.Temperatures != nil {
+		flat["hotend"] = models.FormatTemperature(p.Temperatures.HotendActual, units.Temperature)
+		flat["bed"] = models.FormatTemperature(p.Temperatures.BedActual, units.Temperature)
+	}
+	if p.CurrentSpool != nil {
+		flat["spool"] = fmt.Sprintf("%v %v", p.CurrentSpool["name"], p.CurrentSpool["material"])
+		if remaining, ok := p.CurrentSpool["remaining"].(float64); ok {
+			flat["spool_remaining"] = models.FormatWeight(remaining, units.Weight)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, flat)
+}
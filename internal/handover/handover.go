@@ -0,0 +1,123 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package handover renders shift handover reports as HTML, Markdown or PDF.
+// Every format lays out the same tables, built once by Tables.
+package handover
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+)
+
+// Table is one section of a report
+type Table struct {
+	Title   string
+	Empty   string // shown instead of rows when there are none
+	Header  []string
+	Weights []float64 // relative column widths for fixed-width layouts
+	Rows    [][]string
+}
+
+// Title names a report by the period it covers
+func Title(r models.HandoverReport, loc *time.Location) string {
+	return fmt.Sprintf("Shift handover: %s to %s",
+		r.Since.In(loc).Format("Mon 2 Jan 15:04"), r.GeneratedAt.In(loc).Format("Mon 2 Jan 15:04"))
+}
+
+// Tables lays out a report's sections, with times shown in loc and weights in
+// the report's units
+func Tables(r models.HandoverReport, loc *time.Location) []Table {
+	name := func(id string) string {
+		if n, ok := r.Printers[id]; ok {
+			return n
+		}
+		return id
+	}
+	clock := func(t time.Time) string {
+		return t.In(loc).Format("Mon 15:04")
+	}
+
+	running := Table{
+		Title:   "Running jobs",
+		Empty:   "Nothing is printing.",
+		Header:  []string{"Printer", "Status", "File", "Progress", "ETA"},
+		Weights: []float64{2, 1.2, 4, 1, 1.4},
+	}
+	for _, j := range r.Running {
+		eta := "-"
+		if j.ETA != nil {
+			eta = clock(*j.ETA)
+		}
+		running.Rows = append(running.Rows, []string{name(j.PrinterID), j.Status, j.File, fmt.Sprintf("%.0f%%", j.Completion), eta})
+	}
+
+	finished := func(title, empty string, jobs []models.JobRecord) Table {
+		t := Table{
+			Title:   title,
+			Empty:   empty,
+			Header:  []string{"Printer", "File", "Finished", "Print time"},
+			Weights: []float64{2, 4.4, 1.4, 1.4},
+		}
+		for _, j := range jobs {
+			t.Rows = append(t.Rows, []string{name(j.PrinterID), j.File, clock(j.FinishedAt), duration(j.PrintTime)})
+		}
+		return t
+	}
+
+	spools := Table{
+		Title:   "Low spools",
+		Empty:   "No spools are running low.",
+		Header:  []string{"Spool", "Material", "Remaining"},
+		Weights: []float64{4, 2, 1.6},
+	}
+	for _, s := range r.LowSpools {
+		spool := fmt.Sprintf("#%d %s", s.ID, strings.TrimSpace(s.Vendor+" "+s.Name))
+		spools.Rows = append(spools.Rows, []string{spool, s.Material, models.FormatWeight(s.RemainingWeight, r.Units.Weight)})
+	}
+
+	alerts := Table{
+		Title:   "Alerts",
+		Empty:   "No alerts were raised.",
+		Header:  []string{"Time", "Printer", "Alert"},
+		Weights: []float64{1.4, 2, 5.4},
+	}
+	for _, a := range r.Alerts {
+		alerts.Rows = append(alerts.Rows, []string{clock(a.Time), name(a.PrinterID), a.Message})
+	}
+
+	return []Table{
+		running,
+		finished("Completed", "No jobs completed.", r.Completed),
+		finished("Failed", "No jobs failed.", r.Failed),
+		spools,
+		alerts,
+	}
+}
+
+// duration formats a print time in seconds as hours and minutes
+func duration(seconds int) string {
+	d := time.Duration(seconds) * time.Second
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%dh %02dm", int(d.Hours()), int(d.Minutes())%60)
+}
@@ -0,0 +1,163 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handover
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/pdf"
+)
+
+// Markdown writes the report as GitHub-flavoured Markdown tables
+func Markdown(w io.Writer, r models.HandoverReport, loc *time.Location) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", Title(r, loc))
+	for _, t := range Tables(r, loc) {
+		fmt.Fprintf(&b, "\n## %s\n\n", t.Title)
+		if len(t.Rows) == 0 {
+			fmt.Fprintf(&b, "%s\n", t.Empty)
+			continue
+		}
+		fmt.Fprintf(&b, "| %s |\n", strings.Join(t.Header, " | "))
+		fmt.Fprintf(&b, "|%s\n", strings.Repeat(" --- |", len(t.Header)))
+		for _, row := range t.Rows {
+			cells := make([]string, len(row))
+			for i, c := range row {
+				cells[i] = markdownCell(c)
+			}
+			fmt.Fprintf(&b, "| %s |\n", strings.Join(cells, " | "))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownCell keeps a value from breaking out of its table cell
+var markdownCell = strings.NewReplacer("|", `\|`, "\n", " ").Replace
+
+var htmlReport = template.Must(template.New("handover").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 1em; }
+th, td { text-align: left; padding: 4px 8px; border-bottom: 1px solid #ddd; }
+th { background: #f4f4f4; }
+.empty { color: #777; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{range .Tables}}
+<h2>{{.Title}}</h2>
+{{if .Rows}}<table>
+<tr>{{range .Header}}<th>{{.}}</th>{{end}}</tr>
+{{range .Rows}}<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{end}}</table>{{else}}<p class="empty">{{.Empty}}</p>{{end}}
+{{end}}
+</body>
+</html>
+`))
+
+// HTML writes the report as a standalone page, ready to print
+func HTML(w io.Writer, r models.HandoverReport, loc *time.Location) error {
+	return htmlReport.Execute(w, struct {
+		Title  string
+		Tables []Table
+	}{Title(r, loc), Tables(r, loc)})
+}
+
+// PDF page layout, in points
+const (
+	margin    = 40.0
+	titleSize = 16.0
+	headSize  = 12.0
+	textSize  = 9.0
+	rowHeight = 13.0
+)
+
+// PDF writes the report as an A4 document
+func PDF(w io.Writer, r models.HandoverReport, loc *time.Location) error {
+	doc := pdf.New(pdf.A4Width, pdf.A4Height)
+	doc.AddPage()
+	width := pdf.A4Width - 2*margin
+	y := pdf.A4Height - margin - titleSize
+
+	// room makes sure the next h points fit, starting a new page if not
+	room := func(h float64) {
+		if y-h < margin {
+			doc.AddPage()
+			y = pdf.A4Height - margin - textSize
+		}
+	}
+
+	doc.Text(margin, y, titleSize, pdf.Bold, Title(r, loc))
+	y -= titleSize + 8
+	for _, t := range Tables(r, loc) {
+		room(headSize + 2*rowHeight)
+		y -= headSize
+		doc.Text(margin, y, headSize, pdf.Bold, t.Title)
+		y -= rowHeight
+
+		if len(t.Rows) == 0 {
+			doc.Text(margin, y, textSize, pdf.Regular, t.Empty)
+			y -= rowHeight
+			continue
+		}
+
+		cols := columns(t.Weights, width)
+		row := func(cells []string, font string) {
+			room(rowHeight)
+			x := margin
+			for i, c := range cells {
+				doc.Text(x, y, textSize, font, pdf.Fit(c, cols[i]-6, textSize))
+				x += cols[i]
+			}
+			y -= rowHeight
+		}
+		row(t.Header, pdf.Bold)
+		doc.Line(margin, y+rowHeight-3, margin+width, y+rowHeight-3, 0.5)
+		for _, cells := range t.Rows {
+			row(cells, pdf.Regular)
+		}
+	}
+
+	_, err := doc.WriteTo(w)
+	return err
+}
+
+// columns splits width between columns in proportion to their weights
+func columns(weights []float64, width float64) []float64 {
+	total := 0.0
+	for _, w := range weights {
+		total += w
+	}
+	cols := make([]float64, len(weights))
+	for i, w := range weights {
+		cols[i] = width * w / total
+	}
+	return cols
+}
@@ -0,0 +1,192 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package jobs keeps the farm's history of finished prints, including those
+// imported from OctoPrint's own history from before OctoDash was watching
+package jobs
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/octoapi"
+	"github.com/wmarchesi123/octodash/internal/store"
+)
+
+// Bucket holds the job history
+const Bucket = "jobs"
+
+// importsBucket remembers each printer's last import
+const importsBucket = "job_imports"
+
+// History is the persistent record of finished jobs
+type History struct {
+	store *store.Store
+}
+
+// New creates a History backed by the given store
+func New(s *store.Store) *History {
+	return &History{store: s}
+}
+
+// Record saves a finished job. ref tells apart jobs on one printer finishing
+// in the same second, and recording the same job and ref again is a no-op, so
+// it reports whether the job was new.
+func (h *History) Record(rec models.JobRecord, ref string) (bool, error) {
+	rec.FinishedAt = rec.FinishedAt.UTC().Truncate(time.Second)
+	rec.ID = Key(rec, ref)
+
+	var existing models.JobRecord
+	if ok, err := h.store.Get(Bucket, rec.ID, &existing); err != nil || ok {
+		return false, err
+	}
+	return true, h.store.Put(Bucket, rec.ID, rec)
+}
+
+// Key is the ID Record gives a job, ordering jobs by printer and then finish time
+func Key(rec models.JobRecord, ref string) string {
+	sum := fnv.New32a()
+	sum.Write([]byte(rec.Source + "\x00" + ref))
+	finished := rec.FinishedAt.UTC().Truncate(time.Second)
+	return fmt.Sprintf("%s/%s/%08x", rec.PrinterID, finished.Format("20060102T150405Z"), sum.Sum32())
+}
+
+// Get returns the job with the given ID, reporting whether it exists
+func (h *History) Get(id string) (models.JobRecord, bool, error) {
+	var rec models.JobRecord
+	ok, err := h.store.Get(Bucket, id, &rec)
+	return rec, ok, err
+}
+
+// List returns jobs newest first, optionally for one printer and only those
+// finished after since, up to limit (0 for all)
+func (h *History) List(printerID string, since time.Time, limit int) ([]models.JobRecord, error) {
+	all, err := store.List[models.JobRecord](h.store, Bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(all, func(i, j int) bool { return all[i].FinishedAt.After(all[j].FinishedAt) })
+
+	jobs := []models.JobRecord{}
+	for _, j := range all {
+		if !since.IsZero() && !j.FinishedAt.After(since) {
+			break
+		}
+		if limit > 0 && len(jobs) >= limit {
+			break
+		}
+		if printerID == "" || j.PrinterID == printerID {
+			jobs = append(jobs, j)
+		}
+	}
+	return jobs, nil
+}
+
+// Totals sums jobs for the whole farm and for each printer, ordered by printer ID
+func Totals(jobs []models.JobRecord) (models.JobStats, []models.JobStats) {
+	var farm models.JobStats
+	byPrinter := make(map[string]*models.JobStats)
+	for _, j := range jobs {
+		p := byPrinter[j.PrinterID]
+		if p == nil {
+			p = &models.JobStats{PrinterID: j.PrinterID}
+			byPrinter[j.PrinterID] = p
+		}
+		add(&farm, j)
+		add(p, j)
+	}
+
+	printers := make([]models.JobStats, 0, len(byPrinter))
+	for _, p := range byPrinter {
+		printers = append(printers, *p)
+	}
+	sort.Slice(printers, func(i, j int) bool { return printers[i].PrinterID < printers[j].PrinterID })
+	return farm, printers
+}
+
+func add(s *models.JobStats, j models.JobRecord) {
+	s.Jobs++
+	switch j.Result {
+	case models.JobCompleted:
+		s.Completed++
+	case models.JobCancelled:
+		s.Cancelled++
+	default:
+		s.Failed++
+	}
+	s.PrintTime += j.PrintTime
+	s.Filament += j.Filament
+	s.EnergyKWh += j.EnergyKWh
+	s.SuccessRate = float64(s.Completed) / float64(s.Jobs)
+}
+
+// Import records a printer's past jobs as reported by OctoPrint, skipping ones
+// already recorded or without a finish time, and remembers the import
+func (h *History) Import(printerID, source string, past []octoapi.HistoricJob, at time.Time) (models.JobImport, error) {
+	result := models.JobImport{PrinterID: printerID, Time: at.UTC().Truncate(time.Second), Source: source}
+	for _, p := range past {
+		if p.Finished.IsZero() {
+			result.Skipped++
+			continue
+		}
+
+		rec := models.JobRecord{
+			PrinterID:  printerID,
+			File:       p.File,
+			Result:     p.Result,
+			FinishedAt: p.Finished,
+			PrintTime:  p.PrintTime,
+			Filament:   p.Filament / 1000,
+			Source:     source,
+		}
+		if !p.Started.IsZero() {
+			started := p.Started.UTC().Truncate(time.Second)
+			rec.StartedAt = &started
+		}
+
+		added, err := h.Record(rec, p.ID)
+		if err != nil {
+			return result, err
+		}
+		if added {
+			result.Imported++
+		} else {
+			result.Skipped++
+		}
+	}
+	return result, h.store.Put(importsBucket, printerID, result)
+}
+
+// Imports returns the last import for each printer that has had one
+func (h *History) Imports() ([]models.JobImport, error) {
+	imports, err := store.List[models.JobImport](h.store, importsBucket)
+	if imports == nil {
+		imports = []models.JobImport{}
+	}
+	return imports, err
+}
+
+// Imported reports whether a printer's history has been imported
+func (h *History) Imported(printerID string) (bool, error) {
+	var last models.JobImport
+	return h.store.Get(importsBucket, printerID, &last)
+}
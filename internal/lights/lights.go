@@ -0,0 +1,271 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lights drives status lights, such as a stack light or an LED strip,
+// from the aggregate state of the farm. Each light reflects a set of printers
+// and shows the look mapped to the first of its configured states that any of
+// them is in, so an idle farm can glow green and pulse red once something
+// needs a person.
+package lights
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/settings"
+)
+
+const (
+	// retryInterval is how often lights that failed to update are tried again
+	retryInterval = time.Minute
+	// requestTimeout bounds each request to a WLED device
+	requestTimeout = 5 * time.Second
+)
+
+// WLED effect IDs for each light effect
+var wledEffects = map[string]int{
+	models.EffectSolid: 0,
+	models.EffectBlink: 1,
+	models.EffectPulse: 2, // Breathe
+}
+
+// Gcode sends commands to a printer
+type Gcode func(printerID string, commands ...string) error
+
+// off is the look of a light none of whose states hold
+var off = settings.LightMapping{Effect: models.EffectOff}
+
+// light is one configured light and what it should be showing
+type light struct {
+	cfg       settings.LightSettings
+	want      settings.LightMapping
+	shown     *settings.LightMapping
+	err       string
+	updatedAt *time.Time
+}
+
+// Controller keeps every light in step with the printers it reflects
+type Controller struct {
+	lights []*light
+	gcode  Gcode
+	client *http.Client
+	logger *log.Logger
+	now    func() time.Time
+	wake   chan struct{}
+
+	mu     sync.Mutex
+	states map[string]string // farm state of each printer seen, by ID
+}
+
+// New creates a Controller for the configured lights; call Run to start updating them
+func New(cfg []settings.LightSettings, gcode Gcode, now func() time.Time, logger *log.Logger) *Controller {
+	c := &Controller{
+		gcode:  gcode,
+		client: &http.Client{Timeout: requestTimeout},
+		logger: logger,
+		now:    now,
+		wake:   make(chan struct{}, 1),
+		states: make(map[string]string),
+	}
+	for _, l := range cfg {
+		c.lights = append(c.lights, &light{cfg: l, want: off})
+	}
+	return c
+}
+
+// State is the farm state a printer contributes to the lights
+func State(p *models.PrinterStatus) string {
+	switch {
+	case p.Status == "error":
+		return models.LightError
+	case p.Status == "paused" || p.Status == "completed" || p.Interrupted != nil:
+		return models.LightAttention
+	case p.Status == "printing":
+		return models.LightPrinting
+	case p.Status == "offline":
+		return models.LightOffline
+	default:
+		return models.LightIdle
+	}
+}
+
+// Update records the latest statuses and wakes Run for any light whose look
+// changes. Printers not included keep their last known state, so polling a
+// subset of the farm doesn't change lights reflecting the rest.
+func (c *Controller) Update(printers []*models.PrinterStatus) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, p := range printers {
+		c.states[p.ID] = State(p)
+	}
+
+	changed := false
+	for _, l := range c.lights {
+		l.want = c.look(l.cfg)
+		if l.shown == nil || *l.shown != l.want {
+			changed = true
+		}
+	}
+	if changed {
+		select {
+		case c.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// look picks the mapping for the first state held by the light's printers
+func (c *Controller) look(cfg settings.LightSettings) settings.LightMapping {
+	held := make(map[string]bool)
+	for id, state := range c.states {
+		if len(cfg.Printers) == 0 || slices.Contains(cfg.Printers, id) {
+			held[state] = true
+		}
+	}
+	for _, m := range cfg.Mapping {
+		if held[m.State] {
+			return m
+		}
+	}
+	return off
+}
+
+// Run updates lights as the farm changes, retrying failures, until ctx is cancelled
+func (c *Controller) Run(ctx context.Context) {
+	ticker := time.NewTicker(retryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.wake:
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+		c.apply(ctx)
+	}
+}
+
+// apply sends every light whose look has changed or failed to apply
+func (c *Controller) apply(ctx context.Context) {
+	for _, l := range c.lights {
+		c.mu.Lock()
+		want, pending := l.want, l.shown == nil || *l.shown != l.want
+		c.mu.Unlock()
+		if !pending {
+			continue
+		}
+
+		err := c.send(ctx, l.cfg, want)
+		now := c.now().UTC()
+
+		c.mu.Lock()
+		l.updatedAt = &now
+		if err != nil {
+			l.err = err.Error()
+			c.logger.Printf("Failed to set light %s to %s: %v", l.cfg.ID, want.State, err)
+		} else {
+			l.err = ""
+			l.shown = &want
+		}
+		c.mu.Unlock()
+	}
+}
+
+// send shows look on a light
+func (c *Controller) send(ctx context.Context, cfg settings.LightSettings, look settings.LightMapping) error {
+	if cfg.Printer != "" {
+		// Marlin's M150 sets a NeoPixel strip on the board; it can't animate
+		cmd := "M150 R0 U0 B0"
+		if look.Effect != models.EffectOff {
+			cmd = fmt.Sprintf("M150 R%d U%d B%d P%d", look.Color[0], look.Color[1], look.Color[2], cfg.Brightness)
+		}
+		return c.gcode(cfg.Printer, cmd)
+	}
+
+	state := map[string]interface{}{"on": false}
+	if look.Effect != models.EffectOff {
+		state = map[string]interface{}{
+			"on":  true,
+			"bri": cfg.Brightness,
+			"seg": []map[string]interface{}{{
+				"col": [][3]uint8{look.Color},
+				"fx":  wledEffects[look.Effect],
+			}},
+		}
+	}
+	body, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.WLED+"/json/state", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("WLED returned %s", resp.Status)
+	}
+	return nil
+}
+
+// List reports what every light should be showing and whether it is
+func (c *Controller) List() []models.LightStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	list := make([]models.LightStatus, 0, len(c.lights))
+	for _, l := range c.lights {
+		status := models.LightStatus{
+			ID:        l.cfg.ID,
+			Device:    l.cfg.WLED,
+			Printers:  l.cfg.Printers,
+			State:     l.want.State,
+			Effect:    l.want.Effect,
+			Applied:   l.shown != nil && *l.shown == l.want,
+			Error:     l.err,
+			UpdatedAt: l.updatedAt,
+		}
+		if l.cfg.Printer != "" {
+			status.Device = l.cfg.Printer + " (M150)"
+		}
+		if l.want.Effect != models.EffectOff {
+			status.Color = l.want.Hex()
+		}
+		list = append(list, status)
+	}
+	return list
+}
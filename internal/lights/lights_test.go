@@ -0,0 +1,139 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lights
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/settings"
+)
+
+func TestController(t *testing.T) {
+	var mu sync.Mutex
+	var wled []map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/json/state" {
+			http.NotFound(w, r)
+			return
+		}
+		var state map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&state)
+		mu.Lock()
+		wled = append(wled, state)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	var gcode []string
+	send := func(printerID string, commands ...string) error {
+		gcode = append(gcode, printerID+": "+commands[0])
+		return nil
+	}
+
+	mapping := []settings.LightMapping{
+		{State: models.LightError, Color: [3]uint8{0xff, 0, 0}, Effect: models.EffectPulse},
+		{State: models.LightPrinting, Color: [3]uint8{0, 0, 0xff}, Effect: models.EffectSolid},
+		{State: models.LightIdle, Color: [3]uint8{0, 0xff, 0}, Effect: models.EffectSolid},
+		{State: models.LightOffline, Effect: models.EffectOff},
+	}
+	c := New([]settings.LightSettings{
+		{ID: "light-1", WLED: srv.URL, Brightness: 128, Mapping: mapping},
+		{ID: "light-2", Printer: "printer-2", Printers: []string{"printer-2"}, Brightness: 255, Mapping: mapping},
+	}, send, time.Now, log.New(io.Discard, "", 0))
+
+	// An idle farm is green on both lights
+	c.Update([]*models.PrinterStatus{{ID: "printer-1", Status: "idle"}, {ID: "printer-2", Status: "idle"}})
+	c.apply(context.Background())
+	if len(wled) != 1 || wled[0]["bri"] != 128.0 {
+		t.Fatalf("WLED updates = %v, want one at brightness 128", wled)
+	}
+	seg := wled[0]["seg"].([]interface{})[0].(map[string]interface{})
+	if col := seg["col"].([]interface{})[0].([]interface{}); col[1] != 255.0 || seg["fx"] != 0.0 {
+		t.Errorf("idle segment = %v, want solid green", seg)
+	}
+	if len(gcode) != 1 || gcode[0] != "printer-2: M150 R0 U255 B0 P255" {
+		t.Errorf("gcode = %v, want a green M150 to printer-2", gcode)
+	}
+
+	// Nothing is resent while the look holds
+	c.Update([]*models.PrinterStatus{{ID: "printer-1", Status: "idle"}})
+	c.apply(context.Background())
+	if len(wled) != 1 || len(gcode) != 1 {
+		t.Errorf("unchanged farm sent %d WLED updates and %v", len(wled), gcode)
+	}
+
+	// An error on printer-1 pulses red on the farm light but not on printer-2's own strip
+	c.Update([]*models.PrinterStatus{{ID: "printer-1", Status: "error"}})
+	c.apply(context.Background())
+	seg = wled[1]["seg"].([]interface{})[0].(map[string]interface{})
+	if col := seg["col"].([]interface{})[0].([]interface{}); col[0] != 255.0 || seg["fx"] != float64(wledEffects[models.EffectPulse]) {
+		t.Errorf("error segment = %v, want pulsing red", seg)
+	}
+	if len(gcode) != 1 {
+		t.Errorf("printer-2 strip changed for another printer's error: %v", gcode)
+	}
+
+	// Lights whose printers are all offline turn off
+	c.Update([]*models.PrinterStatus{{ID: "printer-1", Status: "offline"}, {ID: "printer-2", Status: "offline"}})
+	c.apply(context.Background())
+	if wled[2]["on"] != false || gcode[1] != "printer-2: M150 R0 U0 B0" {
+		t.Errorf("offline updates = %v, %v; want both lights off", wled[2], gcode)
+	}
+
+	list := c.List()
+	if len(list) != 2 || list[0].State != models.LightOffline || !list[0].Applied || list[1].Device != "printer-2 (M150)" {
+		t.Errorf("List = %+v", list)
+	}
+}
+
+func TestControllerRetriesFailures(t *testing.T) {
+	fail := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	c := New([]settings.LightSettings{{
+		ID: "light-1", WLED: srv.URL, Brightness: 255,
+		Mapping: []settings.LightMapping{{State: models.LightIdle, Color: [3]uint8{0, 0xff, 0}}},
+	}}, nil, time.Now, log.New(io.Discard, "", 0))
+
+	c.Update([]*models.PrinterStatus{{ID: "printer-1", Status: "idle"}})
+	c.apply(context.Background())
+	if l := c.List()[0]; l.Applied || l.Error == "" {
+		t.Fatalf("after a failed update: %+v, want an error and not applied", l)
+	}
+
+	fail = false
+	c.apply(context.Background())
+	if l := c.List()[0]; !l.Applied || l.Error != "" || l.Color != "#00ff00" {
+		t.Errorf("after retrying: %+v, want green applied", l)
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package manifest reads and writes the farm's print queue, profiles and
+// printer definitions as a YAML document, so they can be kept in version control
+package manifest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Version is the document format written by Marshal and accepted by Parse
+const Version = 1
+
+// Sections that a document can hold
+const (
+	SectionPrinters = "printers"
+	SectionProfiles = "profiles"
+	SectionQueue    = "queue"
+)
+
+// Sections lists every section in the order they are written
+var Sections = []string{SectionPrinters, SectionProfiles, SectionQueue}
+
+// Document is the farm's configuration. Sections left out of a document are
+// left alone on import; an empty list is a section with nothing in it.
+type Document struct {
+	Version  int        `yaml:"version"`
+	Printers *[]Printer `yaml:"printers,omitempty"`
+	Profiles *[]Profile `yaml:"profiles,omitempty"`
+	Queue    *[]Job     `yaml:"queue,omitempty"`
+}
+
+// Printer describes a configured printer. Printers are set up through
+// PRINTER_N_* variables, so these are exported for reference and compared,
+// not applied, on import. API keys are never included.
+type Printer struct {
+	ID         string            `yaml:"id"`
+	Name       string            `yaml:"name"`
+	URL        string            `yaml:"url"`
+	Group      string            `yaml:"group,omitempty"`
+	Tags       map[string]string `yaml:"tags,omitempty"`
+	AutoClear  bool              `yaml:"auto_clear,omitempty"`
+	Continuous bool              `yaml:"continuous,omitempty"`
+}
+
+// Profile is a print profile from the library
+type Profile struct {
+	Name     string            `yaml:"name"`
+	Material string            `yaml:"material,omitempty"`
+	Nozzle   float64           `yaml:"nozzle,omitempty"`
+	Quality  string            `yaml:"quality,omitempty"`
+	Slicer   string            `yaml:"slicer,omitempty"`
+	Tags     map[string]string `yaml:"tags,omitempty"`
+}
+
+// Job is a queue entry waiting for a printer, in queue order. ID ties it to
+// an existing entry; jobs without one are added as new entries.
+type Job struct {
+	ID           string     `yaml:"id,omitempty"`
+	File         string     `yaml:"file"`
+	Printer      string     `yaml:"printer,omitempty"`
+	PrintProfile string     `yaml:"print_profile,omitempty"`
+	Team         string     `yaml:"team,omitempty"`
+	Priority     string     `yaml:"priority,omitempty"`
+	NotBefore    *time.Time `yaml:"not_before,omitempty"`
+	OffPeak      bool       `yaml:"off_peak,omitempty"`
+	SubmittedBy  string     `yaml:"submitted_by,omitempty"`
+}
+
+// Marshal writes a document as YAML
+func Marshal(doc Document) ([]byte, error) {
+	doc.Version = Version
+
+	var buf bytes.Buffer
+	buf.WriteString("# OctoDash farm configuration, applied with POST /api/admin/import\n")
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Parse reads a YAML document, rejecting unknown fields so typos don't pass
+// silently
+func Parse(r io.Reader) (Document, error) {
+	var doc Document
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	if err := dec.Decode(&doc); err != nil {
+		if errors.Is(err, io.EOF) {
+			return doc, errors.New("document is empty")
+		}
+		return doc, err
+	}
+	if doc.Version != Version {
+		return doc, fmt.Errorf("unsupported document version %d, want %d", doc.Version, Version)
+	}
+	return doc, nil
+}
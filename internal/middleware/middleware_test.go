@@ -0,0 +1,114 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRecoverReturns500AndLogsStack(t *testing.T) {
+	var logs bytes.Buffer
+	h := Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}), RequestIDs, Recover(log.New(&logs, "", 0)))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/api/status", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), `"status":"error"`) {
+		t.Errorf("body = %q, want JSON error envelope", rec.Body)
+	}
+	id := rec.Header().Get(RequestIDHeader)
+	if id == "" || !strings.Contains(logs.String(), "["+id+"]") {
+		t.Errorf("log %q does not mention request ID %q", logs.String(), id)
+	}
+	if !strings.Contains(logs.String(), "goroutine") {
+		t.Error("log has no stack trace")
+	}
+}
+
+func TestRequestIDReusesIncomingHeader(t *testing.T) {
+	var seen string
+	h := RequestIDs(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = RequestID(r.Context())
+	}))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(RequestIDHeader, "abc123")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if seen != "abc123" || rec.Header().Get(RequestIDHeader) != "abc123" {
+		t.Errorf("request ID = %q / %q, want abc123", seen, rec.Header().Get(RequestIDHeader))
+	}
+}
+
+func TestGzipCompressesJSONOnly(t *testing.T) {
+	body := strings.Repeat(`{"status":"ok"}`, 100)
+
+	tests := []struct {
+		contentType string
+		wantGzip    bool
+	}{
+		{"application/json", true},
+		{"text/html; charset=utf-8", true},
+		{"image/png", false},
+	}
+
+	for _, tt := range tests {
+		h := Gzip(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", tt.contentType)
+			io.WriteString(w, body)
+		}))
+
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Accept-Encoding", "gzip, deflate")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		gotGzip := rec.Header().Get("Content-Encoding") == "gzip"
+		if gotGzip != tt.wantGzip {
+			t.Errorf("%s: gzip = %v, want %v", tt.contentType, gotGzip, tt.wantGzip)
+			continue
+		}
+
+		got := rec.Body.String()
+		if gotGzip {
+			zr, err := gzip.NewReader(rec.Body)
+			if err != nil {
+				t.Fatalf("%s: %v", tt.contentType, err)
+			}
+			b, _ := io.ReadAll(zr)
+			got = string(b)
+		}
+		if got != body {
+			t.Errorf("%s: body mismatch after decoding", tt.contentType)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"runtime/debug"
+)
+
+// Recover turns a panicking handler into a 500 and logs the stack
+func Recover(logger *log.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rec := &statusRecorder{ResponseWriter: w}
+
+			defer func() {
+				err := recover()
+				if err == nil {
+					return
+				}
+				// Let the server abort the connection as it would without us
+				if err == http.ErrAbortHandler {
+					panic(err)
+				}
+
+				logger.Printf("panic [%s] %s %s: %v\n%s", RequestID(r.Context()), r.Method, r.URL.Path, err, debug.Stack())
+
+				if rec.status == 0 {
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(http.StatusInternalServerError)
+					w.Write([]byte(`{"status":"error","error":"Internal server error"}` + "\n"))
+				}
+			}()
+
+			next.ServeHTTP(rec, r)
+		})
+	}
+}
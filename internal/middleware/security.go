@@ -0,0 +1,76 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Security configures SecurityHeaders
+type Security struct {
+	Policy         string   // Content-Security-Policy without frame-ancestors; empty sends none
+	FramePaths     []string // path prefixes other sites may frame
+	FrameAncestors []string // origins allowed to frame FramePaths
+	ReferrerPolicy string
+	HSTSMaxAge     time.Duration // sent over HTTPS when non-zero
+}
+
+// SecurityHeaders sets the Content-Security-Policy, framing, referrer and HSTS
+// headers
+func SecurityHeaders(s Security) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := w.Header()
+
+			framed := false
+			for _, prefix := range s.FramePaths {
+				if strings.HasPrefix(r.URL.Path, prefix) {
+					framed = true
+					break
+				}
+			}
+			ancestors := "'none'"
+			if framed {
+				ancestors = strings.Join(s.FrameAncestors, " ")
+			} else {
+				header.Set("X-Frame-Options", "DENY")
+			}
+
+			if s.Policy != "" {
+				policy := s.Policy
+				if ancestors != "" {
+					policy = strings.TrimRight(policy, "; ") + "; frame-ancestors " + ancestors
+				}
+				header.Set("Content-Security-Policy", policy)
+			}
+			header.Set("X-Content-Type-Options", "nosniff")
+			if s.ReferrerPolicy != "" {
+				header.Set("Referrer-Policy", s.ReferrerPolicy)
+			}
+			if s.HSTSMaxAge > 0 && (r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https") {
+				header.Set("Strict-Transport-Security", "max-age="+strconv.Itoa(int(s.HSTSMaxAge.Seconds())))
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
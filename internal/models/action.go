@@ -0,0 +1,64 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import "time"
+
+// ActionResult reports the outcome of a control action on a single printer
+type ActionResult struct {
+	PrinterID string `json:"printer_id"`
+	Name      string `json:"name"`
+	Success   bool   `json:"success"`
+	Skipped   string `json:"skipped,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Macro is a configured gcode macro offered on a printer
+type Macro struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Role    string `json:"role"`
+	Confirm bool   `json:"confirm"`
+}
+
+// Command states, in the order a command moves through them. A sent command
+// with an expected printer status is confirmed once a poll shows it, or
+// unconfirmed if none does in time; other commands end at sent.
+const (
+	CommandAccepted    = "accepted"
+	CommandSending     = "sending"
+	CommandSent        = "sent"
+	CommandConfirmed   = "confirmed"
+	CommandUnconfirmed = "unconfirmed"
+	CommandFailed      = "failed"
+)
+
+// Command is a control request run through a printer's command queue
+type Command struct {
+	ID          string     `json:"id"`
+	PrinterID   string     `json:"printer_id"`
+	Action      string     `json:"action"`
+	Status      string     `json:"status"`
+	Expects     string     `json:"expects,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	RequestedBy string     `json:"requested_by"`
+	AcceptedAt  time.Time  `json:"accepted_at"`
+	SentAt      *time.Time `json:"sent_at,omitempty"`
+	ConfirmedAt *time.Time `json:"confirmed_at,omitempty"`
+}
@@ -0,0 +1,40 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import "time"
+
+// Announcement levels; displays play a more urgent sound for alerts
+const (
+	AnnounceInfo  = "info"
+	AnnounceAlert = "alert"
+)
+
+// AnnounceManual is the type of announcements made through the API rather than by an event
+const AnnounceManual = "manual"
+
+// Announcement is a short message read out on kiosk displays and speakers
+type Announcement struct {
+	ID        string    `json:"id"`
+	Time      time.Time `json:"time"`
+	Type      string    `json:"type"`
+	PrinterID string    `json:"printer_id,omitempty"`
+	Level     string    `json:"level"`
+	Text      string    `json:"text"`
+}
@@ -0,0 +1,47 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import "time"
+
+// AuditEntry records who did something that changed how the farm runs
+type AuditEntry struct {
+	ID        string    `json:"id"`
+	Time      time.Time `json:"time"`
+	User      string    `json:"user"`
+	Action    string    `json:"action"`
+	PrinterID string    `json:"printer_id,omitempty"`
+	Detail    string    `json:"detail,omitempty"`
+}
+
+// ChecklistItem is one pre-print check and who ticked it off
+type ChecklistItem struct {
+	Name    string     `json:"name"`
+	Checked bool       `json:"checked"`
+	By      string     `json:"by,omitempty"`
+	At      *time.Time `json:"at,omitempty"`
+}
+
+// Checklist is a printer's pre-print checklist. Queued jobs wait for it to be
+// complete, and starting one clears it for the next.
+type Checklist struct {
+	PrinterID string          `json:"printer_id"`
+	Items     []ChecklistItem `json:"items"`
+	Complete  bool            `json:"complete"`
+}
@@ -0,0 +1,44 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import "time"
+
+// Banner severities, least urgent first
+const (
+	BannerInfo     = "info"
+	BannerWarning  = "warning"
+	BannerCritical = "critical"
+)
+
+// Banner is a farm-wide notice shown across the top of every dashboard until
+// it expires or an admin takes it down
+type Banner struct {
+	ID        string     `json:"id"`
+	Message   string     `json:"message"`
+	Severity  string     `json:"severity"`
+	CreatedBy string     `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// Active reports whether the banner is still showing at the given time
+func (b Banner) Active(at time.Time) bool {
+	return b.ExpiresAt == nil || at.Before(*b.ExpiresAt)
+}
@@ -0,0 +1,78 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import "time"
+
+// Kinds of camera media, which are also their folders in the archive bucket
+const (
+	MediaSnapshot  = "snapshots"
+	MediaClip      = "clips"
+	MediaTimelapse = "timelapses"
+)
+
+// MediaKinds lists the kinds of camera media archived
+var MediaKinds = []string{MediaSnapshot, MediaClip, MediaTimelapse}
+
+// Reasons a clip was saved
+const (
+	ClipFailed      = "failed"
+	ClipCancelled   = "cancelled"
+	ClipError       = "error"
+	ClipInterrupted = "interrupted"
+	ClipManual      = "manual"
+)
+
+// CameraArchiveStatus reports what has been archived from one printer's camera
+type CameraArchiveStatus struct {
+	PrinterID     string     `json:"printer_id"`
+	Name          string     `json:"name"`
+	Capturing     bool       `json:"capturing"`
+	Frames        int        `json:"frames"`
+	LastSnapshot  *time.Time `json:"last_snapshot,omitempty"`
+	LastClip      *time.Time `json:"last_clip,omitempty"`
+	LastTimelapse *time.Time `json:"last_timelapse,omitempty"`
+	Uploaded      int64      `json:"uploaded_bytes"`
+	LastError     string     `json:"last_error,omitempty"`
+}
+
+// CameraClip describes a saved clip; it is stored as clip.json beside the frames
+type CameraClip struct {
+	PrinterID string      `json:"printer_id"`
+	Name      string      `json:"name"`
+	Reason    string      `json:"reason"`
+	File      string      `json:"file,omitempty"`
+	SavedAt   time.Time   `json:"saved_at"`
+	Frames    []ClipFrame `json:"frames"`
+}
+
+// ClipFrame is one image in a clip
+type ClipFrame struct {
+	Key  string    `json:"key"`
+	Time time.Time `json:"time"`
+}
+
+// CameraURLs are signed links to a printer's camera that work without an
+// Authorization header until ExpiresAt
+type CameraURLs struct {
+	PrinterID   string    `json:"printer_id"`
+	SnapshotURL string    `json:"snapshot_url"`
+	StreamURL   string    `json:"stream_url"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
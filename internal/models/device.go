@@ -0,0 +1,38 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import "time"
+
+// Device is a kiosk or wall display paired with OctoDash. It signs in as
+// User with a read-only token handed over when an admin approved it.
+type Device struct {
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`
+	User     string    `json:"user"`
+	PairedBy string    `json:"paired_by"`
+	PairedAt time.Time `json:"paired_at"`
+}
+
+// PairingRequest is a device waiting for an admin to approve the code it shows
+type PairingRequest struct {
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,48 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+// Diagnostic check outcomes
+const (
+	CheckOK       = "ok"
+	CheckMissing  = "missing"
+	CheckDisabled = "disabled"
+	CheckError    = "error"
+	CheckUnknown  = "unknown"
+)
+
+// DiagnosticCheck is one health check run against an OctoPrint instance
+type DiagnosticCheck struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Plugin   string `json:"plugin,omitempty"`
+	Version  string `json:"version,omitempty"`
+	Required bool   `json:"required"`
+	Status   string `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Fix      string `json:"fix,omitempty"`
+}
+
+// PrinterDiagnostics is the result of every health check for one printer
+type PrinterDiagnostics struct {
+	PrinterID string            `json:"printer_id"`
+	Name      string            `json:"name"`
+	Healthy   bool              `json:"healthy"`
+	Checks    []DiagnosticCheck `json:"checks"`
+}
@@ -0,0 +1,34 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import "time"
+
+// DryingSession records a spool spending time in a filament dryer.
+// EndedAt is nil while the session is still running.
+type DryingSession struct {
+	ID              string     `json:"id"`
+	Spoolman        string     `json:"spoolman"`
+	SpoolID         string     `json:"spool_id"`
+	Temperature     float64    `json:"temperature"`
+	StartedAt       time.Time  `json:"started_at"`
+	EndedAt         *time.Time `json:"ended_at,omitempty"`
+	DurationMinutes int        `json:"duration_minutes,omitempty"`
+	RecordedBy      string     `json:"recorded_by,omitempty"`
+}
@@ -0,0 +1,40 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+// DryRun reports what a request made with ?dry_run=1 would have done, and
+// whether the caller's role allows it
+type DryRun struct {
+	Action       string         `json:"action"`
+	User         string         `json:"user"`
+	Role         string         `json:"role"`
+	RequiredRole string         `json:"required_role,omitempty"`
+	Allowed      bool           `json:"allowed"`
+	Targets      []DryRunTarget `json:"targets"`
+}
+
+// DryRunTarget is one printer or record a dry run would have acted on, with
+// the OctoPrint requests it would have sent
+type DryRunTarget struct {
+	PrinterID string   `json:"printer_id,omitempty"`
+	Name      string   `json:"name,omitempty"`
+	Upstream  []string `json:"upstream,omitempty"`
+	Change    string   `json:"change,omitempty"`
+	Skipped   string   `json:"skipped,omitempty"`
+}
@@ -0,0 +1,71 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import "time"
+
+// StatusEvent records a printer moving from one dashboard status to another
+type StatusEvent struct {
+	ID        string    `json:"id"`
+	PrinterID string    `json:"printer_id"`
+	Time      time.Time `json:"time"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	State     string    `json:"state,omitempty"`
+	File      string    `json:"file,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// Domain event types published to webhooks
+const (
+	EventPrinterStatus   = "printer.status"
+	EventJobStarted      = "job.started"
+	EventJobFinished     = "job.finished"
+	EventAlertRaised     = "alert.raised"
+	EventQueueDispatched = "queue.dispatched"
+)
+
+// Alert severities, set on alert.raised events from the watchdogs
+const (
+	SeverityWarning = "warning"
+	SeverityHigh    = "high"
+)
+
+// EventTypes lists every domain event type
+var EventTypes = []string{EventPrinterStatus, EventJobStarted, EventJobFinished, EventAlertRaised, EventQueueDispatched}
+
+// Event is something that happened in OctoDash, published to outside systems
+type Event struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	Time      time.Time   `json:"time"`
+	PrinterID string      `json:"printer_id,omitempty"`
+	Data      interface{} `json:"data"`
+}
+
+// Alert is a raised alert kept for shift handover reports, phrased the way
+// it was announced
+type Alert struct {
+	ID        string    `json:"id"`
+	Time      time.Time `json:"time"`
+	PrinterID string    `json:"printer_id"`
+	Kind      string    `json:"kind"`
+	Severity  string    `json:"severity,omitempty"`
+	Message   string    `json:"message"`
+}
@@ -0,0 +1,35 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import "time"
+
+// FloorPlan places printers on a picture of the room they stand in
+type FloorPlan struct {
+	Positions map[string]FloorPosition `json:"positions"`
+	ImageType string                   `json:"image_type,omitempty"`
+	UpdatedAt time.Time                `json:"updated_at"`
+}
+
+// FloorPosition is where a printer sits on the floor plan, as fractions of
+// the image width and height measured from the top left corner
+type FloorPosition struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
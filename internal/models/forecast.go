@@ -0,0 +1,57 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import "time"
+
+// Forecast projects when spools and materials run out at their recent rate of use.
+// Weights are grams; DailyUse is grams per day.
+type Forecast struct {
+	GeneratedAt time.Time          `json:"generated_at"`
+	Materials   []MaterialForecast `json:"materials"`
+	Spools      []SpoolForecast    `json:"spools"`
+	Reorder     []MaterialForecast `json:"reorder"`
+}
+
+// MaterialForecast totals every spool of one material. OrderSpools suggests
+// how many spools restock it for another forecast window.
+type MaterialForecast struct {
+	Material        string     `json:"material"`
+	Spools          int        `json:"spools"`
+	RemainingWeight float64    `json:"remaining_weight"`
+	DailyUse        float64    `json:"daily_use"`
+	DaysLeft        *float64   `json:"days_left,omitempty"`
+	RunsOutAt       *time.Time `json:"runs_out_at,omitempty"`
+	Reorder         bool       `json:"reorder"`
+	OrderSpools     int        `json:"order_spools,omitempty"`
+}
+
+// SpoolForecast projects a single spool from its own use
+type SpoolForecast struct {
+	ID              int        `json:"id"`
+	Spoolman        string     `json:"spoolman,omitempty"`
+	Name            string     `json:"name"`
+	Material        string     `json:"material"`
+	Vendor          string     `json:"vendor"`
+	Color           string     `json:"color"`
+	RemainingWeight float64    `json:"remaining_weight"`
+	DailyUse        float64    `json:"daily_use"`
+	DaysLeft        *float64   `json:"days_left,omitempty"`
+	RunsOutAt       *time.Time `json:"runs_out_at,omitempty"`
+}
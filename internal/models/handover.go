@@ -0,0 +1,45 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import "time"
+
+// HandoverReport sums up a shift for the operator taking over: what is
+// printing, what finished or failed since Since, spools running low and the
+// alerts raised
+type HandoverReport struct {
+	GeneratedAt time.Time         `json:"generated_at"`
+	Since       time.Time         `json:"since"`
+	Printers    map[string]string `json:"printers"` // printer ID -> name
+	Running     []HandoverJob     `json:"running"`
+	Completed   []JobRecord       `json:"completed"`
+	Failed      []JobRecord       `json:"failed"`
+	LowSpools   []SpoolForecast   `json:"low_spools"`
+	Alerts      []Alert           `json:"alerts"`
+	Units       Units             `json:"units"`
+}
+
+// HandoverJob is a print still on a printer at handover
+type HandoverJob struct {
+	PrinterID  string     `json:"printer_id"`
+	Status     string     `json:"status"`
+	File       string     `json:"file"`
+	Completion float64    `json:"completion"`
+	ETA        *time.Time `json:"eta,omitempty"`
+}
@@ -0,0 +1,43 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+// HomeAssistantDevice is a printer as a Home Assistant device
+type HomeAssistantDevice struct {
+	ID               string `json:"id"`
+	Identifier       string `json:"identifier"`
+	Name             string `json:"name"`
+	Manufacturer     string `json:"manufacturer"`
+	Model            string `json:"model"`
+	ConfigurationURL string `json:"configuration_url,omitempty"`
+}
+
+// HomeAssistantEntity describes a sensor whose state /api/homeassistant/states
+// reports under its object ID. Device is empty for farm-wide entities.
+type HomeAssistantEntity struct {
+	UniqueID    string `json:"unique_id"`
+	ObjectID    string `json:"object_id"`
+	Name        string `json:"name"`
+	Platform    string `json:"platform"`
+	Device      string `json:"device,omitempty"`
+	DeviceClass string `json:"device_class,omitempty"`
+	Unit        string `json:"unit_of_measurement,omitempty"`
+	StateClass  string `json:"state_class,omitempty"`
+	Icon        string `json:"icon,omitempty"`
+}
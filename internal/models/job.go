@@ -0,0 +1,69 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import "time"
+
+// How a finished job ended, as reported in job.finished events
+const (
+	JobCompleted = "completed"
+	JobFailed    = "failed"
+	JobCancelled = "cancelled"
+)
+
+// JobSourceDashboard marks jobs OctoDash saw finish itself; imported ones are
+// marked with the OctoPrint history source they came from
+const JobSourceDashboard = "octodash"
+
+// JobRecord is one finished print in the farm's job history
+type JobRecord struct {
+	ID         string     `json:"id"`
+	PrinterID  string     `json:"printer_id"`
+	File       string     `json:"file"`
+	Result     string     `json:"result"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt time.Time  `json:"finished_at"`
+	PrintTime  int        `json:"print_time"`
+	Filament   float64    `json:"filament_m,omitempty"`
+	EnergyKWh  float64    `json:"energy_kwh,omitempty"`
+	Source     string     `json:"source"`
+}
+
+// JobStats totals finished jobs for one printer, or the farm when PrinterID is empty
+type JobStats struct {
+	PrinterID   string  `json:"printer_id,omitempty"`
+	Jobs        int     `json:"jobs"`
+	Completed   int     `json:"completed"`
+	Failed      int     `json:"failed"`
+	Cancelled   int     `json:"cancelled"`
+	PrintTime   int     `json:"print_time"`
+	Filament    float64 `json:"filament_m"`
+	EnergyKWh   float64 `json:"energy_kwh"`
+	SuccessRate float64 `json:"success_rate"`
+}
+
+// JobImport records pulling a printer's past jobs from OctoPrint
+type JobImport struct {
+	PrinterID string    `json:"printer_id"`
+	Time      time.Time `json:"time"`
+	Source    string    `json:"source,omitempty"`
+	Imported  int       `json:"imported"`
+	Skipped   int       `json:"skipped"`
+	Error     string    `json:"error,omitempty"`
+}
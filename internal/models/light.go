@@ -0,0 +1,54 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import "time"
+
+// Farm states a status light can show, highest priority first
+const (
+	LightError     = "error"
+	LightAttention = "attention"
+	LightPrinting  = "printing"
+	LightIdle      = "idle"
+	LightOffline   = "offline"
+)
+
+// LightStates lists every farm state a light can map to a color
+var LightStates = []string{LightError, LightAttention, LightPrinting, LightIdle, LightOffline}
+
+// Light effects; devices that can't animate show pulse and blink as solid
+const (
+	EffectSolid = "solid"
+	EffectPulse = "pulse"
+	EffectBlink = "blink"
+	EffectOff   = "off"
+)
+
+// LightStatus is what a status light is showing, or failing to show
+type LightStatus struct {
+	ID        string     `json:"id"`
+	Device    string     `json:"device"`
+	Printers  []string   `json:"printers,omitempty"`
+	State     string     `json:"state,omitempty"`
+	Color     string     `json:"color,omitempty"`
+	Effect    string     `json:"effect,omitempty"`
+	Applied   bool       `json:"applied"`
+	Error     string     `json:"error,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
@@ -0,0 +1,35 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+// Plate object states
+const (
+	ObjectPending   = "pending"
+	ObjectPrinting  = "printing"
+	ObjectDone      = "done"
+	ObjectCancelled = "cancelled"
+)
+
+// PlateObject is one labelled object in the job a printer is running
+type PlateObject struct {
+	ID         int     `json:"id"`
+	Name       string  `json:"name"`
+	Completion float64 `json:"completion"`
+	State      string  `json:"state"`
+}
@@ -0,0 +1,40 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import "time"
+
+// OctoPrintBackup is an OctoPrint backup archive stored by OctoDash
+type OctoPrintBackup struct {
+	PrinterID string    `json:"printer_id"`
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	StoredAt  time.Time `json:"stored_at"`
+}
+
+// OctoPrintBackupStatus summarizes backup orchestration for one printer
+type OctoPrintBackupStatus struct {
+	PrinterID   string            `json:"printer_id"`
+	Name        string            `json:"name"`
+	Running     bool              `json:"running"`
+	Pending     bool              `json:"pending"`
+	LastSuccess *time.Time        `json:"last_success,omitempty"`
+	LastError   string            `json:"last_error,omitempty"`
+	Backups     []OctoPrintBackup `json:"backups"`
+}
@@ -18,30 +18,177 @@
 
 package models
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // PrinterStatus represents the dashboard view of a printer
 type PrinterStatus struct {
 	ID           string                 `json:"id"`
 	Name         string                 `json:"name"`
-	OctoPrintURL string                 `json:"octoprint_url"`
+	OctoPrintURL string                 `json:"octoprint_url,omitempty"`
+	Tags         map[string]string      `json:"tags,omitempty"`
 	Status       string                 `json:"status"`
 	State        string                 `json:"state"`
 	Progress     *ProgressInfo          `json:"progress,omitempty"`
 	Temperatures *TemperatureInfo       `json:"temperatures,omitempty"`
+	Power        *PowerInfo             `json:"power,omitempty"`
 	CurrentSpool map[string]interface{} `json:"current_spool,omitempty"`
 	ThumbnailURL string                 `json:"thumbnail_url,omitempty"`
+	Updates      *UpdateBadge           `json:"updates,omitempty"`
+	Completed    *CompletedJob          `json:"completed,omitempty"`
+	Reservation  *Reservation           `json:"reservation,omitempty"`
+	Interrupted  *InterruptedPrint      `json:"interrupted,omitempty"`
+	Checklist    *Checklist             `json:"checklist,omitempty"`
+	Stall        *StallInfo             `json:"stall,omitempty"`
+	Extensions   map[string]interface{} `json:"extensions,omitempty"`
 	Error        string                 `json:"error,omitempty"`
+	Diagnosis    *ErrorDiagnosis        `json:"diagnosis,omitempty"`
+	LastSeen     *time.Time             `json:"last_seen,omitempty"`
+	DataAge      *int                   `json:"data_age_seconds,omitempty"`
+}
+
+// StatusSections lists the optional PrinterStatus sections clients can request
+var StatusSections = []string{"octoprint_url", "tags", "progress", "temperatures", "power", "current_spool", "thumbnail_url", "updates", "completed", "reservation", "interrupted", "checklist", "stall", "extensions"}
+
+// Trimmed returns a copy of the status keeping only the core fields and the requested sections
+func (p *PrinterStatus) Trimmed(sections map[string]bool) *PrinterStatus {
+	trimmed := &PrinterStatus{
+		ID:        p.ID,
+		Name:      p.Name,
+		Status:    p.Status,
+		State:     p.State,
+		Error:     p.Error,
+		Diagnosis: p.Diagnosis,
+		LastSeen:  p.LastSeen,
+		DataAge:   p.DataAge,
+	}
+
+	if sections["octoprint_url"] {
+		trimmed.OctoPrintURL = p.OctoPrintURL
+	}
+	if sections["tags"] {
+		trimmed.Tags = p.Tags
+	}
+	if sections["progress"] {
+		trimmed.Progress = p.Progress
+	}
+	if sections["temperatures"] {
+		trimmed.Temperatures = p.Temperatures
+	}
+	if sections["power"] {
+		trimmed.Power = p.Power
+	}
+	if sections["current_spool"] {
+		trimmed.CurrentSpool = p.CurrentSpool
+	}
+	if sections["thumbnail_url"] {
+		trimmed.ThumbnailURL = p.ThumbnailURL
+	}
+	if sections["updates"] {
+		trimmed.Updates = p.Updates
+	}
+	if sections["completed"] {
+		trimmed.Completed = p.Completed
+	}
+	if sections["reservation"] {
+		trimmed.Reservation = p.Reservation
+	}
+	if sections["interrupted"] {
+		trimmed.Interrupted = p.Interrupted
+	}
+	if sections["checklist"] {
+		trimmed.Checklist = p.Checklist
+	}
+	if sections["stall"] {
+		trimmed.Stall = p.Stall
+	}
+	if sections["extensions"] {
+		trimmed.Extensions = p.Extensions
+	}
+
+	return trimmed
+}
+
+// StatusResponse is the /api/status payload. Its wire format is pinned by
+// golden files in testdata, since users template against the field names.
+// A struct encodes without the map allocation and key sorting a
+// map[string]interface{} costs on every poll.
+type StatusResponse struct {
+	Status   string           `json:"status"`
+	Server   *ServerInfo      `json:"server,omitempty"`
+	PolledAt time.Time        `json:"polled_at"`
+	Sequence uint64           `json:"sequence"`
+	Units    *Units           `json:"units,omitempty"`
+	Timezone string           `json:"timezone,omitempty"`
+	Printers []*PrinterStatus `json:"printers"`
+	Screen   *ScreenInfo      `json:"screen,omitempty"`
+	Banners  []Banner         `json:"banners,omitempty"`
+}
+
+// ServerInfo describes the OctoDash instance that produced a response
+type ServerInfo struct {
+	Version   string    `json:"version"`
+	StartedAt time.Time `json:"started_at"`
 }
 
 // ProgressInfo represents print progress for the dashboard
 type ProgressInfo struct {
-	Completion     float64 `json:"completion"`
-	PrintTime      int     `json:"print_time"`
-	PrintTimeLeft  int     `json:"print_time_left"`
-	EstimatedTotal int     `json:"estimated_total"`
-	FileName       string  `json:"file_name"`
-	FilamentLength float64 `json:"filament_length"`
+	Completion     float64    `json:"completion"`
+	PrintTime      int        `json:"print_time"`
+	PrintTimeLeft  int        `json:"print_time_left"`
+	EstimatedTotal int        `json:"estimated_total"`
+	FileName       string     `json:"file_name"`
+	FilamentLength float64    `json:"filament_length"`
+	Filepos        int64      `json:"filepos,omitempty"`
+	ETA            *time.Time `json:"eta,omitempty"`
+}
+
+// CompletedJob is a finished print whose part is still on the bed
+type CompletedJob struct {
+	PrinterID  string    `json:"printer_id"`
+	File       string    `json:"file"`
+	PrintTime  int       `json:"print_time"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// PrintCheckpoint is the last known position of a running print, kept so a
+// job cut short by a power loss can be resumed by hand
+type PrintCheckpoint struct {
+	PrinterID  string    `json:"printer_id"`
+	File       string    `json:"file"`
+	Path       string    `json:"path"`
+	Completion float64   `json:"completion"`
+	Filepos    int64     `json:"filepos"`
+	PrintTime  int       `json:"print_time"`
+	Layer      int       `json:"layer,omitempty"`
+	Layers     int       `json:"layers,omitempty"`
+	Height     float64   `json:"height,omitempty"`
+	At         time.Time `json:"at"`
+}
+
+// InterruptedPrint is a job that vanished mid-print, most likely because the
+// printer or OctoPrint lost power
+type InterruptedPrint struct {
+	PrintCheckpoint
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+// StallInfo flags a print whose file position hasn't moved since Since while
+// the printer still reports printing, likely a frozen OctoPrint or a lost
+// serial connection
+type StallInfo struct {
+	Since   time.Time `json:"since"`
+	Filepos int64     `json:"filepos"`
+}
+
+// ErrorDiagnosis explains a printer's error state in plain words and
+// suggests what to do about it
+type ErrorDiagnosis struct {
+	Code    string `json:"code"`
+	Summary string `json:"summary"`
+	Action  string `json:"action"`
 }
 
 // TemperatureInfo represents temperature data for the dashboard
@@ -52,6 +199,31 @@ type TemperatureInfo struct {
 	HotendTarget float64 `json:"hotend_target"`
 }
 
+// PowerInfo represents smart plug energy data for the dashboard
+type PowerInfo struct {
+	Watts        float64 `json:"watts"`
+	TotalKWh     float64 `json:"total_kwh"`
+	JobEnergyKWh float64 `json:"job_energy_kwh,omitempty"`
+	JobCost      float64 `json:"job_cost,omitempty"`
+	Currency     string  `json:"currency,omitempty"`
+	Error        string  `json:"error,omitempty"`
+}
+
+// ScreenInfo tells always-on displays whether to show the idle screen
+type ScreenInfo struct {
+	Mode      string     `json:"mode"`
+	Idle      bool       `json:"idle"`
+	IdleSince *time.Time `json:"idle_since,omitempty"`
+}
+
+// FormatTimestamp formats a UTC timestamp for display in the given location
+func FormatTimestamp(t time.Time, loc *time.Location) string {
+	if t.IsZero() {
+		return "--"
+	}
+	return t.In(loc).Format("2006-01-02 15:04 MST")
+}
+
 // FormatDuration formats seconds into a human-readable duration
 func FormatDuration(seconds int) string {
 	if seconds <= 0 {
@@ -0,0 +1,35 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import "time"
+
+// PrintProfile is a named set of print settings queue entries can refer to.
+// Tags use the printer tag syntax and must all be present on a printer for
+// the profile to run there; Slicer names the slicer .ini used for models.
+type PrintProfile struct {
+	Name      string            `json:"name"`
+	Material  string            `json:"material,omitempty"`
+	Nozzle    float64           `json:"nozzle,omitempty"`
+	Quality   string            `json:"quality,omitempty"`
+	Slicer    string            `json:"slicer,omitempty"`
+	Tags      map[string]string `json:"tags,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
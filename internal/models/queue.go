@@ -0,0 +1,90 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import "time"
+
+// Queue entry states
+const (
+	QueueQueued       = "queued"
+	QueueDispatched   = "dispatched"
+	QueueNeedsSlicing = "needs_slicing"
+	QueueSlicing      = "slicing"
+	QueueSliceFailed  = "slice_failed"
+)
+
+// Approval states for entries submitted by users who need an operator's sign-off
+const (
+	ApprovalPending  = "pending"
+	ApprovalApproved = "approved"
+)
+
+// Priorities lists the queue's priority classes, most urgent first. Entries
+// without one are normal.
+var Priorities = []string{"urgent", "high", "normal", "low"}
+
+// PriorityRank orders priority classes, lowest first; unknown ones count as normal
+func PriorityRank(priority string) int {
+	for i, p := range Priorities {
+		if p == priority {
+			return i
+		}
+	}
+	return 2
+}
+
+// JobEstimate is the slicer's or OctoPrint's expectation for a job
+type JobEstimate struct {
+	PrintTime int     `json:"print_time,omitempty"`
+	Filament  float64 `json:"filament_m,omitempty"`
+	Weight    float64 `json:"filament_g,omitempty"`
+}
+
+// QueueEntry is a print job waiting for a printer. Entries submitted as STL or
+// 3MF models keep the upload in Model and wait in QueueNeedsSlicing; once
+// sliced with Profile, the gcode is held in Gcode until a printer takes the job.
+// PrintProfile names a profile from the library that limits which printers fit.
+// Entries with Approval pending are never dispatched. The queue runs in
+// Priority order, then by Order, which starts as submission order and changes
+// when entries are reordered by hand. NotBefore and OffPeak hold an entry back
+// until a start time or the configured off-peak hours.
+type QueueEntry struct {
+	ID           string       `json:"id"`
+	File         string       `json:"file"`
+	PrintProfile string       `json:"print_profile,omitempty"`
+	Model        string       `json:"model,omitempty"`
+	PreviewURL   string       `json:"preview_url,omitempty"`
+	Profile      string       `json:"profile,omitempty"`
+	Gcode        string       `json:"gcode,omitempty"`
+	Error        string       `json:"error,omitempty"`
+	PrinterID    string       `json:"printer_id,omitempty"`
+	Team         string       `json:"team,omitempty"`
+	Priority     string       `json:"priority,omitempty"`
+	Order        int64        `json:"order"`
+	NotBefore    *time.Time   `json:"not_before,omitempty"`
+	OffPeak      bool         `json:"off_peak,omitempty"`
+	Approval     string       `json:"approval,omitempty"`
+	ApprovedBy   string       `json:"approved_by,omitempty"`
+	Estimate     *JobEstimate `json:"estimate,omitempty"`
+	Status       string       `json:"status"`
+	SubmittedBy  string       `json:"submitted_by"`
+	CreatedAt    time.Time    `json:"created_at"`
+	DispatchedTo string       `json:"dispatched_to,omitempty"`
+	DispatchedAt *time.Time   `json:"dispatched_at,omitempty"`
+}
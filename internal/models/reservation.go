@@ -0,0 +1,38 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import "time"
+
+// Reservation books a printer for one user over a time slot. Only that user's
+// queued jobs are dispatched to the printer while it runs.
+type Reservation struct {
+	ID        string    `json:"id"`
+	PrinterID string    `json:"printer_id"`
+	User      string    `json:"user"`
+	Note      string    `json:"note,omitempty"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Covers reports whether the reservation is running at the given time
+func (r Reservation) Covers(at time.Time) bool {
+	return !at.Before(r.Start) && at.Before(r.End)
+}
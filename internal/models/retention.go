@@ -0,0 +1,50 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import "time"
+
+// Stored datasets that retention policies apply to
+const (
+	// DataHistory is the per-printer status transition log
+	DataHistory = "history"
+	// DataEvents is the domain event feed behind webhooks and triggers
+	DataEvents     = "events"
+	DataAudit      = "audit"
+	DataDeliveries = "deliveries"
+	DataUsage      = "usage"
+	DataJobs       = "jobs"
+)
+
+// RetentionDatasets lists every dataset the janitor prunes
+var RetentionDatasets = []string{DataHistory, DataEvents, DataAudit, DataDeliveries, DataUsage, DataJobs}
+
+// RetentionStatus reports a dataset's size, its policy and what the janitor has pruned from it
+type RetentionStatus struct {
+	Dataset       string     `json:"dataset"`
+	MaxAge        string     `json:"max_age,omitempty"`
+	MaxBytes      int64      `json:"max_bytes,omitempty"`
+	Records       int        `json:"records"`
+	Bytes         int64      `json:"bytes"`
+	PrunedRecords int64      `json:"pruned_records"`
+	PrunedBytes   int64      `json:"pruned_bytes"`
+	LastPruned    int        `json:"last_pruned"`
+	LastRun       *time.Time `json:"last_run,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+}
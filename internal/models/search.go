@@ -0,0 +1,59 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import "time"
+
+// SearchResults groups matches from every searchable source
+type SearchResults struct {
+	Files   []FileMatch              `json:"files"`
+	Jobs    []JobMatch               `json:"jobs"`
+	History []HistoryMatch           `json:"history"`
+	Spools  []map[string]interface{} `json:"spools"`
+	Errors  []string                 `json:"errors,omitempty"`
+}
+
+// FileMatch is a file on a printer whose name matched the query
+type FileMatch struct {
+	PrinterID   string `json:"printer_id"`
+	PrinterName string `json:"printer_name"`
+	Name        string `json:"name"`
+	Path        string `json:"path"`
+	Origin      string `json:"origin"`
+	Size        int64  `json:"size"`
+	Date        int64  `json:"date"`
+}
+
+// JobMatch is a running job whose file name matched the query
+type JobMatch struct {
+	PrinterID   string  `json:"printer_id"`
+	PrinterName string  `json:"printer_name"`
+	FileName    string  `json:"file_name"`
+	Completion  float64 `json:"completion"`
+}
+
+// HistoryMatch is a finished job in the job history whose file name matched the query
+type HistoryMatch struct {
+	ID          string    `json:"id"`
+	PrinterID   string    `json:"printer_id"`
+	PrinterName string    `json:"printer_name"`
+	FileName    string    `json:"file_name"`
+	Result      string    `json:"result"`
+	FinishedAt  time.Time `json:"finished_at"`
+}
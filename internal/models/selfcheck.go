@@ -0,0 +1,103 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import "time"
+
+// ServerDiagnostics is the self-diagnostics report behind /diagnostics
+type ServerDiagnostics struct {
+	Server        ServerInfo      `json:"server"`
+	UptimeSeconds int             `json:"uptime_seconds"`
+	Runtime       RuntimeStats    `json:"runtime"`
+	Poller        PollerStats     `json:"poller"`
+	Upstreams     []UpstreamCheck `json:"upstreams"`
+	Printers      []PrinterHealth `json:"printers"`
+	Database      DatabaseHealth  `json:"database"`
+	Config        ConfigSummary   `json:"config"`
+}
+
+// RuntimeStats are Go runtime figures useful for spotting leaks
+type RuntimeStats struct {
+	GoVersion     string  `json:"go_version"`
+	Goroutines    int     `json:"goroutines"`
+	HeapAlloc     uint64  `json:"heap_alloc_bytes"`
+	HeapObjects   uint64  `json:"heap_objects"`
+	Sys           uint64  `json:"sys_bytes"`
+	NumGC         uint32  `json:"num_gc"`
+	LastGCPauseMS float64 `json:"last_gc_pause_ms"`
+}
+
+// PollerStats describes how long collecting every printer's status takes
+type PollerStats struct {
+	Polls          uint64     `json:"polls"`
+	LastPoll       *time.Time `json:"last_poll,omitempty"`
+	LastDurationMS float64    `json:"last_duration_ms"`
+	AvgDurationMS  float64    `json:"avg_duration_ms"`
+	MaxDurationMS  float64    `json:"max_duration_ms"`
+}
+
+// UpstreamCheck is the result of contacting an OctoPrint or Spoolman server
+type UpstreamCheck struct {
+	Name      string  `json:"name"`
+	Kind      string  `json:"kind"`
+	URL       string  `json:"url"`
+	Reachable bool    `json:"reachable"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// PrinterHealth is the poller's recent history with one printer
+type PrinterHealth struct {
+	PrinterID   string     `json:"printer_id"`
+	Name        string     `json:"name"`
+	LastSeen    *time.Time `json:"last_seen,omitempty"`
+	LastError   string     `json:"last_error,omitempty"`
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+	LastFetchMS float64    `json:"last_fetch_ms"`
+}
+
+// DatabaseHealth is the size of the store and the outcome of its latest maintenance
+type DatabaseHealth struct {
+	SizeBytes   int64           `json:"size_bytes"`
+	FreeBytes   int64           `json:"free_bytes"`
+	LastCheck   *MaintenanceRun `json:"last_check,omitempty"`
+	LastCompact *MaintenanceRun `json:"last_compact,omitempty"`
+	Error       string          `json:"error,omitempty"`
+}
+
+// MaintenanceRun is one integrity check or compaction of the store. Problems
+// lists inconsistencies an integrity check found.
+type MaintenanceRun struct {
+	Time        time.Time `json:"time"`
+	DurationMS  float64   `json:"duration_ms"`
+	OK          bool      `json:"ok"`
+	Problems    []string  `json:"problems,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	BytesBefore int64     `json:"bytes_before,omitempty"`
+	BytesAfter  int64     `json:"bytes_after,omitempty"`
+}
+
+// ConfigSummary is the running configuration with secrets redacted
+type ConfigSummary struct {
+	Printers    int      `json:"printers"`
+	DataDir     string   `json:"data_dir"`
+	Demo        bool     `json:"demo"`
+	AuthEnabled bool     `json:"auth_enabled"`
+	Environment []string `json:"environment"`
+}
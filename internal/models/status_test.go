@@ -0,0 +1,144 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files with the current wire format")
+
+// goldenTime stands in for every timestamp in the fixtures
+var goldenTime = time.Date(2025, 3, 14, 15, 9, 26, 0, time.UTC)
+
+// fill sets every field reachable from v to a non-zero value derived from its
+// JSON name, so a golden file made from it shows every key the API can send
+func fill(t *testing.T, v reflect.Value, name string) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		v.Set(reflect.New(v.Type().Elem()))
+		fill(t, v.Elem(), name)
+	case reflect.Struct:
+		if v.Type() == reflect.TypeOf(time.Time{}) {
+			v.Set(reflect.ValueOf(goldenTime))
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			tag := strings.Split(field.Tag.Get("json"), ",")[0]
+			if !field.IsExported() || tag == "-" {
+				continue
+			}
+			if tag == "" {
+				tag = field.Name
+			}
+			fill(t, v.Field(i), tag)
+		}
+	case reflect.String:
+		v.SetString(name)
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(int64(len(name)))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(uint64(len(name)))
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(float64(len(name)) + 0.5)
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		fill(t, v.Index(0), name)
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			t.Fatalf("%s: don't know how to fill a %s", name, v.Type())
+		}
+		v.Set(reflect.MakeMap(v.Type()))
+		elem := reflect.New(v.Type().Elem()).Elem()
+		fill(t, elem, name+"_value")
+		v.SetMapIndex(reflect.ValueOf(name+"_key").Convert(v.Type().Key()), elem)
+	case reflect.Interface:
+		v.Set(reflect.ValueOf(name))
+	default:
+		t.Fatalf("%s: don't know how to fill a %s", name, v.Type())
+	}
+}
+
+// checkGolden compares v's encoding with testdata/name, and that decoding the
+// golden file and encoding it again gives the same bytes
+func checkGolden(t *testing.T, name string, v *StatusResponse) {
+	t.Helper()
+	got, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = append(got, '\n')
+
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test -update to create it)", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s changed; API clients template against these names. If the change is intended, run go test -update and review the diff.\ngot:\n%s", path, got)
+	}
+
+	var decoded StatusResponse
+	if err := json.Unmarshal(want, &decoded); err != nil {
+		t.Fatalf("decoding %s: %v", path, err)
+	}
+	again, _ := json.MarshalIndent(&decoded, "", "  ")
+	if !bytes.Equal(append(again, '\n'), want) {
+		t.Errorf("%s does not survive a round trip:\n%s", path, again)
+	}
+}
+
+func TestStatusWireFormat(t *testing.T) {
+	var full StatusResponse
+	fill(t, reflect.ValueOf(&full).Elem(), "")
+	checkGolden(t, "status_full.json", &full)
+
+	// Keys clients can rely on even when everything optional is missing
+	minimal := StatusResponse{Printers: []*PrinterStatus{{}}}
+	checkGolden(t, "status_minimal.json", &minimal)
+}
+
+func TestStatusSectionsMatchFields(t *testing.T) {
+	var full PrinterStatus
+	fill(t, reflect.ValueOf(&full).Elem(), "")
+	for _, section := range StatusSections {
+		trimmed, _ := json.Marshal(full.Trimmed(map[string]bool{section: true}))
+		var fields map[string]interface{}
+		json.Unmarshal(trimmed, &fields)
+		if _, ok := fields[section]; !ok {
+			t.Errorf("section %q does not select a field of the same name", section)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import "fmt"
+
+// Units is the display unit preference applied to temperatures, weights and lengths.
+// Values in the API stay metric; Units tells clients how to present them.
+type Units struct {
+	Temperature string `json:"temperature"`
+	Weight      string `json:"weight"`
+	Length      string `json:"length"`
+}
+
+// DefaultUnits returns the metric units OctoPrint and Spoolman report in
+func DefaultUnits() Units {
+	return Units{Temperature: "C", Weight: "g", Length: "mm"}
+}
+
+// Validate checks that every unit is supported
+func (u Units) Validate() error {
+	switch u.Temperature {
+	case "C", "F":
+	default:
+		return fmt.Errorf("unsupported temperature unit %q", u.Temperature)
+	}
+
+	switch u.Weight {
+	case "g", "kg", "lb", "oz":
+	default:
+		return fmt.Errorf("unsupported weight unit %q", u.Weight)
+	}
+
+	switch u.Length {
+	case "mm", "m", "in", "ft":
+	default:
+		return fmt.Errorf("unsupported length unit %q", u.Length)
+	}
+
+	return nil
+}
+
+// ConvertTemperature converts degrees Celsius into the given unit
+func ConvertTemperature(celsius float64, unit string) float64 {
+	if unit == "F" {
+		return celsius*9/5 + 32
+	}
+	return celsius
+}
+
+// FormatTemperature formats degrees Celsius in the given unit
+func FormatTemperature(celsius float64, unit string) string {
+	return fmt.Sprintf("%.0f°%s", ConvertTemperature(celsius, unit), unit)
+}
+
+// ConvertWeight converts grams into the given unit
+func ConvertWeight(grams float64, unit string) float64 {
+	switch unit {
+	case "kg":
+		return grams / 1000
+	case "lb":
+		return grams / 453.59237
+	case "oz":
+		return grams / 28.349523125
+	}
+	return grams
+}
+
+// FormatWeight formats grams in the given unit
+func FormatWeight(grams float64, unit string) string {
+	if grams <= 0 {
+		return "--"
+	}
+
+	switch unit {
+	case "kg", "lb":
+		return fmt.Sprintf("%.2f%s", ConvertWeight(grams, unit), unit)
+	case "oz":
+		return fmt.Sprintf("%.1f%s", ConvertWeight(grams, unit), unit)
+	}
+	return fmt.Sprintf("%.0f%s", grams, unit)
+}
+
+// ConvertLength converts millimetres into the given unit
+func ConvertLength(mm float64, unit string) float64 {
+	switch unit {
+	case "m":
+		return mm / 1000
+	case "in":
+		return mm / 25.4
+	case "ft":
+		return mm / 304.8
+	}
+	return mm
+}
+
+// FormatLength formats millimetres in the given unit
+func FormatLength(mm float64, unit string) string {
+	if mm <= 0 {
+		return "--"
+	}
+
+	if unit == "mm" {
+		return fmt.Sprintf("%.0f%s", mm, unit)
+	}
+	return fmt.Sprintf("%.2f%s", ConvertLength(mm, unit), unit)
+}
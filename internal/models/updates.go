@@ -0,0 +1,64 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import "time"
+
+// ComponentUpdate is the installed and available version of one OctoPrint component
+type ComponentUpdate struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	Installed       string `json:"installed"`
+	Available       string `json:"available,omitempty"`
+	UpdateAvailable bool   `json:"update_available"`
+}
+
+// PrinterUpdates is the latest update check for one printer
+type PrinterUpdates struct {
+	PrinterID  string            `json:"printer_id"`
+	Name       string            `json:"name"`
+	CheckedAt  *time.Time        `json:"checked_at,omitempty"`
+	Firmware   string            `json:"firmware,omitempty"`
+	Available  int               `json:"updates_available"`
+	Components []ComponentUpdate `json:"components"`
+	Error      string            `json:"error,omitempty"`
+}
+
+// UpdateSummary rolls update checks up across the farm
+type UpdateSummary struct {
+	PrintersChecked     int                 `json:"printers_checked"`
+	PrintersWithUpdates int                 `json:"printers_with_updates"`
+	Components          []ComponentSummary  `json:"components"`
+	Firmware            map[string][]string `json:"firmware"`
+}
+
+// ComponentSummary lists the printers that can update a component
+type ComponentSummary struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	Available string   `json:"available,omitempty"`
+	Printers  []string `json:"printers"`
+}
+
+// UpdateBadge is the update summary shown on a printer's dashboard card
+type UpdateBadge struct {
+	Available  int      `json:"available"`
+	Components []string `json:"components,omitempty"`
+	Firmware   string   `json:"firmware,omitempty"`
+}
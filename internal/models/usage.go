@@ -0,0 +1,52 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import "time"
+
+// UsageRecord is what one queued job consumed, charged to whoever submitted it.
+// Cancelled jobs are charged for the share they got through. Energy comes from
+// the printer's smart plug and Cost prices it at ENERGY_PRICE_PER_KWH.
+type UsageRecord struct {
+	Entry      string    `json:"entry"`
+	User       string    `json:"user"`
+	PrinterID  string    `json:"printer_id"`
+	File       string    `json:"file"`
+	PrintTime  int       `json:"print_time"`
+	Filament   float64   `json:"filament_m"`
+	EnergyKWh  float64   `json:"energy_kwh,omitempty"`
+	Cost       float64   `json:"cost,omitempty"`
+	Completed  bool      `json:"completed"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// Usage totals a user's consumption over a month
+type Usage struct {
+	User      string  `json:"user"`
+	Jobs      int     `json:"jobs"`
+	PrintTime int     `json:"print_time"`
+	Filament  float64 `json:"filament_m"`
+	EnergyKWh float64 `json:"energy_kwh"`
+	Cost      float64 `json:"cost,omitempty"`
+}
+
+// Hours is the print time in hours
+func (u Usage) Hours() float64 {
+	return float64(u.PrintTime) / 3600
+}
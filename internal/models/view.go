@@ -0,0 +1,46 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import "time"
+
+// View sort orders
+const (
+	ViewSortConfig   = "config"
+	ViewSortName     = "name"
+	ViewSortStatus   = "status"
+	ViewSortProgress = "progress"
+)
+
+// View densities
+const (
+	ViewDensityComfortable = "comfortable"
+	ViewDensityCompact     = "compact"
+)
+
+// View is a user's saved dashboard layout. An empty Printers shows every printer.
+type View struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Printers  []string  `json:"printers"`
+	Sort      string    `json:"sort"`
+	Density   string    `json:"density"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
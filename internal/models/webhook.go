@@ -0,0 +1,48 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Webhook delivery states
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+// WebhookDelivery is one event sent to one webhook, retried until it is
+// delivered or runs out of attempts. Payload is the exact body signed and sent.
+type WebhookDelivery struct {
+	ID           string          `json:"id"`
+	Webhook      string          `json:"webhook"`
+	EventID      string          `json:"event_id"`
+	EventType    string          `json:"event_type"`
+	Status       string          `json:"status"`
+	Attempts     int             `json:"attempts"`
+	ResponseCode int             `json:"response_code,omitempty"`
+	Error        string          `json:"error,omitempty"`
+	CreatedAt    time.Time       `json:"created_at"`
+	NextAttempt  *time.Time      `json:"next_attempt,omitempty"`
+	DeliveredAt  *time.Time      `json:"delivered_at,omitempty"`
+	Payload      json.RawMessage `json:"payload"`
+}
@@ -0,0 +1,708 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octoapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Client covers OctoPrint endpoints that go-3dprint-client does not expose
+type Client struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+
+	// downloadClient has no overall timeout; large transfers are bounded by their context
+	downloadClient *http.Client
+}
+
+// NewClient creates a new OctoPrint API client
+func NewClient(baseURL, apiKey string) *Client {
+	return &Client{
+		baseURL: baseURL,
+		apiKey:  apiKey,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		downloadClient: &http.Client{},
+	}
+}
+
+// SetTimeout changes how long a request may take, for instances on slow links.
+// Downloads are bounded by their context instead.
+func (c *Client) SetTimeout(d time.Duration) {
+	c.httpClient.Timeout = d
+}
+
+// SendCommands sends raw G-code commands to the printer
+func (c *Client) SendCommands(commands ...string) error {
+	payload := map[string]interface{}{
+		"commands": commands,
+	}
+
+	req, err := c.newRequest("POST", "/api/printer/command", payload)
+	if err != nil {
+		return err
+	}
+
+	return c.doRequest(req, nil)
+}
+
+// EmergencyStop sends M112, halting the printer immediately
+func (c *Client) EmergencyStop() error {
+	return c.SendCommands("M112")
+}
+
+// CancelObject sends M486 P<id>, telling the firmware to skip one labelled
+// object for the rest of the print
+func (c *Client) CancelObject(id int) error {
+	return c.SendCommands(fmt.Sprintf("M486 P%d", id))
+}
+
+// ExcludeObject tells Klipper to skip the named object for the rest of the
+// print, as labelled by EXCLUDE_OBJECT_DEFINE
+func (c *Client) ExcludeObject(name string) error {
+	return c.SendCommands("EXCLUDE_OBJECT NAME=" + name)
+}
+
+// ExcludeRegion adds a rectangle to the Exclude Region plugin, which drops any
+// extrusion inside it for the rest of the print
+func (c *Client) ExcludeRegion(id string, minX, minY, maxX, maxY float64) error {
+	payload := map[string]interface{}{
+		"command": "addExcludeRegion",
+		"type":    "RectangularRegion",
+		"id":      id,
+		"x1":      minX,
+		"y1":      minY,
+		"x2":      maxX,
+		"y2":      maxY,
+	}
+
+	req, err := c.newRequest("POST", "/api/plugin/excluderegion", payload)
+	if err != nil {
+		return err
+	}
+
+	return c.doRequest(req, nil)
+}
+
+// Pause pauses the active print job
+func (c *Client) Pause() error {
+	return c.jobCommand(map[string]interface{}{"command": "pause", "action": "pause"})
+}
+
+// Resume resumes a paused print job
+func (c *Client) Resume() error {
+	return c.jobCommand(map[string]interface{}{"command": "pause", "action": "resume"})
+}
+
+// Cancel cancels the active print job
+func (c *Client) Cancel() error {
+	return c.jobCommand(map[string]interface{}{"command": "cancel"})
+}
+
+func (c *Client) jobCommand(payload map[string]interface{}) error {
+	req, err := c.newRequest("POST", "/api/job", payload)
+	if err != nil {
+		return err
+	}
+
+	return c.doRequest(req, nil)
+}
+
+// Version is what /api/version reports about an instance
+type Version struct {
+	API    string `json:"api"`
+	Server string `json:"server"`
+	Text   string `json:"text"`
+}
+
+// Version returns the instance's API and server versions. It needs a valid
+// API key, so it doubles as a credentials check.
+func (c *Client) Version() (Version, error) {
+	var v Version
+	req, err := c.newRequest("GET", "/api/version", nil)
+	if err != nil {
+		return v, err
+	}
+
+	err = c.doRequest(req, &v)
+	return v, err
+}
+
+// ConnectionState returns the serial connection state, e.g. "Closed" or "Operational"
+func (c *Client) ConnectionState() (string, error) {
+	req, err := c.newRequest("GET", "/api/connection", nil)
+	if err != nil {
+		return "", err
+	}
+
+	var response struct {
+		Current struct {
+			State string `json:"state"`
+		} `json:"current"`
+	}
+	if err := c.doRequest(req, &response); err != nil {
+		return "", err
+	}
+
+	return response.Current.State, nil
+}
+
+// Connect opens the serial connection using OctoPrint's saved port settings
+func (c *Client) Connect() error {
+	req, err := c.newRequest("POST", "/api/connection", map[string]interface{}{"command": "connect"})
+	if err != nil {
+		return err
+	}
+
+	return c.doRequest(req, nil)
+}
+
+// File is a printable file stored on an OctoPrint instance
+type File struct {
+	Name    string `json:"name"`
+	Display string `json:"display"`
+	Path    string `json:"path"`
+	Origin  string `json:"origin"`
+	Size    int64  `json:"size"`
+	Date    int64  `json:"date"`
+
+	Analysis *FileAnalysis `json:"gcodeAnalysis,omitempty"`
+	Prints   *FilePrints   `json:"prints,omitempty"`
+}
+
+// FilePrints is OctoPrint's tally of a file's prints, which only keeps the details of the last one
+type FilePrints struct {
+	Success int `json:"success"`
+	Failure int `json:"failure"`
+	Last    *struct {
+		Date      float64 `json:"date"`
+		PrintTime float64 `json:"printTime"`
+		Success   bool    `json:"success"`
+	} `json:"last,omitempty"`
+}
+
+// FileAnalysis is OctoPrint's own estimate for a gcode file
+type FileAnalysis struct {
+	EstimatedPrintTime float64 `json:"estimatedPrintTime"`
+	Filament           map[string]struct {
+		Length float64 `json:"length"`
+	} `json:"filament"`
+}
+
+// ListFiles returns every file on the instance, flattening folders
+func (c *Client) ListFiles() ([]File, error) {
+	req, err := c.newRequest("GET", "/api/files?recursive=true", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Files []fileEntry `json:"files"`
+	}
+	if err := c.doRequest(req, &response); err != nil {
+		return nil, err
+	}
+
+	var files []File
+	flattenFiles(response.Files, &files)
+	return files, nil
+}
+
+// PrintFile selects a file stored locally on OctoPrint and starts printing it
+func (c *Client) PrintFile(path string) error {
+	target := (&url.URL{Path: path}).EscapedPath()
+	req, err := c.newRequest("POST", "/api/files/local/"+target, map[string]interface{}{"command": "select", "print": true})
+	if err != nil {
+		return err
+	}
+
+	return c.doRequest(req, nil)
+}
+
+// DownloadFile streams a file from OctoPrint's local storage to w
+func (c *Client) DownloadFile(ctx context.Context, path string, w io.Writer) (int64, error) {
+	target := (&url.URL{Path: path}).EscapedPath()
+	req, err := c.newRequest("GET", "/downloads/files/local/"+target, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := c.downloadClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return 0, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+	}
+
+	return io.Copy(w, resp.Body)
+}
+
+// UploadFile stores gcode on OctoPrint's local storage as name, replacing any
+// file already there. Like downloads it is bounded by ctx rather than the timeout.
+func (c *Client) UploadFile(ctx context.Context, name string, r io.Reader) error {
+	// Build the form up front; OctoPrint wants a Content-Length on uploads
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, err := mw.CreateFormFile("file", name)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, r); err != nil {
+		return err
+	}
+	if err := mw.Close(); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/api/files/local", &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Api-Key", c.apiKey)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	resp, err := c.downloadClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		msg, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(msg))
+	}
+	return nil
+}
+
+type fileEntry struct {
+	File
+	Type     string      `json:"type"`
+	Children []fileEntry `json:"children"`
+}
+
+func flattenFiles(entries []fileEntry, out *[]File) {
+	for _, e := range entries {
+		if e.Type == "folder" {
+			flattenFiles(e.Children, out)
+			continue
+		}
+		*out = append(*out, e.File)
+	}
+}
+
+// LayerProgress is the DisplayLayerProgress plugin's view of the running print
+type LayerProgress struct {
+	Layer  int
+	Layers int
+	Height float64 // mm
+}
+
+// LayerProgress returns the current layer and height from the DisplayLayerProgress plugin
+func (c *Client) LayerProgress() (LayerProgress, error) {
+	var progress LayerProgress
+
+	req, err := c.newRequest("GET", "/plugin/DisplayLayerProgress/values", nil)
+	if err != nil {
+		return progress, err
+	}
+
+	// The plugin reports numbers as strings, with "-" before the first layer
+	var response struct {
+		Layer struct {
+			Current string `json:"current"`
+			Total   string `json:"total"`
+		} `json:"layer"`
+		Height struct {
+			Current string `json:"current"`
+		} `json:"height"`
+	}
+	if err := c.doRequest(req, &response); err != nil {
+		return progress, err
+	}
+
+	progress.Layer, _ = strconv.Atoi(response.Layer.Current)
+	progress.Layers, _ = strconv.Atoi(response.Layer.Total)
+	progress.Height, _ = strconv.ParseFloat(response.Height.Current, 64)
+	return progress, nil
+}
+
+// Backup is an archive held by OctoPrint's backup plugin
+type Backup struct {
+	Name string `json:"name"`
+	Date int64  `json:"date"`
+	Size int64  `json:"size"`
+}
+
+// BackupState is the backup plugin's view of its archives
+type BackupState struct {
+	Backups    []Backup `json:"backups"`
+	InProgress bool     `json:"backup_in_progress"`
+}
+
+// CreateBackup starts a backup and returns the name the archive will have.
+// OctoPrint writes the archive in the background; poll BackupState for it.
+func (c *Client) CreateBackup() (string, error) {
+	req, err := c.newRequest("POST", "/plugin/backup/backup", map[string]interface{}{"exclude": []string{}})
+	if err != nil {
+		return "", err
+	}
+
+	var response struct {
+		Started bool   `json:"started"`
+		Name    string `json:"name"`
+	}
+	if err := c.doRequest(req, &response); err != nil {
+		return "", err
+	}
+	if response.Name == "" {
+		return "", fmt.Errorf("backup plugin did not name the new backup")
+	}
+
+	return response.Name, nil
+}
+
+// BackupState lists the instance's backups and whether one is being written
+func (c *Client) BackupState() (BackupState, error) {
+	var state BackupState
+
+	req, err := c.newRequest("GET", "/plugin/backup/", nil)
+	if err != nil {
+		return state, err
+	}
+
+	err = c.doRequest(req, &state)
+	return state, err
+}
+
+// DownloadBackup streams the named backup archive to w
+func (c *Client) DownloadBackup(ctx context.Context, name string, w io.Writer) (int64, error) {
+	req, err := c.newRequest("GET", "/plugin/backup/download/"+url.PathEscape(name), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := c.downloadClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return 0, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+	}
+
+	return io.Copy(w, resp.Body)
+}
+
+// DeleteBackup removes the named backup from the instance
+func (c *Client) DeleteBackup(name string) error {
+	req, err := c.newRequest("DELETE", "/plugin/backup/backup/"+url.PathEscape(name), nil)
+	if err != nil {
+		return err
+	}
+
+	return c.doRequest(req, nil)
+}
+
+// Timelapse is a rendered timelapse video held by OctoPrint
+type Timelapse struct {
+	Name  string `json:"name"`
+	Bytes int64  `json:"bytes"`
+	Date  string `json:"date"`
+}
+
+// Timelapses lists the instance's rendered timelapses; ones still rendering are left out
+func (c *Client) Timelapses() ([]Timelapse, error) {
+	req, err := c.newRequest("GET", "/api/timelapse", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Files []Timelapse `json:"files"`
+	}
+	if err := c.doRequest(req, &response); err != nil {
+		return nil, err
+	}
+	return response.Files, nil
+}
+
+// OpenTimelapse starts downloading the named timelapse, returning its body and
+// length. The caller closes the body.
+func (c *Client) OpenTimelapse(ctx context.Context, name string) (io.ReadCloser, int64, error) {
+	req, err := c.newRequest("GET", "/downloads/timelapse/"+url.PathEscape(name), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	resp, err := c.downloadClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, 0, err
+	}
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, 0, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+	}
+	if resp.ContentLength < 0 {
+		resp.Body.Close()
+		return nil, 0, fmt.Errorf("timelapse %s has no length", name)
+	}
+
+	return resp.Body, resp.ContentLength, nil
+}
+
+// DeleteTimelapse removes the named timelapse from the instance
+func (c *Client) DeleteTimelapse(name string) error {
+	req, err := c.newRequest("DELETE", "/api/timelapse/"+url.PathEscape(name), nil)
+	if err != nil {
+		return err
+	}
+
+	return c.doRequest(req, nil)
+}
+
+// SoftwareUpdate is a component tracked by OctoPrint's softwareupdate plugin
+type SoftwareUpdate struct {
+	ID              string
+	Name            string
+	Installed       string
+	Available       string
+	UpdateAvailable bool
+}
+
+// SoftwareUpdates returns the softwareupdate plugin's latest (cached) check, sorted by component ID
+func (c *Client) SoftwareUpdates() ([]SoftwareUpdate, error) {
+	req, err := c.newRequest("GET", "/plugin/softwareupdate/check", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	type version struct {
+		Name string `json:"name"`
+	}
+	var response struct {
+		Information map[string]struct {
+			DisplayName     string `json:"displayName"`
+			DisplayVersion  string `json:"displayVersion"`
+			UpdateAvailable bool   `json:"updateAvailable"`
+			Information     struct {
+				Local  version `json:"local"`
+				Remote version `json:"remote"`
+			} `json:"information"`
+		} `json:"information"`
+	}
+	if err := c.doRequest(req, &response); err != nil {
+		return nil, err
+	}
+
+	updates := make([]SoftwareUpdate, 0, len(response.Information))
+	for id, info := range response.Information {
+		installed := info.Information.Local.Name
+		if installed == "" {
+			installed = info.DisplayVersion
+		}
+		updates = append(updates, SoftwareUpdate{
+			ID:              id,
+			Name:            info.DisplayName,
+			Installed:       installed,
+			Available:       info.Information.Remote.Name,
+			UpdateAvailable: info.UpdateAvailable,
+		})
+	}
+	sort.Slice(updates, func(i, j int) bool { return updates[i].ID < updates[j].ID })
+
+	return updates, nil
+}
+
+// FirmwareVersion returns the firmware name the printer reported to M115, from
+// OctoPrint's system info (OctoPrint 1.8+). It is empty until a printer has connected.
+func (c *Client) FirmwareVersion() (string, error) {
+	req, err := c.newRequest("GET", "/api/system/info", nil)
+	if err != nil {
+		return "", err
+	}
+
+	var response struct {
+		SystemInfo map[string]interface{} `json:"systeminfo"`
+	}
+	if err := c.doRequest(req, &response); err != nil {
+		return "", err
+	}
+
+	firmware, _ := response.SystemInfo["printer.firmware"].(string)
+	return firmware, nil
+}
+
+// Plugin is an installed OctoPrint plugin as reported by the plugin manager
+type Plugin struct {
+	Key          string `json:"key"`
+	Name         string `json:"name"`
+	Version      string `json:"version"`
+	Enabled      bool   `json:"enabled"`
+	Incompatible bool   `json:"incompatible"`
+}
+
+// Plugins lists installed plugins. The API key needs the Plugin Management permission.
+func (c *Client) Plugins() ([]Plugin, error) {
+	req, err := c.newRequest("GET", "/plugin/pluginmanager/plugins", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Plugins []Plugin `json:"plugins"`
+	}
+	if err := c.doRequest(req, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Plugins, nil
+}
+
+// ErrPending means an app key request is still waiting for a user to decide
+var ErrPending = errors.New("app key request pending")
+
+// AppKeyRequest is an app key request awaiting approval in OctoPrint.
+// AuthDialog, sent by OctoPrint 1.8 and later, is where a user approves it.
+type AppKeyRequest struct {
+	AppToken   string `json:"app_token"`
+	AuthDialog string `json:"auth_dialog"`
+}
+
+// RequestAppKey asks OctoPrint's Application Keys plugin to issue a key for
+// app; whichever user approves the request owns the key
+func (c *Client) RequestAppKey(app string) (AppKeyRequest, error) {
+	req, err := c.newRequest("POST", "/plugin/appkeys/request", map[string]string{"app": app})
+	if err != nil {
+		return AppKeyRequest{}, err
+	}
+
+	var request AppKeyRequest
+	err = c.doRequest(req, &request)
+	return request, err
+}
+
+// AppKeyDecision polls an app key request, returning the key once approved
+// and ErrPending until then. OctoPrint answers 404 for denied or expired
+// requests.
+func (c *Client) AppKeyDecision(token string) (string, error) {
+	req, err := c.newRequest("GET", "/plugin/appkeys/request/"+url.PathEscape(token), nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusAccepted {
+		return "", ErrPending
+	}
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return "", &StatusError{Code: resp.StatusCode, Body: string(body)}
+	}
+
+	var response struct {
+		APIKey string `json:"api_key"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return "", err
+	}
+	return response.APIKey, nil
+}
+
+// StatusError is an error response from OctoPrint
+type StatusError struct {
+	Code int
+	Body string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("HTTP %d: %s", e.Code, e.Body)
+}
+
+// notFound reports whether err is a 404, as OctoPrint answers for plugins that aren't installed
+func notFound(err error) bool {
+	var se *StatusError
+	return errors.As(err, &se) && se.Code == http.StatusNotFound
+}
+
+func (c *Client) newRequest(method, path string, body interface{}) (*http.Request, error) {
+	url := c.baseURL + path
+
+	var bodyReader io.Reader
+	if body != nil {
+		jsonBody, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		bodyReader = bytes.NewBuffer(jsonBody)
+	}
+
+	req, err := http.NewRequest(method, url, bodyReader)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("X-Api-Key", c.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	return req, nil
+}
+
+func (c *Client) doRequest(req *http.Request, result interface{}) error {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return &StatusError{Code: resp.StatusCode, Body: string(body)}
+	}
+
+	if result != nil {
+		return json.NewDecoder(resp.Body).Decode(result)
+	}
+
+	return nil
+}
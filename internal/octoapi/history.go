@@ -0,0 +1,209 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octoapi
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Where JobHistory found an instance's past prints
+const (
+	HistoryPrintJobHistory = "printjobhistory"
+	HistoryPrintHistory    = "printhistory"
+	HistoryFiles           = "files"
+)
+
+// HistoricJob is a print an instance remembers finishing. Result is
+// "completed", "failed" or "cancelled"; Started is zero when unknown.
+type HistoricJob struct {
+	ID        string
+	File      string
+	Result    string
+	Started   time.Time
+	Finished  time.Time
+	PrintTime int     // seconds
+	Filament  float64 // mm
+}
+
+// JobHistory returns the instance's past prints from the first source it has:
+// the PrintJobHistory plugin, the older PrintHistory plugin, or failing both,
+// OctoPrint's per-file statistics, which only remember each file's last print.
+// It also reports which source answered.
+func (c *Client) JobHistory() ([]HistoricJob, string, error) {
+	jobs, err := c.printJobHistory()
+	if !notFound(err) {
+		return jobs, HistoryPrintJobHistory, err
+	}
+	jobs, err = c.printHistory()
+	if !notFound(err) {
+		return jobs, HistoryPrintHistory, err
+	}
+	jobs, err = c.fileHistory()
+	return jobs, HistoryFiles, err
+}
+
+// pluginTimeLayouts are the ways PrintJobHistory has written dates, in the instance's local time
+var pluginTimeLayouts = []string{"02.01.2006 15:04", "02.01.2006 15:04:05", "2006-01-02 15:04:05", "2006-01-02 15:04", time.RFC3339}
+
+func (c *Client) printJobHistory() ([]HistoricJob, error) {
+	req, err := c.newRequest("GET", "/plugin/PrintJobHistory/loadPrintJobHistoryByQuery?from=0&to=1000000&sortColumn=printStartDateTime&sortOrder=desc&filterName=all&startDate=&endDate=", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Jobs []struct {
+			ID       int     `json:"databaseId"`
+			File     string  `json:"fileName"`
+			Start    string  `json:"printStartDateTime"`
+			End      string  `json:"printEndDateTime"`
+			Duration float64 `json:"duration"`
+			Status   string  `json:"statusResult"`
+			Filament []struct {
+				UsedLength float64 `json:"usedLength"`
+			} `json:"filamentModels"`
+		} `json:"allPrintJobs"`
+	}
+	if err := c.doRequest(req, &response); err != nil {
+		return nil, err
+	}
+
+	jobs := make([]HistoricJob, 0, len(response.Jobs))
+	for _, j := range response.Jobs {
+		job := HistoricJob{
+			ID:        strconv.Itoa(j.ID),
+			File:      j.File,
+			Result:    pluginResult(j.Status),
+			Started:   parsePluginTime(j.Start),
+			Finished:  parsePluginTime(j.End),
+			PrintTime: int(math.Round(j.Duration)),
+		}
+		for _, f := range j.Filament {
+			job.Filament += f.UsedLength
+		}
+		if job.Finished.IsZero() && !job.Started.IsZero() {
+			job.Finished = job.Started.Add(time.Duration(job.PrintTime) * time.Second)
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// pluginResult maps PrintJobHistory's status ("success", "fail", "canceled") to a result
+func pluginResult(status string) string {
+	status = strings.ToLower(status)
+	switch {
+	case strings.HasPrefix(status, "success"):
+		return "completed"
+	case strings.HasPrefix(status, "cancel"):
+		return "cancelled"
+	}
+	return "failed"
+}
+
+func parsePluginTime(v string) time.Time {
+	for _, layout := range pluginTimeLayouts {
+		if t, err := time.ParseInLocation(layout, strings.TrimSpace(v), time.Local); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (c *Client) printHistory() ([]HistoricJob, error) {
+	req, err := c.newRequest("GET", "/plugin/printhistory/history", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		History []struct {
+			Key       string  `json:"key"`
+			File      string  `json:"fileName"`
+			Timestamp float64 `json:"timestamp"`
+			PrintTime float64 `json:"printTime"`
+			Success   bool    `json:"success"`
+			Filament  float64 `json:"filamentLength"`
+		} `json:"history"`
+	}
+	if err := c.doRequest(req, &response); err != nil {
+		return nil, err
+	}
+
+	jobs := make([]HistoricJob, 0, len(response.History))
+	for _, h := range response.History {
+		job := HistoricJob{
+			ID:        h.Key,
+			File:      h.File,
+			Result:    "failed",
+			Finished:  unixTime(h.Timestamp),
+			PrintTime: int(math.Round(h.PrintTime)),
+			Filament:  h.Filament,
+		}
+		if h.Success {
+			job.Result = "completed"
+		}
+		if job.ID == "" {
+			job.ID = fmt.Sprintf("%s@%d", h.File, int64(h.Timestamp))
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+func (c *Client) fileHistory() ([]HistoricJob, error) {
+	files, err := c.ListFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []HistoricJob
+	for _, f := range files {
+		if f.Prints == nil || f.Prints.Last == nil || f.Prints.Last.Date == 0 {
+			continue
+		}
+		last := f.Prints.Last
+		job := HistoricJob{
+			ID:        f.Origin + "/" + f.Path,
+			File:      f.Display,
+			Result:    "failed",
+			Finished:  unixTime(last.Date),
+			PrintTime: int(math.Round(last.PrintTime)),
+		}
+		if job.File == "" {
+			job.File = f.Name
+		}
+		if last.Success {
+			job.Result = "completed"
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+func unixTime(seconds float64) time.Time {
+	if seconds <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, int64(seconds*float64(time.Second)))
+}
@@ -0,0 +1,77 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octoapi
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Recorder is a transport that notes each request instead of sending it,
+// answering every one with an empty JSON object
+type Recorder struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+// NewRecording creates a client for the instance at baseURL whose requests
+// only go as far as rec
+func NewRecording(baseURL string, rec *Recorder) *Client {
+	c := NewClient(baseURL, "")
+	c.httpClient.Transport = rec
+	c.downloadClient.Transport = rec
+	return c
+}
+
+// RoundTrip records the request as "METHOD URL body"
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	call := req.Method + " " + req.URL.String()
+	if req.Body != nil {
+		body, _ := io.ReadAll(io.LimitReader(req.Body, 4096))
+		req.Body.Close()
+		if len(body) > 0 {
+			call += " " + strings.TrimSpace(string(body))
+		}
+	}
+	r.Record(call)
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader([]byte("{}"))),
+		Request:    req,
+	}, nil
+}
+
+// Record notes a call made some other way
+func (r *Recorder) Record(call string) {
+	r.mu.Lock()
+	r.calls = append(r.calls, call)
+	r.mu.Unlock()
+}
+
+// Calls returns the calls recorded so far
+func (r *Recorder) Calls() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.calls...)
+}
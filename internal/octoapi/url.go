@@ -0,0 +1,63 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octoapi
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// NormalizeBaseURL checks an OctoPrint address and strips trailing slashes, so
+// instances on a nonstandard port or under a subpath (http://host/printer1/)
+// build request URLs the same way as one at the root of its host
+func NormalizeBaseURL(raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("%q must start with http:// or https://", raw)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("%q has no host", raw)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return "", fmt.Errorf("%q must not have a query or fragment", raw)
+	}
+
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.RawPath = strings.TrimRight(u.RawPath, "/")
+	return u.String(), nil
+}
+
+// ThumbnailURL returns the Slicer Thumbnails plugin URL for a file on the
+// instance at baseURL, escaping folder and file names
+func ThumbnailURL(baseURL, path string) string {
+	if path == "" {
+		return ""
+	}
+
+	name := strings.TrimSuffix(strings.TrimSuffix(path, ".gcode"), ".bgcode") + ".png"
+	segments := strings.Split(name, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return baseURL + "/plugin/prusaslicerthumbnails/thumbnail/" + strings.Join(segments, "/")
+}
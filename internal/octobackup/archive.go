@@ -0,0 +1,153 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octobackup
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+)
+
+// Archive stores OctoPrint backups on disk, one directory per printer
+type Archive struct {
+	Dir  string
+	Keep int
+}
+
+// validName rejects anything that could escape the archive directory
+func validName(name string) bool {
+	return name != "" && !strings.HasPrefix(name, ".") && filepath.Base(name) == name && !strings.ContainsAny(name, `/\`)
+}
+
+func (a *Archive) path(printerID, name string) (string, error) {
+	if !validName(printerID) || !validName(name) || !strings.HasSuffix(name, ".zip") {
+		return "", fmt.Errorf("invalid backup name %q", name)
+	}
+	return filepath.Join(a.Dir, printerID, name), nil
+}
+
+// Store saves the archive produced by write, then prunes older ones for the printer
+func (a *Archive) Store(printerID, name string, write func(io.Writer) (int64, error)) (models.OctoPrintBackup, error) {
+	backup := models.OctoPrintBackup{PrinterID: printerID, Name: name}
+
+	path, err := a.path(printerID, name)
+	if err != nil {
+		return backup, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return backup, err
+	}
+
+	// Download to a hidden file so a partial transfer never looks like a backup
+	tmp := filepath.Join(filepath.Dir(path), "."+name+".tmp")
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return backup, err
+	}
+	backup.Size, err = write(f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return backup, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return backup, err
+	}
+	backup.StoredAt = info.ModTime().UTC()
+
+	return backup, a.prune(printerID)
+}
+
+// List returns a printer's stored backups, newest first
+func (a *Archive) List(printerID string) ([]models.OctoPrintBackup, error) {
+	backups := []models.OctoPrintBackup{}
+	if !validName(printerID) {
+		return backups, nil
+	}
+
+	entries, err := os.ReadDir(filepath.Join(a.Dir, printerID))
+	if os.IsNotExist(err) {
+		return backups, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range entries {
+		if !e.Type().IsRegular() || !validName(e.Name()) || !strings.HasSuffix(e.Name(), ".zip") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		backups = append(backups, models.OctoPrintBackup{
+			PrinterID: printerID,
+			Name:      e.Name(),
+			Size:      info.Size(),
+			StoredAt:  info.ModTime().UTC(),
+		})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		if !backups[i].StoredAt.Equal(backups[j].StoredAt) {
+			return backups[i].StoredAt.After(backups[j].StoredAt)
+		}
+		return backups[i].Name > backups[j].Name
+	})
+	return backups, nil
+}
+
+// Open opens a stored backup for reading
+func (a *Archive) Open(printerID, name string) (*os.File, error) {
+	path, err := a.path(printerID, name)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+func (a *Archive) prune(printerID string) error {
+	if a.Keep <= 0 {
+		return nil
+	}
+
+	backups, err := a.List(printerID)
+	if err != nil {
+		return err
+	}
+	for _, b := range backups[min(a.Keep, len(backups)):] {
+		if err := os.Remove(filepath.Join(a.Dir, printerID, b.Name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
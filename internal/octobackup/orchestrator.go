@@ -0,0 +1,312 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octobackup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/octoapi"
+)
+
+var (
+	// ErrUnknownPrinter is returned for a printer the orchestrator does not manage
+	ErrUnknownPrinter = errors.New("unknown printer")
+
+	// ErrBusy is returned when a backup of the printer is already queued or running
+	ErrBusy = errors.New("backup already in progress")
+)
+
+// Client is the OctoPrint backup plugin API
+type Client interface {
+	CreateBackup() (string, error)
+	BackupState() (octoapi.BackupState, error)
+	DownloadBackup(ctx context.Context, name string, w io.Writer) (int64, error)
+	DeleteBackup(name string) error
+}
+
+// Printer is an OctoPrint instance to back up
+type Printer struct {
+	ID     string
+	Name   string
+	Client Client
+}
+
+// Options tunes the orchestrator
+type Options struct {
+	// Interval between scheduled runs; zero only backs up on request
+	Interval time.Duration
+	// Timeout bounds creating and downloading a single backup
+	Timeout time.Duration
+	// PollInterval is how often OctoPrint is asked whether the backup is written
+	PollInterval time.Duration
+	// DeleteRemote removes the backup from OctoPrint once it is stored
+	DeleteRemote bool
+}
+
+// Orchestrator has each OctoPrint instance create a backup and copies it into an Archive
+type Orchestrator struct {
+	printers []Printer
+	archive  *Archive
+	opts     Options
+	logger   *log.Logger
+	requests chan string
+
+	mu    sync.Mutex
+	state map[string]*printerState
+}
+
+type printerState struct {
+	pending     bool
+	running     bool
+	lastSuccess time.Time
+	lastErr     string
+}
+
+// New creates an orchestrator; call Run to start it
+func New(printers []Printer, archive *Archive, opts Options, logger *log.Logger) *Orchestrator {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = 5 * time.Second
+	}
+
+	o := &Orchestrator{
+		printers: printers,
+		archive:  archive,
+		opts:     opts,
+		logger:   logger,
+		requests: make(chan string, len(printers)),
+		state:    make(map[string]*printerState),
+	}
+	for _, p := range printers {
+		o.state[p.ID] = &printerState{}
+	}
+	return o
+}
+
+// Run backs up every printer each interval and serves Trigger requests until ctx is cancelled
+func (o *Orchestrator) Run(ctx context.Context) {
+	var tick <-chan time.Time
+	if o.opts.Interval > 0 {
+		ticker := time.NewTicker(o.opts.Interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-tick:
+			for _, p := range o.printers {
+				if ctx.Err() != nil {
+					return
+				}
+				if o.claim(p.ID, false) == nil {
+					o.run(ctx, p)
+				}
+			}
+		case id := <-o.requests:
+			if p, ok := o.printer(id); ok {
+				o.run(ctx, p)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Trigger queues a backup of one printer for Run to take
+func (o *Orchestrator) Trigger(printerID string) error {
+	if err := o.claim(printerID, true); err != nil {
+		return err
+	}
+
+	// Claimed printers are never queued twice, so the buffer always has room
+	o.requests <- printerID
+	return nil
+}
+
+// Backup backs up one printer immediately
+func (o *Orchestrator) Backup(ctx context.Context, printerID string) (models.OctoPrintBackup, error) {
+	p, ok := o.printer(printerID)
+	if !ok {
+		return models.OctoPrintBackup{}, ErrUnknownPrinter
+	}
+	if err := o.claim(printerID, false); err != nil {
+		return models.OctoPrintBackup{}, err
+	}
+	return o.run(ctx, p)
+}
+
+// Status reports the state and stored backups of every printer
+func (o *Orchestrator) Status() ([]models.OctoPrintBackupStatus, error) {
+	statuses := make([]models.OctoPrintBackupStatus, 0, len(o.printers))
+	for _, p := range o.printers {
+		backups, err := o.archive.List(p.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		o.mu.Lock()
+		st := o.state[p.ID]
+		status := models.OctoPrintBackupStatus{
+			PrinterID: p.ID,
+			Name:      p.Name,
+			Running:   st.running,
+			Pending:   st.pending,
+			LastError: st.lastErr,
+			Backups:   backups,
+		}
+		if !st.lastSuccess.IsZero() {
+			t := st.lastSuccess
+			status.LastSuccess = &t
+		}
+		o.mu.Unlock()
+
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Archive returns where backups are stored
+func (o *Orchestrator) Archive() *Archive {
+	return o.archive
+}
+
+func (o *Orchestrator) printer(id string) (Printer, bool) {
+	for _, p := range o.printers {
+		if p.ID == id {
+			return p, true
+		}
+	}
+	return Printer{}, false
+}
+
+// claim marks a printer queued or running, failing if it already is either
+func (o *Orchestrator) claim(printerID string, queued bool) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	st, ok := o.state[printerID]
+	if !ok {
+		return ErrUnknownPrinter
+	}
+	if st.pending || st.running {
+		return ErrBusy
+	}
+	if queued {
+		st.pending = true
+	} else {
+		st.running = true
+	}
+	return nil
+}
+
+// run backs up a claimed printer and records the outcome
+func (o *Orchestrator) run(ctx context.Context, p Printer) (models.OctoPrintBackup, error) {
+	o.mu.Lock()
+	o.state[p.ID].pending = false
+	o.state[p.ID].running = true
+	o.mu.Unlock()
+
+	backup, err := o.backup(ctx, p)
+
+	o.mu.Lock()
+	st := o.state[p.ID]
+	st.running = false
+	if err != nil {
+		st.lastErr = err.Error()
+	} else {
+		st.lastErr = ""
+		st.lastSuccess = time.Now().UTC()
+	}
+	o.mu.Unlock()
+
+	if err != nil {
+		o.logger.Printf("OctoPrint backup of %s failed: %v", p.Name, err)
+	} else {
+		o.logger.Printf("OctoPrint backup %s of %s stored (%d bytes)", backup.Name, p.Name, backup.Size)
+	}
+	return backup, err
+}
+
+func (o *Orchestrator) backup(ctx context.Context, p Printer) (models.OctoPrintBackup, error) {
+	if o.opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.opts.Timeout)
+		defer cancel()
+	}
+
+	name, err := p.Client.CreateBackup()
+	if err != nil {
+		return models.OctoPrintBackup{}, fmt.Errorf("create backup: %w", err)
+	}
+	if err := o.waitFor(ctx, p.Client, name); err != nil {
+		return models.OctoPrintBackup{}, err
+	}
+
+	backup, err := o.archive.Store(p.ID, name, func(w io.Writer) (int64, error) {
+		return p.Client.DownloadBackup(ctx, name, w)
+	})
+	if err != nil {
+		return backup, fmt.Errorf("download %s: %w", name, err)
+	}
+
+	if o.opts.DeleteRemote {
+		if err := p.Client.DeleteBackup(name); err != nil {
+			o.logger.Printf("Deleting backup %s from %s failed: %v", name, p.Name, err)
+		}
+	}
+	return backup, nil
+}
+
+// waitFor polls until OctoPrint has finished writing the named backup
+func (o *Orchestrator) waitFor(ctx context.Context, client Client, name string) error {
+	ticker := time.NewTicker(o.opts.PollInterval)
+	defer ticker.Stop()
+
+	// A missing archive with nothing in progress twice in a row means the backup failed
+	missing := 0
+	for {
+		state, err := client.BackupState()
+		if err != nil {
+			return fmt.Errorf("check backup state: %w", err)
+		}
+		for _, b := range state.Backups {
+			if b.Name == name && !state.InProgress {
+				return nil
+			}
+		}
+		if !state.InProgress {
+			if missing++; missing >= 2 {
+				return fmt.Errorf("backup %s was not created", name)
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for backup %s: %w", name, ctx.Err())
+		}
+	}
+}
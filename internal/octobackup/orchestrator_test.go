@@ -0,0 +1,158 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octobackup
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/octoapi"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func newOrchestrator(t *testing.T, keep int, deleteRemote bool) (*Orchestrator, *testutil.OctoPrint, string) {
+	t.Helper()
+	op := testutil.NewOctoPrint(t)
+	dir := t.TempDir()
+
+	o := New(
+		[]Printer{{ID: "printer-1", Name: "Prusa", Client: octoapi.NewClient(op.URL, "key")}},
+		&Archive{Dir: dir, Keep: keep},
+		Options{Timeout: 5 * time.Second, PollInterval: 10 * time.Millisecond, DeleteRemote: deleteRemote},
+		log.New(io.Discard, "", 0),
+	)
+	return o, op, dir
+}
+
+func TestBackupStoresArchive(t *testing.T) {
+	o, op, dir := newOrchestrator(t, 0, false)
+
+	backup, err := o.Backup(context.Background(), "printer-1")
+	if err != nil {
+		t.Fatalf("Backup: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "printer-1", backup.Name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != testutil.BackupContent(backup.Name) {
+		t.Errorf("stored %q, want %q", data, testutil.BackupContent(backup.Name))
+	}
+	if backup.Size != int64(len(data)) {
+		t.Errorf("size = %d, want %d", backup.Size, len(data))
+	}
+	if len(op.Backups()) != 1 {
+		t.Errorf("OctoPrint holds %v, want the backup kept", op.Backups())
+	}
+}
+
+func TestBackupPrunesAndDeletesRemote(t *testing.T) {
+	o, op, _ := newOrchestrator(t, 2, true)
+
+	for i := 0; i < 3; i++ {
+		if _, err := o.Backup(context.Background(), "printer-1"); err != nil {
+			t.Fatalf("Backup %d: %v", i, err)
+		}
+	}
+
+	statuses, err := o.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	backups := statuses[0].Backups
+	if len(backups) != 2 {
+		t.Fatalf("kept %d backups, want 2", len(backups))
+	}
+	if backups[0].Name != "octoprint-backup-3.zip" || backups[1].Name != "octoprint-backup-2.zip" {
+		t.Errorf("kept %s and %s, want the two newest", backups[0].Name, backups[1].Name)
+	}
+	if statuses[0].LastSuccess == nil || statuses[0].LastError != "" {
+		t.Errorf("status = %+v, want a recorded success", statuses[0])
+	}
+	if remote := op.Backups(); len(remote) != 0 {
+		t.Errorf("OctoPrint still holds %v", remote)
+	}
+}
+
+func TestBackupFailureIsRecorded(t *testing.T) {
+	o, op, _ := newOrchestrator(t, 0, false)
+	op.SetFault(testutil.Fault{StatusCode: http.StatusInternalServerError})
+
+	if _, err := o.Backup(context.Background(), "printer-1"); err == nil {
+		t.Fatal("Backup succeeded against a failing OctoPrint")
+	}
+
+	statuses, err := o.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if statuses[0].LastError == "" || statuses[0].Running {
+		t.Errorf("status = %+v, want an idle printer with an error", statuses[0])
+	}
+}
+
+func TestTrigger(t *testing.T) {
+	o, _, _ := newOrchestrator(t, 0, false)
+
+	if err := o.Trigger("printer-9"); !errors.Is(err, ErrUnknownPrinter) {
+		t.Errorf("unknown printer: err = %v", err)
+	}
+	if err := o.Trigger("printer-1"); err != nil {
+		t.Fatalf("Trigger: %v", err)
+	}
+	if err := o.Trigger("printer-1"); !errors.Is(err, ErrBusy) {
+		t.Errorf("second trigger: err = %v, want ErrBusy", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go o.Run(ctx)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		statuses, _ := o.Status()
+		if len(statuses[0].Backups) == 1 && !statuses[0].Pending && !statuses[0].Running {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("triggered backup never stored: %+v", statuses[0])
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestArchiveRejectsUnsafeNames(t *testing.T) {
+	a := &Archive{Dir: t.TempDir()}
+	for _, name := range []string{"../escape.zip", "sub/dir.zip", ".hidden.zip", "notes.txt", ""} {
+		if _, err := a.Open("printer-1", name); err == nil || os.IsNotExist(err) {
+			t.Errorf("Open(%q) err = %v, want a validation error", name, err)
+		}
+	}
+	if _, err := a.Open("..", "backup.zip"); err == nil || os.IsNotExist(err) {
+		t.Errorf("Open with printer \"..\" err = %v, want a validation error", err)
+	}
+}
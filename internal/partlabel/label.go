@@ -0,0 +1,117 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package partlabel prints a label for each finished part on a network label
+// printer, so parts from an order-based farm can be matched back to the job
+// that made them
+package partlabel
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/skip2/go-qrcode"
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/pdf"
+)
+
+// Label is what goes on a finished part: the file it was printed from, the
+// printer and finish time, and a QR code linking to URL
+type Label struct {
+	File       string
+	Printer    string
+	FinishedAt time.Time
+	URL        string
+}
+
+// qr returns the label's QR code modules without a quiet zone
+func (l Label) qr() ([][]bool, error) {
+	code, err := qrcode.New(l.URL, qrcode.Medium)
+	if err != nil {
+		return nil, err
+	}
+	code.DisableBorder = true
+	return code.Bitmap(), nil
+}
+
+// ZPL renders the label as a ZPL II program for a width by height mm label at
+// dpi, with the QR code on the left and the text beside it
+func ZPL(l Label, width, height float64, dpi int, loc *time.Location) ([]byte, error) {
+	modules, err := l.qr()
+	if err != nil {
+		return nil, err
+	}
+	dots := func(mm float64) int { return int(mm * float64(dpi) / 25.4) }
+	w, h, margin := dots(width), dots(height), dots(2)
+
+	// ^BQ magnifies each module to a whole number of dots, 1 to 10
+	mag := min(max((h-2*margin)/len(modules), 1), 10)
+	x := 2*margin + len(modules)*mag
+	big, small := h/6, h/9
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "^XA^CI28^PW%d^LL%d\n", w, h)
+	fmt.Fprintf(&b, "^FO%d,%d^BQN,2,%d^FDMA,%s^FS\n", margin, margin, mag, l.URL)
+	fmt.Fprintf(&b, "^FO%d,%d^A0N,%d,%d^FB%d,2,0,L^FH^FD%s^FS\n", x, margin, big, big, w-x-margin, zplEscape(l.File))
+	fmt.Fprintf(&b, "^FO%d,%d^A0N,%d,%d^FH^FD%s^FS\n", x, margin+2*big+small/2, small, small, zplEscape(l.Printer))
+	fmt.Fprintf(&b, "^FO%d,%d^A0N,%d,%d^FD%s^FS\n", x, margin+2*big+small*2, small, small, models.FormatTimestamp(l.FinishedAt, loc))
+	b.WriteString("^XZ\n")
+	return b.Bytes(), nil
+}
+
+// zplEscape hex-escapes the characters ZPL treats as commands, for fields
+// preceded by ^FH
+func zplEscape(s string) string {
+	return strings.NewReplacer("_", "_5F", "^", "_5E", "~", "_7E").Replace(s)
+}
+
+// PDF renders the label as a one-page width by height mm PDF
+func PDF(l Label, width, height float64, loc *time.Location) ([]byte, error) {
+	modules, err := l.qr()
+	if err != nil {
+		return nil, err
+	}
+	w, h, margin := width*pdf.MM, height*pdf.MM, 2*pdf.MM
+	doc := pdf.New(w, h)
+	doc.AddPage()
+
+	size := h - 2*margin
+	module := size / float64(len(modules))
+	for row, line := range modules {
+		for col, dark := range line {
+			if dark {
+				doc.Rect(margin+float64(col)*module, h-margin-float64(row+1)*module, module, module)
+			}
+		}
+	}
+
+	x := 2*margin + size
+	room := w - x - margin
+	big, small := h/8, h/11
+	doc.Text(x, h-margin-big, big, pdf.Bold, pdf.Fit(l.File, room, big))
+	doc.Text(x, h-margin-2*big-small, small, pdf.Regular, pdf.Fit(l.Printer, room, small))
+	doc.Text(x, h-margin-2*big-2.4*small, small, pdf.Regular, models.FormatTimestamp(l.FinishedAt, loc))
+
+	var b bytes.Buffer
+	if _, err := doc.WriteTo(&b); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,198 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package partlabel
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/settings"
+)
+
+const (
+	// queued is how many labels can wait for the printer before new ones are dropped
+	queued = 50
+	// sendTimeout bounds sending each label to the printer
+	sendTimeout = 15 * time.Second
+)
+
+// Printer sends labels to a network label printer from a background loop, so
+// a printer that is off or out of labels never holds up polling. A label that
+// fails to print is logged and not retried.
+type Printer struct {
+	cfg    settings.PartLabelSettings
+	loc    *time.Location
+	client *http.Client
+	logger *log.Logger
+	queue  chan Label
+}
+
+// NewPrinter creates a Printer for cfg; call Run to start printing. Finish
+// times are printed in loc.
+func NewPrinter(cfg settings.PartLabelSettings, loc *time.Location, logger *log.Logger) *Printer {
+	return &Printer{
+		cfg:    cfg,
+		loc:    loc,
+		client: &http.Client{Timeout: sendTimeout},
+		logger: logger,
+		queue:  make(chan Label, queued),
+	}
+}
+
+// Print queues a label without waiting, dropping it if the queue is full
+func (p *Printer) Print(l Label) {
+	select {
+	case p.queue <- l:
+	default:
+		p.logger.Printf("Label printer is backed up; dropped label for %s", l.File)
+	}
+}
+
+// Run prints queued labels until ctx is cancelled
+func (p *Printer) Run(ctx context.Context) {
+	for {
+		select {
+		case l := <-p.queue:
+			if err := p.send(ctx, l); err != nil {
+				p.logger.Printf("Failed to print label for %s on %s: %v", l.File, l.Printer, err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// send renders one label in the printer's language and delivers it
+func (p *Printer) send(ctx context.Context, l Label) error {
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	if p.cfg.Protocol == "ipp" {
+		doc, err := PDF(l, p.cfg.Width, p.cfg.Height, p.loc)
+		if err != nil {
+			return err
+		}
+		return p.sendIPP(ctx, l.File, doc)
+	}
+
+	doc, err := ZPL(l, p.cfg.Width, p.cfg.Height, p.cfg.DPI, p.loc)
+	if err != nil {
+		return err
+	}
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", p.cfg.Address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	_, err = conn.Write(doc)
+	return err
+}
+
+// sendIPP submits a PDF as an IPP Print-Job request
+func (p *Printer) sendIPP(ctx context.Context, name string, doc []byte) error {
+	target, err := url.Parse(p.cfg.Address)
+	if err != nil {
+		return err
+	}
+	printerURI := *target
+	switch target.Scheme {
+	case "ipp", "ipps":
+		if target.Port() == "" {
+			target.Host = net.JoinHostPort(target.Hostname(), "631")
+		}
+		target.Scheme = map[string]string{"ipp": "http", "ipps": "https"}[target.Scheme]
+	case "http", "https":
+		printerURI.Scheme = map[string]string{"http": "ipp", "https": "ipps"}[target.Scheme]
+	}
+
+	body := ippPrintJob(printerURI.String(), name, doc)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/ipp")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("printer returned %s", resp.Status)
+	}
+
+	// The response starts with the IPP version and a status code, where
+	// 0x0000 to 0x00ff are the successful ones
+	head := make([]byte, 4)
+	if _, err := io.ReadFull(resp.Body, head); err != nil {
+		return fmt.Errorf("reading IPP response: %w", err)
+	}
+	if code := binary.BigEndian.Uint16(head[2:]); code > 0x00ff {
+		return fmt.Errorf("printer rejected the job with IPP status 0x%04x", code)
+	}
+	return nil
+}
+
+// IPP value tags for the attributes a Print-Job request carries
+const (
+	tagOperation = 0x01
+	tagEnd       = 0x03
+	tagName      = 0x42
+	tagURI       = 0x45
+	tagCharset   = 0x47
+	tagLanguage  = 0x48
+	tagMimeType  = 0x49
+)
+
+// ippPrintJob encodes an IPP/1.1 Print-Job request for a PDF document
+func ippPrintJob(printerURI, jobName string, doc []byte) []byte {
+	var b bytes.Buffer
+	b.Write([]byte{1, 1})    // version 1.1
+	b.Write([]byte{0, 0x02}) // Print-Job
+	binary.Write(&b, binary.BigEndian, uint32(1))
+	b.WriteByte(tagOperation)
+	attr := func(tag byte, name, value string) {
+		b.WriteByte(tag)
+		binary.Write(&b, binary.BigEndian, uint16(len(name)))
+		b.WriteString(name)
+		binary.Write(&b, binary.BigEndian, uint16(len(value)))
+		b.WriteString(value)
+	}
+	attr(tagCharset, "attributes-charset", "utf-8")
+	attr(tagLanguage, "attributes-natural-language", "en")
+	attr(tagURI, "printer-uri", printerURI)
+	attr(tagName, "requesting-user-name", "octodash")
+	attr(tagName, "job-name", jobName)
+	attr(tagMimeType, "document-format", "application/pdf")
+	b.WriteByte(tagEnd)
+	b.Write(doc)
+	return b.Bytes()
+}
@@ -0,0 +1,71 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package partlabel
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/settings"
+)
+
+func TestSendIPP(t *testing.T) {
+	var got []byte
+	status := []byte{0, 0}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/ipp" {
+			t.Errorf("Content-Type = %q", r.Header.Get("Content-Type"))
+		}
+		got, _ = io.ReadAll(r.Body)
+		w.Write(append([]byte{1, 1}, append(status, 0, 0, 0, 1, 3)...))
+	}))
+	defer srv.Close()
+
+	p := NewPrinter(settings.PartLabelSettings{
+		Protocol: "ipp",
+		Address:  strings.Replace(srv.URL, "http://", "ipp://", 1) + "/ipp/print",
+		Width:    62,
+		Height:   29,
+	}, time.UTC, log.New(io.Discard, "", 0))
+	label := Label{File: "bracket.gcode", Printer: "MK4", FinishedAt: time.Now(), URL: "http://octodash.local/jobs/printer-1/x"}
+
+	if err := p.send(context.Background(), label); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(got, []byte{1, 1, 0, 2}) {
+		t.Errorf("request is not an IPP/1.1 Print-Job: % x", got[:min(4, len(got))])
+	}
+	for _, want := range []string{"printer-uri", "ipp://" + strings.TrimPrefix(srv.URL, "http://") + "/ipp/print", "bracket.gcode", "application/pdf", "%PDF-"} {
+		if !bytes.Contains(got, []byte(want)) {
+			t.Errorf("request is missing %q", want)
+		}
+	}
+
+	status = []byte{0x04, 0x00} // client-error-bad-request
+	if err := p.send(context.Background(), label); err == nil || !strings.Contains(err.Error(), "0x0400") {
+		t.Errorf("rejected job error = %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+func TestWriteTo(t *testing.T) {
+	d := New(A4Width, A4Height)
+	d.Text(40, 800, 14, Bold, "Shift handover")
+	d.Line(40, 790, 555, 790, 0.5)
+	d.AddPage()
+	d.Rect(40, 40, 10, 10)
+
+	var buf bytes.Buffer
+	if _, err := d.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.Bytes()
+	if !bytes.HasPrefix(out, []byte("%PDF-")) || !bytes.HasSuffix(out, []byte("%%EOF\n")) {
+		t.Fatalf("output is not framed as a PDF:\n%s", out)
+	}
+	if !bytes.Contains(out, []byte("/Count 2")) {
+		t.Errorf("page tree does not count two pages")
+	}
+
+	// Every xref entry must point at the start of its object
+	m := regexp.MustCompile(`startxref\n(\d+)`).FindSubmatch(out)
+	if m == nil {
+		t.Fatal("missing startxref")
+	}
+	xref, _ := strconv.Atoi(string(m[1]))
+	entries := regexp.MustCompile(`(\d{10}) 00000 n`).FindAllSubmatch(out[xref:], -1)
+	if len(entries) != 8 {
+		t.Fatalf("xref has %d objects, want 8", len(entries))
+	}
+	for i, e := range entries {
+		off, _ := strconv.Atoi(string(e[1]))
+		want := fmt.Sprintf("%d 0 obj", i+1)
+		if !bytes.HasPrefix(out[off:], []byte(want)) {
+			t.Errorf("xref entry %d points at %q, want %q", i+1, out[off:off+len(want)], want)
+		}
+	}
+}
+
+func TestEscape(t *testing.T) {
+	for in, want := range map[string]string{
+		`part (v2)\final`: `part \(v2\)\\final`,
+		"café":            `caf\351`,
+		"a – b":           "a - b",
+		"tab\there":       "tab here",
+		"日本":              "??",
+	} {
+		if got := escape(in); got != want {
+			t.Errorf("escape(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
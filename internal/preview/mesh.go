@@ -0,0 +1,212 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package preview
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ErrUnsupported is returned for files that are not STL or 3MF models
+var ErrUnsupported = errors.New("unsupported model format, expected .stl or .3mf")
+
+// Vec is a point in model space, in millimetres
+type Vec [3]float64
+
+// Triangle is one face of a mesh
+type Triangle [3]Vec
+
+// Mesh is the triangle soup of a model, which is all a preview needs
+type Mesh struct {
+	Triangles []Triangle
+}
+
+// IsModel reports whether name looks like an unsliced model OctoDash can preview
+func IsModel(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".stl", ".3mf":
+		return true
+	}
+	return false
+}
+
+// Parse reads a model, picking the format from the file extension
+func Parse(name string, data []byte) (*Mesh, error) {
+	var mesh *Mesh
+	var err error
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".stl":
+		mesh, err = parseSTL(data)
+	case ".3mf":
+		mesh, err = parse3MF(data)
+	default:
+		return nil, ErrUnsupported
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(mesh.Triangles) == 0 {
+		return nil, errors.New("model has no triangles")
+	}
+	return mesh, nil
+}
+
+// parseSTL handles both binary and ASCII STL. Some exporters start binary
+// files with "solid" too, so the size check decides.
+func parseSTL(data []byte) (*Mesh, error) {
+	if len(data) >= 84 {
+		count := binary.LittleEndian.Uint32(data[80:84])
+		if uint64(len(data)) == 84+50*uint64(count) {
+			return parseBinarySTL(data[84:], int(count)), nil
+		}
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("solid")) {
+		return parseASCIISTL(data)
+	}
+	return nil, errors.New("not a valid STL file")
+}
+
+func parseBinarySTL(data []byte, count int) *Mesh {
+	mesh := &Mesh{Triangles: make([]Triangle, count)}
+	for i := range mesh.Triangles {
+		// Each record is a normal, three vertices and a two byte attribute
+		rec := data[i*50+12:]
+		for v := 0; v < 3; v++ {
+			for axis := 0; axis < 3; axis++ {
+				bits := binary.LittleEndian.Uint32(rec[(v*3+axis)*4:])
+				mesh.Triangles[i][v][axis] = float64(math.Float32frombits(bits))
+			}
+		}
+	}
+	return mesh
+}
+
+func parseASCIISTL(data []byte) (*Mesh, error) {
+	mesh := &Mesh{}
+	var tri Triangle
+	n := 0
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 4 || fields[0] != "vertex" {
+			continue
+		}
+		for axis := 0; axis < 3; axis++ {
+			f, err := strconv.ParseFloat(fields[axis+1], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid STL vertex %q", scanner.Text())
+			}
+			tri[n][axis] = f
+		}
+		if n++; n == 3 {
+			mesh.Triangles = append(mesh.Triangles, tri)
+			n = 0
+		}
+	}
+	return mesh, scanner.Err()
+}
+
+// parse3MF reads every object mesh in a 3MF package. Build transforms are
+// ignored, which is fine for a thumbnail of a single part.
+func parse3MF(data []byte) (*Mesh, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("not a valid 3MF file: %w", err)
+	}
+
+	mesh := &Mesh{}
+	for _, f := range zr.File {
+		if !strings.HasSuffix(strings.ToLower(f.Name), ".model") {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		err = readModelXML(rc, mesh)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("invalid 3MF model %s: %w", f.Name, err)
+		}
+	}
+	return mesh, nil
+}
+
+func readModelXML(r io.Reader, mesh *Mesh) error {
+	var vertices []Vec
+
+	dec := xml.NewDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		el, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch el.Name.Local {
+		case "object":
+			// Triangle indices are local to their object
+			vertices = vertices[:0]
+		case "vertex":
+			var v Vec
+			for axis, name := range []string{"x", "y", "z"} {
+				if v[axis], err = strconv.ParseFloat(attr(el, name), 64); err != nil {
+					return fmt.Errorf("invalid vertex coordinate %s", name)
+				}
+			}
+			vertices = append(vertices, v)
+		case "triangle":
+			var tri Triangle
+			for i, name := range []string{"v1", "v2", "v3"} {
+				idx, err := strconv.Atoi(attr(el, name))
+				if err != nil || idx < 0 || idx >= len(vertices) {
+					return fmt.Errorf("invalid triangle vertex %s", name)
+				}
+				tri[i] = vertices[idx]
+			}
+			mesh.Triangles = append(mesh.Triangles, tri)
+		}
+	}
+}
+
+func attr(el xml.StartElement, name string) string {
+	for _, a := range el.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,146 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package preview
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+// cube is a 20mm cube as twelve triangles
+func cube() []Triangle {
+	c := func(x, y, z float64) Vec { return Vec{x * 20, y * 20, z * 20} }
+	quads := [][4]Vec{
+		{c(0, 0, 0), c(1, 0, 0), c(1, 1, 0), c(0, 1, 0)},
+		{c(0, 0, 1), c(1, 0, 1), c(1, 1, 1), c(0, 1, 1)},
+		{c(0, 0, 0), c(1, 0, 0), c(1, 0, 1), c(0, 0, 1)},
+		{c(0, 1, 0), c(1, 1, 0), c(1, 1, 1), c(0, 1, 1)},
+		{c(0, 0, 0), c(0, 1, 0), c(0, 1, 1), c(0, 0, 1)},
+		{c(1, 0, 0), c(1, 1, 0), c(1, 1, 1), c(1, 0, 1)},
+	}
+	var tris []Triangle
+	for _, q := range quads {
+		tris = append(tris, Triangle{q[0], q[1], q[2]}, Triangle{q[0], q[2], q[3]})
+	}
+	return tris
+}
+
+func binarySTL(tris []Triangle) []byte {
+	var buf bytes.Buffer
+	buf.Write(make([]byte, 80))
+	binary.Write(&buf, binary.LittleEndian, uint32(len(tris)))
+	for _, tri := range tris {
+		binary.Write(&buf, binary.LittleEndian, [3]float32{})
+		for _, v := range tri {
+			binary.Write(&buf, binary.LittleEndian, [3]float32{float32(v[0]), float32(v[1]), float32(v[2])})
+		}
+		binary.Write(&buf, binary.LittleEndian, uint16(0))
+	}
+	return buf.Bytes()
+}
+
+func asciiSTL(tris []Triangle) []byte {
+	var b strings.Builder
+	b.WriteString("solid cube\n")
+	for _, tri := range tris {
+		b.WriteString("facet normal 0 0 0\nouter loop\n")
+		for _, v := range tri {
+			fmt.Fprintf(&b, "vertex %g %g %g\n", v[0], v[1], v[2])
+		}
+		b.WriteString("endloop\nendfacet\n")
+	}
+	b.WriteString("endsolid cube\n")
+	return []byte(b.String())
+}
+
+func threeMF(tris []Triangle) []byte {
+	var model strings.Builder
+	model.WriteString(`<?xml version="1.0" encoding="UTF-8"?><model unit="millimeter" xmlns="http://schemas.microsoft.com/3dmanufacturing/core/2015/02"><resources><object id="1" type="model"><mesh><vertices>`)
+	for _, tri := range tris {
+		for _, v := range tri {
+			fmt.Fprintf(&model, `<vertex x="%g" y="%g" z="%g"/>`, v[0], v[1], v[2])
+		}
+	}
+	model.WriteString(`</vertices><triangles>`)
+	for i := range tris {
+		fmt.Fprintf(&model, `<triangle v1="%d" v2="%d" v3="%d"/>`, i*3, i*3+1, i*3+2)
+	}
+	model.WriteString(`</triangles></mesh></object></resources><build><item objectid="1"/></build></model>`)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, _ := zw.Create("3D/3dmodel.model")
+	w.Write([]byte(model.String()))
+	zw.Close()
+	return buf.Bytes()
+}
+
+func TestParse(t *testing.T) {
+	want := cube()
+	for name, data := range map[string][]byte{
+		"cube.stl":       binarySTL(want),
+		"cube-ascii.STL": asciiSTL(want),
+		"cube.3mf":       threeMF(want),
+	} {
+		mesh, err := Parse(name, data)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if len(mesh.Triangles) != len(want) {
+			t.Errorf("%s: got %d triangles, want %d", name, len(mesh.Triangles), len(want))
+			continue
+		}
+		if got := mesh.Triangles[3][2]; math.Abs(got[2]-want[3][2][2]) > 1e-6 {
+			t.Errorf("%s: triangle 3 vertex 2 = %v, want %v", name, got, want[3][2])
+		}
+	}
+
+	if _, err := Parse("part.gcode", []byte("G28")); err != ErrUnsupported {
+		t.Errorf("gcode error = %v, want ErrUnsupported", err)
+	}
+	if _, err := Parse("broken.stl", []byte("not a model")); err == nil {
+		t.Error("parsed garbage as an STL")
+	}
+	if _, err := Parse("empty.stl", binarySTL(nil)); err == nil {
+		t.Error("parsed an STL with no triangles")
+	}
+}
+
+func TestRender(t *testing.T) {
+	img := Render(&Mesh{Triangles: cube()}, 64)
+
+	if a := img.NRGBAAt(32, 32).A; a != 0xff {
+		t.Errorf("centre pixel alpha = %d, want the part drawn there", a)
+	}
+	if a := img.NRGBAAt(0, 0).A; a != 0 {
+		t.Errorf("corner pixel alpha = %d, want transparent background", a)
+	}
+
+	// The top of the cube faces the light more squarely than its sides
+	top, side := img.NRGBAAt(32, 12), img.NRGBAAt(20, 44)
+	if top.A == 0 || side.A == 0 || top == side {
+		t.Errorf("top %v and side %v should be differently shaded faces", top, side)
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package preview
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+)
+
+// modelColor is the base shade of rendered parts, the dashboard's accent orange
+var modelColor = color.NRGBA{R: 0xff, G: 0x6b, B: 0x00, A: 0xff}
+
+// The camera looks down at the part from the front left, like a slicer's default view
+const (
+	yaw       = -math.Pi / 4
+	elevation = math.Pi / 6
+)
+
+// light points from the upper right of the view towards the part
+var light = normalize(Vec{0.4, 0.6, -1})
+
+// Render draws the mesh into a size×size image with a transparent background
+// using flat shading and a depth buffer
+func Render(m *Mesh, size int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, size, size))
+	if len(m.Triangles) == 0 || size <= 0 {
+		return img
+	}
+
+	// Project into view space: x right, y up, z away from the camera
+	view := make([]Triangle, len(m.Triangles))
+	min := Vec{math.Inf(1), math.Inf(1), math.Inf(1)}
+	max := Vec{math.Inf(-1), math.Inf(-1), math.Inf(-1)}
+	for i, tri := range m.Triangles {
+		for v, p := range tri {
+			q := project(p)
+			view[i][v] = q
+			for axis := 0; axis < 3; axis++ {
+				min[axis] = math.Min(min[axis], q[axis])
+				max[axis] = math.Max(max[axis], q[axis])
+			}
+		}
+	}
+
+	// Fit the part with a small margin, keeping its aspect ratio
+	extent := math.Max(max[0]-min[0], max[1]-min[1])
+	if extent == 0 {
+		extent = 1
+	}
+	scale := float64(size) * 0.9 / extent
+	cx := (min[0] + max[0]) / 2
+	cy := (min[1] + max[1]) / 2
+	half := float64(size) / 2
+
+	depth := make([]float64, size*size)
+	for i := range depth {
+		depth[i] = math.Inf(1)
+	}
+
+	for _, tri := range view {
+		var s [3]Vec
+		for v, p := range tri {
+			s[v] = Vec{half + (p[0]-cx)*scale, half - (p[1]-cy)*scale, p[2]}
+		}
+
+		n := normalize(cross(sub(tri[1], tri[0]), sub(tri[2], tri[0])))
+		// Winding is not reliable across exporters, so light both faces
+		shade := 0.3 + 0.7*math.Abs(dot(n, light))
+		c := color.NRGBA{
+			R: uint8(float64(modelColor.R) * shade),
+			G: uint8(float64(modelColor.G) * shade),
+			B: uint8(float64(modelColor.B) * shade),
+			A: 0xff,
+		}
+
+		fill(img, depth, s, c)
+	}
+
+	return img
+}
+
+// PNG renders the mesh and encodes it as a PNG
+func PNG(m *Mesh, size int) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, Render(m, size)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// project rotates a model-space point (Z up) into view space
+func project(p Vec) Vec {
+	x := p[0]*math.Cos(yaw) - p[1]*math.Sin(yaw)
+	y := p[0]*math.Sin(yaw) + p[1]*math.Cos(yaw)
+	z := p[2]
+	return Vec{
+		x,
+		y*math.Sin(elevation) + z*math.Cos(elevation),
+		y*math.Cos(elevation) - z*math.Sin(elevation),
+	}
+}
+
+// fill rasterizes one screen-space triangle, keeping the nearest surface per pixel
+func fill(img *image.NRGBA, depth []float64, s [3]Vec, c color.NRGBA) {
+	area := edge(s[0], s[1], s[2])
+	if area == 0 {
+		return
+	}
+
+	size := img.Rect.Dx()
+	x0 := clamp(math.Floor(math.Min(s[0][0], math.Min(s[1][0], s[2][0]))), size)
+	x1 := clamp(math.Ceil(math.Max(s[0][0], math.Max(s[1][0], s[2][0]))), size)
+	y0 := clamp(math.Floor(math.Min(s[0][1], math.Min(s[1][1], s[2][1]))), size)
+	y1 := clamp(math.Ceil(math.Max(s[0][1], math.Max(s[1][1], s[2][1]))), size)
+
+	for y := y0; y < y1; y++ {
+		for x := x0; x < x1; x++ {
+			p := Vec{float64(x) + 0.5, float64(y) + 0.5}
+			w0 := edge(s[1], s[2], p) / area
+			w1 := edge(s[2], s[0], p) / area
+			w2 := edge(s[0], s[1], p) / area
+			if w0 < 0 || w1 < 0 || w2 < 0 {
+				continue
+			}
+
+			z := w0*s[0][2] + w1*s[1][2] + w2*s[2][2]
+			if i := y*size + x; z < depth[i] {
+				depth[i] = z
+				img.SetNRGBA(x, y, c)
+			}
+		}
+	}
+}
+
+// edge is twice the signed area of abp; dividing by the triangle's own
+// value gives a barycentric weight whichever way it is wound
+func edge(a, b, p Vec) float64 {
+	return (b[0]-a[0])*(p[1]-a[1]) - (b[1]-a[1])*(p[0]-a[0])
+}
+
+func clamp(v float64, size int) int {
+	return int(math.Max(0, math.Min(v, float64(size))))
+}
+
+func sub(a, b Vec) Vec { return Vec{a[0] - b[0], a[1] - b[1], a[2] - b[2]} }
+
+func dot(a, b Vec) float64 { return a[0]*b[0] + a[1]*b[1] + a[2]*b[2] }
+
+func cross(a, b Vec) Vec {
+	return Vec{a[1]*b[2] - a[2]*b[1], a[2]*b[0] - a[0]*b[2], a[0]*b[1] - a[1]*b[0]}
+}
+
+func normalize(v Vec) Vec {
+	l := math.Sqrt(dot(v, v))
+	if l == 0 {
+		return v
+	}
+	return Vec{v[0] / l, v[1] / l, v[2] / l}
+}
@@ -0,0 +1,110 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package profiles stores the library of named print profiles
+package profiles
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/store"
+)
+
+const bucket = "profiles"
+
+// ErrNotFound is returned when a profile does not exist
+var ErrNotFound = errors.New("profile not found")
+
+// ErrInvalidName is returned for names that are not short slugs
+var ErrInvalidName = errors.New("profile names may only use letters, digits, '.', '-' and '_'")
+
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// ValidName reports whether a profile can be called name
+func ValidName(name string) bool {
+	return validName.MatchString(name)
+}
+
+// Library is the persistent set of print profiles, keyed by name
+type Library struct {
+	store *store.Store
+}
+
+// New creates a Library backed by the given store
+func New(s *store.Store) *Library {
+	return &Library{store: s}
+}
+
+// List returns every profile in name order
+func (l *Library) List() ([]models.PrintProfile, error) {
+	profiles, err := store.List[models.PrintProfile](l.store, bucket)
+	if profiles == nil {
+		profiles = []models.PrintProfile{}
+	}
+	return profiles, err
+}
+
+// Get returns a single profile
+func (l *Library) Get(name string) (models.PrintProfile, error) {
+	var profile models.PrintProfile
+	ok, err := l.store.Get(bucket, name, &profile)
+	if err != nil {
+		return profile, err
+	}
+	if !ok {
+		return profile, ErrNotFound
+	}
+	return profile, nil
+}
+
+// Put creates or replaces a profile, keeping its original creation time
+func (l *Library) Put(profile models.PrintProfile, at time.Time) (models.PrintProfile, error) {
+	if !ValidName(profile.Name) {
+		return profile, ErrInvalidName
+	}
+
+	// Tag keys match printer tags, which are lower case
+	tags := make(map[string]string, len(profile.Tags))
+	for key, value := range profile.Tags {
+		tags[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+	}
+	profile.Tags = tags
+	profile.Material = strings.TrimSpace(profile.Material)
+
+	at = at.UTC()
+	profile.CreatedAt, profile.UpdatedAt = at, at
+	if existing, err := l.Get(profile.Name); err == nil {
+		profile.CreatedAt = existing.CreatedAt
+	} else if !errors.Is(err, ErrNotFound) {
+		return profile, err
+	}
+
+	return profile, l.store.Put(bucket, profile.Name, profile)
+}
+
+// Delete removes a profile
+func (l *Library) Delete(name string) error {
+	if _, err := l.Get(name); err != nil {
+		return err
+	}
+	return l.store.Delete(bucket, name)
+}
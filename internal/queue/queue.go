@@ -0,0 +1,161 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queue
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/store"
+)
+
+const bucket = "queue"
+
+// ErrNotFound is returned when a queue entry does not exist
+var ErrNotFound = errors.New("queue entry not found")
+
+// Queue is the persistent print queue, run by priority and then in order
+type Queue struct {
+	store *store.Store
+}
+
+// New creates a Queue backed by the given store
+func New(s *store.Store) *Queue {
+	return &Queue{store: s}
+}
+
+// Add appends an entry to the end of the queue, as queued unless it says otherwise
+func (q *Queue) Add(entry models.QueueEntry) (models.QueueEntry, error) {
+	id, err := q.store.NextID(bucket)
+	if err != nil {
+		return entry, err
+	}
+
+	entry.ID = id
+	entry.Order, _ = strconv.ParseInt(id, 10, 64)
+	if entry.Status == "" {
+		entry.Status = models.QueueQueued
+	}
+	entry.CreatedAt = time.Now().UTC()
+
+	return entry, q.store.Put(bucket, id, entry)
+}
+
+// List returns every entry in queue order: by priority, then position
+func (q *Queue) List() ([]models.QueueEntry, error) {
+	entries, err := store.List[models.QueueEntry](q.store, bucket)
+	if entries == nil {
+		entries = []models.QueueEntry{}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := models.PriorityRank(entries[i].Priority), models.PriorityRank(entries[j].Priority)
+		if a != b {
+			return a < b
+		}
+		return order(entries[i]) < order(entries[j])
+	})
+	return entries, err
+}
+
+// order is an entry's position; entries queued before reordering existed use their ID
+func order(e models.QueueEntry) int64 {
+	if e.Order != 0 {
+		return e.Order
+	}
+	n, _ := strconv.ParseInt(e.ID, 10, 64)
+	return n
+}
+
+// Reorder moves the given entries into the order listed. They take over the
+// positions they held between them, so entries left out keep their places.
+func (q *Queue) Reorder(ids []string) error {
+	entries := make([]models.QueueEntry, len(ids))
+	positions := make([]int64, len(ids))
+	seen := make(map[string]bool)
+	for i, id := range ids {
+		if seen[id] {
+			return fmt.Errorf("entry %s is listed twice", id)
+		}
+		seen[id] = true
+
+		entry, err := q.Get(id)
+		if err != nil {
+			return err
+		}
+		entries[i] = entry
+		positions[i] = order(entry)
+	}
+	sort.Slice(positions, func(i, j int) bool { return positions[i] < positions[j] })
+
+	for i, entry := range entries {
+		entry.Order = positions[i]
+		if err := q.store.Put(bucket, entry.ID, entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get returns a single entry
+func (q *Queue) Get(id string) (models.QueueEntry, error) {
+	var entry models.QueueEntry
+	ok, err := q.store.Get(bucket, id, &entry)
+	if err != nil {
+		return entry, err
+	}
+	if !ok {
+		return entry, ErrNotFound
+	}
+	return entry, nil
+}
+
+// Update saves changes to an existing entry
+func (q *Queue) Update(entry models.QueueEntry) error {
+	if _, err := q.Get(entry.ID); err != nil {
+		return err
+	}
+	return q.store.Put(bucket, entry.ID, entry)
+}
+
+// Dispatch records that an entry was started on a printer
+func (q *Queue) Dispatch(id, printerID string, at time.Time) (models.QueueEntry, error) {
+	entry, err := q.Get(id)
+	if err != nil {
+		return entry, err
+	}
+
+	at = at.UTC()
+	entry.Status = models.QueueDispatched
+	entry.DispatchedTo = printerID
+	entry.DispatchedAt = &at
+
+	return entry, q.store.Put(bucket, id, entry)
+}
+
+// Remove deletes an entry from the queue
+func (q *Queue) Remove(id string) error {
+	if _, err := q.Get(id); err != nil {
+		return err
+	}
+	return q.store.Delete(bucket, id)
+}
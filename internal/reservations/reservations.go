@@ -0,0 +1,132 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package reservations stores printer bookings
+package reservations
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/store"
+)
+
+const bucket = "reservations"
+
+var (
+	// ErrNotFound is returned when a reservation does not exist
+	ErrNotFound = errors.New("reservation not found")
+	// ErrConflict is returned when a booking overlaps another on the same printer
+	ErrConflict = errors.New("printer is already reserved for part of that time")
+	// ErrInvalidSlot is returned for slots that end before they start or are already over
+	ErrInvalidSlot = errors.New("reservation must end after it starts and in the future")
+)
+
+// Book is the persistent set of reservations
+type Book struct {
+	store *store.Store
+	mu    sync.Mutex // makes the overlap check and write atomic
+}
+
+// New creates a Book backed by the given store
+func New(s *store.Store) *Book {
+	return &Book{store: s}
+}
+
+// Upcoming returns reservations that have not ended by at, earliest first
+func (b *Book) Upcoming(at time.Time) ([]models.Reservation, error) {
+	all, err := store.List[models.Reservation](b.store, bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	upcoming := []models.Reservation{}
+	for _, r := range all {
+		if r.End.After(at) {
+			upcoming = append(upcoming, r)
+		}
+	}
+	sort.SliceStable(upcoming, func(i, j int) bool { return upcoming[i].Start.Before(upcoming[j].Start) })
+	return upcoming, nil
+}
+
+// Get returns a single reservation
+func (b *Book) Get(id string) (models.Reservation, error) {
+	var r models.Reservation
+	ok, err := b.store.Get(bucket, id, &r)
+	if err != nil {
+		return r, err
+	}
+	if !ok {
+		return r, ErrNotFound
+	}
+	return r, nil
+}
+
+// Add books a slot unless it overlaps another reservation on the same printer
+func (b *Book) Add(r models.Reservation, now time.Time) (models.Reservation, error) {
+	r.Start, r.End = r.Start.UTC(), r.End.UTC()
+	if !r.End.After(r.Start) || !r.End.After(now) {
+		return r, ErrInvalidSlot
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	upcoming, err := b.Upcoming(now)
+	if err != nil {
+		return r, err
+	}
+	for _, other := range upcoming {
+		if other.PrinterID == r.PrinterID && r.Start.Before(other.End) && other.Start.Before(r.End) {
+			return r, ErrConflict
+		}
+	}
+
+	if r.ID, err = b.store.NextID(bucket); err != nil {
+		return r, err
+	}
+	r.CreatedAt = now.UTC()
+	return r, b.store.Put(bucket, r.ID, r)
+}
+
+// Cancel removes a reservation
+func (b *Book) Cancel(id string) error {
+	if _, err := b.Get(id); err != nil {
+		return err
+	}
+	return b.store.Delete(bucket, id)
+}
+
+// Holders maps each printer reserved at the given time to the user holding it
+func (b *Book) Holders(at time.Time) (map[string]string, error) {
+	upcoming, err := b.Upcoming(at)
+	if err != nil {
+		return nil, err
+	}
+	holders := make(map[string]string)
+	for _, r := range upcoming {
+		if r.Covers(at) {
+			holders[r.PrinterID] = r.User
+		}
+	}
+	return holders, nil
+}
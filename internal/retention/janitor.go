@@ -0,0 +1,254 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package retention prunes stored records by age and by size, so a dashboard
+// left running for years doesn't fill its SD card
+package retention
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/settings"
+	"github.com/wmarchesi123/octodash/internal/store"
+)
+
+// Dataset is a kind of stored record, held in one bucket or a family of them
+type Dataset struct {
+	Name string
+	// Bucket names the dataset's bucket; when Prefix is set, every bucket starting with it
+	Bucket string
+	Prefix string
+	// TimeField is the JSON field holding when a record was made
+	TimeField string
+	// Keep, when set, spares records that must not be pruned whatever their age
+	Keep func(value []byte) bool
+}
+
+// Janitor enforces retention policies in the background and counts what it prunes
+type Janitor struct {
+	store    *store.Store
+	datasets []Dataset
+	policies map[string]settings.RetentionPolicy
+	interval time.Duration
+	now      func() time.Time
+	logger   *log.Logger
+
+	// runMu keeps manual runs from overlapping scheduled ones
+	runMu sync.Mutex
+
+	mu    sync.Mutex
+	stats map[string]*models.RetentionStatus
+}
+
+var (
+	publishOnce sync.Once
+	published   atomic.Pointer[Janitor]
+)
+
+// New creates a janitor for the given datasets; call Run to start it
+func New(s *store.Store, datasets []Dataset, cfg settings.RetentionSettings, now func() time.Time, logger *log.Logger) *Janitor {
+	j := &Janitor{
+		store:    s,
+		datasets: datasets,
+		policies: cfg.Policies,
+		interval: cfg.Interval,
+		now:      now,
+		logger:   logger,
+		stats:    make(map[string]*models.RetentionStatus),
+	}
+	for _, d := range datasets {
+		p := cfg.Policies[d.Name]
+		st := &models.RetentionStatus{Dataset: d.Name, MaxBytes: p.MaxBytes}
+		if p.MaxAge > 0 {
+			st.MaxAge = p.MaxAge.String()
+		}
+		j.stats[d.Name] = st
+	}
+	return j
+}
+
+// Publish makes the janitor's status available as the "retention" expvar
+func (j *Janitor) Publish() {
+	published.Store(j)
+	publishOnce.Do(func() {
+		expvar.Publish("retention", expvar.Func(func() interface{} {
+			if j := published.Load(); j != nil {
+				return j.Status()
+			}
+			return nil
+		}))
+	})
+}
+
+// Run prunes every interval until ctx is cancelled, starting straight away
+func (j *Janitor) Run(ctx context.Context) {
+	if j.interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.Prune()
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Prune applies every policy once, measuring datasets without one
+func (j *Janitor) Prune() {
+	j.runMu.Lock()
+	defer j.runMu.Unlock()
+
+	now := j.now().UTC()
+	for _, d := range j.datasets {
+		records, bytes, pruned, prunedBytes, err := j.prune(d, now)
+
+		j.mu.Lock()
+		st := j.stats[d.Name]
+		st.Records, st.Bytes = records, bytes
+		st.PrunedRecords += int64(pruned)
+		st.PrunedBytes += prunedBytes
+		st.LastPruned = pruned
+		st.LastRun = &now
+		st.LastError = ""
+		if err != nil {
+			st.LastError = err.Error()
+		}
+		j.mu.Unlock()
+
+		if err != nil {
+			j.logger.Printf("Pruning %s failed: %v", d.Name, err)
+		} else if pruned > 0 {
+			j.logger.Printf("Pruned %d %s records (%d bytes)", pruned, d.Name, prunedBytes)
+		}
+	}
+}
+
+// Status reports every dataset, in the order they were given
+func (j *Janitor) Status() []models.RetentionStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	list := make([]models.RetentionStatus, 0, len(j.datasets))
+	for _, d := range j.datasets {
+		list = append(list, *j.stats[d.Name])
+	}
+	return list
+}
+
+// record is a stored record as the janitor sees it
+type record struct {
+	bucket string
+	key    string
+	size   int64
+	at     time.Time
+	keep   bool
+}
+
+// prune applies a dataset's policy, returning what remains and what was removed
+func (j *Janitor) prune(d Dataset, now time.Time) (records int, bytes int64, pruned int, prunedBytes int64, err error) {
+	buckets := []string{d.Bucket}
+	if d.Prefix != "" {
+		if buckets, err = j.store.Buckets(d.Prefix); err != nil {
+			return 0, 0, 0, 0, err
+		}
+	}
+
+	var all []record
+	for _, b := range buckets {
+		err := j.store.Scan(b, func(key string, value []byte) error {
+			r := record{bucket: b, key: key, size: int64(len(value)), at: recordTime(value, d.TimeField)}
+			if d.Keep != nil {
+				r.keep = d.Keep(value)
+			}
+			all = append(all, r)
+			bytes += r.size
+			return nil
+		})
+		if err != nil {
+			return 0, 0, 0, 0, fmt.Errorf("scan %s: %w", b, err)
+		}
+	}
+	records = len(all)
+
+	policy := j.policies[d.Name]
+	if policy == (settings.RetentionPolicy{}) {
+		return records, bytes, 0, 0, nil
+	}
+
+	// Age first, then the oldest of the rest until the dataset fits
+	drop := make(map[string][]string)
+	remove := func(r record) {
+		drop[r.bucket] = append(drop[r.bucket], r.key)
+		records--
+		bytes -= r.size
+		pruned++
+		prunedBytes += r.size
+	}
+	var kept []record
+	for _, r := range all {
+		if !r.keep && policy.MaxAge > 0 && !r.at.IsZero() && now.Sub(r.at) > policy.MaxAge {
+			remove(r)
+		} else {
+			kept = append(kept, r)
+		}
+	}
+	if policy.MaxBytes > 0 && bytes > policy.MaxBytes {
+		// Families of buckets, like the per-printer history, interleave by time
+		sort.SliceStable(kept, func(a, b int) bool { return kept[a].at.Before(kept[b].at) })
+		for _, r := range kept {
+			if bytes <= policy.MaxBytes {
+				break
+			}
+			if !r.keep {
+				remove(r)
+			}
+		}
+	}
+
+	for b, keys := range drop {
+		if err := j.store.DeleteKeys(b, keys); err != nil {
+			return records, bytes, pruned, prunedBytes, fmt.Errorf("delete from %s: %w", b, err)
+		}
+	}
+	return records, bytes, pruned, prunedBytes, nil
+}
+
+// recordTime reads a record's timestamp, zero when it has none
+func recordTime(value []byte, field string) time.Time {
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(value, &fields) != nil {
+		return time.Time{}
+	}
+	var t time.Time
+	json.Unmarshal(fields[field], &t)
+	return t
+}
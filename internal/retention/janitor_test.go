@@ -0,0 +1,110 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retention
+
+import (
+	"io"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/settings"
+	"github.com/wmarchesi123/octodash/internal/store"
+)
+
+type entry struct {
+	Time   time.Time `json:"time"`
+	Status string    `json:"status,omitempty"`
+	Pad    string    `json:"pad"`
+}
+
+func TestJanitor(t *testing.T) {
+	s, err := store.Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	put := func(bucket string, age time.Duration, status string) {
+		t.Helper()
+		id, err := s.NextID(bucket)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Put(bucket, id, entry{Time: now.Add(-age), Status: status, Pad: "0123456789"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Two printers' history, interleaved in time
+	put("events:printer-1", 50*time.Hour, "")
+	put("events:printer-1", 10*time.Hour, "")
+	put("events:printer-1", 1*time.Hour, "")
+	put("events:printer-2", 30*time.Hour, "")
+	put("events:printer-2", 5*time.Hour, "")
+	put("deliveries", 72*time.Hour, "pending")
+	put("deliveries", 72*time.Hour, "delivered")
+	put("audit", 72*time.Hour, "")
+
+	if buckets, _ := s.Buckets("events:"); len(buckets) != 2 {
+		t.Fatalf("history buckets = %v", buckets)
+	}
+	recordSize := func() int64 {
+		var n int64
+		s.Scan("audit", func(_ string, v []byte) error { n = int64(len(v)); return nil })
+		return n
+	}()
+
+	j := New(s, []Dataset{
+		{Name: models.DataHistory, Prefix: "events:", TimeField: "time"},
+		{Name: models.DataDeliveries, Bucket: "deliveries", TimeField: "time", Keep: func(v []byte) bool { return strings.Contains(string(v), `"status":"pending"`) }},
+		{Name: models.DataAudit, Bucket: "audit", TimeField: "time"},
+	}, settings.RetentionSettings{Policies: map[string]settings.RetentionPolicy{
+		// Drops the 50h record by age, then the 30h and 10h ones to fit in two and a half
+		models.DataHistory:    {MaxAge: 48 * time.Hour, MaxBytes: 2*recordSize + recordSize/2},
+		models.DataDeliveries: {MaxAge: 24 * time.Hour},
+	}}, func() time.Time { return now }, log.New(io.Discard, "", 0))
+	j.Prune()
+
+	history, _ := store.List[entry](s, "events:printer-1")
+	other, _ := store.List[entry](s, "events:printer-2")
+	if len(history) != 1 || len(other) != 1 || history[0].Time != now.Add(-time.Hour) {
+		t.Errorf("history left %+v and %+v, want the newest of each", history, other)
+	}
+	if deliveries, _ := store.List[entry](s, "deliveries"); len(deliveries) != 1 || deliveries[0].Status != "pending" {
+		t.Errorf("deliveries = %+v, want only the pending one", deliveries)
+	}
+
+	status := j.Status()
+	if st := status[0]; st.Records != 2 || st.PrunedRecords != 3 || st.LastPruned != 3 || st.PrunedBytes != 3*recordSize || st.MaxAge != "48h0m0s" {
+		t.Errorf("history status = %+v", st)
+	}
+	// Datasets without a policy are only measured
+	if st := status[2]; st.Records != 1 || st.Bytes != recordSize || st.PrunedRecords != 0 || st.LastRun == nil {
+		t.Errorf("audit status = %+v", st)
+	}
+
+	j.Prune()
+	if st := j.Status()[0]; st.PrunedRecords != 3 || st.LastPruned != 0 {
+		t.Errorf("second run status = %+v, want nothing more pruned", st)
+	}
+}
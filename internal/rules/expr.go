@@ -0,0 +1,355 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rules
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Expr is a compiled rule condition such as
+//
+//	bed > 100 and status == "idle"
+//
+// It supports numbers, quoted strings, true and false, the status variables
+// listed in Vars, comparisons (== != < <= > >=), and/&&, or/||, not/! and
+// parentheses. Bare words on the right of a comparison that are not variables
+// are read as strings, so status == idle also works.
+type Expr struct {
+	src  string
+	eval evalFunc
+}
+
+type evalFunc func(vars map[string]interface{}) (interface{}, error)
+
+// Compile parses a condition
+func Compile(src string) (*Expr, error) {
+	toks, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	eval, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.toks) {
+		return nil, fmt.Errorf("unexpected %q", p.toks[p.pos].text)
+	}
+	return &Expr{src: src, eval: eval}, nil
+}
+
+// String returns the condition as written
+func (e *Expr) String() string {
+	return e.src
+}
+
+// Eval reports whether the condition holds for the given variables
+func (e *Expr) Eval(vars map[string]interface{}) (bool, error) {
+	v, err := e.eval(vars)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("condition is %v, not true or false", v)
+	}
+	return b, nil
+}
+
+type tokenKind int
+
+const (
+	tokNumber tokenKind = iota
+	tokString
+	tokIdent
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	num  float64
+}
+
+func tokenize(src string) ([]token, error) {
+	var toks []token
+	for i := 0; i < len(src); {
+		c := rune(src[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(src[i+1:], src[i])
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string at %d", i)
+			}
+			toks = append(toks, token{kind: tokString, text: src[i+1 : i+1+end]})
+			i += end + 2
+		case unicode.IsDigit(c) || (c == '.' && i+1 < len(src) && unicode.IsDigit(rune(src[i+1]))):
+			j := i
+			for j < len(src) && (unicode.IsDigit(rune(src[j])) || src[j] == '.') {
+				j++
+			}
+			n, err := strconv.ParseFloat(src[i:j], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q", src[i:j])
+			}
+			toks = append(toks, token{kind: tokNumber, text: src[i:j], num: n})
+			i = j
+		case unicode.IsLetter(c) || c == '_':
+			j := i
+			for j < len(src) && (unicode.IsLetter(rune(src[j])) || unicode.IsDigit(rune(src[j])) || strings.ContainsRune("_.-", rune(src[j]))) {
+				j++
+			}
+			toks = append(toks, token{kind: tokIdent, text: src[i:j]})
+			i = j
+		default:
+			op := ""
+			for _, candidate := range []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!", "(", ")"} {
+				if strings.HasPrefix(src[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected %q at %d", c, i)
+			}
+			toks = append(toks, token{kind: tokOp, text: op})
+			i += len(op)
+		}
+	}
+	return toks, nil
+}
+
+type parser struct {
+	toks []token
+	pos  int
+}
+
+// accept consumes the next token if it is one of the given operators or keywords
+func (p *parser) accept(texts ...string) (string, bool) {
+	if p.pos >= len(p.toks) {
+		return "", false
+	}
+	t := p.toks[p.pos]
+	if t.kind != tokOp && t.kind != tokIdent {
+		return "", false
+	}
+	for _, text := range texts {
+		if t.text == text {
+			p.pos++
+			return text, true
+		}
+	}
+	return "", false
+}
+
+func (p *parser) or() (evalFunc, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("||", "or"); !ok {
+			return left, nil
+		}
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = logical(left, right, true)
+	}
+}
+
+func (p *parser) and() (evalFunc, error) {
+	left, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("&&", "and"); !ok {
+			return left, nil
+		}
+		right, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		left = logical(left, right, false)
+	}
+}
+
+// logical joins two conditions, skipping the right one once the left decides
+func logical(left, right evalFunc, or bool) evalFunc {
+	return func(vars map[string]interface{}) (interface{}, error) {
+		l, err := truth(left, vars)
+		if err != nil || l == or {
+			return l, err
+		}
+		return truth(right, vars)
+	}
+}
+
+func truth(f evalFunc, vars map[string]interface{}) (bool, error) {
+	v, err := f(vars)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("%v is not true or false", v)
+	}
+	return b, nil
+}
+
+func (p *parser) not() (evalFunc, error) {
+	if _, ok := p.accept("!", "not"); ok {
+		inner, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return func(vars map[string]interface{}) (interface{}, error) {
+			b, err := truth(inner, vars)
+			return !b, err
+		}, nil
+	}
+	return p.comparison()
+}
+
+func (p *parser) comparison() (evalFunc, error) {
+	left, err := p.operand(false)
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.accept("==", "!=", "<", "<=", ">", ">=")
+	if !ok {
+		return left, nil
+	}
+	right, err := p.operand(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(vars map[string]interface{}) (interface{}, error) {
+		l, err := left(vars)
+		if err != nil {
+			return nil, err
+		}
+		r, err := right(vars)
+		if err != nil {
+			return nil, err
+		}
+		return compare(l, op, r)
+	}, nil
+}
+
+// operand reads a literal, variable or parenthesized condition; bare words
+// that are not variables are strings when allowed
+func (p *parser) operand(bareStrings bool) (evalFunc, error) {
+	if p.pos >= len(p.toks) {
+		return nil, fmt.Errorf("condition ends too early")
+	}
+	t := p.toks[p.pos]
+	p.pos++
+
+	switch t.kind {
+	case tokNumber:
+		return constant(t.num), nil
+	case tokString:
+		return constant(t.text), nil
+	case tokIdent:
+		switch {
+		case t.text == "true" || t.text == "false":
+			return constant(t.text == "true"), nil
+		case strings.HasPrefix(t.text, "tags."):
+			key := strings.TrimPrefix(t.text, "tags.")
+			return func(vars map[string]interface{}) (interface{}, error) {
+				tags, _ := vars["tags"].(map[string]string)
+				return tags[key], nil
+			}, nil
+		case known(t.text):
+			name := t.text
+			return func(vars map[string]interface{}) (interface{}, error) { return vars[name], nil }, nil
+		case bareStrings:
+			return constant(t.text), nil
+		}
+		return nil, fmt.Errorf("unknown variable %q", t.text)
+	}
+
+	if t.text == "(" {
+		inner, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := p.accept(")"); !ok {
+			return nil, fmt.Errorf("missing )")
+		}
+		return inner, nil
+	}
+	return nil, fmt.Errorf("unexpected %q", t.text)
+}
+
+func constant(v interface{}) evalFunc {
+	return func(map[string]interface{}) (interface{}, error) { return v, nil }
+}
+
+func compare(l interface{}, op string, r interface{}) (interface{}, error) {
+	switch lv := l.(type) {
+	case float64:
+		rv, ok := r.(float64)
+		if !ok {
+			return nil, fmt.Errorf("cannot compare %v with %v", l, r)
+		}
+		switch op {
+		case "==":
+			return lv == rv, nil
+		case "!=":
+			return lv != rv, nil
+		case "<":
+			return lv < rv, nil
+		case "<=":
+			return lv <= rv, nil
+		case ">":
+			return lv > rv, nil
+		default:
+			return lv >= rv, nil
+		}
+	case string:
+		rv, ok := r.(string)
+		if !ok {
+			return nil, fmt.Errorf("cannot compare %q with %v", lv, r)
+		}
+		switch op {
+		case "==":
+			return strings.EqualFold(lv, rv), nil
+		case "!=":
+			return !strings.EqualFold(lv, rv), nil
+		}
+		return nil, fmt.Errorf("cannot order strings with %s", op)
+	case bool:
+		rv, ok := r.(bool)
+		if !ok || (op != "==" && op != "!=") {
+			return nil, fmt.Errorf("cannot compare %v %s %v", l, op, r)
+		}
+		return (lv == rv) == (op == "=="), nil
+	}
+	return nil, fmt.Errorf("cannot compare %v", l)
+}
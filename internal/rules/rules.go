@@ -0,0 +1,150 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rules runs user-written automation rules against printer status:
+// when a condition has held for long enough, send gcode and raise an alert
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/settings"
+)
+
+// vars lists the status variables conditions can use, besides tags.<name>
+var vars = []string{
+	"printer", "name", "status", "state", "error", "file", "completion",
+	"bed", "bed_target", "hotend", "hotend_target", "watts",
+}
+
+func known(name string) bool {
+	return slices.Contains(vars, name)
+}
+
+// Vars returns the values conditions see for a printer. Temperatures,
+// progress and power read as zero when the printer doesn't report them.
+func Vars(p *models.PrinterStatus) map[string]interface{} {
+	v := map[string]interface{}{
+		"printer": p.ID,
+		"name":    p.Name,
+		"status":  p.Status,
+		"state":   p.State,
+		"error":   p.Error,
+		"tags":    p.Tags,
+
+		"file":          "",
+		"completion":    0.0,
+		"bed":           0.0,
+		"bed_target":    0.0,
+		"hotend":        0.0,
+		"hotend_target": 0.0,
+		"watts":         0.0,
+	}
+	if p.Progress != nil {
+		v["file"], v["completion"] = p.Progress.FileName, p.Progress.Completion
+	}
+	if t := p.Temperatures; t != nil {
+		v["bed"], v["bed_target"] = t.BedActual, t.BedTarget
+		v["hotend"], v["hotend_target"] = t.HotendActual, t.HotendTarget
+	}
+	if p.Power != nil {
+		v["watts"] = p.Power.Watts
+	}
+	return v
+}
+
+// Rule is a configured rule with its condition compiled
+type Rule struct {
+	settings.RuleSettings
+	When *Expr
+}
+
+// Engine tracks how long each rule's condition has held on each printer
+type Engine struct {
+	rules []Rule
+
+	mu    sync.Mutex
+	since map[string]time.Time // rule/printer -> when the condition became true
+	fired map[string]bool      // rule/printer -> fired since the condition became true
+}
+
+// New compiles the configured rules
+func New(cfg []settings.RuleSettings) (*Engine, error) {
+	e := &Engine{since: make(map[string]time.Time), fired: make(map[string]bool)}
+	for _, rs := range cfg {
+		when, err := Compile(rs.When)
+		if err == nil {
+			// Catch comparisons between mismatched types before the first poll
+			_, err = when.Eval(Vars(&models.PrinterStatus{}))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s condition %q: %w", rs.ID, rs.When, err)
+		}
+		e.rules = append(e.rules, Rule{RuleSettings: rs, When: when})
+	}
+	return e, nil
+}
+
+// Rules returns the compiled rules
+func (e *Engine) Rules() []Rule {
+	return e.rules
+}
+
+// Observe evaluates every rule that applies to a printer and returns those
+// firing now: their condition has held for the rule's For and they haven't
+// fired since it last became true
+func (e *Engine) Observe(p *models.PrinterStatus, now time.Time) ([]Rule, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	v := Vars(p)
+	var firing []Rule
+	var errs []error
+	for _, r := range e.rules {
+		if len(r.Printers) > 0 && !slices.Contains(r.Printers, p.ID) {
+			continue
+		}
+		key := r.ID + "/" + p.ID
+
+		holds, err := r.When.Eval(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s on %s: %w", r.ID, p.ID, err))
+		}
+		if !holds {
+			delete(e.since, key)
+			delete(e.fired, key)
+			continue
+		}
+
+		since, ok := e.since[key]
+		if !ok {
+			since = now
+			e.since[key] = now
+		}
+		if !e.fired[key] && now.Sub(since) >= r.For {
+			e.fired[key] = true
+			firing = append(firing, r)
+		}
+	}
+	return firing, errors.Join(errs...)
+}
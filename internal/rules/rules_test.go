@@ -0,0 +1,97 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rules
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/settings"
+)
+
+func TestExpr(t *testing.T) {
+	p := &models.PrinterStatus{
+		ID:           "printer-1",
+		Status:       "idle",
+		Tags:         map[string]string{"room": "lab"},
+		Temperatures: &models.TemperatureInfo{BedActual: 105, BedTarget: 110},
+	}
+
+	for src, want := range map[string]bool{
+		`bed > 100 and status == "idle"`:          true,
+		`bed > 100 && status == idle`:             true,
+		`bed >= 110 || status == 'printing'`:      false,
+		`not (bed < 100) and tags.room == lab`:    true,
+		`tags.shelf == ""`:                        true,
+		`printer != printer-1 or bed_target == 0`: false,
+		`!(completion > 0)`:                       true,
+	} {
+		expr, err := Compile(src)
+		if err != nil {
+			t.Errorf("Compile(%s): %v", src, err)
+			continue
+		}
+		if got, err := expr.Eval(Vars(p)); err != nil || got != want {
+			t.Errorf("%s = %v, %v; want %v", src, got, err, want)
+		}
+	}
+
+	for _, src := range []string{`bed >`, `bed > 100 and`, `(bed > 1`, `nozzle > 200`, `status == "idle`, `bed $ 4`} {
+		if _, err := Compile(src); err == nil {
+			t.Errorf("Compile(%s) succeeded, want an error", src)
+		}
+	}
+	if _, err := New([]settings.RuleSettings{{ID: "rule-1", When: `bed > "hot"`}}); err == nil {
+		t.Error("New accepted a comparison between a number and a string")
+	}
+}
+
+func TestEngineHolds(t *testing.T) {
+	e, err := New([]settings.RuleSettings{{ID: "rule-1", When: "status == idle", For: 10 * time.Minute}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	idle := &models.PrinterStatus{ID: "printer-1", Status: "idle"}
+	start := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	fires := func(p *models.PrinterStatus, at time.Duration) int {
+		t.Helper()
+		firing, err := e.Observe(p, start.Add(at))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(firing)
+	}
+
+	if fires(idle, 0) != 0 || fires(idle, 9*time.Minute) != 0 {
+		t.Error("rule fired before its condition held for 10m")
+	}
+	if fires(idle, 10*time.Minute) != 1 {
+		t.Error("rule did not fire after 10m")
+	}
+	if fires(idle, 30*time.Minute) != 0 {
+		t.Error("rule fired twice while its condition kept holding")
+	}
+
+	fires(&models.PrinterStatus{ID: "printer-1", Status: "printing"}, 31*time.Minute)
+	if fires(idle, 32*time.Minute) != 0 || fires(idle, 42*time.Minute) != 1 {
+		t.Error("rule did not re-arm after its condition stopped holding")
+	}
+}
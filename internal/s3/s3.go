@@ -0,0 +1,262 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package s3 is a small client for S3-compatible object storage (AWS, MinIO,
+// Backblaze B2, R2, ...) using path-style requests signed with AWS Signature
+// Version 4
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// unsignedPayload stands in for the body hash of streamed uploads
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// Client reads and writes objects under Prefix in one bucket
+type Client struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	Prefix    string
+	AccessKey string
+	SecretKey string
+
+	HTTPClient *http.Client
+}
+
+// Object is a stored object, keyed relative to the client's Prefix
+type Object struct {
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// Name describes the bucket in logs
+func (c *Client) Name() string {
+	return "s3://" + c.Bucket + "/" + c.Prefix
+}
+
+// Put uploads data as Prefix+key
+func (c *Client) Put(ctx context.Context, key, contentType string, data []byte) error {
+	req, err := c.newRequest(ctx, "PUT", key, nil, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	c.sign(req, sha256Hex(data), time.Now().UTC())
+
+	return c.do(req, nil)
+}
+
+// PutStream uploads size bytes from r as Prefix+key without holding them in
+// memory. The body is not hashed, so use an https endpoint.
+func (c *Client) PutStream(ctx context.Context, key, contentType string, r io.Reader, size int64) error {
+	req, err := c.newRequest(ctx, "PUT", key, nil, io.NopCloser(r))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+	c.sign(req, unsignedPayload, time.Now().UTC())
+
+	return c.do(req, nil)
+}
+
+// List returns every object whose key starts with Prefix+prefix
+func (c *Client) List(ctx context.Context, prefix string) ([]Object, error) {
+	base := strings.TrimPrefix(c.Prefix, "/")
+	var objects []Object
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {base + prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		req, err := c.newRequest(ctx, "GET", "", query, nil)
+		if err != nil {
+			return nil, err
+		}
+		c.sign(req, sha256Hex(nil), time.Now().UTC())
+
+		var result struct {
+			Contents []struct {
+				Key          string
+				Size         int64
+				LastModified time.Time
+			}
+			IsTruncated           bool
+			NextContinuationToken string
+		}
+		if err := c.do(req, &result); err != nil {
+			return nil, err
+		}
+		for _, o := range result.Contents {
+			objects = append(objects, Object{
+				Key:          strings.TrimPrefix(o.Key, base),
+				Size:         o.Size,
+				LastModified: o.LastModified,
+			})
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return objects, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// Delete removes Prefix+key; deleting a missing object succeeds
+func (c *Client) Delete(ctx context.Context, key string) error {
+	req, err := c.newRequest(ctx, "DELETE", key, nil, nil)
+	if err != nil {
+		return err
+	}
+	c.sign(req, sha256Hex(nil), time.Now().UTC())
+
+	return c.do(req, nil)
+}
+
+// newRequest builds a request for Prefix+key, or for the bucket itself when key is empty
+func (c *Client) newRequest(ctx context.Context, method, key string, query url.Values, body io.Reader) (*http.Request, error) {
+	endpoint, err := url.Parse(strings.TrimSuffix(c.Endpoint, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid S3 endpoint: %w", err)
+	}
+
+	u := *endpoint
+	u.Path = "/" + c.Bucket
+	if key != "" {
+		u.Path += "/" + strings.TrimPrefix(c.Prefix+key, "/")
+	}
+	u.RawPath = uriEncode(u.Path)
+	// SigV4 wants spaces as %20, which QueryEscape writes as +
+	u.RawQuery = strings.ReplaceAll(query.Encode(), "+", "%20")
+
+	return http.NewRequestWithContext(ctx, method, u.String(), body)
+}
+
+func (c *Client) do(req *http.Request, result interface{}) error {
+	client := c.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Minute}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if result != nil {
+		return xml.NewDecoder(resp.Body).Decode(result)
+	}
+	return nil
+}
+
+// sign adds SigV4 headers for an unchunked payload with the given hash
+func (c *Client) sign(req *http.Request, payloadHash string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("Host", req.URL.Host)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{
+		"host":                 req.URL.Host,
+		"x-amz-content-sha256": payloadHash,
+		"x-amz-date":           amzDate,
+	}
+	if ct := req.Header.Get("Content-Type"); ct != "" {
+		headers["content-type"] = ct
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + c.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+c.SecretKey), date)
+	key = hmacSHA256(key, c.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		c.AccessKey, scope, signedHeaders, signature))
+}
+
+// uriEncode escapes everything but unreserved characters and slashes, as SigV4 expects
+func uriEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case 'A' <= ch && ch <= 'Z', 'a' <= ch && ch <= 'z', '0' <= ch && ch <= '9',
+			ch == '-', ch == '_', ch == '.', ch == '~', ch == '/':
+			b.WriteByte(ch)
+		default:
+			fmt.Fprintf(&b, "%%%02X", ch)
+		}
+	}
+	return b.String()
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
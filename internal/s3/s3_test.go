@@ -0,0 +1,79 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func TestClient(t *testing.T) {
+	server := testutil.NewS3(t)
+	server.PageSize = 2
+	c := &Client{Endpoint: server.URL, Region: "us-east-1", Bucket: "farm", Prefix: "octodash/", AccessKey: "key", SecretKey: "secret"}
+	ctx := context.Background()
+
+	for _, key := range []string{"clips/a b.jpg", "clips/c.jpg", "clips/d.jpg", "snapshots/e.jpg"} {
+		if err := c.Put(ctx, key, "image/jpeg", []byte(key)); err != nil {
+			t.Fatalf("Put(%q): %v", key, err)
+		}
+	}
+	if o, ok := server.Object("farm/octodash/clips/a b.jpg"); !ok || string(o.Data) != "clips/a b.jpg" || o.ContentType != "image/jpeg" {
+		t.Errorf("stored object = %+v, %v", o, ok)
+	}
+
+	// Three clips over two pages
+	objects, err := c.List(ctx, "clips/")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	var keys []string
+	for _, o := range objects {
+		keys = append(keys, o.Key)
+	}
+	if want := []string{"clips/a b.jpg", "clips/c.jpg", "clips/d.jpg"}; !slices.Equal(keys, want) {
+		t.Errorf("List keys = %v, want %v", keys, want)
+	}
+	if objects[0].Size != int64(len("clips/a b.jpg")) || objects[0].LastModified.IsZero() {
+		t.Errorf("object = %+v, want size and modification time", objects[0])
+	}
+
+	if err := c.Delete(ctx, "clips/a b.jpg"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, ok := server.Object("farm/octodash/clips/a b.jpg"); ok {
+		t.Error("object still stored after Delete")
+	}
+
+	bad := *c
+	bad.Endpoint = server.URL + "/nowhere%zz"
+	if err := bad.Put(ctx, "x", "text/plain", nil); err == nil {
+		t.Error("Put with an invalid endpoint succeeded")
+	}
+}
+
+func TestURIEncode(t *testing.T) {
+	got := uriEncode("/farm/clips/printer-1/gear (v2)+final.jpg")
+	if want := "/farm/clips/printer-1/gear%20%28v2%29%2Bfinal.jpg"; got != want {
+		t.Errorf("uriEncode = %q, want %q", got, want)
+	}
+}
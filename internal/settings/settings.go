@@ -0,0 +1,1882 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package settings
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+)
+
+// maxPrinters mirrors the printer slots read by config.LoadConfig
+const maxPrinters = 10
+
+// maxSpoolmen is the highest SPOOLMAN_N_ slot read; SPOOLMAN_URL is instance 1
+const maxSpoolmen = 5
+
+// maxMacros is the highest MACRO_N_ slot read
+const maxMacros = 20
+
+// maxWebhooks is the highest WEBHOOK_N_ slot read
+const maxWebhooks = 10
+
+// maxRules is the highest RULE_N_ slot read
+const maxRules = 20
+
+// maxLights is the highest LIGHT_N_ slot read
+const maxLights = 10
+
+// defaultLightMapping shows problems first, then work in progress
+const defaultLightMapping = "error=ff0000:pulse,attention=ff8000:pulse,printing=0000ff,idle=00ff00,offline=off"
+
+// Settings holds OctoDash options that live outside the shared printer config.
+// PublicURL is the address phones and other devices reach the dashboard at,
+// used in links such as spool label QR codes; empty uses the request's host.
+// MaterialColors maps upper-case material names to the accent color shown for them.
+type Settings struct {
+	DataDir     string
+	LogRequests bool
+	LocalAssets bool
+	PublicURL   string
+	Printers    map[string]PrinterSettings
+	Preheat     PreheatSettings
+	Units       models.Units
+	Timezone    *time.Location
+	Idle        IdleSettings
+	Energy      EnergySettings
+	Auth        AuthSettings
+	Upstream    UpstreamSettings
+	Demo        DemoSettings
+	Events      EventSettings
+	Queue       QueueSettings
+	Backup      BackupSettings
+
+	OctoPrintBackup OctoPrintBackupSettings
+	Updates         UpdateSettings
+	Profiling       ProfilingSettings
+	Poll            PollSettings
+	Tailscale       TailscaleSettings
+	Spoolman        SpoolmanSettings
+	Forecast        ForecastSettings
+	Drying          DryingSettings
+	MaterialColors  map[string]string
+	Slicer          SlicerSettings
+	Teams           TeamSettings
+	Quota           QuotaSettings
+	Macros          []MacroSettings
+	Webhooks        WebhookSettings
+	EventBus        EventBusSettings
+	Extensions      ExtensionSettings
+	Rules           []RuleSettings
+	HomeAssistant   HomeAssistantSettings
+	Triggers        TriggerSettings
+	Eink            EinkSettings
+	Lights          []LightSettings
+	Announce        AnnounceSettings
+	CameraArchive   CameraArchiveSettings
+	Retention       RetentionSettings
+	Database        DatabaseSettings
+	Security        SecuritySettings
+	GRPC            GRPCSettings
+	Thermal         ThermalSettings
+	FirstLayer      FirstLayerSettings
+	Stall           StallSettings
+	Proxy           ProxySettings
+	SSO             SSOSettings
+	Handover        HandoverSettings
+	PartLabels      PartLabelSettings
+}
+
+// PrinterSettings holds per-printer options, keyed by printer ID in Settings
+// AutoClear marks printers that clear their own bed (belt printers, auto-eject),
+// which the queue can start again without anyone confirming the part was removed.
+// Spoolman names the Spoolman instance holding the printer's spools; empty uses the first.
+// Continuous printers clear their own bed too, but only after waiting Cooldown
+// and running EjectGcode once a print stops; the queue then starts the next job.
+// Checklist lists checks an operator must tick off before each queued job starts.
+// Operators names API users allowed to control the printer even though their
+// role is below operator.
+// SnapshotURL is where the printer's camera serves a JPEG; empty uses OctoPi's
+// /webcam/?action=snapshot next to OctoPrint. StreamURL serves MJPEG likewise,
+// defaulting to /webcam/?action=stream.
+type PrinterSettings struct {
+	Group      string
+	Tags       map[string]string
+	AutoClear  bool
+	Spoolman   string
+	Continuous bool
+	Cooldown   time.Duration
+	EjectGcode []string
+	Checklist  []string
+	Operators  []string
+
+	SnapshotURL string
+	StreamURL   string
+}
+
+// IdleSettings configures the idle screen shown when no printer needs attention
+type IdleSettings struct {
+	After time.Duration
+	Mode  string
+}
+
+// PreheatSettings holds the default targets used by bulk preheat
+type PreheatSettings struct {
+	Hotend float64
+	Bed    float64
+}
+
+// EnergySettings configures smart plug power monitoring
+type EnergySettings struct {
+	PollInterval time.Duration
+	PricePerKWh  float64
+	Currency     string
+	Plugs        map[string]PlugSettings
+}
+
+// PlugSettings describes the smart plug a printer is powered from
+type PlugSettings struct {
+	Type    string
+	Address string
+}
+
+// AuthSettings configures API users and their roles
+type AuthSettings struct {
+	Users []UserSettings
+}
+
+// UpstreamSettings tunes the connection pool shared by OctoPrint, Spoolman and plug clients.
+// Proxy, when set, carries every upstream request; PrinterProxies overrides it
+// per printer ID, with a nil entry meaning connect directly. PrinterTimeouts
+// replaces the connect and request timeouts for printers on slow links.
+type UpstreamSettings struct {
+	MaxIdleConnsPerHost int
+	MaxConnsPerHost     int
+	IdleConnTimeout     time.Duration
+	DNSCacheTTL         time.Duration
+	HostOverrides       map[string]string
+	Proxy               *url.URL
+	PrinterProxies      map[string]*url.URL
+	PrinterTimeouts     map[string]time.Duration
+}
+
+// TailscaleSettings joins OctoDash to a tailnet as its own node, serving the
+// dashboard there and reaching printers by their tailnet addresses
+type TailscaleSettings struct {
+	Enabled  bool
+	Hostname string
+	AuthKey  string
+	StateDir string
+}
+
+// SpoolmanSettings describes the Spoolman at SPOOLMAN_URL, known by Name, and
+// any further Instances for sites that keep separate inventories. Printers use
+// the first unless their PrinterSettings.Spoolman names another.
+type SpoolmanSettings struct {
+	Name string
+	SpoolmanCredentials
+	Instances []SpoolmanInstance
+}
+
+// SpoolmanCredentials authenticate to a Spoolman behind a reverse proxy. Token
+// is sent as a bearer token; Username and Password as basic auth.
+type SpoolmanCredentials struct {
+	Token    string
+	Username string
+	Password string
+}
+
+// SpoolmanInstance is an additional Spoolman server
+type SpoolmanInstance struct {
+	Name string
+	URL  string
+	SpoolmanCredentials
+}
+
+// ForecastSettings tunes filament run-out forecasts. Spools idle for longer
+// than Window don't count towards usage; materials running out within
+// ReorderWithin go on the reorder list.
+type ForecastSettings struct {
+	Window        time.Duration
+	ReorderWithin time.Duration
+}
+
+// DryingSettings lists the hygroscopic materials whose spool cards show when
+// they were last dried. Entries match material names by prefix, so PA covers PA-CF.
+type DryingSettings struct {
+	Materials []string
+}
+
+// SlicerSettings configures the external slicer that turns uploaded models into
+// gcode. Command is split on spaces and run without a shell, with {input},
+// {output}, {profile} and {dir} in its arguments filled in for each job.
+// Profiles are the .ini files in ProfileDir, named without the extension.
+type SlicerSettings struct {
+	Command    []string
+	ProfileDir string
+	Timeout    time.Duration
+}
+
+// TeamSettings turns on multi-tenant mode, where printers and queue entries
+// belong to teams and non-admin users only see their own teams' machines
+type TeamSettings struct {
+	Enabled bool
+}
+
+// QuotaSettings caps how much each API user can print per calendar month.
+// Zero leaves a limit off; exempt users are never limited.
+type QuotaSettings struct {
+	MonthlyHours    float64
+	MonthlyFilament float64 // meters
+	Exempt          []string
+}
+
+// DemoSettings configures the simulated printer farm used instead of real hardware
+type DemoSettings struct {
+	Enabled  bool
+	Printers int
+	Speed    float64
+}
+
+// EventSettings configures the status transition log
+type EventSettings struct {
+	PollInterval time.Duration
+	Retain       int
+}
+
+// RetentionSettings configures the janitor that prunes stored records every
+// Interval, with a policy per dataset in models.RetentionDatasets
+type RetentionSettings struct {
+	Interval time.Duration
+	Policies map[string]RetentionPolicy
+}
+
+// DatabaseSettings schedules maintenance of the store: walking it for
+// inconsistencies every CheckInterval and rewriting it without free pages
+// every CompactInterval; zero disables either
+type DatabaseSettings struct {
+	CheckInterval   time.Duration
+	CompactInterval time.Duration
+}
+
+// SecuritySettings configures the security headers sent with every response.
+// CSP replaces the default Content-Security-Policy, or "off" sends none;
+// FrameAncestors lists the origins allowed to frame the /embed pages, which
+// nothing else may be framed by; HSTSMaxAge enables Strict-Transport-Security
+// on HTTPS requests when non-zero
+type SecuritySettings struct {
+	CSP            string
+	FrameAncestors []string
+	ReferrerPolicy string
+	HSTSMaxAge     time.Duration
+}
+
+// GRPCSettings enables the gRPC API on Addr, which is off when empty
+type GRPCSettings struct {
+	Addr string
+}
+
+// ProxySettings turns on /proxy/{printer}/, which forwards requests for the
+// listed OctoPrint plugins with the printer's API key for operators. Plugins
+// maps each lower-case plugin identifier to whether it may also be written to;
+// the rest are read-only.
+type ProxySettings struct {
+	Enabled bool
+	Plugins map[string]bool
+}
+
+// proxyForbidden lists plugins that manage credentials or the host, which the
+// proxy never forwards to since it authenticates with the printer's own key
+var proxyForbidden = []string{"appkeys", "backup", "pluginmanager", "softwareupdate", "logging", "announcements"}
+
+// loadProxy reads PROXY_ENABLED and the plugins PROXY_PLUGINS and
+// PROXY_WRITE_PLUGINS open to it
+func loadProxy() (ProxySettings, error) {
+	var cfg ProxySettings
+	var err error
+	if cfg.Enabled, err = getBool("PROXY_ENABLED", false); err != nil {
+		return cfg, err
+	}
+
+	cfg.Plugins = make(map[string]bool)
+	add := func(key string, write bool) error {
+		for _, name := range splitList(os.Getenv(key)) {
+			name = strings.ToLower(name)
+			if slices.Contains(proxyForbidden, name) {
+				return fmt.Errorf("%s may not include %q: it manages credentials or the host", key, name)
+			}
+			cfg.Plugins[name] = cfg.Plugins[name] || write
+		}
+		return nil
+	}
+	if err := add("PROXY_PLUGINS", false); err != nil {
+		return cfg, err
+	}
+	return cfg, add("PROXY_WRITE_PLUGINS", true)
+}
+
+// SSOSettings lets operators link their OctoPrint accounts through the
+// Application Keys plugin, so opening a printer's UI logs them in
+type SSOSettings struct {
+	Enabled bool
+}
+
+// HandoverSettings shapes shift handover reports: by default they cover the
+// last Shift, and spools with less than LowSpool grams left are listed
+type HandoverSettings struct {
+	Shift    time.Duration
+	LowSpool float64
+}
+
+// PartLabelSettings prints a label for every finished part on a network label
+// printer: Protocol "zpl" sends raw ZPL to Address (host[:port], 9100 by
+// default) and "ipp" sends a PDF to an ipp:// or http:// printer URI. Labels
+// are Width by Height millimetres; DPI is the ZPL printer's resolution.
+// An empty Address turns label printing off.
+type PartLabelSettings struct {
+	Protocol string
+	Address  string
+	Width    float64
+	Height   float64
+	DPI      int
+}
+
+// ThermalSettings tunes the heater fault watch. A heater more than 10°C below
+// its target must rise MinRise every HeatWindow; one RunawayMargin above its
+// target must not stay there without cooling for RunawayWindow; a reading
+// changing faster than DropoutRate °C/s is a thermistor dropout.
+type ThermalSettings struct {
+	Enabled       bool
+	HeatWindow    time.Duration
+	MinRise       float64
+	RunawayMargin float64
+	RunawayWindow time.Duration
+	DropoutRate   float64
+}
+
+// RetentionPolicy drops a dataset's records older than MaxAge, then its oldest
+// records until at most MaxBytes remain; zero leaves either unlimited
+type RetentionPolicy struct {
+	MaxAge   time.Duration
+	MaxBytes int64
+}
+
+// EventBusSettings configures publishing domain events to a message broker.
+// URL picks the broker by scheme: nats:// publishes to Topic.<event type>
+// subjects, redis:// (or rediss://) appends to the Topic stream, capped at
+// about MaxLen entries. A nil URL publishes nowhere.
+type EventBusSettings struct {
+	URL    *url.URL
+	Topic  string
+	MaxLen int
+}
+
+// AnnounceSettings configures spoken announcements. Events are the domain
+// event types announced on kiosk displays; TTSURL, when set, also sends each
+// announcement to a speech service, with {text} in it replaced by the text and
+// otherwise as a JSON POST. TTSToken is sent to it as a bearer token.
+type AnnounceSettings struct {
+	Events   []string
+	TTSURL   string
+	TTSToken string
+}
+
+// CameraArchiveSettings configures copying camera media to an S3-compatible
+// bucket, off the Pi's SD card. While a printer prints, a frame is grabbed
+// every ClipInterval and the last ClipFrames are kept in memory, to be saved as
+// a clip when the job fails; every SnapshotInterval a frame is also stored as a
+// snapshot. Timelapses copies OctoPrint's rendered timelapses, deleting them
+// from OctoPrint afterwards with DeleteTimelapses. Objects older than the
+// kind's retention in days are removed; zero keeps them.
+type CameraArchiveSettings struct {
+	S3               S3Settings
+	SnapshotInterval time.Duration
+	ClipInterval     time.Duration
+	ClipFrames       int
+	Timelapses       bool
+	DeleteTimelapses bool
+	SnapshotDays     int
+	ClipDays         int
+	TimelapseDays    int
+}
+
+// HomeAssistantSettings configures the Home Assistant endpoints. Token is a
+// long-lived token granting read access to them alone; Name labels the farm's
+// entities.
+type HomeAssistantSettings struct {
+	Token string
+	Name  string
+}
+
+// TriggerSettings enables the IFTTT and Zapier trigger feeds. Key is the API
+// key those services present; the feeds keep the latest Retain events.
+type TriggerSettings struct {
+	Key    string
+	Retain int
+}
+
+// LightSettings is a status light showing the state of the farm, either a
+// WLED device at WLED or the LED strip on Printer's board, driven with M150.
+// Printers limits the printers it reflects; a printer's own strip defaults to
+// just that printer and a WLED device to all of them. Mapping picks the look
+// for the first listed state that any of those printers is in.
+type LightSettings struct {
+	ID         string
+	WLED       string
+	Printer    string
+	Printers   []string
+	Brightness int
+	Mapping    []LightMapping
+}
+
+// LightMapping is the color and effect a light shows for a farm state
+type LightMapping struct {
+	State  string
+	Color  [3]uint8
+	Effect string
+}
+
+// Hex is the mapping's color as #rrggbb
+func (m LightMapping) Hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", m.Color[0], m.Color[1], m.Color[2])
+}
+
+// LightID returns the ID of the light in LIGHT_N_ slot n
+func LightID(n int) string {
+	return fmt.Sprintf("light-%d", n)
+}
+
+// lightColors are the names accepted in place of hex colors
+var lightColors = map[string][3]uint8{
+	"red":    {0xff, 0x00, 0x00},
+	"orange": {0xff, 0x80, 0x00},
+	"yellow": {0xff, 0xc0, 0x00},
+	"green":  {0x00, 0xff, 0x00},
+	"blue":   {0x00, 0x00, 0xff},
+	"purple": {0x80, 0x00, 0xff},
+	"white":  {0xff, 0xff, 0xff},
+}
+
+func loadAnnounce() (AnnounceSettings, error) {
+	a := AnnounceSettings{
+		Events:   splitList(getString("ANNOUNCE_EVENTS", models.EventJobFinished+","+models.EventAlertRaised)),
+		TTSURL:   os.Getenv("ANNOUNCE_TTS_URL"),
+		TTSToken: os.Getenv("ANNOUNCE_TTS_TOKEN"),
+	}
+	if len(a.Events) == 1 && a.Events[0] == "none" {
+		a.Events = nil
+	}
+	for _, t := range a.Events {
+		if !slices.Contains(models.EventTypes, t) {
+			return a, fmt.Errorf("unknown event %q in ANNOUNCE_EVENTS", t)
+		}
+	}
+	if a.TTSURL != "" {
+		if u, err := url.Parse(strings.ReplaceAll(a.TTSURL, "{text}", "x")); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return a, fmt.Errorf("invalid ANNOUNCE_TTS_URL %q: want http(s)://host/path", a.TTSURL)
+		}
+	}
+	return a, nil
+}
+
+func loadLights() ([]LightSettings, error) {
+	defaults, err := parseLightMapping("LIGHT_MAPPING", getString("LIGHT_MAPPING", defaultLightMapping))
+	if err != nil {
+		return nil, err
+	}
+
+	var lights []LightSettings
+	for i := 1; i <= maxLights; i++ {
+		prefix := fmt.Sprintf("LIGHT_%d_", i)
+		l := LightSettings{
+			ID:       LightID(i),
+			WLED:     strings.TrimSuffix(os.Getenv(prefix+"WLED"), "/"),
+			Printer:  os.Getenv(prefix + "PRINTER"),
+			Printers: splitList(os.Getenv(prefix + "PRINTERS")),
+			Mapping:  defaults,
+		}
+		if l.WLED == "" && l.Printer == "" {
+			continue
+		}
+		if l.WLED != "" && l.Printer != "" {
+			return nil, fmt.Errorf("set only one of %sWLED and %sPRINTER", prefix, prefix)
+		}
+		if l.WLED != "" {
+			if u, err := url.Parse(l.WLED); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return nil, fmt.Errorf("invalid %sWLED %q: want http://host", prefix, l.WLED)
+			}
+		}
+		if l.Printer != "" && len(l.Printers) == 0 {
+			l.Printers = []string{l.Printer}
+		}
+
+		if l.Brightness, err = getInt(prefix+"BRIGHTNESS", 255); err != nil {
+			return nil, err
+		}
+		if l.Brightness < 1 || l.Brightness > 255 {
+			return nil, fmt.Errorf("%sBRIGHTNESS must be between 1 and 255", prefix)
+		}
+		if raw := os.Getenv(prefix + "MAPPING"); raw != "" {
+			if l.Mapping, err = parseLightMapping(prefix+"MAPPING", raw); err != nil {
+				return nil, err
+			}
+		}
+		lights = append(lights, l)
+	}
+	return lights, nil
+}
+
+// parseLightMapping reads comma-separated state=color[:effect] rules, where
+// color is rrggbb, a color name or off
+func parseLightMapping(key, raw string) ([]LightMapping, error) {
+	var mapping []LightMapping
+	for _, item := range splitList(raw) {
+		state, look, ok := strings.Cut(item, "=")
+		if !ok || !slices.Contains(models.LightStates, state) {
+			return nil, fmt.Errorf("invalid %s rule %q: want state=color[:effect] with state one of %s",
+				key, item, strings.Join(models.LightStates, ", "))
+		}
+
+		name, effect, _ := strings.Cut(strings.ToLower(look), ":")
+		m := LightMapping{State: state, Effect: models.EffectSolid}
+		if effect != "" {
+			m.Effect = effect
+		}
+		switch m.Effect {
+		case models.EffectSolid, models.EffectPulse, models.EffectBlink:
+		default:
+			return nil, fmt.Errorf("invalid effect %q in %s: want solid, pulse or blink", effect, key)
+		}
+
+		if name == models.EffectOff {
+			m.Effect = models.EffectOff
+		} else if c, ok := lightColors[name]; ok {
+			m.Color = c
+		} else if rgb, err := hex.DecodeString(strings.TrimPrefix(name, "#")); err == nil && len(rgb) == 3 {
+			copy(m.Color[:], rgb)
+		} else {
+			return nil, fmt.Errorf("invalid color %q in %s: want rrggbb or a color name", name, key)
+		}
+		mapping = append(mapping, m)
+	}
+	return mapping, nil
+}
+
+// EinkSettings are the defaults for the e-ink summary image, which displays
+// can override per request. Depth is the bits per pixel of grayscale.
+type EinkSettings struct {
+	Width  int
+	Height int
+	Depth  int
+	Format string
+}
+
+// ExtensionSettings turns off compiled-in extensions by name
+type ExtensionSettings struct {
+	Disabled []string
+}
+
+// QueueSettings configures the print queue scheduler. It starts queued jobs on
+// idle printers every DispatchInterval; zero leaves jobs for manual starts.
+// Uploaded STL and 3MF models and their previews are kept in ModelDir. With
+// Approval, viewers may submit jobs but an operator must approve them first.
+// Entries marked off-peak only start inside OffPeak, e.g. overnight tariffs.
+type QueueSettings struct {
+	DispatchInterval time.Duration
+	ModelDir         string
+	MaxModelSize     int64
+	Approval         bool
+	OffPeak          *DailyWindow
+}
+
+// DailyWindow is a time of day range in the display timezone; it may wrap past midnight
+type DailyWindow struct {
+	Start, End time.Duration // since midnight
+}
+
+// Contains reports whether t's time of day falls inside the window
+func (w DailyWindow) Contains(t time.Time) bool {
+	of := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+	if w.Start <= w.End {
+		return of >= w.Start && of < w.End
+	}
+	return of >= w.Start || of < w.End
+}
+
+// BackupSettings configures scheduled backups
+type BackupSettings struct {
+	Interval time.Duration
+	Dir      string
+	Keep     int
+	S3       S3Settings
+}
+
+// S3Settings describes an S3-compatible bucket for off-site backups
+type S3Settings struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	Prefix    string
+	AccessKey string
+	SecretKey string
+}
+
+// OctoPrintBackupSettings configures collecting backups from each OctoPrint instance
+type OctoPrintBackupSettings struct {
+	Interval     time.Duration
+	Dir          string
+	Keep         int
+	Timeout      time.Duration
+	DeleteRemote bool
+}
+
+// UpdateSettings configures checking OctoPrint instances for software updates
+type UpdateSettings struct {
+	CheckInterval time.Duration
+}
+
+// ProfilingSettings exposes pprof and expvar, either on the main server behind
+// the admin role or, when Addr is set, unauthenticated on a separate listener
+type ProfilingSettings struct {
+	Enabled bool
+	Addr    string
+}
+
+// PollSettings selects how often printers are contacted. The "adaptive" policy
+// backs off printers that have been idle or offline for IdleAfter to one
+// state-only request per IdleInterval; "fixed" contacts every printer on every poll.
+// OfflineAfter and OnlineAfter are how many consecutive failed or successful
+// polls it takes to change a printer between offline and online.
+type PollSettings struct {
+	Policy       string
+	IdleAfter    time.Duration
+	IdleInterval time.Duration
+	OfflineAfter int
+	OnlineAfter  int
+}
+
+// UserSettings describes a single API user. Printers lists the printers the
+// user may operate whatever their role, from PRINTER_N_OPERATORS.
+type UserSettings struct {
+	Name     string
+	Role     string
+	Token    string
+	Printers []string
+}
+
+// Load reads OctoDash settings from the environment
+func Load() (*Settings, error) {
+	s := &Settings{
+		DataDir:  getString("DATA_DIR", "data"),
+		Printers: make(map[string]PrinterSettings),
+	}
+
+	var err error
+	if s.LogRequests, err = getBool("LOG_REQUESTS", false); err != nil {
+		return nil, err
+	}
+	if s.LocalAssets, err = getBool("ASSETS_LOCAL_ONLY", false); err != nil {
+		return nil, err
+	}
+	if s.PublicURL, err = parsePublicURL(os.Getenv("PUBLIC_URL")); err != nil {
+		return nil, err
+	}
+
+	for i := 1; i <= maxPrinters; i++ {
+		p := PrinterSettings{
+			Group:    os.Getenv(fmt.Sprintf("PRINTER_%d_GROUP", i)),
+			Tags:     ParseTags(os.Getenv(fmt.Sprintf("PRINTER_%d_TAGS", i))),
+			Spoolman: os.Getenv(fmt.Sprintf("PRINTER_%d_SPOOLMAN", i)),
+
+			SnapshotURL: os.Getenv(fmt.Sprintf("PRINTER_%d_SNAPSHOT_URL", i)),
+			StreamURL:   os.Getenv(fmt.Sprintf("PRINTER_%d_STREAM_URL", i)),
+		}
+		if p.AutoClear, err = getBool(fmt.Sprintf("PRINTER_%d_AUTO_CLEAR", i), false); err != nil {
+			return nil, err
+		}
+		if p.Continuous, err = getBool(fmt.Sprintf("PRINTER_%d_CONTINUOUS", i), false); err != nil {
+			return nil, err
+		}
+		if p.Cooldown, err = getDuration(fmt.Sprintf("PRINTER_%d_COOLDOWN", i), 0); err != nil {
+			return nil, err
+		}
+		p.EjectGcode = splitList(os.Getenv(fmt.Sprintf("PRINTER_%d_EJECT_GCODE", i)))
+		p.Checklist = splitList(os.Getenv(fmt.Sprintf("PRINTER_%d_CHECKLIST", i)))
+		p.Operators = splitList(os.Getenv(fmt.Sprintf("PRINTER_%d_OPERATORS", i)))
+		s.Printers[PrinterID(i)] = p
+	}
+
+	defaults := models.DefaultUnits()
+	s.Units = models.Units{
+		Temperature: strings.ToUpper(getString("UNITS_TEMPERATURE", defaults.Temperature)),
+		Weight:      strings.ToLower(getString("UNITS_WEIGHT", defaults.Weight)),
+		Length:      strings.ToLower(getString("UNITS_LENGTH", defaults.Length)),
+	}
+	if err := s.Units.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Times are kept in UTC everywhere; the timezone only affects display
+	tz, err := time.LoadLocation(getString("DISPLAY_TIMEZONE", "UTC"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DISPLAY_TIMEZONE: %w", err)
+	}
+	s.Timezone = tz
+
+	if s.Idle.After, err = getDuration("IDLE_SCREEN_AFTER", 0); err != nil {
+		return nil, err
+	}
+	s.Idle.Mode = strings.ToLower(getString("IDLE_SCREEN_MODE", "clock"))
+	switch s.Idle.Mode {
+	case "dim", "shift", "clock":
+	default:
+		return nil, fmt.Errorf("invalid IDLE_SCREEN_MODE %q", s.Idle.Mode)
+	}
+
+	if s.Preheat.Hotend, err = getFloat("PREHEAT_HOTEND", 215); err != nil {
+		return nil, err
+	}
+	if s.Preheat.Bed, err = getFloat("PREHEAT_BED", 60); err != nil {
+		return nil, err
+	}
+	if s.Energy, err = loadEnergy(); err != nil {
+		return nil, err
+	}
+	if s.Auth, err = loadAuth(s.Printers); err != nil {
+		return nil, err
+	}
+	if s.Upstream, err = loadUpstream(); err != nil {
+		return nil, err
+	}
+	if s.Demo, err = loadDemo(); err != nil {
+		return nil, err
+	}
+	if s.Events.PollInterval, err = getDuration("EVENTS_POLL_INTERVAL", 30*time.Second); err != nil {
+		return nil, err
+	}
+	if s.Events.Retain, err = getInt("EVENTS_RETAIN", 1000); err != nil {
+		return nil, err
+	}
+	if s.Queue.DispatchInterval, err = getDuration("QUEUE_DISPATCH_INTERVAL", 0); err != nil {
+		return nil, err
+	}
+	s.Queue.ModelDir = getString("QUEUE_MODEL_DIR", filepath.Join(s.DataDir, "models"))
+	maxModelMB, err := getInt("QUEUE_MAX_MODEL_MB", 100)
+	if err != nil {
+		return nil, err
+	}
+	if maxModelMB <= 0 {
+		return nil, fmt.Errorf("QUEUE_MAX_MODEL_MB must be positive")
+	}
+	s.Queue.MaxModelSize = int64(maxModelMB) << 20
+	if s.Queue.Approval, err = getBool("QUEUE_APPROVAL", false); err != nil {
+		return nil, err
+	}
+	if v := os.Getenv("QUEUE_OFF_PEAK"); v != "" {
+		if s.Queue.OffPeak, err = parseDailyWindow(v); err != nil {
+			return nil, fmt.Errorf("QUEUE_OFF_PEAK: %w", err)
+		}
+	}
+	if s.Backup, err = loadBackup(s.DataDir); err != nil {
+		return nil, err
+	}
+	if s.OctoPrintBackup, err = loadOctoPrintBackup(s.DataDir); err != nil {
+		return nil, err
+	}
+	if s.CameraArchive, err = loadCameraArchive(); err != nil {
+		return nil, err
+	}
+	if s.Retention, err = loadRetention(); err != nil {
+		return nil, err
+	}
+	if s.Database.CheckInterval, err = getDuration("DATABASE_CHECK_INTERVAL", 24*time.Hour); err != nil {
+		return nil, err
+	}
+	if s.Database.CompactInterval, err = getDuration("DATABASE_COMPACT_INTERVAL", 7*24*time.Hour); err != nil {
+		return nil, err
+	}
+	s.Security = SecuritySettings{
+		CSP:            os.Getenv("SECURITY_CSP"),
+		FrameAncestors: splitList(getString("SECURITY_FRAME_ANCESTORS", "*")),
+		ReferrerPolicy: getString("SECURITY_REFERRER_POLICY", "same-origin"),
+	}
+	if s.Security.HSTSMaxAge, err = getDuration("SECURITY_HSTS_MAX_AGE", 0); err != nil {
+		return nil, err
+	}
+	s.GRPC.Addr = os.Getenv("GRPC_ADDR")
+	if s.Thermal, err = loadThermal(); err != nil {
+		return nil, err
+	}
+	if s.FirstLayer, err = loadFirstLayer(); err != nil {
+		return nil, err
+	}
+	if s.Stall, err = loadStall(); err != nil {
+		return nil, err
+	}
+	if s.Proxy, err = loadProxy(); err != nil {
+		return nil, err
+	}
+	if s.SSO.Enabled, err = getBool("SSO_ENABLED", false); err != nil {
+		return nil, err
+	}
+	if s.Handover.Shift, err = getDuration("HANDOVER_SHIFT", 8*time.Hour); err != nil {
+		return nil, err
+	}
+	if s.Handover.LowSpool, err = getFloat("HANDOVER_LOW_SPOOL", 150); err != nil {
+		return nil, err
+	}
+	if s.PartLabels, err = loadPartLabels(s.PublicURL); err != nil {
+		return nil, err
+	}
+	if s.Updates.CheckInterval, err = getDuration("UPDATES_CHECK_INTERVAL", 6*time.Hour); err != nil {
+		return nil, err
+	}
+	if s.Profiling.Enabled, err = getBool("PROFILING_ENABLED", false); err != nil {
+		return nil, err
+	}
+	s.Profiling.Addr = os.Getenv("PROFILING_ADDR")
+	if s.Tailscale, err = loadTailscale(s.DataDir); err != nil {
+		return nil, err
+	}
+	if s.Poll, err = loadPoll(); err != nil {
+		return nil, err
+	}
+	if s.Macros, err = loadMacros(); err != nil {
+		return nil, err
+	}
+	if s.Webhooks, err = loadWebhooks(); err != nil {
+		return nil, err
+	}
+	if s.EventBus, err = loadEventBus(); err != nil {
+		return nil, err
+	}
+	s.Extensions.Disabled = splitList(os.Getenv("EXTENSIONS_DISABLED"))
+	s.Triggers.Key = os.Getenv("TRIGGERS_KEY")
+	if s.Triggers.Retain, err = getInt("TRIGGERS_RETAIN", 200); err != nil {
+		return nil, err
+	}
+	s.HomeAssistant = HomeAssistantSettings{
+		Token: os.Getenv("HOMEASSISTANT_TOKEN"),
+		Name:  getString("HOMEASSISTANT_NAME", "OctoDash"),
+	}
+	if s.Announce, err = loadAnnounce(); err != nil {
+		return nil, err
+	}
+	if s.Lights, err = loadLights(); err != nil {
+		return nil, err
+	}
+	if s.Eink, err = loadEink(); err != nil {
+		return nil, err
+	}
+	if s.Rules, err = loadRules(); err != nil {
+		return nil, err
+	}
+	if s.Spoolman, err = loadSpoolman(); err != nil {
+		return nil, err
+	}
+	if s.Forecast.Window, err = getDuration("FORECAST_WINDOW", 30*24*time.Hour); err != nil {
+		return nil, err
+	}
+	if s.Forecast.ReorderWithin, err = getDuration("FORECAST_REORDER_WITHIN", 14*24*time.Hour); err != nil {
+		return nil, err
+	}
+	if s.MaterialColors, err = parseMaterialColors(getString("MATERIAL_COLORS", defaultMaterialColors)); err != nil {
+		return nil, err
+	}
+	if s.Teams.Enabled, err = getBool("TEAMS_ENABLED", false); err != nil {
+		return nil, err
+	}
+	if s.Quota.MonthlyHours, err = getFloat("QUOTA_MONTHLY_HOURS", 0); err != nil {
+		return nil, err
+	}
+	if s.Quota.MonthlyFilament, err = getFloat("QUOTA_MONTHLY_FILAMENT_M", 0); err != nil {
+		return nil, err
+	}
+	s.Quota.Exempt = splitList(os.Getenv("QUOTA_EXEMPT"))
+	s.Slicer.Command = strings.Fields(os.Getenv("SLICER_COMMAND"))
+	s.Slicer.ProfileDir = getString("SLICER_PROFILE_DIR", filepath.Join(s.DataDir, "slicer-profiles"))
+	if s.Slicer.Timeout, err = getDuration("SLICER_TIMEOUT", 10*time.Minute); err != nil {
+		return nil, err
+	}
+	if s.Slicer.Timeout <= 0 {
+		return nil, fmt.Errorf("SLICER_TIMEOUT must be positive")
+	}
+	s.Drying.Materials = splitList(strings.ToUpper(getString("DRYING_MATERIALS", "PETG,TPU,PA,NYLON,PC,PVA,ASA,ABS")))
+	for id, p := range s.Printers {
+		if p.Spoolman != "" && !s.Spoolman.Has(p.Spoolman) {
+			return nil, fmt.Errorf("%s: unknown Spoolman instance %q", id, p.Spoolman)
+		}
+	}
+
+	return s, nil
+}
+
+func loadEnergy() (EnergySettings, error) {
+	e := EnergySettings{
+		Currency: getString("ENERGY_CURRENCY", "USD"),
+		Plugs:    make(map[string]PlugSettings),
+	}
+
+	var err error
+	if e.PollInterval, err = getDuration("ENERGY_POLL_INTERVAL", 10*time.Second); err != nil {
+		return e, err
+	}
+	if e.PollInterval <= 0 {
+		return e, fmt.Errorf("ENERGY_POLL_INTERVAL must be positive")
+	}
+	if e.PricePerKWh, err = getFloat("ENERGY_PRICE_PER_KWH", 0); err != nil {
+		return e, err
+	}
+
+	for i := 1; i <= maxPrinters; i++ {
+		plugType := os.Getenv(fmt.Sprintf("PRINTER_%d_PLUG_TYPE", i))
+		if plugType == "" {
+			continue
+		}
+
+		address := os.Getenv(fmt.Sprintf("PRINTER_%d_PLUG_ADDRESS", i))
+		if address == "" {
+			return e, fmt.Errorf("PRINTER_%d_PLUG_ADDRESS not set", i)
+		}
+
+		e.Plugs[PrinterID(i)] = PlugSettings{
+			Type:    strings.ToLower(plugType),
+			Address: address,
+		}
+	}
+
+	return e, nil
+}
+
+// loadAuth parses AUTH_USERS, a comma-separated list of name:role:token entries,
+// and grants each user the printers that list them as operators
+func loadAuth(printers map[string]PrinterSettings) (AuthSettings, error) {
+	a := AuthSettings{}
+
+	for _, entry := range splitList(os.Getenv("AUTH_USERS")) {
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+			return a, fmt.Errorf("invalid AUTH_USERS entry %q, expected name:role:token", entry)
+		}
+
+		a.Users = append(a.Users, UserSettings{
+			Name:  parts[0],
+			Role:  parts[1],
+			Token: parts[2],
+		})
+	}
+
+	for i := 1; i <= maxPrinters; i++ {
+		id := PrinterID(i)
+		for _, name := range printers[id].Operators {
+			found := false
+			for u := range a.Users {
+				if a.Users[u].Name == name {
+					a.Users[u].Printers = append(a.Users[u].Printers, id)
+					found = true
+				}
+			}
+			if !found {
+				return a, fmt.Errorf("PRINTER_%d_OPERATORS: unknown user %q", i, name)
+			}
+		}
+	}
+
+	return a, nil
+}
+
+func loadUpstream() (UpstreamSettings, error) {
+	u := UpstreamSettings{}
+
+	var err error
+	if u.MaxIdleConnsPerHost, err = getInt("UPSTREAM_MAX_IDLE_CONNS_PER_HOST", 4); err != nil {
+		return u, err
+	}
+	if u.MaxConnsPerHost, err = getInt("UPSTREAM_MAX_CONNS_PER_HOST", 8); err != nil {
+		return u, err
+	}
+	if u.IdleConnTimeout, err = getDuration("UPSTREAM_IDLE_TIMEOUT", 90*time.Second); err != nil {
+		return u, err
+	}
+	if u.DNSCacheTTL, err = getDuration("DNS_CACHE_TTL", 0); err != nil {
+		return u, err
+	}
+	if u.HostOverrides, err = parseHostOverrides(os.Getenv("HOST_OVERRIDES")); err != nil {
+		return u, err
+	}
+	if u.Proxy, _, err = parseProxy("UPSTREAM_PROXY"); err != nil {
+		return u, err
+	}
+
+	u.PrinterProxies = make(map[string]*url.URL)
+	u.PrinterTimeouts = make(map[string]time.Duration)
+	for i := 1; i <= maxPrinters; i++ {
+		proxy, ok, err := parseProxy(fmt.Sprintf("PRINTER_%d_PROXY", i))
+		if err != nil {
+			return u, err
+		}
+		if ok {
+			u.PrinterProxies[PrinterID(i)] = proxy
+		}
+
+		timeout, err := getDuration(fmt.Sprintf("PRINTER_%d_TIMEOUT", i), 0)
+		if err != nil {
+			return u, err
+		}
+		if timeout > 0 {
+			u.PrinterTimeouts[PrinterID(i)] = timeout
+		}
+	}
+
+	return u, nil
+}
+
+// parseProxy reads a proxy URL (http, https, socks5 or socks5h) from key,
+// reporting whether it was set. "direct" is set but returns no proxy.
+func parseProxy(key string) (*url.URL, bool, error) {
+	v := strings.TrimSpace(os.Getenv(key))
+	if v == "" {
+		return nil, false, nil
+	}
+	if strings.EqualFold(v, "direct") {
+		return nil, true, nil
+	}
+
+	u, err := url.Parse(v)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, false, fmt.Errorf("invalid %s: scheme must be http, https, socks5 or socks5h", key)
+	}
+	if u.Host == "" {
+		return nil, false, fmt.Errorf("invalid %s: missing host", key)
+	}
+	return u, true, nil
+}
+
+func loadDemo() (DemoSettings, error) {
+	d := DemoSettings{}
+
+	var err error
+	if d.Enabled, err = getBool("DEMO", false); err != nil {
+		return d, err
+	}
+	if d.Printers, err = getInt("DEMO_PRINTERS", 4); err != nil {
+		return d, err
+	}
+	if d.Printers < 1 || d.Printers > maxPrinters {
+		return d, fmt.Errorf("DEMO_PRINTERS must be between 1 and %d", maxPrinters)
+	}
+	if d.Speed, err = getFloat("DEMO_SPEED", 10); err != nil {
+		return d, err
+	}
+
+	return d, nil
+}
+
+func loadBackup(dataDir string) (BackupSettings, error) {
+	b := BackupSettings{
+		Dir: getString("BACKUP_DIR", filepath.Join(dataDir, "backups")),
+		S3: S3Settings{
+			Endpoint:  os.Getenv("BACKUP_S3_ENDPOINT"),
+			Region:    getString("BACKUP_S3_REGION", "us-east-1"),
+			Bucket:    os.Getenv("BACKUP_S3_BUCKET"),
+			Prefix:    os.Getenv("BACKUP_S3_PREFIX"),
+			AccessKey: os.Getenv("BACKUP_S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("BACKUP_S3_SECRET_KEY"),
+		},
+	}
+
+	var err error
+	if b.Interval, err = getDuration("BACKUP_INTERVAL", 0); err != nil {
+		return b, err
+	}
+	if b.Keep, err = getInt("BACKUP_KEEP", 7); err != nil {
+		return b, err
+	}
+	if b.S3.Bucket != "" && (b.S3.Endpoint == "" || b.S3.AccessKey == "" || b.S3.SecretKey == "") {
+		return b, fmt.Errorf("BACKUP_S3_BUCKET needs BACKUP_S3_ENDPOINT, BACKUP_S3_ACCESS_KEY and BACKUP_S3_SECRET_KEY")
+	}
+
+	return b, nil
+}
+
+func loadOctoPrintBackup(dataDir string) (OctoPrintBackupSettings, error) {
+	b := OctoPrintBackupSettings{
+		Dir: getString("OCTOPRINT_BACKUP_DIR", filepath.Join(dataDir, "octoprint-backups")),
+	}
+
+	var err error
+	if b.Interval, err = getDuration("OCTOPRINT_BACKUP_INTERVAL", 0); err != nil {
+		return b, err
+	}
+	if b.Keep, err = getInt("OCTOPRINT_BACKUP_KEEP", 5); err != nil {
+		return b, err
+	}
+	if b.Timeout, err = getDuration("OCTOPRINT_BACKUP_TIMEOUT", 15*time.Minute); err != nil {
+		return b, err
+	}
+	if b.DeleteRemote, err = getBool("OCTOPRINT_BACKUP_DELETE_REMOTE", false); err != nil {
+		return b, err
+	}
+
+	return b, nil
+}
+
+func loadCameraArchive() (CameraArchiveSettings, error) {
+	c := CameraArchiveSettings{
+		S3: S3Settings{
+			Endpoint:  os.Getenv("CAMERA_ARCHIVE_S3_ENDPOINT"),
+			Region:    getString("CAMERA_ARCHIVE_S3_REGION", "us-east-1"),
+			Bucket:    os.Getenv("CAMERA_ARCHIVE_S3_BUCKET"),
+			Prefix:    os.Getenv("CAMERA_ARCHIVE_S3_PREFIX"),
+			AccessKey: os.Getenv("CAMERA_ARCHIVE_S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("CAMERA_ARCHIVE_S3_SECRET_KEY"),
+		},
+	}
+
+	var err error
+	if c.SnapshotInterval, err = getDuration("CAMERA_ARCHIVE_SNAPSHOT_INTERVAL", 5*time.Minute); err != nil {
+		return c, err
+	}
+	if c.ClipInterval, err = getDuration("CAMERA_ARCHIVE_CLIP_INTERVAL", 10*time.Second); err != nil {
+		return c, err
+	}
+	if c.ClipFrames, err = getInt("CAMERA_ARCHIVE_CLIP_FRAMES", 30); err != nil {
+		return c, err
+	}
+	if c.Timelapses, err = getBool("CAMERA_ARCHIVE_TIMELAPSES", true); err != nil {
+		return c, err
+	}
+	if c.DeleteTimelapses, err = getBool("CAMERA_ARCHIVE_DELETE_TIMELAPSES", false); err != nil {
+		return c, err
+	}
+	if c.SnapshotDays, err = getInt("CAMERA_ARCHIVE_SNAPSHOT_DAYS", 14); err != nil {
+		return c, err
+	}
+	if c.ClipDays, err = getInt("CAMERA_ARCHIVE_CLIP_DAYS", 90); err != nil {
+		return c, err
+	}
+	if c.TimelapseDays, err = getInt("CAMERA_ARCHIVE_TIMELAPSE_DAYS", 0); err != nil {
+		return c, err
+	}
+	if c.S3.Bucket != "" && (c.S3.Endpoint == "" || c.S3.AccessKey == "" || c.S3.SecretKey == "") {
+		return c, fmt.Errorf("CAMERA_ARCHIVE_S3_BUCKET needs CAMERA_ARCHIVE_S3_ENDPOINT, CAMERA_ARCHIVE_S3_ACCESS_KEY and CAMERA_ARCHIVE_S3_SECRET_KEY")
+	}
+	if c.S3.Bucket != "" && c.ClipFrames > 0 && c.ClipInterval <= 0 {
+		return c, fmt.Errorf("CAMERA_ARCHIVE_CLIP_FRAMES needs a positive CAMERA_ARCHIVE_CLIP_INTERVAL")
+	}
+
+	return c, nil
+}
+
+func loadRetention() (RetentionSettings, error) {
+	r := RetentionSettings{Policies: make(map[string]RetentionPolicy)}
+
+	var err error
+	if r.Interval, err = getDuration("RETENTION_INTERVAL", time.Hour); err != nil {
+		return r, err
+	}
+	for _, dataset := range models.RetentionDatasets {
+		prefix := "RETENTION_" + strings.ToUpper(dataset) + "_"
+
+		var p RetentionPolicy
+		if p.MaxAge, err = getDuration(prefix+"MAX_AGE", 0); err != nil {
+			return r, err
+		}
+		maxMB, err := getInt(prefix+"MAX_MB", 0)
+		if err != nil {
+			return r, err
+		}
+		p.MaxBytes = int64(maxMB) << 20
+		if p != (RetentionPolicy{}) {
+			r.Policies[dataset] = p
+		}
+	}
+
+	// Quotas total the current month from the usage ledger
+	if age := r.Policies[models.DataUsage].MaxAge; age > 0 && age < 31*24*time.Hour {
+		return r, fmt.Errorf("RETENTION_USAGE_MAX_AGE must be at least 744h so quotas keep the whole month")
+	}
+
+	return r, nil
+}
+
+func loadTailscale(dataDir string) (TailscaleSettings, error) {
+	t := TailscaleSettings{
+		Hostname: getString("TAILSCALE_HOSTNAME", "octodash"),
+		AuthKey:  os.Getenv("TAILSCALE_AUTHKEY"),
+		StateDir: getString("TAILSCALE_STATE_DIR", filepath.Join(dataDir, "tailscale")),
+	}
+
+	var err error
+	if t.Enabled, err = getBool("TAILSCALE_ENABLED", false); err != nil {
+		return t, err
+	}
+	return t, nil
+}
+
+func loadPoll() (PollSettings, error) {
+	p := PollSettings{Policy: strings.ToLower(getString("POLL_POLICY", "fixed"))}
+	switch p.Policy {
+	case "fixed", "adaptive":
+	default:
+		return p, fmt.Errorf("invalid POLL_POLICY %q", p.Policy)
+	}
+
+	var err error
+	if p.IdleAfter, err = getDuration("POLL_IDLE_AFTER", 2*time.Minute); err != nil {
+		return p, err
+	}
+	if p.IdleInterval, err = getDuration("POLL_IDLE_INTERVAL", 15*time.Second); err != nil {
+		return p, err
+	}
+	if p.OfflineAfter, err = getInt("POLL_OFFLINE_AFTER", 1); err != nil {
+		return p, err
+	}
+	if p.OnlineAfter, err = getInt("POLL_ONLINE_AFTER", 1); err != nil {
+		return p, err
+	}
+	if p.OfflineAfter < 1 || p.OnlineAfter < 1 {
+		return p, fmt.Errorf("POLL_OFFLINE_AFTER and POLL_ONLINE_AFTER must be at least 1")
+	}
+
+	return p, nil
+}
+
+// FirstLayerSettings alerts when a print has spent Timeout of printing time
+// without getting past Percent complete, the usual sign of a nozzle jammed on
+// the first layer; a zero Timeout turns the watchdog off
+type FirstLayerSettings struct {
+	Percent float64
+	Timeout time.Duration
+}
+
+// StallSettings flags a print whose file position hasn't moved for Window
+// while the printer reports printing; a zero Window turns the check off.
+// Notify raises an alert as well.
+type StallSettings struct {
+	Window time.Duration
+	Notify bool
+}
+
+func loadStall() (StallSettings, error) {
+	var s StallSettings
+	var err error
+	if s.Window, err = getDuration("STALL_WINDOW", 10*time.Minute); err != nil {
+		return s, err
+	}
+	if s.Notify, err = getBool("STALL_NOTIFY", false); err != nil {
+		return s, err
+	}
+	return s, nil
+}
+
+func loadFirstLayer() (FirstLayerSettings, error) {
+	var f FirstLayerSettings
+	var err error
+	if f.Percent, err = getFloat("FIRST_LAYER_PERCENT", 2); err != nil {
+		return f, err
+	}
+	if f.Timeout, err = getDuration("FIRST_LAYER_TIMEOUT", 0); err != nil {
+		return f, err
+	}
+	if f.Percent <= 0 || f.Percent >= 100 {
+		return f, fmt.Errorf("FIRST_LAYER_PERCENT must be between 0 and 100")
+	}
+	return f, nil
+}
+
+func loadThermal() (ThermalSettings, error) {
+	var t ThermalSettings
+	var err error
+	if t.Enabled, err = getBool("THERMAL_WATCH", true); err != nil {
+		return t, err
+	}
+	if t.HeatWindow, err = getDuration("THERMAL_HEAT_WINDOW", 3*time.Minute); err != nil {
+		return t, err
+	}
+	if t.MinRise, err = getFloat("THERMAL_MIN_RISE", 2); err != nil {
+		return t, err
+	}
+	if t.RunawayMargin, err = getFloat("THERMAL_RUNAWAY_MARGIN", 15); err != nil {
+		return t, err
+	}
+	if t.RunawayWindow, err = getDuration("THERMAL_RUNAWAY_WINDOW", time.Minute); err != nil {
+		return t, err
+	}
+	if t.DropoutRate, err = getFloat("THERMAL_DROPOUT_RATE", 10); err != nil {
+		return t, err
+	}
+	if t.HeatWindow <= 0 || t.RunawayWindow <= 0 || t.RunawayMargin <= 0 || t.DropoutRate <= 0 {
+		return t, fmt.Errorf("THERMAL_HEAT_WINDOW, THERMAL_RUNAWAY_WINDOW, THERMAL_RUNAWAY_MARGIN and THERMAL_DROPOUT_RATE must be positive")
+	}
+	return t, nil
+}
+
+// defaultMaterialColors keeps common materials apart at a glance
+const defaultMaterialColors = "PLA=#4caf50,PETG=#2196f3,ABS=#f44336,ASA=#ff9800,TPU=#e040fb,PA=#ffeb3b,PC=#9e9e9e"
+
+// parseMaterialColors reads MATERIAL=#rrggbb pairs; "none" turns accents off
+func parseMaterialColors(v string) (map[string]string, error) {
+	colors := make(map[string]string)
+	if strings.EqualFold(v, "none") {
+		return colors, nil
+	}
+	for _, item := range splitList(v) {
+		material, color, _ := strings.Cut(item, "=")
+		material = strings.ToUpper(strings.TrimSpace(material))
+		color = strings.ToLower(strings.TrimSpace(color))
+		if material == "" || !isHexColor(color) {
+			return nil, fmt.Errorf("invalid MATERIAL_COLORS entry %q: want MATERIAL=#rrggbb", item)
+		}
+		colors[material] = color
+	}
+	return colors, nil
+}
+
+func isHexColor(c string) bool {
+	if len(c) != 7 || c[0] != '#' {
+		return false
+	}
+	_, err := strconv.ParseUint(c[1:], 16, 32)
+	return err == nil
+}
+
+func parsePublicURL(raw string) (string, error) {
+	if raw == "" {
+		return "", nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("invalid PUBLIC_URL %q: want http(s)://host[/path]", raw)
+	}
+	return strings.TrimRight(raw, "/"), nil
+}
+
+// loadPartLabels reads the PART_LABEL_ options. A label's QR code links back
+// to the dashboard, so printing them needs PUBLIC_URL.
+func loadPartLabels(publicURL string) (PartLabelSettings, error) {
+	cfg := PartLabelSettings{
+		Protocol: strings.ToLower(getString("PART_LABEL_PROTOCOL", "zpl")),
+		Address:  os.Getenv("PART_LABEL_ADDRESS"),
+	}
+	if cfg.Address == "" {
+		return cfg, nil
+	}
+	if publicURL == "" {
+		return cfg, fmt.Errorf("PART_LABEL_ADDRESS requires PUBLIC_URL for the label's QR code")
+	}
+
+	switch cfg.Protocol {
+	case "zpl":
+		if _, _, err := net.SplitHostPort(cfg.Address); err != nil {
+			cfg.Address = net.JoinHostPort(cfg.Address, "9100")
+		}
+	case "ipp":
+		u, err := url.Parse(cfg.Address)
+		if err != nil || u.Host == "" || (u.Scheme != "ipp" && u.Scheme != "ipps" && u.Scheme != "http" && u.Scheme != "https") {
+			return cfg, fmt.Errorf("invalid PART_LABEL_ADDRESS %q: want ipp://host[:port]/path", cfg.Address)
+		}
+	default:
+		return cfg, fmt.Errorf("invalid PART_LABEL_PROTOCOL %q: want zpl or ipp", cfg.Protocol)
+	}
+
+	var err error
+	if cfg.Width, err = getFloat("PART_LABEL_WIDTH", 100); err != nil {
+		return cfg, err
+	}
+	if cfg.Height, err = getFloat("PART_LABEL_HEIGHT", 50); err != nil {
+		return cfg, err
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return cfg, fmt.Errorf("PART_LABEL_WIDTH and PART_LABEL_HEIGHT must be positive")
+	}
+	if cfg.DPI, err = getInt("PART_LABEL_DPI", 203); err != nil {
+		return cfg, err
+	}
+	if cfg.DPI <= 0 {
+		return cfg, fmt.Errorf("PART_LABEL_DPI must be positive")
+	}
+	return cfg, nil
+}
+
+// MacroSettings is a named gcode sequence offered as a button on printer cards.
+// Printers limits it to those printer IDs, with none offering it everywhere;
+// Role is the lowest role that may run it and Confirm asks before sending it.
+type MacroSettings struct {
+	ID       string
+	Name     string
+	Gcode    []string
+	Printers []string
+	Role     string
+	Confirm  bool
+}
+
+// MacroID returns the ID of the macro in MACRO_N_ slot n
+func MacroID(n int) string {
+	return fmt.Sprintf("macro-%d", n)
+}
+
+func loadMacros() ([]MacroSettings, error) {
+	var macros []MacroSettings
+	for i := 1; i <= maxMacros; i++ {
+		prefix := fmt.Sprintf("MACRO_%d_", i)
+		m := MacroSettings{
+			ID:       MacroID(i),
+			Name:     os.Getenv(prefix + "NAME"),
+			Gcode:    splitList(os.Getenv(prefix + "GCODE")),
+			Printers: splitList(os.Getenv(prefix + "PRINTERS")),
+			Role:     getString(prefix+"ROLE", "operator"),
+		}
+		if m.Name == "" && len(m.Gcode) == 0 {
+			continue
+		}
+		if m.Name == "" || len(m.Gcode) == 0 {
+			return nil, fmt.Errorf("%sNAME and %sGCODE must be set together", prefix, prefix)
+		}
+
+		var err error
+		if m.Confirm, err = getBool(prefix+"CONFIRM", false); err != nil {
+			return nil, err
+		}
+		macros = append(macros, m)
+	}
+	return macros, nil
+}
+
+// RuleSettings is an automation rule: once When has held on a printer for
+// For, Gcode is sent to it and, with Notify, an alert is raised. Printers
+// limits it to those printer IDs, with none applying it everywhere.
+type RuleSettings struct {
+	ID       string
+	Name     string
+	When     string
+	For      time.Duration
+	Printers []string
+	Gcode    []string
+	Notify   bool
+}
+
+// RuleID returns the ID of the rule in RULE_N_ slot n
+func RuleID(n int) string {
+	return fmt.Sprintf("rule-%d", n)
+}
+
+func loadRules() ([]RuleSettings, error) {
+	var rules []RuleSettings
+	for i := 1; i <= maxRules; i++ {
+		prefix := fmt.Sprintf("RULE_%d_", i)
+		r := RuleSettings{
+			ID:       RuleID(i),
+			When:     os.Getenv(prefix + "WHEN"),
+			Printers: splitList(os.Getenv(prefix + "PRINTERS")),
+			Gcode:    splitList(os.Getenv(prefix + "GCODE")),
+		}
+		if r.When == "" {
+			continue
+		}
+		r.Name = getString(prefix+"NAME", r.ID)
+
+		var err error
+		if r.For, err = getDuration(prefix+"FOR", 0); err != nil {
+			return nil, err
+		}
+		if r.Notify, err = getBool(prefix+"NOTIFY", false); err != nil {
+			return nil, err
+		}
+		if len(r.Gcode) == 0 && !r.Notify {
+			return nil, fmt.Errorf("%sWHEN needs %sGCODE or %sNOTIFY", prefix, prefix, prefix)
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+// WebhookSettings configures the outbox that posts domain events to other systems.
+// Pending deliveries are tried every Interval, backing off between failures
+// until MaxAttempts; the delivery log keeps the latest Retain deliveries,
+// along with any still pending.
+type WebhookSettings struct {
+	Endpoints   []WebhookEndpoint
+	Interval    time.Duration
+	Timeout     time.Duration
+	MaxAttempts int
+	Retain      int
+}
+
+// WebhookEndpoint is a URL receiving events, signed with Secret when set.
+// Events limits it to those event types, with none sending every event.
+type WebhookEndpoint struct {
+	ID     string
+	URL    string
+	Secret string
+	Events []string
+}
+
+// WebhookID returns the ID of the webhook in WEBHOOK_N_ slot n
+func WebhookID(n int) string {
+	return fmt.Sprintf("webhook-%d", n)
+}
+
+func loadWebhooks() (WebhookSettings, error) {
+	var w WebhookSettings
+	for i := 1; i <= maxWebhooks; i++ {
+		prefix := fmt.Sprintf("WEBHOOK_%d_", i)
+		e := WebhookEndpoint{
+			ID:     WebhookID(i),
+			URL:    os.Getenv(prefix + "URL"),
+			Secret: os.Getenv(prefix + "SECRET"),
+			Events: splitList(os.Getenv(prefix + "EVENTS")),
+		}
+		if e.URL == "" {
+			continue
+		}
+		if u, err := url.Parse(e.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return w, fmt.Errorf("invalid %sURL %q: want http(s)://host/path", prefix, e.URL)
+		}
+		for _, t := range e.Events {
+			if !slices.Contains(models.EventTypes, t) {
+				return w, fmt.Errorf("unknown event %q in %sEVENTS", t, prefix)
+			}
+		}
+		w.Endpoints = append(w.Endpoints, e)
+	}
+
+	var err error
+	if w.Interval, err = getDuration("WEBHOOK_INTERVAL", 5*time.Second); err != nil {
+		return w, err
+	}
+	if w.Timeout, err = getDuration("WEBHOOK_TIMEOUT", 10*time.Second); err != nil {
+		return w, err
+	}
+	if w.MaxAttempts, err = getInt("WEBHOOK_MAX_ATTEMPTS", 8); err != nil {
+		return w, err
+	}
+	if w.Retain, err = getInt("WEBHOOK_RETAIN", 1000); err != nil {
+		return w, err
+	}
+	if len(w.Endpoints) > 0 && (w.Interval <= 0 || w.MaxAttempts < 1) {
+		return w, fmt.Errorf("WEBHOOK_INTERVAL and WEBHOOK_MAX_ATTEMPTS must be positive")
+	}
+	return w, nil
+}
+
+func loadEventBus() (EventBusSettings, error) {
+	b := EventBusSettings{Topic: getString("EVENTS_BUS_TOPIC", "octodash")}
+
+	var err error
+	if b.MaxLen, err = getInt("EVENTS_BUS_MAXLEN", 10000); err != nil {
+		return b, err
+	}
+
+	raw := os.Getenv("EVENTS_BUS_URL")
+	if raw == "" {
+		return b, nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return b, fmt.Errorf("invalid EVENTS_BUS_URL %q: want nats://host:port or redis://host:port/db", raw)
+	}
+	switch u.Scheme {
+	case "nats", "redis", "rediss":
+	default:
+		return b, fmt.Errorf("unsupported EVENTS_BUS_URL scheme %q: want nats, redis or rediss", u.Scheme)
+	}
+	b.URL = u
+	return b, nil
+}
+
+func loadEink() (EinkSettings, error) {
+	e := EinkSettings{Format: getString("EINK_FORMAT", "png")}
+
+	var err error
+	if e.Width, err = getInt("EINK_WIDTH", 800); err != nil {
+		return e, err
+	}
+	if e.Height, err = getInt("EINK_HEIGHT", 480); err != nil {
+		return e, err
+	}
+	if e.Depth, err = getInt("EINK_DEPTH", 1); err != nil {
+		return e, err
+	}
+	if e.Width <= 0 || e.Height <= 0 {
+		return e, fmt.Errorf("invalid EINK_WIDTH/EINK_HEIGHT %dx%d", e.Width, e.Height)
+	}
+	switch e.Depth {
+	case 1, 2, 4, 8:
+	default:
+		return e, fmt.Errorf("invalid EINK_DEPTH %d: want 1, 2, 4 or 8", e.Depth)
+	}
+	switch e.Format {
+	case "png", "bmp":
+	default:
+		return e, fmt.Errorf("invalid EINK_FORMAT %q: want png or bmp", e.Format)
+	}
+	return e, nil
+}
+
+func loadSpoolman() (SpoolmanSettings, error) {
+	sp := SpoolmanSettings{Name: getString("SPOOLMAN_NAME", "default")}
+
+	var err error
+	if sp.SpoolmanCredentials, err = loadSpoolmanCredentials("SPOOLMAN_"); err != nil {
+		return sp, err
+	}
+
+	for i := 2; i <= maxSpoolmen; i++ {
+		prefix := fmt.Sprintf("SPOOLMAN_%d_", i)
+		inst := SpoolmanInstance{
+			Name: os.Getenv(prefix + "NAME"),
+			URL:  os.Getenv(prefix + "URL"),
+		}
+		if inst.URL == "" {
+			continue
+		}
+		if inst.Name == "" {
+			inst.Name = fmt.Sprintf("spoolman-%d", i)
+		}
+		if sp.Has(inst.Name) {
+			return sp, fmt.Errorf("duplicate Spoolman instance name %q", inst.Name)
+		}
+		if inst.SpoolmanCredentials, err = loadSpoolmanCredentials(prefix); err != nil {
+			return sp, err
+		}
+		sp.Instances = append(sp.Instances, inst)
+	}
+
+	return sp, nil
+}
+
+func loadSpoolmanCredentials(prefix string) (SpoolmanCredentials, error) {
+	c := SpoolmanCredentials{
+		Token:    os.Getenv(prefix + "TOKEN"),
+		Username: os.Getenv(prefix + "USERNAME"),
+		Password: os.Getenv(prefix + "PASSWORD"),
+	}
+	if c.Token != "" && c.Username != "" {
+		return c, fmt.Errorf("set either %sTOKEN or %sUSERNAME, not both", prefix, prefix)
+	}
+	if c.Password != "" && c.Username == "" {
+		return c, fmt.Errorf("%sPASSWORD requires %sUSERNAME", prefix, prefix)
+	}
+	return c, nil
+}
+
+// Has reports whether name is a configured Spoolman instance
+func (sp SpoolmanSettings) Has(name string) bool {
+	if name == sp.Name {
+		return true
+	}
+	for _, inst := range sp.Instances {
+		if inst.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// envPrefixes lists the environment variables that make up OctoDash's configuration
+var envPrefixes = []string{
+	"PORT=", "LISTEN_ADDR=", "DATA_DIR=", "LOG_REQUESTS=", "PUBLIC_URL=", "MATERIAL_COLORS=", "SPOOLMAN_", "PRINTER_",
+	"PREHEAT_", "UNITS_", "DISPLAY_TIMEZONE=", "IDLE_SCREEN_", "ENERGY_", "AUTH_USERS=",
+	"UPSTREAM_", "DNS_CACHE_TTL=", "HOST_OVERRIDES=", "DEMO", "EVENTS_", "BACKUP_",
+	"OCTOPRINT_BACKUP_", "UPDATES_", "PROFILING_", "POLL_",
+	"QUEUE_", "TAILSCALE_", "FORECAST_", "DRYING_", "SLICER_", "TEAMS_", "QUOTA_", "MACRO_", "WEBHOOK_", "RULE_",
+	"EXTENSIONS_", "EXT_", "HOMEASSISTANT_", "TRIGGERS_", "EINK_", "LIGHT_", "ANNOUNCE_",
+	"CAMERA_ARCHIVE_", "RETENTION_", "DATABASE_", "ASSETS_", "SECURITY_", "GRPC_",
+	"THERMAL_", "FIRST_LAYER_", "STALL_", "PROXY_", "SSO_", "HANDOVER_", "PART_LABEL_",
+}
+
+// Environ returns the OctoDash-related environment as sorted KEY=value lines
+func Environ() []string {
+	var env []string
+	for _, kv := range os.Environ() {
+		for _, prefix := range envPrefixes {
+			if strings.HasPrefix(kv, prefix) {
+				env = append(env, kv)
+				break
+			}
+		}
+	}
+	sort.Strings(env)
+	return env
+}
+
+// secretMarkers identify environment variables whose values must never be shown
+var secretMarkers = []string{"KEY", "TOKEN", "SECRET", "PASSWORD", "AUTH_USERS"}
+
+// RedactedEnviron is Environ with secret values and URL credentials hidden
+func RedactedEnviron() []string {
+	env := Environ()
+	for i, kv := range env {
+		key, value, _ := strings.Cut(kv, "=")
+		env[i] = key + "=" + redact(key, value)
+	}
+	return env
+}
+
+func redact(key, value string) string {
+	for _, marker := range secretMarkers {
+		if strings.Contains(key, marker) {
+			if value == "" {
+				return ""
+			}
+			return "[redacted]"
+		}
+	}
+	if u, err := url.Parse(value); err == nil && u.User != nil {
+		return u.Redacted()
+	}
+	return value
+}
+
+// parseDailyWindow reads "23:00-07:00"
+func parseDailyWindow(v string) (*DailyWindow, error) {
+	start, end, ok := strings.Cut(v, "-")
+	if !ok {
+		return nil, fmt.Errorf("%q is not a HH:MM-HH:MM range", v)
+	}
+	var w DailyWindow
+	for _, part := range []struct {
+		text string
+		dest *time.Duration
+	}{{start, &w.Start}, {end, &w.End}} {
+		t, err := time.Parse("15:04", strings.TrimSpace(part.text))
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a HH:MM-HH:MM range", v)
+		}
+		*part.dest = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	}
+	if w.Start == w.End {
+		return nil, fmt.Errorf("%q is an empty range", v)
+	}
+	return &w, nil
+}
+
+// parseHostOverrides parses a comma-separated list of host=ip pins
+func parseHostOverrides(v string) (map[string]string, error) {
+	overrides := make(map[string]string)
+	for _, item := range splitList(v) {
+		host, ip, ok := strings.Cut(item, "=")
+		host, ip = strings.ToLower(strings.TrimSpace(host)), strings.TrimSpace(ip)
+		if !ok || host == "" || net.ParseIP(ip) == nil {
+			return nil, fmt.Errorf("invalid HOST_OVERRIDES entry %q, expected host=ip", item)
+		}
+		overrides[host] = ip
+	}
+	return overrides, nil
+}
+
+// ParseTags parses a comma-separated list of key=value tags; a bare key has an empty value
+func ParseTags(v string) map[string]string {
+	tags := make(map[string]string)
+	for _, item := range splitList(v) {
+		key, value, _ := strings.Cut(item, "=")
+		tags[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+	}
+	return tags
+}
+
+// PrinterID returns the ID config.LoadConfig assigns to the printer in slot i
+func PrinterID(i int) string {
+	return fmt.Sprintf("printer-%d", i)
+}
+
+func getString(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+func getDuration(key string, def time.Duration) (time.Duration, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return def, nil
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return d, nil
+}
+
+func getFloat(key string, def float64) (float64, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return def, nil
+	}
+
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return f, nil
+}
+
+func getBool(key string, def bool) (bool, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return def, nil
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return b, nil
+}
+
+func getInt(key string, def int) (int, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return def, nil
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s: %q", key, v)
+	}
+	return n, nil
+}
+
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,156 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package slicer runs an external slicer, such as the PrusaSlicer CLI or a
+// container wrapping it, to turn uploaded models into gcode.
+package slicer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/wmarchesi123/octodash/internal/settings"
+)
+
+// ErrDisabled is returned when no SLICER_COMMAND is configured
+var ErrDisabled = errors.New("slicing is not configured; set SLICER_COMMAND")
+
+// ErrUnknownProfile is returned for a profile with no file in the profile directory
+var ErrUnknownProfile = errors.New("unknown slicer profile")
+
+// maxOutput bounds how much of a failed run's output is reported
+const maxOutput = 2048
+
+// maxRunning bounds how many slicer processes run at once; slicing is heavy
+// and a batch of uploads would otherwise swamp a small host
+const maxRunning = 1
+
+// Slicer invokes the configured command for each job
+type Slicer struct {
+	settings settings.SlicerSettings
+	running  chan struct{}
+}
+
+// New creates a Slicer from settings
+func New(s settings.SlicerSettings) *Slicer {
+	return &Slicer{settings: s, running: make(chan struct{}, maxRunning)}
+}
+
+// Enabled reports whether a slicer command is configured
+func (s *Slicer) Enabled() bool {
+	return len(s.settings.Command) > 0
+}
+
+// Profiles lists the available profile names in sorted order
+func (s *Slicer) Profiles() ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(s.settings.ProfileDir, "*.ini"))
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := []string{}
+	for _, m := range matches {
+		profiles = append(profiles, strings.TrimSuffix(filepath.Base(m), ".ini"))
+	}
+	sort.Strings(profiles)
+	return profiles, nil
+}
+
+// HasProfile reports whether a profile with this name exists
+func (s *Slicer) HasProfile(profile string) bool {
+	_, err := s.profilePath(profile)
+	return err == nil
+}
+
+// profilePath returns the file for a profile name, refusing anything that is
+// not a plain name inside the profile directory
+func (s *Slicer) profilePath(profile string) (string, error) {
+	if profile == "" || strings.HasPrefix(profile, ".") || strings.ContainsAny(profile, `/\`) {
+		return "", ErrUnknownProfile
+	}
+	path := filepath.Join(s.settings.ProfileDir, profile+".ini")
+	if _, err := os.Stat(path); err != nil {
+		return "", ErrUnknownProfile
+	}
+	return path, nil
+}
+
+// Slice runs the slicer on input with the named profile, writing gcode to
+// output. Jobs wait their turn, and the timeout only starts once one runs.
+func (s *Slicer) Slice(ctx context.Context, input, output, profile string) error {
+	if !s.Enabled() {
+		return ErrDisabled
+	}
+	profilePath, err := s.profilePath(profile)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case s.running <- struct{}{}:
+		defer func() { <-s.running }()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.settings.Timeout)
+	defer cancel()
+
+	replacer := strings.NewReplacer(
+		"{input}", input,
+		"{output}", output,
+		"{profile}", profilePath,
+		"{dir}", filepath.Dir(output),
+	)
+	args := make([]string, len(s.settings.Command))
+	for i, arg := range s.settings.Command {
+		args[i] = replacer.Replace(arg)
+	}
+
+	var out bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("slicer timed out after %s", s.settings.Timeout)
+		}
+		return fmt.Errorf("slicer failed: %v: %s", err, tail(out.String()))
+	}
+
+	if info, err := os.Stat(output); err != nil || info.Size() == 0 {
+		return fmt.Errorf("slicer produced no gcode: %s", tail(out.String()))
+	}
+	return nil
+}
+
+// tail keeps the end of the slicer's output, where the error usually is
+func tail(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) > maxOutput {
+		s = "..." + s[len(s)-maxOutput:]
+	}
+	return s
+}
@@ -0,0 +1,119 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package spoolapi is a Spoolman client that can authenticate, for instances
+// behind a reverse proxy that asks for a bearer token or basic auth
+package spoolapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/wmarchesi123/go-3dprint-client/spoolman"
+)
+
+// errNotFound is a 404 from Spoolman, which for a single spool means it doesn't exist
+var errNotFound = errors.New("HTTP 404: not found")
+
+// Credentials authenticate requests to Spoolman. Token is sent as a bearer
+// token; otherwise Username and Password are sent as basic auth.
+type Credentials struct {
+	Token    string
+	Username string
+	Password string
+}
+
+// Client reads spools from Spoolman, matching go-3dprint-client's spoolman.Client
+type Client struct {
+	baseURL    string
+	creds      Credentials
+	httpClient *http.Client
+}
+
+// NewClient creates a Spoolman client that sends creds with every request
+func NewClient(baseURL string, creds Credentials) *Client {
+	return &Client{
+		baseURL: baseURL,
+		creds:   creds,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// GetSpool returns a single spool by ID
+func (c *Client) GetSpool(id string) (*spoolman.Spool, error) {
+	var spool spoolman.Spool
+	err := c.get("/api/v1/spool/"+url.PathEscape(id), &spool)
+	if errors.Is(err, errNotFound) {
+		return nil, fmt.Errorf("spool not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &spool, nil
+}
+
+// GetAllSpools returns every spool in the inventory
+func (c *Client) GetAllSpools() ([]spoolman.Spool, error) {
+	var spools []spoolman.Spool
+	err := c.get("/api/v1/spool", &spools)
+	return spools, err
+}
+
+// Check asks for a single spool, confirming Spoolman is reachable and accepts the credentials
+func (c *Client) Check() error {
+	var spools []spoolman.Spool
+	return c.get("/api/v1/spool?limit=1", &spools)
+}
+
+func (c *Client) get(path string, result interface{}) error {
+	req, err := http.NewRequest("GET", c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	switch {
+	case c.creds.Token != "":
+		req.Header.Set("Authorization", "Bearer "+c.creds.Token)
+	case c.creds.Username != "":
+		req.SetBasicAuth(c.creds.Username, c.creds.Password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return errNotFound
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("HTTP %d: Spoolman rejected the credentials", resp.StatusCode)
+	case resp.StatusCode >= 400:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"fmt"
+	"os"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	// maxProblems caps how many inconsistencies Check reports
+	maxProblems = 20
+	// compactTxSize is how much Compact copies per transaction
+	compactTxSize = 16 << 20
+)
+
+// Size reports the database file's size and how much of it is free pages
+// that Compact would reclaim
+func (s *Store) Size() (size, free int64, err error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return 0, 0, err
+	}
+	return info.Size(), int64(s.db.Stats().FreeAlloc), nil
+}
+
+// Check walks every page of the database looking for inconsistencies, such
+// as pages that are lost or referenced twice, and returns the first few found.
+// Writes wait until it finishes.
+func (s *Store) Check() ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// The checker reads the freelist, which commits modify, so hold the
+	// writer's lock for the walk and roll back without writing anything
+	tx, err := s.db.Begin(true)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var problems []string
+	// Drain the channel even past the cap so the checker can finish
+	for err := range tx.Check() {
+		if len(problems) < maxProblems {
+			problems = append(problems, err.Error())
+		}
+	}
+	return problems, nil
+}
+
+// Compact rewrites the database into a fresh file without free pages and
+// swaps it in, returning the file size before and after. Writes wait until
+// it finishes.
+func (s *Store) Compact() (before, after int64, err error) {
+	tmp := s.path + ".compact"
+	os.Remove(tmp)
+	dst, err := openDB(tmp)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info, err := os.Stat(s.path)
+	if err != nil {
+		dst.Close()
+		os.Remove(tmp)
+		return 0, 0, err
+	}
+	before = info.Size()
+
+	err = bolt.Compact(dst, s.db, compactTxSize)
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return before, before, fmt.Errorf("compact: %w", err)
+	}
+
+	if err := s.replace(tmp); err != nil {
+		return before, before, err
+	}
+
+	if info, err := os.Stat(s.path); err == nil {
+		after = info.Size()
+	}
+	return before, after, nil
+}
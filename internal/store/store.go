@@ -0,0 +1,340 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// FileName is the database file created inside the data directory
+const FileName = "octodash.db"
+
+// ErrUnavailable means a failed Restore or Compact could not reopen the
+// database; OctoDash has to be restarted
+var ErrUnavailable = errors.New("database unavailable")
+
+// Store persists OctoDash state as JSON documents in a bbolt database, one bucket per kind
+type Store struct {
+	path string
+
+	// mu guards db, which Restore swaps out
+	mu sync.RWMutex
+	db *bolt.DB
+}
+
+// Open opens (or creates) the database file inside dir
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create data dir: %w", err)
+	}
+
+	path := filepath.Join(dir, FileName)
+	db, err := openDB(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Store{path: path, db: db}, nil
+}
+
+func openDB(path string) (*bolt.DB, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	return db, nil
+}
+
+// Close closes the database
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.db.Close()
+}
+
+// Backup writes a consistent snapshot of the database to w
+func (s *Store) Backup(w io.Writer) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var n int64
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		n, err = tx.WriteTo(w)
+		return err
+	})
+	return n, err
+}
+
+// Restore replaces the database with a snapshot produced by Backup
+func (s *Store) Restore(r io.Reader) error {
+	tmp := s.path + ".restore"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, r)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	// Make sure the snapshot is a usable database before touching the live one
+	check, err := bolt.Open(tmp, 0o600, &bolt.Options{Timeout: time.Second, ReadOnly: true})
+	if err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("invalid database snapshot: %w", err)
+	}
+	check.Close()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.replace(tmp)
+}
+
+// replace swaps the database file for the one at tmp; the caller holds mu.
+// bbolt locks the file it opens, so the old database is closed first but its
+// file is kept aside until the new one opens, and restored if it does not.
+// When even the old file cannot be reopened every later operation fails with
+// bbolt's ErrDatabaseNotOpen and ErrUnavailable is returned.
+func (s *Store) replace(tmp string) error {
+	defer os.Remove(tmp)
+
+	if err := s.db.Close(); err != nil {
+		return err
+	}
+	prev := s.path + ".prev"
+	if err := os.Rename(s.path, prev); err != nil {
+		return s.reopen(err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		os.Rename(prev, s.path)
+		return s.reopen(err)
+	}
+	db, err := openDB(s.path)
+	if err != nil {
+		os.Rename(prev, s.path)
+		return s.reopen(err)
+	}
+	os.Remove(prev)
+	s.db = db
+	return nil
+}
+
+// reopen falls back to the database file in place after a failed swap
+func (s *Store) reopen(cause error) error {
+	db, err := openDB(s.path)
+	if err != nil {
+		return fmt.Errorf("%w: %v; %v", ErrUnavailable, cause, err)
+	}
+	s.db = db
+	return cause
+}
+
+// NextID returns the next sequence number for a bucket, formatted so keys sort in insertion order
+func (s *Store) NextID(bucket string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var id string
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(bucket))
+		if err != nil {
+			return err
+		}
+
+		seq, err := b.NextSequence()
+		if err != nil {
+			return err
+		}
+		id = fmt.Sprintf("%010d", seq)
+		return nil
+	})
+	return id, err
+}
+
+// Put stores v under key
+func (s *Store) Put(bucket, key string, v interface{}) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(bucket))
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte(key), data)
+	})
+}
+
+// Get loads the value under key into v, reporting whether it existed
+func (s *Store) Get(bucket, key string, v interface{}) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var data []byte
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucket))
+		if b == nil {
+			return nil
+		}
+		if raw := b.Get([]byte(key)); raw != nil {
+			data = append([]byte(nil), raw...)
+		}
+		return nil
+	})
+	if err != nil || data == nil {
+		return false, err
+	}
+
+	return true, json.Unmarshal(data, v)
+}
+
+// Delete removes key from a bucket
+func (s *Store) Delete(bucket, key string) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucket))
+		if b == nil {
+			return nil
+		}
+		return b.Delete([]byte(key))
+	})
+}
+
+// List returns every value in a bucket in key order
+func List[T any](s *Store, bucket string) ([]T, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var items []T
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucket))
+		if b == nil {
+			return nil
+		}
+
+		return b.ForEach(func(k, v []byte) error {
+			var item T
+			if err := json.Unmarshal(v, &item); err != nil {
+				return fmt.Errorf("decode %s/%s: %w", bucket, k, err)
+			}
+			items = append(items, item)
+			return nil
+		})
+	})
+	return items, err
+}
+
+// Buckets returns the names of the buckets starting with prefix, in order
+func (s *Store) Buckets(prefix string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var names []string
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(name []byte, _ *bolt.Bucket) error {
+			if strings.HasPrefix(string(name), prefix) {
+				names = append(names, string(name))
+			}
+			return nil
+		})
+	})
+	return names, err
+}
+
+// Scan calls fn with each key and raw JSON value in a bucket in key order;
+// value is only valid during the call
+func (s *Store) Scan(bucket string, fn func(key string, value []byte) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucket))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(k, v []byte) error {
+			return fn(string(k), v)
+		})
+	})
+}
+
+// DeleteKeys removes keys from a bucket in one transaction
+func (s *Store) DeleteKeys(bucket string, keys []string) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucket))
+		if b == nil {
+			return nil
+		}
+		for _, k := range keys {
+			if err := b.Delete([]byte(k)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Trim deletes the oldest keys in a bucket so at most keep remain
+func (s *Store) Trim(bucket string, keep int) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucket))
+		if b == nil {
+			return nil
+		}
+
+		excess := b.Stats().KeyN - keep
+		c := b.Cursor()
+		for k, _ := c.First(); k != nil && excess > 0; k, _ = c.Next() {
+			if err := c.Delete(); err != nil {
+				return err
+			}
+			excess--
+		}
+		return nil
+	})
+}
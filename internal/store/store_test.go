@@ -0,0 +1,155 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func openTest(t *testing.T) *Store {
+	t.Helper()
+	s, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func get(t *testing.T, s *Store, bucket, key string) string {
+	t.Helper()
+	var v string
+	if _, err := s.Get(bucket, key, &v); err != nil {
+		t.Fatalf("Get(%s, %s): %v", bucket, key, err)
+	}
+	return v
+}
+
+func TestRestore(t *testing.T) {
+	src := openTest(t)
+	src.Put("queue", "a", "from snapshot")
+	var snapshot bytes.Buffer
+	if _, err := src.Backup(&snapshot); err != nil {
+		t.Fatal(err)
+	}
+
+	s := openTest(t)
+	s.Put("queue", "a", "live")
+	s.Put("queue", "b", "live only")
+
+	if err := s.Restore(bytes.NewReader([]byte("not a database"))); err == nil {
+		t.Fatal("Restore accepted an invalid snapshot")
+	}
+	if got := get(t, s, "queue", "a"); got != "live" {
+		t.Errorf("after a rejected restore a = %q, want the live value", got)
+	}
+
+	if err := s.Restore(&snapshot); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if got := get(t, s, "queue", "a"); got != "from snapshot" {
+		t.Errorf("a = %q, want the snapshot's value", got)
+	}
+	if got := get(t, s, "queue", "b"); got != "" {
+		t.Errorf("b = %q survived the restore", got)
+	}
+	if err := s.Put("queue", "c", "after"); err != nil {
+		t.Errorf("Put after restore: %v", err)
+	}
+	leftovers(t, s)
+}
+
+func TestReplaceKeepsDatabaseWhenNewFileFails(t *testing.T) {
+	s := openTest(t)
+	s.Put("queue", "a", "live")
+
+	tmp := s.path + ".restore"
+	os.WriteFile(tmp, bytes.Repeat([]byte("x"), 8192), 0o600)
+	s.mu.Lock()
+	err := s.replace(tmp)
+	s.mu.Unlock()
+	if err == nil {
+		t.Fatal("replace opened a corrupt file")
+	}
+
+	if got := get(t, s, "queue", "a"); got != "live" {
+		t.Errorf("a = %q after a failed swap, want the old database", got)
+	}
+	if err := s.Put("queue", "b", "still writable"); err != nil {
+		t.Errorf("Put after a failed swap: %v", err)
+	}
+	leftovers(t, s)
+}
+
+func TestTrim(t *testing.T) {
+	s := openTest(t)
+	for i := 1; i <= 5; i++ {
+		s.Put("history", fmt.Sprintf("%02d", i), fmt.Sprint(i))
+	}
+
+	if err := s.Trim("history", 2); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := List[string](s, "history")
+	if len(got) != 2 || got[0] != "4" || got[1] != "5" {
+		t.Errorf("after Trim = %v, want the newest two", got)
+	}
+
+	if err := s.Trim("missing", 2); err != nil {
+		t.Errorf("Trim of a missing bucket: %v", err)
+	}
+}
+
+func TestCompact(t *testing.T) {
+	s := openTest(t)
+	blob := string(bytes.Repeat([]byte("x"), 4096))
+	for i := 0; i < 200; i++ {
+		s.Put("blobs", fmt.Sprintf("%03d", i), blob)
+	}
+	s.Put("queue", "a", "kept")
+	s.Trim("blobs", 0)
+
+	before, after, err := s.Compact()
+	if err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+	if after >= before {
+		t.Errorf("Compact went from %d to %d bytes", before, after)
+	}
+	if got := get(t, s, "queue", "a"); got != "kept" {
+		t.Errorf("a = %q after Compact", got)
+	}
+	if err := s.Put("queue", "b", "after"); err != nil {
+		t.Errorf("Put after Compact: %v", err)
+	}
+	leftovers(t, s)
+}
+
+// leftovers fails if a swap left temporary files next to the database
+func leftovers(t *testing.T, s *Store) {
+	t.Helper()
+	matches, _ := filepath.Glob(s.path + ".*")
+	if len(matches) > 0 {
+		t.Errorf("temporary files left behind: %v", matches)
+	}
+}
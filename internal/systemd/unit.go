@@ -0,0 +1,68 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package systemd
+
+import (
+	"bytes"
+	"text/template"
+)
+
+var unitTemplate = template.Must(template.New("unit").Parse(`# /etc/systemd/system/octodash.service
+[Unit]
+Description=OctoDash 3D printer dashboard
+Documentation=https://github.com/wmarchesi123/octodash
+After=network-online.target
+Wants=network-online.target
+
+[Service]
+Type=notify
+ExecStart={{.ExecPath}} serve
+EnvironmentFile=-/etc/octodash/octodash.env
+Environment=DATA_DIR=/var/lib/octodash
+StateDirectory=octodash
+DynamicUser=yes
+Restart=on-failure
+RestartSec=5s
+NoNewPrivileges=yes
+ProtectSystem=strict
+ProtectHome=yes
+PrivateTmp=yes
+
+[Install]
+WantedBy=multi-user.target
+
+# Optional socket activation: save as /etc/systemd/system/octodash.socket,
+# then "systemctl enable --now octodash.socket" instead of the service.
+#
+# [Unit]
+# Description=OctoDash socket
+#
+# [Socket]
+# ListenStream=8080
+#
+# [Install]
+# WantedBy=sockets.target
+`))
+
+// Unit renders a systemd service unit that runs the binary at execPath
+func Unit(execPath string) (string, error) {
+	var buf bytes.Buffer
+	err := unitTemplate.Execute(&buf, struct{ ExecPath string }{execPath})
+	return buf.String(), err
+}
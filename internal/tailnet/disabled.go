@@ -0,0 +1,35 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !tailscale
+
+package tailnet
+
+import (
+	"errors"
+
+	"github.com/wmarchesi123/octodash/internal/settings"
+)
+
+// Available reports whether this build can join a tailnet
+const Available = false
+
+// Start fails: this binary was built without the "tailscale" tag
+func Start(settings.TailscaleSettings) (*Node, error) {
+	return nil, errors.New("this build has no Tailscale support; rebuild with -tags tailscale")
+}
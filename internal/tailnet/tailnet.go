@@ -0,0 +1,46 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tailnet joins OctoDash to a Tailscale network with an embedded
+// tsnet node. The tsnet dependency is large, so it is only compiled in with
+// the "tailscale" build tag; other builds report that it is unavailable.
+package tailnet
+
+import (
+	"context"
+	"net"
+)
+
+// Node is OctoDash's presence on the tailnet
+type Node struct {
+	// Listener accepts dashboard connections from the tailnet
+	Listener net.Listener
+
+	dial  func(ctx context.Context, network, addr string) (net.Conn, error)
+	close func() error
+}
+
+// Dial connects to a tailnet address through the embedded node
+func (n *Node) Dial(ctx context.Context, network, addr string) (net.Conn, error) {
+	return n.dial(ctx, network, addr)
+}
+
+// Close leaves the tailnet
+func (n *Node) Close() error {
+	return n.close()
+}
@@ -0,0 +1,50 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build tailscale
+
+package tailnet
+
+import (
+	"log"
+
+	"github.com/wmarchesi123/octodash/internal/settings"
+	"tailscale.com/tsnet"
+)
+
+// Available reports whether this build can join a tailnet
+const Available = true
+
+// Start brings up the embedded node and listens for dashboard traffic on port 80
+func Start(s settings.TailscaleSettings) (*Node, error) {
+	srv := &tsnet.Server{
+		Hostname: s.Hostname,
+		AuthKey:  s.AuthKey,
+		Dir:      s.StateDir,
+		Logf:     func(string, ...any) {},
+		UserLogf: log.Printf,
+	}
+
+	l, err := srv.Listen("tcp", ":80")
+	if err != nil {
+		srv.Close()
+		return nil, err
+	}
+
+	return &Node{Listener: l, dial: srv.Dial, close: srv.Close}, nil
+}
@@ -0,0 +1,164 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package teams stores team membership and printer ownership for multi-tenant installs
+package teams
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/store"
+)
+
+const bucket = "teams"
+
+// ErrNotFound is returned when a team does not exist
+var ErrNotFound = errors.New("team not found")
+
+// ErrInvalidName is returned for names that are not short slugs
+var ErrInvalidName = errors.New("team names may only use letters, digits, '.', '-' and '_'")
+
+// ErrPrinterTaken is returned when a team claims a printer another team owns
+var ErrPrinterTaken = errors.New("printer belongs to another team")
+
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// Teams is the persistent set of teams, keyed by name
+type Teams struct {
+	store *store.Store
+}
+
+// New creates a Teams backed by the given store
+func New(s *store.Store) *Teams {
+	return &Teams{store: s}
+}
+
+// List returns every team in name order
+func (t *Teams) List() ([]models.Team, error) {
+	teams, err := store.List[models.Team](t.store, bucket)
+	if teams == nil {
+		teams = []models.Team{}
+	}
+	return teams, err
+}
+
+// Get returns a single team
+func (t *Teams) Get(name string) (models.Team, error) {
+	var team models.Team
+	ok, err := t.store.Get(bucket, name, &team)
+	if err != nil {
+		return team, err
+	}
+	if !ok {
+		return team, ErrNotFound
+	}
+	return team, nil
+}
+
+// Put creates or replaces a team. A printer can only belong to one team, so
+// claiming one another team owns is an error.
+func (t *Teams) Put(team models.Team, at time.Time) (models.Team, error) {
+	if !validName.MatchString(team.Name) {
+		return team, ErrInvalidName
+	}
+	if team.Printers == nil {
+		team.Printers = []string{}
+	}
+	if team.Members == nil {
+		team.Members = []string{}
+	}
+
+	all, err := t.List()
+	if err != nil {
+		return team, err
+	}
+	at = at.UTC()
+	team.CreatedAt, team.UpdatedAt = at, at
+	for _, other := range all {
+		if other.Name == team.Name {
+			team.CreatedAt = other.CreatedAt
+			continue
+		}
+		for _, id := range team.Printers {
+			if slices.Contains(other.Printers, id) {
+				return team, fmt.Errorf("%w: %s is in team %s", ErrPrinterTaken, id, other.Name)
+			}
+		}
+	}
+
+	return team, t.store.Put(bucket, team.Name, team)
+}
+
+// Delete removes a team
+func (t *Teams) Delete(name string) error {
+	if _, err := t.Get(name); err != nil {
+		return err
+	}
+	return t.store.Delete(bucket, name)
+}
+
+// Membership is a user's view of the teams: the teams they belong to and the
+// printers those teams own
+type Membership struct {
+	Teams    []string
+	Printers map[string]string // printer ID to owning team
+}
+
+// Has reports whether the membership includes a team
+func (m Membership) Has(team string) bool {
+	return slices.Contains(m.Teams, team)
+}
+
+// For returns a user's membership
+func (t *Teams) For(user string) (Membership, error) {
+	m := Membership{Printers: make(map[string]string)}
+	all, err := t.List()
+	if err != nil {
+		return m, err
+	}
+	for _, team := range all {
+		if !slices.Contains(team.Members, user) {
+			continue
+		}
+		m.Teams = append(m.Teams, team.Name)
+		for _, id := range team.Printers {
+			m.Printers[id] = team.Name
+		}
+	}
+	return m, nil
+}
+
+// Owners maps every printer that belongs to a team to the team's name
+func (t *Teams) Owners() (map[string]string, error) {
+	owners := make(map[string]string)
+	all, err := t.List()
+	if err != nil {
+		return owners, err
+	}
+	for _, team := range all {
+		for _, id := range team.Printers {
+			owners[id] = team.Name
+		}
+	}
+	return owners, nil
+}
@@ -0,0 +1,45 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testutil
+
+import (
+	"github.com/wmarchesi123/go-3dprint-client/config"
+	"github.com/wmarchesi123/octodash/internal/settings"
+)
+
+// Printer is a fake OctoPrint together with the name it is configured under
+type Printer struct {
+	Name   string
+	Server *OctoPrint
+}
+
+// Config returns printer config pointing at the fake servers. Printers are
+// numbered in order as printer-1, printer-2, ...
+func Config(spoolman *Spoolman, printers ...Printer) *config.Config {
+	cfg := &config.Config{SpoolmanURL: spoolman.URL}
+	for i, p := range printers {
+		cfg.Printers = append(cfg.Printers, config.Printer{
+			ID:           settings.PrinterID(i + 1),
+			Name:         p.Name,
+			OctoPrintURL: p.Server.URL,
+			APIKey:       "test-key",
+		})
+	}
+	return cfg
+}
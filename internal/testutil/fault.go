@@ -0,0 +1,102 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Fault scripts a misbehaving upstream; the zero value is a healthy server
+type Fault struct {
+	// Delay is slept before every response
+	Delay time.Duration
+	// StatusCode, when set, replaces every response with that error status
+	StatusCode int
+	// PartialJSON cuts JSON bodies off halfway through
+	PartialJSON bool
+}
+
+// faults holds the active fault for a fake server
+type faults struct {
+	mu    sync.Mutex
+	fault Fault
+}
+
+func (f *faults) set(fault Fault) {
+	f.mu.Lock()
+	f.fault = fault
+	f.mu.Unlock()
+}
+
+func (f *faults) get() Fault {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.fault
+}
+
+// wrap applies the active fault before next runs
+func (f *faults) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fault := f.get()
+
+		if fault.Delay > 0 {
+			select {
+			case <-time.After(fault.Delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		if fault.StatusCode != 0 {
+			http.Error(w, http.StatusText(fault.StatusCode), fault.StatusCode)
+			return
+		}
+
+		if fault.PartialJSON {
+			next.ServeHTTP(&truncatingWriter{ResponseWriter: w}, r)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// truncatingWriter writes only the first half of each body
+type truncatingWriter struct {
+	http.ResponseWriter
+}
+
+func (t *truncatingWriter) Write(b []byte) (int, error) {
+	if _, err := t.ResponseWriter.Write(b[:len(b)/2]); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	var buf bytes.Buffer
+	json.NewEncoder(&buf).Encode(v)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(buf.Bytes())
+}
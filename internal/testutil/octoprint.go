@@ -0,0 +1,641 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/wmarchesi123/go-3dprint-client/octoprint"
+	"github.com/wmarchesi123/octodash/internal/octoapi"
+)
+
+// OctoPrint is a scripted fake OctoPrint instance
+type OctoPrint struct {
+	*httptest.Server
+	faults
+
+	mu       sync.Mutex
+	printer  octoprint.PrinterResponse
+	job      octoprint.JobResponse
+	spoolID  string
+	commands []string
+	requests map[string]int
+	backups  []string
+	created  int
+	updates  []octoapi.SoftwareUpdate
+	firmware string
+	plugins  []octoapi.Plugin
+	spoolErr string
+	printed  []string
+	uploads  map[string][]byte
+	apiKey   string
+	layer    octoapi.LayerProgress
+	appKey   string // issued once an app key request is approved
+	denied   bool
+
+	timelapses map[string][]byte
+	history    map[string]interface{}
+}
+
+// NewOctoPrint starts an idle, connected fake OctoPrint that is closed when the test ends
+func NewOctoPrint(t testing.TB) *OctoPrint {
+	o := &OctoPrint{requests: make(map[string]int), uploads: make(map[string][]byte), timelapses: make(map[string][]byte), history: make(map[string]interface{})}
+	o.SetIdle()
+	o.SetPlugins(
+		octoapi.Plugin{Key: "spoolman_api", Name: "Spoolman API", Version: "1.0.0", Enabled: true},
+		octoapi.Plugin{Key: "prusaslicerthumbnails", Name: "Slicer Thumbnails", Version: "1.0.7", Enabled: true},
+		octoapi.Plugin{Key: "DisplayLayerProgress", Name: "DisplayLayerProgress", Version: "1.28.0", Enabled: true},
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/printer", o.handlePrinter)
+	mux.HandleFunc("GET /api/job", o.handleJob)
+	mux.HandleFunc("POST /api/job", o.handleCommand)
+	mux.HandleFunc("POST /api/printer/command", o.handleCommand)
+	mux.HandleFunc("POST /api/printer/tool", o.handleCommand)
+	mux.HandleFunc("POST /api/printer/bed", o.handleCommand)
+	mux.HandleFunc("POST /api/files/local", o.handleUpload)
+	mux.HandleFunc("POST /api/files/local/{path...}", o.handlePrintFile)
+	mux.HandleFunc("GET /downloads/files/local/{path...}", o.handleDownloadFile)
+	mux.HandleFunc("POST /api/plugin/spoolman_api", o.handleSpoolman)
+	mux.HandleFunc("POST /api/plugin/excluderegion", o.handleCommand)
+	mux.HandleFunc("GET /api/connection", o.handleConnection)
+	mux.HandleFunc("GET /api/version", o.handleVersion)
+	mux.HandleFunc("POST /plugin/appkeys/request", o.handleAppKeyRequest)
+	mux.HandleFunc("GET /plugin/appkeys/request/{token}", o.handleAppKeyDecision)
+	mux.HandleFunc("GET /plugin/pluginmanager/plugins", o.handlePlugins)
+	mux.HandleFunc("GET /plugin/DisplayLayerProgress/values", o.handleLayerProgress)
+	mux.HandleFunc("GET /plugin/softwareupdate/check", o.handleSoftwareUpdates)
+	mux.HandleFunc("GET /api/system/info", o.handleSystemInfo)
+	mux.HandleFunc("GET /plugin/backup/", o.handleBackupState)
+	mux.HandleFunc("POST /plugin/backup/backup", o.handleCreateBackup)
+	mux.HandleFunc("DELETE /plugin/backup/backup/{name}", o.handleDeleteBackup)
+	mux.HandleFunc("GET /plugin/backup/download/{name}", o.handleDownloadBackup)
+	mux.HandleFunc("GET /api/timelapse", o.handleTimelapses)
+	mux.HandleFunc("GET /downloads/timelapse/{name}", o.handleDownloadTimelapse)
+	mux.HandleFunc("DELETE /api/timelapse/{name}", o.handleDeleteTimelapse)
+	mux.HandleFunc("GET /webcam/", o.handleSnapshot)
+	mux.HandleFunc("GET /plugin/PrintJobHistory/loadPrintJobHistoryByQuery", o.handleHistory)
+	mux.HandleFunc("GET /plugin/printhistory/history", o.handleHistory)
+
+	o.Server = httptest.NewServer(o.count(o.faults.wrap(o.checkKey(mux))))
+	t.Cleanup(o.Close)
+	return o
+}
+
+// SetIdle reports a connected printer with nothing printing
+func (o *OctoPrint) SetIdle() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.printer.State = octoprint.PrinterState{Text: "Operational"}
+	o.printer.State.Flags.Operational = true
+	o.printer.State.Flags.Ready = true
+	o.job = octoprint.JobResponse{State: "Operational"}
+}
+
+// SetPrinting reports a print of file at the given completion percentage; for
+// stored files the file position follows it
+func (o *OctoPrint) SetPrinting(file string, completion float64, timeLeft int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.printer.State = octoprint.PrinterState{Text: "Printing"}
+	o.printer.State.Flags.Operational = true
+	o.printer.State.Flags.Printing = true
+
+	o.job = octoprint.JobResponse{State: "Printing"}
+	o.job.Job.File = octoprint.JobFile{Name: file, Display: file, Path: file, Origin: "local"}
+	o.job.Job.EstimatedPrintTime = 3600
+	o.job.Progress.Completion = completion
+	if data, ok := o.uploads[file]; ok {
+		o.job.Job.File.Size = int64(len(data))
+		o.job.Progress.Filepos = int64(completion / 100 * float64(len(data)))
+	}
+	o.job.Progress.PrintTime = int(completion / 100 * 3600)
+	o.job.Progress.PrintTimeLeft = timeLeft
+}
+
+// SetPaused reports the current print as paused
+func (o *OctoPrint) SetPaused() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.printer.State = octoprint.PrinterState{Text: "Paused"}
+	o.printer.State.Flags.Operational = true
+	o.printer.State.Flags.Paused = true
+	o.job.State = "Paused"
+}
+
+// SetFinished reports a connected printer whose last job ran to completion
+func (o *OctoPrint) SetFinished(file string) {
+	o.SetPrinting(file, 100, 0)
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.printer.State = octoprint.PrinterState{Text: "Operational"}
+	o.printer.State.Flags.Operational = true
+	o.printer.State.Flags.Ready = true
+	o.job.State = "Operational"
+}
+
+// SetCancelled reports a connected printer whose last job was cancelled,
+// keeping the job's file and progress as OctoPrint does
+func (o *OctoPrint) SetCancelled() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.printer.State = octoprint.PrinterState{Text: "Operational"}
+	o.printer.State.Flags.Operational = true
+	o.printer.State.Flags.Ready = true
+	o.job.State = "Operational"
+}
+
+// SetError reports a printer in an error state
+func (o *OctoPrint) SetError(text string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.printer.State = octoprint.PrinterState{Text: text}
+	o.printer.State.Flags.Error = true
+	o.printer.State.Flags.ClosedOrError = true
+	o.job = octoprint.JobResponse{State: text}
+}
+
+// SetTemperatures sets actual and target temperatures for tool0 and the bed
+func (o *OctoPrint) SetTemperatures(hotend, hotendTarget, bed, bedTarget float64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.printer.Temperature.Tool0 = octoprint.TemperatureData{Actual: hotend, Target: hotendTarget}
+	o.printer.Temperature.Bed = octoprint.TemperatureData{Actual: bed, Target: bedTarget}
+}
+
+// SetSpool sets the spool the Spoolman plugin reports as loaded
+func (o *OctoPrint) SetSpool(id string) {
+	o.mu.Lock()
+	o.spoolID = id
+	o.mu.Unlock()
+}
+
+// SetFault scripts how the server misbehaves from now on
+func (o *OctoPrint) SetFault(f Fault) {
+	o.faults.set(f)
+}
+
+// Commands returns the raw JSON bodies of every command POSTed so far
+func (o *OctoPrint) Commands() []string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append([]string(nil), o.commands...)
+}
+
+// SetPlugins replaces the installed plugins; the default is every plugin OctoDash uses, enabled
+func (o *OctoPrint) SetPlugins(plugins ...octoapi.Plugin) {
+	o.mu.Lock()
+	o.plugins = plugins
+	o.mu.Unlock()
+}
+
+// SetSpoolmanError makes the Spoolman plugin fail with msg, as it does when it cannot reach Spoolman
+func (o *OctoPrint) SetSpoolmanError(msg string) {
+	o.mu.Lock()
+	o.spoolErr = msg
+	o.mu.Unlock()
+}
+
+// SetSoftwareUpdates sets the components the softwareupdate plugin reports
+func (o *OctoPrint) SetSoftwareUpdates(updates ...octoapi.SoftwareUpdate) {
+	o.mu.Lock()
+	o.updates = updates
+	o.mu.Unlock()
+}
+
+// SetFirmware sets the firmware name reported in system info
+func (o *OctoPrint) SetFirmware(firmware string) {
+	o.mu.Lock()
+	o.firmware = firmware
+	o.mu.Unlock()
+}
+
+// Backups returns the names of the backups the backup plugin currently holds
+func (o *OctoPrint) Backups() []string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append([]string(nil), o.backups...)
+}
+
+// BackupContent is the archive body served for a backup name
+func BackupContent(name string) string {
+	return "backup archive " + name
+}
+
+// SetAPIKey makes the server reject requests that don't carry key; empty accepts any
+func (o *OctoPrint) SetAPIKey(key string) {
+	o.mu.Lock()
+	o.apiKey = key
+	o.mu.Unlock()
+}
+
+// ApproveAppKey approves pending app key requests, issuing key
+func (o *OctoPrint) ApproveAppKey(key string) {
+	o.mu.Lock()
+	o.appKey, o.denied = key, false
+	o.mu.Unlock()
+}
+
+// DenyAppKey denies pending app key requests
+func (o *OctoPrint) DenyAppKey() {
+	o.mu.Lock()
+	o.appKey, o.denied = "", true
+	o.mu.Unlock()
+}
+
+// Printed returns the files started through the files API, in order
+func (o *OctoPrint) Printed() []string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append([]string(nil), o.printed...)
+}
+
+// Uploaded returns the contents of a file uploaded to local storage, or nil
+func (o *OctoPrint) Uploaded(name string) []byte {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.uploads[name]
+}
+
+// SetLayer sets what DisplayLayerProgress reports for the running print
+func (o *OctoPrint) SetLayer(layer, layers int, height float64) {
+	o.mu.Lock()
+	o.layer = octoapi.LayerProgress{Layer: layer, Layers: layers, Height: height}
+	o.mu.Unlock()
+}
+
+// SetFile stores a file in local storage, as if it had been uploaded
+func (o *OctoPrint) SetFile(name string, data []byte) {
+	o.mu.Lock()
+	o.uploads[name] = data
+	o.mu.Unlock()
+}
+
+// SetTimelapse stores a rendered timelapse video
+func (o *OctoPrint) SetTimelapse(name string, data []byte) {
+	o.mu.Lock()
+	o.timelapses[name] = data
+	o.mu.Unlock()
+}
+
+// Timelapses returns the names of the timelapses still held, sorted
+func (o *OctoPrint) Timelapses() []string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	names := make([]string, 0, len(o.timelapses))
+	for name := range o.timelapses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetHistory installs a job history plugin ("PrintJobHistory" or
+// "printhistory") answering with body
+func (o *OctoPrint) SetHistory(plugin string, body interface{}) {
+	o.mu.Lock()
+	o.history[plugin] = body
+	o.mu.Unlock()
+}
+
+// Snapshot is the JPEG served by the fake webcam
+var Snapshot = []byte("\xff\xd8\xff\xe0 fake jpeg \xff\xd9")
+
+// Requests returns how many times "METHOD /path" was requested
+func (o *OctoPrint) Requests(route string) int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.requests[route]
+}
+
+func (o *OctoPrint) count(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		o.mu.Lock()
+		o.requests[r.Method+" "+r.URL.Path]++
+		o.mu.Unlock()
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (o *OctoPrint) checkKey(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		o.mu.Lock()
+		key := o.apiKey
+		o.mu.Unlock()
+		if key != "" && r.Header.Get("X-Api-Key") != key {
+			http.Error(w, "Invalid API key", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (o *OctoPrint) handleVersion(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]string{"api": "0.1", "server": "1.10.2", "text": "OctoPrint 1.10.2"})
+}
+
+func (o *OctoPrint) handleAppKeyRequest(w http.ResponseWriter, r *http.Request) {
+	o.mu.Lock()
+	o.appKey, o.denied = "", false
+	o.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(octoapi.AppKeyRequest{AppToken: "app-token", AuthDialog: o.URL + "/plugin/appkeys/auth/app-token"})
+}
+
+func (o *OctoPrint) handleAppKeyDecision(w http.ResponseWriter, r *http.Request) {
+	o.mu.Lock()
+	key, denied := o.appKey, o.denied
+	o.mu.Unlock()
+
+	switch {
+	case r.PathValue("token") != "app-token" || denied:
+		http.NotFound(w, r)
+	case key == "":
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		writeJSON(w, map[string]string{"api_key": key})
+	}
+}
+
+func (o *OctoPrint) handlePrinter(w http.ResponseWriter, r *http.Request) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	writeJSON(w, o.printer)
+}
+
+func (o *OctoPrint) handleJob(w http.ResponseWriter, r *http.Request) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	writeJSON(w, o.job)
+}
+
+func (o *OctoPrint) handleCommand(w http.ResponseWriter, r *http.Request) {
+	var body json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid body", http.StatusBadRequest)
+		return
+	}
+
+	o.mu.Lock()
+	o.commands = append(o.commands, string(body))
+	o.mu.Unlock()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (o *OctoPrint) handlePrintFile(w http.ResponseWriter, r *http.Request) {
+	file := r.PathValue("path")
+	o.SetPrinting(file, 0, 3600)
+
+	o.mu.Lock()
+	o.printed = append(o.printed, file)
+	o.mu.Unlock()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (o *OctoPrint) handleDownloadFile(w http.ResponseWriter, r *http.Request) {
+	o.mu.Lock()
+	data, ok := o.uploads[r.PathValue("path")]
+	o.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Write(data)
+}
+
+func (o *OctoPrint) handleUpload(w http.ResponseWriter, r *http.Request) {
+	f, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer f.Close()
+	data, _ := io.ReadAll(f)
+
+	o.mu.Lock()
+	o.uploads[header.Filename] = data
+	o.mu.Unlock()
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (o *OctoPrint) handleSpoolman(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Command string `json:"command"`
+		SpoolID string `json:"spool_id"`
+	}
+	json.NewDecoder(r.Body).Decode(&body)
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.spoolErr != "" {
+		writeJSON(w, map[string]interface{}{"success": false, "error": o.spoolErr})
+		return
+	}
+	if body.Command == "set_spool" {
+		o.spoolID = body.SpoolID
+		writeJSON(w, map[string]interface{}{"success": true})
+		return
+	}
+	writeJSON(w, map[string]interface{}{"success": true, "spool_id": o.spoolID})
+}
+
+func (o *OctoPrint) handleConnection(w http.ResponseWriter, r *http.Request) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	state := "Closed"
+	if o.printer.State.Flags.Operational {
+		state = o.printer.State.Text
+	}
+	writeJSON(w, map[string]interface{}{"current": map[string]interface{}{"state": state}})
+}
+
+func (o *OctoPrint) handleLayerProgress(w http.ResponseWriter, r *http.Request) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	writeJSON(w, map[string]interface{}{
+		"layer":  map[string]string{"current": strconv.Itoa(o.layer.Layer), "total": strconv.Itoa(o.layer.Layers)},
+		"height": map[string]string{"current": strconv.FormatFloat(o.layer.Height, 'f', 2, 64)},
+	})
+}
+
+func (o *OctoPrint) handlePlugins(w http.ResponseWriter, r *http.Request) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	plugins := append([]octoapi.Plugin{}, o.plugins...)
+	writeJSON(w, map[string]interface{}{"plugins": plugins})
+}
+
+func (o *OctoPrint) handleSoftwareUpdates(w http.ResponseWriter, r *http.Request) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	information := make(map[string]interface{})
+	for _, u := range o.updates {
+		information[u.ID] = map[string]interface{}{
+			"displayName":     u.Name,
+			"displayVersion":  u.Installed,
+			"updateAvailable": u.UpdateAvailable,
+			"information": map[string]interface{}{
+				"local":  map[string]string{"name": u.Installed, "value": u.Installed},
+				"remote": map[string]string{"name": u.Available, "value": u.Available},
+			},
+		}
+	}
+	writeJSON(w, map[string]interface{}{"status": "current", "busy": false, "information": information})
+}
+
+func (o *OctoPrint) handleSystemInfo(w http.ResponseWriter, r *http.Request) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	writeJSON(w, map[string]interface{}{"systeminfo": map[string]interface{}{"printer.firmware": o.firmware}})
+}
+
+func (o *OctoPrint) handleBackupState(w http.ResponseWriter, r *http.Request) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	backups := make([]map[string]interface{}, 0, len(o.backups))
+	for _, name := range o.backups {
+		backups = append(backups, map[string]interface{}{"name": name, "size": len(BackupContent(name))})
+	}
+	writeJSON(w, map[string]interface{}{"backups": backups, "backup_in_progress": false})
+}
+
+func (o *OctoPrint) handleCreateBackup(w http.ResponseWriter, r *http.Request) {
+	o.mu.Lock()
+	o.created++
+	name := fmt.Sprintf("octoprint-backup-%d.zip", o.created)
+	o.backups = append(o.backups, name)
+	o.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{"started": true, "name": name})
+}
+
+func (o *OctoPrint) handleDeleteBackup(w http.ResponseWriter, r *http.Request) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for i, name := range o.backups {
+		if name == r.PathValue("name") {
+			o.backups = append(o.backups[:i], o.backups[i+1:]...)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+	http.NotFound(w, r)
+}
+
+func (o *OctoPrint) handleDownloadBackup(w http.ResponseWriter, r *http.Request) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for _, name := range o.backups {
+		if name == r.PathValue("name") {
+			w.Header().Set("Content-Type", "application/zip")
+			io.WriteString(w, BackupContent(name))
+			return
+		}
+	}
+	http.NotFound(w, r)
+}
+
+func (o *OctoPrint) handleTimelapses(w http.ResponseWriter, r *http.Request) {
+	files := []octoapi.Timelapse{}
+	o.mu.Lock()
+	for name, data := range o.timelapses {
+		files = append(files, octoapi.Timelapse{Name: name, Bytes: int64(len(data)), Date: "2025-01-02 03:04"})
+	}
+	o.mu.Unlock()
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+
+	json.NewEncoder(w).Encode(map[string]interface{}{"files": files, "unrendered": []interface{}{}})
+}
+
+func (o *OctoPrint) handleDownloadTimelapse(w http.ResponseWriter, r *http.Request) {
+	o.mu.Lock()
+	data, ok := o.timelapses[r.PathValue("name")]
+	o.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "video/mp4")
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.Write(data)
+}
+
+func (o *OctoPrint) handleDeleteTimelapse(w http.ResponseWriter, r *http.Request) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if _, ok := o.timelapses[r.PathValue("name")]; !ok {
+		http.NotFound(w, r)
+		return
+	}
+	delete(o.timelapses, r.PathValue("name"))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (o *OctoPrint) handleSnapshot(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("action") != "snapshot" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "image/jpeg")
+	w.Write(Snapshot)
+}
+
+func (o *OctoPrint) handleHistory(w http.ResponseWriter, r *http.Request) {
+	o.mu.Lock()
+	body, ok := o.history[strings.Split(r.URL.Path, "/")[2]]
+	o.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	json.NewEncoder(w).Encode(body)
+}
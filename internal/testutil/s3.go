@@ -0,0 +1,162 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testutil
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// S3 is a fake S3-compatible server holding objects in memory, path-style
+type S3 struct {
+	*httptest.Server
+
+	// PageSize caps the keys returned per list request
+	PageSize int
+
+	mu      sync.Mutex
+	objects map[string]S3Object
+}
+
+// S3Object is a stored object, keyed by bucket/key
+type S3Object struct {
+	Data         []byte
+	ContentType  string
+	LastModified time.Time
+}
+
+// NewS3 starts an empty fake S3 server that is closed when the test ends
+func NewS3(t testing.TB) *S3 {
+	s := &S3{PageSize: 1000, objects: make(map[string]S3Object)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Keys returns the stored bucket/key names, sorted
+func (s *S3) Keys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := make([]string, 0, len(s.objects))
+	for k := range s.objects {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Object returns a stored object by bucket/key
+func (s *S3) Object(key string) (S3Object, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, ok := s.objects[key]
+	return o, ok
+}
+
+// SetObject stores an object directly, as if uploaded at modified
+func (s *S3) SetObject(key string, data []byte, modified time.Time) {
+	s.mu.Lock()
+	s.objects[key] = S3Object{Data: data, LastModified: modified}
+	s.mu.Unlock()
+}
+
+func (s *S3) serve(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=") || r.Header.Get("X-Amz-Date") == "" {
+		http.Error(w, "AccessDenied", http.StatusForbidden)
+		return
+	}
+	key := strings.TrimPrefix(r.URL.Path, "/")
+
+	switch r.Method {
+	case "PUT":
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.mu.Lock()
+		s.objects[key] = S3Object{Data: data, ContentType: r.Header.Get("Content-Type"), LastModified: time.Now().UTC()}
+		s.mu.Unlock()
+	case "DELETE":
+		s.mu.Lock()
+		delete(s.objects, key)
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	case "GET":
+		if r.URL.Query().Get("list-type") != "2" {
+			http.Error(w, "NotImplemented", http.StatusNotImplemented)
+			return
+		}
+		s.list(w, key, r.URL.Query().Get("prefix"), r.URL.Query().Get("continuation-token"))
+	default:
+		http.Error(w, "MethodNotAllowed", http.StatusMethodNotAllowed)
+	}
+}
+
+type s3ListResult struct {
+	XMLName  xml.Name `xml:"ListBucketResult"`
+	Contents []struct {
+		Key          string
+		Size         int64
+		LastModified string
+	}
+	IsTruncated           bool
+	NextContinuationToken string `xml:",omitempty"`
+}
+
+// list answers ListObjectsV2; continuation tokens are offsets into the sorted keys
+func (s *S3) list(w http.ResponseWriter, bucket, prefix, token string) {
+	var keys []string
+	for _, k := range s.Keys() {
+		if name, ok := strings.CutPrefix(k, bucket+"/"); ok && strings.HasPrefix(name, prefix) {
+			keys = append(keys, name)
+		}
+	}
+	start, _ := strconv.Atoi(token)
+	start = min(start, len(keys))
+	end := min(start+s.PageSize, len(keys))
+
+	var result s3ListResult
+	s.mu.Lock()
+	for _, k := range keys[start:end] {
+		o := s.objects[bucket+"/"+k]
+		result.Contents = append(result.Contents, struct {
+			Key          string
+			Size         int64
+			LastModified string
+		}{k, int64(len(o.Data)), o.LastModified.Format("2006-01-02T15:04:05.000Z")})
+	}
+	s.mu.Unlock()
+	if end < len(keys) {
+		result.IsTruncated = true
+		result.NextContinuationToken = strconv.Itoa(end)
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	xml.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,127 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testutil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/wmarchesi123/go-3dprint-client/spoolman"
+)
+
+// Spoolman is a scripted fake Spoolman server
+type Spoolman struct {
+	*httptest.Server
+	faults
+
+	mu     sync.Mutex
+	spools []spoolman.Spool
+	auth   string
+}
+
+// NewSpoolman starts a fake Spoolman holding spools that is closed when the test ends
+func NewSpoolman(t testing.TB, spools ...spoolman.Spool) *Spoolman {
+	s := &Spoolman{spools: spools}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/spool", s.handleList)
+	mux.HandleFunc("GET /api/v1/spool/{id}", s.handleGet)
+
+	s.Server = httptest.NewServer(s.faults.wrap(s.checkAuth(mux)))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// SetFault scripts how the server misbehaves from now on
+func (s *Spoolman) SetFault(f Fault) {
+	s.faults.set(f)
+}
+
+// SetToken makes the server reject requests without this bearer token; empty accepts any
+func (s *Spoolman) SetToken(token string) {
+	header := ""
+	if token != "" {
+		header = "Bearer " + token
+	}
+	s.setAuth(header)
+}
+
+// SetBasicAuth makes the server reject requests without these basic auth credentials
+func (s *Spoolman) SetBasicAuth(username, password string) {
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.SetBasicAuth(username, password)
+	s.setAuth(r.Header.Get("Authorization"))
+}
+
+func (s *Spoolman) setAuth(header string) {
+	s.mu.Lock()
+	s.auth = header
+	s.mu.Unlock()
+}
+
+func (s *Spoolman) checkAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		auth := s.auth
+		s.mu.Unlock()
+		if auth != "" && r.Header.Get("Authorization") != auth {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Spool builds a minimal spool for seeding a fake Spoolman
+func Spool(id int, vendor, material, colorHex string, remaining float64) spoolman.Spool {
+	return spoolman.Spool{
+		ID:              id,
+		InitialWeight:   1000,
+		RemainingWeight: remaining,
+		Filament: spoolman.Filament{
+			Name:     material,
+			Material: material,
+			ColorHex: colorHex,
+			Vendor:   spoolman.Vendor{Name: vendor},
+		},
+	}
+}
+
+func (s *Spoolman) handleList(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, s.spools)
+}
+
+func (s *Spoolman) handleGet(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(r.PathValue("id"))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, sp := range s.spools {
+		if sp.ID == id {
+			writeJSON(w, sp)
+			return
+		}
+	}
+	http.Error(w, `{"message":"Spool not found"}`, http.StatusNotFound)
+}
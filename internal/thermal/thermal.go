@@ -0,0 +1,170 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package thermal watches heater temperatures for faults the firmware may
+// miss or only catch late: a heater that isn't heating, one running away
+// above its target, and thermistor readings that jump implausibly
+package thermal
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/settings"
+)
+
+// Fault kinds
+const (
+	NotHeating = "not_heating"
+	Runaway    = "runaway"
+	Dropout    = "dropout"
+)
+
+// heatMargin is how far below its target a heater must be to count as heating
+const heatMargin = 10.0
+
+// Fault is a heater anomaly found on a printer
+type Fault struct {
+	Heater  string  `json:"heater"`
+	Kind    string  `json:"kind"`
+	Actual  float64 `json:"actual"`
+	Target  float64 `json:"target"`
+	Message string  `json:"message"`
+}
+
+// Watch tracks each heater's recent samples
+type Watch struct {
+	cfg  settings.ThermalSettings
+	unit string // temperature unit for messages
+
+	mu      sync.Mutex
+	heaters map[string]*heater // printer/heater -> state
+}
+
+type heater struct {
+	at     time.Time
+	actual float64
+	target float64
+
+	heating     time.Time // start of the current heating window, zero when not heating
+	heatingFrom float64
+	over        time.Time // start of the current window above target, zero when not over
+	overFrom    float64
+
+	raised map[string]bool // kinds already reported for the current episode
+}
+
+// New returns a watch using the configured thresholds that words its
+// messages in the given temperature unit
+func New(cfg settings.ThermalSettings, unit string) *Watch {
+	return &Watch{cfg: cfg, unit: unit, heaters: make(map[string]*heater)}
+}
+
+// Observe feeds a printer's latest temperatures to the watch and returns
+// the faults that appeared with them. Each fault is reported once until the
+// heater recovers. Statuses the printer hasn't answered since the last call
+// are ignored, so cached data can't look like a flat temperature.
+func (w *Watch) Observe(p *models.PrinterStatus) []Fault {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if p.Temperatures == nil || p.LastSeen == nil || p.Status == "offline" {
+		delete(w.heaters, p.ID+"/hotend")
+		delete(w.heaters, p.ID+"/bed")
+		return nil
+	}
+
+	var faults []Fault
+	t := p.Temperatures
+	faults = w.sample(faults, p.ID, "hotend", *p.LastSeen, t.HotendActual, t.HotendTarget)
+	faults = w.sample(faults, p.ID, "bed", *p.LastSeen, t.BedActual, t.BedTarget)
+	return faults
+}
+
+func (w *Watch) sample(faults []Fault, printerID, name string, at time.Time, actual, target float64) []Fault {
+	key := printerID + "/" + name
+	h, ok := w.heaters[key]
+	if !ok {
+		w.heaters[key] = &heater{at: at, actual: actual, target: target, raised: make(map[string]bool)}
+		return faults
+	}
+	if !at.After(h.at) {
+		return faults
+	}
+
+	temp := func(celsius float64) string {
+		return models.FormatTemperature(celsius, w.unit)
+	}
+	raise := func(kind, format string, args ...interface{}) {
+		if !h.raised[kind] {
+			h.raised[kind] = true
+			faults = append(faults, Fault{Heater: name, Kind: kind, Actual: actual, Target: target, Message: fmt.Sprintf(format, args...)})
+		}
+	}
+
+	// A jump no heater can manage points at the thermistor rather than the heater
+	elapsed := at.Sub(h.at).Seconds()
+	if rate := math.Abs(actual-h.actual) / elapsed; rate > w.cfg.DropoutRate {
+		raise(Dropout, "%s reading jumped from %s to %s in %.0fs; check the thermistor", name, temp(h.actual), temp(actual), elapsed)
+	} else {
+		delete(h.raised, Dropout)
+	}
+
+	// A new target starts new episodes
+	if target != h.target {
+		h.heating, h.over = time.Time{}, time.Time{}
+		delete(h.raised, NotHeating)
+		delete(h.raised, Runaway)
+	}
+
+	switch {
+	case target > 0 && target-actual > heatMargin:
+		if h.heating.IsZero() {
+			h.heating, h.heatingFrom = at, actual
+		} else if at.Sub(h.heating) >= w.cfg.HeatWindow {
+			if actual-h.heatingFrom < w.cfg.MinRise {
+				raise(NotHeating, "%s is at %s with a target of %s and hasn't risen in %s", name, temp(actual), temp(target), w.cfg.HeatWindow)
+			}
+			h.heating, h.heatingFrom = at, actual
+		}
+	default:
+		h.heating = time.Time{}
+		delete(h.raised, NotHeating)
+	}
+
+	switch {
+	case target > 0 && actual-target > w.cfg.RunawayMargin:
+		if h.over.IsZero() {
+			h.over, h.overFrom = at, actual
+		} else if at.Sub(h.over) >= w.cfg.RunawayWindow {
+			if actual >= h.overFrom {
+				raise(Runaway, "%s is at %s, over its target of %s and not cooling", name, temp(actual), temp(target))
+			}
+			h.over, h.overFrom = at, actual
+		}
+	default:
+		h.over = time.Time{}
+		delete(h.raised, Runaway)
+	}
+
+	h.at, h.actual, h.target = at, actual, target
+	return faults
+}
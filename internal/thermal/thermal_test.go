@@ -0,0 +1,143 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package thermal
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/settings"
+)
+
+var testSettings = settings.ThermalSettings{
+	Enabled:       true,
+	HeatWindow:    3 * time.Minute,
+	MinRise:       2,
+	RunawayMargin: 15,
+	RunawayWindow: time.Minute,
+	DropoutRate:   10,
+}
+
+// feed sends hotend samples ten seconds apart and collects the faults raised
+func feed(w *Watch, start time.Time, hotend, target []float64) []Fault {
+	var faults []Fault
+	for i := range hotend {
+		at := start.Add(time.Duration(i) * 10 * time.Second)
+		faults = append(faults, w.Observe(&models.PrinterStatus{
+			ID:           "printer-1",
+			Status:       "idle",
+			LastSeen:     &at,
+			Temperatures: &models.TemperatureInfo{HotendActual: hotend[i], HotendTarget: target[i], BedActual: 22},
+		})...)
+	}
+	return faults
+}
+
+func repeat(v float64, n int) []float64 {
+	s := make([]float64, n)
+	for i := range s {
+		s[i] = v
+	}
+	return s
+}
+
+func ramp(from, step float64, n int) []float64 {
+	s := make([]float64, n)
+	for i := range s {
+		s[i] = from + step*float64(i)
+	}
+	return s
+}
+
+func TestNotHeating(t *testing.T) {
+	start := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	// Flat at room temperature for five minutes with a 215°C target
+	faults := feed(New(testSettings, "C"), start, repeat(24, 31), repeat(215, 31))
+	if len(faults) != 1 || faults[0].Kind != NotHeating || faults[0].Heater != "hotend" {
+		t.Fatalf("flat heater faults = %+v", faults)
+	}
+
+	// Messages use the display unit
+	faults = feed(New(testSettings, "F"), start, repeat(24, 31), repeat(215, 31))
+	if len(faults) != 1 || !strings.Contains(faults[0].Message, "at 75°F with a target of 419°F") {
+		t.Errorf("fahrenheit message = %+v", faults)
+	}
+
+	// Heating at a realistic rate is fine
+	if faults := feed(New(testSettings, "C"), start, ramp(24, 5, 31), repeat(215, 31)); len(faults) != 0 {
+		t.Errorf("heating heater faults = %+v", faults)
+	}
+
+	// No target, no expectation
+	if faults := feed(New(testSettings, "C"), start, repeat(24, 31), repeat(0, 31)); len(faults) != 0 {
+		t.Errorf("idle heater faults = %+v", faults)
+	}
+}
+
+func TestRunaway(t *testing.T) {
+	start := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	faults := feed(New(testSettings, "C"), start, ramp(235, 0.5, 10), repeat(215, 10))
+	if len(faults) != 1 || faults[0].Kind != Runaway {
+		t.Fatalf("climbing past target faults = %+v", faults)
+	}
+
+	// Lowering the target leaves the heater above it while it cools
+	actual := append([]float64{250}, ramp(249, -1, 9)...)
+	target := append([]float64{250}, repeat(200, 9)...)
+	if faults := feed(New(testSettings, "C"), start, actual, target); len(faults) != 0 {
+		t.Errorf("cooling to a lower target faults = %+v", faults)
+	}
+}
+
+func TestDropout(t *testing.T) {
+	start := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	// MINTEMP-style reading falling to zero, then recovering
+	actual := []float64{215, 215, 0, 0, 215, 215}
+	faults := feed(New(testSettings, "C"), start, actual, repeat(215, len(actual)))
+	var dropouts int
+	for _, f := range faults {
+		if f.Kind == Dropout {
+			dropouts++
+		}
+	}
+	if dropouts != 2 {
+		t.Errorf("got %d dropouts in %+v, want the fall and the jump back", dropouts, faults)
+	}
+}
+
+func TestStaleSamplesIgnored(t *testing.T) {
+	w := New(testSettings, "C")
+	seen := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	status := &models.PrinterStatus{
+		ID:           "printer-1",
+		Status:       "idle",
+		LastSeen:     &seen,
+		Temperatures: &models.TemperatureInfo{HotendActual: 24, HotendTarget: 215},
+	}
+	for i := 0; i < 100; i++ {
+		if faults := w.Observe(status); len(faults) != 0 {
+			t.Fatalf("cached status raised %+v", faults)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/pkg/client"
+)
+
+const barWidth = 20
+
+var (
+	titleStyle = lipgloss.NewStyle().Bold(true)
+	dimStyle   = lipgloss.NewStyle().Faint(true)
+	errorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+
+	statusStyles = map[string]lipgloss.Style{
+		"printing":  lipgloss.NewStyle().Foreground(lipgloss.Color("12")).Bold(true),
+		"idle":      lipgloss.NewStyle().Foreground(lipgloss.Color("10")),
+		"completed": lipgloss.NewStyle().Foreground(lipgloss.Color("14")).Bold(true),
+		"cooldown":  lipgloss.NewStyle().Foreground(lipgloss.Color("14")),
+		"error":     lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Bold(true),
+		"offline":   lipgloss.NewStyle().Faint(true),
+	}
+)
+
+type statusMsg struct {
+	printers []*models.PrinterStatus
+	units    *models.Units
+	err      error
+}
+
+type tickMsg time.Time
+
+type model struct {
+	client    *client.Client
+	interval  time.Duration
+	printers  []*models.PrinterStatus
+	units     models.Units
+	err       error
+	updatedAt time.Time
+}
+
+// Run shows the farm monitor until the user quits, refreshing every interval
+func Run(c *client.Client, interval time.Duration) error {
+	m := model{client: c, interval: interval, units: models.DefaultUnits()}
+	_, err := tea.NewProgram(m, tea.WithAltScreen()).Run()
+	return err
+}
+
+func (m model) Init() tea.Cmd {
+	return m.fetch
+}
+
+func (m model) fetch() tea.Msg {
+	status, err := m.client.Status(context.Background())
+	if err != nil {
+		return statusMsg{err: err}
+	}
+	return statusMsg{printers: status.Printers, units: status.Units}
+}
+
+func (m model) tick() tea.Cmd {
+	return tea.Tick(m.interval, func(t time.Time) tea.Msg { return tickMsg(t) })
+}
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c", "esc":
+			return m, tea.Quit
+		case "r":
+			return m, m.fetch
+		}
+	case statusMsg:
+		m.err = msg.err
+		if msg.err == nil {
+			m.printers = msg.printers
+			m.updatedAt = time.Now()
+			// Servers that leave units out report metric
+			if msg.units != nil {
+				m.units = *msg.units
+			}
+		}
+		return m, m.tick()
+	case tickMsg:
+		return m, m.fetch
+	}
+	return m, nil
+}
+
+func (m model) View() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render("OctoDash"))
+	if !m.updatedAt.IsZero() {
+		b.WriteString(dimStyle.Render("  updated " + m.updatedAt.Format("15:04:05")))
+	}
+	b.WriteString("\n\n")
+
+	if m.err != nil {
+		b.WriteString(errorStyle.Render("Error: "+m.err.Error()) + "\n\n")
+	}
+
+	if m.printers == nil && m.err == nil {
+		b.WriteString("Loading...\n")
+	}
+
+	for _, p := range m.printers {
+		b.WriteString(renderPrinter(p, m.units))
+		b.WriteString("\n")
+	}
+
+	b.WriteString(dimStyle.Render("r refresh • q quit"))
+	return b.String()
+}
+
+func renderPrinter(p *models.PrinterStatus, units models.Units) string {
+	style, ok := statusStyles[p.Status]
+	if !ok {
+		style = lipgloss.NewStyle()
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s  %s\n", titleStyle.Render(p.Name), style.Render(p.State))
+
+	if p.Error != "" {
+		fmt.Fprintf(&b, "  %s\n", errorStyle.Render(p.Error))
+	}
+
+	if p.Progress != nil && p.Progress.FileName != "" {
+		fmt.Fprintf(&b, "  %s %5.1f%%  %s left  %s\n",
+			progressBar(p.Progress.Completion),
+			p.Progress.Completion,
+			models.FormatDuration(p.Progress.PrintTimeLeft),
+			dimStyle.Render(p.Progress.FileName))
+	}
+
+	if t := p.Temperatures; t != nil {
+		unit := units.Temperature
+		fmt.Fprintf(&b, "  hotend %.0f/%s  bed %.0f/%s\n",
+			models.ConvertTemperature(t.HotendActual, unit), models.FormatTemperature(t.HotendTarget, unit),
+			models.ConvertTemperature(t.BedActual, unit), models.FormatTemperature(t.BedTarget, unit))
+	}
+
+	return b.String()
+}
+
+func progressBar(completion float64) string {
+	filled := int(completion / 100 * barWidth)
+	if filled < 0 {
+		filled = 0
+	}
+	if filled > barWidth {
+		filled = barWidth
+	}
+	return "[" + strings.Repeat("█", filled) + strings.Repeat("░", barWidth-filled) + "]"
+}
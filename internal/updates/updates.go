@@ -0,0 +1,223 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package updates
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/octoapi"
+)
+
+// Client is the OctoPrint API used to check for updates
+type Client interface {
+	SoftwareUpdates() ([]octoapi.SoftwareUpdate, error)
+	FirmwareVersion() (string, error)
+}
+
+// Printer is an OctoPrint instance to check
+type Printer struct {
+	ID     string
+	Name   string
+	Client Client
+}
+
+// Checker periodically asks each OctoPrint which of its components have updates
+type Checker struct {
+	printers []Printer
+	interval time.Duration
+	logger   *log.Logger
+
+	mu      sync.RWMutex
+	results map[string]models.PrinterUpdates
+}
+
+// New creates a checker; call Run to check on a schedule or Check to check now
+func New(printers []Printer, interval time.Duration, logger *log.Logger) *Checker {
+	return &Checker{
+		printers: printers,
+		interval: interval,
+		logger:   logger,
+		results:  make(map[string]models.PrinterUpdates),
+	}
+}
+
+// Run checks immediately and then every interval until ctx is cancelled
+func (c *Checker) Run(ctx context.Context) {
+	c.Check()
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.Check()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Check queries every printer concurrently and records the results
+func (c *Checker) Check() {
+	var wg sync.WaitGroup
+	for _, p := range c.printers {
+		wg.Add(1)
+		go func(p Printer) {
+			defer wg.Done()
+			c.check(p)
+		}(p)
+	}
+	wg.Wait()
+}
+
+func (c *Checker) check(p Printer) {
+	now := time.Now().UTC()
+
+	c.mu.RLock()
+	result, ok := c.results[p.ID]
+	c.mu.RUnlock()
+	if !ok {
+		result = models.PrinterUpdates{PrinterID: p.ID, Name: p.Name, Components: []models.ComponentUpdate{}}
+	}
+	result.CheckedAt = &now
+	result.Error = ""
+
+	// A failed check keeps the previous results so badges do not flicker
+	components, err := p.Client.SoftwareUpdates()
+	if err != nil {
+		c.logger.Printf("Update check failed for %s: %v", p.Name, err)
+		result.Error = err.Error()
+	} else {
+		result.Components = make([]models.ComponentUpdate, 0, len(components))
+		result.Available = 0
+		for _, u := range components {
+			result.Components = append(result.Components, models.ComponentUpdate{
+				ID:              u.ID,
+				Name:            u.Name,
+				Installed:       u.Installed,
+				Available:       u.Available,
+				UpdateAvailable: u.UpdateAvailable,
+			})
+			if u.UpdateAvailable {
+				result.Available++
+			}
+		}
+	}
+
+	// Older OctoPrint versions have no system info endpoint; that is not worth failing over
+	if firmware, err := p.Client.FirmwareVersion(); err == nil {
+		if firmware != "" {
+			result.Firmware = firmware
+		}
+	} else if result.Error == "" {
+		c.logger.Printf("Firmware lookup failed for %s: %v", p.Name, err)
+	}
+
+	c.mu.Lock()
+	c.results[p.ID] = result
+	c.mu.Unlock()
+}
+
+// Get returns the latest result for a printer, if it has been checked
+func (c *Checker) Get(printerID string) (models.PrinterUpdates, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	result, ok := c.results[printerID]
+	return result, ok
+}
+
+// Badge returns the dashboard card summary for a printer, or nil before its first check
+func (c *Checker) Badge(printerID string) *models.UpdateBadge {
+	result, ok := c.Get(printerID)
+	if !ok || result.CheckedAt == nil {
+		return nil
+	}
+
+	badge := &models.UpdateBadge{Available: result.Available, Firmware: result.Firmware}
+	for _, u := range result.Components {
+		if u.UpdateAvailable {
+			badge.Components = append(badge.Components, u.Name)
+		}
+	}
+	return badge
+}
+
+// All returns the latest result for every printer in configuration order
+func (c *Checker) All() []models.PrinterUpdates {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	all := make([]models.PrinterUpdates, 0, len(c.printers))
+	for _, p := range c.printers {
+		result, ok := c.results[p.ID]
+		if !ok {
+			result = models.PrinterUpdates{PrinterID: p.ID, Name: p.Name, Components: []models.ComponentUpdate{}}
+		}
+		all = append(all, result)
+	}
+	return all
+}
+
+// Summarize rolls per-printer results up into farm-wide totals
+func Summarize(all []models.PrinterUpdates) models.UpdateSummary {
+	summary := models.UpdateSummary{
+		Components: []models.ComponentSummary{},
+		Firmware:   make(map[string][]string),
+	}
+
+	byComponent := make(map[string]*models.ComponentSummary)
+	for _, p := range all {
+		if p.CheckedAt == nil {
+			continue
+		}
+		summary.PrintersChecked++
+		if p.Available > 0 {
+			summary.PrintersWithUpdates++
+		}
+		if p.Firmware != "" {
+			summary.Firmware[p.Firmware] = append(summary.Firmware[p.Firmware], p.PrinterID)
+		}
+
+		for _, u := range p.Components {
+			if !u.UpdateAvailable {
+				continue
+			}
+			cs, ok := byComponent[u.ID]
+			if !ok {
+				cs = &models.ComponentSummary{ID: u.ID, Name: u.Name, Available: u.Available}
+				byComponent[u.ID] = cs
+			}
+			cs.Printers = append(cs.Printers, p.PrinterID)
+		}
+	}
+
+	for _, cs := range byComponent {
+		summary.Components = append(summary.Components, *cs)
+	}
+	sort.Slice(summary.Components, func(i, j int) bool { return summary.Components[i].ID < summary.Components[j].ID })
+
+	return summary
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upstream
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DialContextFunc matches net.Dialer.DialContext
+type DialContextFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+type cacheEntry struct {
+	addrs   []string
+	expires time.Time
+}
+
+// resolver pins hosts to fixed IPs and caches lookups, serving the last good
+// answer when a refresh fails so a flaky mDNS lookup doesn't take a printer offline
+type resolver struct {
+	overrides map[string]string
+	ttl       time.Duration
+	lookup    func(ctx context.Context, host string) ([]string, error)
+
+	mu    sync.Mutex
+	cache map[string]cacheEntry
+}
+
+func newResolver(overrides map[string]string, ttl time.Duration) *resolver {
+	return &resolver{
+		overrides: overrides,
+		ttl:       ttl,
+		lookup:    net.DefaultResolver.LookupHost,
+		cache:     make(map[string]cacheEntry),
+	}
+}
+
+// resolve returns the addresses to try for host
+func (r *resolver) resolve(ctx context.Context, host string) ([]string, error) {
+	if ip, ok := r.overrides[strings.ToLower(host)]; ok {
+		return []string{ip}, nil
+	}
+	if net.ParseIP(host) != nil || r.ttl <= 0 {
+		return []string{host}, nil
+	}
+
+	r.mu.Lock()
+	entry, cached := r.cache[host]
+	r.mu.Unlock()
+
+	if cached && time.Now().Before(entry.expires) {
+		return entry.addrs, nil
+	}
+
+	addrs, err := r.lookup(ctx, host)
+	if err != nil || len(addrs) == 0 {
+		if cached {
+			return entry.addrs, nil
+		}
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.cache[host] = cacheEntry{addrs: addrs, expires: time.Now().Add(r.ttl)}
+	r.mu.Unlock()
+
+	return addrs, nil
+}
+
+// wrap returns a dial function that resolves hosts through r before dialing
+func (r *resolver) wrap(dial DialContextFunc) DialContextFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return dial(ctx, network, addr)
+		}
+
+		addrs, err := r.resolve(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+
+		var lastErr error
+		for _, ip := range addrs {
+			conn, err := dial(ctx, network, net.JoinHostPort(ip, port))
+			if err == nil {
+				return conn, nil
+			}
+			lastErr = err
+		}
+		return nil, lastErr
+	}
+}
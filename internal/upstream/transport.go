@@ -0,0 +1,160 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upstream
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/wmarchesi123/go-3dprint-client/config"
+	"github.com/wmarchesi123/octodash/internal/settings"
+)
+
+// NewTransport returns a pooled transport tuned for polling a handful of
+// hosts, routed through any proxies configured for printers
+func NewTransport(s settings.UpstreamSettings, printers []config.Printer) *http.Transport {
+	// Connect timeouts are applied per host by dialTimeouts
+	dialer := &net.Dialer{KeepAlive: 30 * time.Second}
+	dial := newResolver(s.HostOverrides, s.DNSCacheTTL).wrap(dialer.DialContext)
+
+	return &http.Transport{
+		Proxy:                 proxyFunc(s, printers),
+		DialContext:           dialTimeouts(dial, 5*time.Second, printerHosts(s.PrinterTimeouts, printers)),
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   s.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       s.MaxConnsPerHost,
+		IdleConnTimeout:       s.IdleConnTimeout,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
+
+// proxyFunc sends requests for a printer's host through that printer's proxy
+// and everything else through UPSTREAM_PROXY, falling back to the standard
+// HTTP_PROXY/HTTPS_PROXY/NO_PROXY variables when neither is set
+func proxyFunc(s settings.UpstreamSettings, printers []config.Printer) func(*http.Request) (*url.URL, error) {
+	hosts := printerHosts(s.PrinterProxies, printers)
+
+	return func(req *http.Request) (*url.URL, error) {
+		if proxy, ok := hosts[canonicalAddr(req.URL)]; ok {
+			return proxy, nil
+		}
+		if s.Proxy != nil {
+			return s.Proxy, nil
+		}
+		return http.ProxyFromEnvironment(req)
+	}
+}
+
+// canonicalAddr returns u's host:port, filling in the scheme's default port
+func canonicalAddr(u *url.URL) string {
+	port := u.Port()
+	if port == "" {
+		port = map[string]string{"http": "80", "https": "443"}[u.Scheme]
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// printerHosts maps each printer's host:port to its value in byID
+func printerHosts[T any](byID map[string]T, printers []config.Printer) map[string]T {
+	hosts := make(map[string]T)
+	for _, p := range printers {
+		v, ok := byID[p.ID]
+		if !ok {
+			continue
+		}
+		if u, err := url.Parse(p.OctoPrintURL); err == nil {
+			hosts[canonicalAddr(u)] = v
+		}
+	}
+	return hosts
+}
+
+// dialTimeouts bounds each connection attempt, giving hosts on slow links
+// (tailnets relayed over DERP, VPNs) their own longer timeout
+func dialTimeouts(dial DialContextFunc, def time.Duration, hosts map[string]time.Duration) DialContextFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		timeout := def
+		if t, ok := hosts[addr]; ok {
+			timeout = t
+		}
+
+		// The context only bounds the dial; the connection outlives it
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return dial(ctx, network, addr)
+	}
+}
+
+// tailnetRanges are the addresses Tailscale assigns to nodes
+var tailnetRanges = []*net.IPNet{
+	mustCIDR("100.64.0.0/10"),
+	mustCIDR("fd7a:115c:a1e0::/48"),
+}
+
+func mustCIDR(s string) *net.IPNet {
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// onTailnet reports whether addr is a tailnet IP or MagicDNS name
+func onTailnet(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	if strings.HasSuffix(strings.ToLower(host), ".ts.net") {
+		return true
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		for _, n := range tailnetRanges {
+			if n.Contains(ip) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ViaTailnet sends connections to tailnet addresses through dial, the
+// embedded Tailscale node, leaving every other host on the normal path
+func ViaTailnet(transport *http.Transport, dial DialContextFunc) {
+	next := transport.DialContext
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if onTailnet(addr) {
+			return dial(ctx, network, addr)
+		}
+		return next(ctx, network, addr)
+	}
+}
+
+// Install makes transport the process-wide default. The go-3dprint-client
+// and plug clients build http.Clients without a Transport, so this is how
+// they all end up sharing one connection pool.
+func Install(transport *http.Transport) {
+	http.DefaultTransport = transport
+}
@@ -0,0 +1,106 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upstream
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/wmarchesi123/go-3dprint-client/config"
+	"github.com/wmarchesi123/octodash/internal/settings"
+)
+
+func TestPrinterProxy(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "direct")
+	}))
+	defer target.Close()
+
+	// A forward proxy sees absolute request URIs; it answers without relaying
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "proxied "+r.URL.Host)
+	}))
+	defer proxy.Close()
+	proxyURL, _ := url.Parse(proxy.URL)
+
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "direct")
+	}))
+	defer other.Close()
+
+	s := settings.UpstreamSettings{PrinterProxies: map[string]*url.URL{"printer-1": proxyURL}}
+	printers := []config.Printer{
+		{ID: "printer-1", OctoPrintURL: target.URL + "/octoprint"},
+		{ID: "printer-2", OctoPrintURL: other.URL},
+	}
+	client := &http.Client{Transport: NewTransport(s, printers)}
+
+	get := func(u string) string {
+		t.Helper()
+		resp, err := client.Get(u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	targetURL, _ := url.Parse(target.URL)
+	if got := get(target.URL + "/octoprint/api/printer"); got != "proxied "+targetURL.Host {
+		t.Errorf("printer-1 request = %q, want it sent through the proxy", got)
+	}
+	if got := get(other.URL + "/api/printer"); got != "direct" {
+		t.Errorf("printer-2 request = %q, want direct", got)
+	}
+
+	// A global proxy catches everything without its own route
+	s.Proxy = proxyURL
+	client = &http.Client{Transport: NewTransport(s, printers)}
+	otherURL, _ := url.Parse(other.URL)
+	if got := get(other.URL + "/api/printer"); got != "proxied "+otherURL.Host {
+		t.Errorf("request with global proxy = %q", got)
+	}
+
+	// PRINTER_N_PROXY=direct opts a printer out of the global proxy
+	s.PrinterProxies["printer-2"] = nil
+	client = &http.Client{Transport: NewTransport(s, printers)}
+	if got := get(other.URL + "/api/printer"); got != "direct" {
+		t.Errorf("request for a direct printer = %q", got)
+	}
+}
+
+func TestOnTailnet(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"100.101.102.103:80":         true,
+		"[fd7a:115c:a1e0::1]:5000":   true,
+		"octopi.tail1234.ts.net:443": true,
+		"192.168.1.20:80":            false,
+		"octopi.local:80":            false,
+		"100.128.0.1:80":             false,
+		"[fd7a:115c:a1e1::1]:80":     false,
+	} {
+		if got := onTailnet(addr); got != want {
+			t.Errorf("onTailnet(%q) = %v, want %v", addr, got, want)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package usage keeps the per-user ledger of print time and filament used by queued jobs
+package usage
+
+import (
+	"sort"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/store"
+)
+
+// Bucket holds the usage ledger
+const Bucket = "usage"
+
+// Ledger is the persistent record of finished queued jobs, keyed by queue entry
+// so a job seen finishing twice is only charged once
+type Ledger struct {
+	store *store.Store
+}
+
+// New creates a Ledger backed by the given store
+func New(s *store.Store) *Ledger {
+	return &Ledger{store: s}
+}
+
+// Record charges a finished job to its submitter
+func (l *Ledger) Record(rec models.UsageRecord) error {
+	rec.FinishedAt = rec.FinishedAt.UTC()
+	return l.store.Put(Bucket, rec.Entry, rec)
+}
+
+// Recorded reports whether a queue entry has already been charged
+func (l *Ledger) Recorded(entry string) (bool, error) {
+	var rec models.UsageRecord
+	return l.store.Get(Bucket, entry, &rec)
+}
+
+// Month totals usage per user for the calendar month containing at, in at's location
+func (l *Ledger) Month(at time.Time) (map[string]models.Usage, error) {
+	records, err := store.List[models.UsageRecord](l.store, Bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	start := MonthStart(at)
+	end := start.AddDate(0, 1, 0)
+	totals := make(map[string]models.Usage)
+	for _, rec := range records {
+		if rec.FinishedAt.Before(start) || !rec.FinishedAt.Before(end) {
+			continue
+		}
+		u := totals[rec.User]
+		u.User = rec.User
+		u.Jobs++
+		u.PrintTime += rec.PrintTime
+		u.Filament += rec.Filament
+		u.EnergyKWh += rec.EnergyKWh
+		u.Cost += rec.Cost
+		totals[rec.User] = u
+	}
+	return totals, nil
+}
+
+// User returns one user's usage for the month containing at
+func (l *Ledger) User(user string, at time.Time) (models.Usage, error) {
+	totals, err := l.Month(at)
+	if err != nil {
+		return models.Usage{}, err
+	}
+	u := totals[user]
+	u.User = user
+	return u, nil
+}
+
+// Sorted returns monthly totals ordered by user name
+func Sorted(totals map[string]models.Usage) []models.Usage {
+	list := make([]models.Usage, 0, len(totals))
+	for _, u := range totals {
+		list = append(list, u)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].User < list[j].User })
+	return list
+}
+
+// MonthStart is midnight on the first of at's month, in at's location
+func MonthStart(at time.Time) time.Time {
+	return time.Date(at.Year(), at.Month(), 1, 0, 0, 0, 0, at.Location())
+}
@@ -0,0 +1,126 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package views stores each user's saved dashboard views and their default
+package views
+
+import (
+	"errors"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/store"
+)
+
+// defaultsBucket maps user names to the ID of their default view
+const defaultsBucket = "view_defaults"
+
+// ErrNotFound is returned when a user has no view with the given ID
+var ErrNotFound = errors.New("view not found")
+
+// Views is the persistent set of saved views, one bucket per user
+type Views struct {
+	store *store.Store
+}
+
+// New creates a Views backed by the given store
+func New(s *store.Store) *Views {
+	return &Views{store: s}
+}
+
+func bucket(user string) string {
+	return "views:" + user
+}
+
+// List returns a user's views in creation order
+func (v *Views) List(user string) ([]models.View, error) {
+	views, err := store.List[models.View](v.store, bucket(user))
+	if views == nil {
+		views = []models.View{}
+	}
+	return views, err
+}
+
+// Get returns one of a user's views
+func (v *Views) Get(user, id string) (models.View, error) {
+	var view models.View
+	ok, err := v.store.Get(bucket(user), id, &view)
+	if err != nil {
+		return view, err
+	}
+	if !ok {
+		return view, ErrNotFound
+	}
+	return view, nil
+}
+
+// Create saves a new view for a user
+func (v *Views) Create(user string, view models.View, at time.Time) (models.View, error) {
+	id, err := v.store.NextID(bucket(user))
+	if err != nil {
+		return view, err
+	}
+
+	view.ID = id
+	view.CreatedAt = at.UTC()
+	view.UpdatedAt = view.CreatedAt
+	return view, v.store.Put(bucket(user), id, view)
+}
+
+// Update replaces an existing view, keeping its ID and creation time
+func (v *Views) Update(user string, view models.View, at time.Time) (models.View, error) {
+	existing, err := v.Get(user, view.ID)
+	if err != nil {
+		return view, err
+	}
+
+	view.CreatedAt = existing.CreatedAt
+	view.UpdatedAt = at.UTC()
+	return view, v.store.Put(bucket(user), view.ID, view)
+}
+
+// Delete removes a view, clearing it as the user's default if it was
+func (v *Views) Delete(user, id string) error {
+	if _, err := v.Get(user, id); err != nil {
+		return err
+	}
+	if def, err := v.Default(user); err == nil && def == id {
+		if err := v.SetDefault(user, ""); err != nil {
+			return err
+		}
+	}
+	return v.store.Delete(bucket(user), id)
+}
+
+// Default returns the ID of the user's default view, or "" for none
+func (v *Views) Default(user string) (string, error) {
+	var id string
+	_, err := v.store.Get(defaultsBucket, user, &id)
+	return id, err
+}
+
+// SetDefault makes a view the one the dashboard opens with; "" clears it
+func (v *Views) SetDefault(user, id string) error {
+	if id == "" {
+		return v.store.Delete(defaultsBucket, user)
+	}
+	if _, err := v.Get(user, id); err != nil {
+		return err
+	}
+	return v.store.Put(defaultsBucket, user, id)
+}
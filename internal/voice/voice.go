@@ -0,0 +1,183 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package voice turns what people say to voice assistants into intents and
+// phrases answers so they sound natural when read aloud: names rather than
+// IDs, rounded durations and no symbols a speech engine would spell out.
+package voice
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Intents the API understands
+const (
+	FarmStatus    = "FarmStatus"
+	PrinterStatus = "PrinterStatus"
+	TimeRemaining = "TimeRemaining"
+	Temperatures  = "Temperatures"
+	PausePrinter  = "PausePrinter"
+	ResumePrinter = "ResumePrinter"
+)
+
+// utterances match free text to intents, most specific first; the printer
+// slot is whatever follows the verb
+var utterances = []struct {
+	intent  string
+	pattern *regexp.Regexp
+}{
+	{PausePrinter, regexp.MustCompile(`^pause (?:the )?(?P<printer>.+)$`)},
+	{ResumePrinter, regexp.MustCompile(`^(?:resume|continue|unpause) (?:the )?(?P<printer>.+)$`)},
+	{TimeRemaining, regexp.MustCompile(`^(?:how (?:much time|long) is left|how long) (?:on|for) (?:the )?(?P<printer>.+)$`)},
+	{TimeRemaining, regexp.MustCompile(`^when (?:will|is) (?:the )?(?P<printer>.+?) (?:be )?(?:done|finished|finish)$`)},
+	{TimeRemaining, regexp.MustCompile(`^(?:what(?:'s| is) finishing next|when is the next print done|what finishes next)$`)},
+	{Temperatures, regexp.MustCompile(`^(?:how hot is|what are the temperatures? (?:of|on)|temperatures? (?:of|on)) (?:the )?(?P<printer>.+)$`)},
+	{FarmStatus, regexp.MustCompile(`^(?:how(?:'s| is) the (?:farm|print farm|printers)|(?:farm|printer) status|status of (?:the )?(?:farm|printers)|status)$`)},
+	{PrinterStatus, regexp.MustCompile(`^(?:what(?:'s| is) (?:the )?)?status (?:of|on|for) (?:the )?(?P<printer>.+)$`)},
+	{PrinterStatus, regexp.MustCompile(`^(?:how(?:'s| is)|what(?:'s| is)) (?:the )?(?P<printer>.+?) (?:doing|up to)$`)},
+}
+
+// Parse finds the intent in free text such as "pause printer four",
+// returning its slots
+func Parse(text string) (intent string, slots map[string]string, ok bool) {
+	text = strings.ToLower(strings.TrimSpace(text))
+	text = strings.TrimRight(text, "?.! ")
+	text = strings.TrimPrefix(text, "please ")
+
+	for _, u := range utterances {
+		m := u.pattern.FindStringSubmatch(text)
+		if m == nil {
+			continue
+		}
+		slots = map[string]string{}
+		for i, name := range u.pattern.SubexpNames() {
+			if name != "" {
+				slots[name] = m[i]
+			}
+		}
+		return u.intent, slots, true
+	}
+	return "", nil, false
+}
+
+// numberWords lets "printer four" match a printer named Printer 4
+var numberWords = map[string]string{
+	"zero": "0", "one": "1", "two": "2", "three": "3", "four": "4", "five": "5",
+	"six": "6", "seven": "7", "eight": "8", "nine": "9", "ten": "10",
+	"eleven": "11", "twelve": "12", "thirteen": "13", "fourteen": "14", "fifteen": "15",
+	"sixteen": "16", "seventeen": "17", "eighteen": "18", "nineteen": "19", "twenty": "20",
+}
+
+var nonWord = regexp.MustCompile(`[^a-z0-9]+`)
+
+// normalize reduces a name to lower-case words with numbers as digits, so
+// "Printer #4", "printer-4" and "printer four" all come out the same
+func normalize(s string) string {
+	words := strings.Fields(nonWord.ReplaceAllString(strings.ToLower(s), " "))
+	for i, w := range words {
+		if n, ok := numberWords[w]; ok {
+			words[i] = n
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// Match picks the name a spoken query refers to: an exact match ignoring case,
+// punctuation and number words, then the same without the word "printer",
+// then the only name containing the query
+func Match(query string, names []string) (int, bool) {
+	q := normalize(query)
+	if q == "" {
+		return -1, false
+	}
+	for i, name := range names {
+		if normalize(name) == q {
+			return i, true
+		}
+	}
+
+	bare := func(s string) string {
+		return strings.TrimSpace(strings.ReplaceAll(" "+s+" ", " printer ", " "))
+	}
+	for i, name := range names {
+		if bare(normalize(name)) == bare(q) {
+			return i, true
+		}
+	}
+
+	found := -1
+	for i, name := range names {
+		if strings.Contains(" "+normalize(name)+" ", " "+q+" ") {
+			if found >= 0 {
+				return -1, false
+			}
+			found = i
+		}
+	}
+	return found, found >= 0
+}
+
+// Duration reads a number of seconds as a rounded spoken duration such as
+// "about 2 hours and 5 minutes"
+func Duration(seconds int) string {
+	minutes := (seconds + 30) / 60
+	switch {
+	case seconds <= 0:
+		return "an unknown time"
+	case minutes < 1:
+		return "less than a minute"
+	case minutes < 60:
+		return "about " + Count(minutes, "minute")
+	case minutes%60 == 0:
+		return "about " + Count(minutes/60, "hour")
+	}
+	return fmt.Sprintf("about %s and %s", Count(minutes/60, "hour"), Count(minutes%60, "minute"))
+}
+
+// Count pairs a number with a noun, pluralized with s when needed
+func Count(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return strconv.Itoa(n) + " " + noun + "s"
+}
+
+// List joins items as in speech: "A", "A and B", "A, B and C"
+func List(items []string) string {
+	switch len(items) {
+	case 0:
+		return ""
+	case 1:
+		return items[0]
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
+
+// File turns a file name into words: gear_v2.gcode reads as gear v2
+func File(name string) string {
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.LastIndex(name, "."); i > 0 {
+		name = name[:i]
+	}
+	return strings.NewReplacer("_", " ", "-", " ").Replace(name)
+}
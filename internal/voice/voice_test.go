@@ -0,0 +1,91 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package voice
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		text   string
+		intent string
+		slots  map[string]string
+	}{
+		{"Pause printer four.", PausePrinter, map[string]string{"printer": "printer four"}},
+		{"please resume the Voron", ResumePrinter, map[string]string{"printer": "voron"}},
+		{"How long is left on the Prusa?", TimeRemaining, map[string]string{"printer": "prusa"}},
+		{"when will printer 2 be done", TimeRemaining, map[string]string{"printer": "printer 2"}},
+		{"what's finishing next", TimeRemaining, map[string]string{}},
+		{"how hot is the mini", Temperatures, map[string]string{"printer": "mini"}},
+		{"How's the farm", FarmStatus, map[string]string{}},
+		{"status", FarmStatus, map[string]string{}},
+		{"what is the status of printer three", PrinterStatus, map[string]string{"printer": "printer three"}},
+		{"what is the voron doing", PrinterStatus, map[string]string{"printer": "voron"}},
+	}
+	for _, tt := range tests {
+		intent, slots, ok := Parse(tt.text)
+		if !ok || intent != tt.intent || !reflect.DeepEqual(slots, tt.slots) {
+			t.Errorf("Parse(%q) = %s %v %v, want %s %v", tt.text, intent, slots, ok, tt.intent, tt.slots)
+		}
+	}
+
+	if intent, _, ok := Parse("order a pizza"); ok {
+		t.Errorf("Parse matched unrelated text as %s", intent)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	names := []string{"Printer 4", "Printer 14", "Voron 2.4", "Prusa MK4 Left", "Prusa MK4 Right"}
+	tests := []struct {
+		query string
+		want  int
+		ok    bool
+	}{
+		{"printer four", 0, true},
+		{"Printer #4", 0, true},
+		{"fourteen", 1, true},
+		{"voron", 2, true},
+		{"prusa mk4 left", 3, true},
+		{"prusa", -1, false}, // two Prusas
+		{"ender", -1, false},
+	}
+	for _, tt := range tests {
+		if got, ok := Match(tt.query, names); got != tt.want || ok != tt.ok {
+			t.Errorf("Match(%q) = %d %v, want %d %v", tt.query, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestDuration(t *testing.T) {
+	tests := map[int]string{
+		0:    "an unknown time",
+		20:   "less than a minute",
+		60:   "about 1 minute",
+		754:  "about 13 minutes",
+		3600: "about 1 hour",
+		7500: "about 2 hours and 5 minutes",
+	}
+	for seconds, want := range tests {
+		if got := Duration(seconds); got != want {
+			t.Errorf("Duration(%d) = %q, want %q", seconds, got, want)
+		}
+	}
+}
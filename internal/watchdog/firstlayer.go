@@ -0,0 +1,79 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package watchdog notices prints that are running but not getting anywhere,
+// from the progress the poller sees over time
+package watchdog
+
+import (
+	"sync"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+)
+
+// FirstLayer notices prints stuck in their first few percent for too long
+type FirstLayer struct {
+	percent float64
+	timeout time.Duration
+
+	mu   sync.Mutex
+	jobs map[string]*firstLayerJob // printer ID -> job on its first layer
+}
+
+type firstLayerJob struct {
+	file     string
+	printing time.Duration // printing time seen so far, not counting pauses
+	last     time.Time
+	alerted  bool
+}
+
+// NewFirstLayer returns a watchdog for prints still under percent complete
+// after timeout of printing
+func NewFirstLayer(percent float64, timeout time.Duration) *FirstLayer {
+	return &FirstLayer{percent: percent, timeout: timeout, jobs: make(map[string]*firstLayerJob)}
+}
+
+// Observe records a printer's progress and reports how long its print has
+// been stuck the first time that passes the timeout
+func (f *FirstLayer) Observe(p *models.PrinterStatus, now time.Time) (time.Duration, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	job := f.jobs[p.ID]
+	if (p.Status != "printing" && p.Status != "paused") || p.Progress == nil || p.Progress.Completion >= f.percent {
+		delete(f.jobs, p.ID)
+		return 0, false
+	}
+	if job == nil || job.file != p.Progress.FileName {
+		f.jobs[p.ID] = &firstLayerJob{file: p.Progress.FileName, last: now}
+		return 0, false
+	}
+
+	// Polling intervals that end paused don't count
+	if p.Status == "printing" {
+		job.printing += now.Sub(job.last)
+	}
+	job.last = now
+
+	if job.alerted || job.printing < f.timeout {
+		return 0, false
+	}
+	job.alerted = true
+	return job.printing, true
+}
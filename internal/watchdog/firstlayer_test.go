@@ -0,0 +1,70 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watchdog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+)
+
+func printing(status, file string, completion float64) *models.PrinterStatus {
+	return &models.PrinterStatus{
+		ID:       "printer-1",
+		Status:   status,
+		Progress: &models.ProgressInfo{FileName: file, Completion: completion},
+	}
+}
+
+func TestFirstLayer(t *testing.T) {
+	w := NewFirstLayer(2, 10*time.Minute)
+	start := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	var alerts []time.Duration
+	observe := func(p *models.PrinterStatus, at time.Duration) {
+		if stuck, ok := w.Observe(p, start.Add(at)); ok {
+			alerts = append(alerts, stuck)
+		}
+	}
+
+	// Stuck at 1% with a five-minute pause that doesn't count
+	observe(printing("printing", "gear.gcode", 0.5), 0)
+	observe(printing("printing", "gear.gcode", 1), 4*time.Minute)
+	observe(printing("paused", "gear.gcode", 1), 6*time.Minute)
+	observe(printing("paused", "gear.gcode", 1), 11*time.Minute)
+	observe(printing("printing", "gear.gcode", 1), 12*time.Minute)
+	if len(alerts) != 0 {
+		t.Fatalf("alerted after %v of printing", alerts)
+	}
+	observe(printing("printing", "gear.gcode", 1), 16*time.Minute)
+	observe(printing("printing", "gear.gcode", 1), 20*time.Minute)
+	if len(alerts) != 1 || alerts[0] != 13*time.Minute {
+		t.Fatalf("alerts = %v, want one at the first poll past 10m of printing", alerts)
+	}
+
+	// A print past its first layer never alerts
+	alerts = nil
+	observe(printing("printing", "bracket.gcode", 1), 30*time.Minute)
+	observe(printing("printing", "bracket.gcode", 3), 35*time.Minute)
+	observe(printing("printing", "bracket.gcode", 3), 60*time.Minute)
+	if len(alerts) != 0 {
+		t.Errorf("print past its first layer alerted after %v", alerts)
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watchdog
+
+import (
+	"sync"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+)
+
+// Stall notices prints whose file position stops advancing while the printer
+// still reports printing, which temperatures alone don't reveal
+type Stall struct {
+	window time.Duration
+
+	mu       sync.Mutex
+	printers map[string]*stallState
+}
+
+type stallState struct {
+	file    string
+	filepos int64
+	moved   time.Time // when filepos last changed
+	flagged bool
+}
+
+// NewStall returns a watchdog for prints that haven't moved in window
+func NewStall(window time.Duration) *Stall {
+	return &Stall{window: window, printers: make(map[string]*stallState)}
+}
+
+// Observe records a printer's file position. It returns the stall while the
+// print is stalled, and whether this is the first poll to find it so.
+func (s *Stall) Observe(p *models.PrinterStatus, now time.Time) (stall *models.StallInfo, first bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if p.Status != "printing" || p.Progress == nil {
+		// A pause stops the file position on purpose, so start over afterwards
+		delete(s.printers, p.ID)
+		return nil, false
+	}
+
+	state := s.printers[p.ID]
+	if state == nil || state.file != p.Progress.FileName || state.filepos != p.Progress.Filepos {
+		s.printers[p.ID] = &stallState{file: p.Progress.FileName, filepos: p.Progress.Filepos, moved: now}
+		return nil, false
+	}
+	if now.Sub(state.moved) < s.window {
+		return nil, false
+	}
+
+	first = !state.flagged
+	state.flagged = true
+	return &models.StallInfo{Since: state.moved, Filepos: state.filepos}, first
+}
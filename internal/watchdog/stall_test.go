@@ -0,0 +1,59 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watchdog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+)
+
+func TestStall(t *testing.T) {
+	w := NewStall(10 * time.Minute)
+	start := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	at := func(status string, filepos int64) *models.PrinterStatus {
+		return &models.PrinterStatus{
+			ID:       "printer-1",
+			Status:   status,
+			Progress: &models.ProgressInfo{FileName: "gear.gcode", Filepos: filepos},
+		}
+	}
+
+	if stall, _ := w.Observe(at("printing", 100), start); stall != nil {
+		t.Fatalf("first sample stalled: %+v", stall)
+	}
+	if stall, _ := w.Observe(at("printing", 100), start.Add(9*time.Minute)); stall != nil {
+		t.Fatalf("stalled inside the window: %+v", stall)
+	}
+	stall, first := w.Observe(at("printing", 100), start.Add(11*time.Minute))
+	if stall == nil || !first || !stall.Since.Equal(start) {
+		t.Fatalf("stall = %+v, first %v", stall, first)
+	}
+	if stall, first := w.Observe(at("printing", 100), start.Add(12*time.Minute)); stall == nil || first {
+		t.Errorf("ongoing stall = %+v, first %v", stall, first)
+	}
+
+	// Pauses hold the file position on purpose
+	w.Observe(at("paused", 100), start.Add(13*time.Minute))
+	w.Observe(at("paused", 100), start.Add(40*time.Minute))
+	if stall, _ := w.Observe(at("printing", 100), start.Add(41*time.Minute)); stall != nil {
+		t.Errorf("stalled right after resuming: %+v", stall)
+	}
+}
@@ -0,0 +1,310 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package webhooks delivers domain events to outside systems through a
+// persistent outbox, so events raised while a receiver is down still arrive
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/settings"
+	"github.com/wmarchesi123/octodash/internal/store"
+	"github.com/wmarchesi123/octodash/internal/version"
+)
+
+// Bucket holds the webhook delivery log
+const Bucket = "webhook_deliveries"
+
+// Retry backoff doubles from firstBackoff up to maxBackoff
+const (
+	firstBackoff = 30 * time.Second
+	maxBackoff   = time.Hour
+)
+
+// ErrNotFound is returned when a delivery does not exist
+var ErrNotFound = errors.New("delivery not found")
+
+// Outbox queues events for each subscribed webhook and delivers them
+type Outbox struct {
+	store    *store.Store
+	settings settings.WebhookSettings
+	client   *http.Client
+	logger   *log.Logger
+
+	mu sync.Mutex // serializes delivery runs and retries
+}
+
+// New creates an Outbox for the configured webhooks
+func New(s *store.Store, cfg settings.WebhookSettings, logger *log.Logger) *Outbox {
+	return &Outbox{
+		store:    s,
+		settings: cfg,
+		client:   &http.Client{Timeout: cfg.Timeout},
+		logger:   logger,
+	}
+}
+
+// Enabled reports whether any webhook is configured
+func (o *Outbox) Enabled() bool {
+	return len(o.settings.Endpoints) > 0
+}
+
+// Publish queues an event for every webhook subscribed to its type
+func (o *Outbox) Publish(e models.Event) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	for _, endpoint := range o.settings.Endpoints {
+		if len(endpoint.Events) > 0 && !slices.Contains(endpoint.Events, e.Type) {
+			continue
+		}
+
+		id, err := o.store.NextID(Bucket)
+		if err != nil {
+			return err
+		}
+		next := e.Time
+		d := models.WebhookDelivery{
+			ID:          id,
+			Webhook:     endpoint.ID,
+			EventID:     e.ID,
+			EventType:   e.Type,
+			Status:      models.DeliveryPending,
+			CreatedAt:   e.Time,
+			NextAttempt: &next,
+			Payload:     payload,
+		}
+		if err := o.store.Put(Bucket, id, d); err != nil {
+			return err
+		}
+	}
+	return o.trim()
+}
+
+// trim drops the oldest finished deliveries beyond Retain. Pending ones are
+// kept however many there are, so a long receiver outage loses nothing; each
+// ends up delivered or failed within MaxAttempts and is trimmed after that.
+func (o *Outbox) trim() error {
+	var finished []string
+	total := 0
+	err := o.store.Scan(Bucket, func(key string, value []byte) error {
+		total++
+		var d struct {
+			Status string `json:"status"`
+		}
+		if err := json.Unmarshal(value, &d); err != nil {
+			return err
+		}
+		if d.Status != models.DeliveryPending {
+			finished = append(finished, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	excess := total - o.settings.Retain
+	if excess <= 0 {
+		return nil
+	}
+	return o.store.DeleteKeys(Bucket, finished[:min(excess, len(finished))])
+}
+
+// Run delivers pending events every interval until ctx is cancelled
+func (o *Outbox) Run(ctx context.Context, now func() time.Time) {
+	ticker := time.NewTicker(o.settings.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			o.Deliver(ctx, now())
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Deliver attempts every pending delivery that is due, oldest first
+func (o *Outbox) Deliver(ctx context.Context, now time.Time) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	all, err := store.List[models.WebhookDelivery](o.store, Bucket)
+	if err != nil {
+		o.logger.Printf("Failed to read webhook outbox: %v", err)
+		return
+	}
+
+	for _, d := range all {
+		if ctx.Err() != nil {
+			return
+		}
+		if d.Status != models.DeliveryPending || (d.NextAttempt != nil && d.NextAttempt.After(now)) {
+			continue
+		}
+		endpoint, ok := o.endpoint(d.Webhook)
+		if !ok {
+			continue
+		}
+
+		d = o.attempt(ctx, endpoint, d, now)
+		if err := o.store.Put(Bucket, d.ID, d); err != nil {
+			o.logger.Printf("Failed to record webhook delivery %s: %v", d.ID, err)
+		}
+	}
+}
+
+// attempt sends one delivery and returns it updated with the outcome
+func (o *Outbox) attempt(ctx context.Context, endpoint settings.WebhookEndpoint, d models.WebhookDelivery, now time.Time) models.WebhookDelivery {
+	now = now.UTC().Truncate(time.Second)
+	d.Attempts++
+	d.ResponseCode, d.Error = 0, ""
+
+	code, err := o.send(ctx, endpoint, d)
+	d.ResponseCode = code
+	if err == nil {
+		d.Status = models.DeliveryDelivered
+		d.DeliveredAt, d.NextAttempt = &now, nil
+		return d
+	}
+
+	d.Error = err.Error()
+	if d.Attempts >= o.settings.MaxAttempts {
+		d.Status = models.DeliveryFailed
+		d.NextAttempt = nil
+		o.logger.Printf("Giving up on %s to %s after %d attempts: %v", d.EventType, endpoint.ID, d.Attempts, err)
+		return d
+	}
+	next := now.Add(backoff(d.Attempts))
+	d.NextAttempt = &next
+	return d
+}
+
+// send POSTs the payload, treating any 2xx response as delivered
+func (o *Outbox) send(ctx context.Context, endpoint settings.WebhookEndpoint, d models.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "OctoDash/"+version.String())
+	req.Header.Set("X-OctoDash-Event", d.EventType)
+	req.Header.Set("X-OctoDash-Delivery", d.ID)
+	if endpoint.Secret != "" {
+		req.Header.Set("X-OctoDash-Signature", Sign(endpoint.Secret, d.Payload))
+	}
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign returns the X-OctoDash-Signature value for body: sha256= and the hex HMAC
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// backoff is the wait after the given number of failed attempts
+func backoff(attempts int) time.Duration {
+	wait := firstBackoff
+	for i := 1; i < attempts && wait < maxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, maxBackoff)
+}
+
+func (o *Outbox) endpoint(id string) (settings.WebhookEndpoint, bool) {
+	for _, e := range o.settings.Endpoints {
+		if e.ID == id {
+			return e, true
+		}
+	}
+	return settings.WebhookEndpoint{}, false
+}
+
+// List returns deliveries newest first, optionally for one webhook or status, up to limit (0 for all)
+func (o *Outbox) List(webhook, status string, limit int) ([]models.WebhookDelivery, error) {
+	all, err := store.List[models.WebhookDelivery](o.store, Bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(all, func(i, j int) bool { return all[i].ID > all[j].ID })
+
+	deliveries := []models.WebhookDelivery{}
+	for _, d := range all {
+		if limit > 0 && len(deliveries) >= limit {
+			break
+		}
+		if (webhook == "" || d.Webhook == webhook) && (status == "" || d.Status == status) {
+			deliveries = append(deliveries, d)
+		}
+	}
+	return deliveries, nil
+}
+
+// Retry queues a delivery to be sent again on the next run, with fresh attempts
+func (o *Outbox) Retry(id string, now time.Time) (models.WebhookDelivery, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var d models.WebhookDelivery
+	ok, err := o.store.Get(Bucket, id, &d)
+	if err != nil {
+		return d, err
+	}
+	if !ok {
+		return d, ErrNotFound
+	}
+
+	now = now.UTC().Truncate(time.Second)
+	d.Status = models.DeliveryPending
+	d.Attempts = 0
+	d.NextAttempt = &now
+	return d, o.store.Put(Bucket, id, d)
+}
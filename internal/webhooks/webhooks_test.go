@@ -0,0 +1,162 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+	"github.com/wmarchesi123/octodash/internal/settings"
+	"github.com/wmarchesi123/octodash/internal/store"
+)
+
+// receiver is a webhook endpoint answering with whatever status is set
+type receiver struct {
+	*httptest.Server
+	status atomic.Int32
+	hits   atomic.Int32
+}
+
+func newReceiver(t *testing.T) *receiver {
+	r := &receiver{}
+	r.status.Store(http.StatusOK)
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		r.hits.Add(1)
+		w.WriteHeader(int(r.status.Load()))
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func newOutbox(t *testing.T, url string, maxAttempts, retain int) *Outbox {
+	s, err := store.Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return New(s, settings.WebhookSettings{
+		Endpoints:   []settings.WebhookEndpoint{{ID: "webhook-1", URL: url}},
+		Timeout:     time.Second,
+		MaxAttempts: maxAttempts,
+		Retain:      retain,
+	}, log.New(io.Discard, "", 0))
+}
+
+func TestBackoff(t *testing.T) {
+	for attempts, want := range map[int]time.Duration{
+		1:  30 * time.Second,
+		2:  time.Minute,
+		3:  2 * time.Minute,
+		7:  32 * time.Minute,
+		8:  time.Hour,
+		20: time.Hour,
+	} {
+		if got := backoff(attempts); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", attempts, got, want)
+		}
+	}
+}
+
+func TestDeliverRetriesUntilMaxAttempts(t *testing.T) {
+	r := newReceiver(t)
+	r.status.Store(http.StatusServiceUnavailable)
+	o := newOutbox(t, r.URL, 3, 100)
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := o.Publish(models.Event{ID: "1", Type: models.EventJobFinished, Time: now}); err != nil {
+		t.Fatal(err)
+	}
+	delivery := func() models.WebhookDelivery {
+		list, _ := o.List("", "", 0)
+		return list[0]
+	}
+
+	o.Deliver(context.Background(), now)
+	if d := delivery(); d.Status != models.DeliveryPending || d.Attempts != 1 || !d.NextAttempt.Equal(now.Add(30*time.Second)) {
+		t.Fatalf("after one failure = %s, %d attempts, next %v", d.Status, d.Attempts, d.NextAttempt)
+	}
+
+	// Not due yet: no attempt
+	o.Deliver(context.Background(), now.Add(10*time.Second))
+	if n := r.hits.Load(); n != 1 {
+		t.Errorf("attempted %d times before the backoff elapsed, want 1", n)
+	}
+
+	o.Deliver(context.Background(), now.Add(time.Minute))
+	o.Deliver(context.Background(), now.Add(time.Hour))
+	if d := delivery(); d.Status != models.DeliveryFailed || d.Attempts != 3 || d.NextAttempt != nil || d.ResponseCode != http.StatusServiceUnavailable {
+		t.Fatalf("after max attempts = %+v", d)
+	}
+	o.Deliver(context.Background(), now.Add(2*time.Hour))
+	if n := r.hits.Load(); n != 3 {
+		t.Errorf("receiver hit %d times, want 3", n)
+	}
+
+	// A retry starts afresh and goes through once the receiver is back
+	r.status.Store(http.StatusNoContent)
+	if _, err := o.Retry(delivery().ID, now.Add(3*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	o.Deliver(context.Background(), now.Add(3*time.Hour))
+	if d := delivery(); d.Status != models.DeliveryDelivered || d.Attempts != 1 || d.DeliveredAt == nil {
+		t.Errorf("after retry = %+v", d)
+	}
+	if _, err := o.Retry("missing", now); err != ErrNotFound {
+		t.Errorf("retry of an unknown delivery = %v, want ErrNotFound", err)
+	}
+}
+
+func TestTrimKeepsPending(t *testing.T) {
+	r := newReceiver(t)
+	o := newOutbox(t, r.URL, 3, 2)
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	publish := func(n int) {
+		for i := 0; i < n; i++ {
+			if err := o.Publish(models.Event{Type: models.EventJobFinished, Time: now}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	// Two delivered, then an outage queues more than Retain
+	publish(2)
+	o.Deliver(context.Background(), now)
+	r.status.Store(http.StatusBadGateway)
+	publish(5)
+
+	pending, _ := o.List("", models.DeliveryPending, 0)
+	delivered, _ := o.List("", models.DeliveryDelivered, 0)
+	if len(pending) != 5 || len(delivered) != 0 {
+		t.Errorf("after the outage %d pending and %d delivered, want 5 and 0", len(pending), len(delivered))
+	}
+
+	r.status.Store(http.StatusOK)
+	o.Deliver(context.Background(), now)
+	publish(1)
+	all, _ := o.List("", "", 0)
+	if len(all) != 2 {
+		t.Errorf("log keeps %d deliveries once caught up, want 2", len(all))
+	}
+}
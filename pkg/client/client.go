@@ -0,0 +1,272 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package client is a Go client for the OctoDash HTTP API, for tools and bots
+// that talk to a running server. It is versioned with OctoDash itself and
+// decodes responses into the server's own types, whose wire format is pinned
+// by golden tests, so a client from one release keeps working with the next.
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/models"
+)
+
+// Types returned by the API
+type (
+	StatusResponse = models.StatusResponse
+	PrinterStatus  = models.PrinterStatus
+	QueueEntry     = models.QueueEntry
+	Announcement   = models.Announcement
+	Command        = models.Command
+	Banner         = models.Banner
+)
+
+// Job actions accepted by PrinterAction
+const (
+	ActionPause  = "pause"
+	ActionResume = "resume"
+	ActionCancel = "cancel"
+)
+
+// APIError is a request the server answered with an error status
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("HTTP %d: %s", e.StatusCode, e.Message)
+}
+
+// Client talks to a running OctoDash server's HTTP API
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// Option customizes a Client
+type Option func(*Client)
+
+// WithHTTPClient sends requests through hc instead of a client with a 30
+// second timeout. The announcement stream uses its transport but no timeout.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
+// New creates a client for the server at baseURL; token may be empty when auth is disabled
+func New(baseURL, token string, opts ...Option) *Client {
+	c := &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Status returns every printer's current status
+func (c *Client) Status(ctx context.Context) (*StatusResponse, error) {
+	var response StatusResponse
+	if err := c.do(ctx, "GET", "/api/status", nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ResolvePrinter finds a printer by ID or case-insensitive name
+func (c *Client) ResolvePrinter(ctx context.Context, nameOrID string) (*PrinterStatus, error) {
+	status, err := c.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range status.Printers {
+		if p.ID == nameOrID || strings.EqualFold(p.Name, nameOrID) {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("no printer named %q", nameOrID)
+}
+
+// PrinterAction runs a job action (pause, resume, cancel) on a printer
+func (c *Client) PrinterAction(ctx context.Context, printerID, action string) error {
+	return c.do(ctx, "POST", "/api/printers/"+url.PathEscape(printerID)+"/"+url.PathEscape(action), nil, nil)
+}
+
+// EmergencyStop halts a printer immediately
+func (c *Client) EmergencyStop(ctx context.Context, printerID string) error {
+	return c.do(ctx, "POST", "/api/printers/"+url.PathEscape(printerID)+"/emergency-stop", nil, nil)
+}
+
+// Command reports how far a printer command has got, from accepted to
+// confirmed by the printer's status
+func (c *Client) Command(ctx context.Context, id string) (*Command, error) {
+	var response struct {
+		Command Command `json:"command"`
+	}
+	if err := c.do(ctx, "GET", "/api/commands/"+url.PathEscape(id), nil, &response); err != nil {
+		return nil, err
+	}
+	return &response.Command, nil
+}
+
+// QueueList returns the print queue
+func (c *Client) QueueList(ctx context.Context) ([]QueueEntry, error) {
+	var response struct {
+		Queue []QueueEntry `json:"queue"`
+	}
+	if err := c.do(ctx, "GET", "/api/queue", nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Queue, nil
+}
+
+// QueueAdd queues a file, optionally pinned to a printer
+func (c *Client) QueueAdd(ctx context.Context, file, printerID string) (*QueueEntry, error) {
+	body := map[string]string{"file": file, "printer_id": printerID}
+
+	var response struct {
+		Entry QueueEntry `json:"entry"`
+	}
+	if err := c.do(ctx, "POST", "/api/queue", body, &response); err != nil {
+		return nil, err
+	}
+	return &response.Entry, nil
+}
+
+// QueueUploadModel queues an STL or 3MF model for slicing, optionally pinned to a printer
+func (c *Client) QueueUploadModel(ctx context.Context, name string, model io.Reader, printerID string) (*QueueEntry, error) {
+	q := url.Values{"file": {name}}
+	if printerID != "" {
+		q.Set("printer_id", printerID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/api/queue/models?"+q.Encode(), model)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	var response struct {
+		Entry QueueEntry `json:"entry"`
+	}
+	if err := c.send(req, &response); err != nil {
+		return nil, err
+	}
+	return &response.Entry, nil
+}
+
+// QueueRemove deletes a queue entry
+func (c *Client) QueueRemove(ctx context.Context, id string) error {
+	return c.do(ctx, "DELETE", "/api/queue/"+url.PathEscape(id), nil, nil)
+}
+
+// Announce reads a message out on every kiosk display; level is "info" or "alert"
+func (c *Client) Announce(ctx context.Context, text, level string) (*Announcement, error) {
+	body := map[string]string{"text": text, "level": level}
+
+	var response struct {
+		Announcement Announcement `json:"announcement"`
+	}
+	if err := c.do(ctx, "POST", "/api/announcements", body, &response); err != nil {
+		return nil, err
+	}
+	return &response.Announcement, nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, result interface{}) error {
+	var bodyReader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		bodyReader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bodyReader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return c.send(req, result)
+}
+
+// send adds credentials to req and decodes a JSON response into result
+func (c *Client) send(req *http.Request, result interface{}) error {
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	// Lets the server drop a repeat if the caller retries after a lost response
+	if req.Method == "POST" {
+		req.Header.Set("Idempotency-Key", newKey())
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return responseError(resp)
+	}
+
+	if result != nil {
+		return json.NewDecoder(resp.Body).Decode(result)
+	}
+	return nil
+}
+
+// responseError extracts the server's error message from a JSON or plain-text body
+func responseError(resp *http.Response) error {
+	data, _ := io.ReadAll(resp.Body)
+
+	var apiErr struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(data, &apiErr) == nil && apiErr.Error != "" {
+		return &APIError{StatusCode: resp.StatusCode, Message: apiErr.Error}
+	}
+	return &APIError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(data))}
+}
+
+func newKey() string {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,122 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/wmarchesi123/octodash/internal/handlers"
+	"github.com/wmarchesi123/octodash/internal/settings"
+	"github.com/wmarchesi123/octodash/internal/testutil"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	s, err := settings.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.DataDir = t.TempDir()
+	cfg := testutil.Config(testutil.NewSpoolman(t), testutil.Printer{Name: "Mini", Server: testutil.NewOctoPrint(t)})
+	h, err := handlers.NewHandler(cfg, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(h)
+	t.Cleanup(func() {
+		srv.Close()
+		h.Close()
+	})
+	return srv
+}
+
+func TestClient(t *testing.T) {
+	t.Setenv("AUTH_USERS", "olga:operator:op-token")
+	srv := newServer(t)
+	ctx := context.Background()
+
+	var apiErr *APIError
+	if _, err := New(srv.URL, "").QueueAdd(ctx, "benchy.gcode", ""); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("QueueAdd without a token = %v, want a 401 APIError", err)
+	}
+
+	c := New(srv.URL+"/", "op-token")
+	status, err := c.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status.Printers) != 1 || status.Printers[0].Name != "Mini" || status.Server == nil {
+		t.Errorf("Status = %+v", status)
+	}
+	if p, err := c.ResolvePrinter(ctx, "mini"); err != nil || p.ID != "printer-1" {
+		t.Errorf("ResolvePrinter(mini) = %+v, %v", p, err)
+	}
+
+	if err := c.QueueRemove(ctx, "missing"); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("QueueRemove(missing) = %v, want a 404 APIError", err)
+	}
+}
+
+func TestSubscriptions(t *testing.T) {
+	srv := newServer(t)
+	c := New(srv.URL, "")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	statuses := c.SubscribeStatus(ctx, 10*time.Millisecond)
+	first, second := <-statuses, <-statuses
+	if first.Err != nil || second.Err != nil {
+		t.Fatalf("status updates failed: %v, %v", first.Err, second.Err)
+	}
+	if second.Status.Sequence <= first.Status.Sequence {
+		t.Errorf("sequence went from %d to %d", first.Status.Sequence, second.Status.Sequence)
+	}
+
+	// Announcements made before the stream connects aren't sent, so keep
+	// announcing until one arrives
+	announcements := c.SubscribeAnnouncements(ctx)
+	retry := time.NewTicker(50 * time.Millisecond)
+	defer retry.Stop()
+	for received := false; !received; {
+		if _, err := c.Announce(ctx, "Benchy is done", "info"); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case update := <-announcements:
+			if update.Err != nil || update.Announcement.Text != "Benchy is done" {
+				t.Errorf("announcement update = %+v, %v", update.Announcement, update.Err)
+			}
+			received = true
+		case <-retry.C:
+		case <-ctx.Done():
+			t.Fatal("no announcement received")
+		}
+	}
+
+	cancel()
+	for range statuses {
+	}
+	for range announcements {
+	}
+}
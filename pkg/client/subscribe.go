@@ -0,0 +1,161 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// streamRetry is how long a subscription waits before reconnecting
+const streamRetry = 5 * time.Second
+
+// StatusUpdate is one poll of a status subscription; Err is set when the
+// poll failed, and polling carries on
+type StatusUpdate struct {
+	Status *StatusResponse
+	Err    error
+}
+
+// AnnouncementUpdate is one message of an announcement subscription; Err is
+// set when the stream dropped, and it reconnects on its own
+type AnnouncementUpdate struct {
+	Announcement *Announcement
+	Err          error
+}
+
+// SubscribeStatus polls every printer's status each interval, starting
+// immediately, until ctx ends. Statuses carry an increasing Sequence, so
+// consumers can tell a fresh poll from a repeat.
+func (c *Client) SubscribeStatus(ctx context.Context, interval time.Duration) <-chan StatusUpdate {
+	updates := make(chan StatusUpdate)
+	go func() {
+		defer close(updates)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			status, err := c.Status(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+			select {
+			case updates <- StatusUpdate{Status: status, Err: err}:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return updates
+}
+
+// SubscribeAnnouncements streams announcements as they are made until ctx
+// ends. A dropped stream reconnects and first replays what it missed.
+func (c *Client) SubscribeAnnouncements(ctx context.Context) <-chan AnnouncementUpdate {
+	updates := make(chan AnnouncementUpdate)
+	go func() {
+		defer close(updates)
+		lastID := ""
+		for {
+			err := c.streamAnnouncements(ctx, &lastID, updates)
+			if ctx.Err() != nil {
+				return
+			}
+			select {
+			case updates <- AnnouncementUpdate{Err: err}:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case <-time.After(streamRetry):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return updates
+}
+
+// streamAnnouncements reads one connection's server-sent events, recording
+// the last ID seen so a reconnect resumes after it
+func (c *Client) streamAnnouncements(ctx context.Context, lastID *string, updates chan<- AnnouncementUpdate) error {
+	path := "/api/announcements/stream"
+	if *lastID != "" {
+		path += "?since=" + url.QueryEscape(*lastID)
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	// The stream stays open indefinitely, so no overall timeout
+	resp, err := (&http.Client{Transport: c.httpClient.Transport}).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp)
+	}
+
+	var data []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line != "" {
+			if value, ok := strings.CutPrefix(line, "data: "); ok {
+				data = append(data, value)
+			}
+			continue
+		}
+		if len(data) == 0 {
+			continue
+		}
+
+		var a Announcement
+		err := json.Unmarshal([]byte(strings.Join(data, "\n")), &a)
+		data = nil
+		if err != nil {
+			return fmt.Errorf("decoding announcement: %w", err)
+		}
+		*lastID = a.ID
+		select {
+		case updates <- AnnouncementUpdate{Announcement: &a}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return fmt.Errorf("announcement stream closed")
+}
@@ -0,0 +1,1061 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: octodash/v1/octodash.proto
+
+package octodashv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Action int32
+
+const (
+	Action_ACTION_UNSPECIFIED Action = 0
+	Action_ACTION_PAUSE       Action = 1
+	Action_ACTION_RESUME      Action = 2
+	Action_ACTION_CANCEL      Action = 3
+)
+
+// Enum value maps for Action.
+var (
+	Action_name = map[int32]string{
+		0: "ACTION_UNSPECIFIED",
+		1: "ACTION_PAUSE",
+		2: "ACTION_RESUME",
+		3: "ACTION_CANCEL",
+	}
+	Action_value = map[string]int32{
+		"ACTION_UNSPECIFIED": 0,
+		"ACTION_PAUSE":       1,
+		"ACTION_RESUME":      2,
+		"ACTION_CANCEL":      3,
+	}
+)
+
+func (x Action) Enum() *Action {
+	p := new(Action)
+	*p = x
+	return p
+}
+
+func (x Action) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Action) Descriptor() protoreflect.EnumDescriptor {
+	return file_octodash_v1_octodash_proto_enumTypes[0].Descriptor()
+}
+
+func (Action) Type() protoreflect.EnumType {
+	return &file_octodash_v1_octodash_proto_enumTypes[0]
+}
+
+func (x Action) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Action.Descriptor instead.
+func (Action) EnumDescriptor() ([]byte, []int) {
+	return file_octodash_v1_octodash_proto_rawDescGZIP(), []int{0}
+}
+
+type GetStatusRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Printers to include; empty for all
+	PrinterIds []string `protobuf:"bytes,1,rep,name=printer_ids,json=printerIds,proto3" json:"printer_ids,omitempty"`
+}
+
+func (x *GetStatusRequest) Reset() {
+	*x = GetStatusRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_octodash_v1_octodash_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatusRequest) ProtoMessage() {}
+
+func (x *GetStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_octodash_v1_octodash_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetStatusRequest) Descriptor() ([]byte, []int) {
+	return file_octodash_v1_octodash_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetStatusRequest) GetPrinterIds() []string {
+	if x != nil {
+		return x.PrinterIds
+	}
+	return nil
+}
+
+type StreamStatusRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Printers to include; empty for all
+	PrinterIds []string `protobuf:"bytes,1,rep,name=printer_ids,json=printerIds,proto3" json:"printer_ids,omitempty"`
+	// Time between snapshots, at least one second; defaults to five
+	Interval *durationpb.Duration `protobuf:"bytes,2,opt,name=interval,proto3" json:"interval,omitempty"`
+}
+
+func (x *StreamStatusRequest) Reset() {
+	*x = StreamStatusRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_octodash_v1_octodash_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamStatusRequest) ProtoMessage() {}
+
+func (x *StreamStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_octodash_v1_octodash_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamStatusRequest.ProtoReflect.Descriptor instead.
+func (*StreamStatusRequest) Descriptor() ([]byte, []int) {
+	return file_octodash_v1_octodash_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *StreamStatusRequest) GetPrinterIds() []string {
+	if x != nil {
+		return x.PrinterIds
+	}
+	return nil
+}
+
+func (x *StreamStatusRequest) GetInterval() *durationpb.Duration {
+	if x != nil {
+		return x.Interval
+	}
+	return nil
+}
+
+type StatusSnapshot struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PolledAt *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=polled_at,json=polledAt,proto3" json:"polled_at,omitempty"`
+	Sequence uint64                 `protobuf:"varint,2,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Printers []*PrinterStatus       `protobuf:"bytes,3,rep,name=printers,proto3" json:"printers,omitempty"`
+}
+
+func (x *StatusSnapshot) Reset() {
+	*x = StatusSnapshot{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_octodash_v1_octodash_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StatusSnapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusSnapshot) ProtoMessage() {}
+
+func (x *StatusSnapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_octodash_v1_octodash_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusSnapshot.ProtoReflect.Descriptor instead.
+func (*StatusSnapshot) Descriptor() ([]byte, []int) {
+	return file_octodash_v1_octodash_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *StatusSnapshot) GetPolledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PolledAt
+	}
+	return nil
+}
+
+func (x *StatusSnapshot) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *StatusSnapshot) GetPrinters() []*PrinterStatus {
+	if x != nil {
+		return x.Printers
+	}
+	return nil
+}
+
+type PrinterStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id   string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// idle, printing, paused, completed, cooldown, error or offline
+	Status string `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	// OctoPrint's own state text
+	State        string        `protobuf:"bytes,4,opt,name=state,proto3" json:"state,omitempty"`
+	Error        string        `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	Progress     *Progress     `protobuf:"bytes,6,opt,name=progress,proto3" json:"progress,omitempty"`
+	Temperatures *Temperatures `protobuf:"bytes,7,opt,name=temperatures,proto3" json:"temperatures,omitempty"`
+	// When the printer last answered, set while it's offline
+	LastSeen *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`
+}
+
+func (x *PrinterStatus) Reset() {
+	*x = PrinterStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_octodash_v1_octodash_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PrinterStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PrinterStatus) ProtoMessage() {}
+
+func (x *PrinterStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_octodash_v1_octodash_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PrinterStatus.ProtoReflect.Descriptor instead.
+func (*PrinterStatus) Descriptor() ([]byte, []int) {
+	return file_octodash_v1_octodash_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PrinterStatus) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PrinterStatus) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PrinterStatus) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *PrinterStatus) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *PrinterStatus) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *PrinterStatus) GetProgress() *Progress {
+	if x != nil {
+		return x.Progress
+	}
+	return nil
+}
+
+func (x *PrinterStatus) GetTemperatures() *Temperatures {
+	if x != nil {
+		return x.Temperatures
+	}
+	return nil
+}
+
+func (x *PrinterStatus) GetLastSeen() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastSeen
+	}
+	return nil
+}
+
+type Progress struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Completion           float64                `protobuf:"fixed64,1,opt,name=completion,proto3" json:"completion,omitempty"`
+	PrintTimeSeconds     int32                  `protobuf:"varint,2,opt,name=print_time_seconds,json=printTimeSeconds,proto3" json:"print_time_seconds,omitempty"`
+	PrintTimeLeftSeconds int32                  `protobuf:"varint,3,opt,name=print_time_left_seconds,json=printTimeLeftSeconds,proto3" json:"print_time_left_seconds,omitempty"`
+	FileName             string                 `protobuf:"bytes,4,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	Eta                  *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=eta,proto3" json:"eta,omitempty"`
+}
+
+func (x *Progress) Reset() {
+	*x = Progress{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_octodash_v1_octodash_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Progress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Progress) ProtoMessage() {}
+
+func (x *Progress) ProtoReflect() protoreflect.Message {
+	mi := &file_octodash_v1_octodash_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Progress.ProtoReflect.Descriptor instead.
+func (*Progress) Descriptor() ([]byte, []int) {
+	return file_octodash_v1_octodash_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Progress) GetCompletion() float64 {
+	if x != nil {
+		return x.Completion
+	}
+	return 0
+}
+
+func (x *Progress) GetPrintTimeSeconds() int32 {
+	if x != nil {
+		return x.PrintTimeSeconds
+	}
+	return 0
+}
+
+func (x *Progress) GetPrintTimeLeftSeconds() int32 {
+	if x != nil {
+		return x.PrintTimeLeftSeconds
+	}
+	return 0
+}
+
+func (x *Progress) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+func (x *Progress) GetEta() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Eta
+	}
+	return nil
+}
+
+type Temperatures struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BedActual    float64 `protobuf:"fixed64,1,opt,name=bed_actual,json=bedActual,proto3" json:"bed_actual,omitempty"`
+	BedTarget    float64 `protobuf:"fixed64,2,opt,name=bed_target,json=bedTarget,proto3" json:"bed_target,omitempty"`
+	HotendActual float64 `protobuf:"fixed64,3,opt,name=hotend_actual,json=hotendActual,proto3" json:"hotend_actual,omitempty"`
+	HotendTarget float64 `protobuf:"fixed64,4,opt,name=hotend_target,json=hotendTarget,proto3" json:"hotend_target,omitempty"`
+}
+
+func (x *Temperatures) Reset() {
+	*x = Temperatures{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_octodash_v1_octodash_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Temperatures) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Temperatures) ProtoMessage() {}
+
+func (x *Temperatures) ProtoReflect() protoreflect.Message {
+	mi := &file_octodash_v1_octodash_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Temperatures.ProtoReflect.Descriptor instead.
+func (*Temperatures) Descriptor() ([]byte, []int) {
+	return file_octodash_v1_octodash_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Temperatures) GetBedActual() float64 {
+	if x != nil {
+		return x.BedActual
+	}
+	return 0
+}
+
+func (x *Temperatures) GetBedTarget() float64 {
+	if x != nil {
+		return x.BedTarget
+	}
+	return 0
+}
+
+func (x *Temperatures) GetHotendActual() float64 {
+	if x != nil {
+		return x.HotendActual
+	}
+	return 0
+}
+
+func (x *Temperatures) GetHotendTarget() float64 {
+	if x != nil {
+		return x.HotendTarget
+	}
+	return 0
+}
+
+type PrinterActionRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PrinterId string `protobuf:"bytes,1,opt,name=printer_id,json=printerId,proto3" json:"printer_id,omitempty"`
+	Action    Action `protobuf:"varint,2,opt,name=action,proto3,enum=octodash.v1.Action" json:"action,omitempty"`
+}
+
+func (x *PrinterActionRequest) Reset() {
+	*x = PrinterActionRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_octodash_v1_octodash_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PrinterActionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PrinterActionRequest) ProtoMessage() {}
+
+func (x *PrinterActionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_octodash_v1_octodash_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PrinterActionRequest.ProtoReflect.Descriptor instead.
+func (*PrinterActionRequest) Descriptor() ([]byte, []int) {
+	return file_octodash_v1_octodash_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *PrinterActionRequest) GetPrinterId() string {
+	if x != nil {
+		return x.PrinterId
+	}
+	return ""
+}
+
+func (x *PrinterActionRequest) GetAction() Action {
+	if x != nil {
+		return x.Action
+	}
+	return Action_ACTION_UNSPECIFIED
+}
+
+type PrinterActionResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *PrinterActionResponse) Reset() {
+	*x = PrinterActionResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_octodash_v1_octodash_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PrinterActionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PrinterActionResponse) ProtoMessage() {}
+
+func (x *PrinterActionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_octodash_v1_octodash_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PrinterActionResponse.ProtoReflect.Descriptor instead.
+func (*PrinterActionResponse) Descriptor() ([]byte, []int) {
+	return file_octodash_v1_octodash_proto_rawDescGZIP(), []int{7}
+}
+
+type EmergencyStopRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PrinterId    string `protobuf:"bytes,1,opt,name=printer_id,json=printerId,proto3" json:"printer_id,omitempty"`
+	ConfirmToken string `protobuf:"bytes,2,opt,name=confirm_token,json=confirmToken,proto3" json:"confirm_token,omitempty"`
+}
+
+func (x *EmergencyStopRequest) Reset() {
+	*x = EmergencyStopRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_octodash_v1_octodash_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EmergencyStopRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmergencyStopRequest) ProtoMessage() {}
+
+func (x *EmergencyStopRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_octodash_v1_octodash_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmergencyStopRequest.ProtoReflect.Descriptor instead.
+func (*EmergencyStopRequest) Descriptor() ([]byte, []int) {
+	return file_octodash_v1_octodash_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *EmergencyStopRequest) GetPrinterId() string {
+	if x != nil {
+		return x.PrinterId
+	}
+	return ""
+}
+
+func (x *EmergencyStopRequest) GetConfirmToken() string {
+	if x != nil {
+		return x.ConfirmToken
+	}
+	return ""
+}
+
+type EmergencyStopResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Set on the first step, to be sent back to confirm
+	ConfirmToken string                 `protobuf:"bytes,1,opt,name=confirm_token,json=confirmToken,proto3" json:"confirm_token,omitempty"`
+	ExpiresAt    *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// Whether M112 was sent
+	Stopped bool `protobuf:"varint,3,opt,name=stopped,proto3" json:"stopped,omitempty"`
+}
+
+func (x *EmergencyStopResponse) Reset() {
+	*x = EmergencyStopResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_octodash_v1_octodash_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EmergencyStopResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmergencyStopResponse) ProtoMessage() {}
+
+func (x *EmergencyStopResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_octodash_v1_octodash_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmergencyStopResponse.ProtoReflect.Descriptor instead.
+func (*EmergencyStopResponse) Descriptor() ([]byte, []int) {
+	return file_octodash_v1_octodash_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *EmergencyStopResponse) GetConfirmToken() string {
+	if x != nil {
+		return x.ConfirmToken
+	}
+	return ""
+}
+
+func (x *EmergencyStopResponse) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *EmergencyStopResponse) GetStopped() bool {
+	if x != nil {
+		return x.Stopped
+	}
+	return false
+}
+
+var File_octodash_v1_octodash_proto protoreflect.FileDescriptor
+
+var file_octodash_v1_octodash_proto_rawDesc = []byte{
+	0x0a, 0x1a, 0x6f, 0x63, 0x74, 0x6f, 0x64, 0x61, 0x73, 0x68, 0x2f, 0x76, 0x31, 0x2f, 0x6f, 0x63,
+	0x74, 0x6f, 0x64, 0x61, 0x73, 0x68, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0b, 0x6f, 0x63,
+	0x74, 0x6f, 0x64, 0x61, 0x73, 0x68, 0x2e, 0x76, 0x31, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x33, 0x0a, 0x10, 0x47, 0x65,
+	0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f,
+	0x0a, 0x0b, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x49, 0x64, 0x73, 0x22,
+	0x6d, 0x0a, 0x13, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x65,
+	0x72, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x72, 0x69,
+	0x6e, 0x74, 0x65, 0x72, 0x49, 0x64, 0x73, 0x12, 0x35, 0x0a, 0x08, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x76, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x22, 0x9d,
+	0x01, 0x0a, 0x0e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f,
+	0x74, 0x12, 0x37, 0x0a, 0x09, 0x70, 0x6f, 0x6c, 0x6c, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x08, 0x70, 0x6f, 0x6c, 0x6c, 0x65, 0x64, 0x41, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65,
+	0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x73, 0x65,
+	0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x36, 0x0a, 0x08, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x65,
+	0x72, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x6f, 0x63, 0x74, 0x6f, 0x64,
+	0x61, 0x73, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x52, 0x08, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x73, 0x22, 0xa2,
+	0x02, 0x0a, 0x0d, 0x50, 0x72, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64,
+	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x14, 0x0a, 0x05,
+	0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61,
+	0x74, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x31, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x67,
+	0x72, 0x65, 0x73, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6f, 0x63, 0x74,
+	0x6f, 0x64, 0x61, 0x73, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73,
+	0x73, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x3d, 0x0a, 0x0c, 0x74,
+	0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x19, 0x2e, 0x6f, 0x63, 0x74, 0x6f, 0x64, 0x61, 0x73, 0x68, 0x2e, 0x76, 0x31, 0x2e,
+	0x54, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x52, 0x0c, 0x74, 0x65,
+	0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x12, 0x37, 0x0a, 0x09, 0x6c, 0x61,
+	0x73, 0x74, 0x5f, 0x73, 0x65, 0x65, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x53,
+	0x65, 0x65, 0x6e, 0x22, 0xda, 0x01, 0x0a, 0x08, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73,
+	0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x2c, 0x0a, 0x12, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x73,
+	0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x10, 0x70, 0x72,
+	0x69, 0x6e, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x12, 0x35,
+	0x0a, 0x17, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x6c, 0x65, 0x66,
+	0x74, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x14, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x4c, 0x65, 0x66, 0x74, 0x53, 0x65,
+	0x63, 0x6f, 0x6e, 0x64, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x4e, 0x61,
+	0x6d, 0x65, 0x12, 0x2c, 0x0a, 0x03, 0x65, 0x74, 0x61, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x03, 0x65, 0x74, 0x61,
+	0x22, 0x96, 0x01, 0x0a, 0x0c, 0x54, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x62, 0x65, 0x64, 0x5f, 0x61, 0x63, 0x74, 0x75, 0x61, 0x6c, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x62, 0x65, 0x64, 0x41, 0x63, 0x74, 0x75, 0x61, 0x6c,
+	0x12, 0x1d, 0x0a, 0x0a, 0x62, 0x65, 0x64, 0x5f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x62, 0x65, 0x64, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12,
+	0x23, 0x0a, 0x0d, 0x68, 0x6f, 0x74, 0x65, 0x6e, 0x64, 0x5f, 0x61, 0x63, 0x74, 0x75, 0x61, 0x6c,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0c, 0x68, 0x6f, 0x74, 0x65, 0x6e, 0x64, 0x41, 0x63,
+	0x74, 0x75, 0x61, 0x6c, 0x12, 0x23, 0x0a, 0x0d, 0x68, 0x6f, 0x74, 0x65, 0x6e, 0x64, 0x5f, 0x74,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0c, 0x68, 0x6f, 0x74,
+	0x65, 0x6e, 0x64, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x22, 0x62, 0x0a, 0x14, 0x50, 0x72, 0x69,
+	0x6e, 0x74, 0x65, 0x72, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x49, 0x64,
+	0x12, 0x2b, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x13, 0x2e, 0x6f, 0x63, 0x74, 0x6f, 0x64, 0x61, 0x73, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x41,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x17, 0x0a,
+	0x15, 0x50, 0x72, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x5a, 0x0a, 0x14, 0x45, 0x6d, 0x65, 0x72, 0x67, 0x65,
+	0x6e, 0x63, 0x79, 0x53, 0x74, 0x6f, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d,
+	0x0a, 0x0a, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x49, 0x64, 0x12, 0x23, 0x0a,
+	0x0d, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x54, 0x6f, 0x6b,
+	0x65, 0x6e, 0x22, 0x91, 0x01, 0x0a, 0x15, 0x45, 0x6d, 0x65, 0x72, 0x67, 0x65, 0x6e, 0x63, 0x79,
+	0x53, 0x74, 0x6f, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x23, 0x0a, 0x0d,
+	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0c, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x54, 0x6f, 0x6b, 0x65,
+	0x6e, 0x12, 0x39, 0x0a, 0x0a, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x5f, 0x61, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x09, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x41, 0x74, 0x12, 0x18, 0x0a, 0x07,
+	0x73, 0x74, 0x6f, 0x70, 0x70, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73,
+	0x74, 0x6f, 0x70, 0x70, 0x65, 0x64, 0x2a, 0x58, 0x0a, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x16, 0x0a, 0x12, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45,
+	0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x10, 0x0a, 0x0c, 0x41, 0x43, 0x54, 0x49,
+	0x4f, 0x4e, 0x5f, 0x50, 0x41, 0x55, 0x53, 0x45, 0x10, 0x01, 0x12, 0x11, 0x0a, 0x0d, 0x41, 0x43,
+	0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x52, 0x45, 0x53, 0x55, 0x4d, 0x45, 0x10, 0x02, 0x12, 0x11, 0x0a,
+	0x0d, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x43, 0x41, 0x4e, 0x43, 0x45, 0x4c, 0x10, 0x03,
+	0x32, 0xd4, 0x02, 0x0a, 0x08, 0x4f, 0x63, 0x74, 0x6f, 0x44, 0x61, 0x73, 0x68, 0x12, 0x47, 0x0a,
+	0x09, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1d, 0x2e, 0x6f, 0x63, 0x74,
+	0x6f, 0x64, 0x61, 0x73, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x6f, 0x63, 0x74, 0x6f,
+	0x64, 0x61, 0x73, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x53, 0x6e,
+	0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x4f, 0x0a, 0x0c, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x20, 0x2e, 0x6f, 0x63, 0x74, 0x6f, 0x64, 0x61, 0x73,
+	0x68, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x6f, 0x63, 0x74, 0x6f, 0x64,
+	0x61, 0x73, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x53, 0x6e, 0x61,
+	0x70, 0x73, 0x68, 0x6f, 0x74, 0x30, 0x01, 0x12, 0x56, 0x0a, 0x0d, 0x50, 0x72, 0x69, 0x6e, 0x74,
+	0x65, 0x72, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x21, 0x2e, 0x6f, 0x63, 0x74, 0x6f, 0x64,
+	0x61, 0x73, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x41, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x6f, 0x63,
+	0x74, 0x6f, 0x64, 0x61, 0x73, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x69, 0x6e, 0x74, 0x65,
+	0x72, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x56, 0x0a, 0x0d, 0x45, 0x6d, 0x65, 0x72, 0x67, 0x65, 0x6e, 0x63, 0x79, 0x53, 0x74, 0x6f, 0x70,
+	0x12, 0x21, 0x2e, 0x6f, 0x63, 0x74, 0x6f, 0x64, 0x61, 0x73, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x45,
+	0x6d, 0x65, 0x72, 0x67, 0x65, 0x6e, 0x63, 0x79, 0x53, 0x74, 0x6f, 0x70, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x6f, 0x63, 0x74, 0x6f, 0x64, 0x61, 0x73, 0x68, 0x2e, 0x76,
+	0x31, 0x2e, 0x45, 0x6d, 0x65, 0x72, 0x67, 0x65, 0x6e, 0x63, 0x79, 0x53, 0x74, 0x6f, 0x70, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x43, 0x5a, 0x41, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x77, 0x6d, 0x61, 0x72, 0x63, 0x68, 0x65, 0x73, 0x69, 0x31,
+	0x32, 0x33, 0x2f, 0x6f, 0x63, 0x74, 0x6f, 0x64, 0x61, 0x73, 0x68, 0x2f, 0x70, 0x6b, 0x67, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6f, 0x63, 0x74, 0x6f, 0x64, 0x61, 0x73, 0x68, 0x2f, 0x76,
+	0x31, 0x3b, 0x6f, 0x63, 0x74, 0x6f, 0x64, 0x61, 0x73, 0x68, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_octodash_v1_octodash_proto_rawDescOnce sync.Once
+	file_octodash_v1_octodash_proto_rawDescData = file_octodash_v1_octodash_proto_rawDesc
+)
+
+func file_octodash_v1_octodash_proto_rawDescGZIP() []byte {
+	file_octodash_v1_octodash_proto_rawDescOnce.Do(func() {
+		file_octodash_v1_octodash_proto_rawDescData = protoimpl.X.CompressGZIP(file_octodash_v1_octodash_proto_rawDescData)
+	})
+	return file_octodash_v1_octodash_proto_rawDescData
+}
+
+var file_octodash_v1_octodash_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_octodash_v1_octodash_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_octodash_v1_octodash_proto_goTypes = []any{
+	(Action)(0),                   // 0: octodash.v1.Action
+	(*GetStatusRequest)(nil),      // 1: octodash.v1.GetStatusRequest
+	(*StreamStatusRequest)(nil),   // 2: octodash.v1.StreamStatusRequest
+	(*StatusSnapshot)(nil),        // 3: octodash.v1.StatusSnapshot
+	(*PrinterStatus)(nil),         // 4: octodash.v1.PrinterStatus
+	(*Progress)(nil),              // 5: octodash.v1.Progress
+	(*Temperatures)(nil),          // 6: octodash.v1.Temperatures
+	(*PrinterActionRequest)(nil),  // 7: octodash.v1.PrinterActionRequest
+	(*PrinterActionResponse)(nil), // 8: octodash.v1.PrinterActionResponse
+	(*EmergencyStopRequest)(nil),  // 9: octodash.v1.EmergencyStopRequest
+	(*EmergencyStopResponse)(nil), // 10: octodash.v1.EmergencyStopResponse
+	(*durationpb.Duration)(nil),   // 11: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil), // 12: google.protobuf.Timestamp
+}
+var file_octodash_v1_octodash_proto_depIdxs = []int32{
+	11, // 0: octodash.v1.StreamStatusRequest.interval:type_name -> google.protobuf.Duration
+	12, // 1: octodash.v1.StatusSnapshot.polled_at:type_name -> google.protobuf.Timestamp
+	4,  // 2: octodash.v1.StatusSnapshot.printers:type_name -> octodash.v1.PrinterStatus
+	5,  // 3: octodash.v1.PrinterStatus.progress:type_name -> octodash.v1.Progress
+	6,  // 4: octodash.v1.PrinterStatus.temperatures:type_name -> octodash.v1.Temperatures
+	12, // 5: octodash.v1.PrinterStatus.last_seen:type_name -> google.protobuf.Timestamp
+	12, // 6: octodash.v1.Progress.eta:type_name -> google.protobuf.Timestamp
+	0,  // 7: octodash.v1.PrinterActionRequest.action:type_name -> octodash.v1.Action
+	12, // 8: octodash.v1.EmergencyStopResponse.expires_at:type_name -> google.protobuf.Timestamp
+	1,  // 9: octodash.v1.OctoDash.GetStatus:input_type -> octodash.v1.GetStatusRequest
+	2,  // 10: octodash.v1.OctoDash.StreamStatus:input_type -> octodash.v1.StreamStatusRequest
+	7,  // 11: octodash.v1.OctoDash.PrinterAction:input_type -> octodash.v1.PrinterActionRequest
+	9,  // 12: octodash.v1.OctoDash.EmergencyStop:input_type -> octodash.v1.EmergencyStopRequest
+	3,  // 13: octodash.v1.OctoDash.GetStatus:output_type -> octodash.v1.StatusSnapshot
+	3,  // 14: octodash.v1.OctoDash.StreamStatus:output_type -> octodash.v1.StatusSnapshot
+	8,  // 15: octodash.v1.OctoDash.PrinterAction:output_type -> octodash.v1.PrinterActionResponse
+	10, // 16: octodash.v1.OctoDash.EmergencyStop:output_type -> octodash.v1.EmergencyStopResponse
+	13, // [13:17] is the sub-list for method output_type
+	9,  // [9:13] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_octodash_v1_octodash_proto_init() }
+func file_octodash_v1_octodash_proto_init() {
+	if File_octodash_v1_octodash_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_octodash_v1_octodash_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*GetStatusRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_octodash_v1_octodash_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*StreamStatusRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_octodash_v1_octodash_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*StatusSnapshot); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_octodash_v1_octodash_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*PrinterStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_octodash_v1_octodash_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*Progress); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_octodash_v1_octodash_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*Temperatures); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_octodash_v1_octodash_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*PrinterActionRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_octodash_v1_octodash_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*PrinterActionResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_octodash_v1_octodash_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*EmergencyStopRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_octodash_v1_octodash_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*EmergencyStopResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_octodash_v1_octodash_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_octodash_v1_octodash_proto_goTypes,
+		DependencyIndexes: file_octodash_v1_octodash_proto_depIdxs,
+		EnumInfos:         file_octodash_v1_octodash_proto_enumTypes,
+		MessageInfos:      file_octodash_v1_octodash_proto_msgTypes,
+	}.Build()
+	File_octodash_v1_octodash_proto = out.File
+	file_octodash_v1_octodash_proto_rawDesc = nil
+	file_octodash_v1_octodash_proto_goTypes = nil
+	file_octodash_v1_octodash_proto_depIdxs = nil
+}
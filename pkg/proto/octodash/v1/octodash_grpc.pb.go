@@ -0,0 +1,277 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: octodash/v1/octodash.proto
+
+package octodashv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	OctoDash_GetStatus_FullMethodName     = "/octodash.v1.OctoDash/GetStatus"
+	OctoDash_StreamStatus_FullMethodName  = "/octodash.v1.OctoDash/StreamStatus"
+	OctoDash_PrinterAction_FullMethodName = "/octodash.v1.OctoDash/PrinterAction"
+	OctoDash_EmergencyStop_FullMethodName = "/octodash.v1.OctoDash/EmergencyStop"
+)
+
+// OctoDashClient is the client API for OctoDash service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// OctoDash serves printer status and control to integrators that want typed
+// contracts and streaming instead of polling the JSON API. Callers send the
+// same bearer tokens as the HTTP API in "authorization" metadata.
+type OctoDashClient interface {
+	// GetStatus returns the current status of every printer the caller can see
+	GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*StatusSnapshot, error)
+	// StreamStatus sends a snapshot every interval until the caller cancels
+	StreamStatus(ctx context.Context, in *StreamStatusRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StatusSnapshot], error)
+	// PrinterAction pauses, resumes or cancels the print on a printer
+	PrinterAction(ctx context.Context, in *PrinterActionRequest, opts ...grpc.CallOption) (*PrinterActionResponse, error)
+	// EmergencyStop sends M112 in two steps, like the HTTP API: a request
+	// without a confirm token returns one, and repeating the request with it
+	// within 30 seconds stops the printer
+	EmergencyStop(ctx context.Context, in *EmergencyStopRequest, opts ...grpc.CallOption) (*EmergencyStopResponse, error)
+}
+
+type octoDashClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewOctoDashClient(cc grpc.ClientConnInterface) OctoDashClient {
+	return &octoDashClient{cc}
+}
+
+func (c *octoDashClient) GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*StatusSnapshot, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatusSnapshot)
+	err := c.cc.Invoke(ctx, OctoDash_GetStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *octoDashClient) StreamStatus(ctx context.Context, in *StreamStatusRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StatusSnapshot], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &OctoDash_ServiceDesc.Streams[0], OctoDash_StreamStatus_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamStatusRequest, StatusSnapshot]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OctoDash_StreamStatusClient = grpc.ServerStreamingClient[StatusSnapshot]
+
+func (c *octoDashClient) PrinterAction(ctx context.Context, in *PrinterActionRequest, opts ...grpc.CallOption) (*PrinterActionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PrinterActionResponse)
+	err := c.cc.Invoke(ctx, OctoDash_PrinterAction_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *octoDashClient) EmergencyStop(ctx context.Context, in *EmergencyStopRequest, opts ...grpc.CallOption) (*EmergencyStopResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EmergencyStopResponse)
+	err := c.cc.Invoke(ctx, OctoDash_EmergencyStop_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// OctoDashServer is the server API for OctoDash service.
+// All implementations must embed UnimplementedOctoDashServer
+// for forward compatibility.
+//
+// OctoDash serves printer status and control to integrators that want typed
+// contracts and streaming instead of polling the JSON API. Callers send the
+// same bearer tokens as the HTTP API in "authorization" metadata.
+type OctoDashServer interface {
+	// GetStatus returns the current status of every printer the caller can see
+	GetStatus(context.Context, *GetStatusRequest) (*StatusSnapshot, error)
+	// StreamStatus sends a snapshot every interval until the caller cancels
+	StreamStatus(*StreamStatusRequest, grpc.ServerStreamingServer[StatusSnapshot]) error
+	// PrinterAction pauses, resumes or cancels the print on a printer
+	PrinterAction(context.Context, *PrinterActionRequest) (*PrinterActionResponse, error)
+	// EmergencyStop sends M112 in two steps, like the HTTP API: a request
+	// without a confirm token returns one, and repeating the request with it
+	// within 30 seconds stops the printer
+	EmergencyStop(context.Context, *EmergencyStopRequest) (*EmergencyStopResponse, error)
+	mustEmbedUnimplementedOctoDashServer()
+}
+
+// UnimplementedOctoDashServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedOctoDashServer struct{}
+
+func (UnimplementedOctoDashServer) GetStatus(context.Context, *GetStatusRequest) (*StatusSnapshot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatus not implemented")
+}
+func (UnimplementedOctoDashServer) StreamStatus(*StreamStatusRequest, grpc.ServerStreamingServer[StatusSnapshot]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamStatus not implemented")
+}
+func (UnimplementedOctoDashServer) PrinterAction(context.Context, *PrinterActionRequest) (*PrinterActionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PrinterAction not implemented")
+}
+func (UnimplementedOctoDashServer) EmergencyStop(context.Context, *EmergencyStopRequest) (*EmergencyStopResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EmergencyStop not implemented")
+}
+func (UnimplementedOctoDashServer) mustEmbedUnimplementedOctoDashServer() {}
+func (UnimplementedOctoDashServer) testEmbeddedByValue()                  {}
+
+// UnsafeOctoDashServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to OctoDashServer will
+// result in compilation errors.
+type UnsafeOctoDashServer interface {
+	mustEmbedUnimplementedOctoDashServer()
+}
+
+func RegisterOctoDashServer(s grpc.ServiceRegistrar, srv OctoDashServer) {
+	// If the following call pancis, it indicates UnimplementedOctoDashServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&OctoDash_ServiceDesc, srv)
+}
+
+func _OctoDash_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OctoDashServer).GetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OctoDash_GetStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OctoDashServer).GetStatus(ctx, req.(*GetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OctoDash_StreamStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamStatusRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OctoDashServer).StreamStatus(m, &grpc.GenericServerStream[StreamStatusRequest, StatusSnapshot]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OctoDash_StreamStatusServer = grpc.ServerStreamingServer[StatusSnapshot]
+
+func _OctoDash_PrinterAction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PrinterActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OctoDashServer).PrinterAction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OctoDash_PrinterAction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OctoDashServer).PrinterAction(ctx, req.(*PrinterActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OctoDash_EmergencyStop_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmergencyStopRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OctoDashServer).EmergencyStop(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OctoDash_EmergencyStop_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OctoDashServer).EmergencyStop(ctx, req.(*EmergencyStopRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// OctoDash_ServiceDesc is the grpc.ServiceDesc for OctoDash service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var OctoDash_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "octodash.v1.OctoDash",
+	HandlerType: (*OctoDashServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetStatus",
+			Handler:    _OctoDash_GetStatus_Handler,
+		},
+		{
+			MethodName: "PrinterAction",
+			Handler:    _OctoDash_PrinterAction_Handler,
+		},
+		{
+			MethodName: "EmergencyStop",
+			Handler:    _OctoDash_EmergencyStop_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamStatus",
+			Handler:       _OctoDash_StreamStatus_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "octodash/v1/octodash.proto",
+}
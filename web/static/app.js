@@ -16,23 +16,71 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+// Configuration from the server arrives as a JSON data block rather than as
+// script, so nothing in it can ever run
+const CONFIG = JSON.parse(document.getElementById('dashboard-config').textContent);
+
 document.addEventListener('alpine:init', () => {
     Alpine.data('dashboard', () => ({
         loading: true,
         error: null,
         printers: [],
         showReturnOverlay: false,
+        searchQuery: '',
+        screen: null,
+        clock: '',
+        idleShiftStyle: '',
+        searchResults: null,
+        reorder: [],
+        legend: [],
+        views: [],
+        plateObjects: {},
+        activeViewId: '',
+        defaultViewId: '',
         updateInterval: null,
+        announce: false,
+        announcement: null,
+        announcementTimer: null,
+        controllable: null,
+        banners: [],
+        // Whether opening a printer may log in to OctoPrint with a linked key
+        sso: false,
+        cameras: {},
+        liveCamera: {},
+        // Job actions awaiting confirmation, by printer ID
+        pendingCommands: {},
 
         async init() {
             console.log('Initializing OctoDash...');
             
             // Set up printers from config
-            this.printers = PRINTERS || [];
+            this.printers = CONFIG.printers || [];
             
             // Start fetching status
             await this.fetchStatus();
             
+            // Move idle content a few pixels every minute to avoid burn-in
+            this.tickIdleScreen();
+            setInterval(() => this.tickIdleScreen(), 60000);
+
+            // The material legend only changes with configuration
+            this.fetchLegend();
+
+            // Saved views belong to whoever's token this browser holds
+            this.fetchViews();
+            this.fetchPermissions();
+
+            // Camera links are signed for this browser's token and expire, so renew them
+            this.fetchCameras();
+            setInterval(() => this.fetchCameras(), 600000);
+
+            // Filament forecasts move slowly; refresh the reorder list every ten minutes
+            this.fetchForecast();
+            setInterval(() => this.fetchForecast(), 600000);
+
+            // Announcements arrive as they happen rather than with each poll
+            this.listenAnnouncements();
+
             // Set up polling every second
             this.updateInterval = setInterval(() => {
                 this.fetchStatus();
@@ -43,12 +91,20 @@ document.addEventListener('alpine:init', () => {
 
         async fetchStatus() {
             try {
-                const response = await fetch('/api/status');
+                // Pass the page's ?tags= filter through to the API
+                const tags = new URLSearchParams(window.location.search).get('tags');
+                const url = tags ? `/api/status?tags=${encodeURIComponent(tags)}` : '/api/status';
+                // With teams enabled the token decides which printers come back
+                const response = await fetch(url, { headers: this.tokenHeaders() });
                 if (!response.ok) {
                     throw new Error('Failed to fetch status');
                 }
                 
                 const data = await response.json();
+
+                // Server decides when to idle; any state change wakes on the next poll
+                this.screen = data.screen || null;
+                this.banners = data.banners || [];
                 
                 // Update printer data
                 if (data.printers) {
@@ -57,6 +113,19 @@ document.addEventListener('alpine:init', () => {
                         const updated = data.printers.find(p => p.id === printer.id);
                         return updated || printer;
                     });
+                    // The page only lists printers visible without a token; add the rest
+                    for (const printer of data.printers) {
+                        if (!this.printers.some(p => p.id === printer.id)) {
+                            this.printers.push(printer);
+                        }
+                    }
+                    // A status push showing the expected state settles a pending action
+                    for (const printer of this.printers) {
+                        const pending = this.pendingCommands[printer.id];
+                        if (pending && printer.status === pending.expects) {
+                            delete this.pendingCommands[printer.id];
+                        }
+                    }
                 }
             } catch (err) {
                 console.error('Error fetching status:', err);
@@ -67,7 +136,557 @@ document.addEventListener('alpine:init', () => {
             }
         },
 
-        openPrinter(printer) {
+        // apiFetch adds the stored API token to requests and prompts for one when rejected.
+        // POSTs carry an Idempotency-Key, so the one retry after a dropped connection
+        // can't run the action twice.
+        async apiFetch(url, options = {}) {
+            const headers = Object.assign({ 'Content-Type': 'application/json' }, options.headers);
+            const token = localStorage.getItem('octodash_token');
+            if (token) {
+                headers['Authorization'] = `Bearer ${token}`;
+            }
+            if (options.method === 'POST' && !headers['Idempotency-Key']) {
+                headers['Idempotency-Key'] = crypto.randomUUID
+                    ? crypto.randomUUID()
+                    : `${Date.now()}-${Math.random().toString(36).slice(2)}`;
+            }
+
+            const request = Object.assign({}, options, { headers });
+            let response;
+            try {
+                response = await fetch(url, request);
+            } catch (err) {
+                if (!headers['Idempotency-Key']) {
+                    throw err;
+                }
+                response = await fetch(url, request);
+            }
+            if (response.status === 401 || response.status === 403) {
+                const entered = prompt('This action requires an OctoDash API token:');
+                if (entered) {
+                    localStorage.setItem('octodash_token', entered);
+                    this.fetchPermissions();
+                }
+                throw new Error('Not authorized');
+            }
+            return response;
+        },
+
+        async emergencyStop(printer) {
+            const url = printer
+                ? `/api/printers/${printer.id}/emergency-stop`
+                : '/api/emergency-stop';
+            const target = printer ? printer.name : 'ALL printers';
+
+            try {
+                // Step one: ask the server for a confirmation token
+                const first = await this.apiFetch(url, { method: 'POST', body: '{}' });
+                const pending = await first.json();
+                if (!pending.confirm_token) {
+                    throw new Error(pending.error || 'No confirmation token issued');
+                }
+
+                if (!confirm(`Send EMERGENCY STOP (M112) to ${target}? The printer will need a reset afterwards.`)) {
+                    return;
+                }
+
+                // Step two: echo the token back to actually stop
+                const second = await this.apiFetch(url, {
+                    method: 'POST',
+                    body: JSON.stringify({ confirm_token: pending.confirm_token })
+                });
+                const data = await second.json();
+                const failed = (data.results || []).filter(r => !r.success);
+                if (!second.ok || failed.length > 0) {
+                    alert('Emergency stop failed for: ' + (failed.map(r => r.name).join(', ') || data.error));
+                }
+            } catch (err) {
+                console.error('Emergency stop failed:', err);
+            }
+        },
+
+        // acknowledge confirms a finished part was taken off the bed
+        async acknowledge(printer) {
+            try {
+                const response = await this.apiFetch(`/api/printers/${printer.id}/acknowledge`, { method: 'POST', body: '{}' });
+                if (!response.ok) {
+                    const data = await response.json();
+                    throw new Error(data.error || 'Acknowledge failed');
+                }
+                await this.fetchStatus();
+            } catch (err) {
+                console.error('Acknowledge failed:', err);
+            }
+        },
+
+        diagnosisLabel(diagnosis) {
+            if (!diagnosis) {
+                return '';
+            }
+            return `${diagnosis.summary}. ${diagnosis.action}.`;
+        },
+
+        stallLabel(stall) {
+            if (!stall) {
+                return '';
+            }
+            const minutes = Math.round((Date.now() - new Date(stall.since)) / 60000);
+            return `No progress for ${minutes} min - OctoPrint or the printer connection may be stuck`;
+        },
+
+        interruptedLabel(interrupted) {
+            if (!interrupted) {
+                return '';
+            }
+            let label = `Possible interrupted print: ${interrupted.file} at ${Math.round(interrupted.completion)}%`;
+            if (interrupted.layer) {
+                label += ` · layer ${interrupted.layer}/${interrupted.layers}`;
+            }
+            if (interrupted.height) {
+                label += ` · Z ${interrupted.height.toFixed(2)} mm`;
+            }
+            return label;
+        },
+
+        // dismissInterrupted clears the alert once the print has been recovered or abandoned
+        async dismissInterrupted(printer) {
+            try {
+                const response = await this.apiFetch(`/api/printers/${printer.id}/interrupted`, { method: 'DELETE' });
+                if (!response.ok) {
+                    const data = await response.json();
+                    throw new Error(data.error || 'Dismiss failed');
+                }
+                await this.fetchStatus();
+            } catch (err) {
+                console.error('Dismiss failed:', err);
+            }
+        },
+
+        // tickChecklist records a pre-print check; the queue waits until all are ticked
+        async tickChecklist(printer, item, checked) {
+            try {
+                const response = await this.apiFetch(`/api/printers/${printer.id}/checklist`, {
+                    method: 'POST',
+                    body: JSON.stringify({ item: item.name, checked })
+                });
+                if (!response.ok) {
+                    const data = await response.json();
+                    throw new Error(data.error || 'Checklist update failed');
+                }
+                await this.fetchStatus();
+            } catch (err) {
+                console.error('Checklist update failed:', err);
+                await this.fetchStatus();
+            }
+        },
+
+        // displayStatus shows the state a pending job action expects until a poll settles it
+        displayStatus(printer) {
+            return this.pendingCommands[printer.id]?.expects || printer.status;
+        },
+
+        // jobAction pauses, resumes or cancels a print, showing the expected state
+        // straight away and reverting it if the command fails or never takes effect
+        async jobAction(printer, action) {
+            if (action === 'cancel' && !confirm(`Cancel the print on ${printer.name}?`)) {
+                return;
+            }
+            try {
+                const response = await this.apiFetch(`/api/printers/${printer.id}/${action}`, {
+                    method: 'POST',
+                    body: '{}',
+                    headers: { 'Prefer': 'respond-async' }
+                });
+                const data = await response.json();
+                if (!response.ok) {
+                    throw new Error(data.error || `${action} failed`);
+                }
+                let command = data.command;
+                if (!command.expects) {
+                    return;
+                }
+                this.pendingCommands[printer.id] = command;
+
+                while (['accepted', 'sending', 'sent'].includes(command.status)) {
+                    await new Promise(resolve => setTimeout(resolve, 1000));
+                    if (this.pendingCommands[printer.id]?.id !== command.id) {
+                        return;
+                    }
+                    const poll = await this.apiFetch(`/api/commands/${command.id}`);
+                    if (!poll.ok) {
+                        break;
+                    }
+                    command = (await poll.json()).command;
+                }
+                if (this.pendingCommands[printer.id]?.id === command.id) {
+                    delete this.pendingCommands[printer.id];
+                }
+                if (command.status === 'failed') {
+                    throw new Error(`${printer.name}: ${command.error}`);
+                }
+                await this.fetchStatus();
+            } catch (err) {
+                alert(err.message);
+            }
+        },
+
+        macrosFor(printer) {
+            return (CONFIG.macros || []).filter(m => !m.printers || m.printers.length === 0 || m.printers.includes(printer.id));
+        },
+
+        // runMacro sends a configured macro; ones flagged for confirmation need a second request
+        async runMacro(printer, macro) {
+            const url = `/api/printers/${printer.id}/macros/${macro.id}`;
+            try {
+                let response = await this.apiFetch(url, { method: 'POST', body: '{}' });
+                if (response.status === 202) {
+                    const pending = await response.json();
+                    if (!confirm(`Run "${macro.name}" on ${printer.name}?`)) {
+                        return;
+                    }
+                    response = await this.apiFetch(url, {
+                        method: 'POST',
+                        body: JSON.stringify({ confirm_token: pending.confirm_token })
+                    });
+                }
+                if (!response.ok) {
+                    const data = await response.json();
+                    throw new Error(data.error || 'Macro failed');
+                }
+            } catch (err) {
+                alert(err.message);
+            }
+        },
+
+        // reserve books the printer for the caller from now; other slots go through /api/reservations
+        async reserve(printer) {
+            const hours = parseFloat(prompt(`Reserve ${printer.name} for how many hours?`, '2'));
+            if (!(hours > 0)) {
+                return;
+            }
+            const start = new Date();
+            const end = new Date(start.getTime() + hours * 3600000);
+            try {
+                const response = await this.apiFetch('/api/reservations', {
+                    method: 'POST',
+                    body: JSON.stringify({ printer_id: printer.id, start, end })
+                });
+                if (!response.ok) {
+                    const data = await response.json();
+                    throw new Error(data.error || 'Reservation failed');
+                }
+                await this.fetchStatus();
+            } catch (err) {
+                alert(err.message);
+            }
+        },
+
+        reservationLabel(reservation) {
+            if (!reservation) {
+                return '';
+            }
+            if (new Date(reservation.start) <= new Date()) {
+                return `Reserved by ${reservation.user} until ${this.formatClock(reservation.end)}`;
+            }
+            return `Reserved by ${reservation.user} from ${this.formatClock(reservation.start)}`;
+        },
+
+        // toggleObjects shows or hides the objects labelled in a printer's current job
+        async toggleObjects(printer) {
+            if (this.plateObjects[printer.id]) {
+                delete this.plateObjects[printer.id];
+                return;
+            }
+            await this.fetchObjects(printer);
+        },
+
+        async fetchObjects(printer) {
+            try {
+                const response = await fetch(`/api/printers/${printer.id}/objects`, { headers: this.tokenHeaders() });
+                const data = await response.json();
+                if (!response.ok) {
+                    throw new Error(data.error || 'Failed to fetch objects');
+                }
+                this.plateObjects[printer.id] = data.objects;
+            } catch (err) {
+                console.error('Error fetching objects:', err);
+            }
+        },
+
+        // cancelObject skips one failing object and leaves the rest of the plate printing
+        async cancelObject(printer, object) {
+            if (!confirm(`Cancel "${object.name}" on ${printer.name}? The rest of the plate keeps printing.`)) {
+                return;
+            }
+            try {
+                const response = await this.apiFetch(`/api/printers/${printer.id}/objects/${object.id}/cancel`, { method: 'POST', body: '{}' });
+                if (!response.ok) {
+                    const data = await response.json();
+                    throw new Error(data.error || 'Cancel failed');
+                }
+                await this.fetchObjects(printer);
+            } catch (err) {
+                alert(err.message);
+            }
+        },
+
+        tickIdleScreen() {
+            this.clock = this.formatClock(new Date().toISOString());
+            const dx = Math.round(Math.random() * 16 - 8);
+            const dy = Math.round(Math.random() * 16 - 8);
+            this.idleShiftStyle = `transform: translate(${dx}px, ${dy}px)`;
+        },
+
+        async fetchLegend() {
+            try {
+                const response = await fetch('/api/materials/legend');
+                if (!response.ok) {
+                    throw new Error('Failed to fetch material legend');
+                }
+                const data = await response.json();
+                this.legend = data.legend || [];
+            } catch (err) {
+                console.error('Error fetching material legend:', err);
+            }
+        },
+
+        // tokenHeaders sends the stored API token, if any, without ever prompting
+        // listenAnnouncements reads the announcement stream, reconnecting from the
+        // last one seen. fetch rather than EventSource so the token goes along.
+        async listenAnnouncements() {
+            // Kiosks turn sound on with ?announce=1; the toggle remembers the choice
+            const param = new URLSearchParams(window.location.search).get('announce');
+            if (param !== null) {
+                localStorage.setItem('octodash_announce', param === '0' ? '0' : '1');
+            }
+            this.announce = localStorage.getItem('octodash_announce') === '1';
+
+            let lastId = '';
+            for (;;) {
+                try {
+                    const url = '/api/announcements/stream' + (lastId ? `?since=${lastId}` : '');
+                    const response = await fetch(url, { headers: this.tokenHeaders() });
+                    if (!response.ok) {
+                        throw new Error('Failed to open announcement stream');
+                    }
+                    const reader = response.body.pipeThrough(new TextDecoderStream()).getReader();
+                    let buffer = '';
+                    for (;;) {
+                        const { value, done } = await reader.read();
+                        if (done) {
+                            break;
+                        }
+                        buffer += value;
+                        let end;
+                        while ((end = buffer.indexOf('\n\n')) >= 0) {
+                            const data = buffer.slice(0, end).split('\n')
+                                .filter(line => line.startsWith('data: '))
+                                .map(line => line.slice(6))
+                                .join('\n');
+                            buffer = buffer.slice(end + 2);
+                            if (data) {
+                                const announcement = JSON.parse(data);
+                                lastId = announcement.id;
+                                this.showAnnouncement(announcement);
+                            }
+                        }
+                    }
+                } catch (err) {
+                    console.error('Announcement stream failed:', err);
+                }
+                await new Promise(resolve => setTimeout(resolve, 5000));
+            }
+        },
+
+        showAnnouncement(announcement) {
+            this.announcement = announcement;
+            clearTimeout(this.announcementTimer);
+            this.announcementTimer = setTimeout(() => { this.announcement = null; }, 15000);
+
+            if (!this.announce) {
+                return;
+            }
+            this.chime(announcement.level);
+            if ('speechSynthesis' in window) {
+                speechSynthesis.speak(new SpeechSynthesisUtterance(announcement.text));
+            }
+        },
+
+        // chime plays a short tone before speaking: one for news, two higher for alerts
+        chime(level) {
+            const AudioContext = window.AudioContext || window.webkitAudioContext;
+            if (!AudioContext) {
+                return;
+            }
+            const ctx = new AudioContext();
+            const tones = level === 'alert' ? [880, 880] : [660];
+            tones.forEach((freq, i) => {
+                const osc = ctx.createOscillator();
+                const gain = ctx.createGain();
+                const start = ctx.currentTime + i * 0.3;
+                osc.frequency.value = freq;
+                gain.gain.setValueAtTime(0.2, start);
+                gain.gain.exponentialRampToValueAtTime(0.001, start + 0.25);
+                osc.connect(gain).connect(ctx.destination);
+                osc.start(start);
+                osc.stop(start + 0.25);
+            });
+        },
+
+        // toggleAnnounce also gives browsers the click they want before playing sound
+        toggleAnnounce() {
+            this.announce = !this.announce;
+            localStorage.setItem('octodash_announce', this.announce ? '1' : '0');
+            if (this.announce) {
+                this.chime('info');
+            }
+        },
+
+        tokenHeaders() {
+            const token = localStorage.getItem('octodash_token');
+            return token ? { 'Authorization': `Bearer ${token}` } : {};
+        },
+
+        // fetchPermissions learns which printers the stored token may control.
+        // Without a token every button stays, so pressing one asks for it.
+        async fetchPermissions() {
+            try {
+                const response = await fetch('/api/me', { headers: this.tokenHeaders() });
+                const data = await response.json();
+                this.controllable = data.authenticated ? data.printers : null;
+                this.sso = data.authenticated && data.sso;
+            } catch (err) {
+                console.error('Error fetching permissions:', err);
+            }
+        },
+
+        // fetchCameras gets signed camera URLs, which <img> tags can load without headers
+        async fetchCameras() {
+            try {
+                const response = await fetch('/api/cameras', { headers: this.tokenHeaders() });
+                if (!response.ok) {
+                    return;
+                }
+                const data = await response.json();
+                this.cameras = Object.fromEntries((data.cameras || []).map(c => [c.printer_id, c]));
+            } catch (err) {
+                console.error('Error fetching cameras:', err);
+            }
+        },
+
+        // toggleCamera swaps the card's picture for the live stream; an open
+        // stream keeps running after its link expires
+        toggleCamera(printer) {
+            if (this.liveCamera[printer.id]) {
+                delete this.liveCamera[printer.id];
+                return;
+            }
+            const camera = this.cameras[printer.id];
+            if (camera) {
+                this.liveCamera[printer.id] = camera.stream_url;
+            }
+        },
+
+        canControl(printer) {
+            return !this.controllable || this.controllable.includes(printer.id);
+        },
+
+        async fetchViews() {
+            try {
+                // Without a token there are no views to show, so don't prompt for one
+                const response = await fetch('/api/views', { headers: this.tokenHeaders() });
+                if (!response.ok) {
+                    return;
+                }
+                const data = await response.json();
+                this.views = data.views || [];
+                this.defaultViewId = data.default || '';
+                this.activeViewId = this.defaultViewId;
+            } catch (err) {
+                console.error('Error fetching views:', err);
+            }
+        },
+
+        async makeDefaultView() {
+            try {
+                const response = await this.apiFetch('/api/views/default', {
+                    method: 'PUT',
+                    body: JSON.stringify({ view_id: this.activeViewId })
+                });
+                if (response.ok) {
+                    this.defaultViewId = this.activeViewId;
+                }
+            } catch (err) {
+                console.error('Error saving default view:', err);
+            }
+        },
+
+        get activeView() {
+            return this.views.find(v => v.id === this.activeViewId) || null;
+        },
+
+        // visiblePrinters applies the active view's printer selection and sort
+        get visiblePrinters() {
+            const view = this.activeView;
+            if (!view) {
+                return this.printers;
+            }
+
+            let shown = this.printers;
+            if (view.printers.length) {
+                shown = shown.filter(p => view.printers.includes(p.id));
+            }
+
+            const statusOrder = ['error', 'completed', 'printing', 'idle', 'offline'];
+            const rank = p => {
+                const i = statusOrder.indexOf(p.status);
+                return i < 0 ? statusOrder.length : i;
+            };
+            switch (view.sort) {
+                case 'name':
+                    return [...shown].sort((a, b) => a.name.localeCompare(b.name));
+                case 'status':
+                    return [...shown].sort((a, b) => rank(a) - rank(b));
+                case 'progress':
+                    return [...shown].sort((a, b) => (b.progress?.completion || 0) - (a.progress?.completion || 0));
+                default:
+                    return shown;
+            }
+        },
+
+        async fetchForecast() {
+            try {
+                const response = await fetch('/api/forecast');
+                if (!response.ok) {
+                    throw new Error('Failed to fetch forecast');
+                }
+                const data = await response.json();
+                this.reorder = data.forecast.reorder || [];
+            } catch (err) {
+                console.error('Error fetching forecast:', err);
+            }
+        },
+
+        async search() {
+            const query = this.searchQuery.trim();
+            if (query.length < 2) {
+                this.searchResults = null;
+                return;
+            }
+
+            try {
+                const response = await fetch(`/api/search?q=${encodeURIComponent(query)}`);
+                if (!response.ok) {
+                    throw new Error('Search failed');
+                }
+                const data = await response.json();
+                this.searchResults = data.results;
+            } catch (err) {
+                console.error('Error searching:', err);
+            }
+        },
+
+        async openPrinter(printer) {
             console.log('Opening printer:', printer.name);
             
             // Clear the update interval
@@ -75,8 +694,44 @@ document.addEventListener('alpine:init', () => {
                 clearInterval(this.updateInterval);
             }
             
-            // Navigate to OctoPrint URL
-            window.location.href = printer.octoprint_url;
+            // Navigate to OctoPrint URL, logged in where a key is linked
+            let target = printer.octoprint_url;
+            if (this.sso && this.canControl(printer)) {
+                target = await this.octoPrintLogin(printer) || target;
+            }
+            window.location.href = target;
+        },
+
+        // octoPrintLogin logs the browser in to OctoPrint with the user's
+        // linked app key and returns the UI's URL, or OctoPrint's approval
+        // dialog while a key still needs linking. Null opens OctoPrint as usual.
+        async octoPrintLogin(printer) {
+            try {
+                const response = await this.apiFetch(`/api/printers/${printer.id}/sso/session`, { method: 'POST', body: '{}' });
+                const data = await response.json();
+                if (response.ok) {
+                    const login = await fetch(data.login_url, {
+                        method: 'POST',
+                        mode: 'cors',
+                        credentials: 'include',
+                        headers: { 'Content-Type': 'application/json', 'X-Api-Key': data.api_key },
+                        body: JSON.stringify({ passive: true, remember: true }),
+                    });
+                    return login.ok ? data.url : null;
+                }
+                if (data.link === 'pending') {
+                    return data.auth_dialog;
+                }
+                if (data.link === 'none' && confirm(`Link your OctoPrint login for ${printer.name} so it opens without a password?`)) {
+                    const link = await this.apiFetch(`/api/printers/${printer.id}/sso`, { method: 'POST', body: '{}' });
+                    if (link.ok) {
+                        return (await link.json()).auth_dialog;
+                    }
+                }
+            } catch (err) {
+                console.error('OctoPrint login failed:', err);
+            }
+            return null;
         },
 
         returnToDashboard() {
@@ -90,12 +745,26 @@ document.addEventListener('alpine:init', () => {
             const statusMap = {
                 'idle': 'Ready',
                 'printing': 'Printing',
+                'paused': 'Paused',
+                'completed': 'Done - remove part',
+                'cooldown': 'Cooling before eject',
                 'error': 'Error',
                 'offline': 'Offline'
             };
             return statusMap[status] || status || 'Unknown';
         },
 
+        updatesTitle(updates) {
+            if (!updates) {
+                return '';
+            }
+            const lines = (updates.components || []).map(name => name + ' update available');
+            if (updates.firmware) {
+                lines.push('Firmware: ' + updates.firmware);
+            }
+            return lines.join('\n');
+        },
+
         formatTime(seconds) {
             if (!seconds || seconds <= 0) {
                 return '--:--:--';
@@ -113,27 +782,114 @@ document.addEventListener('alpine:init', () => {
             return `${secs}s`;
         },
 
+        // Unit conversions mirror the helpers in internal/models/units.go
+        units() {
+            return CONFIG.units || { temperature: 'C', weight: 'g', length: 'mm' };
+        },
+
+        convertTemp(celsius) {
+            return this.units().temperature === 'F' ? celsius * 9 / 5 + 32 : celsius;
+        },
+
+        // formatClock renders a UTC timestamp in the configured display timezone
+        formatClock(timestamp) {
+            if (!timestamp) {
+                return '--:--';
+            }
+
+            const options = { hour: '2-digit', minute: '2-digit' };
+            if (CONFIG.timezone) {
+                options.timeZone = CONFIG.timezone;
+            }
+
+            const date = new Date(timestamp);
+            const sameDay = date.toDateString() === new Date().toDateString();
+            if (!sameDay) {
+                options.weekday = 'short';
+            }
+            return date.toLocaleTimeString([], options);
+        },
+
         formatTemp(actual, target) {
+            const unit = this.units().temperature;
             if (actual === undefined || actual === null) {
-                return '--°C';
+                return `--°${unit}`;
             }
             
-            const actualRounded = Math.round(actual);
-            const targetRounded = Math.round(target || 0);
+            const actualRounded = Math.round(this.convertTemp(actual));
+            const targetRounded = target > 0 ? Math.round(this.convertTemp(target)) : 0;
             
             if (targetRounded > 0) {
-                return `${actualRounded}°C / ${targetRounded}°C`;
+                return `${actualRounded}°${unit} / ${targetRounded}°${unit}`;
+            }
+            return `${actualRounded}°${unit}`;
+        },
+
+        // formatDried says how long ago a hygroscopic spool came out of the dryer
+        formatDried(spool) {
+            if (spool.drying) {
+                return 'Drying now';
             }
-            return `${actualRounded}°C`;
+            if (!spool.last_dried) {
+                return 'No drying recorded';
+            }
+            const days = Math.floor((Date.now() - new Date(spool.last_dried)) / 86400000);
+            return days < 1 ? 'Dried today' : `Dried ${days} day${days === 1 ? '' : 's'} ago`;
         },
 
         formatWeight(grams) {
             if (!grams || grams <= 0) {
                 return '--';
             }
+
+            const unit = this.units().weight;
+            switch (unit) {
+                case 'kg':
+                    return `${(grams / 1000).toFixed(2)}kg`;
+                case 'lb':
+                    return `${(grams / 453.59237).toFixed(2)}lb`;
+                case 'oz':
+                    return `${(grams / 28.349523125).toFixed(1)}oz`;
+            }
             return `${Math.round(grams)}g`;
         },
 
+        formatLength(mm) {
+            if (!mm || mm <= 0) {
+                return '--';
+            }
+
+            const unit = this.units().length;
+            switch (unit) {
+                case 'm':
+                    return `${(mm / 1000).toFixed(2)}m`;
+                case 'in':
+                    return `${(mm / 25.4).toFixed(2)}in`;
+                case 'ft':
+                    return `${(mm / 304.8).toFixed(2)}ft`;
+            }
+            return `${Math.round(mm)}mm`;
+        },
+
+        formatPower(watts) {
+            if (watts === undefined || watts === null) {
+                return '-- W';
+            }
+            return `${Math.round(watts)} W`;
+        },
+
+        formatEnergy(power) {
+            if (!power || !power.job_energy_kwh) {
+                return '--';
+            }
+
+            const kwh = `${power.job_energy_kwh.toFixed(2)} kWh`;
+            if (power.job_cost) {
+                return `${kwh} (${power.job_cost.toFixed(2)} ${power.currency})`;
+            }
+            return kwh;
+        },
+
         // Clean up on page unload
         destroy() {
             if (this.updateInterval) {
@@ -184,4 +940,11 @@ document.addEventListener('visibilitychange', () => {
             }
         }
     }
-});
\ No newline at end of file
+});
+
+// Register the service worker so the dashboard installs as a PWA and keeps its shell offline
+if ('serviceWorker' in navigator) {
+    navigator.serviceWorker.register('/sw.js').catch((err) => {
+        console.error('Service worker registration failed:', err);
+    });
+}
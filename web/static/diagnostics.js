@@ -0,0 +1,171 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Self-diagnostics view; renders /api/diagnostics without a framework
+(() => {
+    const container = document.getElementById('diagnostics');
+    const statusLine = document.getElementById('diagnostics-status');
+
+    function formatBytes(bytes) {
+        const units = ['B', 'KB', 'MB', 'GB'];
+        let i = 0;
+        while (bytes >= 1024 && i < units.length - 1) {
+            bytes /= 1024;
+            i++;
+        }
+        return `${bytes.toFixed(i ? 1 : 0)} ${units[i]}`;
+    }
+
+    function formatUptime(seconds) {
+        const days = Math.floor(seconds / 86400);
+        const hours = Math.floor((seconds % 86400) / 3600);
+        const minutes = Math.floor((seconds % 3600) / 60);
+        return days > 0 ? `${days}d ${hours}h ${minutes}m` : `${hours}h ${minutes}m`;
+    }
+
+    function formatTime(value) {
+        return value ? new Date(value).toLocaleString() : '--';
+    }
+
+    function element(tag, text, className) {
+        const el = document.createElement(tag);
+        if (text !== undefined) {
+            el.textContent = text;
+        }
+        if (className) {
+            el.className = className;
+        }
+        return el;
+    }
+
+    function section(title, content) {
+        const el = element('section', undefined, 'diagnostics-section');
+        el.appendChild(element('h2', title));
+        el.appendChild(content);
+        container.appendChild(el);
+    }
+
+    function table(headers, rows) {
+        const el = element('table', undefined, 'diagnostics-table');
+        const head = el.createTHead().insertRow();
+        headers.forEach(h => head.appendChild(element('th', h)));
+        const body = el.createTBody();
+        rows.forEach(cells => {
+            const row = body.insertRow();
+            cells.forEach(cell => {
+                const td = row.insertCell();
+                if (cell instanceof Node) {
+                    td.appendChild(cell);
+                } else {
+                    td.textContent = cell;
+                }
+            });
+        });
+        return el;
+    }
+
+    function badge(ok, text) {
+        return element('span', text, ok ? 'diagnostics-ok' : 'diagnostics-bad');
+    }
+
+    function render(d) {
+        container.replaceChildren();
+
+        section('Server', table(['', ''], [
+            ['Version', d.server.version],
+            ['Uptime', formatUptime(d.uptime_seconds)],
+            ['Go', d.runtime.go_version],
+            ['Goroutines', d.runtime.goroutines],
+            ['Heap', `${formatBytes(d.runtime.heap_alloc_bytes)} (${d.runtime.heap_objects} objects)`],
+            ['Memory from OS', formatBytes(d.runtime.sys_bytes)],
+            ['GC runs', `${d.runtime.num_gc}, last pause ${d.runtime.last_gc_pause_ms} ms`],
+        ]));
+
+        section('Poller', table(['', ''], [
+            ['Polls', d.poller.polls],
+            ['Last poll', formatTime(d.poller.last_poll)],
+            ['Duration (last / avg / max)', `${d.poller.last_duration_ms} / ${d.poller.avg_duration_ms} / ${d.poller.max_duration_ms} ms`],
+        ]));
+
+        section('Upstreams', table(['Name', 'Kind', 'URL', 'Reachable', 'Latency', 'Error'],
+            d.upstreams.map(u => [u.name, u.kind, u.url, badge(u.reachable, u.reachable ? 'yes' : 'no'), `${u.latency_ms} ms`, u.error || ''])));
+
+        section('Printers', table(['Printer', 'Last seen', 'Last fetch', 'Last error', 'At'],
+            d.printers.map(p => [p.name, formatTime(p.last_seen), `${p.last_fetch_ms} ms`, p.last_error || '', formatTime(p.last_error_at)])));
+
+        const db = d.database;
+        const run = (r, detail) => r
+            ? [badge(r.ok, r.ok ? 'ok' : 'failed'), `${formatTime(r.time)}, ${r.duration_ms} ms${detail(r)}`]
+            : ['--', 'never'];
+        const problems = db.last_check && db.last_check.problems ? db.last_check.problems : [];
+        const database = element('div');
+        database.appendChild(table(['', '', ''], [
+            ['Size', formatBytes(db.size_bytes), `${formatBytes(db.free_bytes)} free`],
+            ['Integrity check', ...run(db.last_check, r => r.error ? ` - ${r.error}` : '')],
+            ['Compaction', ...run(db.last_compact, r => r.error ? ` - ${r.error}` : `, ${formatBytes(r.bytes_before)} to ${formatBytes(r.bytes_after)}`)],
+        ]));
+        if (problems.length) {
+            database.appendChild(element('pre', problems.join('\n'), 'diagnostics-env'));
+        }
+        section('Database', database);
+
+        const env = element('pre', d.config.environment.join('\n'), 'diagnostics-env');
+        const config = element('div');
+        config.appendChild(table(['', ''], [
+            ['Printers', d.config.printers],
+            ['Data directory', d.config.data_dir],
+            ['Demo mode', d.config.demo ? 'on' : 'off'],
+            ['Authentication', d.config.auth_enabled ? 'on' : 'off'],
+        ]));
+        config.appendChild(env);
+        section('Configuration', config);
+    }
+
+    async function load() {
+        const headers = {};
+        const token = localStorage.getItem('octodash_token');
+        if (token) {
+            headers['Authorization'] = `Bearer ${token}`;
+        }
+
+        try {
+            const response = await fetch('/api/diagnostics', { headers });
+            if (response.status === 401 || response.status === 403) {
+                const entered = prompt('Diagnostics require an admin API token:');
+                if (entered) {
+                    localStorage.setItem('octodash_token', entered);
+                    return load();
+                }
+                statusLine.textContent = 'Not authorized';
+                return;
+            }
+            if (!response.ok) {
+                throw new Error(`HTTP ${response.status}`);
+            }
+
+            const data = await response.json();
+            render(data.diagnostics);
+            statusLine.textContent = `Updated ${new Date().toLocaleTimeString()}`;
+        } catch (err) {
+            statusLine.textContent = `Failed to load diagnostics: ${err.message}`;
+        }
+    }
+
+    load();
+    setInterval(load, 10000);
+})();
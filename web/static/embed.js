@@ -0,0 +1,79 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Minimal single-printer widget; deliberately has no framework dependency
+(() => {
+    const widget = document.getElementById('widget');
+    const printerId = widget.dataset.printer;
+    const refresh = parseInt(widget.dataset.refresh, 10) * 1000;
+    const tempUnit = widget.dataset.units;
+
+    const statusLabels = {
+        idle: 'Ready',
+        printing: 'Printing',
+        completed: 'Done',
+        error: 'Error',
+        offline: 'Offline'
+    };
+
+    function formatTime(seconds) {
+        if (!seconds || seconds <= 0) {
+            return '';
+        }
+        const hours = Math.floor(seconds / 3600);
+        const minutes = Math.floor((seconds % 3600) / 60);
+        return hours > 0 ? `${hours}h ${minutes}m left` : `${minutes}m left`;
+    }
+
+    function formatTemp(celsius) {
+        const value = tempUnit === 'F' ? celsius * 9 / 5 + 32 : celsius;
+        return `${Math.round(value)}°${tempUnit}`;
+    }
+
+    async function update() {
+        try {
+            const response = await fetch(`/api/printers/${encodeURIComponent(printerId)}/status?fields=progress,temperatures`);
+            if (!response.ok) {
+                throw new Error('Failed to fetch status');
+            }
+            const printer = (await response.json()).printer;
+
+            const status = document.getElementById('status');
+            status.textContent = statusLabels[printer.status] || printer.status;
+            status.className = `embed-status status-${printer.status}`;
+
+            const progress = printer.progress;
+            document.getElementById('progress-section').hidden = !progress;
+            if (progress) {
+                document.getElementById('progress-fill').style.width = `${progress.completion || 0}%`;
+                document.getElementById('progress-text').textContent = `${Math.round(progress.completion || 0)}%`;
+                document.getElementById('time-left').textContent = formatTime(progress.print_time_left);
+            }
+
+            const temps = printer.temperatures;
+            document.getElementById('temps').textContent = temps
+                ? `Hotend ${formatTemp(temps.hotend_actual)} · Bed ${formatTemp(temps.bed_actual)}`
+                : '';
+        } catch (err) {
+            console.error('Error updating widget:', err);
+        }
+    }
+
+    update();
+    setInterval(update, refresh);
+})();
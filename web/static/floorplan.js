@@ -0,0 +1,95 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Floor plan view; places a status dot for each printer on the uploaded room picture
+(() => {
+    const container = document.getElementById('floorplan');
+    const image = document.getElementById('floorplan-image');
+    const empty = document.getElementById('floorplan-empty');
+    const dots = new Map();
+
+    const statusNames = {
+        'idle': 'Ready',
+        'printing': 'Printing',
+        'completed': 'Done - remove part',
+        'error': 'Error',
+        'offline': 'Offline'
+    };
+
+    function dotFor(id, position) {
+        let dot = dots.get(id);
+        if (!dot) {
+            dot = document.createElement('div');
+            dot.className = 'floorplan-dot';
+            dot.innerHTML = '<span class="floorplan-dot-marker"></span><span class="floorplan-dot-label"></span>';
+            container.appendChild(dot);
+            dots.set(id, dot);
+        }
+        dot.style.left = `${position.x * 100}%`;
+        dot.style.top = `${position.y * 100}%`;
+        return dot;
+    }
+
+    async function loadLayout() {
+        const response = await fetch('/api/floorplan');
+        const data = await response.json();
+        if (!data.image_url) {
+            empty.hidden = false;
+            return null;
+        }
+        image.src = data.image_url;
+        image.hidden = false;
+        return data.floorplan.positions;
+    }
+
+    // Teams only see their own printers, so send the dashboard's stored token
+    function headers() {
+        const token = localStorage.getItem('octodash_token');
+        return token ? { 'Authorization': `Bearer ${token}` } : {};
+    }
+
+    async function refresh(positions) {
+        try {
+            const response = await fetch('/api/status?fields=progress', { headers: headers() });
+            const data = await response.json();
+            for (const printer of data.printers || []) {
+                const position = positions[printer.id];
+                if (!position) {
+                    continue;
+                }
+                const dot = dotFor(printer.id, position);
+                dot.className = `floorplan-dot status-${printer.status}`;
+                let label = printer.name;
+                if (printer.status === 'printing' && printer.progress) {
+                    label += ` ${Math.round(printer.progress.completion)}%`;
+                }
+                dot.querySelector('.floorplan-dot-label').textContent = label;
+                dot.title = `${printer.name}: ${statusNames[printer.status] || printer.status}`;
+            }
+        } catch (err) {
+            console.error('Failed to refresh floor plan:', err);
+        }
+    }
+
+    loadLayout().then(positions => {
+        if (positions) {
+            refresh(positions);
+            setInterval(() => refresh(positions), 5000);
+        }
+    });
+})();
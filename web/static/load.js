@@ -0,0 +1,77 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Scan-to-load page; assigns the scanned spool to a printer and logs drying sessions
+(() => {
+    const form = document.getElementById('load-form');
+    const result = document.getElementById('load-result');
+
+    // post sends an authenticated request, prompting for a token when rejected
+    async function post(url, payload) {
+        const headers = { 'Content-Type': 'application/json' };
+        const token = localStorage.getItem('octodash_token');
+        if (token) {
+            headers['Authorization'] = `Bearer ${token}`;
+        }
+
+        const response = await fetch(url, { method: 'POST', headers, body: JSON.stringify(payload) });
+        if (response.status === 401 || response.status === 403) {
+            const entered = prompt('This action requires an OctoDash API token:');
+            if (entered) {
+                localStorage.setItem('octodash_token', entered);
+                return post(url, payload);
+            }
+            throw new Error('Not authorized');
+        }
+        const data = await response.json();
+        if (!response.ok) {
+            throw new Error(data.error);
+        }
+        return data;
+    }
+
+    async function run(action, done) {
+        try {
+            await action();
+            result.textContent = done;
+        } catch (err) {
+            result.textContent = `Failed: ${err.message}`;
+        }
+    }
+
+    const spool = form.dataset.spool;
+    const spoolman = form.dataset.spoolman;
+
+    form.querySelectorAll('.load-printer').forEach(button => {
+        const name = button.textContent.replace(/^Load on /, '');
+        button.addEventListener('click', () => run(() => post(`/api/printers/${button.dataset.printer}/spool`, {
+            spool_id: spool,
+            spoolman,
+            tool: parseInt(document.getElementById('load-tool').value, 10)
+        }), `Loaded on ${name}`));
+    });
+
+    document.getElementById('drying-start').addEventListener('click', () => run(() => post(`/api/spools/${spool}/drying`, {
+        spoolman,
+        temperature: parseFloat(document.getElementById('drying-temp').value)
+    }), 'Drying started'));
+
+    document.getElementById('drying-stop').addEventListener('click', () => run(() => post(`/api/spools/${spool}/drying/stop`, {
+        spoolman
+    }), 'Drying stopped'));
+})();
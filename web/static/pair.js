@@ -0,0 +1,75 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Pairing page: a display shows a code until an admin approves it, then keeps
+// the token it is given; admins type codes into the second form
+(() => {
+    const codeEl = document.getElementById('pair-code');
+    const statusEl = document.getElementById('pair-status');
+
+    document.getElementById('pair-start').addEventListener('submit', async event => {
+        event.preventDefault();
+        const response = await fetch('/api/pair', {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ name: document.getElementById('pair-name').value })
+        });
+        const data = await response.json();
+        if (!response.ok) {
+            statusEl.textContent = data.error;
+            return;
+        }
+
+        codeEl.textContent = data.code;
+        statusEl.textContent = 'Approve this code from an admin account at /pair.';
+        const timer = setInterval(async () => {
+            const poll = await fetch(`/api/pair/${data.request}`);
+            const result = await poll.json();
+            if (!poll.ok) {
+                clearInterval(timer);
+                codeEl.textContent = '';
+                statusEl.textContent = 'The code expired. Start again.';
+            } else if (result.paired) {
+                clearInterval(timer);
+                localStorage.setItem('octodash_token', result.token);
+                window.location.href = '/';
+            }
+        }, 3000);
+    });
+
+    document.getElementById('pair-approve-form').addEventListener('submit', async event => {
+        event.preventDefault();
+        const result = document.getElementById('pair-approve-result');
+        const headers = { 'Content-Type': 'application/json' };
+        let token = localStorage.getItem('octodash_token');
+        if (!token) {
+            token = prompt('Approving a display requires an admin API token:');
+        }
+        if (token) {
+            headers['Authorization'] = `Bearer ${token}`;
+        }
+
+        const response = await fetch('/api/admin/devices/pair', {
+            method: 'POST',
+            headers,
+            body: JSON.stringify({ code: document.getElementById('pair-approve-code').value })
+        });
+        const data = await response.json().catch(() => ({ error: 'Not authorized' }));
+        result.textContent = response.ok ? `Paired ${data.device.name}` : `Failed: ${data.error}`;
+    });
+})();
@@ -0,0 +1,183 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Print queue view; lists entries with a preview for uploaded models and lets
+// operators approve jobs waiting for sign-off and drag waiting jobs into a new order
+(() => {
+    const list = document.getElementById('queue-list');
+
+    const statusNames = {
+        'queued': 'Queued',
+        'dispatched': 'Started',
+        'needs_slicing': 'Needs slicing',
+        'slicing': 'Slicing',
+        'slice_failed': 'Slicing failed'
+    };
+
+    function row(entry) {
+        const item = document.createElement('div');
+        item.className = `queue-entry queue-${entry.status}`;
+        item.dataset.id = entry.id;
+        if (entry.status !== 'dispatched') {
+            item.draggable = true;
+            item.addEventListener('dragstart', () => {
+                dragged = item;
+                item.classList.add('queue-dragging');
+            });
+            item.addEventListener('dragend', () => item.classList.remove('queue-dragging'));
+        }
+
+        const thumb = document.createElement(entry.preview_url ? 'img' : 'div');
+        thumb.className = 'queue-preview';
+        if (entry.preview_url) {
+            thumb.src = entry.preview_url;
+            thumb.alt = `Preview of ${entry.file}`;
+            thumb.loading = 'lazy';
+        }
+        item.appendChild(thumb);
+
+        const text = document.createElement('div');
+        const file = document.createElement('div');
+        file.className = 'queue-file';
+        file.textContent = entry.file;
+        const meta = document.createElement('div');
+        meta.className = 'queue-meta';
+        const printer = entry.dispatched_to || entry.printer_id || 'any printer';
+        const profile = entry.print_profile || entry.profile ? ` · ${entry.print_profile || entry.profile}` : '';
+        const status = entry.approval === 'pending' ? 'Awaiting approval' : statusNames[entry.status] || entry.status;
+        const priority = entry.priority && entry.priority !== 'normal' ? `${entry.priority[0].toUpperCase()}${entry.priority.slice(1)} · ` : '';
+        let schedule = '';
+        if (entry.status !== 'dispatched' && entry.not_before) {
+            schedule += ` · not before ${new Date(entry.not_before).toLocaleString([], { dateStyle: 'short', timeStyle: 'short' })}`;
+        }
+        if (entry.status !== 'dispatched' && entry.off_peak) {
+            schedule += ' · off-peak';
+        }
+        meta.textContent = `${priority}${status}${profile}${schedule} · ${printer} · ${entry.submitted_by || 'unknown'}`;
+        text.append(file, meta);
+        if (entry.estimate) {
+            const estimate = document.createElement('div');
+            estimate.className = 'queue-meta';
+            estimate.textContent = describeEstimate(entry.estimate);
+            text.appendChild(estimate);
+        }
+        if (entry.error) {
+            const error = document.createElement('div');
+            error.className = 'queue-error';
+            error.textContent = entry.error;
+            text.appendChild(error);
+        }
+        item.appendChild(text);
+
+        if (entry.approval === 'pending') {
+            const actions = document.createElement('div');
+            actions.className = 'queue-actions';
+            for (const [label, action] of [['Approve', 'approve'], ['Reject', 'reject']]) {
+                const button = document.createElement('button');
+                button.textContent = label;
+                button.addEventListener('click', () => decide(entry, action));
+                actions.appendChild(button);
+            }
+            item.appendChild(actions);
+        }
+
+        return item;
+    }
+
+    // Dragging moves the entry in the page; dropping saves the new order
+    let dragged = null;
+    list.addEventListener('dragover', event => {
+        const target = event.target.closest('.queue-entry[draggable="true"]');
+        if (!dragged || !target || target === dragged) {
+            return;
+        }
+        event.preventDefault();
+        const box = target.getBoundingClientRect();
+        const after = event.clientY > box.top + box.height / 2;
+        target.parentNode.insertBefore(dragged, after ? target.nextSibling : target);
+    });
+    list.addEventListener('drop', async event => {
+        event.preventDefault();
+        dragged = null;
+        const ids = [...list.querySelectorAll('.queue-entry[draggable="true"]')].map(item => item.dataset.id);
+        const response = await fetch('/api/queue/reorder', {
+            method: 'PATCH',
+            headers: Object.assign({ 'Content-Type': 'application/json' }, headers()),
+            body: JSON.stringify({ ids })
+        });
+        if (!response.ok) {
+            const data = await response.json().catch(() => ({}));
+            alert(data.error || 'Could not reorder the queue');
+        }
+        refresh();
+    });
+
+    function describeEstimate(estimate) {
+        const parts = [];
+        if (estimate.print_time) {
+            const hours = Math.floor(estimate.print_time / 3600);
+            const minutes = Math.round((estimate.print_time % 3600) / 60);
+            parts.push(hours ? `${hours}h ${minutes}m` : `${minutes}m`);
+        }
+        if (estimate.filament_g) {
+            parts.push(`${estimate.filament_g.toFixed(1)} g`);
+        } else if (estimate.filament_m) {
+            parts.push(`${estimate.filament_m.toFixed(2)} m`);
+        }
+        return `Estimated ${parts.join(' · ')}`;
+    }
+
+    async function decide(entry, action) {
+        const response = await fetch(`/api/queue/${entry.id}/${action}`, { method: 'POST', headers: headers() });
+        if (!response.ok) {
+            const data = await response.json().catch(() => ({}));
+            alert(data.error || `Could not ${action} ${entry.file}`);
+        }
+        refresh();
+    }
+
+    // Teams only see their own entries, so send the dashboard's stored token
+    function headers() {
+        const token = localStorage.getItem('octodash_token');
+        return token ? { 'Authorization': `Bearer ${token}` } : {};
+    }
+
+    async function refresh() {
+        try {
+            // Operators also get estimates for entries awaiting approval
+            const [response, pending] = await Promise.all([
+                fetch('/api/queue', { headers: headers() }),
+                fetch('/api/queue/pending', { headers: headers() })
+            ]);
+            const data = await response.json();
+            if (pending.ok) {
+                const detailed = (await pending.json()).queue;
+                data.queue = data.queue.map(entry => detailed.find(p => p.id === entry.id) || entry);
+            }
+            list.replaceChildren(...data.queue.map(row));
+            if (!data.queue.length) {
+                list.textContent = 'The queue is empty.';
+            }
+        } catch (err) {
+            console.error('Failed to load queue:', err);
+        }
+    }
+
+    refresh();
+    setInterval(refresh, 10000);
+})();
@@ -0,0 +1,74 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/fs"
+)
+
+//go:generate go run vendor_fetch.go
+
+// Library is a third-party frontend script vendored into static/vendor, so
+// the dashboard keeps working on networks with no internet access
+type Library struct {
+	Name      string
+	File      string // path within static
+	Source    string // upstream URL, fetched by go generate and used as a fallback
+	Integrity string // subresource integrity hash pinned for File
+}
+
+// Libraries lists every vendored library. Bumping a version means updating
+// File and Source, then running "go run vendor_fetch.go -pin" in this
+// directory and copying the printed hash into Integrity.
+var Libraries = []Library{
+	{
+		Name:   "alpinejs",
+		File:   "vendor/alpinejs-3.14.1.min.js",
+		Source: "https://unpkg.com/alpinejs@3.14.1/dist/cdn.min.js",
+	},
+}
+
+// Integrity returns the subresource integrity hash of a file's content
+func Integrity(data []byte) string {
+	sum := sha512.Sum384(data)
+	return "sha384-" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// Verify reports whether a library is vendored into fsys, failing if the
+// vendored copy doesn't match its pinned hash
+func (l Library) Verify(fsys fs.FS) (bool, error) {
+	data, err := fs.ReadFile(fsys, l.File)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if l.Integrity == "" {
+		return false, fmt.Errorf("%s is vendored but has no pinned integrity hash", l.Name)
+	}
+	if got := Integrity(data); got != l.Integrity {
+		return false, fmt.Errorf("%s does not match its pinned hash: got %s, want %s", l.File, got, l.Integrity)
+	}
+	return true, nil
+}
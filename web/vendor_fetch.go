@@ -0,0 +1,89 @@
+// Copyright 2025 William Marchesi
+
+// Author: William Marchesi
+// Email: will@marchesi.io
+// Website: https://marchesi.io/
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+//     http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build ignore
+
+// vendor_fetch downloads the libraries listed in vendor.go into static/vendor,
+// refusing any download that doesn't match its pinned hash. Run it through
+// go generate ./web; run it directly with -pin to record the hashes of a new
+// version instead.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/wmarchesi123/octodash/web"
+)
+
+func main() {
+	pin := flag.Bool("pin", false, "print the hash of each download instead of checking it")
+	flag.Parse()
+
+	client := &http.Client{Timeout: time.Minute}
+	for _, lib := range web.Libraries {
+		if !*pin {
+			if lib.Integrity == "" {
+				log.Printf("Skipping %s: no pinned hash; run with -pin to record one", lib.Name)
+				continue
+			}
+			vendored, err := lib.Verify(os.DirFS("static"))
+			if err != nil {
+				log.Fatal(err)
+			}
+			if vendored {
+				continue
+			}
+		}
+
+		resp, err := client.Get(lib.Source)
+		if err != nil {
+			log.Fatalf("fetching %s: %v", lib.Name, err)
+		}
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			log.Fatalf("fetching %s: %v", lib.Name, err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			log.Fatalf("fetching %s: %s", lib.Name, resp.Status)
+		}
+
+		got := web.Integrity(data)
+		if *pin {
+			fmt.Printf("%s: Integrity: %q,\n", lib.Name, got)
+		} else if got != lib.Integrity {
+			log.Fatalf("%s from %s has hash %s, want %s (use -pin after reviewing a version bump)", lib.Name, lib.Source, got, lib.Integrity)
+		}
+
+		path := filepath.Join("static", lib.File)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			log.Fatal(err)
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Vendored %s into %s\n", lib.Name, path)
+	}
+}